@@ -76,10 +76,15 @@ func initDBCommands() {
 	dbPullCmd = &cobra.Command{
 		Use:   "pull [output-path]",
 		Short: "Pull schema from database",
-		Long:  "Introspect your database and generate a Prisma schema file",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Introspect your database and generate a Prisma schema file.
+
+If the output path is a directory (or ends with a path separator), a schema
+folder is written instead: schema.prisma holds the datasource and generator,
+and the models are split into one file per model or per --group-by group.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return dbPullCommand(args)
+			groupBy, _ := cmd.Flags().GetString("group-by")
+			return dbPullCommand(args[0], groupBy)
 		},
 	}
 
@@ -104,6 +109,7 @@ func initDBCommands() {
 
 	// Add flags
 	dbPushCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompts (use with caution - may cause data loss)")
+	dbPullCmd.Flags().String("group-by", "", "Write a schema folder grouping models by: model, schema or prefix")
 }
 
 // splitSQLStatements splits SQL into statements, handling semicolons inside string literals
@@ -161,6 +167,7 @@ SUBCOMMANDS:
 EXAMPLES:
     prisma-go db push schema.prisma
     prisma-go db pull output.prisma
+    prisma-go db pull prisma/schema/ --group-by model
     prisma-go db seed
     prisma-go db execute "SELECT * FROM users"
     prisma-go db execute script.sql
@@ -173,18 +180,14 @@ func dbPushCommand(args []string) error {
 
 	fmt.Println("🚀 Pushing schema changes to database...")
 
-	// Read schema file
-	content, err := os.ReadFile(schemaPath)
+	// Read and parse schema (a single file or a schema folder)
+	parsed, files, diags, err := loadSchema(schemaPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to read schema: %v\n", err)
 		return err
 	}
-
-	// Parse schema
-	sourceFile := psl.NewSourceFile(schemaPath, string(content))
-	parsed, diags := psl.ParseSchemaFromFile(sourceFile)
 	if diags.HasErrors() {
-		fmt.Fprintf(os.Stderr, "❌ Error parsing schema:\n%s\n", diags.ToPrettyString(schemaPath, string(content)))
+		fmt.Fprintf(os.Stderr, "❌ Error parsing schema:\n%s\n", psl.RenderDiagnostics(files, diags))
 		return fmt.Errorf("schema parsing failed")
 	}
 
@@ -348,13 +351,16 @@ func dbPushCommand(args []string) error {
 	return nil
 }
 
-func dbPullCommand(args []string) error {
-	if len(args) == 0 {
+// dbPullCommand introspects the database into the schema at outputPath,
+// grouping the models of a schema folder by groupBy when it is set
+func dbPullCommand(outputPath string, groupBy string) error {
+	if outputPath == "" {
 		fmt.Fprintln(os.Stderr, "Error: output file required (use: prisma-go db pull <output-schema-path>)")
 		return fmt.Errorf("output file required")
 	}
 
-	outputPath := args[0]
+	// Write a schema folder when the output is a directory or grouping was requested
+	folderMode := groupBy != "" || psl.IsSchemaFolder(outputPath) || strings.HasSuffix(outputPath, string(filepath.Separator))
 
 	fmt.Println("🔍 Pulling schema from database...")
 
@@ -395,17 +401,37 @@ func dbPullCommand(args []string) error {
 
 	fmt.Printf("✓ Found %d tables\n", len(schema.Tables))
 
-	// Generate Prisma schema file
-	schemaContent := generatePrismaSchemaFromDB(schema, provider)
+	if folderMode {
+		// Generate one schema file per group
+		schemaFiles, err := generatePrismaSchemaFolderFromDB(schema, provider, groupBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to generate schema: %v\n", err)
+			return err
+		}
 
-	// Write to file
-	err = os.WriteFile(outputPath, []byte(schemaContent), 0644)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed to write schema: %v\n", err)
-		return err
-	}
+		removed, err := writePrismaSchemaFolder(outputPath, schemaFiles)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to write schema: %v\n", err)
+			return err
+		}
+		for _, fileName := range removed {
+			fmt.Printf("✓ Removed %s, whose tables no longer exist\n", fileName)
+		}
+
+		fmt.Printf("\n✅ Schema written to %s (%d files)\n", outputPath, len(schemaFiles))
+	} else {
+		// Generate Prisma schema file
+		schemaContent := generatePrismaSchemaFromDB(schema, provider)
 
-	fmt.Printf("\n✅ Schema written to %s\n", outputPath)
+		// Write to file
+		err = os.WriteFile(outputPath, []byte(schemaContent), 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to write schema: %v\n", err)
+			return err
+		}
+
+		fmt.Printf("\n✅ Schema written to %s\n", outputPath)
+	}
 	fmt.Println("\n💡 Next steps:")
 	fmt.Println("  1. Review the generated schema")
 	fmt.Println("  2. Add relations if needed")
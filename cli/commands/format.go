@@ -19,7 +19,10 @@ var formatCmd = &cobra.Command{
 This command will:
 - Parse and validate the schema
 - Format it according to Prisma style guide
-- Write the formatted schema back to the file`,
+- Write the formatted schema back to the file
+
+When the schema path is a folder, every .prisma file in it is formatted
+and rewritten in place.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFormat,
 }
@@ -31,7 +34,7 @@ var (
 )
 
 func init() {
-	formatCmd.Flags().StringVarP(&formatSchemaPath, "schema", "s", "schema.prisma", "Path to schema file")
+	formatCmd.Flags().StringVarP(&formatSchemaPath, "schema", "s", "schema.prisma", "Path to schema file or schema folder")
 	formatCmd.Flags().BoolVarP(&formatCheck, "check", "c", false, "Check if schema is formatted (exit with non-zero if not)")
 	formatCmd.Flags().BoolVarP(&formatWrite, "write", "w", true, "Write formatted schema to file")
 
@@ -49,47 +52,57 @@ func runFormat(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("schema file not found: %s", schemaPath)
 	}
 
-	// Read the schema file
-	content, err := os.ReadFile(schemaPath)
+	// Read the schema file (or every file of a schema folder)
+	_, files, diags, err := loadSchema(schemaPath)
 	if err != nil {
 		return fmt.Errorf("failed to read schema file: %w", err)
 	}
 
 	// Parse and validate first
-	_, diags := psl.ParseSchema(string(content))
 	if diags.HasErrors() {
 		ui.PrintError("Schema validation failed:")
-		fmt.Fprintf(os.Stderr, "\n%s\n", diags.ToPrettyString(schemaPath, string(content)))
+		fmt.Fprintf(os.Stderr, "\n%s\n", psl.RenderDiagnostics(files, diags))
 		return fmt.Errorf("cannot format schema with errors")
 	}
 
-	// Format the schema
-	formatted, err := psl.Reformat(string(content), 2)
-	if err != nil {
-		return fmt.Errorf("failed to format schema: %w", err)
+	// Format every file on its own so each one is rewritten in place
+	unformatted := 0
+	for _, file := range files {
+		formatted, err := psl.Reformat(file.Data, 2)
+		if err != nil {
+			return fmt.Errorf("failed to format schema %s: %w", file.Path, err)
+		}
+
+		// Check mode - just verify formatting
+		if formatCheck {
+			if file.Data != formatted {
+				ui.PrintError("Schema is not properly formatted: %s", file.Path)
+				unformatted++
+			}
+			continue
+		}
+
+		// Write mode - write formatted content back
+		if formatWrite {
+			if file.Data != formatted {
+				if err := os.WriteFile(file.Path, []byte(formatted), 0644); err != nil {
+					return fmt.Errorf("failed to write formatted schema: %w", err)
+				}
+			}
+
+			absPath, _ := filepath.Abs(file.Path)
+			ui.PrintSuccess("Formatted %s", absPath)
+		} else {
+			// Just print formatted content
+			fmt.Print(formatted)
+		}
 	}
 
-	// Check mode - just verify formatting
 	if formatCheck {
-		if string(content) != formatted {
-			ui.PrintError("Schema is not properly formatted")
+		if unformatted > 0 {
 			return fmt.Errorf("schema formatting check failed")
 		}
 		ui.PrintSuccess("Schema is properly formatted")
-		return nil
-	}
-
-	// Write mode - write formatted content back
-	if formatWrite {
-		if err := os.WriteFile(schemaPath, []byte(formatted), 0644); err != nil {
-			return fmt.Errorf("failed to write formatted schema: %w", err)
-		}
-
-		absPath, _ := filepath.Abs(schemaPath)
-		ui.PrintSuccess("Formatted %s", absPath)
-	} else {
-		// Just print formatted content
-		fmt.Print(formatted)
 	}
 
 	return nil
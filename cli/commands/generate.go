@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
@@ -14,8 +15,13 @@ import (
 	"github.com/satishbabariya/prisma-go/cli/internal/ui"
 	"github.com/satishbabariya/prisma-go/cli/internal/watch"
 	"github.com/satishbabariya/prisma-go/generator"
+	"github.com/satishbabariya/prisma-go/generator/dmmf"
+	_ "github.com/satishbabariya/prisma-go/generator/proto" // registers prisma-go-proto
 	"github.com/satishbabariya/prisma-go/internal/debug"
-	parser "github.com/satishbabariya/prisma-go/psl/parsing/v2"
+	psl "github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+	v2ast "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+	"github.com/satishbabariya/prisma-go/psl/validation"
 )
 
 var generateCmd = &cobra.Command{
@@ -26,7 +32,16 @@ var generateCmd = &cobra.Command{
 This command will:
 - Parse and validate your schema.prisma file
 - Generate type-safe Go client code
-- Create model structs and query builders`,
+- Create model structs and query builders
+- Run the plugin of every further generator block
+
+Files are only rewritten when their content changes, and models unchanged
+since the last run are skipped.
+
+With --check nothing is written; the command fails when the generated Go
+client is out of date with the schema, e.g. in CI.
+
+With --dmmf the schema is printed as a JSON document instead.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runGenerate,
 }
@@ -35,12 +50,16 @@ var (
 	generateSchemaPath string
 	generateWatch      bool
 	generateWatchOnly  bool
+	generateDMMF       bool
+	generateCheck      bool
 )
 
 func init() {
-	generateCmd.Flags().StringVarP(&generateSchemaPath, "schema", "s", "schema.prisma", "Path to schema file")
+	generateCmd.Flags().StringVarP(&generateSchemaPath, "schema", "s", "schema.prisma", "Path to schema file or schema folder")
 	generateCmd.Flags().BoolVarP(&generateWatch, "watch", "w", false, "Watch schema file for changes")
 	generateCmd.Flags().BoolVar(&generateWatchOnly, "watch-only", false, "Only watch, don't generate initially")
+	generateCmd.Flags().BoolVar(&generateDMMF, "dmmf", false, "Print the schema as a JSON document instead of generating")
+	generateCmd.Flags().BoolVar(&generateCheck, "check", false, "Fail if the generated client is out of date instead of generating")
 
 	rootCmd.AddCommand(generateCmd)
 }
@@ -55,6 +74,14 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		return runGenerateWatch(schemaPath, !generateWatchOnly)
 	}
 
+	if generateDMMF {
+		return runGenerateDMMF(schemaPath)
+	}
+
+	if generateCheck {
+		return runGenerateCheck(schemaPath)
+	}
+
 	ui.PrintHeader("Prisma-Go", "Generate Client")
 
 	spinner, _ := ui.PrintSpinner("Generating Prisma Client...")
@@ -68,36 +95,28 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("schema file not found: %s", schemaPath)
 	}
 
-	// Read schema
-	debug.Debug("Reading schema file", "path", schemaPath)
-	content, err := os.ReadFile(schemaPath)
+	// Read and parse schema
+	debug.Debug("Reading schema", "path", schemaPath)
+	ast, files, diags, err := loadSchema(schemaPath)
 	if err != nil {
 		spinner.Stop()
 		debug.Error("Failed to read schema file", "path", schemaPath, "error", err)
 		return fmt.Errorf("failed to read schema: %w", err)
 	}
-	debug.Debug("Schema file read successfully", "size", len(content))
+	debug.Debug("Schema files read successfully", "files", len(files))
 
-	// Parse schema
-	debug.Debug("Parsing schema")
-	ast, err := parser.ParseSchema(schemaPath, strings.NewReader(string(content)))
-
-	if err != nil {
+	if diags.HasErrors() {
 		spinner.Stop()
-		debug.Error("Schema parsing failed", "error", err)
+		debug.Error("Schema parsing failed", "errors", len(diags.Errors()))
 		ui.PrintError("Schema parsing failed:")
-		fmt.Fprintf(os.Stderr, "\n%s\n", err) // Error from V2 parser should be descriptive enough
+		fmt.Fprintf(os.Stderr, "\n%s\n", psl.RenderDiagnostics(files, diags))
 		return fmt.Errorf("cannot generate from invalid schema")
 	}
 	debug.Debug("Schema parsed successfully", "topLevelCount", len(ast.Tops))
 
-	// Determine output directory from generator config or use default
-	outputDir := "./generated"
 	provider := "postgresql" // Default provider
 
-	// Get schema file directory for resolving relative paths
-	schemaDir := filepath.Dir(schemaPath)
-	debug.Debug("Extracting configuration from AST", "schemaDir", schemaDir)
+	debug.Debug("Extracting configuration from AST")
 
 	// Extract provider from datasource
 	// Use V2 AST helper methods
@@ -115,25 +134,13 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	for _, gen := range ast.Generators() {
-		debug.Debug("Found generator block")
-		for _, prop := range gen.Properties {
-			if prop.Name.Name == "output" {
-				if prop.Value != nil {
-					if value, ok := prop.Value.AsStringValue(); ok {
-						outputDir = value.GetValue()
-						// Resolve relative paths relative to schema file directory
-						if !filepath.IsAbs(outputDir) {
-							outputDir = filepath.Join(schemaDir, outputDir)
-						}
-						debug.Debug("Extracted output directory", "outputDir", outputDir)
-					}
-				}
-			}
-		}
+	blocks, err := loadGeneratorBlocks(ast, files)
+	if err != nil {
+		spinner.Stop()
+		return err
 	}
 
-	debug.Debug("Configuration extracted", "provider", provider, "outputDir", outputDir)
+	debug.Debug("Configuration extracted", "provider", provider, "generators", len(blocks))
 
 	spinner.UpdateText("Parsing schema...")
 	spinner.Stop()
@@ -145,37 +152,35 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	})
 
 	info.Println(fmt.Sprintf("Schema: %s", schemaPath))
-	info.Println(fmt.Sprintf("Output: %s", outputDir))
+	for _, block := range blocks {
+		info.Println(fmt.Sprintf("Output: %s (%s)", block.config.Output, block.config.Provider))
+	}
 	info.Println(fmt.Sprintf("Provider: %s", provider))
 	fmt.Println()
 
 	// Create generator
 	spinner, _ = ui.PrintSpinner("Generating code...")
 	debug.Debug("Creating generator", "provider", provider)
-	gen := generator.NewGenerator(ast, provider)
+	gen := generator.NewGenerator(ast, files, provider)
 
-	// Generate client code
-	debug.Debug("Starting code generation", "outputDir", outputDir)
-	if err := gen.GenerateClient(outputDir); err != nil {
-		spinner.Stop()
-		debug.Error("Code generation failed", "error", err)
-		return fmt.Errorf("code generation failed: %w", err)
+	clientDir, err := generateBlocks(gen, blocks, schemaPath, func() { spinner.Stop() })
+	if err != nil {
+		return err
 	}
-
-	spinner.Stop()
-
-	absPath, _ := filepath.Abs(outputDir)
-	debug.Info("Code generation completed successfully", "outputDir", absPath)
-	ui.PrintSuccess("Generated Prisma Client at %s", absPath)
 	fmt.Println()
 
+	if clientDir == "" {
+		return nil
+	}
+
 	// Show generated files
 	ui.PrintSection("Generated Files")
-	files := []string{
-		"models.go  - Model structs",
-		"client.go  - Prisma client",
+	generatedFiles := []string{
+		"client.go         - Prisma client",
+		"enums.go          - Enum types",
+		"<model>_model.go  - Model struct and client of each model",
 	}
-	ui.PrintList(files)
+	ui.PrintList(generatedFiles)
 
 	fmt.Println()
 	ui.PrintSection("Next Steps")
@@ -202,33 +207,24 @@ func runGenerateWatch(schemaPath string, generateInitially bool) error {
 		debug.Debug("Watch callback triggered - schema changed")
 		ui.PrintInfo("Schema changed, regenerating...")
 
-		// Read schema
-		debug.Debug("Reading schema file in watch mode", "path", schemaPath)
-		content, err := os.ReadFile(schemaPath)
+		// Read and parse schema
+		debug.Debug("Reading schema in watch mode", "path", schemaPath)
+		ast, files, diags, err := loadSchema(schemaPath)
 		if err != nil {
 			debug.Error("Failed to read schema in watch mode", "error", err)
 			return fmt.Errorf("failed to read schema: %w", err)
 		}
 
-		// Parse schema
-		debug.Debug("Parsing schema in watch mode")
-		ast, err := parser.ParseSchema(schemaPath, strings.NewReader(string(content)))
-
-		if err != nil {
-			debug.Error("Schema parsing failed in watch mode", "error", err)
+		if diags.HasErrors() {
+			debug.Error("Schema parsing failed in watch mode", "errors", len(diags.Errors()))
 			ui.PrintError("Schema parsing failed:")
-			fmt.Fprintf(os.Stderr, "\n%s\n", err)
+			fmt.Fprintf(os.Stderr, "\n%s\n", psl.RenderDiagnostics(files, diags))
 			return fmt.Errorf("cannot generate from invalid schema")
 		}
 		debug.Debug("Schema parsed successfully in watch mode")
 
-		// Determine output directory from generator config or use default
-		outputDir := "./generated"
 		provider := "postgresql" // Default provider
 
-		// Get schema file directory for resolving relative paths
-		schemaDir := filepath.Dir(schemaPath)
-
 		// Extract provider from datasource
 		for _, datasource := range ast.Sources() {
 			for _, prop := range datasource.Properties {
@@ -242,33 +238,15 @@ func runGenerateWatch(schemaPath string, generateInitially bool) error {
 			}
 		}
 
-		for _, gen := range ast.Generators() {
-			for _, prop := range gen.Properties {
-				if prop.Name.Name == "output" {
-					if prop.Value != nil {
-						if value, ok := prop.Value.AsStringValue(); ok {
-							outputDir = value.GetValue()
-							// Resolve relative paths relative to schema file directory
-							if !filepath.IsAbs(outputDir) {
-								outputDir = filepath.Join(schemaDir, outputDir)
-							}
-						}
-					}
-				}
-			}
+		blocks, err := loadGeneratorBlocks(ast, files)
+		if err != nil {
+			return err
 		}
 
 		// Create generator
-		gen := generator.NewGenerator(ast, provider)
-
-		// Generate client code
-		if err := gen.GenerateClient(outputDir); err != nil {
-			return fmt.Errorf("code generation failed: %w", err)
-		}
-
-		absPath, _ := filepath.Abs(outputDir)
-		ui.PrintSuccess("Generated Prisma Client at %s", absPath)
-		return nil
+		gen := generator.NewGenerator(ast, files, provider)
+		_, err = generateBlocks(gen, blocks, schemaPath, func() {})
+		return err
 	}
 
 	// Generate initially if requested
@@ -301,8 +279,274 @@ func runGenerateWatch(schemaPath string, generateInitially bool) error {
 	return nil
 }
 
+// runGenerateDMMF prints the document describing the schema to stdout, so
+// it can be piped into other tools
+func runGenerateDMMF(schemaPath string) error {
+	_, files, diags, err := loadSchema(schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	if diags.HasErrors() {
+		fmt.Fprintf(os.Stderr, "%s\n", psl.RenderDiagnostics(files, diags))
+		return fmt.Errorf("cannot describe invalid schema")
+	}
+
+	doc, diags := dmmf.FromFiles(files)
+	if diags.HasErrors() {
+		fmt.Fprintf(os.Stderr, "%s\n", psl.RenderDiagnostics(files, diags))
+		return fmt.Errorf("cannot describe invalid schema")
+	}
+	doc.SchemaPath = schemaPath
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// runGenerateCheck generates the Go client of each client generator block
+// into a temporary directory and fails when the files in its output differ.
+// Plugins are not run.
+func runGenerateCheck(schemaPath string) error {
+	ast, files, diags, err := loadSchema(schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	if diags.HasErrors() {
+		fmt.Fprintf(os.Stderr, "%s\n", psl.RenderDiagnostics(files, diags))
+		return fmt.Errorf("cannot check invalid schema")
+	}
+
+	provider := "postgresql" // Default provider
+	for _, datasource := range ast.Sources() {
+		for _, prop := range datasource.Properties {
+			if prop.Name.Name == "provider" && prop.Value != nil {
+				if value, ok := prop.Value.AsStringValue(); ok {
+					provider = value.GetValue()
+				}
+			}
+		}
+	}
+
+	blocks, err := loadGeneratorBlocks(ast, files)
+	if err != nil {
+		return err
+	}
+
+	gen := generator.NewGenerator(ast, files, provider)
+	outdated := false
+	for _, block := range blocks {
+		config := block.config
+		if !generator.IsClientProvider(config.Provider) {
+			continue
+		}
+		stale, err := generator.StaleFiles(config.Output, func(dir string) error {
+			return generateClient(gen, config, dir)
+		})
+		if err != nil {
+			return fmt.Errorf("code generation failed: %w", err)
+		}
+		if len(stale) == 0 {
+			ui.PrintSuccess("Generated client at %s is up to date", config.Output)
+			continue
+		}
+		outdated = true
+		ui.PrintError("Generated client at %s is out of date:", config.Output)
+		for _, name := range stale {
+			fmt.Fprintf(os.Stderr, "  %s\n", filepath.Join(config.Output, name))
+		}
+	}
+	if outdated {
+		return fmt.Errorf("generated client is out of date, run prisma-go generate")
+	}
+	return nil
+}
+
 // generateCommand is a helper function for backward compatibility
 // It can be called from other commands that need to trigger generation
 func generateCommand(args []string) error {
 	return runGenerate(nil, args)
 }
+
+// generatorBlock is a generator block of the schema
+type generatorBlock struct {
+	config dmmf.GeneratorConfig
+	dir    string // Directory of the schema file declaring the block
+}
+
+// loadGeneratorBlocks reads the generator blocks of a schema with their
+// output directories resolved. A schema without generator block generates
+// the Go client to ./generated.
+func loadGeneratorBlocks(ast *psl.SchemaAst, files []psl.SourceFile) ([]generatorBlock, error) {
+	diags := diagnostics.NewDiagnostics()
+	loaded := validation.LoadGeneratorsFromAST(ast, &diags, nil)
+	if diags.HasErrors() {
+		ui.PrintError("Invalid generator block:")
+		fmt.Fprintf(os.Stderr, "\n%s\n", psl.RenderDiagnostics(files, diags))
+		return nil, fmt.Errorf("cannot generate from invalid schema")
+	}
+
+	if len(loaded) == 0 {
+		return []generatorBlock{{
+			config: dmmf.GeneratorConfig{
+				Name:            "client",
+				Provider:        "prisma-client-go",
+				Output:          "./generated",
+				PreviewFeatures: []string{},
+				Config:          map[string]interface{}{},
+			},
+			dir: ".",
+		}}, nil
+	}
+
+	// Source positions and preview features are read from the AST
+	declared := make(map[string]*v2ast.GeneratorConfig)
+	for _, block := range ast.Generators() {
+		declared[block.GetName()] = block
+	}
+
+	var blocks []generatorBlock
+	for _, gen := range loaded {
+		block := generatorBlock{dir: "."}
+		previewFeatures := []string{}
+		if decl := declared[gen.Name]; decl != nil {
+			block.dir = filepath.Dir(decl.Pos.Filename)
+			for _, prop := range decl.Properties {
+				if prop.GetName() != "previewFeatures" || prop.Value == nil {
+					continue
+				}
+				if arr, ok := prop.Value.AsArray(); ok {
+					for _, elem := range arr.Elements {
+						if value, ok := elem.AsStringValue(); ok {
+							previewFeatures = append(previewFeatures, value.GetValue())
+						}
+					}
+				}
+			}
+		}
+
+		provider, err := resolveGeneratorEnv(gen.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("generator %s: provider: %w", gen.Name, err)
+		}
+
+		output := filepath.Join(".", "generated")
+		if !generator.IsClientProvider(provider) {
+			output = filepath.Join(output, gen.Name)
+		}
+		if gen.Output != nil {
+			if output, err = resolveGeneratorEnv(*gen.Output); err != nil {
+				return nil, fmt.Errorf("generator %s: output: %w", gen.Name, err)
+			}
+			// Resolve relative paths relative to the file declaring the generator
+			if !filepath.IsAbs(output) {
+				output = filepath.Join(block.dir, output)
+			}
+		}
+
+		config := make(map[string]interface{}, len(gen.Config))
+		for key, value := range gen.Config {
+			if str, ok := value.(string); ok {
+				if value, err = resolveGeneratorEnv(str); err != nil {
+					return nil, fmt.Errorf("generator %s: %s: %w", gen.Name, key, err)
+				}
+			}
+			config[key] = value
+		}
+
+		block.config = dmmf.GeneratorConfig{
+			Name:            gen.Name,
+			Provider:        provider,
+			Output:          output,
+			PreviewFeatures: previewFeatures,
+			Config:          config,
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// resolveGeneratorEnv resolves a generator value read from env("VAR")
+func resolveGeneratorEnv(value string) (string, error) {
+	name, ok := strings.CutPrefix(value, "env:")
+	if !ok {
+		return value, nil
+	}
+	resolved := os.Getenv(name)
+	if resolved == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return resolved, nil
+}
+
+// generateBlocks runs the generator of each block: the Go client for the
+// client providers and a plugin for any other. It returns the absolute
+// output directory of the Go client, or "" when no block generates it.
+// stop is called before the first line is printed.
+func generateBlocks(gen *generator.Generator, blocks []generatorBlock, schemaPath string, stop func()) (string, error) {
+	clientDir := ""
+	for _, block := range blocks {
+		config := block.config
+		if generator.IsClientProvider(config.Provider) {
+			// Generate client code
+			debug.Debug("Starting code generation", "outputDir", config.Output)
+			if err := generateClient(gen, config, config.Output); err != nil {
+				stop()
+				debug.Error("Code generation failed", "error", err)
+				return "", fmt.Errorf("code generation failed: %w", err)
+			}
+			stop()
+
+			clientDir, _ = filepath.Abs(config.Output)
+			debug.Info("Code generation completed successfully", "outputDir", clientDir)
+			ui.PrintSuccess("Generated Prisma Client at %s", clientDir)
+			continue
+		}
+
+		plugin, err := generator.ResolvePlugin(config.Provider, block.dir)
+		if err != nil {
+			stop()
+			return "", fmt.Errorf("generator %s: %w", config.Name, err)
+		}
+		if abs, err := filepath.Abs(config.Output); err == nil {
+			config.Output = abs
+		}
+		if err := gen.RunPlugin(plugin, config, schemaPath); err != nil {
+			stop()
+			return "", fmt.Errorf("code generation failed: %w", err)
+		}
+		stop()
+		ui.PrintSuccess("Generated %s (%s) at %s", config.Name, config.Provider, config.Output)
+	}
+	return clientDir, nil
+}
+
+// generateClient generates the Go client of a client generator block into
+// dir, with the GraphQL and REST files its options enable
+func generateClient(gen *generator.Generator, config dmmf.GeneratorConfig, dir string) error {
+	if err := gen.GenerateClient(dir); err != nil {
+		return err
+	}
+	if enabled(config.Config["graphql"]) {
+		if err := gen.GenerateGraphQL(dir); err != nil {
+			return err
+		}
+	}
+	if enabled(config.Config["openapi"]) {
+		if err := gen.GenerateREST(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enabled reports whether a generator block option is set to true
+func enabled(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
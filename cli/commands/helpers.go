@@ -1,10 +1,12 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode"
 
 	"github.com/satishbabariya/prisma-go/migrate/introspect"
 	psl "github.com/satishbabariya/prisma-go/psl"
@@ -109,6 +111,116 @@ func normalizeProviderForDriver(provider string) string {
 func generatePrismaSchemaFromDB(schema *introspect.DatabaseSchema, provider string) string {
 	var result strings.Builder
 
+	writePrismaSchemaHeader(&result, provider, nil)
+
+	// Models
+	for _, table := range schema.Tables {
+		writePrismaModelFromTable(&result, table)
+	}
+
+	return result.String()
+}
+
+// generatePrismaSchemaFolderFromDB generates a multi-file schema from an introspected database.
+// The returned map is keyed by file name. schema.prisma holds the datasource and generator
+// blocks, the models are split into files according to groupBy:
+// "model" (one file per model), "schema" (one file per database schema) or
+// "prefix" (one file per table name prefix, e.g. auth_users and auth_sessions go to auth.prisma)
+func generatePrismaSchemaFolderFromDB(schema *introspect.DatabaseSchema, provider, groupBy string) (map[string]string, error) {
+	files := make(map[string]*strings.Builder)
+	var order []string
+
+	header := &strings.Builder{}
+	writePrismaSchemaHeader(header, provider, []string{"prismaSchemaFolder"})
+	files["schema.prisma"] = header
+	order = append(order, "schema.prisma")
+
+	for _, table := range schema.Tables {
+		var group string
+		switch groupBy {
+		case "", "model":
+			group = table.Name
+		case "schema":
+			group = table.Schema
+			if group == "" {
+				group = "models"
+			}
+		case "prefix":
+			group = table.Name
+			if idx := strings.Index(table.Name, "_"); idx > 0 {
+				group = table.Name[:idx]
+			}
+		default:
+			return nil, fmt.Errorf("unknown grouping %q (expected model, schema or prefix)", groupBy)
+		}
+
+		fileName := strings.ToLower(group) + ".prisma"
+		if fileName == "schema.prisma" {
+			fileName = "schema_models.prisma"
+		}
+		builder, ok := files[fileName]
+		if !ok {
+			builder = &strings.Builder{}
+			builder.WriteString(pulledSchemaHeader + "\n\n")
+			files[fileName] = builder
+			order = append(order, fileName)
+		}
+		writePrismaModelFromTable(builder, table)
+	}
+
+	result := make(map[string]string, len(files))
+	for _, fileName := range order {
+		result[fileName] = strings.TrimSuffix(files[fileName].String(), "\n")
+	}
+	return result, nil
+}
+
+// pulledSchemaHeader starts the model files of a schema folder written by
+// db pull, so that a later pull knows which files it wrote
+const pulledSchemaHeader = "// Introspected by prisma-go db pull"
+
+// writePrismaSchemaFolder writes the files of a pulled schema folder to dir,
+// and removes the files an earlier pull wrote that this one did not, e.g.
+// for a dropped table. Files without pulledSchemaHeader are left alone.
+// It returns the names of the removed files.
+func writePrismaSchemaFolder(dir string, files map[string]string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	for fileName, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, fileName), []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if _, ok := files[name]; ok || entry.IsDir() || filepath.Ext(name) != ".prisma" {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return removed, err
+		}
+		if !bytes.HasPrefix(data, []byte(pulledSchemaHeader+"\n")) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+// writePrismaSchemaHeader writes the datasource and generator blocks
+func writePrismaSchemaHeader(result *strings.Builder, provider string, previewFeatures []string) {
 	// Datasource
 	result.WriteString("datasource db {\n")
 	result.WriteString(fmt.Sprintf("  provider = \"%s\"\n", provider))
@@ -119,43 +231,77 @@ func generatePrismaSchemaFromDB(schema *introspect.DatabaseSchema, provider stri
 	result.WriteString("generator client {\n")
 	result.WriteString("  provider = \"prisma-client-go\"\n")
 	result.WriteString("  output   = \"./generated\"\n")
+	if len(previewFeatures) > 0 {
+		quoted := make([]string, len(previewFeatures))
+		for i, feature := range previewFeatures {
+			quoted[i] = fmt.Sprintf("%q", feature)
+		}
+		result.WriteString(fmt.Sprintf("  previewFeatures = [%s]\n", strings.Join(quoted, ", ")))
+	}
 	result.WriteString("}\n\n")
+}
 
-	// Models
-	for _, table := range schema.Tables {
-		result.WriteString(fmt.Sprintf("model %s {\n", toPascalCase(table.Name)))
+// writePrismaModelFromTable writes the model block for an introspected table.
+// Tables and columns whose names the generator would not derive from the
+// model and field names get @@map and @map attributes.
+func writePrismaModelFromTable(result *strings.Builder, table introspect.Table) {
+	modelName := toPascalCase(table.Name)
+	result.WriteString(fmt.Sprintf("model %s {\n", modelName))
 
-		for _, col := range table.Columns {
-			fieldType := mapDBTypeToPrisma(col.Type)
-			nullable := ""
-			if col.Nullable {
-				nullable = "?"
-			}
+	for _, col := range table.Columns {
+		fieldName := prismaFieldName(col.Name)
+		fieldType := mapDBTypeToPrisma(col.Type)
+		nullable := ""
+		if col.Nullable {
+			nullable = "?"
+		}
 
-			attrs := ""
-			// Check if primary key
-			if table.PrimaryKey != nil && len(table.PrimaryKey.Columns) == 1 && table.PrimaryKey.Columns[0] == col.Name {
-				attrs += " @id"
-				if col.AutoIncrement {
-					attrs += " @default(autoincrement())"
-				}
+		attrs := ""
+		// Check if primary key
+		if table.PrimaryKey != nil && len(table.PrimaryKey.Columns) == 1 && table.PrimaryKey.Columns[0] == col.Name {
+			attrs += " @id"
+			if col.AutoIncrement {
+				attrs += " @default(autoincrement())"
 			}
+		}
 
-			// Check for unique indexes
-			for _, idx := range table.Indexes {
-				if idx.IsUnique && len(idx.Columns) == 1 && idx.Columns[0] == col.Name {
-					attrs += " @unique"
-					break
-				}
+		// Check for unique indexes
+		for _, idx := range table.Indexes {
+			if idx.IsUnique && len(idx.Columns) == 1 && idx.Columns[0] == col.Name {
+				attrs += " @unique"
+				break
 			}
+		}
 
-			result.WriteString(fmt.Sprintf("  %s %s%s%s\n", col.Name, fieldType, nullable, attrs))
+		if toSnakeCase(fieldName) != col.Name {
+			attrs += fmt.Sprintf(" @map(%q)", col.Name)
 		}
 
-		result.WriteString("}\n\n")
+		result.WriteString(fmt.Sprintf("  %s %s%s%s\n", fieldName, fieldType, nullable, attrs))
 	}
 
-	return result.String()
+	if toSnakeCase(modelName) != table.Name {
+		result.WriteString(fmt.Sprintf("\n  @@map(%q)\n", table.Name))
+	}
+
+	result.WriteString("}\n\n")
+}
+
+// prismaFieldName returns a valid field name for a column name
+func prismaFieldName(column string) string {
+	var result strings.Builder
+	for _, r := range column {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			result.WriteRune(r)
+		} else {
+			result.WriteRune('_')
+		}
+	}
+	name := result.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "field" + name
+	}
+	return name
 }
 
 func mapDBTypeToPrisma(dbType string) string {
@@ -178,6 +324,19 @@ func mapDBTypeToPrisma(dbType string) string {
 	}
 }
 
+// toSnakeCase converts a model or field name to the table or column name
+// the generator and migrations use for it
+func toSnakeCase(s string) string {
+	var result strings.Builder
+	for i, r := range s {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			result.WriteRune('_')
+		}
+		result.WriteRune(r)
+	}
+	return strings.ToLower(result.String())
+}
+
 func toPascalCase(s string) string {
 	words := strings.Split(s, "_")
 	result := ""
@@ -192,23 +351,32 @@ func toPascalCase(s string) string {
 // getSchemaPath returns the schema path using consistent logic:
 // 1. Use explicit flag value if set
 // 2. Use first argument if provided
-// 3. Default to "schema.prisma"
+// 3. Default to "schema.prisma", or a common location such as prisma/schema if it is missing
 func getSchemaPath(flagValue string, args []string) string {
 	if flagValue != "" && flagValue != "schema.prisma" {
 		return flagValue
 	}
+	schemaPath := "schema.prisma"
 	if len(args) > 0 {
-		return args[0]
+		schemaPath = args[0]
+	}
+	if schemaPath == "schema.prisma" {
+		if _, err := os.Stat(schemaPath); os.IsNotExist(err) {
+			if found := findSchemaFile(); found != "" {
+				return found
+			}
+		}
 	}
-	return "schema.prisma"
+	return schemaPath
 }
 
-// findSchemaFile attempts to find a schema file in common locations
+// findSchemaFile attempts to find a schema file or schema folder in common locations
 func findSchemaFile() string {
 	commonPaths := []string{
 		"schema.prisma",
 		"prisma/schema.prisma",
 		"./schema.prisma",
+		"prisma/schema",
 	}
 
 	for _, path := range commonPaths {
@@ -220,6 +388,20 @@ func findSchemaFile() string {
 	return ""
 }
 
+// loadSchema reads and parses the schema at schemaPath.
+// The path may point to a single schema file or to a folder of .prisma files,
+// which are merged into one AST. The returned source files are needed to
+// render diagnostics against the right file.
+func loadSchema(schemaPath string) (*psl.SchemaAst, []psl.SourceFile, psl.Diagnostics, error) {
+	files, err := psl.ReadSchemaFiles(schemaPath)
+	if err != nil {
+		return nil, nil, psl.Diagnostics{}, err
+	}
+
+	schema, diags := psl.ParseSchemaFiles(files)
+	return schema, files, diags, nil
+}
+
 // getDatabaseURLFromEnv attempts to find DATABASE_URL from environment and .env files:
 // 1. Environment variable DATABASE_URL
 // 2. .env file in current directory
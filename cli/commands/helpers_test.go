@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/satishbabariya/prisma-go/generator/codegen"
+	"github.com/satishbabariya/prisma-go/migrate/introspect"
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+// TestIntrospectedLegacyNamesRoundTrip pulls a legacy database whose names
+// do not follow the snake_case convention and checks that the client
+// generated from the pulled schema addresses the original tables and columns.
+func TestIntrospectedLegacyNamesRoundTrip(t *testing.T) {
+	// A file database, since the introspector queries on several connections
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "legacy.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	legacy := []string{
+		`CREATE TABLE "Customers" ("CustomerID" INTEGER PRIMARY KEY AUTOINCREMENT, "FullName" TEXT NOT NULL, "emailAddress" TEXT, "created_at" DATETIME)`,
+		`CREATE TABLE "order_items" ("id" INTEGER PRIMARY KEY, "orderRef" INTEGER NOT NULL, "unit price" REAL)`,
+		`INSERT INTO "Customers" ("FullName", "emailAddress") VALUES ('Ada Lovelace', 'ada@example.com')`,
+		`INSERT INTO "order_items" ("id", "orderRef", "unit price") VALUES (1, 1, 9.5)`,
+	}
+	for _, stmt := range legacy {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	introspector, err := introspect.NewIntrospector(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := introspector.Introspect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulled := generatePrismaSchemaFromDB(schema, "sqlite")
+
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", pulled)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatalf("pulled schema does not parse:\n%s\n%s", psl.RenderDiagnostics(files, diags), pulled)
+	}
+	dbDiags := diagnostics.NewDiagnostics()
+	names := codegen.ResolveDatabaseNames(database.NewParserDatabase(files, &dbDiags, database.NoExtensionTypes{}))
+	models := codegen.GenerateModelsFromAST(schemaAST, names)
+
+	want := make(map[string][]string)
+	for _, table := range schema.Tables {
+		for _, col := range table.Columns {
+			want[table.Name] = append(want[table.Name], col.Name)
+		}
+	}
+	if len(models) != len(want) {
+		t.Fatalf("got %d models for %d tables:\n%s", len(models), len(want), pulled)
+	}
+
+	for _, model := range models {
+		columns, ok := want[model.TableName]
+		if !ok {
+			t.Errorf("model %s maps to unknown table %q", model.Name, model.TableName)
+			continue
+		}
+		var got []string
+		for _, field := range model.Fields {
+			got = append(got, field.DBName)
+			if !strings.Contains(field.Tags, `db:"`+field.DBName+`"`) {
+				t.Errorf("%s.%s has tags %s, want column %q", model.Name, field.Name, field.Tags, field.DBName)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(columns, ",") {
+			t.Errorf("model %s has columns %v, want %v", model.Name, got, columns)
+		}
+
+		// The resolved names must address the legacy table
+		quoted := make([]string, len(got))
+		for i, column := range got {
+			quoted[i] = `"` + column + `"`
+		}
+		var count int
+		query := `SELECT COUNT(*) FROM (SELECT ` + strings.Join(quoted, ", ") + ` FROM "` + model.TableName + `")`
+		if err := db.QueryRow(query).Scan(&count); err != nil || count != 1 {
+			t.Errorf("%s: %d rows, %v", query, count, err)
+		}
+	}
+}
+
+func TestWritePrismaModelFromTableMaps(t *testing.T) {
+	var result strings.Builder
+	writePrismaModelFromTable(&result, introspect.Table{
+		Name: "user_accounts",
+		Columns: []introspect.Column{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "UserID", Type: "INTEGER"},
+			{Name: "first name", Type: "TEXT"},
+		},
+		PrimaryKey: &introspect.PrimaryKey{Columns: []string{"id"}},
+	})
+	want := "model UserAccounts {\n" +
+		"  id Int @id\n" +
+		"  UserID Int @map(\"UserID\")\n" +
+		"  first_name String @map(\"first name\")\n" +
+		"}\n\n"
+	if result.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", result.String(), want)
+	}
+}
+
+func TestWritePrismaSchemaFolderRemovesStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	pull := func(tables ...string) []string {
+		t.Helper()
+		schema := &introspect.DatabaseSchema{}
+		for _, table := range tables {
+			schema.Tables = append(schema.Tables, introspect.Table{Name: table, Columns: []introspect.Column{{Name: "id", Type: "INTEGER"}}})
+		}
+		files, err := generatePrismaSchemaFolderFromDB(schema, "sqlite", "model")
+		if err != nil {
+			t.Fatal(err)
+		}
+		removed, err := writePrismaSchemaFolder(dir, files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return removed
+	}
+
+	if removed := pull("users", "posts"); len(removed) != 0 {
+		t.Fatalf("first pull removed %v", removed)
+	}
+	// A file of the user's own is not the pull's to remove
+	custom := filepath.Join(dir, "views.prisma")
+	if err := os.WriteFile(custom, []byte("model Report {\n  id Int @id\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if removed := pull("users"); !reflect.DeepEqual(removed, []string{"posts.prisma"}) {
+		t.Errorf("removed %v, want the file of the dropped posts table", removed)
+	}
+	var names []string
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{"schema.prisma", "users.prisma", "views.prisma"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("schema folder holds %v, want %v", names, want)
+	}
+}
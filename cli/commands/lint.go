@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/satishbabariya/prisma-go/cli/internal/ui"
+	psl "github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/lint"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [schema-path]",
+	Short: "Lint a Prisma schema for common pitfalls",
+	Long: `Lint a Prisma schema with opinionated rules that go beyond validation.
+
+Rule severities can be configured in .prisma-go.yaml:
+
+  lint:
+    rules:
+      missing-timestamps: off
+      float-for-money: error
+
+or on the command line with --rule missing-timestamps=off.
+
+Findings can be suppressed with comments in the schema:
+
+  // prisma-go-lint-disable-next-line missing-timestamps
+  // prisma-go-lint-disable-line
+  // prisma-go-lint-disable naming-convention ... // prisma-go-lint-enable naming-convention
+
+The command exits with a non-zero status when a finding has error severity.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLint,
+}
+
+var (
+	lintSchemaPath string
+	lintFormat     string
+	lintOutput     string
+	lintRules      []string
+	lintListRules  bool
+)
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintSchemaPath, "schema", "s", "schema.prisma", "Path to schema file or schema folder")
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", lint.FormatText, "Output format: text, json or sarif")
+	lintCmd.Flags().StringVarP(&lintOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	lintCmd.Flags().StringArrayVar(&lintRules, "rule", nil, "Override a rule severity, e.g. --rule float-for-money=error")
+	lintCmd.Flags().BoolVar(&lintListRules, "list-rules", false, "List available rules and exit")
+
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	if lintListRules {
+		printLintRules()
+		return nil
+	}
+
+	schemaPath := getSchemaPath(lintSchemaPath, args)
+	if _, err := os.Stat(schemaPath); os.IsNotExist(err) {
+		return fmt.Errorf("schema file not found: %s", schemaPath)
+	}
+
+	config, err := lintConfig()
+	if err != nil {
+		return err
+	}
+
+	_, files, diags, err := loadSchema(schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to read schema file: %w", err)
+	}
+	if diags.HasErrors() {
+		ui.PrintError("Schema parsing failed:")
+		fmt.Fprintf(os.Stderr, "\n%s\n", psl.RenderDiagnostics(files, diags))
+		return fmt.Errorf("cannot lint schema with errors")
+	}
+
+	findings, err := lint.Lint(files, config)
+	if err != nil {
+		return fmt.Errorf("failed to lint schema: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if lintOutput != "" {
+		file, err := os.Create(lintOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	isText := lintFormat == lint.FormatText
+	if isText && lintOutput == "" {
+		ui.PrintHeader("Prisma-Go", "Lint Schema")
+	}
+	if err := lint.WriteReport(out, lintFormat, findings); err != nil {
+		return err
+	}
+
+	if isText && lintOutput == "" {
+		if len(findings) == 0 {
+			ui.PrintSuccess("No lint findings")
+		} else {
+			fmt.Println()
+			ui.PrintInfo("%d finding(s)", len(findings))
+		}
+	}
+
+	if lint.HasErrors(findings) {
+		return fmt.Errorf("lint found errors")
+	}
+	return nil
+}
+
+// lintConfig builds the lint config from the config file and --rule flags.
+func lintConfig() (lint.Config, error) {
+	config := lint.Config{Severities: make(map[string]lint.Severity)}
+
+	for id, value := range viper.GetStringMapString("lint.rules") {
+		severity, err := lint.ParseSeverity(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid severity for lint rule %s: %w", id, err)
+		}
+		config.Severities[id] = severity
+	}
+
+	for _, rule := range lintRules {
+		id, value, ok := strings.Cut(rule, "=")
+		if !ok {
+			return config, fmt.Errorf("invalid --rule %q, expected <rule>=<severity>", rule)
+		}
+		severity, err := lint.ParseSeverity(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid severity for lint rule %s: %w", id, err)
+		}
+		config.Severities[strings.TrimSpace(id)] = severity
+	}
+
+	return config, config.Validate()
+}
+
+func printLintRules() {
+	rules := append([]*lint.Rule(nil), lint.Rules()...)
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	ui.PrintSection("Lint Rules")
+	for _, rule := range rules {
+		fmt.Printf("  %-28s %-8s %s\n", rule.ID, rule.DefaultSeverity, rule.Description)
+	}
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/satishbabariya/prisma-go/psl/lsp"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Start the Prisma schema language server",
+	Long: `Start a Language Server Protocol server for Prisma schemas on stdin/stdout.
+
+Editors can use it to get:
+- Diagnostics while typing
+- Completions for attributes, native types, models and fields
+- Hover documentation
+- Go to definition for model, enum and type references
+- Rename of models and fields
+- Document formatting
+
+All .prisma files in the directory of an opened file are treated as one schema.`,
+	Args: cobra.NoArgs,
+	RunE: runLSP,
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
+
+func runLSP(cmd *cobra.Command, args []string) error {
+	// stdout carries the protocol, so nothing else may be printed there
+	if err := lsp.NewServer(os.Stdin, os.Stdout).Serve(); err != nil {
+		return fmt.Errorf("language server failed: %w", err)
+	}
+	return nil
+}
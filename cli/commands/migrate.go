@@ -267,17 +267,14 @@ func migrateDevCommand(args []string) error {
 	// Step 1: Generate migration SQL using migrate diff logic
 	fmt.Println("\n📋 Step 1: Analyzing schema differences...")
 
-	// Read and parse schema
-	content, err := os.ReadFile(schemaPath)
+	// Read and parse schema (a single file or a schema folder)
+	parsed, files, diags, err := loadSchema(schemaPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to read schema: %v\n", err)
 		return err
 	}
-
-	sourceFile := psl.NewSourceFile(schemaPath, string(content))
-	parsed, diags := psl.ParseSchemaFromFile(sourceFile)
 	if diags.HasErrors() {
-		fmt.Fprintf(os.Stderr, "❌ Error parsing schema:\n%s\n", diags.ToPrettyString(schemaPath, string(content)))
+		fmt.Fprintf(os.Stderr, "❌ Error parsing schema:\n%s\n", psl.RenderDiagnostics(files, diags))
 		return fmt.Errorf("schema parsing failed")
 	}
 
@@ -620,17 +617,14 @@ func migrateDiffCommand(args []string) error {
 
 	fmt.Println("🔍 Analyzing schema differences...")
 
-	// Read and parse schema
-	content, err := os.ReadFile(schemaPath)
+	// Read and parse schema (a single file or a schema folder)
+	parsed, files, diags, err := loadSchema(schemaPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Failed to read schema: %v\n", err)
 		return err
 	}
-
-	sourceFile := psl.NewSourceFile(schemaPath, string(content))
-	parsed, diags := psl.ParseSchemaFromFile(sourceFile)
 	if diags.HasErrors() {
-		fmt.Fprintf(os.Stderr, "❌ Error parsing schema:\n%s\n", diags.ToPrettyString(schemaPath, string(content)))
+		fmt.Fprintf(os.Stderr, "❌ Error parsing schema:\n%s\n", psl.RenderDiagnostics(files, diags))
 		return fmt.Errorf("schema parsing failed")
 	}
 
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/satishbabariya/prisma-go/cli/internal/ui"
+	"github.com/satishbabariya/prisma-go/telemetry"
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Inspect and control telemetry",
+	Long: `Inspect and control the telemetry of prisma-go.
+
+Events are buffered in the telemetry directory ($PRISMA_TELEMETRY_DIR, or
+prisma-go/telemetry in the user config directory) until they are flushed to
+the sink set by PRISMA_TELEMETRY_SINK:
+
+  none               drop events, keeping nothing but the buffer
+  file:<path>        append events to a JSONL file
+  https://...        post events to an endpoint (the default)
+
+Events only hold the command, provider, duration, error class, version and
+platform; paths, schema contents and connection strings are never recorded.`,
+}
+
+var telemetryExportOutput string
+
+func init() {
+	telemetryShowCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the telemetry settings and the buffered events",
+		Args:  cobra.NoArgs,
+		RunE:  runTelemetryShow,
+	}
+	telemetryExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the buffered events as JSON lines",
+		Args:  cobra.NoArgs,
+		RunE:  runTelemetryExport,
+	}
+	telemetryExportCmd.Flags().StringVarP(&telemetryExportOutput, "output", "o", "", "Write the events to a file instead of stdout")
+	telemetryDisableCmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Disable telemetry and remove the buffered events",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setTelemetryDisabled(true)
+		},
+	}
+	telemetryEnableCmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Enable telemetry again",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setTelemetryDisabled(false)
+		},
+	}
+
+	telemetryCmd.AddCommand(telemetryShowCmd, telemetryExportCmd, telemetryDisableCmd, telemetryEnableCmd)
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+func runTelemetryShow(cmd *cobra.Command, args []string) error {
+	dir, err := telemetry.Dir()
+	if err != nil {
+		return err
+	}
+	events, err := telemetry.NewBuffer(dir).Events()
+	if err != nil {
+		return err
+	}
+
+	status := "enabled"
+	switch {
+	case telemetry.DisabledIn(dir):
+		status = "disabled (prisma-go telemetry disable)"
+	case !telemetry.IsEnabled():
+		status = "disabled"
+	}
+	ui.PrintTable([]string{"Setting", "Value"}, [][]string{
+		{"Status", status},
+		{"Directory", dir},
+		{"Sink", telemetry.SinkSpec()},
+		{"Buffered events", fmt.Sprint(len(events))},
+	})
+	for _, event := range events {
+		data, err := json.MarshalIndent(event, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	}
+	return nil
+}
+
+func runTelemetryExport(cmd *cobra.Command, args []string) error {
+	dir, err := telemetry.Dir()
+	if err != nil {
+		return err
+	}
+	events, err := telemetry.NewBuffer(dir).Events()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if telemetryExportOutput != "" {
+		f, err := os.Create(telemetryExportOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+	if telemetryExportOutput != "" {
+		ui.PrintSuccess("Exported %d events to %s", len(events), telemetryExportOutput)
+	}
+	return nil
+}
+
+func setTelemetryDisabled(disabled bool) error {
+	dir, err := telemetry.Dir()
+	if err != nil {
+		return err
+	}
+	if err := telemetry.SetDisabled(dir, disabled); err != nil {
+		return err
+	}
+	if disabled {
+		ui.PrintSuccess("Telemetry disabled, buffered events removed")
+	} else {
+		ui.PrintSuccess("Telemetry enabled")
+	}
+	return nil
+}
@@ -17,6 +17,9 @@ var validateCmd = &cobra.Command{
 	Short: "Validate a Prisma schema file",
 	Long: `Validate a Prisma schema file for syntax and semantic errors.
 
+The schema path may also point to a folder, in which case every .prisma
+file in it is validated as part of one schema.
+
 This command will:
 - Parse the schema file
 - Check for syntax errors
@@ -31,7 +34,7 @@ var (
 )
 
 func init() {
-	validateCmd.Flags().StringVarP(&validateSchemaPath, "schema", "s", "schema.prisma", "Path to schema file")
+	validateCmd.Flags().StringVarP(&validateSchemaPath, "schema", "s", "schema.prisma", "Path to schema file or schema folder")
 
 	rootCmd.AddCommand(validateCmd)
 }
@@ -46,27 +49,25 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("schema file not found: %s", schemaPath)
 	}
 
-	// Read the schema file
-	content, err := os.ReadFile(schemaPath)
+	// Read the schema file (or every file of a schema folder), then parse
+	// and validate it like the language server does
+	files, err := psl.ReadSchemaFiles(schemaPath)
 	if err != nil {
 		return fmt.Errorf("failed to read schema file: %w", err)
 	}
+	schemaAst, diags := psl.ValidateSchemaFiles(files)
 
-	// Create source file and parse
-	sourceFile := psl.NewSourceFile(schemaPath, string(content))
-	schemaAst, diags := psl.ParseSchemaFromFile(sourceFile)
-
-	// Check for parsing errors
+	// Check for parsing and validation errors
 	if diags.HasErrors() {
-		ui.PrintError("Schema parsing failed:")
-		fmt.Fprintf(os.Stderr, "\n%s\n", diags.ToPrettyString(schemaPath, string(content)))
+		ui.PrintError("Schema validation failed:")
+		fmt.Fprintf(os.Stderr, "\n%s\n", psl.RenderDiagnostics(files, diags))
 		return fmt.Errorf("schema has parsing errors")
 	}
 
 	// Check for warnings
 	if len(diags.Warnings()) > 0 {
 		ui.PrintWarning("Schema parsed with warnings:")
-		fmt.Fprintf(os.Stderr, "\n%s\n", diags.WarningsToPrettyString(schemaPath, string(content)))
+		fmt.Fprintf(os.Stderr, "\n%s\n", psl.RenderWarnings(files, diags))
 	}
 
 	// Count models, enums, datasources, generators
@@ -108,6 +109,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	ui.PrintSection("Schema Summary")
 	summary := []string{
+		fmt.Sprintf("%d file(s)", len(files)),
 		fmt.Sprintf("%d datasource(s)", datasourceCount),
 		fmt.Sprintf("%d generator(s)", generatorCount),
 		fmt.Sprintf("%d model(s)", modelCount),
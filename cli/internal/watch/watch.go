@@ -3,22 +3,26 @@ package watch
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
 
-// Watcher watches a file for changes
+// Watcher watches a file, or every .prisma file of a schema folder, for changes
 type Watcher struct {
 	file     string
+	isDir    bool
 	callback func() error
 	watcher  *fsnotify.Watcher
 	done     chan bool
 }
 
-// NewWatcher creates a new file watcher
+// NewWatcher creates a new file watcher.
+// When file is a directory, all .prisma files below it are watched.
 func NewWatcher(file string, callback func() error) (*Watcher, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -31,21 +35,46 @@ func NewWatcher(file string, callback func() error) (*Watcher, error) {
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	// Watch the directory containing the file
-	dir := filepath.Dir(absPath)
-	if err := watcher.Add(dir); err != nil {
+	info, err := os.Stat(absPath)
+	isDir := err == nil && info.IsDir()
+
+	if isDir {
+		// Watch the schema folder and all of its subdirectories
+		err = filepath.WalkDir(absPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return watcher.Add(path)
+			}
+			return nil
+		})
+	} else {
+		// Watch the directory containing the file
+		err = watcher.Add(filepath.Dir(absPath))
+	}
+	if err != nil {
 		watcher.Close()
 		return nil, fmt.Errorf("failed to watch directory: %w", err)
 	}
 
 	return &Watcher{
 		file:     absPath,
+		isDir:    isDir,
 		callback: callback,
 		watcher:  watcher,
 		done:     make(chan bool),
 	}, nil
 }
 
+// matches reports whether a change to path affects the watched schema
+func (w *Watcher) matches(path string) bool {
+	if !w.isDir {
+		return path == w.file
+	}
+	return filepath.Ext(path) == ".prisma" && strings.HasPrefix(path, w.file+string(filepath.Separator))
+}
+
 // Start starts watching the file
 func (w *Watcher) Start() error {
 	// Initial callback if needed
@@ -65,10 +94,15 @@ func (w *Watcher) Start() error {
 					return
 				}
 
-				// Check if the watched file was modified
-				if event.Op&fsnotify.Write == fsnotify.Write {
+				// Check if the watched file was modified. Files created or
+				// removed inside a schema folder change the schema as well.
+				changed := event.Op&fsnotify.Write == fsnotify.Write
+				if w.isDir {
+					changed = changed || event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0
+				}
+				if changed {
 					eventPath, err := filepath.Abs(event.Name)
-					if err == nil && eventPath == w.file {
+					if err == nil && w.matches(eventPath) {
 						// Debounce: reset timer on each event
 						debounceTimer.Reset(500 * time.Millisecond)
 						debounceCh = debounceTimer.C
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/satishbabariya/prisma-go/generator/codegen"
+)
+
+// generatedHeader starts the Go files generated by prisma-go
+var generatedHeader = []byte("// Code generated by prisma-go. DO NOT EDIT.")
+
+// StaleFiles runs generate on an empty directory and returns the files of
+// outputDir that differ from its output, relative to outputDir: files that
+// changed or are missing, and Go files generated by prisma-go that are no
+// longer generated. The manifest of the client is not compared; it only
+// caches hashes and depends on the build of prisma-go.
+func StaleFiles(outputDir string, generate func(dir string) error) ([]string, error) {
+	fresh, err := os.MkdirTemp("", "prisma-go-check-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(fresh)
+
+	if err := generate(fresh); err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	generated := make(map[string]bool)
+	err = filepath.WalkDir(fresh, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(fresh, path)
+		if err != nil {
+			return err
+		}
+		if rel == codegen.ManifestFile {
+			return nil
+		}
+		generated[rel] = true
+
+		want, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		got, err := os.ReadFile(filepath.Join(outputDir, rel))
+		if err != nil || !bytes.Equal(got, want) {
+			stale = append(stale, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare generated files: %w", err)
+	}
+
+	// Leftovers of models or options that were removed
+	entries, err := os.ReadDir(outputDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", outputDir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || generated[name] || filepath.Ext(name) != ".go" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(outputDir, name))
+		if err == nil && bytes.HasPrefix(data, generatedHeader) {
+			stale = append(stale, name)
+		}
+	}
+
+	sort.Strings(stale)
+	return stale, nil
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/generator/codegen"
+)
+
+func TestStaleFiles(t *testing.T) {
+	gen := newPluginGenerator(t)
+	output := t.TempDir()
+	if err := gen.GenerateClient(output); err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err := StaleFiles(output, gen.GenerateClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("fresh client has stale files %v", stale)
+	}
+
+	// The manifest is not compared
+	if err := os.WriteFile(filepath.Join(output, codegen.ManifestFile), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	userFile := filepath.Join(output, codegen.ModelFileName("User"))
+	if err := os.Remove(userFile); err != nil {
+		t.Fatal(err)
+	}
+	leftover := "// Code generated by prisma-go. DO NOT EDIT.\n\npackage generated\n"
+	if err := os.WriteFile(filepath.Join(output, "post_model.go"), []byte(leftover), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(output, "helpers.go"), []byte("package generated\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(output, codegen.ClientFile))
+	if err := os.WriteFile(filepath.Join(output, codegen.ClientFile), []byte(strings.Replace(string(data), "sqlite", "mysql", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err = StaleFiles(output, gen.GenerateClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{codegen.ClientFile, "post_model.go", "user_model.go"}
+	if !reflect.DeepEqual(stale, want) {
+		t.Errorf("stale = %v, want %v", stale, want)
+	}
+}
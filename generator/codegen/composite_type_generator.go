@@ -26,7 +26,7 @@ func GenerateCompositeTypesFromAST(schemaAST *ast.SchemaAst) []CompositeTypeInfo
 		}
 
 		for _, field := range compositeType.Fields {
-			fieldInfo := generateFieldInfo(field, compositeType.Name.Name)
+			fieldInfo := generateFieldInfo(field, compositeType.Name.Name, toSnakeCase(field.Name.Name))
 			compositeTypeInfo.Fields = append(compositeTypeInfo.Fields, fieldInfo)
 		}
 
@@ -0,0 +1,464 @@
+package codegen
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// ExtensionsFile is the file with the typed extensions of the client:
+// computed fields and custom methods, see renderExtensions
+const ExtensionsFile = "extensions.go"
+
+// ComputedField is a field of a /// @computed(...) doc comment of a model,
+// e.g. {Name: "fullName", GoType: "string", Needs: ["firstName",
+// "lastName"]} for @computed(fullName: String, needs: [firstName,
+// lastName]). Its value is computed from the fields it needs by the
+// extensions of the client. Err holds why a comment could not be parsed.
+type ComputedField struct {
+	Name   string
+	GoName string
+	GoType string
+	Needs  []string
+	Err    string
+}
+
+// computedTypes maps the types of computed fields to Go types
+var computedTypes = map[string]string{
+	"String":  "string",
+	"Int":     "int",
+	"BigInt":  "int64",
+	"Float":   "float64",
+	"Boolean": "bool",
+}
+
+// computedAnnotations reads the /// @computed(...) doc comments of a
+// model, one per computed field
+func computedAnnotations(model *ast.Model) []ComputedField {
+	const marker = "@computed("
+	var computed []ComputedField
+	doc := model.Documentation.GetText()
+	for {
+		start := strings.Index(doc, marker)
+		if start < 0 {
+			return computed
+		}
+		doc = doc[start+len(marker):]
+
+		args, end := splitValidationArgs(doc)
+		if end < 0 {
+			line, _, _ := strings.Cut(doc, "\n")
+			return append(computed, ComputedField{Err: fmt.Sprintf("unterminated @computed(%s", line)})
+		}
+		computed = append(computed, parseComputedField(args))
+		doc = doc[end+1:]
+	}
+}
+
+// parseComputedField parses the arguments of a @computed comment: the name
+// and type of the field, then the fields it needs
+func parseComputedField(args []string) ComputedField {
+	var field ComputedField
+	if len(args) != 2 {
+		field.Err = "@computed takes a field and its needs, e.g. @computed(fullName: String, needs: [firstName, lastName])"
+		return field
+	}
+	name, typ, _ := strings.Cut(args[0], ":")
+	field.Name, typ = strings.TrimSpace(name), strings.TrimSpace(typ)
+	field.GoName = toPascalCase(field.Name)
+	if !token.IsIdentifier(field.Name) {
+		field.Err = fmt.Sprintf("invalid computed field name %q", field.Name)
+		return field
+	}
+	field.GoType = computedTypes[typ]
+	if field.GoType == "" {
+		field.Err = fmt.Sprintf("computed field %s has unsupported type %q, use String, Int, BigInt, Float or Boolean", field.Name, typ)
+		return field
+	}
+
+	key, needs, _ := strings.Cut(args[1], ":")
+	needs = strings.TrimSpace(needs)
+	if strings.TrimSpace(key) != "needs" || !strings.HasPrefix(needs, "[") || !strings.HasSuffix(needs, "]") {
+		field.Err = fmt.Sprintf("computed field %s needs a list of fields, e.g. needs: [firstName]", field.Name)
+		return field
+	}
+	for _, need := range strings.Split(needs[1:len(needs)-1], ",") {
+		if need = strings.TrimSpace(need); need != "" {
+			field.Needs = append(field.Needs, need)
+		}
+	}
+	if len(field.Needs) == 0 {
+		field.Err = fmt.Sprintf("computed field %s needs no field", field.Name)
+	}
+	return field
+}
+
+// computedModel is a model with computed fields, or with relations to
+// models with computed fields, checked against its fields
+type computedModel struct {
+	ModelInfo
+	fields    []computedFieldInfo
+	relations []FieldInfo // relation fields to models computing fields
+}
+
+// computedFieldInfo is a computed field with the fields it needs
+type computedFieldInfo struct {
+	ComputedField
+	needs  []FieldInfo
+	params []string // parameter names of the needed fields
+}
+
+// checkComputedFields checks the computed fields of model against its
+// fields
+func checkComputedFields(model ModelInfo) ([]computedFieldInfo, error) {
+	byName := make(map[string]FieldInfo, len(model.Fields))
+	taken := make(map[string]bool, len(model.Fields))
+	for _, field := range model.Fields {
+		byName[field.Name] = field
+		taken[field.GoName] = true
+	}
+
+	var fields []computedFieldInfo
+	for _, computed := range model.Computed {
+		if computed.Err != "" {
+			return nil, fmt.Errorf("%s: %s", model.Name, computed.Err)
+		}
+		if _, ok := byName[computed.Name]; ok || taken[computed.GoName] {
+			return nil, fmt.Errorf("%s: computed field %s clashes with another field", model.Name, computed.Name)
+		}
+		taken[computed.GoName] = true
+
+		f := computedFieldInfo{ComputedField: computed}
+		seen := make(map[string]bool, len(computed.Needs))
+		for _, need := range computed.Needs {
+			field, ok := byName[need]
+			switch {
+			case !ok:
+				return nil, fmt.Errorf("%s: computed field %s needs unknown field %s", model.Name, computed.Name, need)
+			case field.IsRelation:
+				return nil, fmt.Errorf("%s: computed field %s cannot need relation field %s", model.Name, computed.Name, need)
+			case seen[need]:
+				return nil, fmt.Errorf("%s: computed field %s needs %s twice", model.Name, computed.Name, need)
+			}
+			seen[need] = true
+			param := need
+			if token.IsKeyword(param) {
+				param += "_"
+			}
+			f.needs = append(f.needs, field)
+			f.params = append(f.params, param)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// computedModels returns the models whose results hold computed fields,
+// directly or through their relations, in the order of models
+func computedModels(models []ModelInfo) ([]*computedModel, error) {
+	computing := make(map[string]bool)
+	var checked []*computedModel
+	for _, model := range models {
+		fields, err := checkComputedFields(model)
+		if err != nil {
+			return nil, err
+		}
+		checked = append(checked, &computedModel{ModelInfo: model, fields: fields})
+		computing[model.Name] = len(fields) > 0
+	}
+
+	// A model computes fields of its results when a related one does
+	for changed := true; changed; {
+		changed = false
+		for _, model := range checked {
+			for _, field := range model.Fields {
+				if field.IsRelation && computing[field.RelationTo] && !computing[model.Name] {
+					computing[model.Name] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	var result []*computedModel
+	for _, model := range checked {
+		if !computing[model.Name] {
+			continue
+		}
+		for _, field := range model.Fields {
+			if field.IsRelation && computing[field.RelationTo] {
+				model.relations = append(model.relations, field)
+			}
+		}
+		result = append(result, model)
+	}
+	return result, nil
+}
+
+// renderExtensions renders extensions.go: Extends and Extend derive a
+// client computing the fields declared with /// @computed(...) comments of
+// models from the functions of Extension, and with custom methods, without
+// changing the client they derive from. It returns nil for a schema
+// without models.
+func renderExtensions(models []ModelInfo) ([]byte, error) {
+	if len(models) == 0 {
+		return nil, nil
+	}
+	computed, err := computedModels(models)
+	if err != nil {
+		return nil, err
+	}
+
+	var body strings.Builder
+	body.WriteString(`
+// Extension extends a client with the functions computing the fields
+// declared with /// @computed(...) comments of models, see Extends
+type Extension struct {
+	Result ResultExtension
+}
+
+// ResultExtension holds the functions computing the fields of models.
+// Fields without a function are left zero, unless an extension the client
+// was extended with before computes them.
+type ResultExtension struct {
+`)
+	for _, model := range computed {
+		if len(model.fields) > 0 {
+			fmt.Fprintf(&body, "%s %sResult\n", model.Name, model.Name)
+		}
+	}
+	body.WriteString("}\n")
+
+	for _, model := range computed {
+		if len(model.fields) == 0 {
+			continue
+		}
+		fmt.Fprintf(&body, "\n// %sResult holds the functions computing the fields of %s\ntype %sResult struct {\n", model.Name, model.Name, model.Name)
+		for _, f := range model.fields {
+			params := make([]string, len(f.needs))
+			for i, need := range f.needs {
+				params[i] = f.params[i] + " " + need.GoType
+			}
+			fmt.Fprintf(&body, "%s func(%s) %s // %s from %s\n", f.GoName, strings.Join(params, ", "), f.GoType, f.Name, strings.Join(f.Needs, ", "))
+		}
+		body.WriteString("}\n")
+	}
+
+	body.WriteString(`
+// computation holds the extensions computing the fields of the result of
+// a call, from the least to the most recently added
+type computation struct {
+	extensions []Extension
+}
+
+// computationKey is the context key of the computation of a call
+type computationKey struct{}
+
+// computeFields returns the middleware of a client extended with
+// extensions. The middleware of the outermost extended client computes the
+// fields of the result, with the extensions of the clients it was extended
+// into, so that the most recent extension computing a field wins.
+func computeFields(extensions []Extension) executor.Middleware {
+	return func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		for _, extension := range extensions {
+			selectNeeded(call, extension.Result)
+		}
+		if c, ok := ctx.Value(computationKey{}).(*computation); ok {
+			c.extensions = append(c.extensions, extensions...)
+			return next(ctx)
+		}
+		c := &computation{extensions: append([]Extension(nil), extensions...)}
+		if err := next(context.WithValue(ctx, computationKey{}, c)); err != nil {
+			return err
+		}
+		c.compute(call)
+		return nil
+	}
+}
+
+// selectNeeded adds the columns the functions of r need to the selected
+// columns of call, when only some are selected
+func selectNeeded(call *executor.Call, r ResultExtension) {
+	if call.Args == nil || len(call.Args.Select) == 0 {
+		return
+	}
+	var columns []string
+	switch call.Model {
+`)
+	for _, model := range computed {
+		if len(model.fields) == 0 {
+			continue
+		}
+		fmt.Fprintf(&body, "case %q:\n", model.Name)
+		for _, f := range model.fields {
+			quoted := make([]string, len(f.needs))
+			for i, need := range f.needs {
+				quoted[i] = strconv.Quote(need.DBName)
+			}
+			fmt.Fprintf(&body, "if r.%s.%s != nil {\ncolumns = append(columns, %s)\n}\n", model.Name, f.GoName, strings.Join(quoted, ", "))
+		}
+	}
+	body.WriteString(`}
+	if len(columns) == 0 {
+		return
+	}
+	selected := make(map[string]bool, len(call.Args.Select)+len(columns))
+	for column, ok := range call.Args.Select {
+		selected[column] = ok
+	}
+	for _, column := range columns {
+		selected[column] = true
+	}
+	call.Args.Select = selected
+}
+
+// compute sets the computed fields of the result of call
+func (c *computation) compute(call *executor.Call) {
+	switch call.Model {
+`)
+	for _, model := range computed {
+		fmt.Fprintf(&body, `case %[1]q:
+if records, ok := executor.ResultOf[[]%[1]s](call); ok {
+for i := range *records {
+c.compute%[1]s(&(*records)[i])
+}
+}
+if record, ok := executor.ResultOf[%[1]s](call); ok {
+c.compute%[1]s(record)
+}
+`, model.Name)
+	}
+	body.WriteString("}\n}\n")
+
+	for _, model := range computed {
+		fmt.Fprintf(&body, "\n// compute%[1]s sets the computed fields of m and of its included records\nfunc (c *computation) compute%[1]s(m *%[1]s) {\n", model.Name)
+		for _, f := range model.fields {
+			args := make([]string, len(f.needs))
+			for i, need := range f.needs {
+				args[i] = "m." + need.GoName
+			}
+			fmt.Fprintf(&body, `for i := len(c.extensions) - 1; i >= 0; i-- {
+if compute := c.extensions[i].Result.%s.%s; compute != nil {
+m.%s = compute(%s)
+break
+}
+}
+`, model.Name, f.GoName, f.GoName, strings.Join(args, ", "))
+		}
+		for _, relation := range model.relations {
+			if relation.IsList {
+				fmt.Fprintf(&body, "for i := range m.%[1]s {\nc.compute%[2]s(&m.%[1]s[i])\n}\n", relation.GoName, relation.RelationTo)
+			} else {
+				fmt.Fprintf(&body, "if m.%[1]s != nil {\nc.compute%[2]s(m.%[1]s)\n}\n", relation.GoName, relation.RelationTo)
+			}
+		}
+		body.WriteString("}\n")
+	}
+
+	first := models[0].Name
+	fmt.Fprintf(&body, `
+// Extends returns a client computing the fields of models with the
+// functions of extensions, after those of the extensions c was extended
+// with. The returned client shares the connection, observers and cache of
+// c and runs its middleware, but middleware and extensions added to it do
+// not change c.
+func (c *PrismaClient) Extends(extensions ...Extension) *PrismaClient {
+	base := c.PrismaClient.Derive()
+	if len(extensions) > 0 {
+		base.Intercept(computeFields(extensions))
+	}
+	exec := c.%s.executor.Derive(base.Pipeline())
+	return &PrismaClient{
+		PrismaClient: base,
+`, first)
+	for _, model := range models {
+		fmt.Fprintf(&body, "%[1]s: &%[1]sClient{client: base, executor: exec, table: c.%[1]s.table, relations: c.%[1]s.relations},\n", model.Name)
+	}
+	body.WriteString(`	}
+}
+
+// ExtendedClient is a client extended with custom methods, see Extend
+type ExtendedClient[M any] struct {
+	*PrismaClient
+	Methods M
+}
+
+// Extend returns a client extended with extensions, as by Extends, and
+// with the methods built by methods against it. Methods may be grouped by
+// model, e.g.
+//
+//	type Methods struct {
+//		User struct {
+//			SignUp func(ctx context.Context, email string) (*User, error)
+//		}
+//	}
+//
+// and call the model clients of the extended client they are given, whose
+// results hold the computed fields.
+func Extend[M any](c *PrismaClient, methods func(c *PrismaClient) M, extensions ...Extension) *ExtendedClient[M] {
+	extended := c.Extends(extensions...)
+	return &ExtendedClient[M]{PrismaClient: extended, Methods: methods(extended)}
+}
+`)
+
+	imports, err := usedImports(body.String(), models)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ExtensionsFile, err)
+	}
+	var sb strings.Builder
+	sb.WriteString(generatedHeader + "\n\npackage generated\n\nimport (\n")
+	std := true
+	for _, spec := range imports {
+		if std && strings.Contains(spec, ".") {
+			sb.WriteString("\n")
+			std = false
+		}
+		fmt.Fprintf(&sb, "\t%s\n", spec)
+	}
+	sb.WriteString(")\n")
+	sb.WriteString(body.String())
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", ExtensionsFile, err)
+	}
+	return src, nil
+}
+
+// usedImports returns the import specs of the packages body refers to,
+// standard library packages first
+func usedImports(body string, models []ModelInfo) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package generated\n"+body, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	addUsedImports(file, models)
+	decl, ok := file.Decls[0].(*goast.GenDecl)
+	if !ok || decl.Tok != token.IMPORT {
+		return nil, nil
+	}
+	var std, others []string
+	for _, spec := range decl.Specs {
+		spec := spec.(*goast.ImportSpec)
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		line := spec.Path.Value
+		if spec.Name != nil {
+			line = spec.Name.Name + " " + line
+		}
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			others = append(others, line)
+		} else {
+			std = append(std, line)
+		}
+	}
+	return append(std, others...), nil
+}
@@ -0,0 +1,101 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const computedSchema = `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+/// A member
+/// @computed(fullName: String, needs: [firstName, lastName])
+/// @computed(adult: Boolean, needs: [age])
+model User {
+  id        Int     @id
+  firstName String
+  lastName  String?
+  age       Int
+  posts     Post[]
+}
+
+model Post {
+  id       Int  @id
+  authorId Int
+  author   User @relation(fields: [authorId], references: [id])
+}
+
+model Tag {
+  id Int @id
+}
+`
+
+func TestComputedAnnotations(t *testing.T) {
+	models := validatedModels(t, computedSchema)
+	want := []ComputedField{
+		{Name: "fullName", GoName: "Fullname", GoType: "string", Needs: []string{"firstName", "lastName"}},
+		{Name: "adult", GoName: "Adult", GoType: "bool", Needs: []string{"age"}},
+	}
+	if !reflect.DeepEqual(models[0].Computed, want) {
+		t.Errorf("computed fields = %+v, want %+v", models[0].Computed, want)
+	}
+	if len(models[1].Computed) != 0 {
+		t.Errorf("Post has computed fields %+v", models[1].Computed)
+	}
+}
+
+func TestRenderExtensions(t *testing.T) {
+	src, err := renderExtensions(validatedModels(t, computedSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), ExtensionsFile, src, 0); err != nil {
+		t.Fatalf("invalid %s: %v\n%s", ExtensionsFile, err, src)
+	}
+	for _, want := range []string{
+		"Fullname func(firstName string, lastName *string) string // fullName from firstName, lastName",
+		"Adult    func(age int) bool",
+		`columns = append(columns, "first_name", "last_name")`,
+		"m.Fullname = compute(m.Firstname, m.Lastname)",
+		"for i := range m.Posts {\n\t\tc.computePost(&m.Posts[i])",
+		"if m.Author != nil {\n\t\tc.computeUser(m.Author)",
+		"Tag:          &TagClient{client: base, executor: exec, table: c.Tag.table, relations: c.Tag.relations},",
+		"func Extend[M any](c *PrismaClient, methods func(c *PrismaClient) M, extensions ...Extension) *ExtendedClient[M] {",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("%s does not contain %q", ExtensionsFile, want)
+		}
+	}
+	if strings.Contains(string(src), "computeTag") {
+		t.Error("Tag has no computed fields but is computed")
+	}
+
+	// Computed fields are not columns
+	user, err := renderASTFile(buildModelFile(validatedModels(t, computedSchema)[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(user), "`json:\"full_name\" db:\"-\"`") {
+		t.Errorf("User has no computed fields:\n%s", user)
+	}
+
+	for comment, want := range map[string]string{
+		"/// @computed(fullName: Json, needs: [name])":   `unsupported type "Json"`,
+		"/// @computed(fullName: String, needs: [nick])": "needs unknown field nick",
+		"/// @computed(name: String, needs: [id])":       "clashes with another field",
+		"/// @computed(fullName: String, needs: name)":   "needs a list of fields",
+		"/// @computed(fullName: String":                 "unterminated",
+	} {
+		schema := "datasource db {\n  provider = \"sqlite\"\n  url = \"file:dev.db\"\n}\n\n" + comment + "\nmodel User {\n  id Int @id\n  name String\n}\n"
+		_, err := renderExtensions(validatedModels(t, schema))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: got error %v, want %q", comment, err, want)
+		}
+	}
+}
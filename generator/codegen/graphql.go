@@ -0,0 +1,962 @@
+// Package codegen provides GraphQL schema and resolver generation.
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ast "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// Files written next to the client by GenerateGraphQLFiles
+const (
+	GraphQLSchemaFile    = "schema.graphql"
+	GraphQLResolversFile = "graphql.go"
+)
+
+// graphQLScalar is how a Prisma scalar type is exposed in GraphQL
+type graphQLScalar struct {
+	name    string // GraphQL type
+	goType  string // Go type of filter values
+	builtin bool   // built-in GraphQL scalar, not declared in the schema
+	ops     []graphQLOp
+}
+
+// graphQLOp is an operation of a filter input type
+type graphQLOp struct {
+	name   string // GraphQL input field
+	goName string
+	list   bool
+	call   string // WhereBuilder method, or a LIKE pattern with %s for the value
+}
+
+var (
+	equalityOps = []graphQLOp{
+		{name: "equals", goName: "Equals", call: "Equals"},
+		{name: "not", goName: "Not", call: "NotEquals"},
+		{name: "in", goName: "In", list: true, call: "graphQLIn"},
+		{name: "notIn", goName: "NotIn", list: true, call: "graphQLNotIn"},
+	}
+	comparisonOps = append(equalityOps[:len(equalityOps):len(equalityOps)],
+		graphQLOp{name: "lt", goName: "Lt", call: "LessThan"},
+		graphQLOp{name: "lte", goName: "Lte", call: "LessOrEqual"},
+		graphQLOp{name: "gt", goName: "Gt", call: "GreaterThan"},
+		graphQLOp{name: "gte", goName: "Gte", call: "GreaterOrEqual"},
+	)
+	stringOps = append(comparisonOps[:len(comparisonOps):len(comparisonOps)],
+		graphQLOp{name: "contains", goName: "Contains", call: `"%%"+%s+"%%"`},
+		graphQLOp{name: "startsWith", goName: "StartsWith", call: `%s+"%%"`},
+		graphQLOp{name: "endsWith", goName: "EndsWith", call: `"%%"+%s`},
+	)
+	booleanOps = equalityOps[:2]
+)
+
+// graphQLScalarOrder is the order scalars and their filters are declared in
+var graphQLScalarOrder = []string{"String", "Int", "BigInt", "Float", "Decimal", "Boolean", "DateTime", "Json", "Bytes"}
+
+var graphQLScalars = map[string]graphQLScalar{
+	"String":   {name: "String", goType: "string", builtin: true, ops: stringOps},
+	"Int":      {name: "Int", goType: "int", builtin: true, ops: comparisonOps},
+	"BigInt":   {name: "BigInt", goType: "int64", ops: comparisonOps},
+	"Float":    {name: "Float", goType: "float64", builtin: true, ops: comparisonOps},
+	"Decimal":  {name: "Decimal", goType: "types.Decimal", ops: comparisonOps},
+	"Boolean":  {name: "Boolean", goType: "bool", builtin: true, ops: booleanOps},
+	"DateTime": {name: "DateTime", goType: "time.Time", ops: comparisonOps},
+	"Json":     {name: "Json"},
+	"Bytes":    {name: "Bytes"},
+}
+
+// graphQLModel is a model with the fields exposed in GraphQL
+type graphQLModel struct {
+	ModelInfo
+	doc       string
+	fields    []*graphQLField
+	relations []*graphQLRelation
+}
+
+// graphQLField is a scalar or enum field
+type graphQLField struct {
+	FieldInfo
+	doc      string
+	typ      string // GraphQL type of a value
+	goType   string // Go type of a value
+	list     bool
+	optional bool
+	filter   string // filter input type, empty for fields that cannot be filtered
+}
+
+// graphQLRelation is a relation field resolved with a loader. Records are
+// loaded by the value of key, matched against relatedKey of the related
+// model.
+type graphQLRelation struct {
+	FieldInfo
+	doc        string
+	related    string
+	list       bool
+	required   bool
+	holder     bool // the model holds the foreign key
+	key        *graphQLField
+	relatedKey *graphQLField
+}
+
+// unique reports whether a record can be found by the field
+func (f *graphQLField) unique() bool {
+	return (f.IsID || f.IsUnique) && !f.list && f.filter != ""
+}
+
+// pointer reports whether the model struct holds the field as a pointer
+func (f *graphQLField) pointer() bool {
+	return strings.HasPrefix(f.GoType, "*")
+}
+
+// loaderKey reports whether values of the field can key a loader
+func (f *graphQLField) loaderKey(enums map[string]bool) bool {
+	switch f.goType {
+	case "int", "int64", "string":
+		return !f.list
+	}
+	return !f.list && enums[f.goType]
+}
+
+// GenerateGraphQLFiles writes schema.graphql, a GraphQL schema with
+// Prisma-style where, orderBy and cursor arguments for every model, and
+// graphql.go, the resolvers translating those arguments into client
+// queries. Relation fields are resolved through dataloaders.
+func GenerateGraphQLFiles(schemaAST *ast.SchemaAst, models []ModelInfo, outputDir string) error {
+	gqlModels := graphQLModels(schemaAST, models)
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	sdl := renderGraphQLSchema(schemaAST, gqlModels)
+	if _, err := writeGeneratedFile(filepath.Join(outputDir, GraphQLSchemaFile), []byte(sdl)); err != nil {
+		return err
+	}
+	src, err := renderGraphQLResolvers(schemaAST, gqlModels)
+	if err != nil {
+		return err
+	}
+	if _, err := writeGeneratedFile(filepath.Join(outputDir, GraphQLResolversFile), src); err != nil {
+		return err
+	}
+	return nil
+}
+
+// graphQLModels collects the fields and relations of models exposed in
+// GraphQL. Composite type fields are left out, and so are relations
+// without single field keys, such as implicit many-to-many relations.
+func graphQLModels(schemaAST *ast.SchemaAst, models []ModelInfo) []*graphQLModel {
+	enums := make(map[string]bool)
+	for _, enum := range schemaAST.Enums() {
+		enums[enum.Name.Name] = true
+	}
+	astModels := make(map[string]*ast.Model)
+	for _, model := range schemaAST.Models() {
+		astModels[model.Name.Name] = model
+	}
+
+	var result []*graphQLModel
+	byName := make(map[string]*graphQLModel)
+	for _, model := range models {
+		astModel := astModels[model.Name]
+		if astModel == nil {
+			continue
+		}
+		m := &graphQLModel{ModelInfo: model, doc: docText(astModel.Documentation)}
+		for _, info := range model.Fields {
+			field := astField(astModel, info.Name)
+			if field == nil || field.Type == nil || info.IsRelation {
+				continue
+			}
+			f := &graphQLField{
+				FieldInfo: info,
+				doc:       docText(field.Documentation),
+				list:      field.Arity.IsList(),
+				optional:  field.Arity.IsOptional(),
+			}
+			if scalar, ok := graphQLScalars[field.Type.Name]; ok {
+				f.typ, f.goType = scalar.name, scalar.goType
+				if scalar.ops != nil && !f.list {
+					f.filter = scalar.name + "Filter"
+				}
+			} else if enums[field.Type.Name] {
+				f.typ, f.goType = field.Type.Name, field.Type.Name
+				if !f.list {
+					f.filter = field.Type.Name + "Filter"
+				}
+			} else {
+				continue
+			}
+			m.fields = append(m.fields, f)
+		}
+		result = append(result, m)
+		byName[m.Name] = m
+	}
+
+	for _, m := range result {
+		astModel := astModels[m.Name]
+		for _, info := range m.Fields {
+			related := byName[info.RelationTo]
+			field := astField(astModel, info.Name)
+			if !info.IsRelation || related == nil || field == nil {
+				continue
+			}
+			holder, fields, references, ok := relationKeys(schemaAST, astModel, field)
+			if !ok || len(fields) != 1 || len(references) != 1 {
+				continue
+			}
+			r := &graphQLRelation{
+				FieldInfo: info,
+				doc:       docText(field.Documentation),
+				related:   related.Name,
+				list:      field.Arity.IsList(),
+				required:  !field.Arity.IsOptional(),
+			}
+			r.holder = holder == m.Name && !r.list
+			if r.holder {
+				r.key, r.relatedKey = m.field(fields[0]), related.field(references[0])
+			} else {
+				r.key, r.relatedKey = m.field(references[0]), related.field(fields[0])
+			}
+			if r.key == nil || r.relatedKey == nil || !r.key.loaderKey(enums) || r.key.goType != r.relatedKey.goType {
+				continue
+			}
+			m.relations = append(m.relations, r)
+		}
+	}
+	return result
+}
+
+// field returns the scalar or enum field with the given schema name
+func (m *graphQLModel) field(name string) *graphQLField {
+	for _, f := range m.fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// uniqueFields returns the fields a record can be found by
+func (m *graphQLModel) uniqueFields() []*graphQLField {
+	var unique []*graphQLField
+	for _, f := range m.fields {
+		if f.unique() {
+			unique = append(unique, f)
+		}
+	}
+	return unique
+}
+
+func astField(model *ast.Model, name string) *ast.Field {
+	for _, field := range model.Fields {
+		if field.GetName() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// docText returns the text of a /// documentation comment
+func docText(doc *ast.CommentBlock) string {
+	lines := strings.Split(doc.GetText(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// renderGraphQLSchema renders the GraphQL schema of models
+func renderGraphQLSchema(schemaAST *ast.SchemaAst, models []*graphQLModel) string {
+	var sb strings.Builder
+	sb.WriteString("# Code generated by prisma-go. DO NOT EDIT.\n")
+
+	scalars, filters := graphQLUsedTypes(models)
+	for _, name := range graphQLScalarOrder {
+		if scalar := graphQLScalars[name]; scalars[name] && !scalar.builtin {
+			fmt.Fprintf(&sb, "\nscalar %s\n", scalar.name)
+		}
+	}
+
+	sb.WriteString("\nenum SortOrder {\n  asc\n  desc\n}\n")
+	for _, enum := range schemaAST.Enums() {
+		sb.WriteString("\n")
+		writeGraphQLDescription(&sb, "", docText(enum.Documentation))
+		fmt.Fprintf(&sb, "enum %s {\n", enum.Name.Name)
+		for _, value := range enum.Values {
+			writeGraphQLDescription(&sb, "  ", docText(value.Documentation))
+			fmt.Fprintf(&sb, "  %s\n", value.Name.Name)
+		}
+		sb.WriteString("}\n")
+	}
+
+	for _, m := range models {
+		sb.WriteString("\n")
+		writeGraphQLDescription(&sb, "", m.doc)
+		fmt.Fprintf(&sb, "type %s {\n", m.Name)
+		for _, f := range m.fields {
+			writeGraphQLDescription(&sb, "  ", f.doc)
+			fmt.Fprintf(&sb, "  %s: %s\n", f.Name, graphQLTypeRef(f.typ, f.list, !f.optional))
+		}
+		for _, r := range m.relations {
+			writeGraphQLDescription(&sb, "  ", r.doc)
+			fmt.Fprintf(&sb, "  %s: %s\n", r.Name, graphQLTypeRef(r.related, r.list, r.required))
+		}
+		sb.WriteString("}\n")
+	}
+
+	for _, filter := range filters {
+		fmt.Fprintf(&sb, "\ninput %s {\n", filter.name)
+		for _, op := range filter.ops {
+			fmt.Fprintf(&sb, "  %s: %s\n", op.name, graphQLTypeRef(filter.typ, op.list, false))
+		}
+		sb.WriteString("  isNull: Boolean\n}\n")
+	}
+
+	for _, m := range models {
+		fmt.Fprintf(&sb, "\ninput %sWhereInput {\n", m.Name)
+		fmt.Fprintf(&sb, "  AND: [%[1]sWhereInput!]\n  OR: [%[1]sWhereInput!]\n  NOT: %[1]sWhereInput\n", m.Name)
+		for _, f := range m.fields {
+			if f.filter != "" {
+				fmt.Fprintf(&sb, "  %s: %s\n", f.Name, f.filter)
+			}
+		}
+		sb.WriteString("}\n")
+
+		if unique := m.uniqueFields(); len(unique) > 0 {
+			fmt.Fprintf(&sb, "\ninput %sWhereUniqueInput {\n", m.Name)
+			for _, f := range unique {
+				fmt.Fprintf(&sb, "  %s: %s\n", f.Name, f.typ)
+			}
+			sb.WriteString("}\n")
+		}
+
+		fmt.Fprintf(&sb, "\ninput %sOrderByInput {\n", m.Name)
+		for _, f := range m.fields {
+			if f.filter != "" {
+				fmt.Fprintf(&sb, "  %s: SortOrder\n", f.Name)
+			}
+		}
+		sb.WriteString("}\n")
+	}
+
+	sb.WriteString("\ntype Query {\n")
+	for _, m := range models {
+		cursor := ""
+		if len(m.uniqueFields()) > 0 {
+			fmt.Fprintf(&sb, "  %s(where: %sWhereUniqueInput!): %s\n", lowerFirst(m.Name), m.Name, m.Name)
+			cursor = fmt.Sprintf(", cursor: %sWhereUniqueInput", m.Name)
+		}
+		fmt.Fprintf(&sb, "  %s(where: %sWhereInput, orderBy: [%sOrderByInput!]%s, take: Int, skip: Int): [%s!]!\n",
+			lowerFirst(Plural(m.Name)), m.Name, m.Name, cursor, m.Name)
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// graphQLFilter is a filter input type
+type graphQLFilter struct {
+	name   string
+	typ    string // GraphQL type of the values
+	goType string // Go type of the values
+	ops    []graphQLOp
+}
+
+// graphQLUsedTypes returns the scalars models use and the filter types of
+// their fields, in declaration order
+func graphQLUsedTypes(models []*graphQLModel) (map[string]bool, []graphQLFilter) {
+	scalars := make(map[string]bool)
+	used := make(map[string]bool)
+	var enumFilters []graphQLFilter
+	for _, m := range models {
+		for _, f := range m.fields {
+			if _, ok := graphQLScalars[f.typ]; ok {
+				scalars[f.typ] = true
+			} else if f.filter != "" && !used[f.filter] {
+				enumFilters = append(enumFilters, graphQLFilter{name: f.filter, typ: f.typ, goType: f.goType, ops: equalityOps})
+			}
+			used[f.filter] = true
+		}
+	}
+
+	var filters []graphQLFilter
+	for _, name := range graphQLScalarOrder {
+		scalar := graphQLScalars[name]
+		if used[scalar.name+"Filter"] {
+			filters = append(filters, graphQLFilter{name: scalar.name + "Filter", typ: scalar.name, goType: scalar.goType, ops: scalar.ops})
+		}
+	}
+	return scalars, append(filters, enumFilters...)
+}
+
+func graphQLTypeRef(typ string, list, required bool) string {
+	if list {
+		typ = "[" + typ + "!]"
+	}
+	if required {
+		typ += "!"
+	}
+	return typ
+}
+
+func writeGraphQLDescription(sb *strings.Builder, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, `"""`, `\"""`)
+	fmt.Fprintf(sb, "%s\"\"\"\n", indent)
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(sb, "%s%s\n", indent, line)
+	}
+	fmt.Fprintf(sb, "%s\"\"\"\n", indent)
+}
+
+// renderGraphQLResolvers renders graphql.go: the Go types of the GraphQL
+// inputs, the resolvers of the queries and relation fields, and the
+// loaders batching relation loads
+func renderGraphQLResolvers(schemaAST *ast.SchemaAst, models []*graphQLModel) ([]byte, error) {
+	_, filters := graphQLUsedTypes(models)
+	hasUnique, hasRelations := false, false
+	for _, m := range models {
+		hasUnique = hasUnique || len(m.uniqueFields()) > 0
+		hasRelations = hasRelations || len(m.relations) > 0
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by prisma-go. DO NOT EDIT.\n\npackage generated\n\nimport (\n\t\"context\"\n")
+	if hasUnique {
+		sb.WriteString("\t\"database/sql\"\n\t\"errors\"\n")
+	}
+	sb.WriteString("\t\"fmt\"\n\t\"io\"\n\t\"strconv\"\n")
+	if graphQLFiltersUse(filters, "time.Time") {
+		sb.WriteString("\t\"time\"\n")
+	}
+	sb.WriteString("\n\t\"github.com/satishbabariya/prisma-go/query/builder\"\n")
+	if hasRelations {
+		sb.WriteString("\t\"github.com/satishbabariya/prisma-go/runtime/dataloader\"\n")
+	}
+	if graphQLFiltersUse(filters, "types.Decimal") {
+		sb.WriteString("\t\"github.com/satishbabariya/prisma-go/runtime/types\"\n")
+	}
+	sb.WriteString(")\n")
+
+	sb.WriteString(graphQLRuntime)
+
+	for _, enum := range schemaAST.Enums() {
+		writeGraphQLEnum(&sb, enum)
+	}
+	for _, filter := range filters {
+		writeGraphQLFilter(&sb, filter)
+	}
+	for _, m := range models {
+		writeGraphQLInputs(&sb, m)
+	}
+
+	sb.WriteString(`
+// GraphQLResolver resolves the queries and relation fields of schema.graphql
+// with the client. Relation fields are loaded in batches by the loaders of
+// the request context, see WithGraphQLLoaders.
+type GraphQLResolver struct {
+	Client *PrismaClient
+}
+
+// NewGraphQLResolver creates a resolver querying with client
+func NewGraphQLResolver(client *PrismaClient) *GraphQLResolver {
+	return &GraphQLResolver{Client: client}
+}
+`)
+	for _, m := range models {
+		writeGraphQLQueryResolvers(&sb, m)
+	}
+	if hasRelations {
+		writeGraphQLLoaders(&sb, models)
+	}
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", GraphQLResolversFile, err)
+	}
+	return src, nil
+}
+
+func graphQLFiltersUse(filters []graphQLFilter, goType string) bool {
+	for _, filter := range filters {
+		if filter.goType == goType {
+			return true
+		}
+	}
+	return false
+}
+
+// graphQLRuntime is the part of graphql.go that does not depend on the
+// schema
+const graphQLRuntime = `
+// SortOrder is the direction of an orderBy field
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+// MarshalGQL writes the order as a GraphQL enum value
+func (o SortOrder) MarshalGQL(w io.Writer) {
+	io.WriteString(w, strconv.Quote(string(o)))
+}
+
+// UnmarshalGQL reads the order from a GraphQL enum value
+func (o *SortOrder) UnmarshalGQL(v interface{}) error {
+	switch v {
+	case "asc":
+		*o = SortOrderAsc
+	case "desc":
+		*o = SortOrderDesc
+	default:
+		return fmt.Errorf("invalid SortOrder %v", v)
+	}
+	return nil
+}
+
+// graphQLOrder is a column of an orderBy with its value in the cursor row
+type graphQLOrder struct {
+	column string
+	desc   bool
+	value  interface{}
+}
+
+// graphQLOrderBy returns the ORDER BY of orders, nil when there is none
+func graphQLOrderBy(orders []graphQLOrder) *builder.OrderByBuilder {
+	if len(orders) == 0 {
+		return nil
+	}
+	orderBy := builder.NewOrderByBuilder()
+	for _, o := range orders {
+		if o.desc {
+			orderBy.Desc(o.column)
+		} else {
+			orderBy.Asc(o.column)
+		}
+	}
+	return orderBy
+}
+
+// graphQLCursor returns the condition selecting the cursor row and the rows
+// after it in the order of orders: rows equal to the cursor on the first
+// columns and after it on the next one, or equal on all of them. A NULL
+// cursor value only matches NULL.
+func graphQLCursor(orders []graphQLOrder) *builder.WhereBuilder {
+	var branches []*builder.WhereBuilder
+	for i, o := range orders {
+		if o.value == nil {
+			continue
+		}
+		branch := builder.NewSubWhereBuilder()
+		for _, prev := range orders[:i] {
+			graphQLEqual(branch, prev)
+		}
+		if o.desc {
+			branch.LessThan(o.column, o.value)
+		} else {
+			branch.GreaterThan(o.column, o.value)
+		}
+		branches = append(branches, branch)
+	}
+	last := builder.NewSubWhereBuilder()
+	for _, o := range orders {
+		graphQLEqual(last, o)
+	}
+	return builder.NewSubWhereBuilder().OR(append(branches, last)...)
+}
+
+func graphQLEqual(w *builder.WhereBuilder, o graphQLOrder) {
+	if o.value == nil {
+		w.IsNull(o.column)
+	} else {
+		w.Equals(o.column, o.value)
+	}
+}
+
+// graphQLTiebreak orders by the unique cursor column last, so the order of
+// rows is total
+func graphQLTiebreak(orders []graphQLOrder, column string) []graphQLOrder {
+	for _, o := range orders {
+		if o.column == column {
+			return orders
+		}
+	}
+	return append(orders, graphQLOrder{column: column})
+}
+
+// graphQLPage sets take and skip on a query
+func graphQLPage(take, skip *int, limit, offset func(int)) error {
+	if take != nil {
+		if *take < 0 {
+			return fmt.Errorf("take must not be negative")
+		}
+		limit(*take)
+	}
+	if skip != nil {
+		if *skip < 0 {
+			return fmt.Errorf("skip must not be negative")
+		}
+		offset(*skip)
+	}
+	return nil
+}
+
+// graphQLWheres returns the conditions of the inputs that have any
+func graphQLWheres[T any](inputs []T, where func(*T) *builder.WhereBuilder) []*builder.WhereBuilder {
+	var wheres []*builder.WhereBuilder
+	for i := range inputs {
+		if w := where(&inputs[i]); w != nil {
+			wheres = append(wheres, w)
+		}
+	}
+	return wheres
+}
+
+// graphQLIn adds an IN condition. An empty list matches no rows, which
+// IN () cannot express.
+func graphQLIn[T any](w *builder.WhereBuilder, column string, values []T) {
+	if len(values) == 0 {
+		w.IsNull(column).IsNotNull(column)
+		return
+	}
+	w.In(column, graphQLValues(values))
+}
+
+// graphQLNotIn adds a NOT IN condition. An empty list matches all rows.
+func graphQLNotIn[T any](w *builder.WhereBuilder, column string, values []T) {
+	if len(values) > 0 {
+		w.NotIn(column, graphQLValues(values))
+	}
+}
+
+func graphQLValues[T any](values []T) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+`
+
+// writeGraphQLEnum writes the gqlgen marshalers of an enum, mapping the
+// GraphQL value names to the database values of the Go constants
+func writeGraphQLEnum(sb *strings.Builder, enum *ast.Enum) {
+	name := enum.Name.Name
+	fmt.Fprintf(sb, "\n// MarshalGQL writes the %s as a GraphQL enum value\n", name)
+	fmt.Fprintf(sb, "func (e %s) MarshalGQL(w io.Writer) {\n\tswitch e {\n", name)
+	for _, value := range enum.Values {
+		fmt.Fprintf(sb, "\tcase %s%s:\n\t\tio.WriteString(w, %q)\n", name, value.Name.Name, `"`+value.Name.Name+`"`)
+	}
+	sb.WriteString("\tdefault:\n\t\tio.WriteString(w, strconv.Quote(string(e)))\n\t}\n}\n")
+
+	fmt.Fprintf(sb, "\n// UnmarshalGQL reads the %s from a GraphQL enum value\n", name)
+	fmt.Fprintf(sb, "func (e *%s) UnmarshalGQL(v interface{}) error {\n\tswitch v {\n", name)
+	for _, value := range enum.Values {
+		fmt.Fprintf(sb, "\tcase %q:\n\t\t*e = %s%s\n", value.Name.Name, name, value.Name.Name)
+	}
+	fmt.Fprintf(sb, "\tdefault:\n\t\treturn fmt.Errorf(\"invalid %s %%v\", v)\n\t}\n\treturn nil\n}\n", name)
+}
+
+// writeGraphQLFilter writes the Go type of a filter input type with the
+// method adding its conditions to a query
+func writeGraphQLFilter(sb *strings.Builder, filter graphQLFilter) {
+	fmt.Fprintf(sb, "\n// %s filters %s fields\ntype %s struct {\n", filter.name, filter.typ, filter.name)
+	for _, op := range filter.ops {
+		typ := "*" + filter.goType
+		if op.list {
+			typ = "[]" + filter.goType
+		}
+		fmt.Fprintf(sb, "\t%s %s `json:\"%s\"`\n", op.goName, typ, op.name)
+	}
+	sb.WriteString("\tIsNull *bool `json:\"isNull\"`\n}\n")
+
+	fmt.Fprintf(sb, "\n// apply adds the conditions of the filter on column to w and returns\n// their number\n")
+	fmt.Fprintf(sb, "func (f *%s) apply(w *builder.WhereBuilder, column string) int {\n", filter.name)
+	sb.WriteString("\tif f == nil {\n\t\treturn 0\n\t}\n\tn := 0\n")
+	for _, op := range filter.ops {
+		value := "f." + op.goName
+		switch {
+		case op.list:
+			fmt.Fprintf(sb, "\tif %s != nil {\n\t\t%s(w, column, %s)\n", value, op.call, value)
+		case strings.Contains(op.call, "%s"):
+			fmt.Fprintf(sb, "\tif %s != nil {\n\t\tw.Like(column, %s)\n", value, fmt.Sprintf(op.call, "*"+value))
+		default:
+			fmt.Fprintf(sb, "\tif %s != nil {\n\t\tw.%s(column, *%s)\n", value, op.call, value)
+		}
+		sb.WriteString("\t\tn++\n\t}\n")
+	}
+	sb.WriteString("\tif f.IsNull != nil {\n\t\tif *f.IsNull {\n\t\t\tw.IsNull(column)\n\t\t} else {\n\t\t\tw.IsNotNull(column)\n\t\t}\n\t\tn++\n\t}\n\treturn n\n}\n")
+}
+
+// writeGraphQLInputs writes the where, unique and orderBy input types of a
+// model
+func writeGraphQLInputs(sb *strings.Builder, m *graphQLModel) {
+	name := m.Name
+	fmt.Fprintf(sb, "\n// %sWhereInput filters %s records\ntype %sWhereInput struct {\n", name, name, name)
+	fmt.Fprintf(sb, "\tAND []%[1]sWhereInput `json:\"AND\"`\n\tOR []%[1]sWhereInput `json:\"OR\"`\n\tNOT *%[1]sWhereInput `json:\"NOT\"`\n", name)
+	for _, f := range m.fields {
+		if f.filter != "" {
+			fmt.Fprintf(sb, "\t%s *%s `json:\"%s\"`\n", f.GoName, f.filter, f.Name)
+		}
+	}
+	sb.WriteString("}\n")
+
+	fmt.Fprintf(sb, "\n// where returns the conditions of the input, nil when it has none\n")
+	fmt.Fprintf(sb, "func (in *%sWhereInput) where() *builder.WhereBuilder {\n", name)
+	sb.WriteString("\tif in == nil {\n\t\treturn nil\n\t}\n\tw := builder.NewSubWhereBuilder()\n\tn := 0\n")
+	for _, f := range m.fields {
+		if f.filter != "" {
+			fmt.Fprintf(sb, "\tn += in.%s.apply(w, %q)\n", f.GoName, f.DBName)
+		}
+	}
+	fmt.Fprintf(sb, "\tif and := graphQLWheres(in.AND, (*%sWhereInput).where); len(and) > 0 {\n\t\tw.AND(and...)\n\t\tn++\n\t}\n", name)
+	sb.WriteString("\t// An input without conditions matches every row, and so does OR with it\n")
+	fmt.Fprintf(sb, "\tif or := graphQLWheres(in.OR, (*%sWhereInput).where); len(or) > 0 && len(or) == len(in.OR) {\n\t\tw.OR(or...)\n\t\tn++\n\t}\n", name)
+	sb.WriteString("\tif not := in.NOT.where(); not != nil {\n\t\tw.NOT(not)\n\t\tn++\n\t}\n")
+	sb.WriteString("\tif n == 0 {\n\t\treturn nil\n\t}\n\treturn w\n}\n")
+
+	if unique := m.uniqueFields(); len(unique) > 0 {
+		fmt.Fprintf(sb, "\n// %sWhereUniqueInput finds a %s record by one unique field\ntype %sWhereUniqueInput struct {\n", name, name, name)
+		names := make([]string, len(unique))
+		for i, f := range unique {
+			fmt.Fprintf(sb, "\t%s *%s `json:\"%s\"`\n", f.GoName, f.goType, f.Name)
+			names[i] = f.Name
+		}
+		sb.WriteString("}\n")
+
+		fmt.Fprintf(sb, "\n// unique returns the column and value of the field set in the input\n")
+		fmt.Fprintf(sb, "func (in %sWhereUniqueInput) unique() (string, interface{}, error) {\n", name)
+		sb.WriteString("\tvar column string\n\tvar value interface{}\n\tn := 0\n")
+		for _, f := range unique {
+			fmt.Fprintf(sb, "\tif in.%s != nil {\n\t\tcolumn, value = %q, *in.%s\n\t\tn++\n\t}\n", f.GoName, f.DBName, f.GoName)
+		}
+		fmt.Fprintf(sb, "\tif n != 1 {\n\t\treturn \"\", nil, fmt.Errorf(\"%sWhereUniqueInput needs exactly one of %s\")\n\t}\n",
+			name, strings.Join(names, ", "))
+		sb.WriteString("\treturn column, value, nil\n}\n")
+
+		fmt.Fprintf(sb, "\n// graphQLValue returns the value of an orderBy column, nil for NULL\n")
+		fmt.Fprintf(sb, "func (m *%s) graphQLValue(column string) interface{} {\n\tswitch column {\n", name)
+		for _, f := range m.fields {
+			if f.filter == "" {
+				continue
+			}
+			fmt.Fprintf(sb, "\tcase %q:\n", f.DBName)
+			if f.pointer() {
+				fmt.Fprintf(sb, "\t\tif m.%[1]s == nil {\n\t\t\treturn nil\n\t\t}\n\t\treturn *m.%[1]s\n", f.GoName)
+			} else {
+				fmt.Fprintf(sb, "\t\treturn m.%s\n", f.GoName)
+			}
+		}
+		sb.WriteString("\t}\n\treturn nil\n}\n")
+	}
+
+	fmt.Fprintf(sb, "\n// %sOrderByInput orders %s records by one or more fields\ntype %sOrderByInput struct {\n", name, name, name)
+	for _, f := range m.fields {
+		if f.filter != "" {
+			fmt.Fprintf(sb, "\t%s *SortOrder `json:\"%s\"`\n", f.GoName, f.Name)
+		}
+	}
+	sb.WriteString("}\n")
+
+	fmt.Fprintf(sb, "\n// order returns the columns the input orders by\n")
+	fmt.Fprintf(sb, "func (in %sOrderByInput) order() []graphQLOrder {\n\tvar orders []graphQLOrder\n", name)
+	for _, f := range m.fields {
+		if f.filter != "" {
+			fmt.Fprintf(sb, "\tif in.%[1]s != nil {\n\t\torders = append(orders, graphQLOrder{column: %[2]q, desc: *in.%[1]s == SortOrderDesc})\n\t}\n",
+				f.GoName, f.DBName)
+		}
+	}
+	sb.WriteString("\treturn orders\n}\n")
+}
+
+// writeGraphQLQueryResolvers writes the resolvers of the queries of a model
+// and of its relation fields
+func writeGraphQLQueryResolvers(sb *strings.Builder, m *graphQLModel) {
+	name := m.Name
+	single, list := lowerFirst(name), lowerFirst(Plural(name))
+	listMethod := Plural(name)
+	hasUnique := len(m.uniqueFields()) > 0
+
+	if hasUnique {
+		fmt.Fprintf(sb, "\n// %s resolves Query.%s, nil when no record matches\n", name, single)
+		fmt.Fprintf(sb, "func (r *GraphQLResolver) %s(ctx context.Context, where %sWhereUniqueInput) (*%s, error) {\n", name, name, name)
+		fmt.Fprintf(sb, `	column, value, err := where.unique()
+	if err != nil {
+		return nil, err
+	}
+	q := r.Client.%s.Query()
+	q.where = builder.NewWhereBuilder().Equals(column, value)
+	record, err := q.ExecuteFirst(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return record, err
+}
+`, name)
+	}
+
+	fmt.Fprintf(sb, "\n// %sArgs are the arguments of Query.%s\ntype %sArgs struct {\n", listMethod, list, listMethod)
+	fmt.Fprintf(sb, "\tWhere *%sWhereInput `json:\"where\"`\n\tOrderBy []%sOrderByInput `json:\"orderBy\"`\n", name, name)
+	if hasUnique {
+		fmt.Fprintf(sb, "\tCursor *%sWhereUniqueInput `json:\"cursor\"`\n", name)
+	}
+	sb.WriteString("\tTake *int `json:\"take\"`\n\tSkip *int `json:\"skip\"`\n}\n")
+
+	fmt.Fprintf(sb, "\n// %s resolves Query.%s\n", listMethod, list)
+	fmt.Fprintf(sb, "func (r *GraphQLResolver) %s(ctx context.Context, args %sArgs) ([]%s, error) {\n", listMethod, listMethod, name)
+	fmt.Fprintf(sb, "\tq := r.Client.%s.Query()\n\tq.where = args.Where.where()\n", name)
+	sb.WriteString("\tvar orders []graphQLOrder\n\tfor _, in := range args.OrderBy {\n\t\torders = append(orders, in.order()...)\n\t}\n")
+	if hasUnique {
+		fmt.Fprintf(sb, `	if args.Cursor != nil {
+		column, _, err := args.Cursor.unique()
+		if err != nil {
+			return nil, err
+		}
+		cursor, err := r.%s(ctx, *args.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor == nil {
+			return []%s{}, nil
+		}
+		orders = graphQLTiebreak(orders, column)
+		for i := range orders {
+			orders[i].value = cursor.graphQLValue(orders[i].column)
+		}
+		if q.where == nil {
+			q.where = builder.NewWhereBuilder()
+		}
+		q.where.AND(graphQLCursor(orders))
+	}
+`, name, name)
+	}
+	fmt.Fprintf(sb, `	q.orderBy = graphQLOrderBy(orders)
+	if err := graphQLPage(args.Take, args.Skip, func(n int) { q.Limit(n) }, func(n int) { q.Offset(n) }); err != nil {
+		return nil, err
+	}
+	if args.Take != nil && *args.Take == 0 {
+		return []%[1]s{}, nil
+	}
+	records, err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = []%[1]s{}
+	}
+	return records, nil
+}
+`, name)
+
+	for _, r := range m.relations {
+		loader := name + r.GoName
+		key := "obj." + r.key.GoName
+		fmt.Fprintf(sb, "\n// %s resolves %s.%s, from the record when it was included and\n// through the loaders of ctx otherwise\n", loader, name, r.Name)
+		if r.list {
+			fmt.Fprintf(sb, "func (r *GraphQLResolver) %s(ctx context.Context, obj *%s) ([]%s, error) {\n", loader, name, r.related)
+			fmt.Fprintf(sb, "\tif obj.%s != nil {\n\t\treturn obj.%s, nil\n\t}\n", r.GoName, r.GoName)
+			if r.key.pointer() {
+				fmt.Fprintf(sb, "\tif %s == nil {\n\t\treturn []%s{}, nil\n\t}\n", key, r.related)
+				key = "*" + key
+			}
+			fmt.Fprintf(sb, "\trecords, err := graphQLLoadersFrom(ctx, r.Client).%s.Load(ctx, %s)\n", loader, key)
+			fmt.Fprintf(sb, "\tif records == nil && err == nil {\n\t\trecords = []%s{}\n\t}\n\treturn records, err\n}\n", r.related)
+			continue
+		}
+		fmt.Fprintf(sb, "func (r *GraphQLResolver) %s(ctx context.Context, obj *%s) (*%s, error) {\n", loader, name, r.related)
+		fmt.Fprintf(sb, "\tif obj.%s != nil {\n\t\treturn obj.%s, nil\n\t}\n", r.GoName, r.GoName)
+		if r.key.pointer() {
+			fmt.Fprintf(sb, "\tif %s == nil {\n\t\treturn nil, nil\n\t}\n", key)
+			key = "*" + key
+		}
+		fmt.Fprintf(sb, "\treturn graphQLLoadersFrom(ctx, r.Client).%s.Load(ctx, %s)\n}\n", loader, key)
+	}
+}
+
+// writeGraphQLLoaders writes the loaders of the relation fields, each
+// loading the related records of a batch of keys with one IN query
+func writeGraphQLLoaders(sb *strings.Builder, models []*graphQLModel) {
+	sb.WriteString(`
+// GraphQLLoaders batch the relation loads of one request, so resolving a
+// relation for a list of records runs one query instead of one per record
+type GraphQLLoaders struct {
+`)
+	for _, m := range models {
+		for _, r := range m.relations {
+			value := "*" + r.related
+			if r.list {
+				value = "[]" + r.related
+			}
+			fmt.Fprintf(sb, "\t%s%s *dataloader.Loader[%s, %s]\n", m.Name, r.GoName, r.key.goType, value)
+		}
+	}
+	sb.WriteString("}\n")
+
+	sb.WriteString(`
+// NewGraphQLLoaders creates the loaders of a request. Loaders cache what
+// they load, so they must not outlive the request.
+func NewGraphQLLoaders(client *PrismaClient) *GraphQLLoaders {
+	return &GraphQLLoaders{
+`)
+	for _, m := range models {
+		for _, r := range m.relations {
+			value := "*" + r.related
+			if r.list {
+				value = "[]" + r.related
+			}
+			keyType := r.key.goType
+			fmt.Fprintf(sb, "\t\t%s%s: dataloader.New(func(ctx context.Context, keys []%s) (map[%s]%s, error) {\n",
+				m.Name, r.GoName, keyType, keyType, value)
+			fmt.Fprintf(sb, "\t\t\trecords, err := client.%s.FindManyWhere(ctx, builder.NewWhereBuilder().In(%q, graphQLValues(keys)))\n",
+				r.related, r.relatedKey.DBName)
+			sb.WriteString("\t\t\tif err != nil {\n\t\t\t\treturn nil, err\n\t\t\t}\n")
+			fmt.Fprintf(sb, "\t\t\tvalues := make(map[%s]%s, len(keys))\n\t\t\tfor i := range records {\n", keyType, value)
+			key := "records[i]." + r.relatedKey.GoName
+			if r.relatedKey.pointer() {
+				fmt.Fprintf(sb, "\t\t\t\tif %s == nil {\n\t\t\t\t\tcontinue\n\t\t\t\t}\n", key)
+				key = "*" + key
+			}
+			if r.list {
+				fmt.Fprintf(sb, "\t\t\t\tvalues[%[1]s] = append(values[%[1]s], records[i])\n", key)
+			} else {
+				fmt.Fprintf(sb, "\t\t\t\tvalues[%s] = &records[i]\n", key)
+			}
+			sb.WriteString("\t\t\t}\n\t\t\treturn values, nil\n\t\t}),\n")
+		}
+	}
+	sb.WriteString("\t}\n}\n")
+
+	sb.WriteString(`
+type graphQLLoadersKey struct{}
+
+// WithGraphQLLoaders returns a context carrying new loaders for the relation
+// resolvers. Call it once per request, for example in an HTTP middleware, so
+// the loads of the request are batched together.
+func WithGraphQLLoaders(ctx context.Context, client *PrismaClient) context.Context {
+	return context.WithValue(ctx, graphQLLoadersKey{}, NewGraphQLLoaders(client))
+}
+
+// graphQLLoadersFrom returns the loaders of ctx. Without them, loads are
+// not batched.
+func graphQLLoadersFrom(ctx context.Context, client *PrismaClient) *GraphQLLoaders {
+	if loaders, ok := ctx.Value(graphQLLoadersKey{}).(*GraphQLLoaders); ok {
+		return loaders
+	}
+	return NewGraphQLLoaders(client)
+}
+`)
+}
@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+func TestGenerateGraphQLFiles(t *testing.T) {
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", mappedSchema)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	dbDiags := diagnostics.NewDiagnostics()
+	db := database.NewParserDatabase(files, &dbDiags, database.NoExtensionTypes{})
+	models := GenerateModelsFromAST(schemaAST, ResolveDatabaseNames(db))
+
+	dir := t.TempDir()
+	if err := GenerateGraphQLFiles(schemaAST, models, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	sdl, err := os.ReadFile(filepath.Join(dir, GraphQLSchemaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"scalar DateTime\n",
+		"enum Role {\n  ADMIN\n  USER\n}",
+		"type User {\n  id: Int!\n  email: String!\n  name: String\n  role: Role!\n  createdAt: DateTime!\n  posts: [Post!]!\n}",
+		"  author: User!\n",
+		"input RoleFilter {\n  equals: Role\n  not: Role\n  in: [Role!]\n  notIn: [Role!]\n  isNull: Boolean\n}",
+		"  contains: String\n",
+		"input UserWhereInput {\n  AND: [UserWhereInput!]\n  OR: [UserWhereInput!]\n  NOT: UserWhereInput\n  id: IntFilter\n",
+		"input UserWhereUniqueInput {\n  id: Int\n  email: String\n}",
+		"input PostOrderByInput {\n  id: SortOrder\n  authorId: SortOrder\n}",
+		"  user(where: UserWhereUniqueInput!): User\n",
+		"  posts(where: PostWhereInput, orderBy: [PostOrderByInput!], cursor: PostWhereUniqueInput, take: Int, skip: Int): [Post!]!\n",
+	} {
+		if !strings.Contains(string(sdl), want) {
+			t.Errorf("%s does not contain %q:\n%s", GraphQLSchemaFile, want, sdl)
+		}
+	}
+	if strings.Contains(string(sdl), "scalar Int") {
+		t.Errorf("built-in scalars must not be declared:\n%s", sdl)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, GraphQLResolversFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), GraphQLResolversFile, src, 0); err != nil {
+		t.Fatalf("invalid %s: %v\n%s", GraphQLResolversFile, err, src)
+	}
+	for _, want := range []string{
+		"n += in.Id.apply(w, \"UserID\")",
+		"n += in.Name.apply(w, \"FullName\")",
+		"case \"ADMIN\":\n\t\t*e = RoleADMIN",
+		"func (r *GraphQLResolver) Users(ctx context.Context, args UsersArgs) ([]User, error) {",
+		"UserPosts  *dataloader.Loader[int, []Post]",
+		"PostAuthor *dataloader.Loader[int, *User]",
+		"client.Post.FindManyWhere(ctx, builder.NewWhereBuilder().In(\"AuthorRef\", graphQLValues(keys)))",
+		"values[records[i].Authorid] = append(values[records[i].Authorid], records[i])",
+		"client.User.FindManyWhere(ctx, builder.NewWhereBuilder().In(\"UserID\", graphQLValues(keys)))",
+		"return graphQLLoadersFrom(ctx, r.Client).PostAuthor.Load(ctx, obj.Authorid)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("%s does not contain %q", GraphQLResolversFile, want)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	for in, want := range map[string]string{
+		"User":     "Users",
+		"Category": "Categories",
+		"Day":      "Days",
+		"Address":  "Addresses",
+		"Box":      "Boxes",
+		"Match":    "Matches",
+	} {
+		if got := Plural(in); got != want {
+			t.Errorf("Plural(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package codegen
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	ast "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
@@ -9,10 +10,15 @@ import (
 
 // ModelInfo represents information about a model for code generation
 type ModelInfo struct {
-	Name      string
-	TableName string
-	Fields    []FieldInfo
-	Relations []RelationInfo // Relations from this model
+	Name       string
+	TableName  string
+	Fields     []FieldInfo
+	Relations  []RelationInfo  // Relations from this model
+	Computed   []ComputedField // Fields of /// @computed(...) comments
+	SoftDelete string          // Field of the /// @softDelete(...) comment
+
+	// SoftDeleteErr holds why the @softDelete comment could not be parsed
+	SoftDeleteErr string
 }
 
 // RelationInfo represents a relation between models
@@ -22,49 +28,69 @@ type RelationInfo struct {
 	IsList          bool   // true if one-to-many or many-to-many
 	ForeignKey      string // Foreign key field name (e.g., "authorId")
 	ForeignKeyTable string // Table name of the model with the foreign key
+	RelatedTable    string // Table name of the related model
 	LocalKey        string // Local key field name (usually "id")
+	// Column names of ForeignKey and LocalKey
+	ForeignKeyColumn string
+	LocalKeyColumn   string
 }
 
 // FieldInfo represents information about a field
 type FieldInfo struct {
 	Name         string
+	DBName       string // Column name in the database
 	GoName       string
 	GoType       string
 	Tags         string
 	IsID         bool
 	IsUnique     bool
 	IsRelation   bool
-	RelationTo   string // Model name if this is a relation field
-	IsList       bool   // true if relation is a list (one-to-many or many-to-many)
-	IsForeignKey bool   // true if this is a foreign key field (e.g., authorId)
-	ForeignKeyTo string // Model name this foreign key references
+	RelationTo   string           // Model name if this is a relation field
+	IsList       bool             // true if relation is a list (one-to-many or many-to-many)
+	IsForeignKey bool             // true if this is a foreign key field (e.g., authorId)
+	ForeignKeyTo string           // Model name this foreign key references
+	IsJson       bool             // true if this is a Json field stored as JSON text
+	GoImport     string           // Import path of a Go type bound with /// @go.type
+	GoImportName string           // Package name GoType refers to GoImport by
+	Validations  []ValidationRule // Rules of /// @validate(...) comments
+	IsSensitive  bool             // true if annotated /// @sensitive, its values are redacted in logs
 }
 
-// GenerateModelsFromAST generates model information from the AST
-func GenerateModelsFromAST(schemaAST *ast.SchemaAst) []ModelInfo {
+// GenerateModelsFromAST generates model information from the AST. Table and
+// column names are taken from names, which may be nil for a schema without
+// @map and @@map attributes.
+func GenerateModelsFromAST(schemaAST *ast.SchemaAst, names *DatabaseNames) []ModelInfo {
 	var models []ModelInfo
 	modelMap := make(map[string]*ModelInfo)
 
 	// First pass: create all models (including views)
 	astModels := schemaAST.Models()
 	for _, model := range astModels {
-		// Extract table name from @@map attribute if present
-		tableName := extractTableNameFromModel(model)
+		tableName := names.Table(model.Name.Name)
 
 		modelInfo := ModelInfo{
 			Name:      model.Name.Name,
 			TableName: tableName,
 			Fields:    []FieldInfo{},
 			Relations: []RelationInfo{},
+			Computed:  computedAnnotations(model),
+		}
+		if field, err := model.SoftDeleteField(); err != nil {
+			modelInfo.SoftDeleteErr = err.Error()
+		} else {
+			modelInfo.SoftDelete = field
 		}
 
 		for _, field := range model.Fields {
-			fieldInfo := generateFieldInfo(field, model.Name.Name)
+			fieldInfo := generateFieldInfo(field, model.Name.Name, names.Column(model.Name.Name, field.Name.Name))
 			modelInfo.Fields = append(modelInfo.Fields, fieldInfo)
 		}
 
 		models = append(models, modelInfo)
-		modelMap[model.Name.Name] = &models[len(models)-1]
+	}
+	// Pointers are taken once models no longer grows
+	for i := range models {
+		modelMap[models[i].Name] = &models[i]
 	}
 
 	// Process ExtendedType declarations and merge fields into base models
@@ -75,7 +101,7 @@ func GenerateModelsFromAST(schemaAST *ast.SchemaAst) []ModelInfo {
 			if baseModel, exists := modelMap[typeName]; exists {
 				// Merge extended fields into the base model
 				for _, field := range extendedType.Fields {
-					fieldInfo := generateFieldInfo(field, typeName)
+					fieldInfo := generateFieldInfo(field, typeName, names.Column(typeName, field.Name.Name))
 					baseModel.Fields = append(baseModel.Fields, fieldInfo)
 				}
 			}
@@ -187,9 +213,11 @@ func GenerateModelsFromAST(schemaAST *ast.SchemaAst) []ModelInfo {
 						// Check current model for foreign keys pointing to related model (many-to-one)
 						if !field.IsList {
 							expectedFK := toSnakeCase(field.RelationTo) + "_id"
-							// Try direct lookup first
-							for fieldName, checkField := range modelFields {
-								if toSnakeCase(fieldName) == expectedFK &&
+							// Fields are tried in declaration order, so the
+							// same field wins on every run
+							for k := range model.Fields {
+								checkField := &model.Fields[k]
+								if toSnakeCase(checkField.Name) == expectedFK &&
 									!checkField.IsID && !checkField.IsRelation {
 									foreignKeyField = checkField
 									foreignKeyField.IsForeignKey = true
@@ -214,10 +242,8 @@ func GenerateModelsFromAST(schemaAST *ast.SchemaAst) []ModelInfo {
 						} else if field.IsList && relatedASTFields != nil {
 							// One-to-many: foreign key should be on related model
 							expectedFK := toSnakeCase(model.Name) + "_id"
-							for fieldName, checkASTField := range relatedASTFields {
-								// Check AST field attributes properly potentially
-								// V2 AST doesn't have hasAttribute helper directly here unless we use our package one
-								if toSnakeCase(fieldName) == expectedFK &&
+							for _, checkASTField := range astModelMap[field.RelationTo].Fields {
+								if toSnakeCase(checkASTField.Name.Name) == expectedFK &&
 									!hasAttribute(checkASTField, "id") {
 									relation.ForeignKey = checkASTField.Name.Name
 									relation.ForeignKeyTable = relatedModel.TableName
@@ -236,6 +262,21 @@ func GenerateModelsFromAST(schemaAST *ast.SchemaAst) []ModelInfo {
 		}
 	}
 
+	// Resolve the column names of relation keys
+	for i := range models {
+		model := &models[i]
+		for j := range model.Relations {
+			rel := &model.Relations[j]
+			fkModel, localModel := model.Name, rel.RelatedModel
+			if rel.IsList {
+				fkModel, localModel = rel.RelatedModel, model.Name
+			}
+			rel.ForeignKeyColumn = relationKeyColumn(modelFieldIndex[fkModel], rel.ForeignKey)
+			rel.LocalKeyColumn = relationKeyColumn(modelFieldIndex[localModel], rel.LocalKey)
+			rel.RelatedTable = modelMap[rel.RelatedModel].TableName
+		}
+	}
+
 	// Third pass: Fix IsRelation for enum-typed fields
 	// Some fields were marked as relations because their type starts with capital letter
 	// but they're actually enum types, not model types
@@ -259,7 +300,15 @@ func GenerateModelsFromAST(schemaAST *ast.SchemaAst) []ModelInfo {
 	return models
 }
 
-func generateFieldInfo(field *ast.Field, modelName string) FieldInfo {
+// relationKeyColumn returns the column name of a relation key field
+func relationKeyColumn(fields map[string]*FieldInfo, fieldName string) string {
+	if field := fields[fieldName]; field != nil {
+		return field.DBName
+	}
+	return toSnakeCase(fieldName)
+}
+
+func generateFieldInfo(field *ast.Field, modelName, column string) FieldInfo {
 	fieldName := field.Name.Name
 	typeName := ""
 	if field.Type != nil {
@@ -285,6 +334,15 @@ func generateFieldInfo(field *ast.Field, modelName string) FieldInfo {
 
 	goType := mapPrismaTypeToGo(field.Type)
 
+	// Json fields can be bound to a Go type with a /// @go.type(...) comment
+	isJson := typeName == "Json" && !field.Arity.IsList()
+	bound := false
+	var goImport, goImportName string
+	if binding, ok := goTypeAnnotation(field); ok && typeName == "Json" {
+		goType, goImport, goImportName = binding.goType, binding.importPath, binding.packageName
+		bound = true
+	}
+
 	// Check for optional/list fields using Arity
 	// V2 AST FieldArity is an int with methods
 	if field.Arity.IsList() {
@@ -295,27 +353,140 @@ func generateFieldInfo(field *ast.Field, modelName string) FieldInfo {
 	} else if isRelation {
 		// All single relations must be pointers to prevent recursive struct definitions
 		goType = "*" + goType
-	} else if field.Arity.IsOptional() {
+	} else if field.Arity.IsOptional() && !(bound && isNilable(goType)) {
 		goType = "*" + goType
 	}
 
-	tags := generateFieldTags(field, isRelation)
+	tags := generateFieldTags(field, isRelation, column)
 	isID := hasAttribute(field, "id")
 	isUnique := hasAttribute(field, "unique")
 
 	return FieldInfo{
-		Name:       fieldName,
-		GoName:     toPascalCase(fieldName),
-		GoType:     goType,
-		Tags:       tags,
-		IsID:       isID,
-		IsUnique:   isUnique,
-		IsRelation: isRelation,
-		RelationTo: relationTo,
-		IsList:     isList,
+		Name:         fieldName,
+		DBName:       column,
+		GoName:       toPascalCase(fieldName),
+		GoType:       goType,
+		Tags:         tags,
+		IsID:         isID,
+		IsUnique:     isUnique,
+		IsRelation:   isRelation,
+		RelationTo:   relationTo,
+		IsList:       isList,
+		IsJson:       isJson,
+		GoImport:     goImport,
+		GoImportName: goImportName,
+		Validations:  validationAnnotations(field),
+		IsSensitive:  sensitiveAnnotation(field),
 	}
 }
 
+// sensitiveAnnotation reports whether a field has a /// @sensitive doc
+// comment, e.g. a password hash or a token
+func sensitiveAnnotation(field *ast.Field) bool {
+	doc := field.Documentation.GetText()
+	for {
+		start := strings.Index(doc, "@sensitive")
+		if start < 0 {
+			return false
+		}
+		// @sensitive itself, not e.g. @sensitiveData
+		doc = doc[start+len("@sensitive"):]
+		if doc == "" || !strings.ContainsAny(doc[:1], "_.abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") {
+			return true
+		}
+	}
+}
+
+// goTypeBinding is a Go type bound to a field with /// @go.type(...)
+type goTypeBinding struct {
+	goType      string // e.g. "[]billing.Item"
+	importPath  string // e.g. "github.com/acme/app/billing"
+	packageName string // e.g. "billing"
+}
+
+// goTypeAnnotation reads a /// @go.type(...) doc comment of a field. The
+// type is written as "<import path>.<Type>" with optional * and []
+// prefixes, e.g. "@go.type(github.com/acme/app/billing.Address)". A type
+// without import path must be declared in the generated package.
+func goTypeAnnotation(field *ast.Field) (goTypeBinding, bool) {
+	const marker = "@go.type("
+	doc := field.Documentation.GetText()
+	start := strings.Index(doc, marker)
+	if start < 0 {
+		return goTypeBinding{}, false
+	}
+	spec := doc[start+len(marker):]
+	end := strings.IndexByte(spec, ')')
+	if end < 0 {
+		return goTypeBinding{}, false
+	}
+	spec = strings.Trim(strings.TrimSpace(spec[:end]), `"`)
+
+	prefix := ""
+	for strings.HasPrefix(spec, "*") || strings.HasPrefix(spec, "[]") {
+		if spec[0] == '*' {
+			prefix += "*"
+			spec = spec[1:]
+		} else {
+			prefix += "[]"
+			spec = spec[2:]
+		}
+	}
+
+	dot := strings.LastIndexByte(spec, '.')
+	if dot < 0 {
+		if spec == "" {
+			return goTypeBinding{}, false
+		}
+		return goTypeBinding{goType: prefix + spec}, true
+	}
+	importPath, typeName := spec[:dot], spec[dot+1:]
+	if importPath == "" || typeName == "" {
+		return goTypeBinding{}, false
+	}
+	name := packageNameFromImport(importPath)
+	return goTypeBinding{
+		goType:      prefix + name + "." + typeName,
+		importPath:  importPath,
+		packageName: name,
+	}, true
+}
+
+// packageNameFromImport guesses the package name of an import path, e.g.
+// "billing" for "github.com/acme/billing/v2" or "yaml" for "gopkg.in/yaml.v3".
+// The generated code imports the path under this name, so the guess only has
+// to be a valid identifier.
+func packageNameFromImport(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && len(name) > 1 && name[0] == 'v' && isAllDigits(name[1:]) {
+		name = elements[len(elements)-2]
+	}
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+	name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "pkg" + name
+	}
+	return name
+}
+
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isNilable reports whether goType can already hold nil, so an optional
+// bound field does not need an extra pointer
+func isNilable(goType string) bool {
+	return strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[")
+}
+
 func mapPrismaTypeToGo(fieldType *ast.FieldType) string {
 	if fieldType == nil {
 		return "interface{}"
@@ -337,7 +508,7 @@ func mapPrismaTypeToGo(fieldType *ast.FieldType) string {
 	case "Float":
 		return "float64"
 	case "Decimal":
-		return "string" // Use string for now, can be improved with decimal library
+		return "types.Decimal"
 	case "Json":
 		return "interface{}"
 	case "Bytes":
@@ -348,7 +519,7 @@ func mapPrismaTypeToGo(fieldType *ast.FieldType) string {
 	}
 }
 
-func generateFieldTags(field *ast.Field, isRelation bool) string {
+func generateFieldTags(field *ast.Field, isRelation bool, column string) string {
 	tags := []string{}
 
 	// JSON tag
@@ -357,10 +528,33 @@ func generateFieldTags(field *ast.Field, isRelation bool) string {
 
 	// DB tag - only for non-relation fields (relations are not database columns)
 	if !isRelation {
-		dbTag := fmt.Sprintf(`db:"%s"`, toSnakeCase(field.Name.Name))
+		dbTag := fmt.Sprintf(`db:"%s"`, column)
 		tags = append(tags, dbTag)
 	}
 
+	// Prisma tag - Json fields are encoded and decoded by the executor, and
+	// zero values of fields with a database default are not inserted
+	var options []string
+	if field.Type != nil && field.Type.Name == "Json" && !field.Arity.IsList() {
+		options = append(options, "json")
+	}
+	if !isRelation {
+		switch defaultFunction(field) {
+		case "now":
+			options = append(options, "now")
+		case "autoincrement", "dbgenerated", "sequence":
+			options = append(options, "default")
+		}
+	}
+	if len(options) > 0 {
+		tags = append(tags, fmt.Sprintf(`prisma:"%s"`, strings.Join(options, ",")))
+	}
+
+	// Decimal tag - lets the executor round values to the column scale
+	if precision, scale, ok := decimalPrecisionScale(field); ok {
+		tags = append(tags, fmt.Sprintf(`decimal:"%d,%d"`, precision, scale))
+	}
+
 	if len(tags) > 0 {
 		return "`" + strings.Join(tags, " ") + "`"
 	}
@@ -368,6 +562,37 @@ func generateFieldTags(field *ast.Field, isRelation bool) string {
 	return ""
 }
 
+// decimalPrecisionScale returns the arguments of a @db.Decimal(p, s)
+// native type attribute on a Decimal field.
+func decimalPrecisionScale(field *ast.Field) (int, int, bool) {
+	if field.Type == nil || field.Type.Name != "Decimal" {
+		return 0, 0, false
+	}
+	for _, attr := range field.Attributes {
+		// The datasource prefix is part of the name, e.g. "db.Decimal"
+		name := strings.ReplaceAll(attr.GetName(), ".", "")
+		if name == "Decimal" || !strings.HasSuffix(name, "Decimal") || attr.Arguments == nil {
+			continue
+		}
+		args := attr.Arguments.Arguments
+		if len(args) != 2 {
+			continue
+		}
+		precision, ok1 := args[0].Value.AsNumericValue()
+		scale, ok2 := args[1].Value.AsNumericValue()
+		if !ok1 || !ok2 {
+			continue
+		}
+		p, err1 := strconv.Atoi(precision.Value)
+		s, err2 := strconv.Atoi(scale.Value)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		return p, s, true
+	}
+	return 0, 0, false
+}
+
 func hasAttribute(field *ast.Field, attrName string) bool {
 	for _, attr := range field.Attributes {
 		if attr.Name.Name == attrName {
@@ -377,6 +602,45 @@ func hasAttribute(field *ast.Field, attrName string) bool {
 	return false
 }
 
+// defaultFunction returns the function of the @default of field, e.g.
+// "autoincrement" or "now", and "" for literal defaults. Zero values of
+// fields the database fills in, like autoincrement(), are left out of
+// inserts, and now() is filled in by the client; literal defaults are kept,
+// since their zero value may be meant, and so are the ids Prisma generates
+// on the client.
+func defaultFunction(field *ast.Field) string {
+	for _, attr := range field.Attributes {
+		if attr.Name.Name != "default" || attr.Arguments == nil || len(attr.Arguments.Arguments) == 0 {
+			continue
+		}
+		if fn, ok := attr.Arguments.Arguments[0].Value.AsFunction(); ok {
+			return fn.Name
+		}
+		return ""
+	}
+	return ""
+}
+
+// GoFieldName returns the name of the struct field generated for a schema
+// field
+func GoFieldName(name string) string {
+	return toPascalCase(name)
+}
+
+// Plural returns the English plural of a model name, e.g. Users or
+// Categories, for names such as list methods
+func Plural(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	}
+	return s + "s"
+}
+
 func toPascalCase(s string) string {
 	if s == "" {
 		return ""
@@ -404,35 +668,3 @@ func toSnakeCase(s string) string {
 	}
 	return strings.ToLower(result.String())
 }
-
-// extractTableNameFromModel extracts the table name from a model's @@map attribute
-// or falls back to snake_case of the model name
-func extractTableNameFromModel(model *ast.Model) string {
-	// Check for @@map attribute
-	// In V2 AST, attributes are in BlockAttributes
-	for _, attr := range model.BlockAttributes {
-		if attr.Name.Name == "map" {
-			// Check for named argument "name"
-			if attr.Arguments != nil {
-				for _, arg := range attr.Arguments.Arguments {
-					if arg.Name != nil && arg.Name.Name == "name" {
-						if strLit, ok := arg.Value.AsStringValue(); ok {
-							return strLit.GetValue()
-						}
-					}
-				}
-				// Check for unnamed first argument (positional)
-				if len(attr.Arguments.Arguments) > 0 {
-					firstArg := attr.Arguments.Arguments[0]
-					if firstArg.Name == nil {
-						if strLit, ok := firstArg.Value.AsStringValue(); ok {
-							return strLit.GetValue()
-						}
-					}
-				}
-			}
-		}
-	}
-	// Fall back to snake_case of model name
-	return toSnakeCase(model.Name.Name)
-}
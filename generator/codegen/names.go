@@ -0,0 +1,89 @@
+package codegen
+
+import (
+	"github.com/satishbabariya/prisma-go/psl/database"
+)
+
+// DatabaseNames holds the database names of models, fields and enum values
+// as resolved by the parser database. Models and fields without @@map or
+// @map use the snake_case names that migrations create.
+type DatabaseNames struct {
+	tables     map[string]string            // model name -> table name
+	columns    map[string]map[string]string // model name -> field name -> column name
+	enums      map[string]string            // enum name -> database name
+	enumValues map[string]map[string]string // enum name -> value name -> database value
+}
+
+// ResolveDatabaseNames collects the database names of a validated schema
+func ResolveDatabaseNames(db *database.ParserDatabase) *DatabaseNames {
+	names := &DatabaseNames{
+		tables:     make(map[string]string),
+		columns:    make(map[string]map[string]string),
+		enums:      make(map[string]string),
+		enumValues: make(map[string]map[string]string),
+	}
+
+	for _, model := range db.WalkModels() {
+		if mapped := model.MappedName(); mapped != nil {
+			names.tables[model.Name()] = *mapped
+		}
+		columns := make(map[string]string)
+		for _, field := range model.ScalarFields() {
+			if mapped := field.MappedName(); mapped != nil {
+				columns[field.Name()] = *mapped
+			}
+		}
+		names.columns[model.Name()] = columns
+	}
+
+	for _, enum := range db.WalkEnums() {
+		names.enums[enum.Name()] = enum.DatabaseName()
+		values := make(map[string]string)
+		for _, value := range enum.Values() {
+			values[value.Name()] = value.DatabaseName()
+		}
+		names.enumValues[enum.Name()] = values
+	}
+
+	return names
+}
+
+// Table returns the table name of a model
+func (n *DatabaseNames) Table(model string) string {
+	if n != nil {
+		if table, ok := n.tables[model]; ok {
+			return table
+		}
+	}
+	return toSnakeCase(model)
+}
+
+// Column returns the column name of a model field
+func (n *DatabaseNames) Column(model, field string) string {
+	if n != nil {
+		if column, ok := n.columns[model][field]; ok {
+			return column
+		}
+	}
+	return toSnakeCase(field)
+}
+
+// Enum returns the database name of an enum
+func (n *DatabaseNames) Enum(enum string) string {
+	if n != nil {
+		if mapped, ok := n.enums[enum]; ok {
+			return mapped
+		}
+	}
+	return enum
+}
+
+// EnumValue returns the value stored in the database for an enum value
+func (n *DatabaseNames) EnumValue(enum, value string) string {
+	if n != nil {
+		if mapped, ok := n.enumValues[enum][value]; ok {
+			return mapped
+		}
+	}
+	return value
+}
@@ -0,0 +1,129 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+const mappedSchema = `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+enum Role {
+  ADMIN @map("admin")
+  USER
+
+  @@map("roles")
+}
+
+model User {
+  id        Int      @id @default(autoincrement()) @map("UserID")
+  email     String   @unique @db.VarChar(255)
+  name      String?  @map(name: "FullName")
+  role      Role     @default(USER)
+  createdAt DateTime @default(now())
+  posts     Post[]
+
+  @@map("Users")
+}
+
+model Post {
+  id       Int    @id @default(autoincrement())
+  authorId Int    @map("AuthorRef")
+  author   User   @relation(fields: [authorId], references: [id])
+}
+`
+
+func TestDatabaseNames(t *testing.T) {
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", mappedSchema)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	dbDiags := diagnostics.NewDiagnostics()
+	db := database.NewParserDatabase(files, &dbDiags, database.NoExtensionTypes{})
+	if dbDiags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, dbDiags))
+	}
+	names := ResolveDatabaseNames(db)
+
+	if got := names.Enum("Role"); got != "roles" {
+		t.Errorf("Role = %q, want roles", got)
+	}
+	if got := names.EnumValue("Role", "ADMIN"); got != "admin" {
+		t.Errorf("Role.ADMIN = %q, want admin", got)
+	}
+	if got := names.EnumValue("Role", "USER"); got != "USER" {
+		t.Errorf("Role.USER = %q, want USER", got)
+	}
+
+	models := GenerateModelsFromAST(schemaAST, names)
+	tables := map[string]string{}
+	columns := map[string]string{}
+	tags := map[string]string{}
+	for _, model := range models {
+		tables[model.Name] = model.TableName
+		for _, field := range model.Fields {
+			if !field.IsRelation {
+				columns[model.Name+"."+field.Name] = field.DBName
+				tags[model.Name+"."+field.Name] = field.Tags
+			}
+		}
+	}
+
+	wantTables := map[string]string{"User": "Users", "Post": "post"}
+	for model, want := range wantTables {
+		if tables[model] != want {
+			t.Errorf("table of %s = %q, want %q", model, tables[model], want)
+		}
+	}
+	wantColumns := map[string]string{
+		"User.id":        "UserID",
+		"User.email":     "email",
+		"User.name":      "FullName",
+		"User.createdAt": "created_at",
+		"Post.authorId":  "AuthorRef",
+	}
+	for field, want := range wantColumns {
+		if columns[field] != want {
+			t.Errorf("column of %s = %q, want %q", field, columns[field], want)
+		}
+	}
+
+	// Columns filled in by the database are left out of inserts when zero,
+	// now() is filled in by the client and literal defaults are kept
+	for field, want := range map[string]string{"User.id": `prisma:"default"`, "User.createdAt": `prisma:"now"`, "User.role": "", "User.email": ""} {
+		if got := strings.Contains(tags[field], "prisma:"); got != (want != "") || !strings.Contains(tags[field], want) {
+			t.Errorf("tags of %s = %s, want %s", field, tags[field], want)
+		}
+	}
+
+	for _, model := range models {
+		if model.Name != "Post" {
+			continue
+		}
+		if len(model.Relations) != 1 {
+			t.Fatalf("Post has %d relations, want 1", len(model.Relations))
+		}
+		rel := model.Relations[0]
+		if rel.ForeignKeyColumn != "AuthorRef" || rel.LocalKeyColumn != "UserID" {
+			t.Errorf("author relation keys = %q, %q, want AuthorRef, UserID", rel.ForeignKeyColumn, rel.LocalKeyColumn)
+		}
+		// The foreign key is in post, but the relation joins Users
+		if rel.ForeignKeyTable != "post" || rel.RelatedTable != "Users" {
+			t.Errorf("author relation tables = %q, %q, want post, Users", rel.ForeignKeyTable, rel.RelatedTable)
+		}
+	}
+
+	// Without a parser database the snake_case convention applies
+	var none *DatabaseNames
+	if none.Table("UserProfile") != "user_profile" || none.Column("User", "createdAt") != "created_at" {
+		t.Error("nil DatabaseNames should fall back to snake_case")
+	}
+}
@@ -0,0 +1,305 @@
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	runtimedebug "runtime/debug"
+	"sort"
+	"strings"
+
+	prismaAST "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// Files of a generated client besides the file of each model
+const (
+	ClientFile = "client.go"
+	EnumsFile  = "enums.go"
+
+	// ManifestFile lists the files of the client with the hashes of their
+	// inputs and contents. Commit it with the client.
+	ManifestFile = "prisma-client.sum"
+)
+
+// legacyFiles are generated files of clients from before the manifest,
+// removed when the manifest is first written
+var legacyFiles = []string{"models.go", ValidatorsFile}
+
+// ModelFileName returns the name of the file of a model in the client, e.g.
+// "user_profile_model.go" for UserProfile. The suffix keeps models such as
+// Linux or FooTest from naming build-constrained or test files.
+func ModelFileName(model string) string {
+	return toSnakeCase(model) + "_model.go"
+}
+
+// ClientFiles lists the files of a client by what GenerateClientFiles did
+// with them
+type ClientFiles struct {
+	Written   []string // created or changed
+	Unchanged []string // left as they were
+	Removed   []string // no longer generated
+}
+
+// GenerateClientFiles writes the Go client into outputDir: client.go with
+// the PrismaClient, enums.go, validate.go (see renderValidators),
+// extensions.go (see renderExtensions) and a file per model with its
+// struct, column references and client. Files are only
+// written when their content changes, so build watchers are not triggered
+// by unchanged output. A model whose information is unchanged since the
+// last run, per the hash recorded in prisma-client.sum, is not rendered at
+// all. Files no longer generated are removed.
+func GenerateClientFiles(schemaAST *prismaAST.SchemaAst, models []ModelInfo, names *DatabaseNames, provider, outputDir string) (*ClientFiles, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	previous, err := readManifest(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := &ClientFiles{}
+	next := make(manifest)
+	fingerprint := generatorFingerprint()
+
+	// emit writes the file rendered by render, which is skipped when input
+	// is recorded for the file and its content is as recorded. A nil
+	// rendering means the file is not generated.
+	emit := func(name, input string, render func() ([]byte, error)) error {
+		path := filepath.Join(outputDir, name)
+		if entry, ok := previous[name]; ok && input != "" && entry.input == input {
+			if current, err := os.ReadFile(path); err == nil && hashBytes(current) == entry.content {
+				next[name] = entry
+				files.Unchanged = append(files.Unchanged, name)
+				return nil
+			}
+		}
+
+		src, err := render()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if src == nil {
+			return nil
+		}
+		written, err := writeGeneratedFile(path, src)
+		if err != nil {
+			return err
+		}
+		if input == "" {
+			input = "-"
+		}
+		next[name] = manifestEntry{input: input, content: hashBytes(src)}
+		if written {
+			files.Written = append(files.Written, name)
+		} else {
+			files.Unchanged = append(files.Unchanged, name)
+		}
+		return nil
+	}
+
+	err = emit(ClientFile, "", func() ([]byte, error) {
+		return renderASTFile(buildClientFile(models, provider))
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = emit(EnumsFile, "", func() ([]byte, error) {
+		decls := buildEnumDecls(schemaAST, names)
+		if len(decls) == 0 {
+			return nil, nil
+		}
+		return renderASTFile(newGeneratedFile(decls, nil))
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = emit(ValidatorsFile, "", func() ([]byte, error) {
+		return renderValidators(models)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = emit(ExtensionsFile, "", func() ([]byte, error) {
+		return renderExtensions(models)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]string)
+	for _, model := range models {
+		name := ModelFileName(model.Name)
+		if owner, ok := owners[name]; ok {
+			return nil, fmt.Errorf("models %s and %s would both be generated into %s", owner, model.Name, name)
+		}
+		owners[name] = model.Name
+
+		input := ""
+		if fingerprint != "" {
+			data, err := json.Marshal(model)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash model %s: %w", model.Name, err)
+			}
+			input = hashBytes(append([]byte(fingerprint+"\n"), data...))
+		}
+		err := emit(name, input, func() ([]byte, error) {
+			if _, _, err := softDeleteField(model); err != nil {
+				return nil, err
+			}
+			return renderASTFile(buildModelFile(model))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Remove what an earlier run generated but this one did not
+	stale := make([]string, 0, len(previous))
+	for name := range previous {
+		stale = append(stale, name)
+	}
+	if previous == nil {
+		stale = legacyGeneratedFiles(outputDir)
+	}
+	sort.Strings(stale)
+	for _, name := range stale {
+		if _, ok := next[name]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(outputDir, name)); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+		files.Removed = append(files.Removed, name)
+	}
+
+	if _, err := writeGeneratedFile(filepath.Join(outputDir, ManifestFile), next.encode()); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// legacyGeneratedFiles returns the legacy files in dir that were generated
+// by prisma-go
+func legacyGeneratedFiles(dir string) []string {
+	var names []string
+	for _, name := range legacyFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil && bytes.HasPrefix(data, []byte(generatedHeader)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// writeGeneratedFile writes src to path unless the file already holds it,
+// so that unchanged files keep their modification time. It reports whether
+// the file was written.
+func writeGeneratedFile(path string, src []byte) (bool, error) {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, src) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, src, 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return true, nil
+}
+
+// manifest maps the files of a client to their hashes
+type manifest map[string]manifestEntry
+
+// manifestEntry holds the hash of the inputs a file was rendered from, "-"
+// for files rendered on every run, and the hash of its content
+type manifestEntry struct {
+	input   string
+	content string
+}
+
+// readManifest reads the manifest of the client in dir, nil when there is
+// none
+func readManifest(dir string) (manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ManifestFile, err)
+	}
+
+	m := make(manifest)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			// A damaged manifest regenerates everything
+			return make(manifest), nil
+		}
+		m[fields[0]] = manifestEntry{input: fields[1], content: fields[2]}
+	}
+	return m, nil
+}
+
+// encode returns the manifest as lines of file name, input hash and
+// content hash, sorted by file name
+func (m manifest) encode() []byte {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s %s %s\n", name, m[name].input, m[name].content)
+	}
+	return buf.Bytes()
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// generatorFingerprint identifies the build of the generator, so that input
+// hashes change when prisma-go is upgraded. It is empty for builds from a
+// modified checkout, whose output cannot be told apart by version.
+func generatorFingerprint() string {
+	const modulePath = "github.com/satishbabariya/prisma-go"
+	info, ok := runtimedebug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Path != modulePath {
+		// prisma-go is a dependency of the program generating
+		for _, dep := range info.Deps {
+			if dep.Path != modulePath {
+				continue
+			}
+			if dep.Replace != nil {
+				// A local replacement may hold any code
+				return ""
+			}
+			return dep.Version + " " + dep.Sum
+		}
+		return ""
+	}
+	fingerprint := info.Main.Version
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			fingerprint += " " + setting.Value
+		case "vcs.modified":
+			if setting.Value == "true" {
+				return ""
+			}
+		}
+	}
+	return fingerprint
+}
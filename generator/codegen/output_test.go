@@ -0,0 +1,182 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+const splitSchema = `
+datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+
+enum Role {
+  ADMIN
+  USER
+}
+
+model User {
+  id        Int      @id @default(autoincrement())
+  role      Role
+  createdAt DateTime @default(now())
+  posts     Post[]
+}
+
+model Post {
+  id      Int    @id @default(autoincrement())
+  title   String
+  user_id Int
+  userId  Int
+  author  User
+}
+`
+
+func generateClientFiles(t *testing.T, schema, dir string) *ClientFiles {
+	t.Helper()
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", schema)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	dbDiags := diagnostics.NewDiagnostics()
+	db := database.NewParserDatabase(files, &dbDiags, database.NoExtensionTypes{})
+	names := ResolveDatabaseNames(db)
+	generated, err := GenerateClientFiles(schemaAST, GenerateModelsFromAST(schemaAST, names), names, "sqlite", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return generated
+}
+
+func readDir(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = data
+	}
+	return files
+}
+
+func TestGenerateClientFiles(t *testing.T) {
+	dir := t.TempDir()
+	// A client generated before the split into files per model
+	legacy := []byte(generatedHeader + "\n\npackage generated\n")
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), legacy, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first := generateClientFiles(t, splitSchema, dir)
+	want := []string{ClientFile, EnumsFile, ExtensionsFile, "user_model.go", "post_model.go"}
+	if !reflect.DeepEqual(first.Written, want) {
+		t.Errorf("written = %v, want %v", first.Written, want)
+	}
+	if !reflect.DeepEqual(first.Removed, []string{"models.go"}) {
+		t.Errorf("removed = %v, want the legacy models.go", first.Removed)
+	}
+
+	files := readDir(t, dir)
+	for name, src := range files {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if !bytes.HasPrefix(src, []byte(generatedHeader+"\n\npackage generated\n")) {
+			t.Errorf("%s starts with %q", name, src[:60])
+		}
+	}
+	post := string(files["post_model.go"])
+	if strings.Contains(post, `"time"`) || !strings.Contains(string(files["user_model.go"]), `"time"`) {
+		t.Error("only the file of User uses time")
+	}
+	if !strings.Contains(post, "type PostClient struct") || strings.Contains(post, "type UserClient struct") {
+		t.Error("post_model.go should hold the client of Post only")
+	}
+	if !strings.Contains(post, `var postTableNames = client.TableNames{Model: "Post", Fields: map[string]string{"id": "id", "title": "title", "user_id": "user_id"}, Relations: map[string]client.RelationNames{"author": {Model: "User", List: false, ForeignKey: "user_id", References: "id"}}}`) {
+		t.Error("post_model.go should map the columns and relations of Post to its fields")
+	}
+	if !strings.Contains(string(files[ClientFile]), "baseClient.SetSchemaNames(schemaNames)") {
+		t.Error("the client does not know the names of the schema")
+	}
+	if !strings.Contains(string(files[ClientFile]), "exec.SetErrorMapper(schemaNames.ClassifyError)") {
+		t.Error("the executor of the client does not classify errors")
+	}
+	if !strings.Contains(string(files[ClientFile]), "exec.SetObservers(baseClient.Observers())") {
+		t.Error("the executor of the client is not observed")
+	}
+	if !strings.Contains(string(files[ClientFile]), "exec.SetPipeline(baseClient.Pipeline())") {
+		t.Error("the executor of the client does not run the middleware of the client")
+	}
+
+	// An unchanged schema writes nothing, and a hand-edited file is restored
+	edited := filepath.Join(dir, "post_model.go")
+	if err := os.WriteFile(edited, []byte("package generated\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	second := generateClientFiles(t, splitSchema, dir)
+	if !reflect.DeepEqual(second.Written, []string{"post_model.go"}) || len(second.Removed) != 0 {
+		t.Errorf("second run wrote %v and removed %v, want only post_model.go", second.Written, second.Removed)
+	}
+	if !reflect.DeepEqual(readDir(t, dir), files) {
+		t.Error("second run changed the output")
+	}
+
+	// Changing a model rewrites its file, removing one removes its file
+	changed := strings.Replace(splitSchema, "  title   String\n", "  title   String\n  body    String?\n", 1)
+	third := generateClientFiles(t, changed, dir)
+	if !reflect.DeepEqual(third.Written, []string{"post_model.go"}) {
+		t.Errorf("changing Post wrote %v", third.Written)
+	}
+	removed := strings.Replace(splitSchema, "  posts     Post[]\n", "", 1)
+	removed = removed[:strings.Index(removed, "model Post")]
+	fourth := generateClientFiles(t, removed, dir)
+	if !reflect.DeepEqual(fourth.Removed, []string{"post_model.go"}) {
+		t.Errorf("removing Post removed %v", fourth.Removed)
+	}
+	if _, err := os.Stat(edited); !os.IsNotExist(err) {
+		t.Error("post_model.go was not removed")
+	}
+}
+
+func TestGenerateClientFilesDeterministic(t *testing.T) {
+	want := readDir(t, func() string {
+		dir := t.TempDir()
+		generateClientFiles(t, splitSchema, dir)
+		return dir
+	}())
+	for i := 0; i < 5; i++ {
+		dir := t.TempDir()
+		generateClientFiles(t, splitSchema, dir)
+		if got := readDir(t, dir); !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d generated different output", i+2)
+		}
+	}
+}
+
+func TestModelFileName(t *testing.T) {
+	for model, want := range map[string]string{
+		"User":        "user_model.go",
+		"UserProfile": "user_profile_model.go",
+		"Linux":       "linux_model.go",
+		"FooTest":     "foo_test_model.go",
+	} {
+		if got := ModelFileName(model); got != want {
+			t.Errorf("ModelFileName(%s) = %s, want %s", model, got, want)
+		}
+	}
+}
@@ -24,7 +24,7 @@ func GenerateQueryBuilderMethods(model ModelInfo) string {
 	for _, field := range model.Fields {
 		fieldName := field.Name
 		goFieldName := field.GoName
-		dbColumnName := toSnakeCase(fieldName)
+		dbColumnName := field.DBName
 
 		// Equals method
 		sb.WriteString(fmt.Sprintf("// %sEquals filters records where %s equals the given value\n", goFieldName, fieldName))
@@ -83,7 +83,7 @@ func GenerateQueryBuilderMethods(model ModelInfo) string {
 
 // isNumericType checks if a type is numeric
 func isNumericType(goType string) bool {
-	numericTypes := []string{"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64"}
+	numericTypes := []string{"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64", "types.Decimal"}
 	baseType := strings.TrimPrefix(goType, "*")
 	for _, numType := range numericTypes {
 		if baseType == numType {
@@ -92,3 +92,8 @@ func isNumericType(goType string) bool {
 	}
 	return false
 }
+
+// isDecimalType checks if a type is the exact decimal type
+func isDecimalType(goType string) bool {
+	return strings.TrimPrefix(goType, "*") == "types.Decimal"
+}
@@ -102,3 +102,52 @@ func findForeignKeyFromRelation(model *ast.Model, relationField *ast.Field) (str
 
 	return "", "", fmt.Errorf("could not determine foreign key for relation field %s", relationField.Name.Name)
 }
+
+// relationName returns the name of a relation given with @relation("name")
+// or @relation(name: "name")
+func relationName(field *ast.Field) string {
+	for _, attr := range field.Attributes {
+		if attr.GetName() != "relation" || attr.Arguments == nil {
+			continue
+		}
+		for _, arg := range attr.Arguments.Arguments {
+			if arg.Name == nil || arg.Name.Name == "name" {
+				if str, ok := arg.Value.AsStringValue(); ok {
+					return str.GetValue()
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// relationKeys returns the scalar fields joining a relation field to the
+// related model: the fields of the holder model reference the references
+// fields of the other one. The back relation side, without fields and
+// references, takes them from the opposite relation field. Implicit
+// many-to-many relations have no keys.
+func relationKeys(schemaAST *ast.SchemaAst, model *ast.Model, field *ast.Field) (holder string, fields, references []string, ok bool) {
+	if fields, references, _ = parseRelationAttribute(field); len(fields) > 0 && len(references) > 0 {
+		return model.Name.Name, fields, references, true
+	}
+	if field.Type == nil {
+		return "", nil, nil, false
+	}
+
+	name := relationName(field)
+	for _, related := range schemaAST.Models() {
+		if related.Name.Name != field.Type.Name {
+			continue
+		}
+		for _, opposite := range related.Fields {
+			if opposite == field || opposite.Type == nil || opposite.Type.Name != model.Name.Name ||
+				relationName(opposite) != name {
+				continue
+			}
+			if fields, references, _ = parseRelationAttribute(opposite); len(fields) > 0 && len(references) > 0 {
+				return related.Name.Name, fields, references, true
+			}
+		}
+	}
+	return "", nil, nil, false
+}
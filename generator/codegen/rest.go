@@ -0,0 +1,1372 @@
+// Package codegen provides OpenAPI document and REST handler generation.
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ast "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// Files written next to the client by GenerateRESTFiles
+const (
+	OpenAPIFile      = "openapi.json"
+	RESTHandlersFile = "rest.go"
+)
+
+// restModel is a model served under a REST collection path. Records are
+// addressed by key, the id or first unique field, and models without one
+// only have the collection endpoints.
+type restModel struct {
+	*graphQLModel
+	path     string
+	key      *graphQLField
+	required map[string]bool // fields a create must set
+}
+
+// restOps returns the filter operations of a field in query parameters
+func restOps(f *graphQLField) string {
+	switch f.typ {
+	case "String":
+		return "restStringOps"
+	case "Int", "BigInt", "Float", "Decimal", "DateTime":
+		return "restComparisonOps"
+	}
+	return "restEqualityOps"
+}
+
+// restParser returns the function parsing a query parameter or path value
+// of the Go type of a field
+func restParser(f *graphQLField) string {
+	switch f.goType {
+	case "string":
+		return "restString"
+	case "int":
+		return "strconv.Atoi"
+	case "int64":
+		return "restInt64"
+	case "float64":
+		return "restFloat64"
+	case "bool":
+		return "strconv.ParseBool"
+	case "time.Time":
+		return "restTime"
+	case "types.Decimal":
+		return "types.ParseDecimal"
+	}
+	return "restParse" + f.goType
+}
+
+// restEnum reports whether f holds values of an enum
+func restEnum(f *graphQLField) bool {
+	return f.goType != "" && f.goType == f.typ
+}
+
+// GenerateRESTFiles writes openapi.json, an OpenAPI 3.1 document of CRUD
+// endpoints for every model, and rest.go, the net/http handlers serving
+// them with the client. Lists take filters, pagination, ordering and
+// relations to include from query parameters, and creates accept nested
+// creates of related records. Records and relations are exposed as in
+// GraphQL, see GenerateGraphQLFiles.
+func GenerateRESTFiles(schemaAST *ast.SchemaAst, models []ModelInfo, names *DatabaseNames, provider, outputDir string) error {
+	restModels := buildRESTModels(schemaAST, models)
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	doc, err := renderOpenAPI(schemaAST, names, restModels)
+	if err != nil {
+		return err
+	}
+	if _, err := writeGeneratedFile(filepath.Join(outputDir, OpenAPIFile), doc); err != nil {
+		return err
+	}
+	src, err := renderRESTHandlers(schemaAST, restModels, provider)
+	if err != nil {
+		return err
+	}
+	if _, err := writeGeneratedFile(filepath.Join(outputDir, RESTHandlersFile), src); err != nil {
+		return err
+	}
+	return nil
+}
+
+func buildRESTModels(schemaAST *ast.SchemaAst, models []ModelInfo) []*restModel {
+	astModels := make(map[string]*ast.Model)
+	for _, model := range schemaAST.Models() {
+		astModels[model.Name.Name] = model
+	}
+
+	var result []*restModel
+	for _, m := range graphQLModels(schemaAST, models) {
+		r := &restModel{graphQLModel: m, path: "/" + lowerFirst(Plural(m.Name)), required: make(map[string]bool)}
+		for _, f := range m.uniqueFields() {
+			if r.key == nil || (f.IsID && !r.key.IsID) {
+				r.key = f
+			}
+		}
+		for _, f := range m.fields {
+			field := astField(astModels[m.Name], f.Name)
+			r.required[f.Name] = !f.optional && !f.list &&
+				!hasAttribute(field, "default") && !hasAttribute(field, "updatedAt")
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// jsonName returns the JSON name of a field, as tagged on the model struct
+func jsonName(name string) string {
+	return toSnakeCase(name)
+}
+
+// renderOpenAPI renders the OpenAPI document of the REST endpoints
+func renderOpenAPI(schemaAST *ast.SchemaAst, names *DatabaseNames, models []*restModel) ([]byte, error) {
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"code", "message"},
+			"properties": map[string]interface{}{
+				"code":    map[string]interface{}{"type": "string", "description": "Prisma error code, e.g. P2002"},
+				"message": map[string]interface{}{"type": "string"},
+				"model":   map[string]interface{}{"type": "string"},
+				"field":   map[string]interface{}{"type": "string"},
+				"meta":    map[string]interface{}{"type": "object"},
+			},
+		},
+	}
+	used := make(map[string]bool)
+	for _, m := range models {
+		for _, f := range m.fields {
+			used[f.typ] = true
+		}
+	}
+	for _, enum := range schemaAST.Enums() {
+		name := enum.Name.Name
+		if !used[name] {
+			continue
+		}
+		var values []string
+		for _, value := range enum.Values {
+			values = append(values, names.EnumValue(name, value.Name.Name))
+		}
+		schema := map[string]interface{}{"type": "string", "enum": values}
+		if doc := docText(enum.Documentation); doc != "" {
+			schema["description"] = doc
+		}
+		schemas[name] = schema
+	}
+
+	byName := make(map[string]*restModel)
+	for _, m := range models {
+		byName[m.Name] = m
+	}
+	paths := make(map[string]interface{})
+	for _, m := range models {
+		schemas[m.Name] = restModelSchema(m)
+		schemas[m.Name+"CreateInput"] = restCreateSchema(m, "")
+		for _, r := range m.relations {
+			schemas[m.Name+"Create"+r.GoName+"Input"] = restNestedSchema(m, r, byName[r.related])
+		}
+		if m.key != nil {
+			schemas[m.Name+"UpdateInput"] = restUpdateSchema(m)
+		}
+		restPaths(paths, m)
+	}
+
+	doc := struct {
+		OpenAPI    string                 `json:"openapi"`
+		Info       map[string]string      `json:"info"`
+		Paths      map[string]interface{} `json:"paths"`
+		Components map[string]interface{} `json:"components"`
+	}{
+		OpenAPI: "3.1.0",
+		Info:    map[string]string{"title": "REST API", "version": "1.0.0"},
+		Paths:   paths,
+		Components: map[string]interface{}{
+			"schemas": schemas,
+			"responses": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"description": "Error classified with a Prisma error code",
+					"content":     restContent(restRef("Error")),
+				},
+			},
+		},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", OpenAPIFile, err)
+	}
+	return append(data, '\n'), nil
+}
+
+func restRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func restContent(schema interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// restNullable allows null in addition to the values of schema
+func restNullable(schema map[string]interface{}) map[string]interface{} {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+	if _, ok := schema["$ref"]; ok {
+		return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+	}
+	return schema
+}
+
+// restValueSchema returns the JSON schema of a value of f
+func restValueSchema(f *graphQLField) map[string]interface{} {
+	var schema map[string]interface{}
+	switch f.typ {
+	case "String":
+		schema = map[string]interface{}{"type": "string"}
+	case "Int":
+		schema = map[string]interface{}{"type": "integer", "format": "int32"}
+	case "BigInt":
+		schema = map[string]interface{}{"type": "integer", "format": "int64"}
+	case "Float":
+		schema = map[string]interface{}{"type": "number", "format": "double"}
+	case "Decimal":
+		schema = map[string]interface{}{"type": "string", "format": "decimal"}
+	case "Boolean":
+		schema = map[string]interface{}{"type": "boolean"}
+	case "DateTime":
+		schema = map[string]interface{}{"type": "string", "format": "date-time"}
+	case "Json":
+		schema = map[string]interface{}{}
+	case "Bytes":
+		schema = map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	default:
+		schema = restRef(f.typ)
+	}
+	if f.list {
+		schema = map[string]interface{}{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+// restFieldSchema returns the JSON schema of f in records and inputs
+func restFieldSchema(f *graphQLField) map[string]interface{} {
+	schema := restValueSchema(f)
+	if f.optional {
+		schema = restNullable(schema)
+	}
+	if f.doc != "" {
+		if _, ok := schema["$ref"]; ok {
+			schema = map[string]interface{}{"allOf": []interface{}{schema}}
+		}
+		schema["description"] = f.doc
+	}
+	return schema
+}
+
+func restModelSchema(m *restModel) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	for _, f := range m.fields {
+		properties[jsonName(f.Name)] = restFieldSchema(f)
+		required = append(required, jsonName(f.Name))
+	}
+	for _, r := range m.relations {
+		var schema map[string]interface{}
+		if r.list {
+			schema = map[string]interface{}{"type": []string{"array", "null"}, "items": restRef(r.related)}
+		} else {
+			schema = restNullable(restRef(r.related))
+		}
+		schema["description"] = "Only loaded when included"
+		properties[jsonName(r.Name)] = schema
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	if m.doc != "" {
+		schema["description"] = m.doc
+	}
+	return schema
+}
+
+// restCreateSchema returns the schema of the create input of m. without is
+// the foreign key field a nested create takes from its parent.
+func restCreateSchema(m *restModel, without string) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for _, f := range m.fields {
+		if f.Name == without {
+			continue
+		}
+		properties[jsonName(f.Name)] = restFieldSchema(f)
+		if m.required[f.Name] {
+			required = append(required, jsonName(f.Name))
+		}
+	}
+	for _, r := range m.relations {
+		// The parent of a nested create is not created again
+		if r.holder && r.key.Name == without {
+			continue
+		}
+		properties[jsonName(r.Name)] = restRef(m.Name + "Create" + r.GoName + "Input")
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// restNestedSchema returns the schema of the nested creates of relation r
+func restNestedSchema(m *restModel, r *graphQLRelation, related *restModel) map[string]interface{} {
+	var create map[string]interface{}
+	if r.holder {
+		create = restRef(related.Name + "CreateInput")
+	} else {
+		create = restCreateSchema(related, r.relatedKey.Name)
+	}
+	if r.list {
+		create = map[string]interface{}{"type": "array", "items": create}
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"description":          fmt.Sprintf("Creates the %s of a new %s", r.Name, m.Name),
+		"properties":           map[string]interface{}{"create": create},
+		"required":             []string{"create"},
+		"additionalProperties": false,
+	}
+}
+
+func restUpdateSchema(m *restModel) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, f := range m.fields {
+		if f != m.key {
+			properties[jsonName(f.Name)] = restFieldSchema(f)
+		}
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// restPaths adds the collection and item paths of m to paths
+func restPaths(paths map[string]interface{}, m *restModel) {
+	errorResponse := map[string]interface{}{"$ref": "#/components/responses/Error"}
+	tags := []string{m.Name}
+
+	var parameters []interface{}
+	for _, f := range m.fields {
+		if f.filter == "" {
+			continue
+		}
+		properties := make(map[string]interface{})
+		value := restValueSchema(f)
+		for _, op := range restOpNames(f) {
+			switch op {
+			case "in", "notIn":
+				properties[op] = map[string]interface{}{"type": "string", "description": "Comma separated values"}
+			case "isNull":
+				properties[op] = map[string]interface{}{"type": "boolean"}
+			default:
+				properties[op] = value
+			}
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name":        jsonName(f.Name),
+			"in":          "query",
+			"style":       "deepObject",
+			"explode":     true,
+			"description": fmt.Sprintf("Filters by %[1]s, e.g. %[1]s[not]=value. %[1]s=value is short for %[1]s[equals]=value.", jsonName(f.Name)),
+			"schema":      map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false},
+		})
+	}
+	parameters = append(parameters,
+		map[string]interface{}{"name": "take", "in": "query", "description": "Number of records to return", "schema": map[string]interface{}{"type": "integer", "minimum": 0}},
+		map[string]interface{}{"name": "skip", "in": "query", "description": "Number of records to skip", "schema": map[string]interface{}{"type": "integer", "minimum": 0}},
+		map[string]interface{}{"name": "orderBy", "in": "query", "description": "Comma separated fields to order by, prefixed with - for descending order", "schema": map[string]interface{}{"type": "string"}},
+	)
+	include := restIncludeParameter(m)
+	if include != nil {
+		parameters = append(parameters, include)
+	}
+
+	paths[m.path] = map[string]interface{}{
+		"get": map[string]interface{}{
+			"operationId": "list" + Plural(m.Name),
+			"summary":     fmt.Sprintf("List %s records", m.Name),
+			"tags":        tags,
+			"parameters":  parameters,
+			"responses": map[string]interface{}{
+				"200":     map[string]interface{}{"description": fmt.Sprintf("The %s records", m.Name), "content": restContent(map[string]interface{}{"type": "array", "items": restRef(m.Name)})},
+				"default": errorResponse,
+			},
+		},
+		"post": map[string]interface{}{
+			"operationId": "create" + m.Name,
+			"summary":     fmt.Sprintf("Create a %s record", m.Name),
+			"tags":        tags,
+			"requestBody": map[string]interface{}{"required": true, "content": restContent(restRef(m.Name + "CreateInput"))},
+			"responses": map[string]interface{}{
+				"201":     map[string]interface{}{"description": fmt.Sprintf("The created %s record", m.Name), "content": restContent(restRef(m.Name))},
+				"default": errorResponse,
+			},
+		},
+	}
+	if m.key == nil {
+		return
+	}
+
+	keyName := jsonName(m.key.Name)
+	record := map[string]interface{}{"description": fmt.Sprintf("The %s record", m.Name), "content": restContent(restRef(m.Name))}
+	get := map[string]interface{}{
+		"operationId": "get" + m.Name,
+		"summary":     fmt.Sprintf("Get a %s record by %s", m.Name, keyName),
+		"tags":        tags,
+		"responses":   map[string]interface{}{"200": record, "default": errorResponse},
+	}
+	if include != nil {
+		get["parameters"] = []interface{}{include}
+	}
+	paths[m.path+"/{"+keyName+"}"] = map[string]interface{}{
+		"parameters": []interface{}{map[string]interface{}{
+			"name":     keyName,
+			"in":       "path",
+			"required": true,
+			"schema":   restValueSchema(m.key),
+		}},
+		"get": get,
+		"patch": map[string]interface{}{
+			"operationId": "update" + m.Name,
+			"summary":     fmt.Sprintf("Update a %s record", m.Name),
+			"tags":        tags,
+			"requestBody": map[string]interface{}{"required": true, "content": restContent(restRef(m.Name + "UpdateInput"))},
+			"responses":   map[string]interface{}{"200": record, "default": errorResponse},
+		},
+		"delete": map[string]interface{}{
+			"operationId": "delete" + m.Name,
+			"summary":     fmt.Sprintf("Delete a %s record", m.Name),
+			"tags":        tags,
+			"responses":   map[string]interface{}{"200": map[string]interface{}{"description": fmt.Sprintf("The deleted %s record", m.Name), "content": restContent(restRef(m.Name))}, "default": errorResponse},
+		},
+	}
+}
+
+func restIncludeParameter(m *restModel) map[string]interface{} {
+	if len(m.relations) == 0 {
+		return nil
+	}
+	var relations []string
+	for _, r := range m.relations {
+		relations = append(relations, jsonName(r.Name))
+	}
+	return map[string]interface{}{
+		"name":        "include",
+		"in":          "query",
+		"description": "Comma separated relations to load: " + strings.Join(relations, ", "),
+		"schema":      map[string]interface{}{"type": "string"},
+	}
+}
+
+// restOpNames returns the filter operations of f, matching the operation
+// lists of rest.go
+func restOpNames(f *graphQLField) []string {
+	ops := []string{"equals", "not", "in", "notIn"}
+	switch restOps(f) {
+	case "restStringOps":
+		ops = append(ops, "lt", "lte", "gt", "gte", "contains", "startsWith", "endsWith")
+	case "restComparisonOps":
+		ops = append(ops, "lt", "lte", "gt", "gte")
+	}
+	if f.optional {
+		ops = append(ops, "isNull")
+	}
+	return ops
+}
+
+// renderRESTHandlers renders rest.go: the handlers of every model and the
+// inputs of creates
+func renderRESTHandlers(schemaAST *ast.SchemaAst, models []*restModel, provider string) ([]byte, error) {
+	decimal := false
+	enums := make(map[string]bool)
+	for _, m := range models {
+		for _, f := range m.fields {
+			decimal = decimal || f.goType == "types.Decimal"
+			if restEnum(f) {
+				enums[f.goType] = true
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by prisma-go. DO NOT EDIT.\n\npackage generated\n\nimport (\n")
+	sb.WriteString("\t\"context\"\n\t_ \"embed\"\n\t\"encoding/json\"\n\t\"errors\"\n\t\"fmt\"\n\t\"log/slog\"\n\t\"net/http\"\n\t\"net/url\"\n\t\"sort\"\n\t\"strconv\"\n\t\"strings\"\n\t\"time\"\n\n")
+	sb.WriteString("\t\"github.com/satishbabariya/prisma-go/query/builder\"\n\t\"github.com/satishbabariya/prisma-go/query/executor\"\n\t\"github.com/satishbabariya/prisma-go/runtime/client\"\n")
+	if decimal {
+		sb.WriteString("\t\"github.com/satishbabariya/prisma-go/runtime/types\"\n")
+	}
+	if bound := restBoundImports(models); len(bound) > 0 {
+		sb.WriteString("\n" + strings.Join(bound, ""))
+	}
+	sb.WriteString(")\n")
+	sb.WriteString(restRuntime)
+	fmt.Fprintf(&sb, "\n// restProvider is the database provider of the client, which executors of\n// transactions need\nconst restProvider = %q\n", provider)
+
+	sb.WriteString(`
+// NewRESTHandler creates the handler of the endpoints described by
+// openapi.json, which it serves at /openapi.json
+func NewRESTHandler(client *PrismaClient) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(OpenAPIDocument)
+	})
+`)
+	for _, m := range models {
+		fmt.Fprintf(&sb, "\t%s := New%sHandler(client)\n", lowerFirst(m.Name), m.Name)
+		fmt.Fprintf(&sb, "\tmux.Handle(%q, %s)\n\tmux.Handle(%q, %s)\n", m.path, lowerFirst(m.Name), m.path+"/", lowerFirst(m.Name))
+	}
+	sb.WriteString("\treturn mux\n}\n")
+
+	for _, enum := range schemaAST.Enums() {
+		name := enum.Name.Name
+		if !enums[name] {
+			continue
+		}
+		var values []string
+		for _, value := range enum.Values {
+			values = append(values, name+value.Name.Name)
+		}
+		fmt.Fprintf(&sb, "\nfunc restParse%[1]s(s string) (%[1]s, error) {\n\tswitch v := %[1]s(s); v {\n\tcase %[2]s:\n\t\treturn v, nil\n\t}\n\treturn \"\", fmt.Errorf(\"invalid %[1]s %%q\", s)\n}\n",
+			name, strings.Join(values, ", "))
+	}
+
+	byName := make(map[string]*restModel)
+	for _, m := range models {
+		byName[m.Name] = m
+	}
+	for _, m := range models {
+		writeRESTInputs(&sb, m)
+		writeRESTHandler(&sb, m, byName)
+	}
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", RESTHandlersFile, err)
+	}
+	return src, nil
+}
+
+// restRuntime is the part of rest.go that does not depend on the schema
+const restRuntime = `
+// OpenAPIDocument is the OpenAPI 3.1 document of the REST endpoints
+//
+//go:embed openapi.json
+var OpenAPIDocument []byte
+
+// Filter operations of query parameters, e.g. name[contains]=value
+var (
+	restEqualityOps   = []string{"equals", "not", "in", "notIn"}
+	restComparisonOps = append(restEqualityOps[:4:4], "lt", "lte", "gt", "gte")
+	restStringOps     = append(restComparisonOps[:8:8], "contains", "startsWith", "endsWith")
+)
+
+// restParams are the query parameters of lists that are not filters
+var restParams = map[string]bool{"take": true, "skip": true, "orderBy": true, "include": true}
+
+// restField is a field that lists can be filtered and ordered by
+type restField struct {
+	column string
+	filter func(w *builder.WhereBuilder, column, op, value string) error
+}
+
+// restFilter returns the filter of a field with values parsed by parse.
+// isNull is only accepted for nullable fields.
+func restFilter[T any](parse func(string) (T, error), ops []string, nullable bool) func(w *builder.WhereBuilder, column, op, value string) error {
+	return func(w *builder.WhereBuilder, column, op, value string) error {
+		if op == "isNull" && nullable {
+			null, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			if null {
+				w.IsNull(column)
+			} else {
+				w.IsNotNull(column)
+			}
+			return nil
+		}
+		known := false
+		for _, o := range ops {
+			known = known || o == op
+		}
+		if !known {
+			return fmt.Errorf("unknown operation %s", op)
+		}
+		if op == "in" || op == "notIn" {
+			var values []interface{}
+			if value != "" {
+				for _, s := range strings.Split(value, ",") {
+					v, err := parse(s)
+					if err != nil {
+						return err
+					}
+					values = append(values, v)
+				}
+			}
+			switch {
+			case op == "notIn" && len(values) > 0:
+				w.NotIn(column, values)
+			case op == "in" && len(values) > 0:
+				w.In(column, values)
+			case op == "in":
+				// An empty list matches no rows, which IN () cannot express
+				w.IsNull(column).IsNotNull(column)
+			}
+			return nil
+		}
+		v, err := parse(value)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case "equals":
+			w.Equals(column, v)
+		case "not":
+			w.NotEquals(column, v)
+		case "lt":
+			w.LessThan(column, v)
+		case "lte":
+			w.LessOrEqual(column, v)
+		case "gt":
+			w.GreaterThan(column, v)
+		case "gte":
+			w.GreaterOrEqual(column, v)
+		case "contains":
+			w.Like(column, "%"+value+"%")
+		case "startsWith":
+			w.Like(column, value+"%")
+		case "endsWith":
+			w.Like(column, "%"+value)
+		}
+		return nil
+	}
+}
+
+// restWhere returns the conditions of the filter query parameters of a
+// list, nil when there is none. name=value is short for name[equals]=value.
+func restWhere(model string, params url.Values, fields map[string]restField) (*builder.WhereBuilder, error) {
+	var w *builder.WhereBuilder
+	for _, key := range restKeys(params) {
+		if restParams[key] {
+			continue
+		}
+		name, op := key, "equals"
+		if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+			name, op = key[:i], key[i+1:len(key)-1]
+		}
+		field, ok := fields[name]
+		if !ok {
+			return nil, client.NewValidationError(model, name, fmt.Sprintf("unknown query parameter %s", key))
+		}
+		if w == nil {
+			w = builder.NewWhereBuilder()
+		}
+		for _, value := range params[key] {
+			if err := field.filter(w, field.column, op, value); err != nil {
+				return nil, client.NewValidationError(model, name, fmt.Sprintf("invalid query parameter %s: %v", key, err))
+			}
+		}
+	}
+	return w, nil
+}
+
+// restOrderBy returns the ORDER BY of an orderBy query parameter, a comma
+// separated list of fields prefixed with - for descending order
+func restOrderBy(model, value string, fields map[string]restField) (*builder.OrderByBuilder, error) {
+	if value == "" {
+		return nil, nil
+	}
+	orderBy := builder.NewOrderByBuilder()
+	for _, name := range strings.Split(value, ",") {
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		field, ok := fields[name]
+		if !ok {
+			return nil, client.NewValidationError(model, name, fmt.Sprintf("cannot order by %s", name))
+		}
+		if desc {
+			orderBy.Desc(field.column)
+		} else {
+			orderBy.Asc(field.column)
+		}
+	}
+	return orderBy, nil
+}
+
+// restPage reads the take and skip query parameters of a list
+func restPage(model string, params url.Values) (take, skip *int, err error) {
+	for _, p := range []struct {
+		name  string
+		value **int
+	}{{"take", &take}, {"skip", &skip}} {
+		s := params.Get(p.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return nil, nil, client.NewValidationError(model, p.name, fmt.Sprintf("%s must be a non-negative integer", p.name))
+		}
+		*p.value = &n
+	}
+	return take, skip, nil
+}
+
+// restList splits a comma separated query parameter
+func restList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func restKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func restValues[T any](values []T) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+func restString(s string) (string, error) {
+	return s, nil
+}
+
+func restInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func restFloat64(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+func restTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// restDecode decodes the JSON body of r into v, rejecting unknown fields
+func restDecode(r *http.Request, model string, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		field := ""
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			field = typeErr.Field
+		}
+		return client.NewValidationError(model, field, fmt.Sprintf("invalid request body: %v", err))
+	}
+	return nil
+}
+
+// restValue decodes the JSON value of a body field into v. null is only
+// accepted for nullable fields.
+func restValue(model, field string, raw json.RawMessage, v interface{}, nullable bool) error {
+	if string(raw) == "null" && !nullable {
+		return client.NewValidationError(model, field, fmt.Sprintf("%s must not be null", field))
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return client.NewValidationError(model, field, fmt.Sprintf("invalid %s: %v", field, err))
+	}
+	return nil
+}
+
+// restJSON returns the value stored for a Json field set to raw
+func restJSON(raw json.RawMessage) interface{} {
+	if string(raw) == "null" {
+		return nil
+	}
+	return string(raw)
+}
+
+// restRequired returns the error of a create without a required field
+func restRequired(model, field string) error {
+	return client.NewValidationError(model, field, fmt.Sprintf("%s is required", field))
+}
+
+// restTx creates the records of a request and its nested creates in one
+// transaction
+type restTx struct {
+	ctx  context.Context
+	exec *executor.TxExecutor
+}
+
+// restCreate runs create in a transaction, committed when it succeeds
+func restCreate[T any](ctx context.Context, c *PrismaClient, create func(tx *restTx) (*T, error)) (_ *T, err error) {
+	ctx, end := c.Observers().StartTransaction(ctx, restProvider)
+	defer func() { end(err) }()
+	sqlTx, err := c.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	exec := executor.NewTxExecutor(sqlTx, restProvider)
+	exec.SetErrorMapper(schemaNames.ClassifyError)
+	exec.SetObservers(c.Observers())
+	exec.SetPipeline(c.Pipeline())
+	exec.SetModelNames(schemaNames.Models())
+	record, err := create(&restTx{ctx: ctx, exec: exec})
+	if err != nil {
+		sqlTx.Rollback()
+		return nil, err
+	}
+	if err := sqlTx.Commit(); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// restInsert inserts data into table, returning the inserted record
+func restInsert[T any](tx *restTx, table string, data T) (*T, error) {
+	result, err := tx.exec.Create(tx.ctx, table, data)
+	if err != nil {
+		return nil, err
+	}
+	if record, ok := result.(*T); ok {
+		return record, nil
+	}
+	return &data, nil
+}
+
+// restErrorBody is the JSON body of an error response
+type restErrorBody struct {
+	Code    string                 ` + "`json:\"code\"`" + `
+	Message string                 ` + "`json:\"message\"`" + `
+	Model   string                 ` + "`json:\"model,omitempty\"`" + `
+	Field   string                 ` + "`json:\"field,omitempty\"`" + `
+	Meta    map[string]interface{} ` + "`json:\"meta,omitempty\"`" + `
+	Errors  []restErrorBody        ` + "`json:\"errors,omitempty\"`" + `
+}
+
+// restError writes err classified as a Prisma error, with the HTTP status
+// of its class. Server errors are logged, and their responses only carry
+// the code, so that SQL and driver messages do not reach clients.
+func restError(w http.ResponseWriter, model string, err error) {
+	var prismaErr *client.PrismaError
+	if !errors.As(schemaNames.ClassifyError("", err), &prismaErr) {
+		prismaErr = client.NewPrismaError("P0000", err.Error())
+	}
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(prismaErr, client.ErrValidationFailed), errors.Is(prismaErr, client.ErrNullConstraint):
+		status = http.StatusBadRequest
+	case errors.Is(prismaErr, client.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(prismaErr, client.ErrUniqueConstraint), errors.Is(prismaErr, client.ErrForeignKeyConstraint):
+		status = http.StatusConflict
+	case errors.Is(prismaErr, client.ErrTimeout):
+		status = http.StatusGatewayTimeout
+	}
+	if status >= http.StatusInternalServerError {
+		slog.Error("REST request failed", "model", model, "code", prismaErr.Code, "error", err)
+		restWrite(w, status, restErrorBody{Code: prismaErr.Code, Message: http.StatusText(status), Model: model})
+		return
+	}
+	body := restErrorBody{Code: prismaErr.Code, Message: prismaErr.Message, Model: prismaErr.Model, Field: prismaErr.Field}
+	if body.Model == "" {
+		body.Model = model
+	}
+	for key, value := range prismaErr.Meta {
+		if key == "cause" {
+			continue // The driver message
+		}
+		if body.Meta == nil {
+			body.Meta = make(map[string]interface{}, len(prismaErr.Meta))
+		}
+		body.Meta[key] = value
+	}
+	var fields client.ValidationErrors
+	if errors.As(err, &fields) && len(fields) > 1 {
+		for _, e := range fields {
+			body.Errors = append(body.Errors, restErrorBody{Code: e.Code, Message: e.Message, Model: e.Model, Field: e.Field})
+		}
+	}
+	restWrite(w, status, body)
+}
+
+func restWrite(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+`
+
+// restBoundImports returns the import specs of the packages of Go types
+// bound to Json fields with /// @go.type(...), sorted by import path
+func restBoundImports(models []*restModel) []string {
+	names := make(map[string]string) // import path -> package name
+	for _, m := range models {
+		for _, f := range m.fields {
+			if f.GoImport != "" {
+				names[f.GoImport] = f.GoImportName
+			}
+		}
+	}
+	paths := make([]string, 0, len(names))
+	for importPath := range names {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
+
+	specs := make([]string, len(paths))
+	for i, importPath := range paths {
+		if names[importPath] != path.Base(importPath) {
+			specs[i] = fmt.Sprintf("\t%s %q\n", names[importPath], importPath)
+		} else {
+			specs[i] = fmt.Sprintf("\t%q\n", importPath)
+		}
+	}
+	return specs
+}
+
+// restInputType returns the Go type of the values of f in create inputs.
+// Json fields without a bound Go type take the JSON of the request as is.
+func restInputType(f *graphQLField) string {
+	goType := strings.TrimPrefix(f.GoType, "*")
+	if goType == "interface{}" {
+		return "json.RawMessage"
+	}
+	return goType
+}
+
+// writeRESTInputs writes the create input of a model and the inputs of its
+// nested creates
+func writeRESTInputs(sb *strings.Builder, m *restModel) {
+	fmt.Fprintf(sb, "\n// %[1]sCreateInput is the body of POST %[2]s. Relations take nested creates\n// of related records.\ntype %[1]sCreateInput struct {\n", m.Name, m.path)
+	for _, f := range m.fields {
+		fmt.Fprintf(sb, "\t%s *%s `json:\"%s,omitempty\"`\n", f.GoName, restInputType(f), jsonName(f.Name))
+	}
+	for _, r := range m.relations {
+		fmt.Fprintf(sb, "\t%s *%sCreate%sInput `json:\"%s,omitempty\"`\n", r.GoName, m.Name, r.GoName, jsonName(r.Name))
+	}
+	sb.WriteString("}\n")
+
+	for _, r := range m.relations {
+		create := "*" + r.related + "CreateInput"
+		if r.list {
+			create = "[]" + r.related + "CreateInput"
+		}
+		fmt.Fprintf(sb, "\n// %[1]sCreate%[2]sInput creates the %[3]s of a new %[1]s\ntype %[1]sCreate%[2]sInput struct {\n\tCreate %[4]s `json:\"create\"`\n}\n",
+			m.Name, r.GoName, r.Name, create)
+	}
+
+	fmt.Fprintf(sb, "\n// Validate checks the required fields, enum values and @validate rules of in\nfunc (in *%sCreateInput) Validate() error {\n", m.Name)
+	for _, f := range m.fields {
+		if m.required[f.Name] {
+			fmt.Fprintf(sb, "\tif in.%s == nil {\n\t\treturn restRequired(%q, %q)\n\t}\n", f.GoName, m.Name, jsonName(f.Name))
+		}
+	}
+	for _, f := range m.fields {
+		writeRESTEnumCheck(sb, m, f, "in."+f.GoName, true)
+	}
+	var validated strings.Builder
+	for _, f := range m.fields {
+		if len(f.Validations) > 0 {
+			writeValidateCall(&validated, m.Name, f.FieldInfo, "in."+f.GoName, true)
+		}
+	}
+	if validated.Len() > 0 {
+		fmt.Fprintf(sb, "var errs client.ValidationErrors\n%sreturn errs.Err()\n}\n", validated.String())
+	} else {
+		sb.WriteString("\treturn nil\n}\n")
+	}
+
+	fmt.Fprintf(sb, "\n// record returns the %[1]s created from in\nfunc (in *%[1]sCreateInput) record() %[1]s {\n\tvar data %[1]s\n", m.Name)
+	for _, f := range m.fields {
+		switch {
+		case f.GoType == "*interface{}":
+			fmt.Fprintf(sb, "\tif in.%[1]s != nil {\n\t\tvar v interface{} = *in.%[1]s\n\t\tdata.%[1]s = &v\n\t}\n", f.GoName)
+		case f.pointer():
+			fmt.Fprintf(sb, "\tdata.%[1]s = in.%[1]s\n", f.GoName)
+		default:
+			fmt.Fprintf(sb, "\tif in.%[1]s != nil {\n\t\tdata.%[1]s = *in.%[1]s\n\t}\n", f.GoName)
+		}
+	}
+	sb.WriteString("\treturn data\n}\n")
+}
+
+// writeRESTEnumCheck writes the check that value, a pointer when pointer is
+// set, holds values of the enum of f
+func writeRESTEnumCheck(sb *strings.Builder, m *restModel, f *graphQLField, value string, pointer bool) {
+	if !restEnum(f) {
+		return
+	}
+	check := func(v string) string {
+		return fmt.Sprintf("if _, err := restParse%s(string(%s)); err != nil {\nreturn client.NewValidationError(%q, %q, err.Error())\n}\n",
+			f.goType, v, m.Name, jsonName(f.Name))
+	}
+	switch {
+	case f.list && pointer:
+		fmt.Fprintf(sb, "if %[1]s != nil {\nfor _, e := range *%[1]s {\n%[2]s}\n}\n", value, check("e"))
+	case f.list:
+		fmt.Fprintf(sb, "for _, e := range %s {\n%s}\n", value, check("e"))
+	case pointer:
+		fmt.Fprintf(sb, "if %s != nil {\n%s}\n", value, check("*"+value))
+	default:
+		sb.WriteString(check(value))
+	}
+}
+
+// writeRESTValidateCheck writes the check of value, a pointer when pointer
+// is set, against the @validate rules of f
+func writeRESTValidateCheck(sb *strings.Builder, m *restModel, f *graphQLField, value string, pointer bool) {
+	check := func(v string) string {
+		return fmt.Sprintf("if err := validate%s%s(%s); err != nil {\nreturn err\n}\n", m.Name, f.GoName, v)
+	}
+	if pointer {
+		fmt.Fprintf(sb, "if %s != nil {\n%s}\n", value, check("*"+value))
+		return
+	}
+	sb.WriteString(check(value))
+}
+
+// writeRESTHandler writes the handler of the endpoints of a model
+func writeRESTHandler(sb *strings.Builder, m *restModel, models map[string]*restModel) {
+	name, client := m.Name, "h.Client."+m.Name
+
+	fmt.Fprintf(sb, "\n// %sRESTFields are the fields %s can be filtered and ordered by\nvar %sRESTFields = map[string]restField{\n", lowerFirst(name), m.path, lowerFirst(name))
+	for _, f := range m.fields {
+		if f.filter == "" {
+			continue
+		}
+		fmt.Fprintf(sb, "\t%q: {column: %q, filter: restFilter(%s, %s, %t)},\n", jsonName(f.Name), f.DBName, restParser(f), restOps(f), f.optional)
+	}
+	sb.WriteString("}\n")
+
+	fmt.Fprintf(sb, `
+// %[1]sHandler serves the REST endpoints of %[1]s records under %[2]s
+type %[1]sHandler struct {
+	Client *PrismaClient
+	mux    *http.ServeMux
+}
+
+// New%[1]sHandler creates a handler of %[1]s records using client
+func New%[1]sHandler(client *PrismaClient) *%[1]sHandler {
+	h := &%[1]sHandler{Client: client, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET %[2]s", h.list)
+	h.mux.HandleFunc("POST %[2]s", h.create)
+`, name, m.path)
+	if m.key != nil {
+		item := m.path + "/{" + jsonName(m.key.Name) + "}"
+		fmt.Fprintf(sb, "\th.mux.HandleFunc(\"GET %[1]s\", h.get)\n\th.mux.HandleFunc(\"PATCH %[1]s\", h.update)\n\th.mux.HandleFunc(\"DELETE %[1]s\", h.delete)\n", item)
+	}
+	fmt.Fprintf(sb, `	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *%[1]sHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *%[1]sHandler) list(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q := %[2]s.Query()
+	var err error
+	if q.where, err = restWhere(%[1]q, params, %[3]sRESTFields); err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	if q.orderBy, err = restOrderBy(%[1]q, params.Get("orderBy"), %[3]sRESTFields); err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	take, skip, err := restPage(%[1]q, params)
+	if err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	if take != nil {
+		if *take == 0 {
+			restWrite(w, http.StatusOK, []%[1]s{})
+			return
+		}
+		q.Limit(*take)
+	}
+	if skip != nil {
+		q.Offset(*skip)
+	}
+	records, err := q.Execute(r.Context())
+	if err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	if records == nil {
+		records = []%[1]s{}
+	}
+	if err := h.include(r.Context(), records, params.Get("include")); err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	restWrite(w, http.StatusOK, records)
+}
+
+func (h *%[1]sHandler) create(w http.ResponseWriter, r *http.Request) {
+	var in %[1]sCreateInput
+	if err := restDecode(r, %[1]q, &in); err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	record, err := restCreate(r.Context(), h.Client, func(tx *restTx) (*%[1]s, error) {
+		return tx.create%[1]s(h.Client, &in)
+	})
+	if err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	restWrite(w, http.StatusCreated, record)
+}
+`, name, client, lowerFirst(name))
+
+	writeRESTCreate(sb, m, models)
+	writeRESTInclude(sb, m)
+	if m.key != nil {
+		writeRESTItem(sb, m)
+	}
+}
+
+// writeRESTCreate writes the create of a record with its nested creates.
+// Records the new record holds the foreign key of are created first, and
+// records holding a foreign key to it after it.
+func writeRESTCreate(sb *strings.Builder, m *restModel, models map[string]*restModel) {
+	fmt.Fprintf(sb, `
+// create%[1]s creates the record of in and its nested creates
+func (tx *restTx) create%[1]s(c *PrismaClient, in *%[1]sCreateInput) (*%[1]s, error) {
+`, m.Name)
+	for _, r := range m.relations {
+		if !r.holder {
+			continue
+		}
+		fmt.Fprintf(sb, "\tif in.%s != nil && in.%s.Create != nil {\n", r.GoName, r.GoName)
+		fmt.Fprintf(sb, "\t\tif in.%s != nil {\n\t\t\treturn nil, client.NewValidationError(%q, %q, %q)\n\t\t}\n",
+			r.key.GoName, m.Name, jsonName(r.Name), fmt.Sprintf("%s cannot be set together with a nested create of %s", jsonName(r.key.Name), jsonName(r.Name)))
+		fmt.Fprintf(sb, "\t\trelated, err := tx.create%s(c, in.%s.Create)\n\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n", r.related, r.GoName)
+		writeRESTAssign(sb, "in."+r.key.GoName, "related."+r.relatedKey.GoName, r.relatedKey.pointer())
+		sb.WriteString("\t}\n")
+	}
+	fmt.Fprintf(sb, "\tif err := in.Validate(); err != nil {\n\t\treturn nil, err\n\t}\n\trecord, err := restInsert(tx, c.%s.table, in.record())\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", m.Name)
+	for _, r := range m.relations {
+		if r.holder {
+			continue
+		}
+		related := models[r.related]
+		child := "in." + r.GoName + ".Create"
+		fmt.Fprintf(sb, "\tif in.%s != nil {\n", r.GoName)
+		if r.list {
+			fmt.Fprintf(sb, "\t\tfor i := range %s {\n", child)
+			child += "[i]"
+		} else {
+			fmt.Fprintf(sb, "\t\tif %s != nil {\n", child)
+		}
+		writeRESTAssign(sb, child+"."+r.relatedKey.GoName, "record."+r.key.GoName, r.key.pointer())
+		target := child
+		if r.list {
+			target = "&" + child
+		}
+		fmt.Fprintf(sb, "\t\t\tif _, err := tx.create%s(c, %s); err != nil {\n\t\t\t\treturn nil, err\n\t\t\t}\n\t\t}\n\t}\n", related.Name, target)
+	}
+	sb.WriteString("\treturn record, nil\n}\n")
+}
+
+// writeRESTAssign writes the assignment of value to the pointer field dest
+func writeRESTAssign(sb *strings.Builder, dest, value string, pointer bool) {
+	if pointer {
+		fmt.Fprintf(sb, "%s = %s\n", dest, value)
+		return
+	}
+	fmt.Fprintf(sb, "key := %s\n%s = &key\n", value, dest)
+}
+
+// writeRESTInclude writes the loading of the relations named in an include
+// query parameter, each with one IN query for all records
+func writeRESTInclude(sb *strings.Builder, m *restModel) {
+	fmt.Fprintf(sb, "\n// include loads the relations of records named in an include query\n// parameter\nfunc (h *%[1]sHandler) include(ctx context.Context, records []%[1]s, include string) error {\n\tfor _, name := range restList(include) {\n\t\tswitch name {\n", m.Name)
+	for _, r := range m.relations {
+		fmt.Fprintf(sb, "\t\tcase %q:\n\t\t\tif err := h.include%s(ctx, records); err != nil {\n\t\t\t\treturn err\n\t\t\t}\n", jsonName(r.Name), r.GoName)
+	}
+	fmt.Fprintf(sb, "\t\tdefault:\n\t\t\treturn client.NewValidationError(%q, name, fmt.Sprintf(\"cannot include %%s\", name))\n\t\t}\n\t}\n\treturn nil\n}\n", m.Name)
+
+	for _, r := range m.relations {
+		keyType := r.key.goType
+		value := "*" + r.related
+		if r.list {
+			value = "[]" + r.related
+		}
+		fmt.Fprintf(sb, "\nfunc (h *%sHandler) include%s(ctx context.Context, records []%s) error {\n\tvar keys []%s\n\tfor i := range records {\n", m.Name, r.GoName, m.Name, keyType)
+		key := "records[i]." + r.key.GoName
+		if r.key.pointer() {
+			fmt.Fprintf(sb, "\t\tif %s != nil {\n\t\t\tkeys = append(keys, *%s)\n\t\t}\n", key, key)
+		} else {
+			fmt.Fprintf(sb, "\t\tkeys = append(keys, %s)\n", key)
+		}
+		sb.WriteString("\t}\n\tif len(keys) == 0 {\n\t\treturn nil\n\t}\n")
+		fmt.Fprintf(sb, "\trelated, err := h.Client.%s.FindManyWhere(ctx, builder.NewWhereBuilder().In(%q, restValues(keys)))\n", r.related, r.relatedKey.DBName)
+		sb.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		fmt.Fprintf(sb, "\tvalues := make(map[%s]%s, len(keys))\n\tfor i := range related {\n", keyType, value)
+		relatedKey := "related[i]." + r.relatedKey.GoName
+		if r.relatedKey.pointer() {
+			fmt.Fprintf(sb, "\t\tif %s == nil {\n\t\t\tcontinue\n\t\t}\n", relatedKey)
+			relatedKey = "*" + relatedKey
+		}
+		if r.list {
+			fmt.Fprintf(sb, "\t\tvalues[%[1]s] = append(values[%[1]s], related[i])\n", relatedKey)
+		} else {
+			fmt.Fprintf(sb, "\t\tvalues[%s] = &related[i]\n", relatedKey)
+		}
+		sb.WriteString("\t}\n\tfor i := range records {\n")
+		if r.key.pointer() {
+			fmt.Fprintf(sb, "\t\tif %s == nil {\n", key)
+			if r.list {
+				fmt.Fprintf(sb, "\t\t\trecords[i].%s = []%s{}\n", r.GoName, r.related)
+			}
+			sb.WriteString("\t\t\tcontinue\n\t\t}\n")
+			key = "*" + key
+		}
+		fmt.Fprintf(sb, "\t\trecords[i].%s = values[%s]\n", r.GoName, key)
+		if r.list {
+			fmt.Fprintf(sb, "\t\tif records[i].%[1]s == nil {\n\t\t\trecords[i].%[1]s = []%[2]s{}\n\t\t}\n", r.GoName, r.related)
+		}
+		sb.WriteString("\t}\n\treturn nil\n}\n")
+	}
+}
+
+// writeRESTItem writes the endpoints of a record addressed by its key
+func writeRESTItem(sb *strings.Builder, m *restModel) {
+	key := m.key
+	fmt.Fprintf(sb, `
+// key parses the %[2]s of the record addressed by the path of r
+func (h *%[1]sHandler) key(r *http.Request) (%[3]s, error) {
+	key, err := %[4]s(r.PathValue(%[2]q))
+	if err != nil {
+		return key, client.NewValidationError(%[1]q, %[2]q, fmt.Sprintf("invalid %[2]s: %%v", err))
+	}
+	return key, nil
+}
+
+// find returns the record addressed by the path of r and its key
+func (h *%[1]sHandler) find(r *http.Request) (*%[1]s, %[3]s, error) {
+	key, err := h.key(r)
+	if err != nil {
+		return nil, key, err
+	}
+	record, err := h.Client.%[1]s.FindFirstWhere(r.Context(), builder.NewWhereBuilder().Equals(%[5]q, key))
+	if err != nil {
+		return nil, key, err
+	}
+	return record, key, nil
+}
+
+func (h *%[1]sHandler) get(w http.ResponseWriter, r *http.Request) {
+	record, _, err := h.find(r)
+	if err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	records := []%[1]s{*record}
+	if err := h.include(r.Context(), records, r.URL.Query().Get("include")); err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	restWrite(w, http.StatusOK, records[0])
+}
+
+func (h *%[1]sHandler) update(w http.ResponseWriter, r *http.Request) {
+	var body map[string]json.RawMessage
+	if err := restDecode(r, %[1]q, &body); err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	record, key, err := h.find(r)
+	if err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	if len(body) == 0 {
+		restWrite(w, http.StatusOK, record)
+		return
+	}
+	u := h.Client.%[1]s.Update()
+	if err := h.set(u, body); err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	u.Where().Equals(%[5]q, key)
+	updated, err := u.Execute(r.Context())
+	if err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	restWrite(w, http.StatusOK, updated)
+}
+
+func (h *%[1]sHandler) delete(w http.ResponseWriter, r *http.Request) {
+	record, key, err := h.find(r)
+	if err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	d := h.Client.%[1]s.Delete()
+	d.Equals(%[5]q, key)
+	if err := d.Execute(r.Context()); err != nil {
+		restError(w, %[1]q, err)
+		return
+	}
+	restWrite(w, http.StatusOK, record)
+}
+
+// set adds the fields of the body of an update to u
+func (h *%[1]sHandler) set(u *%[1]sUpdateBuilder, body map[string]json.RawMessage) error {
+	for _, name := range restKeys(body) {
+		raw := body[name]
+		switch name {
+`, m.Name, jsonName(key.Name), key.goType, restParser(key), key.DBName)
+
+	for _, f := range m.fields {
+		fmt.Fprintf(sb, "\t\tcase %q:\n", jsonName(f.Name))
+		if f == key {
+			fmt.Fprintf(sb, "\t\t\treturn client.NewValidationError(%q, name, %q)\n", m.Name, jsonName(f.Name)+" cannot be updated")
+			continue
+		}
+		if f.typ == "Json" {
+			if !f.optional {
+				fmt.Fprintf(sb, "\t\t\tif string(raw) == \"null\" {\n\t\t\t\treturn client.NewValidationError(%q, name, name+\" must not be null\")\n\t\t\t}\n", m.Name)
+			}
+			fmt.Fprintf(sb, "\t\t\tu.Set(%q, restJSON(raw))\n", f.DBName)
+			continue
+		}
+		fmt.Fprintf(sb, "\t\t\tvar v %s\n\t\t\tif err := restValue(%q, name, raw, &v, %t); err != nil {\n\t\t\t\treturn err\n\t\t\t}\n", f.GoType, m.Name, f.optional)
+		writeRESTEnumCheck(sb, m, f, "v", f.pointer())
+		if len(f.Validations) > 0 {
+			writeRESTValidateCheck(sb, m, f, "v", f.pointer())
+		}
+		fmt.Fprintf(sb, "\t\t\tu.Set(%q, v)\n", f.DBName)
+	}
+	fmt.Fprintf(sb, "\t\tdefault:\n\t\t\treturn client.NewValidationError(%q, name, fmt.Sprintf(\"unknown field %%s\", name))\n\t\t}\n\t}\n\treturn nil\n}\n", m.Name)
+}
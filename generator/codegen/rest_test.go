@@ -0,0 +1,139 @@
+package codegen
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+func TestGenerateRESTFiles(t *testing.T) {
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", mappedSchema)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	dbDiags := diagnostics.NewDiagnostics()
+	db := database.NewParserDatabase(files, &dbDiags, database.NoExtensionTypes{})
+	names := ResolveDatabaseNames(db)
+	models := GenerateModelsFromAST(schemaAST, names)
+
+	dir := t.TempDir()
+	if err := GenerateRESTFiles(schemaAST, models, names, "postgresql", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, OpenAPIFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage        `json:"paths"`
+		Components map[string]map[string]map[string]interface{} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid %s: %v", OpenAPIFile, err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+	for path, methods := range map[string][]string{
+		"/users":      {"get", "post"},
+		"/users/{id}": {"get", "patch", "delete", "parameters"},
+		"/posts":      {"get", "post"},
+	} {
+		for _, method := range methods {
+			if _, ok := doc.Paths[path][method]; !ok {
+				t.Errorf("%s has no %s", path, method)
+			}
+		}
+	}
+	schemas := doc.Components["schemas"]
+	if got := schemas["Role"]["enum"]; len(got.([]interface{})) != 2 || got.([]interface{})[0] != "admin" {
+		t.Errorf("Role enum = %v, want the database values", got)
+	}
+	if got := schemas["UserCreateInput"]["required"]; len(got.([]interface{})) != 1 || got.([]interface{})[0] != "email" {
+		t.Errorf("UserCreateInput requires %v, want [email]", got)
+	}
+	nested, _ := json.Marshal(schemas["UserCreatePostsInput"])
+	if strings.Contains(string(nested), "author") {
+		t.Errorf("nested create of posts takes the author from its parent: %s", nested)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, RESTHandlersFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), RESTHandlersFile, src, 0); err != nil {
+		t.Fatalf("invalid %s: %v\n%s", RESTHandlersFile, err, src)
+	}
+	for _, want := range []string{
+		"const restProvider = \"postgresql\"",
+		"\"name\":       {column: \"FullName\", filter: restFilter(restString, restStringOps, true)},",
+		"\"role\":       {column: \"role\", filter: restFilter(restParseRole, restEqualityOps, false)},",
+		"h.mux.HandleFunc(\"PATCH /users/{id}\", h.update)",
+		"in.Posts.Create[i].Authorid = &key",
+		"related, err := tx.createUser(c, in.Author.Create)",
+		"related, err := h.Client.Post.FindManyWhere(ctx, builder.NewWhereBuilder().In(\"AuthorRef\", restValues(keys)))",
+		"return client.NewValidationError(\"User\", name, \"id cannot be updated\")",
+		"u.Set(\"FullName\", v)",
+		// Server errors are logged rather than returned to clients
+		"slog.Error(\"REST request failed\", \"model\", model, \"code\", prismaErr.Code, \"error\", err)",
+		"restErrorBody{Code: prismaErr.Code, Message: http.StatusText(status), Model: model}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("%s does not contain %q", RESTHandlersFile, want)
+		}
+	}
+}
+
+func TestRESTJsonInputs(t *testing.T) {
+	schema := `
+datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+
+model User {
+  id       Int   @id
+  /// @go.type(github.com/acme/app/profiles.Profile)
+  profile  Json?
+  /// @go.type(github.com/acme/app/v2.Settings)
+  settings Json
+  extra    Json?
+  tags     Json
+}
+`
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", schema)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	models := buildRESTModels(schemaAST, GenerateModelsFromAST(schemaAST, nil))
+	src, err := renderRESTHandlers(schemaAST, models, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\t\"github.com/acme/app/profiles\"\n",
+		"\tapp \"github.com/acme/app/v2\"\n",
+		"Profile  *profiles.Profile",
+		"Settings *app.Settings",
+		"Extra    *json.RawMessage",
+		"Tags     *json.RawMessage",
+		"if in.Extra != nil {\n\t\tvar v interface{} = *in.Extra\n\t\tdata.Extra = &v\n\t}",
+		"if in.Tags != nil {\n\t\tdata.Tags = *in.Tags\n\t}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("%s does not contain %q", RESTHandlersFile, want)
+		}
+	}
+}
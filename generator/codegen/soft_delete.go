@@ -0,0 +1,112 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// softDeleteField returns the field of model marking its soft-deleted
+// records, which must be an optional DateTime, and false when model is not
+// soft-deleted
+func softDeleteField(model ModelInfo) (FieldInfo, bool, error) {
+	if model.SoftDeleteErr != "" {
+		return FieldInfo{}, false, fmt.Errorf("%s: %s", model.Name, model.SoftDeleteErr)
+	}
+	if model.SoftDelete == "" {
+		return FieldInfo{}, false, nil
+	}
+	for _, field := range model.Fields {
+		if field.Name != model.SoftDelete {
+			continue
+		}
+		if field.IsRelation || field.GoType != "*time.Time" {
+			return FieldInfo{}, false, fmt.Errorf("%s: @softDelete field %s must be an optional DateTime", model.Name, field.Name)
+		}
+		return field, true, nil
+	}
+	return FieldInfo{}, false, fmt.Errorf("%s: @softDelete(%s) does not name a field", model.Name, model.SoftDelete)
+}
+
+// buildSoftDeleteMethods builds the methods of the client of a soft-deleted
+// model escaping the scope of its reads and writes, and restoring its records
+func buildSoftDeleteMethods(model ModelInfo) []ast.Decl {
+	clientType := model.Name + "Client"
+	recv := &ast.FieldList{
+		List: []*ast.Field{
+			{Names: []*ast.Ident{ast.NewIdent("c")}, Type: &ast.StarExpr{X: ast.NewIdent(clientType)}},
+		},
+	}
+
+	// scoped returns the client over executor.<method>()
+	scoped := func(method, doc string) ast.Decl {
+		results := &ast.FieldList{
+			List: []*ast.Field{{Type: &ast.StarExpr{X: ast.NewIdent(clientType)}}},
+		}
+		body := newBlockStmt(
+			newReturnStmt(&ast.UnaryExpr{
+				Op: token.AND,
+				X: newCompositeLit(ast.NewIdent(clientType), []ast.Expr{
+					newKeyValueExpr("client", newSelectorExpr(ast.NewIdent("c"), "client")),
+					newKeyValueExpr("executor", newCallExpr(newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), method))),
+					newKeyValueExpr("table", newSelectorExpr(ast.NewIdent("c"), "table")),
+					newKeyValueExpr("relations", newSelectorExpr(ast.NewIdent("c"), "relations")),
+				}),
+			}),
+		)
+		return newFuncDecl(method, doc, recv, &ast.FieldList{}, results, body)
+	}
+
+	decls := []ast.Decl{
+		scoped("WithDeleted", fmt.Sprintf("WithDeleted returns a client whose reads and writes also see soft-deleted %s records", model.Name)),
+		scoped("OnlyDeleted", fmt.Sprintf("OnlyDeleted returns a client whose reads and writes only see soft-deleted %s records", model.Name)),
+	}
+
+	// Restore method
+	params := &ast.FieldList{
+		List: []*ast.Field{
+			{Names: []*ast.Ident{ast.NewIdent("ctx")}, Type: newSelectorExpr(ast.NewIdent("context"), "Context")},
+			{Names: []*ast.Ident{ast.NewIdent("where")}, Type: &ast.StarExpr{X: newSelectorExpr(ast.NewIdent("builder"), "WhereBuilder")}},
+		},
+	}
+	results := &ast.FieldList{
+		List: []*ast.Field{
+			{Type: ast.NewIdent("int64")},
+			{Type: ast.NewIdent("error")},
+		},
+	}
+	body := newBlockStmt(
+		&ast.DeclStmt{
+			Decl: &ast.GenDecl{
+				Tok: token.VAR,
+				Specs: []ast.Spec{
+					&ast.ValueSpec{
+						Names: []*ast.Ident{ast.NewIdent("whereClause")},
+						Type:  &ast.StarExpr{X: newSelectorExpr(ast.NewIdent("sqlgen"), "WhereClause")},
+					},
+				},
+			},
+		},
+		newIfStmt(
+			&ast.BinaryExpr{X: ast.NewIdent("where"), Op: token.NEQ, Y: ast.NewIdent("nil")},
+			newBlockStmt(
+				newAssignStmt(
+					[]ast.Expr{ast.NewIdent("whereClause")},
+					token.ASSIGN,
+					[]ast.Expr{newCallExpr(newSelectorExpr(ast.NewIdent("where"), "Build"))},
+				),
+			),
+			nil,
+		),
+		newReturnStmt(
+			newCallExpr(
+				newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Restore"),
+				ast.NewIdent("ctx"),
+				newSelectorExpr(ast.NewIdent("c"), "table"),
+				ast.NewIdent("whereClause"),
+			),
+		),
+	)
+	doc := fmt.Sprintf("Restore restores the soft-deleted %s records matching the WHERE clause, all of them when where is nil", model.Name)
+	return append(decls, newFuncDecl("Restore", doc, recv, params, results, body))
+}
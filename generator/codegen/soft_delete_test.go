@@ -0,0 +1,68 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+const softDeleteSchema = `
+datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+
+/// Posts of the blog
+/// @softDelete(deletedAt)
+model Post {
+  id        Int       @id
+  title     String    @unique
+  deletedAt DateTime?
+}
+
+model Tag {
+  id Int @id
+}
+`
+
+func TestSoftDeleteModels(t *testing.T) {
+	models := validatedModels(t, softDeleteSchema)
+	if models[0].SoftDelete != "deletedAt" || models[1].SoftDelete != "" {
+		t.Fatalf("soft delete fields = %q, %q, want deletedAt for Post only", models[0].SoftDelete, models[1].SoftDelete)
+	}
+
+	post, err := renderASTFile(buildModelFile(models[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`SoftDelete: "deleted_at"`,
+		"func (c *PostClient) WithDeleted() *PostClient {\n\treturn &PostClient{client: c.client, executor: c.executor.WithDeleted(), table: c.table, relations: c.relations}",
+		"func (c *PostClient) OnlyDeleted() *PostClient {",
+		"func (c *PostClient) Restore(ctx context.Context, where *builder.WhereBuilder) (int64, error) {",
+		"return c.executor.Restore(ctx, c.table, whereClause)",
+	} {
+		if !strings.Contains(string(post), want) {
+			t.Errorf("post_model.go does not contain %q", want)
+		}
+	}
+	tag, err := renderASTFile(buildModelFile(models[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(tag), "SoftDelete") || strings.Contains(string(tag), "Restore") {
+		t.Error("Tag is not soft-deleted but has soft delete methods")
+	}
+
+	for comment, want := range map[string]string{
+		"/// @softDelete(removedAt)": "does not name a field",
+		"/// @softDelete(name)":      "must be an optional DateTime",
+		"/// @softDelete(createdAt)": "must be an optional DateTime",
+		"/// @softDelete(deletedAt":  "is not closed",
+	} {
+		schema := "datasource db {\n  provider = \"sqlite\"\n  url = \"file:dev.db\"\n}\n\n" + comment + "\nmodel Post {\n  id Int @id\n  name String\n  createdAt DateTime\n  deletedAt DateTime?\n}\n"
+		_, _, err := softDeleteField(validatedModels(t, schema)[0])
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: got error %v, want %q", comment, err, want)
+		}
+	}
+}
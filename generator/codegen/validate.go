@@ -0,0 +1,294 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// ValidatorsFile is the file with the Validate methods of models whose
+// fields have /// @validate(...) rules
+const ValidatorsFile = "validate.go"
+
+// ValidationRule is a rule of a /// @validate(...) doc comment, e.g.
+// {Name: "max", Arg: "255"} for @validate(max: 255). A rule without Name
+// holds the text of a @validate comment that could not be parsed.
+type ValidationRule struct {
+	Name string
+	Arg  string
+}
+
+// validationAnnotations reads the /// @validate(...) doc comments of a
+// field. Rules are separated by commas and take an argument after a colon,
+// e.g. "@validate(email, max: 255)" or `@validate(regex: "^[a-z]+$")`.
+// Arguments may be quoted as Go strings; commas and parentheses inside
+// quotes and brackets do not end the rule.
+func validationAnnotations(field *ast.Field) []ValidationRule {
+	const marker = "@validate("
+	var rules []ValidationRule
+	doc := field.Documentation.GetText()
+	for {
+		start := strings.Index(doc, marker)
+		if start < 0 {
+			return rules
+		}
+		doc = doc[start+len(marker):]
+
+		args, end := splitValidationArgs(doc)
+		if end < 0 {
+			line, _, _ := strings.Cut(doc, "\n")
+			return append(rules, ValidationRule{Arg: line})
+		}
+		doc = doc[end+1:]
+		for _, arg := range args {
+			if arg == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(arg, ":")
+			value = strings.TrimSpace(value)
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			}
+			rules = append(rules, ValidationRule{Name: strings.TrimSpace(name), Arg: value})
+		}
+	}
+}
+
+// splitValidationArgs splits the arguments of a @validate comment at the
+// top-level commas, and returns the index of the closing parenthesis, or -1
+// when the comment is not closed
+func splitValidationArgs(s string) ([]string, int) {
+	var args []string
+	depth, begin := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`':
+			quote = c
+		case c == '\n':
+			return nil, -1
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case (c == ']' || c == '}') && depth > 0:
+			depth--
+		case c == ')' && depth > 0:
+			depth--
+		case c == ')':
+			if arg := strings.TrimSpace(s[begin:i]); arg != "" {
+				args = append(args, arg)
+			}
+			return args, i
+		case c == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(s[begin:i]))
+			begin = i + 1
+		}
+	}
+	return nil, -1
+}
+
+// validatedField is a field with @validate rules, checked against the type
+// of the field
+type validatedField struct {
+	FieldInfo
+	goType   string // Go type of a value, without pointer
+	checks   []validationCheck
+	patterns []string // regular expressions of regex rules
+}
+
+// validationCheck is the condition under which a value v fails a rule, and
+// the message of the failure
+type validationCheck struct {
+	fails   string
+	message string
+}
+
+// validatedFields returns the fields of model with @validate rules
+func validatedFields(model ModelInfo) ([]*validatedField, error) {
+	var fields []*validatedField
+	for _, field := range model.Fields {
+		if len(field.Validations) == 0 {
+			continue
+		}
+		f, err := checkValidations(model.Name, field)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", model.Name, field.Name, err)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// checkValidations builds the checks of the rules of field
+func checkValidations(model string, field FieldInfo) (*validatedField, error) {
+	f := &validatedField{FieldInfo: field, goType: strings.TrimPrefix(field.GoType, "*")}
+	text := f.goType == "string"
+	numeric := f.goType == "int" || f.goType == "int64" || f.goType == "float64"
+	if field.IsRelation || field.IsJson || (!text && !numeric) {
+		return nil, fmt.Errorf("@validate is not supported on fields of type %s", field.GoType)
+	}
+
+	for _, rule := range field.Validations {
+		var check validationCheck
+		switch rule.Name {
+		case "":
+			return nil, fmt.Errorf("unterminated @validate(%s", rule.Arg)
+		case "email", "url", "uuid":
+			if !text {
+				return nil, fmt.Errorf("@validate(%s) needs a String field", rule.Name)
+			}
+			if rule.Arg != "" {
+				return nil, fmt.Errorf("@validate(%s) takes no argument", rule.Name)
+			}
+			is := map[string]string{"email": "IsEmail", "url": "IsURL", "uuid": "IsUUID"}[rule.Name]
+			kind := map[string]string{"email": "an email address", "url": "a URL", "uuid": "a UUID"}[rule.Name]
+			check = validationCheck{"!client." + is + "(v)", field.Name + " must be " + kind}
+		case "min", "max":
+			op, bound := "<", "at least"
+			if rule.Name == "max" {
+				op, bound = ">", "at most"
+			}
+			switch {
+			case text:
+				n, err := strconv.Atoi(rule.Arg)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("@validate(%s) of a String field needs a length, got %q", rule.Name, rule.Arg)
+				}
+				unit := "characters"
+				if n == 1 {
+					unit = "character"
+				}
+				check = validationCheck{fmt.Sprintf("utf8.RuneCountInString(v) %s %d", op, n), fmt.Sprintf("%s must be %s %d %s long", field.Name, bound, n, unit)}
+			case f.goType == "float64":
+				if _, err := strconv.ParseFloat(rule.Arg, 64); err != nil {
+					return nil, fmt.Errorf("@validate(%s) needs a number, got %q", rule.Name, rule.Arg)
+				}
+				check = validationCheck{fmt.Sprintf("v %s %s", op, rule.Arg), fmt.Sprintf("%s must be %s %s", field.Name, bound, rule.Arg)}
+			default:
+				if _, err := strconv.ParseInt(rule.Arg, 10, 64); err != nil {
+					return nil, fmt.Errorf("@validate(%s) needs an integer, got %q", rule.Name, rule.Arg)
+				}
+				check = validationCheck{fmt.Sprintf("v %s %s", op, rule.Arg), fmt.Sprintf("%s must be %s %s", field.Name, bound, rule.Arg)}
+			}
+		case "regex":
+			if !text {
+				return nil, fmt.Errorf("@validate(regex) needs a String field")
+			}
+			if _, err := regexp.Compile(rule.Arg); err != nil {
+				return nil, fmt.Errorf("@validate(regex): %w", err)
+			}
+			check = validationCheck{fmt.Sprintf("!%s.MatchString(v)", f.patternName(model, len(f.patterns))), field.Name + " must match " + rule.Arg}
+			f.patterns = append(f.patterns, rule.Arg)
+		default:
+			return nil, fmt.Errorf("unknown @validate rule %q", rule.Name)
+		}
+		f.checks = append(f.checks, check)
+	}
+	return f, nil
+}
+
+// funcName returns the name of the function validating values of f
+func (f *validatedField) funcName(model string) string {
+	return "validate" + model + f.GoName
+}
+
+// patternName returns the name of the variable holding the i-th regular
+// expression of f
+func (f *validatedField) patternName(model string, i int) string {
+	name := lowerFirst(model) + f.GoName + "Pattern"
+	if i > 0 {
+		name += strconv.Itoa(i + 1)
+	}
+	return name
+}
+
+// writeValidateCall writes the check of value, a pointer when pointer is
+// set, added to the ValidationErrors errs
+func writeValidateCall(sb *strings.Builder, model string, f FieldInfo, value string, pointer bool) {
+	call := fmt.Sprintf("validate%s%s", model, f.GoName)
+	if pointer {
+		fmt.Fprintf(sb, "if %[1]s != nil {\nerrs.Add(%[2]s(*%[1]s))\n}\n", value, call)
+		return
+	}
+	fmt.Fprintf(sb, "errs.Add(%s(%s))\n", call, value)
+}
+
+// hasValidations reports whether fields of model have @validate rules
+func hasValidations(model ModelInfo) bool {
+	for _, field := range model.Fields {
+		if len(field.Validations) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// renderValidators renders validate.go with a Validate method for each
+// model with /// @validate(...) rules on its fields, or nil when no field
+// has rules. The generated Create validates records before inserting them,
+// and the Set methods of updates validate their values. The supported rules
+// are email, url, uuid, regex for String fields, and min and max, the
+// length of a String field or the value of a number.
+func renderValidators(models []ModelInfo) ([]byte, error) {
+	var body strings.Builder
+	usesRegexp, usesUTF8 := false, false
+	for _, model := range models {
+		fields, err := validatedFields(model)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&body, "\n// Validate checks the fields of m against their @validate rules\nfunc (m *%s) Validate() error {\nvar errs client.ValidationErrors\n", model.Name)
+		for _, f := range fields {
+			writeValidateCall(&body, model.Name, f.FieldInfo, "m."+f.GoName, strings.HasPrefix(f.GoType, "*"))
+		}
+		body.WriteString("return errs.Err()\n}\n")
+
+		for _, f := range fields {
+			for i, pattern := range f.patterns {
+				fmt.Fprintf(&body, "\nvar %s = regexp.MustCompile(%s)\n", f.patternName(model.Name, i), strconv.Quote(pattern))
+				usesRegexp = true
+			}
+			fmt.Fprintf(&body, "\n// %s checks a value of %s.%s against its @validate rules\nfunc %s(v %s) *client.PrismaError {\n",
+				f.funcName(model.Name), model.Name, f.Name, f.funcName(model.Name), f.goType)
+			for _, check := range f.checks {
+				fmt.Fprintf(&body, "if %s {\nreturn client.NewValidationError(%q, %q, %q)\n}\n", check.fails, model.Name, f.Name, check.message)
+				usesUTF8 = usesUTF8 || strings.Contains(check.fails, "utf8.")
+			}
+			body.WriteString("return nil\n}\n")
+		}
+	}
+	if body.Len() == 0 {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by prisma-go. DO NOT EDIT.\n\npackage generated\n\nimport (\n")
+	if usesRegexp {
+		sb.WriteString("\t\"regexp\"\n")
+	}
+	if usesUTF8 {
+		sb.WriteString("\t\"unicode/utf8\"\n")
+	}
+	sb.WriteString("\n\t\"github.com/satishbabariya/prisma-go/runtime/client\"\n)\n")
+	sb.WriteString(body.String())
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", ValidatorsFile, err)
+	}
+	return src, nil
+}
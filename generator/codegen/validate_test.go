@@ -0,0 +1,140 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl"
+)
+
+const validatedSchema = `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+model User {
+  id    Int     @id @default(autoincrement())
+  /// Login address
+  /// @validate(email, max: 255)
+  email String  @unique
+  /// @validate(regex: "^[a-z]+(, [a-z]+)*$", min:1)
+  tags  String?
+  /// @validate(min: 0, max: 150)
+  age   Int
+}
+
+model Post {
+  id Int @id
+}
+`
+
+func validatedModels(t *testing.T, schema string) []ModelInfo {
+	t.Helper()
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", schema)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	return GenerateModelsFromAST(schemaAST, nil)
+}
+
+func TestValidationAnnotations(t *testing.T) {
+	models := validatedModels(t, validatedSchema)
+	rules := map[string][]ValidationRule{}
+	for _, field := range models[0].Fields {
+		rules[field.Name] = field.Validations
+	}
+	want := map[string][]ValidationRule{
+		"email": {{Name: "email"}, {Name: "max", Arg: "255"}},
+		"tags":  {{Name: "regex", Arg: "^[a-z]+(, [a-z]+)*$"}, {Name: "min", Arg: "1"}},
+		"age":   {{Name: "min", Arg: "0"}, {Name: "max", Arg: "150"}},
+	}
+	for field, want := range want {
+		got := rules[field]
+		if len(got) != len(want) {
+			t.Errorf("rules of %s = %v, want %v", field, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("rules of %s = %v, want %v", field, got, want)
+			}
+		}
+	}
+	if len(rules["id"]) != 0 {
+		t.Errorf("id has rules %v", rules["id"])
+	}
+}
+
+func TestRenderValidators(t *testing.T) {
+	src, err := renderValidators(validatedModels(t, validatedSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), ValidatorsFile, src, 0); err != nil {
+		t.Fatalf("invalid %s: %v\n%s", ValidatorsFile, err, src)
+	}
+	for _, want := range []string{
+		"func (m *User) Validate() error {",
+		"errs.Add(validateUserEmail(m.Email))",
+		"if m.Tags != nil {\n\t\terrs.Add(validateUserTags(*m.Tags))",
+		"if !client.IsEmail(v) {",
+		"if utf8.RuneCountInString(v) > 255 {",
+		"var userTagsPattern = regexp.MustCompile(\"^[a-z]+(, [a-z]+)*$\")",
+		"return client.NewValidationError(\"User\", \"age\", \"age must be at most 150\")",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("%s does not contain %q", ValidatorsFile, want)
+		}
+	}
+	if strings.Contains(string(src), "*Post") {
+		t.Errorf("Post has no rules but got a Validate method")
+	}
+
+	// Without rules there is no file
+	if src, err := renderValidators(validatedModels(t, mappedSchema)); err != nil || src != nil {
+		t.Errorf("renderValidators without rules = %s, %v", src, err)
+	}
+
+	for rule, want := range map[string]string{
+		"/// @validate(email)\n  age Int":            "needs a String field",
+		"/// @validate(max: ten)\n  age Int":         `needs an integer, got "ten"`,
+		"/// @validate(regex: \"[\")\n  name String": "@validate(regex)",
+		"/// @validate(positive)\n  age Int":         `unknown @validate rule "positive"`,
+		"/// @validate(min: 1\n  name String":        "unterminated",
+	} {
+		schema := "datasource db {\n  provider = \"sqlite\"\n  url = \"file:dev.db\"\n}\n\nmodel User {\n  id Int @id\n  " + rule + "\n}\n"
+		_, err := renderValidators(validatedModels(t, schema))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: got error %v, want %q", rule, err, want)
+		}
+	}
+}
+
+func TestSensitiveAnnotation(t *testing.T) {
+	models := validatedModels(t, `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+model User {
+  id           Int    @id
+  /// Argon2 hash
+  /// @sensitive
+  passwordHash String
+  /// @sensitiveness is not an annotation
+  note         String
+}
+`)
+	sensitive := map[string]bool{}
+	for _, field := range models[0].Fields {
+		sensitive[field.Name] = field.IsSensitive
+	}
+	if !sensitive["passwordHash"] || sensitive["note"] || sensitive["id"] {
+		t.Errorf("sensitive fields = %v, want passwordHash only", sensitive)
+	}
+}
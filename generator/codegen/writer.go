@@ -1,17 +1,16 @@
 package codegen
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/token"
-	"os"
-	"path/filepath"
+	"path"
+	"sort"
 	"strings"
-	"time"
 
-	"github.com/satishbabariya/prisma-go/internal/debug"
 	prismaAST "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
 )
 
@@ -32,6 +31,8 @@ func getColumnType(goType string) string {
 		return "columns.BoolColumn"
 	case "time.Time":
 		return "columns.DateTimeColumn"
+	case "types.Decimal":
+		return "columns.DecimalColumn"
 	case "float32", "float64":
 		return "columns.IntColumn" // Use IntColumn for now, could add FloatColumn later
 	default:
@@ -52,6 +53,8 @@ func getColumnConstructor(columnType string) string {
 		return "columns.NewBoolColumn"
 	case "columns.DateTimeColumn":
 		return "columns.NewDateTimeColumn"
+	case "columns.DecimalColumn":
+		return "columns.NewDecimalColumn"
 	default:
 		return "columns.NewStringColumn"
 	}
@@ -149,21 +152,6 @@ func newImportSpec(path string) *ast.ImportSpec {
 	}
 }
 
-// addImports adds import declarations to the file
-func addImports(file *ast.File, imports []string) {
-	if len(imports) == 0 {
-		return
-	}
-	specs := make([]ast.Spec, len(imports))
-	for i, imp := range imports {
-		specs[i] = newImportSpec(imp)
-	}
-	file.Decls = append(file.Decls, &ast.GenDecl{
-		Tok:   token.IMPORT,
-		Specs: specs,
-	})
-}
-
 // newStructType creates a new struct type
 func newStructType(fields []*ast.Field) *ast.StructType {
 	return &ast.StructType{
@@ -350,68 +338,11 @@ func newBlockStmt(stmts ...ast.Stmt) *ast.BlockStmt {
 	}
 }
 
-// writeASTFile writes an AST file to disk with proper formatting
-func writeASTFile(file *ast.File, filePath string) error {
-	// Create directory if needed
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	// Create file
-	f, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer f.Close()
-
-	// Format and write the file
-	debug.Debug("Formatting AST", "decl_count", len(file.Decls))
-	formatStart := time.Now()
-	fset := token.NewFileSet()
-	if err := format.Node(f, fset, file); err != nil {
-		return fmt.Errorf("failed to format file: %w", err)
-	}
-	debug.Debug("AST formatted successfully", "elapsed", time.Since(formatStart))
-
-	return nil
-}
-
-// GenerateModelsFile generates the models.go file using AST
-func GenerateModelsFile(schemaAST *prismaAST.SchemaAst, models []ModelInfo, outputDir string) error {
-	// Create AST file
-	file := newFile("generated")
-
-	// Add header comment
-	file.Comments = []*ast.CommentGroup{
-		{
-			List: []*ast.Comment{
-				{Text: "// Code generated by prisma-go. DO NOT EDIT."},
-			},
-		},
-	}
-
-	// Check for time.Time usage
-	hasDateTime := false
-	for _, model := range models {
-		for _, field := range model.Fields {
-			if strings.Contains(field.GoType, "time.Time") {
-				hasDateTime = true
-				break
-			}
-		}
-		if hasDateTime {
-			break
-		}
-	}
-
-	// Add imports
-	imports := []string{"github.com/satishbabariya/prisma-go/query/columns"}
-	if hasDateTime {
-		imports = append([]string{"time"}, imports...)
-	}
-	addImports(file, imports)
+// buildEnumDecls builds a string type for each enum of the schema, with
+// constants holding the database values from names
+func buildEnumDecls(schemaAST *prismaAST.SchemaAst, names *DatabaseNames) []ast.Decl {
+	var decls []ast.Decl
 
-	// Generate enum types BEFORE models (since models reference enums)
 	for _, enumAST := range schemaAST.Enums() {
 		enumName := enumAST.Name.Name
 
@@ -425,25 +356,26 @@ func GenerateModelsFile(schemaAST *prismaAST.SchemaAst, models []ModelInfo, outp
 				},
 			},
 		}
-		file.Decls = append(file.Decls, typeDecl)
+		decls = append(decls, typeDecl)
 
 		// Create const block for enum values
 		constSpecs := []ast.Spec{}
 		for i, value := range enumAST.Values {
 			valueIdent := value.Name.Name
+			dbValue := names.EnumValue(enumName, valueIdent)
 			var valueExpr ast.Expr
 
 			if i == 0 {
 				// First value: PlayerStatusACTIVE PlayerStatus = "ACTIVE"
 				valueExpr = &ast.BasicLit{
 					Kind:  token.STRING,
-					Value: fmt.Sprintf("\"%s\"", valueIdent),
+					Value: fmt.Sprintf("%q", dbValue),
 				}
 			} else {
 				// Subsequent values: PlayerStatusINACTIVE PlayerStatus = "INACTIVE"
 				valueExpr = &ast.BasicLit{
 					Kind:  token.STRING,
-					Value: fmt.Sprintf("\"%s\"", valueIdent),
+					Value: fmt.Sprintf("%q", dbValue),
 				}
 			}
 
@@ -460,102 +392,107 @@ func GenerateModelsFile(schemaAST *prismaAST.SchemaAst, models []ModelInfo, outp
 				Tok:   token.CONST,
 				Specs: constSpecs,
 			}
-			file.Decls = append(file.Decls, constDecl)
+			decls = append(decls, constDecl)
 		}
 	}
 
-	// Generate model structs
-	for _, model := range models {
-		// Create struct fields
-		fields := make([]*ast.Field, 0, len(model.Fields))
-		for _, field := range model.Fields {
-			fieldAST := newField(field.GoName, parseTypeFromString(field.GoType), field.Tags)
-			fields = append(fields, fieldAST)
+	return decls
+}
+
+// buildModelDecls builds the struct of a model with its TableName method,
+// and the struct and instance of its column references
+func buildModelDecls(model ModelInfo) []ast.Decl {
+	var decls []ast.Decl
+	modelName := model.Name
+	tableName := model.TableName
+
+	// Create struct fields
+	fields := make([]*ast.Field, 0, len(model.Fields))
+	for _, field := range model.Fields {
+		fieldAST := newField(field.GoName, parseTypeFromString(field.GoType), field.Tags)
+		fields = append(fields, fieldAST)
+	}
+	for _, computed := range model.Computed {
+		if computed.GoType == "" {
+			continue
 		}
+		tags := fmt.Sprintf("`json:\"%s\" db:\"-\"`", toSnakeCase(computed.Name))
+		fields = append(fields, newField(computed.GoName, parseTypeFromString(computed.GoType), tags))
+	}
 
-		// Create struct type declaration
-		structType := newStructType(fields)
-		typeDecl := newTypeDecl(model.Name, fmt.Sprintf("%s represents the %s model", model.Name, model.Name), structType)
-		file.Decls = append(file.Decls, typeDecl)
+	// Create struct type declaration
+	structType := newStructType(fields)
+	typeDecl := newTypeDecl(modelName, fmt.Sprintf("%s represents the %s model", modelName, modelName), structType)
+	decls = append(decls, typeDecl)
 
-		// Add TableName method
-		recv := &ast.FieldList{
-			List: []*ast.Field{
-				{
-					Type: ast.NewIdent(model.Name),
-				},
+	// Add TableName method
+	recv := &ast.FieldList{
+		List: []*ast.Field{
+			{
+				Type: ast.NewIdent(modelName),
 			},
-		}
-		params := &ast.FieldList{}
-		results := &ast.FieldList{
-			List: []*ast.Field{
-				{
-					Type: ast.NewIdent("string"),
-				},
+		},
+	}
+	params := &ast.FieldList{}
+	results := &ast.FieldList{
+		List: []*ast.Field{
+			{
+				Type: ast.NewIdent("string"),
 			},
-		}
-		body := newBlockStmt(
-			newReturnStmt(newStringLit(model.TableName)),
-		)
-		method := newFuncDecl("TableName", fmt.Sprintf("TableName returns the table name for %s", model.Name), recv, params, results, body)
-		file.Decls = append(file.Decls, method)
+		},
 	}
+	body := newBlockStmt(
+		newReturnStmt(newStringLit(tableName)),
+	)
+	method := newFuncDecl("TableName", fmt.Sprintf("TableName returns the table name for %s", modelName), recv, params, results, body)
+	decls = append(decls, method)
 
-	// Generate column structs and instances
-	for _, model := range models {
-		modelName := model.Name
-		tableName := model.TableName
-
-		// Generate column struct
-		columnFields := make([]*ast.Field, 0)
-		for _, field := range model.Fields {
-			if !field.IsRelation {
-				columnType := getColumnType(field.GoType)
-				fieldAST := newField(field.GoName, parseTypeFromString(columnType), "")
-				columnFields = append(columnFields, fieldAST)
-			}
+	// Generate column struct
+	columnFields := make([]*ast.Field, 0)
+	for _, field := range model.Fields {
+		if !field.IsRelation {
+			columnType := getColumnType(field.GoType)
+			fieldAST := newField(field.GoName, parseTypeFromString(columnType), "")
+			columnFields = append(columnFields, fieldAST)
 		}
+	}
+	if len(columnFields) == 0 {
+		return decls
+	}
 
-		if len(columnFields) > 0 {
-			columnStructType := newStructType(columnFields)
-			columnTypeName := modelName + "Columns"
-			typeDecl := newTypeDecl(columnTypeName, fmt.Sprintf("%s provides type-safe column references for %s", columnTypeName, modelName), columnStructType)
-			file.Decls = append(file.Decls, typeDecl)
-
-			// Generate column instance
-			instanceFields := make([]ast.Expr, 0)
-			for _, field := range model.Fields {
-				if !field.IsRelation {
-					fieldName := field.GoName
-					columnName := toSnakeCase(field.Name)
-					columnType := getColumnType(field.GoType)
-					constructor := getColumnConstructor(columnType)
-
-					// Create constructor call: columns.NewIntColumn("table", "column")
-					constructorExpr := parseTypeFromString(constructor)
-					callExpr := newCallExpr(constructorExpr, newStringLit(tableName), newStringLit(columnName))
-					instanceFields = append(instanceFields, newKeyValueExpr(fieldName, callExpr))
-				}
-			}
+	columnStructType := newStructType(columnFields)
+	columnTypeName := modelName + "Columns"
+	typeDecl = newTypeDecl(columnTypeName, fmt.Sprintf("%s provides type-safe column references for %s", columnTypeName, modelName), columnStructType)
+	decls = append(decls, typeDecl)
 
-			if len(instanceFields) > 0 {
-				varType := parseTypeFromString(columnTypeName)
-				compositeLit := newCompositeLit(varType, instanceFields)
-				varName := modelName + "ColumnsInstance"
-				varDecl := newVarDecl(varName, nil, compositeLit)
-				varDecl.Doc = &ast.CommentGroup{
-					List: []*ast.Comment{
-						{Text: fmt.Sprintf("// %s provides type-safe column references for %s", varName, modelName)},
-					},
-				}
-				file.Decls = append(file.Decls, varDecl)
-			}
+	// Generate column instance
+	instanceFields := make([]ast.Expr, 0)
+	for _, field := range model.Fields {
+		if !field.IsRelation {
+			fieldName := field.GoName
+			columnName := field.DBName
+			columnType := getColumnType(field.GoType)
+			constructor := getColumnConstructor(columnType)
+
+			// Create constructor call: columns.NewIntColumn("table", "column")
+			constructorExpr := parseTypeFromString(constructor)
+			callExpr := newCallExpr(constructorExpr, newStringLit(tableName), newStringLit(columnName))
+			instanceFields = append(instanceFields, newKeyValueExpr(fieldName, callExpr))
 		}
 	}
 
-	// Write AST file to disk
-	filePath := filepath.Join(outputDir, "models.go")
-	return writeASTFile(file, filePath)
+	varType := parseTypeFromString(columnTypeName)
+	compositeLit := newCompositeLit(varType, instanceFields)
+	varName := modelName + "ColumnsInstance"
+	varDecl := newVarDecl(varName, nil, compositeLit)
+	varDecl.Doc = &ast.CommentGroup{
+		List: []*ast.Comment{
+			{Text: fmt.Sprintf("// %s provides type-safe column references for %s", varName, modelName)},
+		},
+	}
+	decls = append(decls, varDecl)
+
+	return decls
 }
 
 // buildPrismaClientStruct builds the PrismaClient struct AST
@@ -578,6 +515,90 @@ func buildPrismaClientStruct(models []ModelInfo) *ast.GenDecl {
 	return newTypeDecl("PrismaClient", "PrismaClient is the main client for database operations", newStructType(fields))
 }
 
+// buildSchemaNamesDecl builds the schemaNames variable, mapping tables to
+// the names of their models for classified errors and diagnostics
+func buildSchemaNamesDecl(models []ModelInfo) *ast.GenDecl {
+	var tables []ast.Expr
+	for _, model := range models {
+		tables = append(tables, newMapKeyValueExpr(newStringLit(model.TableName), ast.NewIdent(tableNamesVar(model))))
+	}
+	decl := newVarDecl("schemaNames", nil, newCompositeLit(newSelectorExpr(ast.NewIdent("client"), "SchemaNames"), tables))
+	decl.Doc = &ast.CommentGroup{
+		List: []*ast.Comment{
+			{Text: "// schemaNames maps tables to their models in classified errors and diagnostics"},
+		},
+	}
+	return decl
+}
+
+// tableNamesVar returns the name of the variable holding the names of the
+// table of model
+func tableNamesVar(model ModelInfo) string {
+	return lowerFirst(model.Name) + "TableNames"
+}
+
+// buildTableNamesDecl builds the variable mapping the columns of the table
+// of model to its fields, with its sensitive columns and relations,
+// declared in the file of the model so that changing its fields leaves
+// client.go as it is
+func buildTableNamesDecl(model ModelInfo) *ast.GenDecl {
+	var columns []ast.Expr
+	seen := make(map[string]bool)
+	for _, field := range model.Fields {
+		// Of fields sharing a column, which the schema should not have, the
+		// first is named
+		if field.IsRelation || seen[field.DBName] {
+			continue
+		}
+		seen[field.DBName] = true
+		columns = append(columns, newMapKeyValueExpr(newStringLit(field.DBName), newStringLit(field.Name)))
+	}
+	elts := []ast.Expr{
+		newKeyValueExpr("Model", newStringLit(model.Name)),
+		newKeyValueExpr("Fields", newCompositeLit(
+			&ast.MapType{Key: ast.NewIdent("string"), Value: ast.NewIdent("string")},
+			columns,
+		)),
+	}
+	var sensitive []ast.Expr
+	for _, field := range model.Fields {
+		if field.IsSensitive && !field.IsRelation {
+			sensitive = append(sensitive, newStringLit(field.DBName))
+		}
+	}
+	if len(sensitive) > 0 {
+		elts = append(elts, newKeyValueExpr("Sensitive", newCompositeLit(&ast.ArrayType{Elt: ast.NewIdent("string")}, sensitive)))
+	}
+	var relations []ast.Expr
+	for _, rel := range model.Relations {
+		if rel.ForeignKeyColumn == "" {
+			continue
+		}
+		relations = append(relations, newMapKeyValueExpr(newStringLit(rel.FieldName), newCompositeLit(nil, []ast.Expr{
+			newKeyValueExpr("Model", newStringLit(rel.RelatedModel)),
+			newKeyValueExpr("List", newBoolLit(rel.IsList)),
+			newKeyValueExpr("ForeignKey", newStringLit(rel.ForeignKeyColumn)),
+			newKeyValueExpr("References", newStringLit(rel.LocalKeyColumn)),
+		})))
+	}
+	if field, ok, _ := softDeleteField(model); ok {
+		elts = append(elts, newKeyValueExpr("SoftDelete", newStringLit(field.DBName)))
+	}
+	if len(relations) > 0 {
+		elts = append(elts, newKeyValueExpr("Relations", newCompositeLit(
+			&ast.MapType{Key: ast.NewIdent("string"), Value: newSelectorExpr(ast.NewIdent("client"), "RelationNames")},
+			relations,
+		)))
+	}
+	decl := newVarDecl(tableNamesVar(model), nil, newCompositeLit(newSelectorExpr(ast.NewIdent("client"), "TableNames"), elts))
+	decl.Doc = &ast.CommentGroup{
+		List: []*ast.Comment{
+			{Text: fmt.Sprintf("// %s maps the columns and relations of %s to their fields", tableNamesVar(model), model.Name)},
+		},
+	}
+	return decl
+}
+
 // buildNewPrismaClientFunc builds the NewPrismaClient function AST
 func buildNewPrismaClientFunc(models []ModelInfo, provider string) *ast.FuncDecl {
 	params := &ast.FieldList{
@@ -657,6 +678,48 @@ func buildNewPrismaClientFunc(models []ModelInfo, provider string) *ast.FuncDecl
 				),
 			},
 		),
+		// exec.SetErrorMapper(schemaNames.ClassifyError)
+		&ast.ExprStmt{
+			X: newCallExpr(
+				newSelectorExpr(ast.NewIdent("exec"), "SetErrorMapper"),
+				newSelectorExpr(ast.NewIdent("schemaNames"), "ClassifyError"),
+			),
+		},
+		// exec.SetObservers(baseClient.Observers())
+		&ast.ExprStmt{
+			X: newCallExpr(
+				newSelectorExpr(ast.NewIdent("exec"), "SetObservers"),
+				newCallExpr(newSelectorExpr(ast.NewIdent("baseClient"), "Observers")),
+			),
+		},
+		// exec.SetPipeline(baseClient.Pipeline())
+		&ast.ExprStmt{
+			X: newCallExpr(
+				newSelectorExpr(ast.NewIdent("exec"), "SetPipeline"),
+				newCallExpr(newSelectorExpr(ast.NewIdent("baseClient"), "Pipeline")),
+			),
+		},
+		// exec.SetSoftDelete(schemaNames.SoftDeletes())
+		&ast.ExprStmt{
+			X: newCallExpr(
+				newSelectorExpr(ast.NewIdent("exec"), "SetSoftDelete"),
+				newCallExpr(newSelectorExpr(ast.NewIdent("schemaNames"), "SoftDeletes")),
+			),
+		},
+		// exec.SetModelNames(schemaNames.Models())
+		&ast.ExprStmt{
+			X: newCallExpr(
+				newSelectorExpr(ast.NewIdent("exec"), "SetModelNames"),
+				newCallExpr(newSelectorExpr(ast.NewIdent("schemaNames"), "Models")),
+			),
+		},
+		// baseClient.SetSchemaNames(schemaNames)
+		&ast.ExprStmt{
+			X: newCallExpr(
+				newSelectorExpr(ast.NewIdent("baseClient"), "SetSchemaNames"),
+				ast.NewIdent("schemaNames"),
+			),
+		},
 	}
 
 	// Add model client initialization
@@ -670,9 +733,9 @@ func buildNewPrismaClientFunc(models []ModelInfo, provider string) *ast.FuncDecl
 					newCompositeLit(
 						newSelectorExpr(ast.NewIdent("executor"), "RelationMetadata"),
 						[]ast.Expr{
-							newKeyValueExpr("RelatedTable", newStringLit(rel.ForeignKeyTable)),
-							newKeyValueExpr("ForeignKey", newStringLit(toSnakeCase(rel.ForeignKey))),
-							newKeyValueExpr("LocalKey", newStringLit(toSnakeCase(rel.LocalKey))),
+							newKeyValueExpr("RelatedTable", newStringLit(rel.RelatedTable)),
+							newKeyValueExpr("ForeignKey", newStringLit(rel.ForeignKeyColumn)),
+							newKeyValueExpr("LocalKey", newStringLit(rel.LocalKeyColumn)),
 							newKeyValueExpr("IsList", newBoolLit(rel.IsList)),
 						},
 					),
@@ -744,12 +807,15 @@ func buildRawSQLMethods() []ast.Decl {
 	}
 	body := newBlockStmt(
 		newReturnStmt(
-			newCallExpr(
-				newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "PrismaClient"), "Raw"),
-				ast.NewIdent("ctx"),
-				ast.NewIdent("query"),
-				ast.NewIdent("args"),
-			),
+			&ast.CallExpr{
+				Fun: newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "PrismaClient"), "Raw"),
+				Args: []ast.Expr{
+					ast.NewIdent("ctx"),
+					ast.NewIdent("query"),
+					ast.NewIdent("args"),
+				},
+				Ellipsis: 1,
+			},
 		),
 	)
 	methods = append(methods, newFuncDecl("Raw", "Raw executes a raw SQL query and returns the result", recv, params, results, body))
@@ -770,13 +836,16 @@ func buildRawSQLMethods() []ast.Decl {
 	}
 	body = newBlockStmt(
 		newReturnStmt(
-			newCallExpr(
-				newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "PrismaClient"), "RawScan"),
-				ast.NewIdent("ctx"),
-				ast.NewIdent("dest"),
-				ast.NewIdent("query"),
-				ast.NewIdent("args"),
-			),
+			&ast.CallExpr{
+				Fun: newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "PrismaClient"), "RawScan"),
+				Args: []ast.Expr{
+					ast.NewIdent("ctx"),
+					ast.NewIdent("dest"),
+					ast.NewIdent("query"),
+					ast.NewIdent("args"),
+				},
+				Ellipsis: 1,
+			},
 		),
 	)
 	methods = append(methods, newFuncDecl("RawScan", "RawScan executes a raw SQL query and scans the results into the destination", recv, params, results, body))
@@ -797,12 +866,15 @@ func buildRawSQLMethods() []ast.Decl {
 	}
 	body = newBlockStmt(
 		newReturnStmt(
-			newCallExpr(
-				newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "PrismaClient"), "RawExec"),
-				ast.NewIdent("ctx"),
-				ast.NewIdent("query"),
-				ast.NewIdent("args"),
-			),
+			&ast.CallExpr{
+				Fun: newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "PrismaClient"), "RawExec"),
+				Args: []ast.Expr{
+					ast.NewIdent("ctx"),
+					ast.NewIdent("query"),
+					ast.NewIdent("args"),
+				},
+				Ellipsis: 1,
+			},
 		),
 	)
 	methods = append(methods, newFuncDecl("RawExec", "RawExec executes a raw SQL statement (INSERT, UPDATE, DELETE) and returns the result", recv, params, results, body))
@@ -1641,7 +1713,7 @@ func buildFieldFilterMethods(model ModelInfo) []ast.Decl {
 	for _, field := range model.Fields {
 		goFieldName := field.GoName
 		goType := field.GoType
-		dbColumnName := toSnakeCase(field.Name)
+		dbColumnName := field.DBName
 
 		// Equals method
 		params := &ast.FieldList{
@@ -1670,7 +1742,7 @@ func buildFieldFilterMethods(model ModelInfo) []ast.Decl {
 				X: newCallExpr(
 					newSelectorExpr(newSelectorExpr(ast.NewIdent("q"), "where"), "Equals"),
 					newStringLit(dbColumnName),
-					ast.NewIdent("value"),
+					columnValueExpr(field),
 				),
 			},
 			newReturnStmt(ast.NewIdent("q")),
@@ -1698,7 +1770,7 @@ func buildFieldFilterMethods(model ModelInfo) []ast.Decl {
 				X: newCallExpr(
 					newSelectorExpr(newSelectorExpr(ast.NewIdent("q"), "where"), "NotEquals"),
 					newStringLit(dbColumnName),
-					ast.NewIdent("value"),
+					columnValueExpr(field),
 				),
 			},
 			newReturnStmt(ast.NewIdent("q")),
@@ -1866,11 +1938,82 @@ func buildFieldFilterMethods(model ModelInfo) []ast.Decl {
 				recv, params, results, body,
 			))
 		}
+
+		// JSON filter methods
+		if field.IsJson {
+			decls = append(decls, buildJsonFilterMethods(field, recv, results)...)
+		}
 	}
 
 	return decls
 }
 
+// columnValueExpr returns the expression passing the value parameter to a
+// query. Json values are wrapped in types.JsonValue so they are encoded.
+func columnValueExpr(field FieldInfo) ast.Expr {
+	if !field.IsJson {
+		return ast.NewIdent("value")
+	}
+	return &ast.CompositeLit{
+		Type: newSelectorExpr(ast.NewIdent("types"), "JsonValue"),
+		Elts: []ast.Expr{&ast.KeyValueExpr{Key: ast.NewIdent("V"), Value: ast.NewIdent("value")}},
+	}
+}
+
+// buildJsonFilterMethods generates the path, string, array and key filters
+// of a Json field. They compile through the provider's JSON operators.
+func buildJsonFilterMethods(field FieldInfo, recv, results *ast.FieldList) []ast.Decl {
+	dbColumnName := field.DBName
+	methods := []struct {
+		suffix string
+		call   string
+		params []string // name and type pairs
+		doc    string
+	}{
+		{"Path", "JsonPath", []string{"path", "string", "value", "interface{}"},
+			"filters where the JSON value at path equals the value"},
+		{"StringContains", "JsonStringContains", []string{"path", "string", "value", "string"},
+			"filters where the JSON string at path contains the value"},
+		{"ArrayContains", "JsonArrayContains", []string{"path", "string", "value", "interface{}"},
+			"filters where the JSON array at path contains the value, or all elements of a slice"},
+		{"HasKey", "JsonHasKey", []string{"key", "string"},
+			"filters where the top-level JSON object has the key"},
+	}
+
+	var decls []ast.Decl
+	for _, m := range methods {
+		params := &ast.FieldList{}
+		args := []ast.Expr{newStringLit(dbColumnName)}
+		for i := 0; i < len(m.params); i += 2 {
+			params.List = append(params.List, &ast.Field{
+				Names: []*ast.Ident{ast.NewIdent(m.params[i])},
+				Type:  parseTypeFromString(m.params[i+1]),
+			})
+			args = append(args, ast.NewIdent(m.params[i]))
+		}
+		body := newBlockStmt(
+			newIfStmt(
+				&ast.BinaryExpr{X: newSelectorExpr(ast.NewIdent("q"), "where"), Op: token.EQL, Y: ast.NewIdent("nil")},
+				newBlockStmt(
+					newAssignStmt(
+						[]ast.Expr{newSelectorExpr(ast.NewIdent("q"), "where")},
+						token.ASSIGN,
+						[]ast.Expr{newCallExpr(newSelectorExpr(ast.NewIdent("builder"), "NewWhereBuilder"))},
+					),
+				),
+				nil,
+			),
+			&ast.ExprStmt{
+				X: newCallExpr(newSelectorExpr(newSelectorExpr(ast.NewIdent("q"), "where"), m.call), args...),
+			},
+			newReturnStmt(ast.NewIdent("q")),
+		)
+		name := field.GoName + m.suffix
+		decls = append(decls, newFuncDecl(name, name+" "+m.doc, recv, params, results, body))
+	}
+	return decls
+}
+
 // buildOrderByMethods builds OrderBy methods for QueryBuilder
 func buildOrderByMethods(model ModelInfo) []ast.Decl {
 	var decls []ast.Decl
@@ -1891,7 +2034,7 @@ func buildOrderByMethods(model ModelInfo) []ast.Decl {
 
 	for _, field := range model.Fields {
 		goFieldName := field.GoName
-		dbColumnName := toSnakeCase(field.Name)
+		dbColumnName := field.DBName
 
 		// OrderByAsc
 		body := newBlockStmt(
@@ -2301,6 +2444,21 @@ func buildCRUDMethods(model ModelInfo) []ast.Decl {
 		),
 		newReturnStmt(&ast.UnaryExpr{Op: token.AND, X: ast.NewIdent("data")}, ast.NewIdent("nil")),
 	)
+	validated := hasValidations(model)
+	if validated {
+		// Check the @validate rules before inserting
+		body.List = append([]ast.Stmt{
+			&ast.IfStmt{
+				Init: newAssignStmt(
+					[]ast.Expr{ast.NewIdent("err")},
+					token.DEFINE,
+					[]ast.Expr{newCallExpr(newSelectorExpr(ast.NewIdent("data"), "Validate"))},
+				),
+				Cond: &ast.BinaryExpr{X: ast.NewIdent("err"), Op: token.NEQ, Y: ast.NewIdent("nil")},
+				Body: newBlockStmt(newReturnStmt(ast.NewIdent("nil"), ast.NewIdent("err"))),
+			},
+		}, body.List...)
+	}
 	decls = append(decls, newFuncDecl("Create", fmt.Sprintf("Create creates a new %s record", modelName), recv, params, results, body))
 
 	// UpdateBuilder type
@@ -2308,6 +2466,13 @@ func buildCRUDMethods(model ModelInfo) []ast.Decl {
 		{Type: &ast.StarExpr{X: newSelectorExpr(ast.NewIdent("builder"), "UpdateBuilder")}},
 		{Names: []*ast.Ident{ast.NewIdent("client")}, Type: &ast.StarExpr{X: ast.NewIdent(modelName + "Client")}},
 	}
+	if validated {
+		// Values failing @validate rules, reported by Execute
+		updateBuilderFields = append(updateBuilderFields, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent("invalid")},
+			Type:  newSelectorExpr(ast.NewIdent("client"), "ValidationErrors"),
+		})
+	}
 	updateBuilderType := newTypeDecl(
 		modelName+"UpdateBuilder",
 		fmt.Sprintf("%sUpdateBuilder builds UPDATE queries for %s", modelName, modelName),
@@ -2477,13 +2642,26 @@ func buildCRUDMethods(model ModelInfo) []ast.Decl {
 			nil,
 		),
 	)
+	if validated {
+		body.List = append([]ast.Stmt{
+			&ast.IfStmt{
+				Init: newAssignStmt(
+					[]ast.Expr{ast.NewIdent("err")},
+					token.DEFINE,
+					[]ast.Expr{newCallExpr(newSelectorExpr(newSelectorExpr(ast.NewIdent("u"), "invalid"), "Err"))},
+				),
+				Cond: &ast.BinaryExpr{X: ast.NewIdent("err"), Op: token.NEQ, Y: ast.NewIdent("nil")},
+				Body: newBlockStmt(newReturnStmt(ast.NewIdent("nil"), ast.NewIdent("err"))),
+			},
+		}, body.List...)
+	}
 	decls = append(decls, newFuncDecl("Execute", "Execute executes the UPDATE query", recv, params, results, body))
 
 	// Generate Set methods for UpdateBuilder
 	for _, field := range model.Fields {
 		goFieldName := field.GoName
 		goType := field.GoType
-		dbColumnName := toSnakeCase(field.Name)
+		dbColumnName := field.DBName
 
 		recv = &ast.FieldList{
 			List: []*ast.Field{
@@ -2505,11 +2683,32 @@ func buildCRUDMethods(model ModelInfo) []ast.Decl {
 				X: newCallExpr(
 					newSelectorExpr(newSelectorExpr(ast.NewIdent("u"), "UpdateBuilder"), "Set"),
 					newStringLit(dbColumnName),
-					ast.NewIdent("value"),
+					columnValueExpr(field),
 				),
 			},
 			newReturnStmt(ast.NewIdent("u")),
 		)
+		if len(field.Validations) > 0 {
+			// u.invalid.Add(validateUserEmail(value)), skipped for nil pointers
+			value := ast.Expr(ast.NewIdent("value"))
+			if strings.HasPrefix(goType, "*") {
+				value = &ast.StarExpr{X: value}
+			}
+			var check ast.Stmt = &ast.ExprStmt{
+				X: newCallExpr(
+					newSelectorExpr(newSelectorExpr(ast.NewIdent("u"), "invalid"), "Add"),
+					newCallExpr(ast.NewIdent("validate"+modelName+goFieldName), value),
+				),
+			}
+			if strings.HasPrefix(goType, "*") {
+				check = newIfStmt(
+					&ast.BinaryExpr{X: ast.NewIdent("value"), Op: token.NEQ, Y: ast.NewIdent("nil")},
+					newBlockStmt(check),
+					nil,
+				)
+			}
+			body.List = append([]ast.Stmt{check}, body.List...)
+		}
 		decls = append(decls, newFuncDecl(
 			"Set"+goFieldName,
 			fmt.Sprintf("Set%s sets the %s field", goFieldName, field.Name),
@@ -2594,7 +2793,7 @@ func buildCRUDMethods(model ModelInfo) []ast.Decl {
 	for _, field := range model.Fields {
 		goFieldName := field.GoName
 		goType := field.GoType
-		dbColumnName := toSnakeCase(field.Name)
+		dbColumnName := field.DBName
 
 		recv = &ast.FieldList{
 			List: []*ast.Field{
@@ -2616,7 +2815,7 @@ func buildCRUDMethods(model ModelInfo) []ast.Decl {
 				X: newCallExpr(
 					newSelectorExpr(newSelectorExpr(ast.NewIdent("d"), "WhereBuilder"), "Equals"),
 					newStringLit(dbColumnName),
-					ast.NewIdent("value"),
+					columnValueExpr(field),
 				),
 			},
 			newReturnStmt(ast.NewIdent("d")),
@@ -2632,7 +2831,7 @@ func buildCRUDMethods(model ModelInfo) []ast.Decl {
 				X: newCallExpr(
 					newSelectorExpr(newSelectorExpr(ast.NewIdent("d"), "WhereBuilder"), "NotEquals"),
 					newStringLit(dbColumnName),
-					ast.NewIdent("value"),
+					columnValueExpr(field),
 				),
 			},
 			newReturnStmt(ast.NewIdent("d")),
@@ -2761,7 +2960,13 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 	for _, field := range model.Fields {
 		if !field.IsRelation && isNumericType(field.GoType) {
 			goFieldName := field.GoName
-			dbColumnName := toSnakeCase(field.Name)
+			dbColumnName := field.DBName
+
+			// Decimal columns are aggregated exactly instead of as float64
+			resultType, suffix := "float64", ""
+			if isDecimalType(field.GoType) {
+				resultType, suffix = "types.Decimal", "Decimal"
+			}
 
 			// Sum
 			params = &ast.FieldList{
@@ -2771,14 +2976,14 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 			}
 			results = &ast.FieldList{
 				List: []*ast.Field{
-					{Type: ast.NewIdent("float64")},
+					{Type: parseTypeFromString(resultType)},
 					{Type: ast.NewIdent("error")},
 				},
 			}
 			body = newBlockStmt(
 				newReturnStmt(
 					newCallExpr(
-						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Sum"),
+						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Sum"+suffix),
 						ast.NewIdent("ctx"),
 						newSelectorExpr(ast.NewIdent("c"), "table"),
 						newStringLit(dbColumnName),
@@ -2820,7 +3025,7 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 				),
 				newReturnStmt(
 					newCallExpr(
-						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Sum"),
+						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Sum"+suffix),
 						ast.NewIdent("ctx"),
 						newSelectorExpr(ast.NewIdent("c"), "table"),
 						newStringLit(dbColumnName),
@@ -2839,7 +3044,7 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 			body = newBlockStmt(
 				newReturnStmt(
 					newCallExpr(
-						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Avg"),
+						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Avg"+suffix),
 						ast.NewIdent("ctx"),
 						newSelectorExpr(ast.NewIdent("c"), "table"),
 						newStringLit(dbColumnName),
@@ -2881,7 +3086,7 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 				),
 				newReturnStmt(
 					newCallExpr(
-						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Avg"),
+						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Avg"+suffix),
 						ast.NewIdent("ctx"),
 						newSelectorExpr(ast.NewIdent("c"), "table"),
 						newStringLit(dbColumnName),
@@ -2900,7 +3105,7 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 			body = newBlockStmt(
 				newReturnStmt(
 					newCallExpr(
-						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Min"),
+						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Min"+suffix),
 						ast.NewIdent("ctx"),
 						newSelectorExpr(ast.NewIdent("c"), "table"),
 						newStringLit(dbColumnName),
@@ -2942,7 +3147,7 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 				),
 				newReturnStmt(
 					newCallExpr(
-						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Min"),
+						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Min"+suffix),
 						ast.NewIdent("ctx"),
 						newSelectorExpr(ast.NewIdent("c"), "table"),
 						newStringLit(dbColumnName),
@@ -2961,7 +3166,7 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 			body = newBlockStmt(
 				newReturnStmt(
 					newCallExpr(
-						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Max"),
+						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Max"+suffix),
 						ast.NewIdent("ctx"),
 						newSelectorExpr(ast.NewIdent("c"), "table"),
 						newStringLit(dbColumnName),
@@ -3003,7 +3208,7 @@ func buildAggregationMethods(model ModelInfo) []ast.Decl {
 				),
 				newReturnStmt(
 					newCallExpr(
-						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Max"),
+						newSelectorExpr(newSelectorExpr(ast.NewIdent("c"), "executor"), "Max"+suffix),
 						ast.NewIdent("ctx"),
 						newSelectorExpr(ast.NewIdent("c"), "table"),
 						newStringLit(dbColumnName),
@@ -3150,70 +3355,116 @@ func buildModelClientDecls(model ModelInfo) []ast.Decl {
 	// 13. Aggregation methods
 	decls = append(decls, buildAggregationMethods(model)...)
 
+	// 14. Soft delete methods
+	if _, ok, _ := softDeleteField(model); ok {
+		decls = append(decls, buildSoftDeleteMethods(model)...)
+	}
+
 	return decls
 }
 
-// GenerateClientFile generates the client.go file using AST
-func GenerateClientFile(models []ModelInfo, provider string, outputDir string) error {
-	// Create AST file
+// generatedHeader is the first line of every generated Go file
+const generatedHeader = "// Code generated by prisma-go. DO NOT EDIT."
+
+// newGeneratedFile creates a file of the generated package with decls,
+// importing the packages they use
+func newGeneratedFile(decls []ast.Decl, models []ModelInfo) *ast.File {
 	file := newFile("generated")
+	file.Decls = decls
+	addUsedImports(file, models)
+	return file
+}
 
-	// Add header comment
-	file.Comments = []*ast.CommentGroup{
-		{
-			List: []*ast.Comment{
-				{Text: "// Code generated by prisma-go. DO NOT EDIT."},
-			},
-		},
-	}
+// generatedPackages maps the package names generated code refers to to
+// their import paths
+var generatedPackages = map[string]string{
+	"context":  "context",
+	"sql":      "database/sql",
+	"time":     "time",
+	"builder":  "github.com/satishbabariya/prisma-go/query/builder",
+	"columns":  "github.com/satishbabariya/prisma-go/query/columns",
+	"executor": "github.com/satishbabariya/prisma-go/query/executor",
+	"sqlgen":   "github.com/satishbabariya/prisma-go/query/sqlgen",
+	"client":   "github.com/satishbabariya/prisma-go/runtime/client",
+	"types":    "github.com/satishbabariya/prisma-go/runtime/types",
+}
 
-	// Add imports
-	imports := []string{
-		"context",
-		"database/sql",
-		"time",
-		"github.com/satishbabariya/prisma-go/query/builder",
-		"github.com/satishbabariya/prisma-go/query/columns",
-		"github.com/satishbabariya/prisma-go/query/executor",
-		"github.com/satishbabariya/prisma-go/query/sqlgen",
-		"github.com/satishbabariya/prisma-go/runtime/client",
+// addUsedImports adds an import declaration for the packages the file
+// refers to, including those of Go types bound to Json fields of models
+// with /// @go.type(...), sorted by import path
+func addUsedImports(file *ast.File, models []ModelInfo) {
+	packages := make(map[string]string, len(generatedPackages))
+	for name, importPath := range generatedPackages {
+		packages[name] = importPath
+	}
+	for _, model := range models {
+		for _, field := range model.Fields {
+			if field.GoImport != "" {
+				packages[field.GoImportName] = field.GoImport
+			}
+		}
 	}
-	addImports(file, imports)
-
-	// Add PrismaClient struct
-	file.Decls = append(file.Decls, buildPrismaClientStruct(models))
-
-	// Add NewPrismaClient function
-	file.Decls = append(file.Decls, buildNewPrismaClientFunc(models, provider))
-
-	// Add raw SQL methods
-	rawMethods := buildRawSQLMethods()
-	file.Decls = append(file.Decls, rawMethods...)
 
-	// Generate all model client declarations using AST
-	debug.Debug("Starting client code generation", "model_count", len(models))
-	startTime := time.Now()
+	used := make(map[string]string) // import path -> package name
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				if importPath, ok := packages[ident.Name]; ok {
+					used[importPath] = ident.Name
+				}
+			}
+		}
+		return true
+	})
+	if len(used) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(used))
+	for importPath := range used {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
 
-	for i, model := range models {
-		if i%10 == 0 && len(models) > 10 {
-			debug.Debug("Generating client code", "progress", fmt.Sprintf("%d/%d", i, len(models)), "elapsed", time.Since(startTime))
+	specs := make([]ast.Spec, len(paths))
+	for i, importPath := range paths {
+		spec := newImportSpec(importPath)
+		if used[importPath] != path.Base(importPath) {
+			spec.Name = ast.NewIdent(used[importPath])
 		}
+		specs[i] = spec
+	}
+	file.Decls = append([]ast.Decl{&ast.GenDecl{Tok: token.IMPORT, Specs: specs}}, file.Decls...)
+}
 
-		// Generate all declarations for this model client
-		modelDecls := buildModelClientDecls(model)
-		file.Decls = append(file.Decls, modelDecls...)
+// buildClientFile builds client.go, the PrismaClient with a client for each
+// model and the raw SQL methods
+func buildClientFile(models []ModelInfo, provider string) *ast.File {
+	decls := []ast.Decl{
+		buildPrismaClientStruct(models),
+		buildSchemaNamesDecl(models),
+		buildNewPrismaClientFunc(models, provider),
 	}
+	decls = append(decls, buildRawSQLMethods()...)
+	return newGeneratedFile(decls, models)
+}
 
-	debug.Debug("Client code generation completed", "total_elapsed", time.Since(startTime), "models", len(models), "total_decls", len(file.Decls))
+// buildModelFile builds the file of a model: its struct, column references
+// and client
+func buildModelFile(model ModelInfo) *ast.File {
+	decls := buildModelDecls(model)
+	decls = append(decls, buildTableNamesDecl(model))
+	decls = append(decls, buildModelClientDecls(model)...)
+	return newGeneratedFile(decls, []ModelInfo{model})
+}
 
-	// Write AST file to disk
-	filePath := filepath.Join(outputDir, "client.go")
-	debug.Debug("Writing AST file to disk", "path", filePath, "decl_count", len(file.Decls))
-	writeStart := time.Now()
-	err := writeASTFile(file, filePath)
-	if err != nil {
-		return err
+// renderASTFile formats a generated file below the generated code header.
+// The header is written as text, as comments without positions would be
+// printed after the package keyword.
+func renderASTFile(file *ast.File) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(generatedHeader + "\n\n")
+	if err := format.Node(&buf, token.NewFileSet(), file); err != nil {
+		return nil, fmt.Errorf("failed to format file: %w", err)
 	}
-	debug.Debug("AST file written successfully", "elapsed", time.Since(writeStart))
-	return nil
+	return buf.Bytes(), nil
 }
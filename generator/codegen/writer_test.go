@@ -0,0 +1,141 @@
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const generatedClientSchema = `
+datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+
+/// @softDelete(deletedAt)
+model Note {
+  id        Int       @id @default(autoincrement())
+  title     String    @unique
+  deletedAt DateTime?
+
+  @@map("notes")
+}
+`
+
+// generatedClientMain drives the generated client through the error
+// mapper, observers, pipeline, soft delete and model names NewPrismaClient
+// wires into its executor
+const generatedClientMain = `package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/runtime/client"
+
+	"github.com/satishbabariya/prisma-go/generator/codegen/%s/generated"
+)
+
+func main() {
+	if err := run(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	p, err := generated.NewPrismaClient(":memory:")
+	if err != nil {
+		return err
+	}
+	defer p.Disconnect(ctx)
+	p.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"CREATE TABLE notes (id INTEGER PRIMARY KEY, title TEXT, deleted_at DATETIME)",
+		"CREATE UNIQUE INDEX notes_title_unique ON notes (title) WHERE deleted_at IS NULL",
+	} {
+		if _, err := p.RawExec(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	var models []string
+	p.EnableSlowQueryLog(client.SlowQueryConfig{
+		Threshold:   time.Nanosecond,
+		OnSlowQuery: func(q client.SlowQuery) { models = append(models, q.Model+"."+q.Operation) },
+	})
+	var calls []string
+	p.Intercept(func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		calls = append(calls, call.Model+"."+call.Name)
+		return next(ctx)
+	})
+
+	if _, err := p.Note.Create(ctx, generated.Note{Title: "a"}); err != nil {
+		return err
+	}
+	_, err = p.Note.Create(ctx, generated.Note{Title: "a"})
+	var prismaErr *client.PrismaError
+	if !errors.As(err, &prismaErr) || prismaErr.Code != "P2002" || prismaErr.Model != "Note" {
+		return fmt.Errorf("duplicate create: %%v, want P2002 on Note", err)
+	}
+
+	if err := p.Note.Delete().TitleEquals("a").Execute(ctx); err != nil {
+		return err
+	}
+	if notes, err := p.Note.FindMany(ctx); err != nil || len(notes) != 0 {
+		return fmt.Errorf("after delete: %%d notes, %%v, want none", len(notes), err)
+	}
+	if notes, err := p.Note.WithDeleted().FindMany(ctx); err != nil || len(notes) != 1 {
+		return fmt.Errorf("with deleted: %%d notes, %%v, want the deleted note", len(notes), err)
+	}
+	if _, err := p.Note.Create(ctx, generated.Note{Title: "a"}); err != nil {
+		return fmt.Errorf("create after delete: %%v", err)
+	}
+
+	if len(calls) == 0 || calls[0] != "Note.create" {
+		return fmt.Errorf("intercepted %%v, want the operations of Note", calls)
+	}
+	if len(models) == 0 || models[0] != "Note.create" {
+		return fmt.Errorf("observed %%v, want the statements of Note", models)
+	}
+	return nil
+}
+`
+
+func TestGeneratedClientRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs a generated client")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+	// The client imports the runtime, so it is built inside this module
+	if err := os.MkdirAll("testdata", 0755); err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.MkdirTemp("testdata", "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+		os.Remove("testdata")
+	})
+
+	generateClientFiles(t, generatedClientSchema, filepath.Join(dir, "generated"))
+	src := []byte(fmt.Sprintf(generatedClientMain, filepath.ToSlash(dir)))
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated client: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,258 @@
+package dmmf
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/generator/codegen"
+	"github.com/satishbabariya/prisma-go/psl/core"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+	ast "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// FromFiles builds the parser database of schema files and describes it.
+// The document is nil when the schema has errors.
+func FromFiles(files []core.SourceFile) (*Document, diagnostics.Diagnostics) {
+	diags := diagnostics.NewDiagnostics()
+	db := database.NewParserDatabase(files, &diags, database.NoExtensionTypes{})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return Build(db), diags
+}
+
+// Build describes a schema from the walkers of its parser database. Table
+// and column names are the ones the generated client uses: the @@map and
+// @map names, or else the snake_case names that migrations create.
+func Build(db *database.ParserDatabase) *Document {
+	names := codegen.ResolveDatabaseNames(db)
+	doc := &Document{
+		Version:     Version,
+		Datasources: []Datasource{},
+		Datamodel: Datamodel{
+			Models: []Model{},
+			Enums:  []Enum{},
+			Types:  []Model{},
+		},
+	}
+
+	for _, source := range db.Datasources() {
+		ds := Datasource{Name: source.GetName()}
+		for _, prop := range source.Properties {
+			if prop.GetName() == "provider" && prop.Value != nil {
+				if value, ok := prop.Value.AsStringValue(); ok {
+					ds.Provider = value.GetValue()
+				}
+			}
+		}
+		doc.Datasources = append(doc.Datasources, ds)
+	}
+
+	for _, model := range db.WalkModels() {
+		doc.Datamodel.Models = append(doc.Datamodel.Models, buildModel(db, model, names))
+	}
+
+	for _, compositeType := range db.WalkCompositeTypes() {
+		t := Model{
+			Name:          compositeType.Name(),
+			DBName:        compositeType.Name(),
+			Documentation: documentation(compositeType.AstCompositeType().Documentation),
+			Fields:        []Field{},
+			PrimaryKey:    []string{},
+			UniqueFields:  [][]string{},
+			UniqueIndexes: []UniqueIndex{},
+		}
+		for _, field := range compositeType.Fields() {
+			astField := field.AstField()
+			f := Field{
+				Name:          field.Name(),
+				DBName:        field.DatabaseName(),
+				IsList:        astField.Arity.IsList(),
+				IsRequired:    !astField.Arity.IsOptional(),
+				Documentation: documentation(astField.Documentation),
+			}
+			f.Kind, f.Type = scalarKind(db, field.Type(), astField)
+			if value := field.DefaultValue(); value != nil {
+				f.HasDefaultValue = true
+				f.Default = defaultValue(value)
+			}
+			t.Fields = append(t.Fields, f)
+		}
+		doc.Datamodel.Types = append(doc.Datamodel.Types, t)
+	}
+
+	for _, enum := range db.WalkEnums() {
+		e := Enum{
+			Name:          enum.Name(),
+			DBName:        enum.DatabaseName(),
+			Documentation: documentation(enum.AstEnum().Documentation),
+			Values:        []EnumValue{},
+		}
+		for _, value := range enum.Values() {
+			e.Values = append(e.Values, EnumValue{
+				Name:   value.Name(),
+				DBName: value.DatabaseName(),
+			})
+		}
+		doc.Datamodel.Enums = append(doc.Datamodel.Enums, e)
+	}
+
+	return doc
+}
+
+// buildModel describes a model and its fields in declaration order
+func buildModel(db *database.ParserDatabase, model *database.ModelWalker, names *codegen.DatabaseNames) Model {
+	m := Model{
+		Name:          model.Name(),
+		DBName:        names.Table(model.Name()),
+		IsView:        model.IsView(),
+		Documentation: documentation(model.AstModel().Documentation),
+		Fields:        []Field{},
+		PrimaryKey:    []string{},
+		UniqueFields:  [][]string{},
+		UniqueIndexes: []UniqueIndex{},
+	}
+
+	// Field level @unique marks the field, @@unique lists the fields
+	uniqueFields := make(map[string]bool)
+	for _, index := range model.Indexes() {
+		if !index.IsUnique() {
+			continue
+		}
+		fields := []string{}
+		for _, field := range index.Fields() {
+			if scalar := field.ScalarField(); scalar != nil {
+				fields = append(fields, scalar.Name())
+			}
+		}
+		if index.IsDefinedOnField() && len(fields) == 1 {
+			uniqueFields[fields[0]] = true
+			continue
+		}
+		m.UniqueFields = append(m.UniqueFields, fields)
+		unique := UniqueIndex{Fields: fields}
+		if name := index.Name(); name != nil {
+			unique.Name = *name
+		}
+		m.UniqueIndexes = append(m.UniqueIndexes, unique)
+	}
+
+	if pk := model.PrimaryKey(); pk != nil {
+		for _, field := range pk.Fields() {
+			if scalar := field.ScalarField(); scalar != nil {
+				m.PrimaryKey = append(m.PrimaryKey, scalar.Name())
+			}
+		}
+	}
+
+	for _, field := range model.Fields() {
+		refined := field.Refine()
+		if refined == nil {
+			continue
+		}
+		astField := field.AstField()
+		f := Field{
+			Name:          field.Name(),
+			IsList:        astField.Arity.IsList(),
+			IsRequired:    !astField.Arity.IsOptional(),
+			Documentation: documentation(astField.Documentation),
+		}
+
+		if refined.IsScalar {
+			scalar := refined.Scalar
+			f.DBName = names.Column(model.Name(), f.Name)
+			f.Kind, f.Type = scalarKind(db, scalar.ScalarFieldType(), astField)
+			f.IsID = scalar.IsSinglePK()
+			f.IsUnique = uniqueFields[f.Name]
+			f.IsUpdatedAt = scalar.IsUpdatedAt()
+			if def := scalar.DefaultValue(); def != nil {
+				f.HasDefaultValue = true
+				f.Default = defaultValue(def.Value())
+			}
+			if native := scalar.RawNativeType(); native != nil {
+				f.NativeType = strings.TrimPrefix(*native, "@db.")
+			}
+		} else {
+			relation := refined.Relation
+			f.Kind = KindObject
+			f.Type = relation.ReferencedModel().Name()
+			if rel := relation.Relation(); rel != nil {
+				f.RelationName = rel.RelationName()
+			}
+			for _, from := range relation.ReferencingFields() {
+				f.RelationFromFields = append(f.RelationFromFields, from.Name())
+			}
+			for _, to := range relation.ReferencedFields() {
+				f.RelationToFields = append(f.RelationToFields, to.Name())
+			}
+			if relation.ExplicitOnDelete() {
+				f.RelationOnDelete = string(relation.OnDelete().Action)
+			}
+			if relation.ExplicitOnUpdate() {
+				f.RelationOnUpdate = string(relation.OnUpdate().Action)
+			}
+		}
+
+		m.Fields = append(m.Fields, f)
+	}
+
+	return m
+}
+
+// scalarKind returns the kind and type name of a scalar field type
+func scalarKind(db *database.ParserDatabase, fieldType database.ScalarFieldType, astField *ast.Field) (string, string) {
+	switch {
+	case fieldType.BuiltInScalar != nil:
+		return KindScalar, string(*fieldType.BuiltInScalar)
+	case fieldType.EnumID != nil:
+		return KindEnum, db.WalkEnum(*fieldType.EnumID).Name()
+	case fieldType.CompositeTypeID != nil:
+		return KindObject, db.WalkCompositeType(*fieldType.CompositeTypeID).Name()
+	case fieldType.ExtensionID != nil:
+		return KindScalar, db.GetExtensionTypePrismaName(*fieldType.ExtensionID)
+	}
+	if astField.Type != nil {
+		return KindUnsupported, astField.Type.String()
+	}
+	return KindUnsupported, ""
+}
+
+// documentation returns the text of /// comments without the space after
+// each ///
+func documentation(comments *ast.CommentBlock) string {
+	lines := strings.Split(comments.GetText(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// defaultValue converts the value of @default to its JSON form
+func defaultValue(expr ast.Expression) interface{} {
+	if str, ok := expr.AsStringValue(); ok {
+		return str.GetValue()
+	}
+	if num, ok := expr.AsNumericValue(); ok {
+		return json.Number(num.Value)
+	}
+	if b, ok := expr.AsBooleanValue(); ok {
+		return b
+	}
+	if fn, ok := expr.AsFunction(); ok {
+		def := FunctionDefault{Name: fn.Name, Args: []interface{}{}}
+		for _, arg := range fn.Arguments.Iter() {
+			def.Args = append(def.Args, defaultValue(arg.Value))
+		}
+		return def
+	}
+	if arr, ok := expr.AsArray(); ok {
+		values := make([]interface{}, 0, len(arr.Elements))
+		for _, elem := range arr.Elements {
+			values = append(values, defaultValue(elem))
+		}
+		return values
+	}
+	// Enum values and other constants
+	return expr.String()
+}
@@ -0,0 +1,191 @@
+package dmmf
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl"
+)
+
+const blogSchema = `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+enum Role {
+  ADMIN @map("admin")
+  USER
+
+  @@map("roles")
+}
+
+/// A registered user
+model User {
+  id      Int     @id @default(autoincrement()) @map("UserID")
+  email   String  @unique @db.VarChar(255)
+  role    Role    @default(USER)
+  tags    String[]
+  address Address?
+  posts   Post[]
+
+  @@map("Users")
+}
+
+model Post {
+  id        Int      @id @default(autoincrement())
+  title     String   @default("untitled")
+  published Boolean  @default(false)
+  authorId  Int      @map("AuthorRef")
+  author    User     @relation(fields: [authorId], references: [id], onDelete: Cascade)
+  updatedAt DateTime @updatedAt
+
+  @@unique([authorId, title], name: "authorTitle")
+}
+
+type Address {
+  city String
+}
+`
+
+func buildBlog(t *testing.T) *Document {
+	t.Helper()
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", blogSchema)}
+	doc, diags := FromFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	return doc
+}
+
+func TestBuild(t *testing.T) {
+	doc := buildBlog(t)
+
+	if doc.Version != Version || len(doc.Datasources) != 1 || doc.Datasources[0].Provider != "postgresql" {
+		t.Errorf("version %d, datasources %+v", doc.Version, doc.Datasources)
+	}
+
+	models := make(map[string]Model)
+	for _, model := range doc.Datamodel.Models {
+		models[model.Name] = model
+	}
+	user, post := models["User"], models["Post"]
+	if user.DBName != "Users" || post.DBName != "post" {
+		t.Errorf("table names = %q, %q", user.DBName, post.DBName)
+	}
+	if user.Documentation != "A registered user" {
+		t.Errorf("documentation = %q", user.Documentation)
+	}
+	if !reflect.DeepEqual(user.PrimaryKey, []string{"id"}) {
+		t.Errorf("primary key = %v", user.PrimaryKey)
+	}
+	if !reflect.DeepEqual(post.UniqueFields, [][]string{{"authorId", "title"}}) {
+		t.Errorf("unique fields = %v", post.UniqueFields)
+	}
+	wantIndexes := []UniqueIndex{{Name: "authorTitle", Fields: []string{"authorId", "title"}}}
+	if !reflect.DeepEqual(post.UniqueIndexes, wantIndexes) || len(user.UniqueIndexes) != 0 {
+		t.Errorf("unique indexes = %v, %v", post.UniqueIndexes, user.UniqueIndexes)
+	}
+
+	fields := make(map[string]Field)
+	for _, model := range doc.Datamodel.Models {
+		for _, field := range model.Fields {
+			fields[model.Name+"."+field.Name] = field
+		}
+	}
+
+	id := fields["User.id"]
+	if id.DBName != "UserID" || id.Kind != KindScalar || !id.IsID || !id.HasDefaultValue {
+		t.Errorf("User.id = %+v", id)
+	}
+	if def, ok := id.Default.(FunctionDefault); !ok || def.Name != "autoincrement" {
+		t.Errorf("User.id default = %#v", id.Default)
+	}
+	if email := fields["User.email"]; !email.IsUnique || email.NativeType != "VarChar(255)" {
+		t.Errorf("User.email = %+v", email)
+	}
+	if role := fields["User.role"]; role.Kind != KindEnum || role.Default != "USER" {
+		t.Errorf("User.role = %+v", role)
+	}
+	if tags := fields["User.tags"]; !tags.IsList || !tags.IsRequired {
+		t.Errorf("User.tags = %+v", tags)
+	}
+	if address := fields["User.address"]; address.Kind != KindObject || address.IsRequired || address.RelationName != "" {
+		t.Errorf("User.address = %+v", address)
+	}
+	if title := fields["Post.title"]; title.Default != "untitled" {
+		t.Errorf("Post.title default = %#v", title.Default)
+	}
+	if published := fields["Post.published"]; published.Default != false {
+		t.Errorf("Post.published default = %#v", published.Default)
+	}
+	if !fields["Post.updatedAt"].IsUpdatedAt {
+		t.Error("Post.updatedAt is not @updatedAt")
+	}
+
+	posts, author := fields["User.posts"], fields["Post.author"]
+	if posts.Kind != KindObject || posts.DBName != "" || !posts.IsList {
+		t.Errorf("User.posts = %+v", posts)
+	}
+	if posts.RelationName != "PostToUser" || author.RelationName != posts.RelationName {
+		t.Errorf("relation names = %q, %q", posts.RelationName, author.RelationName)
+	}
+	if !reflect.DeepEqual(author.RelationFromFields, []string{"authorId"}) ||
+		!reflect.DeepEqual(author.RelationToFields, []string{"id"}) ||
+		author.RelationOnDelete != "Cascade" {
+		t.Errorf("Post.author = %+v", author)
+	}
+
+	if len(doc.Datamodel.Enums) != 1 {
+		t.Fatalf("got %d enums", len(doc.Datamodel.Enums))
+	}
+	role := doc.Datamodel.Enums[0]
+	want := []EnumValue{{Name: "ADMIN", DBName: "admin"}, {Name: "USER", DBName: "USER"}}
+	if role.DBName != "roles" || !reflect.DeepEqual(role.Values, want) {
+		t.Errorf("enum = %+v", role)
+	}
+
+	types := doc.Datamodel.Types
+	if len(types) != 1 || types[0].Name != "Address" || len(types[0].Fields) != 1 ||
+		types[0].Fields[0].Kind != KindScalar || types[0].Fields[0].Type != "String" {
+		t.Errorf("types = %+v", types)
+	}
+}
+
+// TestDocumentJSON checks the keys plugins rely on
+func TestDocumentJSON(t *testing.T) {
+	data, err := json.Marshal(buildBlog(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Version   int `json:"version"`
+		Datamodel struct {
+			Models []struct {
+				Name   string `json:"name"`
+				DBName string `json:"dbName"`
+				Fields []struct {
+					Name       string      `json:"name"`
+					Kind       string      `json:"kind"`
+					IsRequired bool        `json:"isRequired"`
+					Default    interface{} `json:"default"`
+				} `json:"fields"`
+			} `json:"models"`
+		} `json:"datamodel"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Version != Version || len(decoded.Datamodel.Models) != 2 {
+		t.Fatalf("decoded %s", data)
+	}
+	id := decoded.Datamodel.Models[0].Fields[0]
+	if id.Name != "id" || id.Kind != KindScalar || !id.IsRequired {
+		t.Errorf("id = %+v", id)
+	}
+	def, _ := id.Default.(map[string]interface{})
+	if def["name"] != "autoincrement" {
+		t.Errorf("default = %#v", id.Default)
+	}
+}
@@ -0,0 +1,116 @@
+// Package dmmf describes a validated Prisma schema as a JSON document, in
+// the spirit of Prisma's DMMF.
+//
+// The document is built from the walkers of the parser database with Build
+// or FromFiles, and printed by `prisma-go generate --dmmf`. It is the
+// contract between prisma-go and generator plugins: a plugin selected by a
+// generator block receives it on stdin and writes its own files. Fields are
+// only ever added to the document; renaming or removing one bumps Version.
+package dmmf
+
+// Version is the version of the document format
+const Version = 1
+
+// Field kinds
+const (
+	KindScalar      = "scalar"
+	KindEnum        = "enum"
+	KindObject      = "object"
+	KindUnsupported = "unsupported"
+)
+
+// Document is the description of a schema passed to generator plugins
+type Document struct {
+	Version     int              `json:"version"`
+	SchemaPath  string           `json:"schemaPath,omitempty"`
+	Generator   *GeneratorConfig `json:"generator,omitempty"`
+	Datasources []Datasource     `json:"datasources"`
+	Datamodel   Datamodel        `json:"datamodel"`
+}
+
+// GeneratorConfig is the generator block a plugin runs for. Output is an
+// absolute directory and environment variables are already resolved.
+type GeneratorConfig struct {
+	Name            string                 `json:"name"`
+	Provider        string                 `json:"provider"`
+	Output          string                 `json:"output"`
+	PreviewFeatures []string               `json:"previewFeatures"`
+	Config          map[string]interface{} `json:"config"`
+}
+
+// Datasource is a datasource block. The connection URL is left out on
+// purpose, generators must not depend on credentials.
+type Datasource struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+}
+
+// Datamodel holds the models, enums and composite types of a schema
+type Datamodel struct {
+	Models []Model `json:"models"`
+	Enums  []Enum  `json:"enums"`
+	Types  []Model `json:"types"`
+}
+
+// Model is a model, view or composite type. PrimaryKey lists the fields of
+// @id or @@id, UniqueFields the fields of each @@unique.
+type Model struct {
+	Name          string        `json:"name"`
+	DBName        string        `json:"dbName"`
+	IsView        bool          `json:"isView"`
+	Documentation string        `json:"documentation,omitempty"`
+	Fields        []Field       `json:"fields"`
+	PrimaryKey    []string      `json:"primaryKey"`
+	UniqueFields  [][]string    `json:"uniqueFields"`
+	UniqueIndexes []UniqueIndex `json:"uniqueIndexes"`
+}
+
+// UniqueIndex is a @@unique criteria with its optional name
+type UniqueIndex struct {
+	Name   string   `json:"name,omitempty"`
+	Fields []string `json:"fields"`
+}
+
+// Field is a field of a model or composite type. DBName is the column the
+// generated client reads and writes, and is empty for relation fields.
+type Field struct {
+	Name               string      `json:"name"`
+	DBName             string      `json:"dbName,omitempty"`
+	Kind               string      `json:"kind"`
+	Type               string      `json:"type"`
+	IsList             bool        `json:"isList"`
+	IsRequired         bool        `json:"isRequired"`
+	IsID               bool        `json:"isId"`
+	IsUnique           bool        `json:"isUnique"`
+	IsUpdatedAt        bool        `json:"isUpdatedAt"`
+	HasDefaultValue    bool        `json:"hasDefaultValue"`
+	Default            interface{} `json:"default,omitempty"`
+	NativeType         string      `json:"nativeType,omitempty"`
+	RelationName       string      `json:"relationName,omitempty"`
+	RelationFromFields []string    `json:"relationFromFields,omitempty"`
+	RelationToFields   []string    `json:"relationToFields,omitempty"`
+	RelationOnDelete   string      `json:"relationOnDelete,omitempty"`
+	RelationOnUpdate   string      `json:"relationOnUpdate,omitempty"`
+	Documentation      string      `json:"documentation,omitempty"`
+}
+
+// FunctionDefault is a default value computed by a function, such as
+// autoincrement() or now()
+type FunctionDefault struct {
+	Name string        `json:"name"`
+	Args []interface{} `json:"args"`
+}
+
+// Enum is an enum and its values
+type Enum struct {
+	Name          string      `json:"name"`
+	DBName        string      `json:"dbName"`
+	Documentation string      `json:"documentation,omitempty"`
+	Values        []EnumValue `json:"values"`
+}
+
+// EnumValue is a value of an enum. DBName is the value stored in the database.
+type EnumValue struct {
+	Name   string `json:"name"`
+	DBName string `json:"dbName"`
+}
@@ -3,23 +3,31 @@ package generator
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/satishbabariya/prisma-go/generator/codegen"
+	"github.com/satishbabariya/prisma-go/generator/dmmf"
 	"github.com/satishbabariya/prisma-go/internal/debug"
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/core"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
 	ast "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
 )
 
 // Generator generates Go client code from schema
 type Generator struct {
 	ast      *ast.SchemaAst
+	files    []core.SourceFile
 	provider string
 }
 
-// NewGenerator creates a new code generator
-func NewGenerator(ast *ast.SchemaAst, provider string) *Generator {
+// NewGenerator creates a new code generator for the schema parsed from files
+func NewGenerator(ast *ast.SchemaAst, files []core.SourceFile, provider string) *Generator {
 	debug.Debug("Creating new generator", "provider", provider)
 	return &Generator{
 		ast:      ast,
+		files:    files,
 		provider: provider,
 	}
 }
@@ -36,9 +44,12 @@ func (g *Generator) GenerateClient(outputDir string) error {
 	}
 	debug.Debug("Schema validation passed")
 
+	// Resolve table, column and enum value names
+	names := g.resolveDatabaseNames()
+
 	// Generate model information from AST
 	debug.Debug("Generating models from AST")
-	models := codegen.GenerateModelsFromAST(g.ast)
+	models := codegen.GenerateModelsFromAST(g.ast, names)
 	debug.Debug("Models generated", "count", len(models))
 
 	if len(models) == 0 {
@@ -54,26 +65,117 @@ func (g *Generator) GenerateClient(outputDir string) error {
 	}
 	debug.Debug("Model validation passed")
 
-	// Generate models.go
-	debug.Debug("Generating models.go file", "outputDir", outputDir)
-	if err := codegen.GenerateModelsFile(g.ast, models, outputDir); err != nil {
-		debug.Error("Failed to generate models file", "error", err)
-		return fmt.Errorf("failed to generate models: %w", err)
-	}
-	debug.Debug("models.go generated successfully")
-
-	// Generate client.go
-	debug.Debug("Generating client.go file", "outputDir", outputDir)
-	if err := codegen.GenerateClientFile(models, g.provider, outputDir); err != nil {
-		debug.Error("Failed to generate client file", "error", err)
+	// Generate client.go, enums.go, validate.go and a file per model
+	debug.Debug("Generating client files", "outputDir", outputDir)
+	files, err := codegen.GenerateClientFiles(g.ast, models, names, g.provider, outputDir)
+	if err != nil {
+		debug.Error("Failed to generate client files", "error", err)
 		return fmt.Errorf("failed to generate client: %w", err)
 	}
-	debug.Debug("client.go generated successfully")
+	debug.Debug("Client files generated", "written", files.Written, "removed", files.Removed)
 	debug.Info("Client generation completed", "outputDir", outputDir, "models", len(models))
 
 	return nil
 }
 
+// GenerateGraphQL generates schema.graphql and its resolvers into the
+// directory of a client generated with GenerateClient
+func (g *Generator) GenerateGraphQL(outputDir string) error {
+	debug.Debug("Starting GraphQL generation", "outputDir", outputDir)
+
+	if err := g.validateSchema(); err != nil {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+	models := codegen.GenerateModelsFromAST(g.ast, g.resolveDatabaseNames())
+	if len(models) == 0 {
+		return fmt.Errorf("no models found in schema")
+	}
+
+	if err := codegen.GenerateGraphQLFiles(g.ast, models, outputDir); err != nil {
+		debug.Error("Failed to generate GraphQL files", "error", err)
+		return fmt.Errorf("failed to generate GraphQL schema: %w", err)
+	}
+	debug.Info("GraphQL generation completed", "outputDir", outputDir)
+
+	return nil
+}
+
+// GenerateREST generates openapi.json and the REST handlers serving it into
+// the directory of a client generated with GenerateClient
+func (g *Generator) GenerateREST(outputDir string) error {
+	debug.Debug("Starting REST generation", "outputDir", outputDir)
+
+	if err := g.validateSchema(); err != nil {
+		return fmt.Errorf("schema validation failed: %w", err)
+	}
+	names := g.resolveDatabaseNames()
+	models := codegen.GenerateModelsFromAST(g.ast, names)
+	if len(models) == 0 {
+		return fmt.Errorf("no models found in schema")
+	}
+
+	if err := codegen.GenerateRESTFiles(g.ast, models, names, g.provider, outputDir); err != nil {
+		debug.Error("Failed to generate REST files", "error", err)
+		return fmt.Errorf("failed to generate REST handlers: %w", err)
+	}
+	debug.Info("REST generation completed", "outputDir", outputDir)
+
+	return nil
+}
+
+// Document describes the schema as the JSON document generator plugins
+// receive. The schema must be free of parser database errors.
+func (g *Generator) Document() (*dmmf.Document, error) {
+	if err := g.validateSchema(); err != nil {
+		return nil, fmt.Errorf("schema validation failed: %w", err)
+	}
+	if len(g.files) == 0 {
+		return nil, fmt.Errorf("no schema files to describe")
+	}
+	doc, diags := dmmf.FromFiles(g.files)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("schema validation failed:\n%s", psl.RenderDiagnostics(g.files, diags))
+	}
+	return doc, nil
+}
+
+// RunPlugin runs a plugin for the generator block described by config. The
+// output directory is created before the plugin runs.
+func (g *Generator) RunPlugin(plugin Plugin, config dmmf.GeneratorConfig, schemaPath string) error {
+	debug.Debug("Starting plugin generation", "generator", config.Name, "provider", config.Provider, "outputDir", config.Output)
+
+	doc, err := g.Document()
+	if err != nil {
+		return err
+	}
+	doc.SchemaPath = schemaPath
+	doc.Generator = &config
+
+	if err := os.MkdirAll(config.Output, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := plugin.Generate(doc); err != nil {
+		debug.Error("Generator plugin failed", "generator", config.Name, "error", err)
+		return fmt.Errorf("generator %s failed: %w", config.Name, err)
+	}
+	debug.Info("Plugin generation completed", "generator", config.Name, "outputDir", config.Output)
+
+	return nil
+}
+
+// resolveDatabaseNames resolves the database names of the schema from the
+// parser database, so @map and @@map are honoured the same way everywhere
+func (g *Generator) resolveDatabaseNames() *codegen.DatabaseNames {
+	if len(g.files) == 0 {
+		return nil
+	}
+	// Schema errors are reported by validate; generation only needs names
+	diags := diagnostics.NewDiagnostics()
+	db := database.NewParserDatabase(g.files, &diags, database.NoExtensionTypes{})
+	debug.Debug("Parser database built", "errors", len(diags.Errors()))
+	return codegen.ResolveDatabaseNames(db)
+}
+
 // validateSchema performs basic validation on the schema AST
 func (g *Generator) validateSchema() error {
 	// Check that we have at least one model
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/satishbabariya/prisma-go/generator/dmmf"
+	"github.com/satishbabariya/prisma-go/internal/debug"
+)
+
+// clientProviders select the built-in Go client. prisma-client-js is
+// accepted so schemas shared with the Prisma CLI generate the client too.
+var clientProviders = map[string]bool{
+	"prisma-client-go": true,
+	"prisma-client-js": true,
+}
+
+// Plugin generates files from the document of a validated schema into
+// doc.Generator.Output. Plugins are selected by the provider of a generator
+// block.
+type Plugin interface {
+	Generate(doc *dmmf.Document) error
+}
+
+// PluginFunc adapts a function to the Plugin interface
+type PluginFunc func(doc *dmmf.Document) error
+
+// Generate calls f(doc)
+func (f PluginFunc) Generate(doc *dmmf.Document) error {
+	return f(doc)
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   = make(map[string]Plugin)
+)
+
+// RegisterPlugin makes a plugin available to generator blocks with the
+// given provider. It is meant to be called from init functions and panics
+// if the provider is taken.
+func RegisterPlugin(provider string, plugin Plugin) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+
+	if plugin == nil {
+		panic("generator: RegisterPlugin plugin is nil")
+	}
+	if _, dup := plugins[provider]; dup || clientProviders[provider] {
+		panic("generator: RegisterPlugin called twice for provider " + provider)
+	}
+	plugins[provider] = plugin
+}
+
+// Plugins returns the sorted providers of the registered plugins
+func Plugins() []string {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+
+	providers := make([]string, 0, len(plugins))
+	for provider := range plugins {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// IsClientProvider reports whether a generator block with the provider
+// generates the built-in Go client
+func IsClientProvider(provider string) bool {
+	return clientProviders[provider]
+}
+
+// ResolvePlugin returns the plugin of a provider: a registered plugin, or
+// else an executable. The provider of an executable is a command line, a
+// relative program path is resolved against dir, the directory of the
+// schema file declaring the generator.
+func ResolvePlugin(provider, dir string) (Plugin, error) {
+	pluginsMu.RLock()
+	plugin, ok := plugins[provider]
+	pluginsMu.RUnlock()
+	if ok {
+		return plugin, nil
+	}
+
+	args := strings.Fields(provider)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("generator provider is empty")
+	}
+	program := args[0]
+	if strings.ContainsRune(program, '/') || strings.ContainsRune(program, filepath.Separator) {
+		if !filepath.IsAbs(program) {
+			// An absolute path keeps LookPath from searching PATH
+			abs, err := filepath.Abs(filepath.Join(dir, program))
+			if err != nil {
+				return nil, err
+			}
+			program = abs
+		}
+	}
+	path, err := exec.LookPath(program)
+	if err != nil {
+		registered := "none"
+		if providers := Plugins(); len(providers) > 0 {
+			registered = strings.Join(providers, ", ")
+		}
+		return nil, fmt.Errorf("unknown generator provider %q: no registered plugin (registered: %s) and no executable: %w",
+			provider, registered, err)
+	}
+	return &ExecPlugin{Path: path, Args: args[1:], Dir: dir}, nil
+}
+
+// ExecPlugin runs an executable as plugin. The executable reads the
+// document as JSON from stdin, writes its files and exits with status 0.
+// Its stdout is passed through, its stderr is included in the error on
+// failure.
+type ExecPlugin struct {
+	Path string   // Program to run
+	Args []string // Arguments after the program
+	Dir  string   // Working directory
+}
+
+// Generate runs the executable with the document on stdin
+func (p *ExecPlugin) Generate(doc *dmmf.Document) error {
+	input, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode schema document: %w", err)
+	}
+
+	debug.Debug("Running generator plugin", "path", p.Path, "args", p.Args, "dir", p.Dir)
+	cmd := exec.Command(p.Path, p.Args...)
+	cmd.Dir = p.Dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = os.Stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", filepath.Base(p.Path), err, msg)
+		}
+		return fmt.Errorf("%s: %w", filepath.Base(p.Path), err)
+	}
+	return nil
+}
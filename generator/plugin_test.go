@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/generator/dmmf"
+	"github.com/satishbabariya/prisma-go/psl"
+)
+
+const pluginSchema = `
+datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+
+model User {
+  id    Int    @id @default(autoincrement())
+  email String @unique
+}
+`
+
+// TestMain lets the test binary act as an executable plugin, which writes
+// the names of the models it receives to models.txt
+func TestMain(m *testing.M) {
+	if os.Getenv("PRISMA_GO_TEST_PLUGIN") == "1" {
+		var doc dmmf.Document
+		if err := json.NewDecoder(os.Stdin).Decode(&doc); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if doc.Generator.Config["fail"] == "true" {
+			fmt.Fprintln(os.Stderr, "failing as configured")
+			os.Exit(2)
+		}
+		var models []string
+		for _, model := range doc.Datamodel.Models {
+			models = append(models, model.Name+":"+model.DBName)
+		}
+		path := filepath.Join(doc.Generator.Output, "models.txt")
+		if err := os.WriteFile(path, []byte(strings.Join(models, "\n")), 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func newPluginGenerator(t *testing.T) *Generator {
+	t.Helper()
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", pluginSchema)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	return NewGenerator(schemaAST, files, "sqlite")
+}
+
+func TestExecPlugin(t *testing.T) {
+	t.Setenv("PRISMA_GO_TEST_PLUGIN", "1")
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := ResolvePlugin(exe, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gen := newPluginGenerator(t)
+	output := filepath.Join(t.TempDir(), "out")
+	config := dmmf.GeneratorConfig{Name: "models", Provider: exe, Output: output, Config: map[string]interface{}{}}
+	if err := gen.RunPlugin(plugin, config, "schema.prisma"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(output, "models.txt"))
+	if err != nil || string(data) != "User:user" {
+		t.Errorf("models.txt = %q, %v", data, err)
+	}
+
+	config.Config["fail"] = "true"
+	err = gen.RunPlugin(plugin, config, "schema.prisma")
+	if err == nil || !strings.Contains(err.Error(), "failing as configured") {
+		t.Errorf("expected plugin failure with its stderr, got %v", err)
+	}
+}
+
+func TestRegisteredPlugin(t *testing.T) {
+	var got *dmmf.Document
+	RegisterPlugin("prisma-go-test", PluginFunc(func(doc *dmmf.Document) error {
+		got = doc
+		return nil
+	}))
+
+	plugin, err := ResolvePlugin("prisma-go-test", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := dmmf.GeneratorConfig{Name: "test", Provider: "prisma-go-test", Output: t.TempDir()}
+	if err := newPluginGenerator(t).RunPlugin(plugin, config, "schema.prisma"); err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Generator.Name != "test" || got.SchemaPath != "schema.prisma" || len(got.Datamodel.Models) != 1 {
+		t.Errorf("plugin received %+v", got)
+	}
+
+	if _, err := ResolvePlugin("prisma-go-no-such-plugin", "."); err == nil ||
+		!strings.Contains(err.Error(), "prisma-go-test") {
+		t.Errorf("expected unknown provider error listing registered plugins, got %v", err)
+	}
+	if !IsClientProvider("prisma-client-go") || IsClientProvider("prisma-go-test") {
+		t.Error("IsClientProvider mismatch")
+	}
+}
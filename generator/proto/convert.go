@@ -0,0 +1,371 @@
+package proto
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/generator/codegen"
+	"github.com/satishbabariya/prisma-go/generator/dmmf"
+)
+
+// convertWriter renders the Go conversions between the model structs of
+// the generated client, imported as "models", and the protobuf messages
+type convertWriter struct {
+	types    map[string]bool // Composite types, which have no model struct
+	body     strings.Builder
+	usesJSON bool
+}
+
+// renderConversions renders ConvertFile. Imports are picked by the
+// packages the functions use.
+func renderConversions(doc *dmmf.Document, opts Options) ([]byte, error) {
+	w := &convertWriter{types: make(map[string]bool)}
+	for _, t := range doc.Datamodel.Types {
+		w.types[t.Name] = true
+	}
+
+	for _, enum := range doc.Datamodel.Enums {
+		w.enum(enum)
+	}
+	for _, model := range doc.Datamodel.Models {
+		w.toProto(model)
+		w.fromProto(model)
+	}
+	if w.usesJSON {
+		w.body.WriteString(jsonHelpers)
+	}
+
+	body := w.body.String()
+	var out strings.Builder
+	out.WriteString("// Code generated by prisma-go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", goPackageName(opts.GoPackage))
+	for _, imp := range []struct{ name, path string }{
+		{"json", "encoding/json"},
+		{"fmt", "fmt"},
+		{"", ""},
+		{"types", "github.com/satishbabariya/prisma-go/runtime/types"},
+		{"structpb", "google.golang.org/protobuf/types/known/structpb"},
+		{"timestamppb", "google.golang.org/protobuf/types/known/timestamppb"},
+	} {
+		if imp.path == "" {
+			out.WriteString("\n")
+		} else if strings.Contains(body, imp.name+".") {
+			fmt.Fprintf(&out, "\t%q\n", imp.path)
+		}
+	}
+	fmt.Fprintf(&out, "\tmodels %q\n)\n", opts.ModelsPackage)
+	out.WriteString(body)
+
+	src, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid generated conversions: %w", Provider, err)
+	}
+	return src, nil
+}
+
+func (w *convertWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&w.body, format, args...)
+}
+
+// enum renders the conversions of an enum. The unspecified value has no
+// model value and fails to convert.
+func (w *convertWriter) enum(enum dmmf.Enum) {
+	name, pb := enum.Name, goCamelCase(enum.Name)
+
+	w.printf("\n// %sToProto converts a %s of the generated client to its protobuf enum\n", name, name)
+	w.printf("func %sToProto(v models.%s) %s {\n\tswitch v {\n", name, name, pb)
+	for _, value := range enum.Values {
+		w.printf("\tcase models.%s%s:\n\t\treturn %s_%s\n", name, value.Name, pb, enumValueName(enum.Name, value.Name))
+	}
+	w.printf("\t}\n\treturn %s_%s\n}\n", pb, enumValueName(enum.Name, "UNSPECIFIED"))
+
+	w.printf("\n// %sFromProto converts a protobuf enum to a %s of the generated client\n", name, name)
+	w.printf("func %sFromProto(v %s) (models.%s, error) {\n\tswitch v {\n", name, pb, name)
+	for _, value := range enum.Values {
+		w.printf("\tcase %s_%s:\n\t\treturn models.%s%s, nil\n", pb, enumValueName(enum.Name, value.Name), name, value.Name)
+	}
+	w.printf("\t}\n\treturn \"\", fmt.Errorf(\"invalid %s %%v\", v)\n}\n", name)
+}
+
+// names returns the Go names of a field in the model struct and the
+// protobuf message
+func names(field dmmf.Field) (string, string) {
+	return codegen.GoFieldName(field.Name), goCamelCase(snakeCase(field.Name))
+}
+
+// converted reports whether a field is part of the conversions
+func (w *convertWriter) converted(field dmmf.Field) bool {
+	switch field.Kind {
+	case dmmf.KindEnum:
+		return true
+	case dmmf.KindObject:
+		return !w.types[field.Type]
+	case dmmf.KindScalar:
+		_, ok := scalarTypes[field.Type]
+		return ok
+	}
+	return false
+}
+
+func (w *convertWriter) toProto(model dmmf.Model) {
+	name, pb := model.Name, goCamelCase(model.Name)
+	w.printf("\n// %sToProto converts a %s of the generated client to its message\n", name, name)
+	w.printf("func %sToProto(m *models.%s) (*%s, error) {\n", name, name, pb)
+	w.printf("\tif m == nil {\n\t\treturn nil, nil\n\t}\n")
+	w.printf("\tp := &%s{}\n", pb)
+	if w.hasJSON(model) {
+		w.printf("\tvar err error\n")
+	}
+	for _, field := range model.Fields {
+		if w.converted(field) {
+			w.fieldToProto(model.Name, field)
+		}
+	}
+	w.printf("\treturn p, nil\n}\n")
+}
+
+func (w *convertWriter) fromProto(model dmmf.Model) {
+	name, pb := model.Name, goCamelCase(model.Name)
+	w.printf("\n// %sFromProto converts a message to a %s of the generated client\n", name, name)
+	w.printf("func %sFromProto(p *%s) (*models.%s, error) {\n", name, pb, name)
+	w.printf("\tif p == nil {\n\t\treturn nil, nil\n\t}\n")
+	w.printf("\tm := &models.%s{}\n", name)
+	if w.hasJSON(model) {
+		w.printf("\tvar err error\n")
+	}
+	for _, field := range model.Fields {
+		if w.converted(field) {
+			w.fieldFromProto(model.Name, field)
+		}
+	}
+	w.printf("\treturn m, nil\n}\n")
+}
+
+func (w *convertWriter) hasJSON(model dmmf.Model) bool {
+	for _, field := range model.Fields {
+		if field.Kind == dmmf.KindScalar && field.Type == "Json" {
+			w.usesJSON = true
+			return true
+		}
+	}
+	return false
+}
+
+// fail returns the statement returning a conversion error of a field
+func fail(model string, field dmmf.Field) string {
+	return fmt.Sprintf("return nil, fmt.Errorf(\"%s.%s: %%w\", err)", model, field.Name)
+}
+
+// fieldToProto renders the assignment of a message field from a model
+// field. Model enums and single relations are pointers, as are optional
+// scalars.
+func (w *convertWriter) fieldToProto(model string, field dmmf.Field) {
+	mf, pf := names(field)
+	src, dst := "m."+mf, "p."+pf
+	optional := !field.IsRequired && !field.IsList
+
+	switch {
+	case field.Kind == dmmf.KindEnum:
+		if field.IsList {
+			w.printf("\tfor _, v := range %s {\n\t\t%s = append(%s, %sToProto(v))\n\t}\n", src, dst, dst, field.Type)
+		} else if optional {
+			w.printf("\tif %s != nil {\n\t\t%s = %sToProto(*%s).Enum()\n\t}\n", src, dst, field.Type, src)
+		} else {
+			w.printf("\tif %s != nil {\n\t\t%s = %sToProto(*%s)\n\t}\n", src, dst, field.Type, src)
+		}
+
+	case field.Kind == dmmf.KindObject:
+		if field.IsList {
+			w.printf("\tfor i := range %s {\n\t\tv, err := %sToProto(&%s[i])\n\t\tif err != nil {\n\t\t\t%s\n\t\t}\n\t\t%s = append(%s, v)\n\t}\n",
+				src, field.Type, src, fail(model, field), dst, dst)
+		} else {
+			w.printf("\tif %s != nil {\n\t\tv, err := %sToProto(%s)\n\t\tif err != nil {\n\t\t\t%s\n\t\t}\n\t\t%s = v\n\t}\n",
+				src, field.Type, src, fail(model, field), dst)
+		}
+
+	case field.Type == "Json":
+		helper := "jsonToStruct"
+		if field.IsList {
+			helper = "jsonToStructs"
+		}
+		w.printf("\tif %s, err = %s(%s); err != nil {\n\t\t%s\n\t}\n", dst, helper, src, fail(model, field))
+
+	default:
+		w.scalar(model, src, dst, field, scalarToProto[field.Type])
+	}
+}
+
+// fieldFromProto renders the assignment of a model field from a message
+// field. Unset messages and enums leave the model field unset.
+func (w *convertWriter) fieldFromProto(model string, field dmmf.Field) {
+	mf, pf := names(field)
+	src, dst := "p."+pf, "m."+mf
+	optional := !field.IsRequired && !field.IsList
+	pb := goCamelCase(field.Type)
+
+	switch {
+	case field.Kind == dmmf.KindEnum:
+		if field.IsList {
+			w.printf("\tfor _, e := range %s {\n\t\tv, err := %sFromProto(e)\n\t\tif err != nil {\n\t\t\t%s\n\t\t}\n\t\t%s = append(%s, v)\n\t}\n",
+				src, field.Type, fail(model, field), dst, dst)
+		} else if optional {
+			w.printf("\tif %s != nil {\n\t\tv, err := %sFromProto(*%s)\n\t\tif err != nil {\n\t\t\t%s\n\t\t}\n\t\t%s = &v\n\t}\n",
+				src, field.Type, src, fail(model, field), dst)
+		} else {
+			w.printf("\tif %s != %s_%s {\n\t\tv, err := %sFromProto(%s)\n\t\tif err != nil {\n\t\t\t%s\n\t\t}\n\t\t%s = &v\n\t}\n",
+				src, pb, enumValueName(field.Type, "UNSPECIFIED"), field.Type, src, fail(model, field), dst)
+		}
+
+	case field.Kind == dmmf.KindObject:
+		if field.IsList {
+			w.printf("\tfor _, e := range %s {\n\t\tv, err := %sFromProto(e)\n\t\tif err != nil {\n\t\t\t%s\n\t\t}\n\t\t%s = append(%s, *v)\n\t}\n",
+				src, field.Type, fail(model, field), dst, dst)
+		} else {
+			w.printf("\tif %s != nil {\n\t\tv, err := %sFromProto(%s)\n\t\tif err != nil {\n\t\t\t%s\n\t\t}\n\t\t%s = v\n\t}\n",
+				src, field.Type, src, fail(model, field), dst)
+		}
+
+	case field.Type == "Json":
+		helper := "structToJSON"
+		if field.IsList {
+			helper = "structsToJSON"
+		}
+		w.printf("\tif err = %s(%s, &%s); err != nil {\n\t\t%s\n\t}\n", helper, src, dst, fail(model, field))
+
+	default:
+		w.scalar(model, src, dst, field, scalarFromProto[field.Type])
+	}
+}
+
+// scalarConversion converts a scalar value between its model and message
+// types
+type scalarConversion struct {
+	expr     string // Expression converting the value %s
+	fallible bool   // expr returns an error as well
+	isSet    string // Condition on a required value %s to convert it
+	pointer  bool   // expr returns a pointer, which optional fields take as is
+}
+
+var scalarToProto = map[string]scalarConversion{
+	"Int":      {expr: "int32(%s)"},
+	"Decimal":  {expr: "%s.String()"},
+	"DateTime": {expr: "timestamppb.New(%s)", pointer: true},
+}
+
+var scalarFromProto = map[string]scalarConversion{
+	"Int":      {expr: "int(%s)"},
+	"Decimal":  {expr: "types.ParseDecimal(%s)", fallible: true, isSet: `%s != ""`},
+	"DateTime": {expr: "%s.AsTime()", isSet: "%s != nil"},
+}
+
+// scalar renders the conversion of a scalar field. Types without
+// conversion are assigned as they are, except optional bytes, which
+// protobuf keeps in a plain slice.
+func (w *convertWriter) scalar(model string, src, dst string, field dmmf.Field, c scalarConversion) {
+	if c.expr == "" {
+		if field.Type == "Bytes" && !field.IsRequired && !field.IsList {
+			if strings.HasPrefix(src, "m.") {
+				w.printf("\tif %s != nil {\n\t\t%s = *%s\n\t}\n", src, dst, src)
+			} else {
+				w.printf("\tif %s != nil {\n\t\tv := %s\n\t\t%s = &v\n\t}\n", src, src, dst)
+			}
+			return
+		}
+		w.printf("\t%s = %s\n", dst, src)
+		return
+	}
+
+	convert := func(value, target string) {
+		if c.fallible {
+			w.printf("\t\tv, err := %s\n\t\tif err != nil {\n\t\t\t%s\n\t\t}\n", fmt.Sprintf(c.expr, value), fail(model, field))
+		} else {
+			w.printf("\t\tv := %s\n", fmt.Sprintf(c.expr, value))
+		}
+		w.printf("\t\t%s\n\t}\n", target)
+	}
+	switch {
+	case field.IsList:
+		w.printf("\tfor _, e := range %s {\n", src)
+		convert("e", fmt.Sprintf("%s = append(%s, v)", dst, dst))
+	case !field.IsRequired:
+		// Methods are called on the pointer, other conversions take the value
+		value := "*" + src
+		if strings.HasPrefix(c.expr, "%s.") {
+			value = src
+		}
+		target := dst + " = &v"
+		if c.pointer {
+			target = dst + " = v"
+		}
+		w.printf("\tif %s != nil {\n", src)
+		convert(value, target)
+	case c.isSet != "":
+		w.printf("\tif %s {\n", fmt.Sprintf(c.isSet, src))
+		convert(src, dst+" = v")
+	default:
+		w.printf("\t%s = %s\n", dst, fmt.Sprintf(c.expr, src))
+	}
+}
+
+// jsonHelpers convert Json values, whatever their Go type, through their
+// JSON encoding
+const jsonHelpers = `
+func jsonToStruct(v interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("Json value is not an object: %w", err)
+	}
+	return structpb.NewStruct(fields)
+}
+
+func jsonToStructs(v interface{}) ([]*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var list []map[string]interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("Json values are not objects: %w", err)
+	}
+	structs := make([]*structpb.Struct, 0, len(list))
+	for _, fields := range list {
+		s, err := structpb.NewStruct(fields)
+		if err != nil {
+			return nil, err
+		}
+		structs = append(structs, s)
+	}
+	return structs, nil
+}
+
+func structToJSON(s *structpb.Struct, v interface{}) error {
+	if s == nil {
+		return nil
+	}
+	data, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func structsToJSON(structs []*structpb.Struct, v interface{}) error {
+	if structs == nil {
+		return nil
+	}
+	list := make([]map[string]interface{}, len(structs))
+	for i, s := range structs {
+		list[i] = s.AsMap()
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+`
@@ -0,0 +1,162 @@
+package proto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// LockFile is the file next to the generated .proto recording the number
+// of every field and enum value. It is meant to be committed: numbers stay
+// the same when fields are reordered or added, and the numbers of removed
+// fields are reserved so they are never reused. A renamed field is a
+// removed field plus a new one.
+const LockFile = "prisma-proto.lock.json"
+
+const lockVersion = 1
+
+// Protobuf reserves field numbers 19000 to 19999 for its implementation
+const (
+	firstReservedNumber = 19000
+	lastReservedNumber  = 19999
+)
+
+// lock is the content of LockFile. Messages and enums are keyed by their
+// protobuf name, their numbers by the protobuf name of the field or value.
+type lock struct {
+	Version  int                   `json:"version"`
+	Messages map[string]*numbering `json:"messages"`
+	Enums    map[string]*numbering `json:"enums"`
+
+	used map[string]bool // Messages and enums of this run
+}
+
+// numbering holds the numbers of one message or enum
+type numbering struct {
+	Numbers       map[string]int `json:"numbers"`
+	Reserved      []int          `json:"reserved,omitempty"`
+	ReservedNames []string       `json:"reservedNames,omitempty"`
+}
+
+// readLock reads a lock file, a missing file is an empty lock
+func readLock(path string) (*lock, error) {
+	l := &lock{
+		Version:  lockVersion,
+		Messages: make(map[string]*numbering),
+		Enums:    make(map[string]*numbering),
+		used:     make(map[string]bool),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if l.Version != lockVersion {
+		return nil, fmt.Errorf("%s has version %d, expected %d", path, l.Version, lockVersion)
+	}
+	if l.Messages == nil {
+		l.Messages = make(map[string]*numbering)
+	}
+	if l.Enums == nil {
+		l.Enums = make(map[string]*numbering)
+	}
+	return l, nil
+}
+
+// write writes the lock, leaving out the messages and enums that were not
+// generated this time
+func (l *lock) write(path string) error {
+	for name := range l.Messages {
+		if !l.used["message "+name] {
+			delete(l.Messages, name)
+		}
+	}
+	for name := range l.Enums {
+		if !l.used["enum "+name] {
+			delete(l.Enums, name)
+		}
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// message numbers the fields of a message
+func (l *lock) message(name string, fields []string) *numbering {
+	l.used["message "+name] = true
+	return assign(l.Messages, name, fields)
+}
+
+// enum numbers the values of an enum, 0 is left for the unspecified value
+func (l *lock) enum(name string, values []string) *numbering {
+	l.used["enum "+name] = true
+	return assign(l.Enums, name, values)
+}
+
+// assign keeps the numbers of known names, gives new names the numbers
+// after the highest number ever used and reserves the numbers and names of
+// names that are gone
+func assign(entries map[string]*numbering, key string, names []string) *numbering {
+	n := entries[key]
+	if n == nil {
+		n = &numbering{}
+		entries[key] = n
+	}
+	if n.Numbers == nil {
+		n.Numbers = make(map[string]int)
+	}
+
+	present := make(map[string]bool, len(names))
+	for _, name := range names {
+		present[name] = true
+	}
+	for name, number := range n.Numbers {
+		if !present[name] {
+			n.Reserved = append(n.Reserved, number)
+			n.ReservedNames = append(n.ReservedNames, name)
+			delete(n.Numbers, name)
+		}
+	}
+	// A name that comes back is a new field with a new number
+	reservedNames := n.ReservedNames[:0]
+	for _, name := range n.ReservedNames {
+		if !present[name] {
+			reservedNames = append(reservedNames, name)
+		}
+	}
+	n.ReservedNames = reservedNames
+	sort.Ints(n.Reserved)
+	sort.Strings(n.ReservedNames)
+
+	next := 1
+	for _, number := range n.Numbers {
+		if number >= next {
+			next = number + 1
+		}
+	}
+	for _, number := range n.Reserved {
+		if number >= next {
+			next = number + 1
+		}
+	}
+	for _, name := range names {
+		if _, ok := n.Numbers[name]; ok {
+			continue
+		}
+		if next >= firstReservedNumber && next <= lastReservedNumber {
+			next = lastReservedNumber + 1
+		}
+		n.Numbers[name] = next
+		next++
+	}
+	return n
+}
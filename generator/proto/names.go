@@ -0,0 +1,84 @@
+package proto
+
+import (
+	"strings"
+	"unicode"
+)
+
+// snakeCase converts a schema name to the lower_snake_case of protobuf
+// field names, keeping acronyms together: "userID" -> "user_id",
+// "HTTPServer" -> "http_server"
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// enumValueName returns the protobuf name of an enum value. Enum values
+// share the scope of their package, so they are prefixed with the enum:
+// Role.ADMIN -> ROLE_ADMIN.
+func enumValueName(enum, value string) string {
+	return strings.ToUpper(snakeCase(enum) + "_" + snakeCase(value))
+}
+
+// goCamelCase returns the Go name protoc-gen-go derives from a protobuf
+// name, following google.golang.org/protobuf/internal/strs.GoCamelCase
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skip over '.' in ".{{lowercase}}"
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skip over '_' in "_{{lowercase}}"
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// goPackageName returns the package name of a go_package option, either
+// the name after ';' or the last element of the import path
+func goPackageName(goPackage string) string {
+	if i := strings.IndexByte(goPackage, ';'); i >= 0 {
+		return goPackage[i+1:]
+	}
+	name := goPackage[strings.LastIndexByte(goPackage, '/')+1:]
+	name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	if name == "" || isASCIIDigit(name[0]) {
+		name = "pb" + name
+	}
+	return name
+}
@@ -0,0 +1,367 @@
+// Package proto generates Protocol Buffers definitions from a schema. It
+// registers the generator plugin "prisma-go-proto":
+//
+//	generator proto {
+//	  provider      = "prisma-go-proto"
+//	  output        = "./proto"
+//	  package       = "blog.v1"
+//	  goPackage     = "github.com/acme/blog/proto;blogpb"
+//	  services      = true
+//	  modelsPackage = "github.com/acme/blog/generated"
+//	}
+//
+// The plugin writes schema.proto with a message for each model and
+// composite type and an enum for each enum. DateTime fields are
+// google.protobuf.Timestamp, Json fields google.protobuf.Struct and Decimal
+// fields strings. Field numbers are kept stable in LockFile.
+//
+// With services, each model with a primary key gets a service with Get,
+// List, Create, Update and Delete methods. With modelsPackage, the import
+// path of the generated client, convert.go holds functions converting
+// between the model structs and the messages compiled by protoc-gen-go into
+// the goPackage. Fields of composite types have no struct in the client
+// and are left out of the conversions.
+package proto
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/generator"
+	"github.com/satishbabariya/prisma-go/generator/codegen"
+	"github.com/satishbabariya/prisma-go/generator/dmmf"
+)
+
+// Provider selects this plugin in a generator block
+const Provider = "prisma-go-proto"
+
+// ProtoFile is the name of the generated .proto file
+const ProtoFile = "schema.proto"
+
+// ConvertFile is the name of the generated Go conversions
+const ConvertFile = "convert.go"
+
+func init() {
+	generator.RegisterPlugin(Provider, generator.PluginFunc(Generate))
+}
+
+// Options are the settings of the generator block
+type Options struct {
+	Package       string // Protobuf package, "prisma" by default
+	GoPackage     string // go_package option
+	Services      bool   // Generate a CRUD service per model
+	ModelsPackage string // Import path of the generated client
+}
+
+// ParseOptions reads the options from the config of a generator block
+func ParseOptions(config map[string]interface{}) (Options, error) {
+	opts := Options{Package: "prisma"}
+	for key, value := range config {
+		switch key {
+		case "package", "goPackage", "modelsPackage":
+			s, ok := value.(string)
+			if !ok {
+				return opts, fmt.Errorf("%s must be a string", key)
+			}
+			switch key {
+			case "package":
+				opts.Package = s
+			case "goPackage":
+				opts.GoPackage = s
+			case "modelsPackage":
+				opts.ModelsPackage = s
+			}
+		case "services":
+			switch v := value.(type) {
+			case bool:
+				opts.Services = v
+			case string:
+				opts.Services = v == "true"
+			default:
+				return opts, fmt.Errorf("services must be true or false")
+			}
+		}
+	}
+	if opts.ModelsPackage != "" && opts.GoPackage == "" {
+		return opts, fmt.Errorf("modelsPackage requires goPackage, the package convert.go belongs to")
+	}
+	return opts, nil
+}
+
+// Generate writes ProtoFile, LockFile and, with a models package,
+// ConvertFile into the output directory of the generator
+func Generate(doc *dmmf.Document) error {
+	if doc.Generator == nil {
+		return fmt.Errorf("%s: document has no generator", Provider)
+	}
+	opts, err := ParseOptions(doc.Generator.Config)
+	if err != nil {
+		return fmt.Errorf("%s: %w", Provider, err)
+	}
+
+	output := doc.Generator.Output
+	lockPath := filepath.Join(output, LockFile)
+	l, err := readLock(lockPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(output, ProtoFile), renderProto(doc, opts, l), 0644); err != nil {
+		return err
+	}
+	if opts.ModelsPackage != "" {
+		src, err := renderConversions(doc, opts)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(output, ConvertFile), src, 0644); err != nil {
+			return err
+		}
+	}
+	return l.write(lockPath)
+}
+
+// protoField is a field of a generated message
+type protoField struct {
+	name  string // lower_snake_case name
+	typ   string // Protobuf type
+	label string // "", "optional " or "repeated "
+	doc   string
+}
+
+// protoWriter renders the .proto file
+type protoWriter struct {
+	lock    *lock
+	imports map[string]bool
+	body    strings.Builder
+}
+
+// renderProto renders the .proto file of a schema, numbering fields with
+// the lock and recording new numbers in it
+func renderProto(doc *dmmf.Document, opts Options, l *lock) []byte {
+	w := &protoWriter{
+		lock:    l,
+		imports: make(map[string]bool),
+	}
+
+	for _, enum := range doc.Datamodel.Enums {
+		w.enum(enum)
+	}
+	for _, model := range doc.Datamodel.Models {
+		w.message(model.Name, model.Documentation, w.fields(model.Fields))
+	}
+	for _, t := range doc.Datamodel.Types {
+		w.message(t.Name, t.Documentation, w.fields(t.Fields))
+	}
+	if opts.Services {
+		for _, model := range doc.Datamodel.Models {
+			if !model.IsView && len(model.PrimaryKey) > 0 {
+				w.service(model)
+			}
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString("// Code generated by prisma-go. DO NOT EDIT.\n")
+	out.WriteString("// Field numbers are kept in " + LockFile + ".\n\n")
+	out.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&out, "package %s;\n", opts.Package)
+	if len(w.imports) > 0 {
+		imports := make([]string, 0, len(w.imports))
+		for imp := range w.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		out.WriteString("\n")
+		for _, imp := range imports {
+			fmt.Fprintf(&out, "import %q;\n", imp)
+		}
+	}
+	if opts.GoPackage != "" {
+		fmt.Fprintf(&out, "\noption go_package = %q;\n", opts.GoPackage)
+	}
+	out.WriteString(w.body.String())
+	return []byte(out.String())
+}
+
+// fields returns the message fields of model fields, leaving out the
+// fields without protobuf type
+func (w *protoWriter) fields(fields []dmmf.Field) []protoField {
+	var result []protoField
+	for _, field := range fields {
+		typ, ok := w.fieldType(field)
+		if !ok {
+			continue
+		}
+		f := protoField{name: snakeCase(field.Name), typ: typ, doc: field.Documentation}
+		switch {
+		case field.IsList:
+			f.label = "repeated "
+		case !field.IsRequired && !isMessage(field):
+			f.label = "optional "
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// scalarTypes maps scalar types to protobuf types
+var scalarTypes = map[string]string{
+	"String":   "string",
+	"Int":      "int32",
+	"BigInt":   "int64",
+	"Float":    "double",
+	"Boolean":  "bool",
+	"Bytes":    "bytes",
+	"Decimal":  "string",
+	"DateTime": "google.protobuf.Timestamp",
+	"Json":     "google.protobuf.Struct",
+}
+
+// wellKnownTypes maps the well-known types used to the files declaring them
+var wellKnownTypes = map[string]string{
+	"google.protobuf.Timestamp": "google/protobuf/timestamp.proto",
+	"google.protobuf.Struct":    "google/protobuf/struct.proto",
+	"google.protobuf.Empty":     "google/protobuf/empty.proto",
+	"google.protobuf.FieldMask": "google/protobuf/field_mask.proto",
+}
+
+// fieldType maps the type of a field to a protobuf type. Unsupported and
+// extension types have none.
+func (w *protoWriter) fieldType(field dmmf.Field) (string, bool) {
+	switch field.Kind {
+	case dmmf.KindEnum, dmmf.KindObject:
+		return field.Type, true
+	case dmmf.KindScalar:
+		typ, ok := scalarTypes[field.Type]
+		w.use(typ)
+		return typ, ok
+	}
+	return "", false
+}
+
+// use imports the file of a well-known type
+func (w *protoWriter) use(typ string) {
+	if file, ok := wellKnownTypes[typ]; ok {
+		w.imports[file] = true
+	}
+}
+
+// isMessage reports whether a field has a message type, which tracks
+// presence without the optional label
+func isMessage(field dmmf.Field) bool {
+	return field.Kind == dmmf.KindObject ||
+		field.Kind == dmmf.KindScalar && (field.Type == "DateTime" || field.Type == "Json")
+}
+
+func (w *protoWriter) comment(indent, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		w.body.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+}
+
+func (w *protoWriter) enum(enum dmmf.Enum) {
+	names := make([]string, len(enum.Values))
+	for i, value := range enum.Values {
+		names[i] = enumValueName(enum.Name, value.Name)
+	}
+	numbers := w.lock.enum(enum.Name, names)
+
+	w.body.WriteString("\n")
+	w.comment("", enum.Documentation)
+	fmt.Fprintf(&w.body, "enum %s {\n", enum.Name)
+	w.reserved(numbers)
+	fmt.Fprintf(&w.body, "  %s = 0;\n", enumValueName(enum.Name, "UNSPECIFIED"))
+	for _, name := range names {
+		fmt.Fprintf(&w.body, "  %s = %d;\n", name, numbers.Numbers[name])
+	}
+	w.body.WriteString("}\n")
+}
+
+func (w *protoWriter) message(name, doc string, fields []protoField) {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+	numbers := w.lock.message(name, names)
+
+	w.body.WriteString("\n")
+	w.comment("", doc)
+	fmt.Fprintf(&w.body, "message %s {\n", name)
+	w.reserved(numbers)
+	for _, field := range fields {
+		w.comment("  ", field.doc)
+		fmt.Fprintf(&w.body, "  %s%s %s = %d;\n", field.label, field.typ, field.name, numbers.Numbers[field.name])
+	}
+	w.body.WriteString("}\n")
+}
+
+func (w *protoWriter) reserved(n *numbering) {
+	if len(n.Reserved) > 0 {
+		list := make([]string, len(n.Reserved))
+		for i, number := range n.Reserved {
+			list[i] = fmt.Sprint(number)
+		}
+		fmt.Fprintf(&w.body, "  reserved %s;\n", strings.Join(list, ", "))
+	}
+	if len(n.ReservedNames) > 0 {
+		list := make([]string, len(n.ReservedNames))
+		for i, name := range n.ReservedNames {
+			list[i] = fmt.Sprintf("%q", name)
+		}
+		fmt.Fprintf(&w.body, "  reserved %s;\n", strings.Join(list, ", "))
+	}
+}
+
+// service renders the CRUD service of a model and its request messages.
+// Get and Delete take the primary key, Update a field mask of the fields
+// to write.
+func (w *protoWriter) service(model dmmf.Model) {
+	w.use("google.protobuf.Empty")
+	w.use("google.protobuf.FieldMask")
+
+	name, many := model.Name, codegen.Plural(model.Name)
+	var key []protoField
+	for _, field := range model.Fields {
+		for _, pk := range model.PrimaryKey {
+			if field.Name == pk {
+				typ, _ := w.fieldType(field)
+				key = append(key, protoField{name: snakeCase(field.Name), typ: typ})
+			}
+		}
+	}
+
+	w.body.WriteString("\n")
+	fmt.Fprintf(&w.body, "// %sService reads and writes %s records\n", name, name)
+	fmt.Fprintf(&w.body, "service %sService {\n", name)
+	fmt.Fprintf(&w.body, "  rpc Get%s(Get%sRequest) returns (%s);\n", name, name, name)
+	fmt.Fprintf(&w.body, "  rpc List%s(List%sRequest) returns (List%sResponse);\n", many, many, many)
+	fmt.Fprintf(&w.body, "  rpc Create%s(Create%sRequest) returns (%s);\n", name, name, name)
+	fmt.Fprintf(&w.body, "  rpc Update%s(Update%sRequest) returns (%s);\n", name, name, name)
+	fmt.Fprintf(&w.body, "  rpc Delete%s(Delete%sRequest) returns (google.protobuf.Empty);\n", name, name)
+	w.body.WriteString("}\n")
+
+	record := snakeCase(name)
+	w.message("Get"+name+"Request", "", key)
+	w.message("List"+many+"Request", "", []protoField{
+		{name: "take", typ: "int32", doc: "Maximum number of records, all when 0"},
+		{name: "skip", typ: "int32", doc: "Number of records to skip"},
+	})
+	w.message("List"+many+"Response", "", []protoField{
+		{name: snakeCase(many), typ: name, label: "repeated "},
+	})
+	w.message("Create"+name+"Request", "", []protoField{
+		{name: record, typ: name},
+	})
+	w.message("Update"+name+"Request", "", []protoField{
+		{name: record, typ: name, doc: "Record with the primary key and the new values"},
+		{name: "update_mask", typ: "google.protobuf.FieldMask", doc: "Fields to update, all when empty"},
+	})
+	w.message("Delete"+name+"Request", "", key)
+}
@@ -0,0 +1,203 @@
+package proto
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/generator/codegen"
+	"github.com/satishbabariya/prisma-go/generator/dmmf"
+	"github.com/satishbabariya/prisma-go/psl"
+)
+
+const shopSchema = `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+enum Status {
+  IN_STOCK
+  soldOut
+}
+
+/// A product for sale
+model Product {
+  id        Int       @id @default(autoincrement())
+  name      String
+  price     Decimal
+  status    Status?
+  tags      String[]
+  specs     Json?
+  createdAt DateTime  @default(now())
+  shipping  Shipping?
+  reviews   Review[]
+}
+
+model Review {
+  id        Int     @id @default(autoincrement())
+  productId Int
+  product   Product @relation(fields: [productId], references: [id])
+}
+
+type Shipping {
+  weight Float
+}
+`
+
+// generate runs the plugin for a schema into dir and returns the files it
+// wrote
+func generate(t *testing.T, schema, dir string, config map[string]interface{}) map[string]string {
+	t.Helper()
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", schema)}
+	doc, diags := dmmf.FromFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	doc.Generator = &dmmf.GeneratorConfig{Name: "proto", Provider: Provider, Output: dir, Config: config}
+	if err := Generate(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	written := make(map[string]string)
+	for _, name := range []string{ProtoFile, LockFile, ConvertFile} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			written[name] = string(data)
+		}
+	}
+	return written
+}
+
+func TestGenerate(t *testing.T) {
+	written := generate(t, shopSchema, t.TempDir(), map[string]interface{}{
+		"package":       "shop.v1",
+		"goPackage":     "example.com/shop/pb;shoppb",
+		"services":      true,
+		"modelsPackage": "example.com/shop/generated",
+	})
+
+	proto := written[ProtoFile]
+	for _, want := range []string{
+		"package shop.v1;",
+		`import "google/protobuf/struct.proto";`,
+		`import "google/protobuf/timestamp.proto";`,
+		`option go_package = "example.com/shop/pb;shoppb";`,
+		"enum Status {\n  STATUS_UNSPECIFIED = 0;\n  STATUS_IN_STOCK = 1;\n  STATUS_SOLD_OUT = 2;\n}",
+		"// A product for sale\nmessage Product {",
+		"  int32 id = 1;",
+		"  string price = 3;",
+		"  optional Status status = 4;",
+		"  repeated string tags = 5;",
+		"  google.protobuf.Struct specs = 6;",
+		"  google.protobuf.Timestamp created_at = 7;",
+		"  Shipping shipping = 8;",
+		"  repeated Review reviews = 9;",
+		"message Shipping {\n  double weight = 1;\n}",
+		"  rpc ListProducts(ListProductsRequest) returns (ListProductsResponse);",
+		"  rpc DeleteReview(DeleteReviewRequest) returns (google.protobuf.Empty);",
+		"message GetProductRequest {\n  int32 id = 1;\n}",
+		"  google.protobuf.FieldMask update_mask = 2;",
+	} {
+		if !strings.Contains(proto, want) {
+			t.Errorf("%s does not contain %q:\n%s", ProtoFile, want, proto)
+		}
+	}
+
+	convert := written[ConvertFile]
+	if _, err := parser.ParseFile(token.NewFileSet(), ConvertFile, convert, 0); err != nil {
+		t.Fatalf("invalid %s: %v\n%s", ConvertFile, err, convert)
+	}
+	for _, want := range []string{
+		"package shoppb",
+		`models "example.com/shop/generated"`,
+		"func ProductToProto(m *models.Product) (*Product, error) {",
+		"func ReviewFromProto(p *Review) (*models.Review, error) {",
+		"p.Status = StatusToProto(*m.Status).Enum()",
+		"case Status_STATUS_SOLD_OUT:\n\t\treturn models.StatussoldOut, nil",
+		"p.CreatedAt = timestamppb.New(m.Createdat)",
+		"v, err := types.ParseDecimal(p.Price)",
+		"if err = structToJSON(p.Specs, &m.Specs); err != nil {",
+	} {
+		if !strings.Contains(convert, want) {
+			t.Errorf("%s does not contain %q:\n%s", ConvertFile, want, convert)
+		}
+	}
+	if strings.Contains(convert, "Shipping") {
+		t.Errorf("composite type fields must be left out of the conversions:\n%s", convert)
+	}
+}
+
+func TestFieldNumbersStable(t *testing.T) {
+	dir := t.TempDir()
+	generate(t, shopSchema, dir, map[string]interface{}{})
+
+	// Drop price, add a field in front of name and a value to the enum
+	changed := strings.Replace(shopSchema, "  price     Decimal\n", "", 1)
+	changed = strings.Replace(changed, "  name      String\n", "  sku       String\n  name      String\n", 1)
+	changed = strings.Replace(changed, "  IN_STOCK\n", "  PREORDER\n  IN_STOCK\n", 1)
+	proto := generate(t, changed, dir, map[string]interface{}{})[ProtoFile]
+
+	for _, want := range []string{
+		"  reserved 3;\n  reserved \"price\";\n  int32 id = 1;\n  string sku = 10;\n  string name = 2;\n",
+		"  STATUS_PREORDER = 3;\n  STATUS_IN_STOCK = 1;\n",
+		"  repeated Review reviews = 9;",
+	} {
+		if !strings.Contains(proto, want) {
+			t.Errorf("%s does not contain %q:\n%s", ProtoFile, want, proto)
+		}
+	}
+
+	// Regenerating without changes keeps the files as they are
+	again := generate(t, changed, dir, map[string]interface{}{})
+	if again[ProtoFile] != proto {
+		t.Errorf("regenerated %s differs:\n%s", ProtoFile, again[ProtoFile])
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions(map[string]interface{}{"services": "true"})
+	if err != nil || opts.Package != "prisma" || !opts.Services {
+		t.Errorf("ParseOptions = %+v, %v", opts, err)
+	}
+	if _, err := ParseOptions(map[string]interface{}{"modelsPackage": "example.com/generated"}); err == nil {
+		t.Error("expected an error for modelsPackage without goPackage")
+	}
+}
+
+func TestNames(t *testing.T) {
+	for _, tt := range []struct{ fn, in, want string }{
+		{"snake", "createdAt", "created_at"},
+		{"snake", "userID", "user_id"},
+		{"snake", "HTTPServer", "http_server"},
+		{"snake", "IN_STOCK", "in_stock"},
+		{"enum", "OrderStatus", "ORDER_STATUS_X"},
+		{"go", "created_at", "CreatedAt"},
+		{"go", "user_id2", "UserId2"},
+		{"go", "_private", "XPrivate"},
+		{"plural", "Category", "Categories"},
+		{"plural", "Box", "Boxes"},
+		{"plural", "Day", "Days"},
+		{"package", "example.com/shop/pb;shoppb", "shoppb"},
+		{"package", "example.com/shop/shop-pb", "shop_pb"},
+	} {
+		var got string
+		switch tt.fn {
+		case "snake":
+			got = snakeCase(tt.in)
+		case "enum":
+			got = enumValueName(tt.in, "x")
+		case "go":
+			got = goCamelCase(tt.in)
+		case "plural":
+			got = codegen.Plural(tt.in)
+		case "package":
+			got = goPackageName(tt.in)
+		}
+		if got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.fn, tt.in, got, tt.want)
+		}
+	}
+}
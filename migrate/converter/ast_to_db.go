@@ -7,6 +7,7 @@ import (
 
 	"github.com/satishbabariya/prisma-go/migrate/introspect"
 	ast "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
 )
 
 // ConvertASTToDBSchema converts Prisma schema AST to DatabaseSchema
@@ -55,6 +56,11 @@ func convertModelToTable(model *ast.Model, parsed *ast.SchemaAst, provider strin
 
 	// Convert fields to columns
 	var primaryKeyColumns []string
+	softDelete, err := model.SoftDeleteField()
+	if err != nil {
+		return nil, err
+	}
+	softDeleteColumn := ""
 	scalarTypes := map[string]bool{
 		"int": true, "bigint": true, "string": true, "boolean": true, "bool": true,
 		"datetime": true, "float": true, "decimal": true, "json": true, "bytes": true,
@@ -97,6 +103,9 @@ func convertModelToTable(model *ast.Model, parsed *ast.SchemaAst, provider strin
 			return nil, fmt.Errorf("failed to convert field %s: %w", field.Name.Name, err)
 		}
 		table.Columns = append(table.Columns, *column)
+		if field.Name.Name == softDelete {
+			softDeleteColumn = column.Name
+		}
 
 		// Check for primary key
 		if hasAttribute(field, "id") {
@@ -122,6 +131,16 @@ func convertModelToTable(model *ast.Model, parsed *ast.SchemaAst, provider strin
 		}
 	}
 
+	// Unique indexes of soft-deleting models ignore soft-deleted rows; MySQL
+	// has no partial indexes
+	if softDeleteColumn != "" && provider != "mysql" {
+		for i := range table.Indexes {
+			if table.Indexes[i].IsUnique {
+				table.Indexes[i].Where = sqlgen.QuoteIdentifier(provider, softDeleteColumn) + " IS NULL"
+			}
+		}
+	}
+
 	// Extract foreign keys from relation attributes
 	foreignKeys := extractForeignKeys(model, tableName)
 	table.ForeignKeys = append(table.ForeignKeys, foreignKeys...)
@@ -221,6 +240,15 @@ func mapPrismaTypeToDB(prismaType string, provider string) (string, error) {
 		case "sqlite":
 			return "REAL", nil
 		}
+	case "decimal":
+		// SQLite gives DECIMAL columns numeric affinity, so values compare as
+		// numbers rather than as text
+		switch provider {
+		case "postgresql", "postgres", "mysql":
+			return "DECIMAL(65,30)", nil
+		case "sqlite":
+			return "DECIMAL", nil
+		}
 	case "json":
 		switch provider {
 		case "postgresql", "postgres":
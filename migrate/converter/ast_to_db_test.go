@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	schema "github.com/satishbabariya/prisma-go/psl/parsing/v2"
+)
+
+const softDeleteSchema = `
+/// @softDelete(deletedAt)
+model Post {
+  id        Int       @id
+  title     String    @unique
+  deletedAt DateTime? @map("deleted_at")
+}
+`
+
+func TestSoftDeleteUniqueIndexes(t *testing.T) {
+	parsed, err := schema.ParseSchemaString("schema.prisma", softDeleteSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for provider, want := range map[string]string{
+		"postgresql": `"deleted_at" IS NULL`,
+		"sqlite":     `"deleted_at" IS NULL`,
+		"mysql":      "", // MySQL has no partial indexes
+	} {
+		db, err := ConvertASTToDBSchema(parsed, provider)
+		if err != nil {
+			t.Fatalf("%s: %v", provider, err)
+		}
+		index := db.Tables[0].Indexes[0]
+		if !index.IsUnique || index.Where != want {
+			t.Errorf("%s: index %s has predicate %q, want %q", provider, index.Name, index.Where, want)
+		}
+	}
+
+	parsed, err = schema.ParseSchemaString("schema.prisma", strings.Replace(softDeleteSchema, "(deletedAt)", "(deletedAt", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ConvertASTToDBSchema(parsed, "sqlite"); err == nil {
+		t.Error("an unclosed @softDelete comment converted without error")
+	}
+}
@@ -2,6 +2,8 @@
 package flavour
 
 import (
+	"strings"
+
 	"github.com/satishbabariya/prisma-go/migrate/introspect"
 )
 
@@ -51,3 +53,20 @@ func NewColumnTypeChange(fromType, toType string, isSafe bool) *ColumnTypeChange
 		IsSafe:   isSafe,
 	}
 }
+
+// PredicatesMatch checks if the predicates of two partial indexes match,
+// ignoring the quoting, parentheses, case and spacing databases add when
+// they report them
+func PredicatesMatch(prev, next string) bool {
+	normalize := func(predicate string) string {
+		predicate = strings.Map(func(r rune) rune {
+			switch r {
+			case '"', '`', '[', ']', '(', ')':
+				return -1
+			}
+			return r
+		}, predicate)
+		return strings.ToLower(strings.Join(strings.Fields(predicate), " "))
+	}
+	return normalize(prev) == normalize(next)
+}
@@ -18,7 +18,7 @@ func (f *SQLiteFlavour) IndexesMatch(prev, next *introspect.Index) bool {
 	if len(prev.Columns) != len(next.Columns) {
 		return false
 	}
-	if prev.IsUnique != next.IsUnique {
+	if prev.IsUnique != next.IsUnique || !PredicatesMatch(prev.Where, next.Where) {
 		return false
 	}
 	for i, col := range prev.Columns {
@@ -136,7 +136,7 @@ func (e *Executor) Rollback(ctx context.Context, migrationID string) error {
 		if err != nil {
 			debug.Error("Failed to execute rollback statement", "index", i+1, "statement", stmt, "error", err)
 			_ = tx.Rollback()
-			return fmt.Errorf("failed to execute rollback statement %d: %w", i+1, err)
+			return fmt.Errorf("failed to execute rollback statement %d: %w", i+1, classifyError(err))
 		}
 		debug.Debug("Rollback statement executed successfully", "index", i+1)
 	}
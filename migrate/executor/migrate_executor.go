@@ -4,10 +4,12 @@ package executor
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/satishbabariya/prisma-go/migrate/history"
+	"github.com/satishbabariya/prisma-go/runtime/dberrors"
 )
 
 // MigrationExecutor executes migrations on a database
@@ -52,7 +54,7 @@ func (e *MigrationExecutor) ExecuteMigration(ctx context.Context, migrationSQL s
 	_, err = tx.ExecContext(ctx, migrationSQL)
 	if err != nil {
 		_ = tx.Rollback()
-		return fmt.Errorf("failed to execute migration: %w", err)
+		return fmt.Errorf("failed to execute migration: %w", classifyError(err))
 	}
 
 	// Calculate checksum
@@ -104,7 +106,7 @@ func (e *MigrationExecutor) ExecuteMigrationStatements(ctx context.Context, stat
 		_, err = tx.ExecContext(ctx, stmt)
 		if err != nil {
 			_ = tx.Rollback()
-			return fmt.Errorf("failed to execute statement %d: %w", i+1, err)
+			return fmt.Errorf("failed to execute statement %d: %w", i+1, classifyError(err))
 		}
 	}
 
@@ -224,7 +226,7 @@ func (e *MigrationExecutor) RollbackMigration(ctx context.Context, rollbackSQL s
 	_, err = tx.ExecContext(ctx, rollbackSQL)
 	if err != nil {
 		_ = tx.Rollback()
-		return fmt.Errorf("failed to execute rollback SQL: %w", err)
+		return fmt.Errorf("failed to execute rollback SQL: %w", classifyError(err))
 	}
 
 	_ = time.Since(startTime) // Track execution time for potential logging
@@ -320,3 +322,15 @@ type Migration struct {
 	AppliedAt time.Time
 	Checksum  string
 }
+
+// classifyError classifies the error of a migration statement by the code
+// of the database driver, as errors of generated clients are, e.g. P2002
+// for a unique index added over duplicate values. Errors without a known
+// code are returned as they are.
+func classifyError(err error) error {
+	var prismaErr *dberrors.PrismaError
+	if errors.As(dberrors.ClassifyError(err), &prismaErr) && prismaErr.Code != "P0000" {
+		return prismaErr
+	}
+	return err
+}
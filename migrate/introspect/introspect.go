@@ -52,6 +52,7 @@ type Index struct {
 	Name     string
 	Columns  []string
 	IsUnique bool
+	Where    string // Predicate of a partial index, e.g. "deleted_at" IS NULL
 }
 
 // ForeignKey represents a foreign key constraint
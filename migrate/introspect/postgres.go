@@ -274,7 +274,8 @@ func (i *PostgresIntrospector) introspectIndexes(ctx context.Context, schema, ta
 		SELECT 
 			i.relname as index_name,
 			array_agg(a.attname ORDER BY array_position(ix.indkey, a.attnum)) as columns,
-			ix.indisunique as is_unique
+			ix.indisunique as is_unique,
+			COALESCE(pg_get_expr(ix.indpred, ix.indrelid), '') as predicate
 		FROM pg_class t
 		JOIN pg_index ix ON t.oid = ix.indrelid
 		JOIN pg_class i ON i.oid = ix.indexrelid
@@ -283,7 +284,7 @@ func (i *PostgresIntrospector) introspectIndexes(ctx context.Context, schema, ta
 		WHERE n.nspname = $1
 		  AND t.relname = $2
 		  AND NOT ix.indisprimary
-		GROUP BY i.relname, ix.indisunique
+		GROUP BY i.relname, ix.indisunique, ix.indpred, ix.indrelid
 		ORDER BY i.relname
 	`
 
@@ -298,7 +299,7 @@ func (i *PostgresIntrospector) introspectIndexes(ctx context.Context, schema, ta
 		var idx Index
 		var columnsArray string
 
-		err := rows.Scan(&idx.Name, &columnsArray, &idx.IsUnique)
+		err := rows.Scan(&idx.Name, &columnsArray, &idx.IsUnique, &idx.Where)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan index: %w", err)
 		}
@@ -171,6 +171,9 @@ func (i *SQLiteIntrospector) introspectIndexes(ctx context.Context, tableName st
 		}
 
 		idx.IsUnique = (unique == 1)
+		if partial == 1 {
+			idx.Where = i.indexPredicate(ctx, idx.Name)
+		}
 
 		// Get columns for this index
 		colQuery := fmt.Sprintf("PRAGMA index_info(%s)", idx.Name)
@@ -202,6 +205,22 @@ func (i *SQLiteIntrospector) introspectIndexes(ctx context.Context, tableName st
 	return indexes, rows.Err()
 }
 
+// indexPredicate returns the predicate of a partial index, read from its
+// CREATE INDEX statement
+func (i *SQLiteIntrospector) indexPredicate(ctx context.Context, name string) string {
+	var stmt sql.NullString
+	err := i.db.QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE type = 'index' AND name = ?", name).Scan(&stmt)
+	if err != nil || !stmt.Valid {
+		return ""
+	}
+	upper := strings.ToUpper(stmt.String)
+	at := strings.LastIndex(upper, " WHERE ")
+	if at < 0 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt.String[at+len(" WHERE "):]), ";"))
+}
+
 // introspectForeignKeys reads all foreign keys for a table
 func (i *SQLiteIntrospector) introspectForeignKeys(ctx context.Context, tableName string) ([]ForeignKey, error) {
 	query := fmt.Sprintf("PRAGMA foreign_key_list(%s)", tableName)
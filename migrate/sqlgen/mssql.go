@@ -89,10 +89,15 @@ func (g *SQLServerMigrationGenerator) generateCreateTableFromTable(table *intros
 
 	// Add indexes
 	for _, idx := range table.Indexes {
+		// Partial indexes are filtered indexes in SQL Server
+		where := ""
+		if idx.Where != "" {
+			where = " WHERE " + idx.Where
+		}
 		if idx.IsUnique {
-			sql.WriteString(fmt.Sprintf("CREATE UNIQUE INDEX [%s] ON [%s] (%s);\n", idx.Name, table.Name, g.quoteColumns(idx.Columns)))
+			sql.WriteString(fmt.Sprintf("CREATE UNIQUE INDEX [%s] ON [%s] (%s)%s;\n", idx.Name, table.Name, g.quoteColumns(idx.Columns), where))
 		} else {
-			sql.WriteString(fmt.Sprintf("CREATE INDEX [%s] ON [%s] (%s);\n", idx.Name, table.Name, g.quoteColumns(idx.Columns)))
+			sql.WriteString(fmt.Sprintf("CREATE INDEX [%s] ON [%s] (%s)%s;\n", idx.Name, table.Name, g.quoteColumns(idx.Columns), where))
 		}
 	}
 
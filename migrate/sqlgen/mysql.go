@@ -375,7 +375,8 @@ func (g *MySQLMigrationGenerator) generateAlterTable(change diff.TableChange, db
 	return sql.String()
 }
 
-// generateCreateIndex generates CREATE INDEX SQL for MySQL
+// generateCreateIndex generates CREATE INDEX SQL for MySQL. MySQL has no
+// partial indexes: the predicate of the index is not applied.
 func (g *MySQLMigrationGenerator) generateCreateIndex(tableName string, idx introspect.Index) string {
 	unique := ""
 	if idx.IsUnique {
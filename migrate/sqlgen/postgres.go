@@ -472,8 +472,13 @@ func (g *PostgresMigrationGenerator) generateCreateIndex(tableName string, idx i
 		cols[i] = fmt.Sprintf("\"%s\"", col)
 	}
 
-	return fmt.Sprintf("CREATE %sINDEX \"%s\" ON \"%s\" (%s);",
-		unique, idx.Name, tableName, strings.Join(cols, ", "))
+	where := ""
+	if idx.Where != "" {
+		where = " WHERE " + idx.Where
+	}
+
+	return fmt.Sprintf("CREATE %sINDEX \"%s\" ON \"%s\" (%s)%s;",
+		unique, idx.Name, tableName, strings.Join(cols, ", "), where)
 }
 
 // generateAddForeignKey generates ALTER TABLE ADD CONSTRAINT SQL for foreign keys
@@ -273,7 +273,9 @@ func (g *SQLiteMigrationGenerator) mapToSQLiteType(genericType string) string {
 		return "INTEGER" // SQLite uses INTEGER for booleans
 	case strings.Contains(upperType, "VARCHAR"), strings.Contains(upperType, "CHAR"), upperType == "TEXT", upperType == "UUID":
 		return "TEXT"
-	case strings.Contains(upperType, "DECIMAL"), strings.Contains(upperType, "NUMERIC"), strings.Contains(upperType, "FLOAT"), strings.Contains(upperType, "DOUBLE"), strings.Contains(upperType, "REAL"):
+	case strings.Contains(upperType, "DECIMAL"), strings.Contains(upperType, "NUMERIC"):
+		return "DECIMAL" // NUMERIC affinity keeps values REAL would round as text
+	case strings.Contains(upperType, "FLOAT"), strings.Contains(upperType, "DOUBLE"), strings.Contains(upperType, "REAL"):
 		return "REAL"
 	case strings.Contains(upperType, "TIMESTAMP"), strings.Contains(upperType, "DATE"), strings.Contains(upperType, "TIME"):
 		return "TEXT" // SQLite stores dates as TEXT
@@ -377,8 +379,13 @@ func (g *SQLiteMigrationGenerator) generateCreateIndex(tableName string, idx int
 		cols[i] = fmt.Sprintf("\"%s\"", col)
 	}
 
-	return fmt.Sprintf("CREATE %sINDEX \"%s\" ON \"%s\" (%s);",
-		unique, idx.Name, tableName, strings.Join(cols, ", "))
+	where := ""
+	if idx.Where != "" {
+		where = " WHERE " + idx.Where
+	}
+
+	return fmt.Sprintf("CREATE %sINDEX \"%s\" ON \"%s\" (%s)%s;",
+		unique, idx.Name, tableName, strings.Join(cols, ", "), where)
 }
 
 // generateForeignKeyDefinition generates FOREIGN KEY constraint for SQLite
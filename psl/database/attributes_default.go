@@ -155,7 +155,7 @@ func defaultAttributeMappedName(ctx *Context) *StringId {
 		return nil
 	}
 
-	name, ok := CoerceString(expr, ctx.diagnostics)
+	name, ok := CoerceString(expr, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
@@ -183,7 +183,7 @@ func validateDbGeneratedArgs(args []v2ast.Expression, accept func(), ctx *Contex
 	}
 
 	// Validate that the argument is a string
-	if _, ok := CoerceString(args[0], ctx.diagnostics); ok {
+	if _, ok := CoerceString(args[0], ctx.fileID(), ctx.diagnostics); ok {
 		accept()
 	} else {
 		ctx.PushAttributeValidationError("The `dbgenerated()` function accepts only a string argument.")
@@ -16,7 +16,7 @@ func HandleFieldId(
 ) {
 	if modelAttrs.PrimaryKey != nil {
 		pos := astModel.TopPos()
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astModel.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astModel.GetName()), ctx.fileID())
 		ctx.PushError(diagnostics.NewModelValidationError(
 			"At most one field must be marked as the id field with the `@id` attribute.",
 			"model",
@@ -31,7 +31,7 @@ func HandleFieldId(
 	// Handle length argument for @id fields
 	var length *int
 	if expr := ctx.VisitOptionalArg("length"); expr != nil {
-		if intVal, ok := CoerceInteger(expr, ctx.diagnostics); ok {
+		if intVal, ok := CoerceInteger(expr, ctx.fileID(), ctx.diagnostics); ok {
 			if intVal > 0 {
 				lengthInt := int(intVal)
 				length = &lengthInt
@@ -43,7 +43,7 @@ func HandleFieldId(
 
 	var sortOrder *SortOrder
 	if expr := ctx.VisitOptionalArg("sort"); expr != nil {
-		if sortVal, ok := CoerceConstant(expr, ctx.diagnostics); ok {
+		if sortVal, ok := CoerceConstant(expr, ctx.fileID(), ctx.diagnostics); ok {
 			switch sortVal {
 			case "Desc":
 				desc := SortOrderDesc
@@ -107,7 +107,7 @@ func HandleModelId(
 
 	// Use the common field resolution function (without composite type support for @@id)
 	pos := attr.Pos
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 	resolvedFields, resolveErr := resolveFieldArrayWithArgs(fieldsExpr, span, modelID, false, false, ctx)
 	if resolveErr != nil {
 		// Errors already pushed
@@ -137,7 +137,7 @@ func HandleModelId(
 
 	if len(fieldsThatAreNotRequired) > 0 && !modelAttrs.IsIgnored {
 		pos := attr.Pos
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 		ctx.PushError(diagnostics.NewModelValidationError(
 			"The id definition refers to the optional fields: "+formatFieldNames(fieldsThatAreNotRequired)+". ID definitions must reference only required fields.",
 			"model",
@@ -148,7 +148,7 @@ func HandleModelId(
 
 	if modelAttrs.PrimaryKey != nil {
 		pos := astModel.TopPos()
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astModel.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astModel.GetName()), ctx.fileID())
 		ctx.PushError(diagnostics.NewModelValidationError(
 			"Each model must have at most one id criteria. You can't have `@id` and `@@id` at the same time.",
 			"model",
@@ -162,7 +162,7 @@ func HandleModelId(
 	name := getNameArgument(ctx)
 	if name != nil {
 		pos := attr.Pos
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 		validateClientName(span, astModel.GetName(), *name, "@@id", ctx)
 	}
 
@@ -217,7 +217,7 @@ func ValidateIdFieldArities(
 		// TODO: Get proper attribute span from AttributeId
 		// For now, use the field span
 		pos := astField.Pos
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), modelID.FileID)
 		ctx.PushError(diagnostics.NewAttributeValidationError(
 			"Fields that are marked as id must be required.",
 			"@id",
@@ -233,7 +233,7 @@ func primaryKeyMappedName(ctx *Context) *StringId {
 		return nil
 	}
 
-	name, ok := CoerceString(expr, ctx.diagnostics)
+	name, ok := CoerceString(expr, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
@@ -254,7 +254,7 @@ func getNameArgument(ctx *Context) *StringId {
 		return nil
 	}
 
-	name, ok := CoerceString(expr, ctx.diagnostics)
+	name, ok := CoerceString(expr, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
@@ -300,7 +300,7 @@ func validateClusteringSetting(ctx *Context) *bool {
 		return nil
 	}
 
-	val, ok := CoerceBoolean(expr, ctx.diagnostics)
+	val, ok := CoerceBoolean(expr, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
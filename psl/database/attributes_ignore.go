@@ -39,7 +39,7 @@ func HandleModelIgnore(modelID ModelId, modelAttrs *ModelAttributes, ctx *Contex
 					astField := astModel.Fields[entry.Field.FieldID]
 					if astField != nil {
 						pos := astField.Pos
-						span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), diagnostics.FileIDZero)
+						span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), modelID.FileID)
 				ctx.PushError(diagnostics.NewAttributeValidationError(
 					"Fields on an already ignored Model do not need an `@ignore` annotation.",
 					"@ignore",
@@ -59,7 +59,7 @@ func HandleModelIgnore(modelID ModelId, modelAttrs *ModelAttributes, ctx *Contex
 				astField := astModel.Fields[entry.Field.FieldID]
 				if astField != nil {
 					pos := astField.Pos
-					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), diagnostics.FileIDZero)
+					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), modelID.FileID)
 				ctx.PushError(diagnostics.NewAttributeValidationError(
 					"Fields on an already ignored Model do not need an `@ignore` annotation.",
 					"@ignore",
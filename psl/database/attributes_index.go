@@ -87,7 +87,7 @@ func HandleModelUnique(
 	name := getNameArgument(ctx)
 	if name != nil {
 		pos := attr.Pos
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 		validateClientName(span, astModel.GetName(), *name, "@@unique", ctx)
 	}
 
@@ -105,6 +105,58 @@ func HandleModelUnique(
 	})
 }
 
+// HandleFieldUnique handles @unique on a scalar field.
+func HandleFieldUnique(
+	sfid ScalarFieldId,
+	modelAttrs *ModelAttributes,
+	ctx *Context,
+) {
+	field := FieldWithArgs{Field: sfid}
+
+	if expr := ctx.VisitOptionalArg("length"); expr != nil {
+		if intVal, ok := CoerceInteger(expr, ctx.fileID(), ctx.diagnostics); ok {
+			if intVal > 0 {
+				length := int(intVal)
+				field.Length = &length
+			} else {
+				ctx.PushAttributeValidationError("The `length` argument must be a positive integer.")
+			}
+		}
+	}
+
+	if expr := ctx.VisitOptionalArg("sort"); expr != nil {
+		if sortVal, ok := CoerceConstant(expr, ctx.fileID(), ctx.diagnostics); ok {
+			switch sortVal {
+			case "Desc":
+				desc := SortOrderDesc
+				field.SortOrder = &desc
+			case "Asc":
+				asc := SortOrderAsc
+				field.SortOrder = &asc
+			default:
+				ctx.PushAttributeValidationError(
+					"The `sort` argument can only be `Asc` or `Desc` you provided: " + sortVal + ".",
+				)
+			}
+		}
+	}
+
+	source := sfid
+	indexAttr := IndexAttribute{
+		Type:        IndexTypeUnique,
+		Fields:      []FieldWithArgs{field},
+		SourceField: &source,
+		MappedName:  getIndexMappedName(ctx),
+		Clustered:   validateClusteringSetting(ctx),
+	}
+
+	attrID := ctx.CurrentAttributeID()
+	modelAttrs.AstIndexes = append(modelAttrs.AstIndexes, IndexAttributeEntry{
+		AttributeID: uint32(attrID.Index), // Simplified
+		Index:       indexAttr,
+	})
+}
+
 // HandleModelFulltext handles @@fulltext on a model.
 func HandleModelFulltext(
 	modelID ModelId,
@@ -154,7 +206,7 @@ func commonIndexValidations(
 
 	isUnique := indexData != nil && indexData.Type == IndexTypeUnique
 	pos := attr.Pos
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 	resolvedFields, resolveErr := resolveFieldArrayWithArgs(fieldsExpr, span, modelID, followComposites, isUnique, ctx)
 	if resolveErr == errFieldResolutionAlreadyDealtWith {
 		return
@@ -202,16 +254,17 @@ func resolveFieldArrayWithArgs(
 		if elem == nil {
 			continue
 		}
-		// Try to parse as function call first (field with arguments)
-		fieldName, args, _, isFunc := CoerceFunction(elem, ctx.diagnostics)
-		if !isFunc {
-			// Try as constant
+		// A field with arguments is a function call, e.g. title(sort: Desc)
+		var fieldName string
+		var args []v2ast.Expression
+		if _, isFunc := elem.(*v2ast.FunctionCall); isFunc {
+			fieldName, args, _, _ = CoerceFunction(elem, ctx.fileID(), ctx.diagnostics)
+		} else {
 			var ok bool
-			fieldName, ok = CoerceConstant(elem, ctx.diagnostics)
+			fieldName, ok = CoerceConstant(elem, ctx.fileID(), ctx.diagnostics)
 			if !ok {
 				continue
 			}
-			args = nil
 		}
 
 		// Parse field arguments
@@ -313,7 +366,7 @@ func getIndexMappedName(ctx *Context) *StringId {
 		return nil
 	}
 
-	name, ok := CoerceString(expr, ctx.diagnostics)
+	name, ok := CoerceString(expr, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
@@ -334,7 +387,7 @@ func getIndexAlgorithm(ctx *Context) *IndexAlgorithm {
 		return nil
 	}
 
-	algoName, ok := CoerceConstant(expr, ctx.diagnostics)
+	algoName, ok := CoerceConstant(expr, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
@@ -14,7 +14,7 @@ func VisitMapAttribute(ctx *Context) *StringId {
 		return nil
 	}
 
-	name, ok := CoerceString(expr, ctx.diagnostics)
+	name, ok := CoerceString(expr, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
@@ -65,7 +65,7 @@ func HandleScalarFieldMap(
 	if existingFieldID, exists := ctx.mappedModelScalarFieldNames[key]; exists {
 		fieldName := astField.GetName()
 		pos := astField.Pos
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), ctx.fileID())
 		ctx.PushError(diagnostics.NewDuplicateFieldError(
 			astModel.GetName(),
 			fieldName,
@@ -91,7 +91,7 @@ func HandleScalarFieldMap(
 					// Conflict with regular field name
 					fieldName := astField.GetName()
 					pos := astField.Pos
-					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), diagnostics.FileIDZero)
+					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), ctx.fileID())
 					ctx.PushError(diagnostics.NewDuplicateFieldError(
 						astModel.GetName(),
 						fieldName,
@@ -144,7 +144,7 @@ func HandleCompositeTypeFieldMap(
 			if existingField.MappedName != nil && *existingField.MappedName == *mappedName {
 				fieldName := astField.GetName()
 				pos := astField.Pos
-				span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), diagnostics.FileIDZero)
+				span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), ctx.fileID())
 				ctx.PushError(diagnostics.NewCompositeTypeDuplicateFieldError(
 					ct.GetName(),
 					ctx.interner.Get(*mappedName),
@@ -175,7 +175,7 @@ func HandleCompositeTypeFieldMap(
 			// Conflict with regular field name
 			fieldName := astField.GetName()
 			pos := astField.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), ctx.fileID())
 			ctx.PushError(diagnostics.NewCompositeTypeDuplicateFieldError(
 				ct.GetName(),
 				fieldName,
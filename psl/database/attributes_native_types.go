@@ -27,7 +27,7 @@ func HandleModelFieldNativeType(
 	}
 
 	pos := attr.Pos
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 	nativeType := &NativeTypeInfo{
 		Scope:     datasourceName,
 		TypeName:  typeName,
@@ -59,7 +59,7 @@ func HandleCompositeTypeFieldNativeType(
 	}
 
 	pos := attr.Pos
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 	nativeType := &NativeTypeInfo{
 		Scope:     datasourceName,
 		TypeName:  typeName,
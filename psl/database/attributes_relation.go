@@ -26,7 +26,7 @@ func HandleRelation(
 	// Handle "fields" argument
 	if fieldsExpr := ctx.VisitOptionalArg("fields"); fieldsExpr != nil {
 		pos := attr.Pos
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 		fields, err := resolveFieldArrayWithoutArgs(fieldsExpr, span, modelID, ctx)
 		if err == errFieldResolutionAlreadyDealtWith {
 			// Error already handled
@@ -44,7 +44,7 @@ func HandleRelation(
 		rf := &ctx.types.RelationFields[rfid]
 		if referencesExpr := ctx.VisitOptionalArg("references"); referencesExpr != nil {
 			pos := attr.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 			references, err := resolveFieldArrayWithoutArgs(referencesExpr, span, rf.ReferencedModel, ctx)
 			if err == errFieldResolutionAlreadyDealtWith {
 				// Error already handled
@@ -58,7 +58,7 @@ func HandleRelation(
 
 	// Handle "name" argument (optional, but if present must be a string)
 	if nameExpr := ctx.VisitOptionalArg("name"); nameExpr != nil {
-		name, ok := CoerceString(nameExpr, ctx.diagnostics)
+		name, ok := CoerceString(nameExpr, ctx.fileID(), ctx.diagnostics)
 		if ok {
 			if name == "" {
 				ctx.PushAttributeValidationError("A relation cannot have an empty name.")
@@ -73,7 +73,7 @@ func HandleRelation(
 
 	// Handle "map" argument
 	if mapExpr := ctx.VisitOptionalArg("map"); mapExpr != nil {
-		mappedName, ok := CoerceString(mapExpr, ctx.diagnostics)
+		mappedName, ok := CoerceString(mapExpr, ctx.fileID(), ctx.diagnostics)
 		if ok {
 			if mappedName == "" {
 				ctx.PushAttributeValidationError("The `map` argument cannot be an empty string.")
@@ -93,7 +93,7 @@ func HandleRelation(
 			if int(rfid) < len(ctx.types.RelationFields) {
 				ctx.types.RelationFields[rfid].OnDelete = &ReferentialActionInfo{
 					Action: *action,
-					Span:   getExpressionSpan(onDeleteExpr),
+					Span:   getExpressionSpan(onDeleteExpr, ctx.fileID()),
 				}
 			}
 		}
@@ -106,7 +106,7 @@ func HandleRelation(
 			if int(rfid) < len(ctx.types.RelationFields) {
 				ctx.types.RelationFields[rfid].OnUpdate = &ReferentialActionInfo{
 					Action: *action,
-					Span:   getExpressionSpan(onUpdateExpr),
+					Span:   getExpressionSpan(onUpdateExpr, ctx.fileID()),
 				}
 			}
 		}
@@ -145,7 +145,7 @@ func resolveFieldArrayWithoutArgs(
 		if elem == nil {
 			continue
 		}
-		fieldName, ok := CoerceConstant(elem, ctx.diagnostics)
+		fieldName, ok := CoerceConstant(elem, ctx.fileID(), ctx.diagnostics)
 		if !ok {
 			continue
 		}
@@ -217,7 +217,7 @@ func resolveFieldArrayWithoutArgs(
 
 // parseReferentialAction parses a referential action from an expression.
 func parseReferentialAction(expr v2ast.Expression, ctx *Context) *ReferentialAction {
-	actionName, ok := CoerceConstant(expr, ctx.diagnostics)
+	actionName, ok := CoerceConstant(expr, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
@@ -245,7 +245,7 @@ func parseReferentialAction(expr v2ast.Expression, ctx *Context) *ReferentialAct
 }
 
 // getExpressionSpan extracts the span from an expression.
-func getExpressionSpan(expr v2ast.Expression) diagnostics.Span {
+func getExpressionSpan(expr v2ast.Expression, fileID diagnostics.FileID) diagnostics.Span {
 	pos := expr.Span()
-	return diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+	return diagnostics.NewSpan(pos.Offset, pos.Offset+10, fileID)
 }
@@ -31,14 +31,14 @@ func visitSchemaAttribute(ctx *Context) *SchemaInfo {
 		return nil
 	}
 
-	name, ok := CoerceString(arg, ctx.diagnostics)
+	name, ok := CoerceString(arg, ctx.fileID(), ctx.diagnostics)
 	if !ok {
 		return nil
 	}
 
 	nameID := ctx.interner.Intern(name)
 	pos := arg.Span()
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), ctx.fileID())
 
 	return &SchemaInfo{
 		Name: nameID,
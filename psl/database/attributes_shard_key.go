@@ -24,7 +24,7 @@ func HandleModelShardKey(
 	}
 
 	pos := attr.Pos
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 	resolvedFields, resolveErr := resolveFieldArrayWithoutArgs(fieldsExpr, span, modelID, ctx)
 	if resolveErr == errFieldResolutionAlreadyDealtWith {
 		return
@@ -59,7 +59,7 @@ func HandleModelShardKey(
 
 	if len(fieldsThatAreNotRequired) > 0 && !modelAttrs.IsIgnored {
 		pos := attr.Pos
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attr.GetName()), ctx.fileID())
 		ctx.PushError(diagnostics.NewModelValidationError(
 			"The shard key definition refers to the optional fields: "+formatFieldNames(fieldsThatAreNotRequired)+". Shard key definitions must reference only required fields.",
 			"model",
@@ -70,7 +70,7 @@ func HandleModelShardKey(
 
 	if modelAttrs.ShardKey != nil {
 		pos := astModel.TopPos()
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astModel.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astModel.GetName()), ctx.fileID())
 		ctx.PushError(diagnostics.NewModelValidationError(
 			"Each model must have at most one shard key. You can't have `@shardKey` and `@@shardKey` at the same time.",
 			"model",
@@ -98,7 +98,7 @@ func HandleFieldShardKey(
 ) {
 	if modelAttrs.ShardKey != nil {
 		pos := astModel.TopPos()
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astModel.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astModel.GetName()), ctx.fileID())
 		ctx.PushError(diagnostics.NewModelValidationError(
 			"At most one field must be marked as the shard key with the `@shardKey` attribute.",
 			"model",
@@ -152,7 +152,7 @@ func ValidateShardKeyFieldArities(
 	if astField.Arity.IsOptional() || astField.Arity.IsList() {
 		// TODO: Get proper attribute span from AttributeId
 		pos := astField.Pos
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), modelID.FileID)
 		ctx.PushError(diagnostics.NewAttributeValidationError(
 			"Fields that are marked as shard keys must be required.",
 			"@shardKey",
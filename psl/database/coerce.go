@@ -11,7 +11,7 @@ import (
 
 // CoerceString coerces an expression to a string.
 // Returns the string value and true if successful, empty string and false otherwise.
-func CoerceString(expr v2ast.Expression, diags *diagnostics.Diagnostics) (string, bool) {
+func CoerceString(expr v2ast.Expression, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) (string, bool) {
 	if strVal, ok := expr.(*v2ast.StringValue); ok {
 		return strVal.GetValue(), true
 	}
@@ -19,7 +19,7 @@ func CoerceString(expr v2ast.Expression, diags *diagnostics.Diagnostics) (string
 	// Try to get span for error
 	var span diagnostics.Span
 	pos := expr.Span()
-	span = diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+	span = diagnostics.NewSpan(pos.Offset, pos.Offset+10, fileID)
 
 	diags.PushError(diagnostics.NewValueParserError(
 		"string",
@@ -31,7 +31,7 @@ func CoerceString(expr v2ast.Expression, diags *diagnostics.Diagnostics) (string
 
 // CoerceConstant coerces an expression to a constant string (identifier).
 // Returns the constant value and true if successful, empty string and false otherwise.
-func CoerceConstant(expr v2ast.Expression, diags *diagnostics.Diagnostics) (string, bool) {
+func CoerceConstant(expr v2ast.Expression, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) (string, bool) {
 	// In Prisma, constants are typically identifiers or constant values
 	if constVal, ok := expr.(*v2ast.ConstantValue); ok {
 		return constVal.Value, true
@@ -43,7 +43,7 @@ func CoerceConstant(expr v2ast.Expression, diags *diagnostics.Diagnostics) (stri
 	}
 
 	pos := expr.Span()
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, fileID)
 
 	diags.PushError(diagnostics.NewValueParserError(
 		"constant",
@@ -55,7 +55,7 @@ func CoerceConstant(expr v2ast.Expression, diags *diagnostics.Diagnostics) (stri
 
 // CoerceInteger coerces an expression to an integer.
 // Returns the integer value and true if successful, 0 and false otherwise.
-func CoerceInteger(expr v2ast.Expression, diags *diagnostics.Diagnostics) (int64, bool) {
+func CoerceInteger(expr v2ast.Expression, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) (int64, bool) {
 	// Try numeric value
 	if numVal, ok := expr.(*v2ast.NumericValue); ok {
 		if val, err := strconv.ParseInt(numVal.Value, 10, 64); err == nil {
@@ -71,7 +71,7 @@ func CoerceInteger(expr v2ast.Expression, diags *diagnostics.Diagnostics) (int64
 	}
 
 	pos := expr.Span()
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, fileID)
 
 	diags.PushError(diagnostics.NewValueParserError(
 		"numeric",
@@ -83,7 +83,7 @@ func CoerceInteger(expr v2ast.Expression, diags *diagnostics.Diagnostics) (int64
 
 // CoerceBoolean coerces an expression to a boolean.
 // Returns the boolean value and true if successful, false and false otherwise.
-func CoerceBoolean(expr v2ast.Expression, diags *diagnostics.Diagnostics) (bool, bool) {
+func CoerceBoolean(expr v2ast.Expression, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) (bool, bool) {
 	// Try constant value (true/false)
 	if constVal, ok := expr.(*v2ast.ConstantValue); ok {
 		if val, ok := constVal.AsBooleanValue(); ok {
@@ -99,7 +99,7 @@ func CoerceBoolean(expr v2ast.Expression, diags *diagnostics.Diagnostics) (bool,
 	}
 
 	pos := expr.Span()
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, fileID)
 
 	diags.PushError(diagnostics.NewValueParserError(
 		"boolean",
@@ -111,7 +111,7 @@ func CoerceBoolean(expr v2ast.Expression, diags *diagnostics.Diagnostics) (bool,
 
 // CoerceFloat coerces an expression to a float.
 // Returns the float value and true if successful, 0 and false otherwise.
-func CoerceFloat(expr v2ast.Expression, diags *diagnostics.Diagnostics) (float64, bool) {
+func CoerceFloat(expr v2ast.Expression, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) (float64, bool) {
 	// Try numeric value
 	if numVal, ok := expr.(*v2ast.NumericValue); ok {
 		if val, err := strconv.ParseFloat(numVal.Value, 64); err == nil {
@@ -127,7 +127,7 @@ func CoerceFloat(expr v2ast.Expression, diags *diagnostics.Diagnostics) (float64
 	}
 
 	pos := expr.Span()
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, fileID)
 
 	diags.PushError(diagnostics.NewValueParserError(
 		"float",
@@ -139,7 +139,7 @@ func CoerceFloat(expr v2ast.Expression, diags *diagnostics.Diagnostics) (float64
 
 // CoerceFunction coerces an expression to a function call.
 // Returns the function name, arguments (as expressions), span and true if successful.
-func CoerceFunction(expr v2ast.Expression, diags *diagnostics.Diagnostics) (string, []v2ast.Expression, diagnostics.Span, bool) {
+func CoerceFunction(expr v2ast.Expression, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) (string, []v2ast.Expression, diagnostics.Span, bool) {
 	if funcCall, ok := expr.(*v2ast.FunctionCall); ok {
 		var args []v2ast.Expression
 		if funcCall.Arguments != nil && len(funcCall.Arguments.Arguments) > 0 {
@@ -151,12 +151,12 @@ func CoerceFunction(expr v2ast.Expression, diags *diagnostics.Diagnostics) (stri
 			}
 		}
 		pos := funcCall.Span()
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(funcCall.Name), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(funcCall.Name), fileID)
 		return funcCall.Name, args, span, true
 	}
 
 	pos := expr.Span()
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, fileID)
 
 	diags.PushError(diagnostics.NewValueParserError(
 		"function",
@@ -54,8 +54,22 @@ type AttributesValidationState struct {
 	attribute *AttributeId
 	// Unused attributes (by AttributeId)
 	unusedAttributes map[AttributeId]bool
-	// Arguments by name (nil key = unnamed argument)
-	args map[*StringId]int
+	// Arguments by name (the zero key is the unnamed argument)
+	args map[argKey]int
+}
+
+// argKey identifies an attribute argument by its name.
+type argKey struct {
+	named bool
+	name  StringId
+}
+
+// unnamedArg is the key of the unnamed argument of an attribute.
+var unnamedArg = argKey{}
+
+// namedArg returns the key of the argument called name.
+func namedArg(name StringId) argKey {
+	return argKey{named: true, name: name}
 }
 
 // NewContext creates a new validation context.
@@ -78,7 +92,7 @@ func NewContext(
 		extensionTypes: extensionTypes,
 		attributes: AttributesValidationState{
 			unusedAttributes: make(map[AttributeId]bool),
-			args:             make(map[*StringId]int),
+			args:             make(map[argKey]int),
 		},
 		mappedModelScalarFieldNames: make(map[ModelFieldKeyByID]uint32),
 		mappedCompositeTypeNames:    make(map[CompositeTypeFieldKeyByID]uint32),
@@ -33,7 +33,7 @@ func (ctx *Context) VisitOptionalSingleAttr(name string) bool {
 			} else {
 				// Duplicate found - report error for all of them
 				pos := entry.Attr.Pos
-				span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), diagnostics.FileIDZero)
+				span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), ctx.fileID())
 				ctx.PushError(diagnostics.NewDuplicateAttributeError(
 					name,
 					span,
@@ -54,7 +54,7 @@ func (ctx *Context) VisitOptionalSingleAttr(name string) bool {
 		if entry.Attr != nil && entry.Attr.GetName() == name && entry.ID != foundAttr.ID {
 			hasDuplicates = true
 			pos := entry.Attr.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), ctx.fileID())
 			ctx.PushError(diagnostics.NewDuplicateAttributeError(
 				name,
 				span,
@@ -107,13 +107,13 @@ func (ctx *Context) VisitDefaultArg(name string) (v2ast.Expression, int, error)
 	nameID := ctx.interner.Intern(name)
 
 	// Try named argument first
-	namedIdx, hasNamed := ctx.attributes.args[&nameID]
+	namedIdx, hasNamed := ctx.attributes.args[namedArg(nameID)]
 	// Try unnamed argument
-	unnamedIdx, hasUnnamed := ctx.attributes.args[nil]
+	unnamedIdx, hasUnnamed := ctx.attributes.args[unnamedArg]
 
 	if hasNamed && !hasUnnamed {
 		// Only named argument
-		delete(ctx.attributes.args, &nameID)
+		delete(ctx.attributes.args, namedArg(nameID))
 		arg := ctx.argAt(namedIdx)
 		if arg == nil {
 			return nil, 0, fmt.Errorf("invalid argument index")
@@ -121,7 +121,7 @@ func (ctx *Context) VisitDefaultArg(name string) (v2ast.Expression, int, error)
 		return arg.Value, namedIdx, nil
 	} else if !hasNamed && hasUnnamed {
 		// Only unnamed argument
-		delete(ctx.attributes.args, nil)
+		delete(ctx.attributes.args, unnamedArg)
 		arg := ctx.argAt(unnamedIdx)
 		if arg == nil {
 			return nil, 0, fmt.Errorf("invalid argument index")
@@ -132,7 +132,7 @@ func (ctx *Context) VisitDefaultArg(name string) (v2ast.Expression, int, error)
 		arg := ctx.argAt(namedIdx)
 		if arg != nil && arg.Name != nil {
 			pos := arg.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), ctx.fileID())
 			ctx.PushError(diagnostics.NewDuplicateDefaultArgumentError(
 				name,
 				span,
@@ -143,7 +143,7 @@ func (ctx *Context) VisitDefaultArg(name string) (v2ast.Expression, int, error)
 		// Neither present - error
 		if attr := ctx.currentAttribute(); attr != nil {
 			pos := attr.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(name), ctx.fileID())
 			ctx.PushError(diagnostics.NewArgumentNotFoundError(
 				name,
 				span,
@@ -157,11 +157,11 @@ func (ctx *Context) VisitDefaultArg(name string) (v2ast.Expression, int, error)
 // Returns the expression if found, nil otherwise.
 func (ctx *Context) VisitOptionalArg(name string) v2ast.Expression {
 	nameID := ctx.interner.Intern(name)
-	idx, ok := ctx.attributes.args[&nameID]
+	idx, ok := ctx.attributes.args[namedArg(nameID)]
 	if !ok {
 		return nil
 	}
-	delete(ctx.attributes.args, &nameID)
+	delete(ctx.attributes.args, namedArg(nameID))
 	arg := ctx.argAt(idx)
 	return arg.Value
 }
@@ -186,7 +186,7 @@ func (ctx *Context) ValidateVisitedArguments() {
 				arg := attr.Arguments.Arguments[argIdx]
 				if arg != nil {
 					pos := arg.Pos
-					span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+					span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, ctx.fileID())
 					ctx.PushError(diagnostics.NewUnusedArgumentError(span))
 				}
 			}
@@ -207,7 +207,7 @@ func (ctx *Context) ValidateVisitedAttributes() {
 		if attr := ctx.getAttribute(attrID); attr != nil {
 			attrName := attr.GetName()
 			pos := attr.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attrName), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attrName), attrID.FileID)
 			ctx.PushError(diagnostics.NewAttributeNotKnownError(
 				attrName,
 				span,
@@ -242,7 +242,7 @@ func (ctx *Context) PushAttributeValidationError(message string) {
 	attr := ctx.currentAttribute()
 	attrName := attr.GetName()
 	pos := attr.Pos
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attrName), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(attrName), ctx.fileID())
 	ctx.PushError(diagnostics.NewAttributeValidationError(
 		message,
 		"@"+attrName,
@@ -275,9 +275,9 @@ func (ctx *Context) setAttributes(container AttributeContainer) {
 	}
 }
 
-// getAttributesFromContainer gets attributes from an attribute container.
-// This is a simplified version - proper implementation would need to track
-// container types (model, field, enum, etc.) in the AttributeContainer.
+// getAttributesFromContainer gets attributes from an attribute container:
+// the block attributes of a model or enum, or the attributes of a field or
+// enum value.
 func (ctx *Context) getAttributesFromContainer(container AttributeContainer) []*v2ast.Attribute {
 	// Find the file
 	var file *FileEntry
@@ -291,36 +291,55 @@ func (ctx *Context) getAttributesFromContainer(container AttributeContainer) []*
 		return nil
 	}
 
-	// Get attributes based on container ID
-	// For now, we'll iterate through tops to find the right container
-	// TODO: Implement proper container type tracking
-
-	// Simplified: try to find model by index
-	modelCount := 0
-	for _, top := range file.AST.Tops {
-		if model, ok := top.(*v2ast.Model); ok {
-			if uint32(modelCount) == container.ID {
-				// Convert BlockAttribute to Attribute
-				result := make([]*v2ast.Attribute, len(model.BlockAttributes))
-				for i, blockAttr := range model.BlockAttributes {
-					if blockAttr != nil {
-						// Create Attribute from BlockAttribute (they have the same structure)
-						result[i] = &v2ast.Attribute{
-							Pos:       blockAttr.Pos,
-							Name:      blockAttr.Name,
-							Arguments: blockAttr.Arguments,
-						}
-					}
-				}
-				return result
+	topID, memberID, isMember := decodeAttributeContainer(container)
+	if int(topID) >= len(file.AST.Tops) {
+		return nil
+	}
+
+	switch top := file.AST.Tops[topID].(type) {
+	case *v2ast.Model:
+		if isMember {
+			if int(memberID) >= len(top.Fields) || top.Fields[memberID] == nil {
+				return nil
+			}
+			return top.Fields[memberID].Attributes
+		}
+		return blockAttributesAsAttributes(top.BlockAttributes)
+	case *v2ast.Enum:
+		if isMember {
+			if int(memberID) >= len(top.Values) || top.Values[memberID] == nil {
+				return nil
 			}
-			modelCount++
+			return top.Values[memberID].Attributes
+		}
+		return blockAttributesAsAttributes(top.BlockAttributes)
+	case *v2ast.CompositeType:
+		if isMember {
+			if int(memberID) >= len(top.Fields) || top.Fields[memberID] == nil {
+				return nil
+			}
+			return top.Fields[memberID].Attributes
 		}
 	}
-
 	return nil
 }
 
+// blockAttributesAsAttributes converts block attributes to attributes, which
+// have the same structure.
+func blockAttributesAsAttributes(blockAttrs []*v2ast.BlockAttribute) []*v2ast.Attribute {
+	result := make([]*v2ast.Attribute, len(blockAttrs))
+	for i, blockAttr := range blockAttrs {
+		if blockAttr != nil {
+			result[i] = &v2ast.Attribute{
+				Pos:       blockAttr.Pos,
+				Name:      blockAttr.Name,
+				Arguments: blockAttr.Arguments,
+			}
+		}
+	}
+	return result
+}
+
 // iterAttributes iterates over all attributes in the current container.
 func (ctx *Context) iterAttributes() []AttributeEntry {
 	if ctx.attributes.attributes == nil {
@@ -359,7 +378,7 @@ func (ctx *Context) setAttribute(attrID AttributeId, attr *v2ast.Attribute) bool
 
 	// Validate arguments
 	ctx.attributes.attribute = &attrID
-	ctx.attributes.args = make(map[*StringId]int)
+	ctx.attributes.args = make(map[argKey]int)
 
 	// Process arguments
 	var unnamedArguments []string
@@ -368,34 +387,33 @@ func (ctx *Context) setAttribute(attrID AttributeId, attr *v2ast.Attribute) bool
 			if arg == nil {
 				continue
 			}
-		var argName *StringId
-		if arg.Name != nil {
-			nameID := ctx.interner.Intern(arg.Name.Name)
-			argName = &nameID
-		}
+			argName := unnamedArg
+			if arg.Name != nil {
+				argName = namedArg(ctx.interner.Intern(arg.Name.Name))
+			}
 
-		// Check for duplicates
-		if existingIdx, exists := ctx.attributes.args[argName]; exists {
-			if argName == nil {
-				// Unnamed argument duplicate
+			// Check for duplicates
+			if existingIdx, exists := ctx.attributes.args[argName]; exists {
+				if !argName.named {
+					// Unnamed argument duplicate
 					if len(unnamedArguments) == 0 && existingIdx < len(attr.Arguments.Arguments) {
 						existingArg := attr.Arguments.Arguments[existingIdx]
 						if existingArg != nil {
-					unnamedArguments = append(unnamedArguments, exprToString(existingArg.Value))
+							unnamedArguments = append(unnamedArguments, exprToString(existingArg.Value))
 						}
-				}
-				unnamedArguments = append(unnamedArguments, exprToString(arg.Value))
-			} else {
-				// Named argument duplicate
+					}
+					unnamedArguments = append(unnamedArguments, exprToString(arg.Value))
+				} else {
+					// Named argument duplicate
 					pos := arg.Pos
-					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(arg.Name.Name), diagnostics.FileIDZero)
-				ctx.PushError(diagnostics.NewDuplicateArgumentError(
-					arg.Name.Name,
+					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(arg.Name.Name), ctx.fileID())
+					ctx.PushError(diagnostics.NewDuplicateArgumentError(
+						arg.Name.Name,
 						span,
-				))
-			}
-		} else {
-			ctx.attributes.args[argName] = i
+					))
+				}
+			} else {
+				ctx.attributes.args[argName] = i
 			}
 		}
 	}
@@ -413,7 +431,7 @@ func (ctx *Context) setAttribute(attrID AttributeId, attr *v2ast.Attribute) bool
 // discardArguments discards the current attribute's arguments.
 func (ctx *Context) discardArguments() {
 	ctx.attributes.attribute = nil
-	ctx.attributes.args = make(map[*StringId]int)
+	ctx.attributes.args = make(map[argKey]int)
 }
 
 // currentAttribute returns the current attribute being validated.
@@ -426,6 +444,14 @@ func (ctx *Context) currentAttribute() *v2ast.Attribute {
 	return ctx.getAttribute(attrID)
 }
 
+// fileID returns the ID of the file of the attributes being validated.
+func (ctx *Context) fileID() diagnostics.FileID {
+	if ctx.attributes.attributes == nil {
+		return diagnostics.FileIDZero
+	}
+	return ctx.attributes.attributes.FileID
+}
+
 // getAttribute gets an attribute by its ID.
 func (ctx *Context) getAttribute(attrID AttributeId) *v2ast.Attribute {
 	attrs := ctx.getAttributesFromContainer(attrID.Container)
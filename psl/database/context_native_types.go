@@ -46,7 +46,7 @@ func (ctx *Context) VisitDatasourceScoped() (StringId, StringId, AttributeId, bo
 			if nativeTypeAttr != nil {
 				// Convert lexer.Position to diagnostics.Span
 				pos := entry.Attr.Pos
-				span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(datasourceName), diagnostics.FileIDZero)
+				span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(datasourceName), entry.ID.FileID)
 				ctx.PushError(diagnostics.NewDuplicateAttributeError(
 					datasourceName,
 					span,
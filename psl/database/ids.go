@@ -23,14 +23,36 @@ type TopId = InFile
 
 // AttributeContainer represents an attribute container with file ID.
 // The ID field encodes the container type and index:
-// - For models: ID is the model index
-// - For fields: ID encodes (model_index << 16) | field_index
-// - For enums: ID is the enum index
-// - For enum values: ID encodes (enum_index << 16) | value_index
-// - For composite types: ID is the composite type index
-// - For composite type fields: ID encodes (composite_type_index << 16) | field_index
+// - For models, enums and composite types: ID is the top index
+// - For fields and enum values: ID is flag | (top_index << 16) | member_index
 type AttributeContainer = InFile
 
+// containerMemberFlag marks an attribute container as a field of a model or
+// composite type, or a value of an enum.
+const containerMemberFlag uint32 = 1 << 31
+
+// TopAttributeContainer returns the container of the block attributes of
+// a model, enum or composite type.
+func TopAttributeContainer(fileID diagnostics.FileID, topID uint32) AttributeContainer {
+	return AttributeContainer{FileID: fileID, ID: topID}
+}
+
+// MemberAttributeContainer returns the container of the attributes of a
+// field of a model or composite type, or of a value of an enum.
+func MemberAttributeContainer(fileID diagnostics.FileID, topID, memberID uint32) AttributeContainer {
+	return AttributeContainer{FileID: fileID, ID: containerMemberFlag | topID<<16 | memberID&0xffff}
+}
+
+// decodeAttributeContainer returns the top index of a container, and the
+// member index if it is a field or enum value container.
+func decodeAttributeContainer(container AttributeContainer) (topID uint32, memberID uint32, isMember bool) {
+	if container.ID&containerMemberFlag == 0 {
+		return container.ID, 0, false
+	}
+	id := container.ID &^ containerMemberFlag
+	return id >> 16, id & 0xffff, true
+}
+
 // AttributeId represents an attribute identifier.
 // It contains the container and the index of the attribute within that container.
 type AttributeId struct {
@@ -353,16 +353,12 @@ func getFieldArity(field *v2ast.Field) FieldArity {
 func getModelFromID(modelID ModelId, ctx *Context) *v2ast.Model {
 	for _, file := range ctx.asts.files {
 		if file.FileID == modelID.FileID {
-			// Find the model by index
-			modelCount := 0
-			for _, top := range file.AST.Tops {
-				if model, ok := top.(*v2ast.Model); ok {
-					if uint32(modelCount) == modelID.ID {
-						return model
-					}
-					modelCount++
-				}
+			// Model IDs are indexes into the top-level declarations
+			if int(modelID.ID) >= len(file.AST.Tops) {
+				return nil
 			}
+			model, _ := file.AST.Tops[modelID.ID].(*v2ast.Model)
+			return model
 		}
 	}
 	return nil
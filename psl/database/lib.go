@@ -33,7 +33,7 @@ func NewFiles(files []core.SourceFile, diags *diagnostics.Diagnostics) Files {
 	entries := make([]FileEntry, len(files))
 	for i, source := range files {
 		fileID := diagnostics.FileID(i)
-		ast, parseDiags := parsing.ParseSchemaFromSourceFileV2(source)
+		ast, parseDiags := parsing.ParseSourceFile(source, fileID)
 
 		// Merge diagnostics
 		for _, err := range parseDiags.Errors() {
@@ -76,11 +76,10 @@ func (f Files) Iter() []FileEntry {
 func (f Files) RenderDiagnostics(diags *diagnostics.Diagnostics) string {
 	var result strings.Builder
 
-	for _, err := range diags.Errors() {
-		fileEntry := f.Get(err.Span().FileID)
-		if fileEntry != nil {
-			result.WriteString(diags.ToPrettyString(fileEntry.Name, fileEntry.Source.Data))
-		}
+	for _, fileEntry := range f.files {
+		fileDiags := diags.ForFile(fileEntry.FileID)
+		result.WriteString(fileDiags.WarningsToPrettyString(fileEntry.Name, fileEntry.Source.Data))
+		result.WriteString(fileDiags.ToPrettyString(fileEntry.Name, fileEntry.Source.Data))
 	}
 
 	return result.String()
@@ -239,25 +238,25 @@ func (pd *ParserDatabase) validateBasic(diags *diagnostics.Diagnostics) {
 				name = t.GetName()
 				// Convert lexer.Position to diagnostics.Span
 				pos := t.TopPos()
-				span = diagnostics.NewSpan(pos.Offset, pos.Offset+len(t.GetName()), diagnostics.FileIDZero)
+				span = diagnostics.NewSpan(pos.Offset, pos.Offset+len(t.GetName()), file.FileID)
 
 				// Validate model fields
-				pd.validateModel(t, diags)
+				pd.validateModel(t, file.FileID, diags)
 			case *v2ast.Enum:
 				name = t.GetName()
 				pos := t.TopPos()
-				span = diagnostics.NewSpan(pos.Offset, pos.Offset+len(t.GetName()), diagnostics.FileIDZero)
+				span = diagnostics.NewSpan(pos.Offset, pos.Offset+len(t.GetName()), file.FileID)
 
 				// Validate enum values
-				pd.validateEnum(t, diags)
+				pd.validateEnum(t, file.FileID, diags)
 			case *v2ast.SourceConfig:
 				name = t.GetName()
 				pos := t.TopPos()
-				span = diagnostics.NewSpan(pos.Offset, pos.Offset+len(t.GetName()), diagnostics.FileIDZero)
+				span = diagnostics.NewSpan(pos.Offset, pos.Offset+len(t.GetName()), file.FileID)
 			case *v2ast.GeneratorConfig:
 				name = t.GetName()
 				pos := t.TopPos()
-				span = diagnostics.NewSpan(pos.Offset, pos.Offset+len(t.GetName()), diagnostics.FileIDZero)
+				span = diagnostics.NewSpan(pos.Offset, pos.Offset+len(t.GetName()), file.FileID)
 			}
 
 			if name != "" {
@@ -274,7 +273,7 @@ func (pd *ParserDatabase) validateBasic(diags *diagnostics.Diagnostics) {
 }
 
 // validateModel performs validation on a model.
-func (pd *ParserDatabase) validateModel(model *v2ast.Model, diags *diagnostics.Diagnostics) {
+func (pd *ParserDatabase) validateModel(model *v2ast.Model, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) {
 	fieldNames := make(map[string]bool)
 	hasIdField := false
 
@@ -283,11 +282,11 @@ func (pd *ParserDatabase) validateModel(model *v2ast.Model, diags *diagnostics.D
 			continue
 		}
 		fieldName := field.GetName()
-		
+
 		// Check for duplicate field names
 		if fieldNames[fieldName] {
 			pos := field.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(fieldName), fileID)
 			diags.PushError(diagnostics.NewDuplicateFieldError(
 				model.GetName(),
 				fieldName,
@@ -301,7 +300,7 @@ func (pd *ParserDatabase) validateModel(model *v2ast.Model, diags *diagnostics.D
 		typeName := field.GetTypeName()
 		if typeName == "" {
 			pos := field.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+10, fileID)
 			diags.PushError(diagnostics.NewFieldValidationError(
 				"Field type cannot be empty",
 				"model",
@@ -311,29 +310,36 @@ func (pd *ParserDatabase) validateModel(model *v2ast.Model, diags *diagnostics.D
 			))
 		}
 
-		// Check for @id attribute
+		// Check for @id and @unique attributes
 		for _, attr := range field.Attributes {
-			if attr != nil && attr.GetName() == "id" {
+			if attr != nil && (attr.GetName() == "id" || attr.GetName() == "unique") {
 				hasIdField = true
 			}
 		}
+	}
 
-		// Models should have at least one @id field
-		if !hasIdField && len(model.Fields) > 0 {
-			pos := model.TopPos()
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(model.GetName()), diagnostics.FileIDZero)
-			diags.PushError(diagnostics.NewModelValidationError(
-				"Model must have at least one field with @id attribute",
-				"model",
-				model.GetName(),
-				span,
-			))
+	// @@id and @@unique are unique criteria as well
+	for _, attr := range model.BlockAttributes {
+		if attr != nil && (attr.GetName() == "id" || attr.GetName() == "unique") {
+			hasIdField = true
 		}
 	}
+
+	// Models should have at least one unique criteria
+	if !hasIdField && len(model.Fields) > 0 {
+		pos := model.TopPos()
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(model.GetName()), fileID)
+		diags.PushError(diagnostics.NewModelValidationError(
+			"Model must have at least one field with @id attribute",
+			"model",
+			model.GetName(),
+			span,
+		))
+	}
 }
 
 // validateEnum performs validation on an enum.
-func (pd *ParserDatabase) validateEnum(enum *v2ast.Enum, diags *diagnostics.Diagnostics) {
+func (pd *ParserDatabase) validateEnum(enum *v2ast.Enum, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) {
 	valueNames := make(map[string]bool)
 
 	for _, value := range enum.Values {
@@ -341,11 +347,11 @@ func (pd *ParserDatabase) validateEnum(enum *v2ast.Enum, diags *diagnostics.Diag
 			continue
 		}
 		valueName := value.GetName()
-		
+
 		// Check for duplicate enum values
 		if valueNames[valueName] {
 			pos := value.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(valueName), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(valueName), fileID)
 			diags.PushError(diagnostics.NewValidationError(
 				fmt.Sprintf("Duplicate enum value: %s", valueName),
 				span,
@@ -456,17 +462,11 @@ func (pd *ParserDatabase) getModelFromID(modelID ModelId) *v2ast.Model {
 		return nil
 	}
 
-	modelCount := 0
-	for _, top := range file.AST.Tops {
-		if model, ok := top.(*v2ast.Model); ok {
-			if uint32(modelCount) == modelID.ID {
-				return model
-			}
-			modelCount++
-		}
+	if int(modelID.ID) >= len(file.AST.Tops) {
+		return nil
 	}
-
-	return nil
+	model, _ := file.AST.Tops[modelID.ID].(*v2ast.Model)
+	return model
 }
 
 // GetString returns the string for the given StringId.
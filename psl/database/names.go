@@ -70,10 +70,10 @@ func ResolveNames(db *ParserDatabase, diags *diagnostics.Diagnostics) Names {
 			case *v2ast.Model:
 				identifierName = t.GetName()
 				pos := t.TopPos()
-				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), diagnostics.FileIDZero)
+				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), file.FileID)
 				topType = "Model"
 				validateIdentifier(identifierName, identifierSpan, "Model", diags)
-				validateModelName(t, "model", diags)
+				validateModelName(t, "model", file.FileID, diags)
 
 				// Validate model fields
 				for j, field := range t.Fields {
@@ -82,7 +82,7 @@ func ResolveNames(db *ParserDatabase, diags *diagnostics.Diagnostics) Names {
 					}
 					fieldName := field.GetName()
 					fieldPos := field.Pos
-					fieldSpan := diagnostics.NewSpan(fieldPos.Offset, fieldPos.Offset+len(fieldName), diagnostics.FileIDZero)
+					fieldSpan := diagnostics.NewSpan(fieldPos.Offset, fieldPos.Offset+len(fieldName), file.FileID)
 					validateIdentifier(fieldName, fieldSpan, "Field", diags)
 					fieldNameID := db.interner.Intern(fieldName)
 					key := ModelFieldKey{
@@ -100,13 +100,44 @@ func ResolveNames(db *ParserDatabase, diags *diagnostics.Diagnostics) Names {
 						names.ModelFields[key] = uint32(j)
 					}
 				}
+			case *v2ast.CompositeType:
+				identifierName = t.GetName()
+				pos := t.TopPos()
+				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), file.FileID)
+				topType = "Composite type"
+				validateIdentifier(identifierName, identifierSpan, "Composite type", diags)
+
+				// Validate composite type fields
+				for j, field := range t.Fields {
+					if field == nil {
+						continue
+					}
+					fieldName := field.GetName()
+					fieldPos := field.Pos
+					fieldSpan := diagnostics.NewSpan(fieldPos.Offset, fieldPos.Offset+len(fieldName), file.FileID)
+					validateIdentifier(fieldName, fieldSpan, "Field", diags)
+					key := CompositeTypeFieldKey{
+						CompositeTypeID: CompositeTypeId(topID),
+						NameID:          db.interner.Intern(fieldName),
+					}
+					if _, exists := names.CompositeTypeFields[key]; exists {
+						diags.PushError(diagnostics.NewDuplicateFieldError(
+							t.GetName(),
+							fieldName,
+							"composite type",
+							fieldSpan,
+						))
+					} else {
+						names.CompositeTypeFields[key] = uint32(j)
+					}
+				}
 			case *v2ast.Enum:
 				identifierName = t.GetName()
 				pos := t.TopPos()
-				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), diagnostics.FileIDZero)
+				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), file.FileID)
 				topType = "Enum"
 				validateIdentifier(identifierName, identifierSpan, "Enum", diags)
-				validateEnumName(t, diags)
+				validateEnumName(t, file.FileID, diags)
 
 				// Validate enum values
 				tmpNames = make(map[string]bool)
@@ -116,7 +147,7 @@ func ResolveNames(db *ParserDatabase, diags *diagnostics.Diagnostics) Names {
 					}
 					valueName := value.GetName()
 					valuePos := value.Pos
-					valueSpan := diagnostics.NewSpan(valuePos.Offset, valuePos.Offset+len(valueName), diagnostics.FileIDZero)
+					valueSpan := diagnostics.NewSpan(valuePos.Offset, valuePos.Offset+len(valueName), file.FileID)
 					validateIdentifier(valueName, valueSpan, "Enum Value", diags)
 					if tmpNames[valueName] {
 						diags.PushError(diagnostics.NewDuplicateEnumValueError(
@@ -130,15 +161,15 @@ func ResolveNames(db *ParserDatabase, diags *diagnostics.Diagnostics) Names {
 			case *v2ast.SourceConfig:
 				identifierName = t.GetName()
 				pos := t.TopPos()
-				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), diagnostics.FileIDZero)
+				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), file.FileID)
 				topType = "Datasource"
-				checkForDuplicateProperties(top, t.Properties, tmpNames, diags)
+				checkForDuplicateProperties(top, t.Properties, tmpNames, file.FileID, diags)
 			case *v2ast.GeneratorConfig:
 				identifierName = t.GetName()
 				pos := t.TopPos()
-				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), diagnostics.FileIDZero)
+				identifierSpan = diagnostics.NewSpan(pos.Offset, pos.Offset+len(identifierName), file.FileID)
 				topType = "Generator"
-				checkForDuplicateProperties(top, t.Properties, tmpNames, diags)
+				checkForDuplicateProperties(top, t.Properties, tmpNames, file.FileID, diags)
 			}
 
 			if identifierName != "" {
@@ -198,11 +229,11 @@ func validateIdentifier(name string, span diagnostics.Span, schemaItem string, d
 }
 
 // validateModelName validates a model name.
-func validateModelName(model *v2ast.Model, containerType string, diags *diagnostics.Diagnostics) {
+func validateModelName(model *v2ast.Model, containerType string, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) {
 	modelName := model.GetName()
 	if IsReservedTypeName(modelName) {
 		pos := model.TopPos()
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(modelName), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(modelName), fileID)
 		diags.PushError(diagnostics.NewModelValidationError(
 			"The "+containerType+" name `"+modelName+"` is invalid. It is a reserved name. Please change it. Read more at https://pris.ly/d/naming-models",
 			"model",
@@ -213,11 +244,11 @@ func validateModelName(model *v2ast.Model, containerType string, diags *diagnost
 }
 
 // validateEnumName validates an enum name.
-func validateEnumName(enum *v2ast.Enum, diags *diagnostics.Diagnostics) {
+func validateEnumName(enum *v2ast.Enum, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) {
 	enumName := enum.GetName()
 	if IsReservedTypeName(enumName) {
 		pos := enum.TopPos()
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(enumName), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(enumName), fileID)
 		diags.PushError(diagnostics.NewEnumValidationError(
 			"The enum name `"+enumName+"` is invalid. It is a reserved name. Please change it. Read more at https://www.prisma.io/docs/reference/tools-and-interfaces/prisma-schema/data-model#naming-enums",
 			enumName,
@@ -227,7 +258,7 @@ func validateEnumName(enum *v2ast.Enum, diags *diagnostics.Diagnostics) {
 }
 
 // checkForDuplicateProperties checks for duplicate properties in config blocks.
-func checkForDuplicateProperties(top v2ast.Top, props []*v2ast.ConfigBlockProperty, tmpNames map[string]bool, diags *diagnostics.Diagnostics) {
+func checkForDuplicateProperties(top v2ast.Top, props []*v2ast.ConfigBlockProperty, tmpNames map[string]bool, fileID diagnostics.FileID, diags *diagnostics.Diagnostics) {
 	clearMap(tmpNames)
 	for _, prop := range props {
 		if prop == nil {
@@ -236,7 +267,7 @@ func checkForDuplicateProperties(top v2ast.Top, props []*v2ast.ConfigBlockProper
 		propName := prop.GetName()
 		if tmpNames[propName] {
 			pos := prop.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(propName), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(propName), fileID)
 			diags.PushError(diagnostics.NewDuplicateConfigKeyError(
 				getTopType(top)+" \""+getName(top)+"\"",
 				propName,
@@ -254,6 +285,8 @@ func getTopType(top v2ast.Top) string {
 		return "model"
 	case *v2ast.Enum:
 		return "enum"
+	case *v2ast.CompositeType:
+		return "composite type"
 	case *v2ast.SourceConfig:
 		return "datasource"
 	case *v2ast.GeneratorConfig:
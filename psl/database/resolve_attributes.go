@@ -49,13 +49,7 @@ func visitRelationFieldAttributes(rfid RelationFieldId, ctx *Context) {
 		return
 	}
 
-	// Create attribute container for field
-	// TODO: Properly encode field container in AttributeContainer
-	container := AttributeContainer{
-		FileID: rf.ModelID.FileID,
-		ID:     rf.ModelID.ID, // This should encode model + field
-	}
-	ctx.VisitAttributes(container)
+	ctx.VisitAttributes(MemberAttributeContainer(rf.ModelID.FileID, rf.ModelID.ID, rf.FieldID))
 
 	// @ignore
 	if ctx.VisitOptionalSingleAttr("ignore") {
@@ -121,12 +115,7 @@ func resolveModelAttributes(modelID ModelId, ctx *Context) {
 	}
 
 	// Then resolve model-level attributes
-	// Create attribute container for model
-	container := AttributeContainer{
-		FileID: modelID.FileID,
-		ID:     modelID.ID,
-	}
-	ctx.VisitAttributes(container)
+	ctx.VisitAttributes(TopAttributeContainer(modelID.FileID, modelID.ID))
 
 	// @@ignore
 	if ctx.VisitOptionalSingleAttr("ignore") {
@@ -200,13 +189,7 @@ func visitScalarFieldAttributes(sfid ScalarFieldId, modelAttrs *ModelAttributes,
 		return
 	}
 
-	// Create attribute container for field
-	// TODO: Properly encode field container in AttributeContainer
-	container := AttributeContainer{
-		FileID: sf.ModelID.FileID,
-		ID:     sf.ModelID.ID, // This should encode model + field
-	}
-	ctx.VisitAttributes(container)
+	ctx.VisitAttributes(MemberAttributeContainer(sf.ModelID.FileID, sf.ModelID.ID, sf.FieldID))
 
 	// @map
 	if ctx.VisitOptionalSingleAttr("map") {
@@ -226,6 +209,12 @@ func visitScalarFieldAttributes(sfid ScalarFieldId, modelAttrs *ModelAttributes,
 		ctx.ValidateVisitedArguments()
 	}
 
+	// @unique
+	if ctx.VisitOptionalSingleAttr("unique") {
+		HandleFieldUnique(sfid, modelAttrs, ctx)
+		ctx.ValidateVisitedArguments()
+	}
+
 	// @default
 	if ctx.VisitOptionalSingleAttr("default") {
 		HandleModelFieldDefault(sfid, sf.ModelID, sf.FieldID, sf.Type, ctx)
@@ -264,11 +253,7 @@ func resolveEnumAttributes(enumID EnumId, astEnum *v2ast.Enum, ctx *Context) {
 	for i := range astEnum.Values {
 		valueID := uint32(i)
 		// Visit attributes for this enum value
-		container := AttributeContainer{
-			FileID: enumID.FileID,
-			ID:     enumID.ID, // TODO: Properly encode enum + value in container
-		}
-		ctx.VisitAttributes(container)
+		ctx.VisitAttributes(MemberAttributeContainer(enumID.FileID, enumID.ID, valueID))
 
 		// Process @map attribute on enum value
 		if ctx.VisitOptionalSingleAttr("map") {
@@ -283,11 +268,7 @@ func resolveEnumAttributes(enumID EnumId, astEnum *v2ast.Enum, ctx *Context) {
 	}
 
 	// Process enum-level attributes
-	container := AttributeContainer{
-		FileID: enumID.FileID,
-		ID:     enumID.ID,
-	}
-	ctx.VisitAttributes(container)
+	ctx.VisitAttributes(TopAttributeContainer(enumID.FileID, enumID.ID))
 
 	// @@map
 	if ctx.VisitOptionalSingleAttr("map") {
@@ -326,12 +307,7 @@ func resolveCompositeTypeAttributes(ctID CompositeTypeId, astCT *v2ast.Composite
 		}
 
 		// Visit attributes for composite type field
-		// TODO: Properly encode field container in AttributeContainer
-		container := AttributeContainer{
-			FileID: ctID.FileID,
-			ID:     ctID.ID, // This should encode composite type + field
-		}
-		ctx.VisitAttributes(container)
+		ctx.VisitAttributes(MemberAttributeContainer(ctID.FileID, ctID.ID, fieldID))
 
 		// Native types (e.g., @db.Text)
 		if ctf.Type.BuiltInScalar != nil {
@@ -72,7 +72,7 @@ func visitModel(modelID ModelId, astModel *v2ast.Model, ctx *Context) {
 
 				if strings.EqualFold(name, typeName) {
 					pos := astField.Pos
-					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), diagnostics.FileIDZero)
+					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), modelID.FileID)
 					ctx.PushError(diagnostics.NewTypeForCaseNotFoundError(
 						typeName,
 						name,
@@ -87,7 +87,7 @@ func visitModel(modelID ModelId, astModel *v2ast.Model, ctx *Context) {
 			if !foundSimilar {
 				if scalarType := parseScalarTypeCaseInsensitive(typeName); scalarType != nil {
 					pos := astField.Pos
-					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), diagnostics.FileIDZero)
+					span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), modelID.FileID)
 					ctx.PushError(diagnostics.NewTypeForCaseNotFoundError(
 						typeName,
 						string(*scalarType),
@@ -99,7 +99,7 @@ func visitModel(modelID ModelId, astModel *v2ast.Model, ctx *Context) {
 
 			if !foundSimilar {
 				pos := astField.Pos
-				span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), diagnostics.FileIDZero)
+				span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), modelID.FileID)
 				ctx.PushError(diagnostics.NewTypeNotFoundError(
 					typeName,
 					span,
@@ -146,7 +146,7 @@ func visitEnum(enumID EnumId, astEnum *v2ast.Enum, ctx *Context) {
 	// Validate that enum has at least one value
 	if len(astEnum.Values) == 0 {
 		pos := astEnum.TopPos()
-		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astEnum.GetName()), diagnostics.FileIDZero)
+		span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astEnum.GetName()), enumID.FileID)
 		ctx.PushError(diagnostics.NewValidationError(
 			"An enum must have at least one value.",
 			span,
@@ -172,7 +172,7 @@ func visitCompositeType(ctID CompositeTypeId, astCT *v2ast.CompositeType, ctx *C
 			// Type not found
 			typeName := astField.GetTypeName()
 			pos := astField.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), ctID.FileID)
 			ctx.PushError(diagnostics.NewTypeNotFoundError(
 				typeName,
 				span,
@@ -198,7 +198,7 @@ func visitCompositeType(ctID CompositeTypeId, astCT *v2ast.CompositeType, ctx *C
 			}
 			typeName := astField.GetTypeName()
 			pos := astField.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(typeName), ctID.FileID)
 			ctx.PushError(diagnostics.NewCompositeTypeValidationError(
 				modelName+" refers to a model, making this a relation field. Relation fields inside composite types are not supported.",
 				astCT.GetName(),
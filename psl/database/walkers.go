@@ -153,15 +153,13 @@ func (pd *ParserDatabase) WalkModels() []*ModelWalker {
 	var result []*ModelWalker
 
 	for _, file := range pd.asts.files {
-		modelCount := 0
-		for _, top := range file.AST.Tops {
+		for i, top := range file.AST.Tops {
 			if _, ok := top.(*v2ast.Model); ok {
 				modelID := ModelId{
 					FileID: file.FileID,
-					ID:     uint32(modelCount),
+					ID:     uint32(i),
 				}
 				result = append(result, pd.WalkModel(modelID))
-				modelCount++
 			}
 		}
 	}
@@ -174,15 +172,13 @@ func (pd *ParserDatabase) WalkEnums() []*EnumWalker {
 	var result []*EnumWalker
 
 	for _, file := range pd.asts.files {
-		enumCount := 0
-		for _, top := range file.AST.Tops {
+		for i, top := range file.AST.Tops {
 			if _, ok := top.(*v2ast.Enum); ok {
 				enumID := EnumId{
 					FileID: file.FileID,
-					ID:     uint32(enumCount),
+					ID:     uint32(i),
 				}
 				result = append(result, pd.WalkEnum(enumID))
-				enumCount++
 			}
 		}
 	}
@@ -195,15 +191,13 @@ func (pd *ParserDatabase) WalkCompositeTypes() []*CompositeTypeWalker {
 	var result []*CompositeTypeWalker
 
 	for _, file := range pd.asts.files {
-		ctCount := 0
-		for _, top := range file.AST.Tops {
+		for i, top := range file.AST.Tops {
 			if _, ok := top.(*v2ast.CompositeType); ok {
 				ctID := CompositeTypeId{
 					FileID: file.FileID,
-					ID:     uint32(ctCount),
+					ID:     uint32(i),
 				}
 				result = append(result, pd.WalkCompositeType(ctID))
-				ctCount++
 			}
 		}
 	}
@@ -33,17 +33,12 @@ func (w *CompositeTypeWalker) AstCompositeType() *v2ast.CompositeType {
 		return nil
 	}
 
-	ctCount := 0
-	for _, top := range file.AST.Tops {
-		if ct, ok := top.(*v2ast.CompositeType); ok {
-			if uint32(ctCount) == w.id.ID {
-				return ct
-			}
-			ctCount++
-		}
+	// Composite type IDs are indexes into the top-level declarations of the file
+	if int(w.id.ID) >= len(file.AST.Tops) {
+		return nil
 	}
-
-	return nil
+	ct, _ := file.AST.Tops[w.id.ID].(*v2ast.CompositeType)
+	return ct
 }
 
 // Fields returns all fields in the composite type.
@@ -187,6 +182,6 @@ func (w *CompositeTypeFieldWalker) Span() diagnostics.Span {
 		return diagnostics.EmptySpan()
 	}
 	pos := astField.Pos
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), w.ctID.FileID)
 	return span
 }
@@ -33,17 +33,12 @@ func (w *EnumWalker) AstEnum() *v2ast.Enum {
 		return nil
 	}
 
-	enumCount := 0
-	for _, top := range file.AST.Tops {
-		if enum, ok := top.(*v2ast.Enum); ok {
-			if uint32(enumCount) == w.id.ID {
-				return enum
-			}
-			enumCount++
-		}
+	// Enum IDs are indexes into the top-level declarations of the file
+	if int(w.id.ID) >= len(file.AST.Tops) {
+		return nil
 	}
-
-	return nil
+	enum, _ := file.AST.Tops[w.id.ID].(*v2ast.Enum)
+	return enum
 }
 
 // Attributes returns the parsed attributes for the enum.
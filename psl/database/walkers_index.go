@@ -48,6 +48,12 @@ func (w *IndexWalker) Fields() []*IndexFieldWalker {
 	return result
 }
 
+// IsDefinedOnField returns whether the index is defined by a field attribute
+// such as @unique rather than a block attribute.
+func (w *IndexWalker) IsDefinedOnField() bool {
+	return w.index != nil && w.index.SourceField != nil
+}
+
 // Name returns the name of the index if @@unique(name: "...") is present.
 func (w *IndexWalker) Name() *string {
 	if w.index == nil || w.index.Name == nil {
@@ -192,7 +198,7 @@ func (w *IndexWalker) SpanForArgument(argumentName string) *diagnostics.Span {
 	for _, arg := range attr.Arguments.Arguments {
 		if arg != nil && arg.Name != nil && arg.Name.Name == argumentName {
 			pos := arg.Pos
-			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(argumentName), diagnostics.FileIDZero)
+			span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(argumentName), w.modelID.FileID)
 			return &span
 		}
 	}
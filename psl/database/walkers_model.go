@@ -33,17 +33,12 @@ func (w *ModelWalker) AstModel() *v2ast.Model {
 		return nil
 	}
 
-	modelCount := 0
-	for _, top := range file.AST.Tops {
-		if model, ok := top.(*v2ast.Model); ok {
-			if uint32(modelCount) == w.id.ID {
-				return model
-			}
-			modelCount++
-		}
+	// Model IDs are indexes into the top-level declarations of the file
+	if int(w.id.ID) >= len(file.AST.Tops) {
+		return nil
 	}
-
-	return nil
+	model, _ := file.AST.Tops[w.id.ID].(*v2ast.Model)
+	return model
 }
 
 // Attributes returns the parsed attributes for the model.
@@ -73,6 +68,16 @@ func (w *ModelWalker) DatabaseName() string {
 	return w.db.interner.Get(*attrs.MappedName)
 }
 
+// MappedName returns the mapped name from the @@map attribute on the model.
+func (w *ModelWalker) MappedName() *string {
+	attrs := w.Attributes()
+	if attrs == nil || attrs.MappedName == nil {
+		return nil
+	}
+	name := w.db.interner.Get(*attrs.MappedName)
+	return &name
+}
+
 // Schema returns the schema name if @@schema is present.
 func (w *ModelWalker) Schema() *SchemaInfo {
 	attrs := w.Attributes()
@@ -124,10 +124,13 @@ func (w *RelationWalker) RelationName() string {
 	if rel.RelationName != nil {
 		return w.db.interner.Get(*rel.RelationName)
 	}
-	// Generate name from model names
-	modelA := w.db.WalkModel(rel.ModelA)
-	modelB := w.db.WalkModel(rel.ModelB)
-	return modelA.Name() + "To" + modelB.Name()
+	// Generate name from the model names in alphabetical order
+	nameA := w.db.WalkModel(rel.ModelA).Name()
+	nameB := w.db.WalkModel(rel.ModelB).Name()
+	if nameB < nameA {
+		nameA, nameB = nameB, nameA
+	}
+	return nameA + "To" + nameB
 }
 
 // astRelation returns the relation attributes.
@@ -73,6 +73,16 @@ func (w *ScalarFieldWalker) DatabaseName() string {
 	return w.Name()
 }
 
+// MappedName returns the mapped name from the @map attribute on the field.
+func (w *ScalarFieldWalker) MappedName() *string {
+	sf := w.attributes()
+	if sf == nil || sf.MappedName == nil {
+		return nil
+	}
+	name := w.db.interner.Get(*sf.MappedName)
+	return &name
+}
+
 // IsIgnored returns whether the field has an @ignore attribute.
 func (w *ScalarFieldWalker) IsIgnored() bool {
 	sf := w.attributes()
@@ -276,6 +286,6 @@ func (w *ScalarFieldWalker) Span() diagnostics.Span {
 		return diagnostics.EmptySpan()
 	}
 	pos := astField.Pos
-	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), diagnostics.FileIDZero)
+	span := diagnostics.NewSpan(pos.Offset, pos.Offset+len(astField.GetName()), w.attributes().ModelID.FileID)
 	return span
 }
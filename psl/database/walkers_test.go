@@ -0,0 +1,84 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl/core"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+// interleavedSchema declares enums and a datasource between its models, so
+// that the index of a model among the declarations of the file differs
+// from its index among the models
+const interleavedSchema = `
+datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+enum Role {
+  ADMIN
+  USER
+}
+
+model User {
+  id    Int    @id
+  role  Role
+  posts Post[]
+
+  @@map("users")
+}
+
+enum Status {
+  DRAFT
+  PUBLISHED
+}
+
+model Post {
+  id       Int    @id
+  status   Status
+  authorId Int
+  author   User   @relation(fields: [authorId], references: [id])
+
+  @@map("posts")
+}
+`
+
+func TestWalkerIDsIndexTopLevelDeclarations(t *testing.T) {
+	files := []core.SourceFile{core.NewSourceFile("schema.prisma", interleavedSchema)}
+	diags := diagnostics.NewDiagnostics()
+	db := NewParserDatabase(files, &diags, NoExtensionTypes{})
+	if diags.HasErrors() {
+		t.Fatal(diags.Errors())
+	}
+
+	var models, tables []string
+	for _, model := range db.WalkModels() {
+		models = append(models, model.Name())
+		tables = append(tables, model.DatabaseName())
+	}
+	if !reflect.DeepEqual(models, []string{"User", "Post"}) || !reflect.DeepEqual(tables, []string{"users", "posts"}) {
+		t.Errorf("walked models %v mapped to %v, want User and Post mapped to users and posts", models, tables)
+	}
+
+	// Walkers found by name carry the IDs of the walked ones
+	post := db.FindModel("Post")
+	if post == nil || post.Name() != "Post" || post.id != db.WalkModels()[1].id {
+		t.Fatalf("FindModel(Post) = %+v, want the second walked model", post)
+	}
+	if relations := post.RelationFields(); len(relations) != 1 || relations[0].ReferencedModel().Name() != "User" {
+		t.Errorf("Post relations = %v, want author referencing User", relations)
+	}
+
+	var enums []string
+	for _, enum := range db.WalkEnums() {
+		enums = append(enums, enum.Name())
+	}
+	if !reflect.DeepEqual(enums, []string{"Role", "Status"}) {
+		t.Errorf("walked enums %v, want Role and Status", enums)
+	}
+	if status := db.FindEnum("Status"); status == nil || status.Name() != "Status" || len(status.Values()) != 2 {
+		t.Errorf("FindEnum(Status) = %+v, want Status and its values", status)
+	}
+}
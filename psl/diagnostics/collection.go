@@ -56,6 +56,22 @@ func (d *Diagnostics) HasErrors() bool {
 	return len(d.errors) > 0
 }
 
+// ForFile returns the errors and warnings whose span belongs to the given file.
+func (d *Diagnostics) ForFile(fileID FileID) Diagnostics {
+	result := NewDiagnostics()
+	for _, err := range d.errors {
+		if err.Span().FileID == fileID {
+			result.errors = append(result.errors, err)
+		}
+	}
+	for _, warn := range d.warnings {
+		if warn.Span().FileID == fileID {
+			result.warnings = append(result.warnings, warn)
+		}
+	}
+	return result
+}
+
 // ToResult returns an error if there are errors, otherwise returns nil.
 func (d *Diagnostics) ToResult() error {
 	if d.HasErrors() {
@@ -16,7 +16,11 @@ func Reformat(input string, indentWidth int) (string, error) {
 		return "", fmt.Errorf("cannot reformat invalid schema: %w", err)
 	}
 
-	// Render the AST back to formatted string
+	// Render the AST back to formatted string, ending with a newline like any text file
 	renderer := NewRenderer()
-	return renderer.Render(ast), nil
+	formatted := renderer.Render(ast)
+	if formatted != "" {
+		formatted += "\n"
+	}
+	return formatted, nil
 }
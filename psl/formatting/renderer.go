@@ -248,8 +248,8 @@ func (r *Renderer) renderIdentifier(ident *ast.Identifier) {
 func (r *Renderer) renderExpression(expr ast.Expression) {
 	switch e := expr.(type) {
 	case *ast.StringValue:
-		// e.Value includes quotes
-		r.builder.WriteString(e.Value)
+		// e.Value is unquoted by the parser, so quote it again
+		r.builder.WriteString(e.String())
 	case *ast.NumericValue:
 		r.builder.WriteString(e.Value)
 	case *ast.ConstantValue:
@@ -257,7 +257,12 @@ func (r *Renderer) renderExpression(expr ast.Expression) {
 		r.builder.WriteString(e.Value)
 	case *ast.FunctionCall:
 		r.builder.WriteString(e.Name)
-		r.renderArguments(e.Arguments)
+		if e.Arguments == nil || len(e.Arguments.Arguments) == 0 {
+			// Functions without arguments such as now() keep their parentheses
+			r.builder.WriteString("()")
+		} else {
+			r.renderArguments(e.Arguments)
+		}
 	case *ast.ArrayExpression:
 		r.builder.WriteString("[")
 		for i, elem := range e.Elements {
@@ -0,0 +1,231 @@
+// Package lint implements opinionated lint rules for Prisma schemas.
+//
+// Unlike psl/validation, which only reports what Prisma considers invalid,
+// the rules in this package flag schemas that are valid but likely to cause
+// problems, such as unindexed foreign keys or Float columns holding money.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/core"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+// Severity is the severity of a lint finding.
+type Severity int
+
+const (
+	// SeverityOff disables a rule.
+	SeverityOff Severity = iota
+	// SeverityInfo reports a finding without failing the lint run.
+	SeverityInfo
+	// SeverityWarning reports a finding without failing the lint run.
+	SeverityWarning
+	// SeverityError reports a finding and fails the lint run.
+	SeverityError
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityOff:
+		return "off"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// ParseSeverity parses a severity name.
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "off", "none", "disable", "disabled":
+		return SeverityOff, nil
+	case "info", "note", "hint":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityOff, fmt.Errorf("unknown severity %q (expected off, info, warning or error)", name)
+}
+
+// Finding is a single problem reported by a rule.
+type Finding struct {
+	RuleID    string           `json:"ruleId"`
+	Severity  Severity         `json:"severity"`
+	Message   string           `json:"message"`
+	File      string           `json:"file"`
+	Line      int              `json:"line"`
+	Column    int              `json:"column"`
+	EndLine   int              `json:"endLine"`
+	EndColumn int              `json:"endColumn"`
+	Span      diagnostics.Span `json:"-"`
+}
+
+// String formats the finding as file:line:column: severity: message [rule].
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]", f.File, f.Line, f.Column, f.Severity, f.Message, f.RuleID)
+}
+
+// Config configures a lint run.
+type Config struct {
+	// Severities overrides the default severity of rules, keyed by rule ID.
+	Severities map[string]Severity
+}
+
+// Validate checks that the config only refers to known rules.
+func (c Config) Validate() error {
+	for id := range c.Severities {
+		if RuleByID(id) == nil {
+			return fmt.Errorf("unknown lint rule %q", id)
+		}
+	}
+	return nil
+}
+
+// severity returns the effective severity of rule.
+func (c Config) severity(rule *Rule) Severity {
+	if severity, ok := c.Severities[rule.ID]; ok {
+		return severity
+	}
+	return rule.DefaultSeverity
+}
+
+// Lint runs every enabled rule against the schema made of files.
+// Files must be free of syntax errors; otherwise an error with the rendered
+// diagnostics is returned.
+func Lint(files []core.SourceFile, config Config) ([]Finding, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	_, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("schema has errors:\n%s", psl.RenderDiagnostics(files, diags))
+	}
+
+	// The parser database reports its own validation errors; those are the
+	// job of validate, so they are collected and dropped here.
+	dbDiags := diagnostics.NewDiagnostics()
+	db := database.NewParserDatabase(files, &dbDiags, database.NoExtensionTypes{})
+
+	fileSuppressions := make([]suppressions, len(files))
+	for i, file := range files {
+		fileSuppressions[i] = parseSuppressions(file.Data)
+	}
+
+	var findings []Finding
+	for _, rule := range Rules() {
+		severity := config.severity(rule)
+		if severity == SeverityOff {
+			continue
+		}
+
+		ctx := &Context{db: db, files: files, rule: rule, severity: severity}
+		rule.Check(ctx)
+
+		for _, finding := range ctx.findings {
+			if fileSuppressions[finding.Span.FileID].suppressed(finding.Line, rule.ID) {
+				continue
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.RuleID < b.RuleID
+	})
+	return findings, nil
+}
+
+// HasErrors reports whether any finding has error severity.
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Context gives rules access to the schema and collects their findings.
+type Context struct {
+	db       *database.ParserDatabase
+	files    []core.SourceFile
+	rule     *Rule
+	severity Severity
+	findings []Finding
+}
+
+// Db returns the parser database of the schema.
+func (c *Context) Db() *database.ParserDatabase {
+	return c.db
+}
+
+// Report records a finding covering length bytes at offset in the given file.
+func (c *Context) Report(fileID diagnostics.FileID, offset, length int, format string, args ...interface{}) {
+	if int(fileID) >= len(c.files) {
+		return
+	}
+	file := c.files[fileID]
+	line, column := lineColumn(file.Data, offset)
+	endLine, endColumn := lineColumn(file.Data, offset+length)
+
+	c.findings = append(c.findings, Finding{
+		RuleID:    c.rule.ID,
+		Severity:  c.severity,
+		Message:   fmt.Sprintf(format, args...),
+		File:      file.Path,
+		Line:      line,
+		Column:    column,
+		EndLine:   endLine,
+		EndColumn: endColumn,
+		Span:      diagnostics.NewSpan(offset, offset+length, fileID),
+	})
+}
+
+// lineColumn converts a byte offset into a one-based line and column.
+func lineColumn(text string, offset int) (int, int) {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	line := strings.Count(text[:offset], "\n") + 1
+	column := offset - (strings.LastIndexByte(text[:offset], '\n') + 1) + 1
+	return line, column
+}
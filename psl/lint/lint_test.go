@@ -0,0 +1,189 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl/core"
+)
+
+const lintSchema = `datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+model User {
+  id        String   @id
+  email     String?  @unique
+  posts     Post[]
+  createdAt DateTime @default(now())
+  updatedAt DateTime @updatedAt
+}
+
+model Post {
+  id          Int      @id @default(autoincrement())
+  author_id   String
+  author      User     @relation(fields: [author_id], references: [id])
+  price       Float
+  createdAt   DateTime @default(now())
+  updatedAt   DateTime @updatedAt
+}
+
+model Comment {
+  id       String @id @default(cuid())
+  postId   Int
+  post     Post   @relation(fields: [postId], references: [id], onDelete: Cascade)
+  altTitle String?
+
+  @@index([postId])
+  @@unique([postId, altTitle])
+}
+
+enum Role {
+  ADMIN
+  Member
+}
+`
+
+func lintString(t *testing.T, schema string, config Config) []Finding {
+	t.Helper()
+	findings, err := Lint([]core.SourceFile{core.NewSourceFile("schema.prisma", schema)}, config)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	return findings
+}
+
+// byRule groups the lines of findings by rule ID.
+func byRule(findings []Finding) map[string][]int {
+	result := make(map[string][]int)
+	for _, finding := range findings {
+		result[finding.RuleID] = append(result[finding.RuleID], finding.Line)
+	}
+	return result
+}
+
+func TestRules(t *testing.T) {
+	got := byRule(lintString(t, lintSchema, Config{}))
+
+	want := map[string][]int{
+		RuleMissingForeignKeyIndex: {17},
+		RuleMissingTimestamps:      {23},
+		RuleStringIDWithoutDefault: {7},
+		RuleNamingConvention:       {16, 35},
+		RuleMissingOnDelete:        {17},
+		RuleFloatForMoney:          {18},
+		RuleNullableUnique:         {8, 30},
+	}
+	for rule, lines := range want {
+		if !reflect.DeepEqual(got[rule], lines) {
+			t.Errorf("%s: got lines %v, want %v", rule, got[rule], lines)
+		}
+	}
+	for rule := range got {
+		if _, ok := want[rule]; !ok {
+			t.Errorf("unexpected findings for %s: %v", rule, got[rule])
+		}
+	}
+}
+
+func TestConfigSeverities(t *testing.T) {
+	findings := lintString(t, lintSchema, Config{Severities: map[string]Severity{
+		RuleMissingTimestamps: SeverityOff,
+		RuleFloatForMoney:     SeverityError,
+	}})
+
+	got := byRule(findings)
+	if _, ok := got[RuleMissingTimestamps]; ok {
+		t.Errorf("disabled rule %s reported findings", RuleMissingTimestamps)
+	}
+	if !HasErrors(findings) {
+		t.Errorf("expected %s to be reported as error", RuleFloatForMoney)
+	}
+
+	if err := (Config{Severities: map[string]Severity{"no-such-rule": SeverityError}}).Validate(); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+}
+
+func TestSuppressionComments(t *testing.T) {
+	schema := `datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db" // prisma-go-lint-disable-line
+}
+
+// prisma-go-lint-disable-next-line missing-timestamps
+model Account {
+  id      String @id // prisma-go-lint-disable-line string-id-without-default
+  balance Float  // prisma-go-lint-disable-line
+}
+
+// prisma-go-lint-disable naming-convention
+model legacy_table {
+  id Int @id
+  createdAt DateTime @default(now())
+  updatedAt DateTime @updatedAt
+}
+// prisma-go-lint-enable naming-convention
+
+model audit_log {
+  id        Int      @id
+  createdAt DateTime @default(now())
+  updatedAt DateTime @updatedAt
+}
+`
+	got := byRule(lintString(t, schema, Config{}))
+	if len(got) != 1 || len(got[RuleNamingConvention]) != 1 || got[RuleNamingConvention][0] != 20 {
+		t.Errorf("unexpected findings: %v", got)
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	_, err := Lint([]core.SourceFile{core.NewSourceFile("schema.prisma", "model {")}, Config{})
+	if err == nil {
+		t.Fatal("expected error for invalid schema")
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	findings := lintString(t, lintSchema, Config{})
+
+	var sarif bytes.Buffer
+	if err := WriteReport(&sarif, FormatSARIF, findings); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(sarif.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != len(findings) {
+		t.Fatalf("unexpected SARIF log: %+v", log)
+	}
+	result := log.Runs[0].Results[0]
+	if result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "schema.prisma" || result.Locations[0].PhysicalLocation.Region.StartLine == 0 {
+		t.Errorf("unexpected SARIF result: %+v", result)
+	}
+	if log.Runs[0].Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+		t.Errorf("rule index %d does not match rule %s", result.RuleIndex, result.RuleID)
+	}
+
+	var out bytes.Buffer
+	if err := WriteReport(&out, FormatJSON, findings); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Findings []Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Findings) != len(findings) || decoded.Findings[0].Severity != findings[0].Severity {
+		t.Errorf("JSON round trip mismatch: %+v", decoded.Findings)
+	}
+
+	if err := WriteReport(&out, "xml", findings); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
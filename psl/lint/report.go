@@ -0,0 +1,170 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// Output formats supported by WriteReport.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// WriteReport writes findings to w in the given format.
+func WriteReport(w io.Writer, format string, findings []Finding) error {
+	switch format {
+	case FormatText, "":
+		return writeText(w, findings)
+	case FormatJSON:
+		return writeJSON(w, findings)
+	case FormatSARIF:
+		return writeSARIF(w, findings)
+	}
+	return fmt.Errorf("unknown output format %q (expected text, json or sarif)", format)
+}
+
+func writeText(w io.Writer, findings []Finding) error {
+	for _, finding := range findings {
+		if _, err := fmt.Fprintln(w, finding.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Findings []Finding `json:"findings"`
+	}{findings})
+}
+
+// SARIF 2.1.0 types, limited to what lint reports need.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	}
+	return "none"
+}
+
+func writeSARIF(w io.Writer, findings []Finding) error {
+	driver := sarifDriver{
+		Name:           "prisma-go-lint",
+		InformationURI: "https://github.com/satishbabariya/prisma-go",
+	}
+	ruleIndex := make(map[string]int)
+	for i, rule := range Rules() {
+		ruleIndex[rule.ID] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.DefaultSeverity)},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, finding := range findings {
+		results = append(results, sarifResult{
+			RuleID:    finding.RuleID,
+			RuleIndex: ruleIndex[finding.RuleID],
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(finding.File)},
+					Region: sarifRegion{
+						StartLine:   finding.Line,
+						StartColumn: finding.Column,
+						EndLine:     finding.EndLine,
+						EndColumn:   finding.EndColumn,
+					},
+				},
+			}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
@@ -0,0 +1,534 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// Rule is a single lint check.
+type Rule struct {
+	// ID identifies the rule in configs, suppression comments and reports.
+	ID string
+	// Description explains what the rule checks.
+	Description string
+	// DefaultSeverity is used unless the config overrides it.
+	DefaultSeverity Severity
+	// Check inspects the schema and reports findings through ctx.
+	Check func(ctx *Context)
+}
+
+// Rule IDs.
+const (
+	RuleMissingForeignKeyIndex = "missing-fk-index"
+	RuleMissingTimestamps      = "missing-timestamps"
+	RuleStringIDWithoutDefault = "string-id-without-default"
+	RuleNamingConvention       = "naming-convention"
+	RuleMissingOnDelete        = "missing-on-delete"
+	RuleFloatForMoney          = "float-for-money"
+	RuleNullableUnique         = "nullable-unique"
+)
+
+var rules = []*Rule{
+	{
+		ID:              RuleMissingForeignKeyIndex,
+		Description:     "Foreign key fields of a relation should be covered by an index.",
+		DefaultSeverity: SeverityWarning,
+		Check:           checkMissingForeignKeyIndex,
+	},
+	{
+		ID:              RuleMissingTimestamps,
+		Description:     "Models should have createdAt and updatedAt fields.",
+		DefaultSeverity: SeverityWarning,
+		Check:           checkMissingTimestamps,
+	},
+	{
+		ID:              RuleStringIDWithoutDefault,
+		Description:     "String ids should have a @default such as cuid() or uuid().",
+		DefaultSeverity: SeverityWarning,
+		Check:           checkStringIDWithoutDefault,
+	},
+	{
+		ID:              RuleNamingConvention,
+		Description:     "Models, enums and types should be PascalCase, fields camelCase, and enum values consistent.",
+		DefaultSeverity: SeverityWarning,
+		Check:           checkNamingConvention,
+	},
+	{
+		ID:              RuleMissingOnDelete,
+		Description:     "Required relations should state their onDelete referential action explicitly.",
+		DefaultSeverity: SeverityInfo,
+		Check:           checkMissingOnDelete,
+	},
+	{
+		ID:              RuleFloatForMoney,
+		Description:     "Monetary values should use Decimal instead of Float.",
+		DefaultSeverity: SeverityWarning,
+		Check:           checkFloatForMoney,
+	},
+	{
+		ID:              RuleNullableUnique,
+		Description:     "Unique constraints on optional fields allow any number of NULL values.",
+		DefaultSeverity: SeverityWarning,
+		Check:           checkNullableUnique,
+	},
+}
+
+// Rules returns every available rule.
+func Rules() []*Rule {
+	return rules
+}
+
+// RuleByID returns the rule with the given ID, or nil.
+func RuleByID(id string) *Rule {
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+// lintedModels returns the models rules should look at: views and
+// ignored models are skipped.
+func lintedModels(ctx *Context) []*database.ModelWalker {
+	var result []*database.ModelWalker
+	for _, model := range ctx.db.WalkModels() {
+		astModel := model.AstModel()
+		if astModel == nil || astModel.IsView() || hasBlockAttribute(astModel, "ignore") {
+			continue
+		}
+		result = append(result, model)
+	}
+	return result
+}
+
+func checkMissingForeignKeyIndex(ctx *Context) {
+	for _, model := range lintedModels(ctx) {
+		astModel := model.AstModel()
+		indexed := indexedFieldLists(astModel)
+
+		for _, relation := range model.RelationFields() {
+			field := relation.AstField()
+			if field == nil {
+				continue
+			}
+			attr := fieldAttribute(field, "relation")
+			if attr == nil {
+				continue
+			}
+			fields := expressionFieldNames(namedArgument(attr.Arguments, "fields"))
+			if len(fields) == 0 || isCoveredByIndex(fields, indexed) {
+				continue
+			}
+			ctx.Report(model.FileID(), field.Name.Pos.Offset, len(field.GetName()),
+				"Foreign key %s of relation %q on model %q is not indexed; add @@index([%s])",
+				pluralize(len(fields), "field", "fields"), field.GetName(), model.Name(), strings.Join(fields, ", "))
+		}
+	}
+}
+
+// indexedFieldLists returns the field lists of every index, unique constraint
+// and primary key of the model, including single-field attributes.
+func indexedFieldLists(model *ast.Model) [][]string {
+	var result [][]string
+	for _, attr := range model.BlockAttributes {
+		switch attr.GetName() {
+		case "index", "unique", "id":
+			if fields := expressionFieldNames(fieldsArgument(attr.Arguments)); len(fields) > 0 {
+				result = append(result, fields)
+			}
+		}
+	}
+	for _, field := range model.Fields {
+		if fieldAttribute(field, "id") != nil || fieldAttribute(field, "unique") != nil {
+			result = append(result, []string{field.GetName()})
+		}
+	}
+	return result
+}
+
+// isCoveredByIndex reports whether some index starts with exactly the given
+// fields, in any order, so it can serve lookups by the foreign key.
+func isCoveredByIndex(fields []string, indexed [][]string) bool {
+	for _, index := range indexed {
+		if len(index) < len(fields) {
+			continue
+		}
+		prefix := make(map[string]bool, len(fields))
+		for _, name := range index[:len(fields)] {
+			prefix[name] = true
+		}
+		covered := true
+		for _, name := range fields {
+			if !prefix[name] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+func checkMissingTimestamps(ctx *Context) {
+	for _, model := range lintedModels(ctx) {
+		var missing []string
+		createdAt := findScalarField(model, "createdAt")
+		if createdAt == nil || !isScalarType(createdAt, database.ScalarTypeDateTime) {
+			missing = append(missing, "createdAt DateTime @default(now())")
+		}
+		updatedAt := findScalarField(model, "updatedAt")
+		if updatedAt == nil || !isScalarType(updatedAt, database.ScalarTypeDateTime) {
+			missing = append(missing, "updatedAt DateTime @updatedAt")
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		name := model.AstModel().Name
+		ctx.Report(model.FileID(), name.Pos.Offset, len(name.Name),
+			"Model %q is missing timestamp %s: %s",
+			model.Name(), pluralize(len(missing), "field", "fields"), strings.Join(missing, ", "))
+	}
+}
+
+func checkStringIDWithoutDefault(ctx *Context) {
+	for _, model := range lintedModels(ctx) {
+		for _, scalar := range model.ScalarFields() {
+			field := scalar.AstField()
+			if field == nil || fieldAttribute(field, "id") == nil {
+				continue
+			}
+			if !isScalarType(scalar, database.ScalarTypeString) || fieldAttribute(field, "default") != nil {
+				continue
+			}
+			ctx.Report(model.FileID(), field.Name.Pos.Offset, len(field.GetName()),
+				"String id %q of model %q has no @default; use @default(cuid()) or @default(uuid())",
+				field.GetName(), model.Name())
+		}
+	}
+}
+
+var (
+	pascalCasePattern     = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	camelCasePattern      = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+	upperSnakeCasePattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+)
+
+func checkNamingConvention(ctx *Context) {
+	for _, model := range ctx.db.WalkModels() {
+		astModel := model.AstModel()
+		if astModel == nil {
+			continue
+		}
+		kind := "Model"
+		if astModel.IsView() {
+			kind = "View"
+		}
+		checkTypeName(ctx, model.FileID(), kind, astModel.Name)
+		for _, field := range astModel.Fields {
+			checkFieldName(ctx, model.FileID(), model.Name(), field)
+		}
+	}
+
+	for _, compositeType := range ctx.db.WalkCompositeTypes() {
+		astType := compositeType.AstCompositeType()
+		if astType == nil {
+			continue
+		}
+		checkTypeName(ctx, compositeType.FileID(), "Type", astType.Name)
+		for _, field := range astType.Fields {
+			checkFieldName(ctx, compositeType.FileID(), compositeType.Name(), field)
+		}
+	}
+
+	for _, enum := range ctx.db.WalkEnums() {
+		astEnum := enum.AstEnum()
+		if astEnum == nil {
+			continue
+		}
+		checkTypeName(ctx, enum.FileID(), "Enum", astEnum.Name)
+
+		// Enum values may be PascalCase or UPPER_SNAKE_CASE, but not both in one enum
+		var first string
+		for _, value := range astEnum.Values {
+			if value.Name == nil {
+				continue
+			}
+			style := enumValueStyle(value.Name.Name)
+			if first == "" {
+				first = style
+				continue
+			}
+			if style != first {
+				ctx.Report(enum.FileID(), value.Name.Pos.Offset, len(value.Name.Name),
+					"Enum value %q of enum %q is %s, but earlier values are %s",
+					value.Name.Name, enum.Name(), style, first)
+			}
+		}
+	}
+}
+
+func checkTypeName(ctx *Context, fileID diagnostics.FileID, kind string, name *ast.Identifier) {
+	if name == nil || pascalCasePattern.MatchString(name.Name) {
+		return
+	}
+	ctx.Report(fileID, name.Pos.Offset, len(name.Name),
+		"%s name %q should be PascalCase, e.g. %q", kind, name.Name, toPascalCase(name.Name))
+}
+
+func checkFieldName(ctx *Context, fileID diagnostics.FileID, owner string, field *ast.Field) {
+	if field.Name == nil || camelCasePattern.MatchString(field.Name.Name) {
+		return
+	}
+	ctx.Report(fileID, field.Name.Pos.Offset, len(field.Name.Name),
+		"Field name %q of %q should be camelCase, e.g. %q; use @map to keep the column name",
+		field.Name.Name, owner, toCamelCase(field.Name.Name))
+}
+
+// enumValueStyle names the casing convention of an enum value.
+func enumValueStyle(name string) string {
+	switch {
+	case upperSnakeCasePattern.MatchString(name):
+		return "UPPER_SNAKE_CASE"
+	case pascalCasePattern.MatchString(name):
+		return "PascalCase"
+	}
+	return "mixed case"
+}
+
+func checkMissingOnDelete(ctx *Context) {
+	for _, model := range lintedModels(ctx) {
+		for _, relation := range model.RelationFields() {
+			field := relation.AstField()
+			if field == nil || !field.Arity.IsRequired() {
+				continue
+			}
+			attr := fieldAttribute(field, "relation")
+			if attr == nil || namedArgument(attr.Arguments, "fields") == nil {
+				// Only the side holding the foreign key can declare referential actions
+				continue
+			}
+			if namedArgument(attr.Arguments, "onDelete") != nil {
+				continue
+			}
+			ctx.Report(model.FileID(), attr.Pos.Offset, len(attr.GetName())+1,
+				"Required relation %q of model %q has no explicit onDelete; add onDelete: Cascade, Restrict or NoAction",
+				field.GetName(), model.Name())
+		}
+	}
+}
+
+// moneyWords are name parts that indicate a field holds a monetary amount.
+var moneyWords = map[string]bool{
+	"amount": true, "balance": true, "budget": true, "charge": true,
+	"cost": true, "fee": true, "fees": true, "money": true,
+	"payment": true, "price": true, "refund": true, "revenue": true,
+	"salary": true, "subtotal": true, "tax": true, "total": true,
+	"wage": true, "discount": true,
+}
+
+func checkFloatForMoney(ctx *Context) {
+	for _, model := range lintedModels(ctx) {
+		for _, scalar := range model.ScalarFields() {
+			field := scalar.AstField()
+			if field == nil || !isScalarType(scalar, database.ScalarTypeFloat) {
+				continue
+			}
+			for _, word := range splitWords(field.GetName()) {
+				if moneyWords[word] {
+					ctx.Report(model.FileID(), field.Type.Pos.Offset, len(field.Type.Name),
+						"Field %q of model %q looks like a monetary value; use Decimal instead of Float to avoid rounding errors",
+						field.GetName(), model.Name())
+					break
+				}
+			}
+		}
+	}
+}
+
+func checkNullableUnique(ctx *Context) {
+	for _, model := range lintedModels(ctx) {
+		astModel := model.AstModel()
+		optional := make(map[string]bool)
+		for _, scalar := range model.ScalarFields() {
+			field := scalar.AstField()
+			if field == nil || !field.Arity.IsOptional() {
+				continue
+			}
+			optional[field.GetName()] = true
+			if attr := fieldAttribute(field, "unique"); attr != nil {
+				ctx.Report(model.FileID(), attr.Pos.Offset, len(attr.GetName())+1,
+					"Optional field %q of model %q is unique; NULL values are not considered duplicates",
+					field.GetName(), model.Name())
+			}
+		}
+
+		for _, attr := range astModel.BlockAttributes {
+			if attr.GetName() != "unique" {
+				continue
+			}
+			var nullable []string
+			for _, name := range expressionFieldNames(fieldsArgument(attr.Arguments)) {
+				if optional[name] {
+					nullable = append(nullable, name)
+				}
+			}
+			if len(nullable) == 0 {
+				continue
+			}
+			ctx.Report(model.FileID(), attr.Pos.Offset, len(attr.GetName())+2,
+				"Unique constraint of model %q includes optional %s %s; rows with NULL values are never considered duplicates",
+				model.Name(), pluralize(len(nullable), "field", "fields"), strings.Join(nullable, ", "))
+		}
+	}
+}
+
+// findScalarField returns the scalar field of model called name.
+func findScalarField(model *database.ModelWalker, name string) *database.ScalarFieldWalker {
+	for _, scalar := range model.ScalarFields() {
+		if scalar.Name() == name {
+			return scalar
+		}
+	}
+	return nil
+}
+
+// isScalarType reports whether the field has the given built-in scalar type.
+func isScalarType(field *database.ScalarFieldWalker, scalarType database.ScalarType) bool {
+	actual := field.ScalarType()
+	return actual != nil && *actual == scalarType
+}
+
+// fieldAttribute returns the attribute of field called name.
+func fieldAttribute(field *ast.Field, name string) *ast.Attribute {
+	for _, attr := range field.Attributes {
+		if attr.GetName() == name {
+			return attr
+		}
+	}
+	return nil
+}
+
+// hasBlockAttribute reports whether the model has the block attribute called name.
+func hasBlockAttribute(model *ast.Model, name string) bool {
+	for _, attr := range model.BlockAttributes {
+		if attr.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// namedArgument returns the value of the argument called name.
+func namedArgument(args *ast.ArgumentsList, name string) ast.Expression {
+	if args == nil {
+		return nil
+	}
+	for _, arg := range args.Arguments {
+		if arg.GetName() == name {
+			return arg.Value
+		}
+	}
+	return nil
+}
+
+// fieldsArgument returns the field list of a block attribute, which is either
+// the first positional argument or the argument called fields.
+func fieldsArgument(args *ast.ArgumentsList) ast.Expression {
+	if args == nil {
+		return nil
+	}
+	if value := namedArgument(args, "fields"); value != nil {
+		return value
+	}
+	if len(args.Arguments) > 0 && !args.Arguments[0].IsNamed() {
+		return args.Arguments[0].Value
+	}
+	return nil
+}
+
+// expressionFieldNames returns the field names of a field list such as [a, b(sort: Desc)].
+func expressionFieldNames(expr ast.Expression) []string {
+	if expr == nil {
+		return nil
+	}
+	array, ok := expr.AsArray()
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, element := range array.Elements {
+		switch e := element.(type) {
+		case *ast.ConstantValue:
+			names = append(names, e.Value)
+		case *ast.PathValue:
+			names = append(names, e.String())
+		case *ast.FunctionCall:
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// splitWords splits a camelCase or snake_case name into lower case words.
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' || r == '-' {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			// Keep acronyms such as "ID" together
+			prevUpper := unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !prevUpper || nextLower {
+				words = append(words, string(current))
+				current = nil
+			}
+		}
+		current = append(current, unicode.ToLower(r))
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+// toPascalCase converts a name to PascalCase.
+func toPascalCase(name string) string {
+	var result strings.Builder
+	for _, word := range splitWords(name) {
+		result.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return result.String()
+}
+
+// toCamelCase converts a name to camelCase.
+func toCamelCase(name string) string {
+	pascal := toPascalCase(name)
+	if pascal == "" {
+		return pascal
+	}
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,118 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Suppression comments:
+//
+//	// prisma-go-lint-disable [rule, ...]            disables rules until a matching enable comment
+//	// prisma-go-lint-enable [rule, ...]             re-enables rules
+//	// prisma-go-lint-disable-line [rule, ...]       disables rules on the same line
+//	// prisma-go-lint-disable-next-line [rule, ...]  disables rules on the following line
+//
+// Without a rule list the comment applies to every rule.
+var suppressionPattern = regexp.MustCompile(`^//\s*prisma-go-lint-(disable-next-line|disable-line|disable|enable)\b(.*)$`)
+
+// allRules is the key used when a comment applies to every rule.
+const allRules = "*"
+
+// suppressions records which rules are disabled on which lines of a file.
+type suppressions map[int]map[string]bool
+
+// suppressed reports whether rule is disabled on line.
+func (s suppressions) suppressed(line int, rule string) bool {
+	rules := s[line]
+	return rules[allRules] || rules[rule]
+}
+
+// add disables rules on line.
+func (s suppressions) add(line int, rules []string) {
+	if s[line] == nil {
+		s[line] = make(map[string]bool)
+	}
+	for _, rule := range rules {
+		s[line][rule] = true
+	}
+}
+
+// parseSuppressions scans text for suppression comments.
+func parseSuppressions(text string) suppressions {
+	result := make(suppressions)
+	// Rules disabled by block comments, applied to every following line
+	active := make(map[string]bool)
+
+	for i, line := range strings.Split(text, "\n") {
+		lineNumber := i + 1
+
+		kind, rules := "", []string(nil)
+		if comment := lineComment(line); comment != "" {
+			if m := suppressionPattern.FindStringSubmatch(comment); m != nil {
+				kind, rules = m[1], parseRuleList(m[2])
+			}
+		}
+
+		switch kind {
+		case "disable":
+			for _, rule := range rules {
+				active[rule] = true
+			}
+		case "enable":
+			if len(rules) == 1 && rules[0] == allRules {
+				active = make(map[string]bool)
+			}
+			for _, rule := range rules {
+				delete(active, rule)
+			}
+		case "disable-line":
+			result.add(lineNumber, rules)
+		case "disable-next-line":
+			result.add(lineNumber+1, rules)
+		}
+
+		for rule := range active {
+			result.add(lineNumber, []string{rule})
+		}
+	}
+	return result
+}
+
+// parseRuleList parses the comma or space separated rule IDs of a comment.
+func parseRuleList(text string) []string {
+	// Allow a trailing explanation after "--"
+	if idx := strings.Index(text, "--"); idx >= 0 {
+		text = text[:idx]
+	}
+	rules := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(rules) == 0 {
+		return []string{allRules}
+	}
+	return rules
+}
+
+// lineComment returns the // comment of line, ignoring // inside strings.
+func lineComment(line string) string {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case '/':
+			if !inString && i+1 < len(line) && line[i+1] == '/' {
+				// Documentation comments (///) are never suppression comments
+				if i+2 < len(line) && line[i+2] == '/' {
+					return ""
+				}
+				return strings.TrimSpace(line[i:])
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,343 @@
+package lsp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+	"github.com/satishbabariya/prisma-go/psl/validation"
+)
+
+// blockContext describes the top-level block surrounding a position.
+type blockContext struct {
+	keyword string
+	name    string
+}
+
+var (
+	blockHeaderPattern   = regexp.MustCompile(`^\s*(model|view|enum|type|datasource|generator)\s+([\p{L}\p{N}_-]+)\s*\{`)
+	nativeTypePattern    = regexp.MustCompile(`@([\p{L}\p{N}_-]+)\.([\p{L}\p{N}_]*)$`)
+	blockAttrPattern     = regexp.MustCompile(`^\s*@@([\p{L}\p{N}_]*)$`)
+	fieldAttrPattern     = regexp.MustCompile(`(^|[^@])@([\p{L}\p{N}_]*)$`)
+	referencesPattern    = regexp.MustCompile(`references\s*:\s*\[[^\]]*$`)
+	fieldsPattern        = regexp.MustCompile(`(fields\s*:\s*\[|@@(id|unique|index|fulltext)\(\s*\[)[^\]]*$`)
+	fieldTypePattern     = regexp.MustCompile(`^\s*[\p{L}\p{N}_-]+\s+([\p{L}\p{N}_]*)$`)
+	fieldDeclPattern     = regexp.MustCompile(`^\s*([\p{L}\p{N}_-]+)\s+([\p{L}\p{N}_]+)`)
+	providerValuePattern = regexp.MustCompile(`^\s*provider\s*=\s*"[^"]*$`)
+	stringLiteralPattern = regexp.MustCompile(`"(?:\\.|[^"\\])*"`)
+)
+
+// scalarTypes lists the built-in scalar types with their documentation.
+var scalarTypes = []struct {
+	name string
+	doc  string
+}{
+	{"String", "Variable length text."},
+	{"Boolean", "True or false value."},
+	{"Int", "Integer value."},
+	{"BigInt", "Integer value that does not fit into 32 bits."},
+	{"Float", "Floating point number."},
+	{"Decimal", "Exact decimal number with a fixed precision and scale."},
+	{"DateTime", "Timestamp."},
+	{"Json", "A JSON object."},
+	{"Bytes", "Binary data."},
+}
+
+// attributeDoc documents an attribute for completions and hover.
+type attributeDoc struct {
+	name        string
+	example     string
+	insertText  string
+	description string
+}
+
+// fieldAttributes are the attributes available on fields.
+var fieldAttributes = []attributeDoc{
+	{"id", "@id", "id", "Defines a single-field ID on the model."},
+	{"unique", "@unique", "unique", "Defines a unique constraint for this field."},
+	{"default", "@default(value)", "default($0)", "Defines a default value for this field."},
+	{"relation", "@relation(fields: [], references: [])", "relation(fields: [$1], references: [$2])", "Defines a connection between two models."},
+	{"map", `@map("name")`, `map("$0")`, "Maps a field name from the Prisma schema to a different column name."},
+	{"updatedAt", "@updatedAt", "updatedAt", "Automatically stores the time when a record was last updated."},
+	{"ignore", "@ignore", "ignore", "Excludes the field from the generated client."},
+}
+
+// blockAttributes are the attributes available on models.
+var blockAttributes = []attributeDoc{
+	{"id", "@@id([field1, field2])", "id([$0])", "Defines a multi-field ID on the model."},
+	{"unique", "@@unique([field1, field2])", "unique([$0])", "Defines a compound unique constraint for the specified fields."},
+	{"index", "@@index([field1, field2])", "index([$0])", "Defines an index on the model."},
+	{"fulltext", "@@fulltext([field1, field2])", "fulltext([$0])", "Defines a full text index on the model."},
+	{"map", `@@map("name")`, `map("$0")`, "Maps the model name from the Prisma schema to a different table name."},
+	{"schema", `@@schema("name")`, `schema("$0")`, "Specifies the database schema the model belongs to."},
+	{"ignore", "@@ignore", "ignore", "Excludes the model from the generated client."},
+}
+
+// enumBlockAttributes are the attributes available on enums.
+var enumBlockAttributes = []attributeDoc{
+	{"map", `@@map("name")`, `map("$0")`, "Maps the enum name from the Prisma schema to a different database name."},
+	{"schema", `@@schema("name")`, `schema("$0")`, "Specifies the database schema the enum belongs to."},
+}
+
+// datasourceProperties are the properties of a datasource block.
+var datasourceProperties = []attributeDoc{
+	{"provider", `provider = "postgresql"`, `provider = "$0"`, "Describes which datasource connector to use."},
+	{"url", `url = env("DATABASE_URL")`, `url = env("$0")`, "Connection URL including authentication info."},
+	{"directUrl", `directUrl = env("DIRECT_URL")`, `directUrl = env("$0")`, "Connection URL for direct connection to the database."},
+	{"shadowDatabaseUrl", `shadowDatabaseUrl = env("SHADOW_DATABASE_URL")`, `shadowDatabaseUrl = env("$0")`, "Connection URL to the shadow database used by migrations."},
+	{"relationMode", `relationMode = "foreignKeys" | "prisma"`, `relationMode = "$0"`, "Sets the global relation mode for relations."},
+	{"schemas", `schemas = ["public"]`, `schemas = [$0]`, "The list of database schemas."},
+}
+
+// generatorProperties are the properties of a generator block.
+var generatorProperties = []attributeDoc{
+	{"provider", `provider = "prisma-client-go"`, `provider = "$0"`, "Describes which generator to use."},
+	{"output", `output = "./generated"`, `output = "$0"`, "Determines the location for the generated client."},
+	{"previewFeatures", `previewFeatures = []`, `previewFeatures = [$0]`, "Enables preview features."},
+	{"binaryTargets", `binaryTargets = ["native"]`, `binaryTargets = [$0]`, "Specifies the OS on which the client is going to run."},
+}
+
+// topLevelKeywords are the block keywords available outside of blocks.
+var topLevelKeywords = []attributeDoc{
+	{"model", "model Name {}", "model $1 {\n  $0\n}", "Defines a model, mapped to a table or collection."},
+	{"enum", "enum Name {}", "enum $1 {\n  $0\n}", "Defines an enum."},
+	{"type", "type Name {}", "type $1 {\n  $0\n}", "Defines a composite type (MongoDB only)."},
+	{"view", "view Name {}", "view $1 {\n  $0\n}", "Defines a view."},
+	{"datasource", "datasource db {}", "datasource $1 {\n  $0\n}", "Defines the database connection."},
+	{"generator", "generator client {}", "generator $1 {\n  $0\n}", "Defines a code generator."},
+}
+
+// providers are the supported datasource providers.
+var providers = []string{"postgresql", "mysql", "sqlite", "sqlserver", "mongodb", "cockroachdb"}
+
+// enclosingBlock returns the block that contains offset, if any.
+func enclosingBlock(text string, offset int) blockContext {
+	var current blockContext
+	for _, line := range strings.Split(text[:offset], "\n") {
+		line = stripCommentsAndStrings(line)
+		if m := blockHeaderPattern.FindStringSubmatch(line); m != nil {
+			current = blockContext{keyword: m[1], name: m[2]}
+			rest := line[strings.Index(line, "{")+1:]
+			if strings.Contains(rest, "}") {
+				current = blockContext{}
+			}
+			continue
+		}
+		if strings.Contains(line, "}") {
+			current = blockContext{}
+		}
+	}
+	return current
+}
+
+// stripCommentsAndStrings blanks out string literals and drops comments of a line.
+func stripCommentsAndStrings(line string) string {
+	line = stringLiteralPattern.ReplaceAllStringFunc(line, func(s string) string {
+		return `"` + strings.Repeat(" ", len(s)-2) + `"`
+	})
+	if idx := strings.Index(line, "//"); idx >= 0 {
+		line = line[:idx]
+	}
+	return line
+}
+
+// complete computes the completions at offset in doc.
+func complete(group *schemaGroup, doc *document, offset int) *validation.CompletionList {
+	list := &validation.CompletionList{}
+	text := doc.text
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	linePrefix := text[lineStart:offset]
+	block := enclosingBlock(text, offset)
+
+	switch block.keyword {
+	case "":
+		if !strings.ContainsAny(strings.TrimSpace(linePrefix), " \t") {
+			addAttributeDocs(list, topLevelKeywords, validation.CompletionItemKindKeyword, "")
+		}
+	case "datasource":
+		if providerValuePattern.MatchString(linePrefix) {
+			for _, provider := range providers {
+				list.AddItem(validation.CompletionItem{Label: provider, Kind: validation.CompletionItemKindConstant})
+			}
+		} else if !strings.Contains(linePrefix, "=") {
+			addAttributeDocs(list, datasourceProperties, validation.CompletionItemKindProperty, "")
+		}
+	case "generator":
+		if !strings.Contains(linePrefix, "=") {
+			addAttributeDocs(list, generatorProperties, validation.CompletionItemKindProperty, "")
+		}
+	case "model", "view", "type":
+		completeInModel(group, block, linePrefix, list)
+	case "enum":
+		if blockAttrPattern.MatchString(linePrefix) {
+			addAttributeDocs(list, enumBlockAttributes, validation.CompletionItemKindProperty, "@@")
+		}
+	}
+
+	return list
+}
+
+// completeInModel computes completions inside a model, view or composite type.
+func completeInModel(group *schemaGroup, block blockContext, linePrefix string, list *validation.CompletionList) {
+	switch {
+	case nativeTypePattern.MatchString(linePrefix):
+		m := nativeTypePattern.FindStringSubmatch(linePrefix)
+		if m[1] != group.datasourceName() {
+			return
+		}
+		fieldType := ""
+		if decl := fieldDeclPattern.FindStringSubmatch(linePrefix); decl != nil {
+			fieldType = decl[2]
+		}
+		addNativeTypes(group, fieldType, list)
+
+	case blockAttrPattern.MatchString(linePrefix):
+		addAttributeDocs(list, blockAttributes, validation.CompletionItemKindProperty, "@@")
+
+	case fieldAttrPattern.MatchString(linePrefix):
+		addAttributeDocs(list, fieldAttributes, validation.CompletionItemKindProperty, "@")
+		list.AddItem(validation.CompletionItem{
+			Label:  group.datasourceName(),
+			Kind:   validation.CompletionItemKindModule,
+			Detail: stringPtr("Native database types"),
+		})
+
+	case referencesPattern.MatchString(linePrefix):
+		// References point at fields of the related model, which is the type of this field
+		if decl := fieldDeclPattern.FindStringSubmatch(linePrefix); decl != nil {
+			addFieldNames(group.findModel(decl[2]), list)
+		}
+
+	case fieldsPattern.MatchString(linePrefix):
+		addFieldNames(group.findModel(block.name), list)
+
+	case fieldTypePattern.MatchString(linePrefix):
+		for _, scalar := range scalarTypes {
+			list.AddItem(validation.CompletionItem{
+				Label:         scalar.name,
+				Kind:          validation.CompletionItemKindTypeParameter,
+				Documentation: stringPtr(scalar.doc),
+			})
+		}
+		for _, top := range group.tops() {
+			switch top.(type) {
+			case *ast.Model:
+				list.AddItem(validation.CompletionItem{Label: top.GetName(), Kind: validation.CompletionItemKindReference, Detail: stringPtr("model")})
+			case *ast.Enum:
+				list.AddItem(validation.CompletionItem{Label: top.GetName(), Kind: validation.CompletionItemKindEnum, Detail: stringPtr("enum")})
+			case *ast.CompositeType:
+				list.AddItem(validation.CompletionItem{Label: top.GetName(), Kind: validation.CompletionItemKindStruct, Detail: stringPtr("type")})
+			}
+		}
+	}
+}
+
+// addAttributeDocs adds documented completions such as attributes or properties.
+func addAttributeDocs(list *validation.CompletionList, docs []attributeDoc, kind validation.CompletionItemKind, prefix string) {
+	snippet := validation.InsertTextFormatSnippet
+	for _, doc := range docs {
+		list.AddItem(validation.CompletionItem{
+			Label:            doc.name,
+			Kind:             kind,
+			Detail:           stringPtr(prefix + doc.name),
+			Documentation:    stringPtr(validation.FormatCompletionDocs(doc.example, doc.description, nil)),
+			InsertText:       stringPtr(doc.insertText),
+			InsertTextFormat: &snippet,
+		})
+	}
+}
+
+// addNativeTypes adds the native types of the datasource connector.
+// When fieldType is a scalar type, only compatible native types are offered.
+func addNativeTypes(group *schemaGroup, fieldType string, list *validation.CompletionList) {
+	connector := validation.NewBuiltinConnectors().GetConnector(group.provider())
+	if connector == nil {
+		return
+	}
+
+	constructors := connector.AvailableNativeTypeConstructors()
+	sort.Slice(constructors, func(i, j int) bool { return constructors[i].Name < constructors[j].Name })
+
+	snippet := validation.InsertTextFormatSnippet
+	for _, constructor := range constructors {
+		if fieldType != "" && !nativeTypeAllows(constructor, fieldType) {
+			continue
+		}
+		insertText := constructor.Name
+		if constructor.NumberOfArgs > 0 {
+			insertText += "($0)"
+		}
+		list.AddItem(validation.CompletionItem{
+			Label:            constructor.Name,
+			Kind:             validation.CompletionItemKindTypeParameter,
+			Detail:           stringPtr(fmt.Sprintf("%s native type", connector.Name())),
+			InsertText:       &insertText,
+			InsertTextFormat: &snippet,
+		})
+	}
+}
+
+// nativeTypeAllows reports whether a native type can be used with the scalar type.
+func nativeTypeAllows(constructor *validation.NativeTypeConstructor, fieldType string) bool {
+	isScalar := false
+	for _, scalar := range scalarTypes {
+		if scalar.name == fieldType {
+			isScalar = true
+			break
+		}
+	}
+	if !isScalar {
+		return true
+	}
+	for _, allowed := range constructor.AllowedTypes {
+		if allowed.FieldType != nil && allowed.FieldType.BuiltInScalar != nil && string(*allowed.FieldType.BuiltInScalar) == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// addFieldNames adds the field names of model as completions.
+func addFieldNames(model *ast.Model, list *validation.CompletionList) {
+	if model == nil {
+		return
+	}
+	for _, field := range model.Fields {
+		list.AddItem(validation.CompletionItem{
+			Label:  field.GetName(),
+			Kind:   validation.CompletionItemKindField,
+			Detail: stringPtr(field.GetTypeName() + field.Arity.String()),
+		})
+	}
+}
+
+// toProtocolCompletions converts completion items to their protocol form.
+func toProtocolCompletions(list *validation.CompletionList) *CompletionList {
+	result := &CompletionList{Items: make([]CompletionItem, 0, len(list.Items))}
+	for _, item := range list.Items {
+		converted := CompletionItem{
+			Label: item.Label,
+			Kind:  int(item.Kind),
+		}
+		if item.Detail != nil {
+			converted.Detail = *item.Detail
+		}
+		if item.Documentation != nil {
+			converted.Documentation = &MarkupContent{Kind: "markdown", Value: *item.Documentation}
+		}
+		if item.InsertText != nil {
+			converted.InsertText = *item.InsertText
+		}
+		if item.InsertTextFormat != nil {
+			converted.InsertTextFormat = int(*item.InsertTextFormat)
+		}
+		result.Items = append(result.Items, converted)
+	}
+	return result
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
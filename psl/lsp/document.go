@@ -0,0 +1,234 @@
+package lsp
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/satishbabariya/prisma-go/psl/core"
+	parser "github.com/satishbabariya/prisma-go/psl/parsing/v2"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// document is a schema file known to the server.
+type document struct {
+	uri  string
+	text string
+	// ast is the latest parse of the document. When the text has syntax
+	// errors it holds the declarations the parser recovered, so completions
+	// keep working while the user is typing.
+	ast *ast.SchemaAst
+	// valid reports whether the text parsed without syntax errors.
+	valid bool
+}
+
+// update replaces the document text and reparses it.
+func (d *document) update(text string) {
+	d.text = text
+	schema, err := parser.ParseSchemaString(d.uri, text)
+	d.valid = err == nil
+	if schema != nil {
+		d.ast = schema
+	}
+}
+
+// schemaGroup is the set of files that form one schema: every open document
+// and every .prisma file on disk that lives in the same directory.
+type schemaGroup struct {
+	docs []*document
+}
+
+// sourceFiles returns the group as source files keyed by URI.
+func (g *schemaGroup) sourceFiles() []core.SourceFile {
+	files := make([]core.SourceFile, len(g.docs))
+	for i, doc := range g.docs {
+		files[i] = core.NewSourceFile(doc.uri, doc.text)
+	}
+	return files
+}
+
+// get returns the document of the group with the given URI.
+func (g *schemaGroup) get(uri string) *document {
+	for _, doc := range g.docs {
+		if doc.uri == uri {
+			return doc
+		}
+	}
+	return nil
+}
+
+// tops returns the declarations of all documents in the group.
+func (g *schemaGroup) tops() []ast.Top {
+	var tops []ast.Top
+	for _, doc := range g.docs {
+		if doc.ast != nil {
+			tops = append(tops, doc.ast.Tops...)
+		}
+	}
+	return tops
+}
+
+// findTop returns the model, enum, composite type, datasource or generator named name.
+func (g *schemaGroup) findTop(name string) ast.Top {
+	for _, top := range g.tops() {
+		if _, ok := top.(*ast.ExtendedType); ok {
+			continue
+		}
+		if top.GetName() == name {
+			return top
+		}
+	}
+	return nil
+}
+
+// findModel returns the model or view named name.
+func (g *schemaGroup) findModel(name string) *ast.Model {
+	if model, ok := g.findTop(name).(*ast.Model); ok {
+		return model
+	}
+	return nil
+}
+
+// provider returns the provider of the first datasource in the group.
+func (g *schemaGroup) provider() string {
+	for _, top := range g.tops() {
+		if source, ok := top.(*ast.SourceConfig); ok {
+			if prop := source.GetProperty("provider"); prop != nil && prop.Value != nil {
+				if value, ok := prop.Value.AsStringValue(); ok {
+					return value.GetValue()
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// datasourceName returns the name of the first datasource, used as native type prefix.
+func (g *schemaGroup) datasourceName() string {
+	for _, top := range g.tops() {
+		if source, ok := top.(*ast.SourceConfig); ok {
+			return source.GetName()
+		}
+	}
+	return "db"
+}
+
+// uriToPath converts a file:// URI to a local path. Other URIs yield "".
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// pathToURI converts a local path to a file:// URI.
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// siblingSchemaFiles lists the .prisma files in the directory of uri.
+func siblingSchemaFiles(uri string) []string {
+	path := uriToPath(uri)
+	if path == "" {
+		return nil
+	}
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*.prisma"))
+	if err != nil {
+		return nil
+	}
+	return matches
+}
+
+// loadDiskDocument reads a schema file that is not open in the editor.
+func loadDiskDocument(path string) *document {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	doc := &document{uri: pathToURI(path)}
+	doc.update(string(content))
+	return doc
+}
+
+// directoryKey returns the key used to group documents of one schema.
+func directoryKey(uri string) string {
+	if idx := strings.LastIndex(uri, "/"); idx >= 0 {
+		return uri[:idx]
+	}
+	return uri
+}
+
+// sortDocuments orders documents by URI so diagnostics are deterministic.
+func sortDocuments(docs []*document) {
+	sort.Slice(docs, func(i, j int) bool { return docs[i].uri < docs[j].uri })
+}
+
+// offsetAt converts an LSP position into a byte offset in text.
+func offsetAt(text string, pos Position) int {
+	offset := 0
+	for line := 0; line < pos.Line; line++ {
+		idx := strings.IndexByte(text[offset:], '\n')
+		if idx < 0 {
+			return len(text)
+		}
+		offset += idx + 1
+	}
+
+	units := 0
+	for offset < len(text) && units < pos.Character {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if r == '\n' {
+			break
+		}
+		units += utf16.RuneLen(r)
+		offset += size
+	}
+	return offset
+}
+
+// positionAt converts a byte offset in text into an LSP position.
+func positionAt(text string, offset int) Position {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	line := strings.Count(text[:offset], "\n")
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+
+	character := 0
+	for _, r := range text[lineStart:offset] {
+		character += utf16.RuneLen(r)
+	}
+	return Position{Line: line, Character: character}
+}
+
+// rangeAt converts a byte range in text into an LSP range.
+func rangeAt(text string, start, end int) Range {
+	return Range{Start: positionAt(text, start), End: positionAt(text, end)}
+}
+
+// isIdentChar reports whether b can be part of an identifier.
+func isIdentChar(b byte) bool {
+	return b == '_' || b == '-' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= utf8.RuneSelf
+}
+
+// wordAt returns the identifier around offset and its byte range.
+func wordAt(text string, offset int) (string, int, int) {
+	start := offset
+	for start > 0 && isIdentChar(text[start-1]) {
+		start--
+	}
+	end := offset
+	for end < len(text) && isIdentChar(text[end]) {
+		end++
+	}
+	return text[start:end], start, end
+}
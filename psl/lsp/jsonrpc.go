@@ -0,0 +1,109 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// conn reads and writes Content-Length framed JSON-RPC messages.
+type conn struct {
+	in  *bufio.Reader
+	out io.Writer
+	mu  sync.Mutex
+}
+
+func newConn(in io.Reader, out io.Writer) *conn {
+	return &conn{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// read reads the next message. It returns io.EOF when the stream ends.
+func (c *conn) read() (*message, error) {
+	length := -1
+	for {
+		line, err := c.in.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length == -1 {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid header line %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %w", err)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.in, body); err != nil {
+		return nil, err
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, &ResponseError{Code: codeParseError, Message: err.Error()}
+	}
+	return &msg, nil
+}
+
+// write writes a single message.
+func (c *conn) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintf(c.out, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.out.Write(body)
+	return err
+}
+
+// reply sends the result of a request.
+func (c *conn) reply(id *json.RawMessage, result interface{}) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	rawResult := json.RawMessage(raw)
+	return c.write(&message{ID: id, Result: &rawResult})
+}
+
+// replyError sends an error response to a request.
+func (c *conn) replyError(id *json.RawMessage, respErr *ResponseError) error {
+	return c.write(&message{ID: id, Error: respErr})
+}
+
+// notify sends a notification to the client.
+func (c *conn) notify(method string, params interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(&message{Method: method, Params: raw})
+}
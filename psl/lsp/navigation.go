@@ -0,0 +1,395 @@
+package lsp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/satishbabariya/prisma-go/psl/formatting"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+	"github.com/satishbabariya/prisma-go/psl/validation"
+)
+
+// symbolKind classifies the identifier under the cursor.
+type symbolKind int
+
+const (
+	symbolTop symbolKind = iota + 1
+	symbolField
+	symbolScalar
+	symbolFieldAttribute
+	symbolBlockAttribute
+)
+
+// symbol is the identifier under the cursor together with what it refers to.
+type symbol struct {
+	kind symbolKind
+	name string
+	// model is the model owning the field for symbolField.
+	model string
+	start int
+	end   int
+}
+
+var (
+	topDeclPattern   = regexp.MustCompile(`^\s*(model|view|enum|type)\s+$`)
+	identNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// resolveSymbol finds the symbol at offset in doc.
+func resolveSymbol(group *schemaGroup, doc *document, offset int) *symbol {
+	text := doc.text
+	word, start, end := wordAt(text, offset)
+	if word == "" {
+		return nil
+	}
+	sym := &symbol{name: word, start: start, end: end}
+
+	if start > 0 && text[start-1] == '@' {
+		sym.kind = symbolFieldAttribute
+		if start > 1 && text[start-2] == '@' {
+			sym.kind = symbolBlockAttribute
+		}
+		return sym
+	}
+	if start > 0 && text[start-1] == '.' {
+		// Native types such as @db.VarChar are not symbols
+		return nil
+	}
+
+	lineStart := strings.LastIndexByte(text[:start], '\n') + 1
+	linePrefix := text[lineStart:start]
+	block := enclosingBlock(text, start)
+
+	switch block.keyword {
+	case "model", "view", "type":
+		switch {
+		case strings.TrimSpace(linePrefix) == "":
+			sym.kind = symbolField
+			sym.model = block.name
+			return sym
+		case referencesPattern.MatchString(linePrefix):
+			if decl := fieldDeclPattern.FindStringSubmatch(linePrefix); decl != nil {
+				sym.kind = symbolField
+				sym.model = decl[2]
+				return sym
+			}
+			return nil
+		case fieldsPattern.MatchString(linePrefix):
+			sym.kind = symbolField
+			sym.model = block.name
+			return sym
+		}
+	case "":
+		if !topDeclPattern.MatchString(linePrefix) {
+			return nil
+		}
+	}
+
+	if group.findTop(word) != nil {
+		sym.kind = symbolTop
+		return sym
+	}
+	for _, scalar := range scalarTypes {
+		if scalar.name == word {
+			sym.kind = symbolScalar
+			return sym
+		}
+	}
+	return nil
+}
+
+// findField returns the field named name of the model or composite type named model.
+func (g *schemaGroup) findField(model, name string) *ast.Field {
+	var fields []*ast.Field
+	switch top := g.findTop(model).(type) {
+	case *ast.Model:
+		fields = top.Fields
+	case *ast.CompositeType:
+		fields = top.Fields
+	}
+	for _, field := range fields {
+		if field.GetName() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// hover computes the hover documentation for the symbol at offset.
+func hover(group *schemaGroup, doc *document, offset int) *Hover {
+	sym := resolveSymbol(group, doc, offset)
+	if sym == nil {
+		return nil
+	}
+
+	var value string
+	switch sym.kind {
+	case symbolTop:
+		top := group.findTop(sym.name)
+		value = "```prisma\n" + strings.TrimSpace(renderTop(top)) + "\n```"
+		if docs := strings.TrimSpace(top.GetDocumentation()); docs != "" {
+			value += "\n___\n" + docs
+		}
+	case symbolField:
+		field := group.findField(sym.model, sym.name)
+		if field == nil {
+			return nil
+		}
+		value = "```prisma\n" + field.String() + "\n```"
+		if docs := strings.TrimSpace(field.Documentation.GetText()); docs != "" {
+			value += "\n___\n" + docs
+		}
+	case symbolScalar:
+		for _, scalar := range scalarTypes {
+			if scalar.name == sym.name {
+				value = "```prisma\n" + scalar.name + "\n```\n___\n" + scalar.doc
+			}
+		}
+	case symbolFieldAttribute, symbolBlockAttribute:
+		docs := fieldAttributes
+		if sym.kind == symbolBlockAttribute {
+			docs = blockAttributes
+		}
+		for _, attr := range docs {
+			if attr.name == sym.name {
+				value = validation.FormatCompletionDocs(attr.example, attr.description, nil)
+			}
+		}
+	}
+	if value == "" {
+		return nil
+	}
+
+	r := rangeAt(doc.text, sym.start, sym.end)
+	return &Hover{Contents: MarkupContent{Kind: "markdown", Value: value}, Range: &r}
+}
+
+// renderTop renders a single declaration as schema source.
+func renderTop(top ast.Top) string {
+	return formatting.NewRenderer().Render(&ast.SchemaAst{Tops: []ast.Top{top}})
+}
+
+// definition returns the declaration of the symbol at offset.
+func definition(group *schemaGroup, doc *document, offset int) *Location {
+	sym := resolveSymbol(group, doc, offset)
+	if sym == nil {
+		return nil
+	}
+
+	switch sym.kind {
+	case symbolTop:
+		if ident := topIdentifier(group.findTop(sym.name)); ident != nil {
+			return group.location(ident.Pos, len(ident.Name))
+		}
+	case symbolField:
+		if field := group.findField(sym.model, sym.name); field != nil && field.Name != nil {
+			return group.location(field.Name.Pos, len(field.Name.Name))
+		}
+	}
+	return nil
+}
+
+// location converts an AST position into a location inside the group.
+func (g *schemaGroup) location(pos lexer.Position, length int) *Location {
+	doc := g.get(pos.Filename)
+	if doc == nil {
+		return nil
+	}
+	return &Location{URI: doc.uri, Range: rangeAt(doc.text, pos.Offset, pos.Offset+length)}
+}
+
+// rename computes the edits renaming the symbol at offset to newName.
+func rename(group *schemaGroup, doc *document, offset int, newName string) (*WorkspaceEdit, error) {
+	if !identNamePattern.MatchString(newName) {
+		return nil, fmt.Errorf("%q is not a valid identifier", newName)
+	}
+	for _, d := range group.docs {
+		if !d.valid {
+			return nil, fmt.Errorf("cannot rename while %s has syntax errors", d.uri)
+		}
+	}
+
+	sym := resolveSymbol(group, doc, offset)
+	if sym == nil || sym.kind != symbolTop && sym.kind != symbolField {
+		return nil, fmt.Errorf("no renameable symbol at this position")
+	}
+
+	edits := &renameEdits{group: group, newName: newName, seen: make(map[string]bool)}
+	if sym.kind == symbolTop {
+		if _, ok := group.findTop(sym.name).(*ast.SourceConfig); ok {
+			return nil, fmt.Errorf("datasources cannot be renamed")
+		}
+		renameTop(group, sym.name, edits)
+	} else {
+		if group.findField(sym.model, sym.name) == nil {
+			return nil, fmt.Errorf("field %s.%s not found", sym.model, sym.name)
+		}
+		renameField(group, sym.model, sym.name, edits)
+	}
+	return &WorkspaceEdit{Changes: edits.changes}, nil
+}
+
+// renameEdits collects the edits of a rename, skipping duplicate positions.
+type renameEdits struct {
+	group   *schemaGroup
+	newName string
+	changes map[string][]TextEdit
+	seen    map[string]bool
+}
+
+// add replaces length bytes at pos with the new name.
+func (r *renameEdits) add(pos lexer.Position, length int) {
+	key := fmt.Sprintf("%s:%d", pos.Filename, pos.Offset)
+	if r.seen[key] {
+		return
+	}
+	loc := r.group.location(pos, length)
+	if loc == nil {
+		return
+	}
+	r.seen[key] = true
+	if r.changes == nil {
+		r.changes = make(map[string][]TextEdit)
+	}
+	r.changes[loc.URI] = append(r.changes[loc.URI], TextEdit{Range: loc.Range, NewText: r.newName})
+}
+
+// renameTop renames a model, enum or composite type and every field typed with it.
+func renameTop(group *schemaGroup, name string, edits *renameEdits) {
+	for _, top := range group.tops() {
+		if ident := topIdentifier(top); ident != nil && ident.Name == name {
+			edits.add(ident.Pos, len(name))
+		}
+		for _, field := range topFields(top) {
+			if field.Type != nil && !field.Type.IsUnsupported() && field.Type.Name == name {
+				edits.add(field.Type.Pos, len(name))
+			}
+		}
+	}
+}
+
+// renameField renames a field and every reference to it in relation and
+// block attributes.
+func renameField(group *schemaGroup, modelName, name string, edits *renameEdits) {
+	for _, top := range group.tops() {
+		if top.GetName() == modelName {
+			for _, field := range topFields(top) {
+				if field.GetName() == name && field.Name != nil {
+					edits.add(field.Name.Pos, len(name))
+				}
+				for _, attr := range field.Attributes {
+					if attr.GetName() == "relation" {
+						addFieldReferences(namedArgument(attr.Arguments, "fields"), name, edits)
+					}
+				}
+			}
+			if model, ok := top.(*ast.Model); ok {
+				for _, attr := range model.BlockAttributes {
+					switch attr.GetName() {
+					case "id", "unique", "index", "fulltext":
+						addFieldReferences(fieldsArgument(attr.Arguments), name, edits)
+					}
+				}
+			}
+		}
+
+		// Relation fields of other models pointing at this model reference its fields
+		for _, field := range topFields(top) {
+			if field.GetTypeName() != modelName {
+				continue
+			}
+			for _, attr := range field.Attributes {
+				if attr.GetName() == "relation" {
+					addFieldReferences(namedArgument(attr.Arguments, "references"), name, edits)
+				}
+			}
+		}
+	}
+}
+
+// addFieldReferences adds an edit for every element of array that names the field.
+func addFieldReferences(expr ast.Expression, name string, edits *renameEdits) {
+	if expr == nil {
+		return
+	}
+	array, ok := expr.AsArray()
+	if !ok {
+		return
+	}
+	for _, element := range array.Elements {
+		switch e := element.(type) {
+		case *ast.ConstantValue:
+			if e.Value == name {
+				edits.add(e.Pos, len(name))
+			}
+		case *ast.PathValue:
+			if len(e.Parts) == 1 && e.Parts[0] == name {
+				edits.add(e.Pos, len(name))
+			}
+		case *ast.FunctionCall:
+			// Fields with arguments such as title(sort: Desc)
+			if e.Name == name {
+				edits.add(e.Pos, len(name))
+			}
+		}
+	}
+}
+
+// namedArgument returns the value of the argument called name.
+func namedArgument(args *ast.ArgumentsList, name string) ast.Expression {
+	if args == nil {
+		return nil
+	}
+	for _, arg := range args.Arguments {
+		if arg.GetName() == name {
+			return arg.Value
+		}
+	}
+	return nil
+}
+
+// fieldsArgument returns the field list of a block attribute, which is either
+// the first positional argument or the argument called fields.
+func fieldsArgument(args *ast.ArgumentsList) ast.Expression {
+	if args == nil {
+		return nil
+	}
+	if value := namedArgument(args, "fields"); value != nil {
+		return value
+	}
+	if len(args.Arguments) > 0 && !args.Arguments[0].IsNamed() {
+		return args.Arguments[0].Value
+	}
+	return nil
+}
+
+// topFields returns the fields of a model or composite type.
+func topFields(top ast.Top) []*ast.Field {
+	switch t := top.(type) {
+	case *ast.Model:
+		return t.Fields
+	case *ast.CompositeType:
+		return t.Fields
+	}
+	return nil
+}
+
+// topIdentifier returns the name identifier of a declaration.
+func topIdentifier(top ast.Top) *ast.Identifier {
+	switch t := top.(type) {
+	case *ast.Model:
+		return t.Name
+	case *ast.Enum:
+		return t.Name
+	case *ast.CompositeType:
+		return t.Name
+	case *ast.SourceConfig:
+		return t.Name
+	case *ast.GeneratorConfig:
+		return t.Name
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+// Package lsp implements a Language Server Protocol server for Prisma schemas.
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/satishbabariya/prisma-go/internal/debug"
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+	"github.com/satishbabariya/prisma-go/psl/formatting"
+)
+
+// Server is a language server speaking JSON-RPC over a pair of streams.
+type Server struct {
+	conn *conn
+	// open holds the documents opened by the client, keyed by URI.
+	open     map[string]*document
+	shutdown bool
+}
+
+// NewServer creates a server reading requests from in and writing responses to out.
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		conn: newConn(in, out),
+		open: make(map[string]*document),
+	}
+}
+
+// Serve handles messages until the client sends exit or closes the stream.
+func (s *Server) Serve() error {
+	for {
+		msg, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if respErr, ok := err.(*ResponseError); ok {
+			if err := s.conn.replyError(nil, respErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a single request or notification.
+func (s *Server) handle(msg *message) error {
+	debug.Debug("LSP message", "method", msg.Method)
+
+	result, err := s.dispatch(msg)
+	if msg.ID == nil {
+		// Notifications never get a response
+		if err != nil {
+			debug.Warn("LSP notification failed", "method", msg.Method, "error", err)
+		}
+		return nil
+	}
+
+	if err != nil {
+		respErr, ok := err.(*ResponseError)
+		if !ok {
+			respErr = &ResponseError{Code: codeRequestFailed, Message: err.Error()}
+		}
+		return s.conn.replyError(msg.ID, respErr)
+	}
+	return s.conn.reply(msg.ID, result)
+}
+
+// dispatch runs the handler of a method and returns its result.
+func (s *Server) dispatch(msg *message) (interface{}, error) {
+	switch msg.Method {
+	case "initialize":
+		return s.initialize(), nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc := &document{uri: params.TextDocument.URI}
+		doc.update(params.TextDocument.Text)
+		s.open[doc.uri] = doc
+		return nil, s.publishDiagnostics(doc.uri)
+
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc, ok := s.open[params.TextDocument.URI]
+		if !ok || len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		doc.update(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		return nil, s.publishDiagnostics(doc.uri)
+
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		delete(s.open, params.TextDocument.URI)
+		// Clear the diagnostics of the closed document
+		return nil, s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		group, doc, err := s.resolvePosition(msg, &params)
+		if err != nil {
+			return nil, err
+		}
+		return toProtocolCompletions(complete(group, doc, offsetAt(doc.text, params.Position))), nil
+
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		group, doc, err := s.resolvePosition(msg, &params)
+		if err != nil {
+			return nil, err
+		}
+		return hover(group, doc, offsetAt(doc.text, params.Position)), nil
+
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		group, doc, err := s.resolvePosition(msg, &params)
+		if err != nil {
+			return nil, err
+		}
+		return definition(group, doc, offsetAt(doc.text, params.Position)), nil
+
+	case "textDocument/rename":
+		var params RenameParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		group, doc, err := s.documentGroup(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return rename(group, doc, offsetAt(doc.text, params.Position), params.NewName)
+
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		_, doc, err := s.documentGroup(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return format(doc, params.Options)
+	}
+
+	if msg.ID == nil {
+		return nil, nil
+	}
+	return nil, &ResponseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", msg.Method)}
+}
+
+// initialize returns the capabilities of the server.
+func (s *Server) initialize() *InitializeResult {
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: syncFull,
+			CompletionProvider: &CompletionOptions{
+				TriggerCharacters: []string{"@", ".", "\"", "["},
+			},
+			HoverProvider:              true,
+			DefinitionProvider:         true,
+			RenameProvider:             true,
+			DocumentFormattingProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: "prisma-go"},
+	}
+}
+
+// decodeParams unmarshals the parameters of msg into params.
+func decodeParams(msg *message, params interface{}) error {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &ResponseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// resolvePosition decodes position params and returns the addressed document.
+func (s *Server) resolvePosition(msg *message, params *TextDocumentPositionParams) (*schemaGroup, *document, error) {
+	if err := decodeParams(msg, params); err != nil {
+		return nil, nil, err
+	}
+	return s.documentGroup(params.TextDocument.URI)
+}
+
+// documentGroup returns the open document with the given URI and the schema it belongs to.
+func (s *Server) documentGroup(uri string) (*schemaGroup, *document, error) {
+	doc, ok := s.open[uri]
+	if !ok {
+		return nil, nil, &ResponseError{Code: codeInvalidParams, Message: fmt.Sprintf("document not open: %s", uri)}
+	}
+	return s.group(uri), doc, nil
+}
+
+// group collects the schema files belonging to the same schema as uri:
+// the open documents of its directory and the unopened .prisma files next to it.
+func (s *Server) group(uri string) *schemaGroup {
+	key := directoryKey(uri)
+	group := &schemaGroup{}
+	for openURI, doc := range s.open {
+		if directoryKey(openURI) == key {
+			group.docs = append(group.docs, doc)
+		}
+	}
+	for _, path := range siblingSchemaFiles(uri) {
+		if _, ok := s.open[pathToURI(path)]; ok {
+			continue
+		}
+		if doc := loadDiskDocument(path); doc != nil {
+			group.docs = append(group.docs, doc)
+		}
+	}
+	sortDocuments(group.docs)
+	return group
+}
+
+// publishDiagnostics validates the schema of uri and publishes diagnostics
+// for every open document that is part of it.
+func (s *Server) publishDiagnostics(uri string) error {
+	group := s.group(uri)
+	_, diags := psl.ValidateSchemaFiles(group.sourceFiles())
+
+	for i, doc := range group.docs {
+		if _, ok := s.open[doc.uri]; !ok {
+			continue
+		}
+		fileDiags := diags.ForFile(diagnostics.FileID(i))
+		result := []Diagnostic{}
+		for _, err := range fileDiags.Errors() {
+			result = append(result, toProtocolDiagnostic(doc.text, err.Span(), err.Message(), SeverityError))
+		}
+		for _, warn := range fileDiags.Warnings() {
+			result = append(result, toProtocolDiagnostic(doc.text, warn.Span(), warn.Message(), SeverityWarning))
+		}
+		if err := s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: doc.uri, Diagnostics: result}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toProtocolDiagnostic converts a schema diagnostic into its protocol form.
+func toProtocolDiagnostic(text string, span diagnostics.Span, message string, severity int) Diagnostic {
+	return Diagnostic{
+		Range:    rangeAt(text, span.Start, span.End),
+		Severity: severity,
+		Source:   "prisma-go",
+		Message:  message,
+	}
+}
+
+// format reformats doc and returns an edit replacing the whole document.
+func format(doc *document, options FormattingOptions) ([]TextEdit, error) {
+	tabSize := options.TabSize
+	if tabSize <= 0 {
+		tabSize = 2
+	}
+	formatted, err := formatting.Reformat(doc.text, tabSize)
+	if err != nil {
+		return nil, &ResponseError{Code: codeRequestFailed, Message: err.Error()}
+	}
+	if formatted == doc.text {
+		return []TextEdit{}, nil
+	}
+	return []TextEdit{{
+		Range:   rangeAt(doc.text, 0, len(doc.text)),
+		NewText: formatted,
+	}}, nil
+}
@@ -0,0 +1,336 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+/// A registered user.
+model User {
+  id    Int    @id @default(autoincrement())
+  email String @unique
+  posts Post[]
+}
+`
+
+const testPostSchema = `model Post {
+  id       Int  @id
+  authorId Int
+  author   User @relation(fields: [authorId], references: [id])
+
+  @@index([authorId])
+}
+`
+
+// session drives a server with a scripted sequence of messages.
+type session struct {
+	t      *testing.T
+	input  bytes.Buffer
+	nextID int
+}
+
+func (s *session) send(id int, method string, params interface{}) {
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	if id > 0 {
+		msg["id"] = id
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	fmt.Fprintf(&s.input, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func (s *session) request(method string, params interface{}) int {
+	s.nextID++
+	s.send(s.nextID, method, params)
+	return s.nextID
+}
+
+func (s *session) notify(method string, params interface{}) {
+	s.send(0, method, params)
+}
+
+// run serves the scripted input and returns the decoded output messages.
+func (s *session) run() []message {
+	var out bytes.Buffer
+	if err := NewServer(&s.input, &out).Serve(); err != nil {
+		s.t.Fatalf("Serve failed: %v", err)
+	}
+
+	var messages []message
+	reader := newConn(bufio.NewReader(&out), nil)
+	for {
+		msg, err := reader.read()
+		if err != nil {
+			break
+		}
+		messages = append(messages, *msg)
+	}
+	return messages
+}
+
+func findResponse(t *testing.T, messages []message, id int, result interface{}) {
+	t.Helper()
+	for _, msg := range messages {
+		if msg.ID == nil || string(*msg.ID) != fmt.Sprint(id) {
+			continue
+		}
+		if msg.Error != nil {
+			t.Fatalf("request %d failed: %s", id, msg.Error.Message)
+		}
+		if msg.Result == nil {
+			// A null result decodes to a nil RawMessage
+			return
+		}
+		if err := json.Unmarshal(*msg.Result, result); err != nil {
+			t.Fatalf("failed to decode response %d: %v", id, err)
+		}
+		return
+	}
+	t.Fatalf("no response for request %d", id)
+}
+
+func position(text, marker string, delta int) Position {
+	return positionAt(text, strings.Index(text, marker)+delta)
+}
+
+func TestServerSession(t *testing.T) {
+	dir := t.TempDir()
+	userURI := pathToURI(filepath.Join(dir, "schema.prisma"))
+	postURI := pathToURI(filepath.Join(dir, "post.prisma"))
+	// post.prisma is not opened by the client and must be picked up from disk
+	if err := os.WriteFile(filepath.Join(dir, "post.prisma"), []byte(testPostSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	broken := strings.Replace(testSchema, "posts Post[]", "posts Post[]\n  role  Role", 1)
+	withAt := strings.Replace(testSchema, "email String @unique", "email String @", 1)
+	unformatted := strings.Replace(testSchema, "email String @unique", "email    String    @unique", 1)
+
+	s := &session{t: t}
+	initID := s.request("initialize", map[string]interface{}{})
+	s.notify("initialized", map[string]interface{}{})
+	s.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": TextDocumentItem{URI: userURI, LanguageID: "prisma", Version: 1, Text: broken},
+	})
+	s.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   TextDocumentIdentifier{URI: userURI},
+		"contentChanges": []TextDocumentContentChangeEvent{{Text: withAt}},
+	})
+	completionID := s.request("textDocument/completion", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: userURI},
+		Position:     position(withAt, "String @", len("String @")),
+	})
+	s.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   TextDocumentIdentifier{URI: userURI},
+		"contentChanges": []TextDocumentContentChangeEvent{{Text: testSchema}},
+	})
+	hoverID := s.request("textDocument/hover", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: userURI},
+		Position:     position(testSchema, "model User", len("model U")),
+	})
+	definitionID := s.request("textDocument/definition", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: userURI},
+		Position:     position(testSchema, "Post[]", 1),
+	})
+	renameID := s.request("textDocument/rename", RenameParams{
+		TextDocument: TextDocumentIdentifier{URI: userURI},
+		Position:     position(testSchema, "User {", 0),
+		NewName:      "Account",
+	})
+	s.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   TextDocumentIdentifier{URI: userURI},
+		"contentChanges": []TextDocumentContentChangeEvent{{Text: unformatted}},
+	})
+	formattingID := s.request("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: userURI},
+		Options:      FormattingOptions{TabSize: 2, InsertSpaces: true},
+	})
+	unknownID := s.request("textDocument/unknown", map[string]interface{}{})
+	shutdownID := s.request("shutdown", nil)
+	s.notify("exit", nil)
+
+	messages := s.run()
+
+	var initResult InitializeResult
+	findResponse(t, messages, initID, &initResult)
+	if !initResult.Capabilities.HoverProvider || initResult.Capabilities.CompletionProvider == nil {
+		t.Errorf("unexpected capabilities: %+v", initResult.Capabilities)
+	}
+
+	var published []PublishDiagnosticsParams
+	for _, msg := range messages {
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var params PublishDiagnosticsParams
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				t.Fatal(err)
+			}
+			published = append(published, params)
+		}
+	}
+	if len(published) == 0 || len(published[0].Diagnostics) != 1 {
+		t.Fatalf("expected one diagnostic after opening, got %+v", published)
+	}
+	diag := published[0].Diagnostics[0]
+	if !strings.Contains(diag.Message, "Role") || diag.Range.Start.Line != 10 {
+		t.Errorf("unexpected diagnostic: %+v", diag)
+	}
+	for _, params := range published {
+		if params.URI != userURI {
+			t.Errorf("diagnostics published for unopened document %s", params.URI)
+		}
+	}
+
+	var completions CompletionList
+	findResponse(t, messages, completionID, &completions)
+	labels := make(map[string]bool)
+	for _, item := range completions.Items {
+		labels[item.Label] = true
+	}
+	for _, want := range []string{"id", "unique", "default", "db"} {
+		if !labels[want] {
+			t.Errorf("completion %q missing, got %v", want, labels)
+		}
+	}
+
+	var hoverResult Hover
+	findResponse(t, messages, hoverID, &hoverResult)
+	if !strings.Contains(hoverResult.Contents.Value, "model User") || !strings.Contains(hoverResult.Contents.Value, "A registered user.") {
+		t.Errorf("unexpected hover: %q", hoverResult.Contents.Value)
+	}
+
+	var location Location
+	findResponse(t, messages, definitionID, &location)
+	if location.URI != postURI || location.Range.Start != (Position{Line: 0, Character: 6}) {
+		t.Errorf("unexpected definition: %+v", location)
+	}
+
+	var edit WorkspaceEdit
+	findResponse(t, messages, renameID, &edit)
+	if len(edit.Changes[userURI]) != 1 || len(edit.Changes[postURI]) != 1 {
+		t.Errorf("unexpected rename edits: %+v", edit.Changes)
+	}
+	if edits := edit.Changes[postURI]; len(edits) == 1 && edits[0].Range.Start != (Position{Line: 3, Character: 11}) {
+		t.Errorf("unexpected rename edit in post.prisma: %+v", edits[0])
+	}
+
+	var edits []TextEdit
+	findResponse(t, messages, formattingID, &edits)
+	if len(edits) != 1 || !strings.Contains(edits[0].NewText, "email String @unique") {
+		t.Errorf("unexpected formatting edits: %+v", edits)
+	}
+
+	found := false
+	for _, msg := range messages {
+		if msg.ID != nil && string(*msg.ID) == fmt.Sprint(unknownID) {
+			found = msg.Error != nil && msg.Error.Code == codeMethodNotFound
+		}
+	}
+	if !found {
+		t.Errorf("expected method not found error for unknown request")
+	}
+
+	var shutdownResult interface{}
+	findResponse(t, messages, shutdownID, &shutdownResult)
+}
+
+func TestServerSemanticDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	userURI := pathToURI(filepath.Join(dir, "schema.prisma"))
+	postURI := pathToURI(filepath.Join(dir, "post.prisma"))
+	// The schema parses, but Post has two @id fields and an unknown attribute
+	post := strings.Replace(testPostSchema, "authorId Int", "authorId Int  @id @primary", 1)
+
+	s := &session{t: t}
+	s.request("initialize", map[string]interface{}{})
+	for uri, text := range map[string]string{userURI: testSchema, postURI: post} {
+		s.notify("textDocument/didOpen", map[string]interface{}{
+			"textDocument": TextDocumentItem{URI: uri, LanguageID: "prisma", Version: 1, Text: text},
+		})
+	}
+	s.notify("exit", nil)
+
+	// The diagnostics of the last publish of each document count
+	latest := make(map[string][]Diagnostic)
+	for _, msg := range s.run() {
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var params PublishDiagnosticsParams
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				t.Fatal(err)
+			}
+			latest[params.URI] = params.Diagnostics
+		}
+	}
+	if diags := latest[userURI]; len(diags) != 0 {
+		t.Errorf("unexpected diagnostics for schema.prisma: %+v", diags)
+	}
+	var messages []string
+	for _, diag := range latest[postURI] {
+		messages = append(messages, diag.Message)
+		if strings.Contains(diag.Message, "@primary") && diag.Range.Start.Line != 2 {
+			t.Errorf("%q is reported on line %d, want the authorId field", diag.Message, diag.Range.Start.Line)
+		}
+	}
+	for _, want := range []string{"At most one field must be marked as the id field", `Attribute not known: "@primary"`} {
+		if !strings.Contains(strings.Join(messages, "\n"), want) {
+			t.Errorf("post.prisma diagnostics %q do not contain %q", messages, want)
+		}
+	}
+}
+
+func TestRenameField(t *testing.T) {
+	dir := t.TempDir()
+	user := &document{uri: pathToURI(filepath.Join(dir, "schema.prisma"))}
+	user.update(testSchema)
+	post := &document{uri: pathToURI(filepath.Join(dir, "post.prisma"))}
+	post.update(testPostSchema)
+	group := &schemaGroup{docs: []*document{post, user}}
+
+	// Renaming User.id also renames the references of Post.author
+	edit, err := rename(group, user, strings.Index(testSchema, "id "), "userId")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edit.Changes[user.uri]) != 1 || len(edit.Changes[post.uri]) != 1 {
+		t.Fatalf("unexpected edits: %+v", edit.Changes)
+	}
+
+	// Renaming Post.authorId renames the declaration, @relation fields and @@index
+	edit, err = rename(group, post, strings.Index(testPostSchema, "authorId"), "ownerId")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edit.Changes[post.uri]) != 3 {
+		t.Fatalf("unexpected edits: %+v", edit.Changes)
+	}
+
+	if _, err := rename(group, post, strings.Index(testPostSchema, "authorId"), "not valid"); err == nil {
+		t.Error("expected error for invalid identifier")
+	}
+}
+
+func TestOffsetPositionRoundTrip(t *testing.T) {
+	text := "model Ünïcode {\n  name String // 😀 emoji\n}\n"
+	for offset := 0; offset <= len(text); offset++ {
+		pos := positionAt(text, offset)
+		if got := offsetAt(text, pos); got != offset && !isContinuation(text, offset) {
+			t.Errorf("offset %d -> %+v -> %d", offset, pos, got)
+		}
+	}
+}
+
+func isContinuation(text string, offset int) bool {
+	return offset < len(text) && text[offset]&0xC0 == 0x80
+}
@@ -2,14 +2,14 @@
 package parsing
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/satishbabariya/prisma-go/internal/debug"
 	"github.com/satishbabariya/prisma-go/psl/core"
 	"github.com/satishbabariya/prisma-go/psl/diagnostics"
-	"github.com/satishbabariya/prisma-go/psl/parsing/ast"
-	v2 "github.com/satishbabariya/prisma-go/psl/parsing/v2"
-	v2ast "github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+	parser "github.com/satishbabariya/prisma-go/psl/parsing/v2"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
 )
 
 // StringLiteralValue transforms the input string into a valid PSL string literal.
@@ -53,62 +53,61 @@ func StringLiteralValue(s string) string {
 }
 
 // ParseSchema parses a Prisma schema string into an AST.
+// Syntax errors are reported as diagnostics; the returned AST holds every
+// declaration that could be recovered.
 func ParseSchema(input string) (*ast.SchemaAst, diagnostics.Diagnostics) {
-	debug.Debug("Starting schema parsing", "inputLength", len(input))
-	diags := diagnostics.NewDiagnostics()
-
-	debug.Debug("Parsing with v2 parser")
-	v2Schema, err := v2.ParseSchemaString("schema.prisma", input)
-	if err != nil {
-		debug.Error("Parser v2 error", "error", err)
-		// Report parser error
-		// For now simple wrapper, can be improved to extract line/col from error if available in v2
-		span := diagnostics.NewSpan(0, len(input), diagnostics.FileIDZero)
-		diags.PushError(diagnostics.NewDatamodelError(
-			fmt.Sprintf("Parser error: %v", err),
-			span,
-		))
-		return &ast.SchemaAst{Tops: []ast.Top{}}, diags
-	}
-
-	debug.Debug("Converting v2 AST to legacy AST")
-	astResult := convertSchema(v2Schema)
-	debug.Debug("AST parsing and conversion completed", "topLevelCount", len(astResult.Tops))
-
-	return astResult, diags
+	return ParseSourceFile(core.NewSourceFile("schema.prisma", input), diagnostics.FileIDZero)
 }
 
 // ParseSchemaFromSourceFile parses a Prisma schema from a source file.
 func ParseSchemaFromSourceFile(file core.SourceFile) (*ast.SchemaAst, diagnostics.Diagnostics) {
-	debug.Debug("Parsing schema from source file", "path", file.Path, "dataLength", len(file.Data))
-	return ParseSchema(file.Data)
+	return ParseSourceFile(file, diagnostics.FileIDZero)
+}
+
+// ParseSchemaV2 parses a Prisma schema string into an AST.
+//
+// Deprecated: ParseSchema returns the same AST since the legacy AST was
+// removed. ParseSchemaV2 will be removed in the next release.
+func ParseSchemaV2(input string) (*ast.SchemaAst, diagnostics.Diagnostics) {
+	return ParseSchema(input)
 }
 
-// ParseSchemaV2 parses a Prisma schema string into a v2 AST.
-// This returns the v2 AST directly without conversion to legacy AST.
-func ParseSchemaV2(input string) (*v2ast.SchemaAst, diagnostics.Diagnostics) {
-	debug.Debug("Starting v2 schema parsing", "inputLength", len(input))
+// ParseSchemaFromSourceFileV2 parses a Prisma schema from a source file.
+//
+// Deprecated: use ParseSchemaFromSourceFile, or ParseSourceFile for a file
+// of a multi-file schema. ParseSchemaFromSourceFileV2 will be removed in the
+// next release.
+func ParseSchemaFromSourceFileV2(file core.SourceFile) (*ast.SchemaAst, diagnostics.Diagnostics) {
+	return ParseSchemaFromSourceFile(file)
+}
+
+// ParseSourceFile parses a source file of a possibly multi-file schema.
+// Every diagnostic span carries fileID. The parser recovers from syntax
+// errors, so all of them are reported at once and the AST is partial
+// rather than empty.
+func ParseSourceFile(file core.SourceFile, fileID diagnostics.FileID) (*ast.SchemaAst, diagnostics.Diagnostics) {
+	debug.Debug("Parsing schema", "path", file.Path, "dataLength", len(file.Data))
 	diags := diagnostics.NewDiagnostics()
 
-	debug.Debug("Parsing with v2 parser")
-	v2Schema, err := v2.ParseSchemaString("schema.prisma", input)
+	schema, err := parser.ParseSchemaString(file.Path, file.Data)
 	if err != nil {
-		debug.Error("Parser v2 error", "error", err)
-		// Report parser error
-		span := diagnostics.NewSpan(0, len(input), diagnostics.FileIDZero)
-		diags.PushError(diagnostics.NewDatamodelError(
-			fmt.Sprintf("Parser error: %v", err),
-			span,
-		))
-		return &v2ast.SchemaAst{Tops: []v2ast.Top{}}, diags
+		debug.Debug("Schema has syntax errors", "path", file.Path, "error", err)
+		var errs parser.ErrorList
+		if !errors.As(err, &errs) {
+			errs = parser.ErrorList{{Msg: err.Error()}}
+		}
+		for _, e := range errs {
+			offset := e.Pos.Offset
+			if offset > len(file.Data) {
+				offset = len(file.Data)
+			}
+			diags.PushError(diagnostics.NewDatamodelError(e.Msg, diagnostics.NewSpan(offset, offset, fileID)))
+		}
+	}
+	if schema == nil {
+		schema = &ast.SchemaAst{Tops: []ast.Top{}}
 	}
 
-	debug.Debug("v2 AST parsing completed", "topLevelCount", len(v2Schema.Tops))
-	return v2Schema, diags
-}
-
-// ParseSchemaFromSourceFileV2 parses a Prisma schema from a source file into v2 AST.
-func ParseSchemaFromSourceFileV2(file core.SourceFile) (*v2ast.SchemaAst, diagnostics.Diagnostics) {
-	debug.Debug("Parsing schema from source file (v2)", "path", file.Path, "dataLength", len(file.Data))
-	return ParseSchemaV2(file.Data)
+	debug.Debug("Schema parsed", "path", file.Path, "topLevelCount", len(schema.Tops))
+	return schema, diags
 }
@@ -0,0 +1,36 @@
+package parsing
+
+import (
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl/core"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+func TestParseSourceFileRecovers(t *testing.T) {
+	input := `model User {
+  id    Int    @id
+  email String @unique(
+  name  String
+}
+
+model Post {
+  id    Int    @id
+  title String @default("a" "b")
+}
+`
+	schema, diags := ParseSourceFile(core.NewSourceFile("schema.prisma", input), diagnostics.FileID(2))
+
+	errs := diags.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), errs)
+	}
+	for _, err := range errs {
+		if err.Span().FileID != diagnostics.FileID(2) {
+			t.Errorf("expected file ID 2, got %v", err.Span().FileID)
+		}
+	}
+	if len(schema.Models()) != 2 {
+		t.Errorf("expected both models to be recovered, got %d", len(schema.Models()))
+	}
+}
@@ -7,7 +7,7 @@ import (
 // Identifier represents a named identifier in the schema.
 type Identifier struct {
 	Pos  lexer.Position
-	Name string `(@Ident | @Keyword) ("." (@Ident | @Keyword))*`
+	Name string `@((Ident | Keyword) ("." (Ident | Keyword))*)`
 }
 
 // String returns the identifier name.
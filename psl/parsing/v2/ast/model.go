@@ -1,6 +1,10 @@
 package ast
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
@@ -58,6 +62,28 @@ func (m *Model) GetDocumentation() string {
 	return m.Documentation.GetText()
 }
 
+// SoftDeleteField returns the field named by the /// @softDelete(field)
+// comment of the model, or "" when it has none. The comment must close on
+// its own line.
+func (m *Model) SoftDeleteField() (string, error) {
+	const marker = "@softDelete("
+	doc := m.GetDocumentation()
+	start := strings.Index(doc, marker)
+	if start < 0 {
+		return "", nil
+	}
+	line, _, _ := strings.Cut(doc[start+len(marker):], "\n")
+	field, _, ok := strings.Cut(line, ")")
+	if !ok {
+		return "", fmt.Errorf("%s%s is not closed", marker, line)
+	}
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return "", errors.New("@softDelete needs a field, e.g. @softDelete(deletedAt)")
+	}
+	return field, nil
+}
+
 // ModelID is an opaque identifier for a model in the schema.
 type ModelID int
 
@@ -0,0 +1,274 @@
+package cst_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	schema "github.com/satishbabariya/prisma-go/psl/parsing/v2"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/cst"
+)
+
+const editSchema = `// Main database
+datasource db {
+  provider = "postgresql" // overridden in CI
+  url      = env("DATABASE_URL")
+}
+
+/// A registered user
+model User {
+  id    Int     @id @default(autoincrement())
+  email String  @unique // login name
+  posts Post[]
+
+  // keep in sync with the auth service
+  @@map("users")
+}
+
+
+model Post {
+  id       Int  @id
+  authorId Int
+  author   User @relation(fields: [authorId], references: [id])
+}
+
+enum Role {
+  USER
+  ADMIN // full access
+}
+`
+
+func TestRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"\n\n",
+		editSchema,
+		strings.ReplaceAll(editSchema, "\n", "\r\n"),
+		"model A { id Int @id }",
+		"model A {}\nmodel B {\n\tid Int @id\n}",
+		"model A {\n  id Int @id\n", // unterminated
+		"/* header */ model A {\n  id Int /* inline */ @id\n  @@index([id,\n    name], map: \"x\")\n}\n",
+		"model Ünïcode {\n  naïve String @default(\"a \\\" b\")\n}\n",
+		"model A {\n  field Unsupported(\"circle\")? @default(dbgenerated(\"x\"))\n}\n",
+		"garbage $ % ^ model\nmodel A {\n  ??? !!\n}\ntrailing",
+		"model A {\n  s String @default(\"unterminated\n}\n",
+		"extend type Address {\n  zip String\n}\n",
+	}
+	inputs = append(inputs, testSchemas(t)...)
+
+	for _, input := range inputs {
+		if got := cst.Parse(input).String(); got != input {
+			t.Errorf("round trip mismatch\ninput:\n%q\ngot:\n%q", input, got)
+		}
+	}
+
+	// Every prefix of a schema must round trip as well
+	for i := range editSchema {
+		if got := cst.Parse(editSchema[:i]).String(); got != editSchema[:i] {
+			t.Fatalf("round trip mismatch for prefix %q", editSchema[:i])
+		}
+	}
+}
+
+// testSchemas returns the schemas embedded as raw strings in the parser tests.
+func testSchemas(t *testing.T) []string {
+	t.Helper()
+	files, err := filepath.Glob("../*_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schemas []string
+	fset := token.NewFileSet()
+	for _, path := range files {
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING && strings.HasPrefix(lit.Value, "`") {
+				value, err := strconv.Unquote(lit.Value)
+				if err == nil && strings.Contains(value, "{") {
+					schemas = append(schemas, value)
+				}
+			}
+			return true
+		})
+	}
+	if len(schemas) == 0 {
+		t.Fatal("no schemas found in parser tests")
+	}
+	return schemas
+}
+
+func TestTree(t *testing.T) {
+	file := cst.Parse(editSchema)
+
+	var names []string
+	for _, block := range file.Blocks() {
+		names = append(names, block.Keyword()+" "+block.Name())
+	}
+	if got := strings.Join(names, ", "); got != "datasource db, model User, model Post, enum Role" {
+		t.Fatalf("unexpected blocks: %s", got)
+	}
+
+	user := file.Block("User")
+	if !strings.HasPrefix(user.String(), "/// A registered user\nmodel User {") {
+		t.Errorf("doc comment not attached to block: %q", user.String())
+	}
+	if len(user.Fields()) != 3 || len(user.BlockAttributes()) != 1 {
+		t.Errorf("unexpected members: %d fields, %d block attributes", len(user.Fields()), len(user.BlockAttributes()))
+	}
+	posts := user.Field("posts")
+	if posts.Type().Name() != "Post" || !posts.Type().IsList() || posts.Type().IsOptional() {
+		t.Errorf("unexpected type %s", posts.Type())
+	}
+	id := user.Field("id")
+	if attr := id.Attribute("default"); attr == nil || attr.Arguments() != "autoincrement()" {
+		t.Errorf("unexpected @default: %v", attr)
+	}
+	if got := user.BlockAttributes()[0].Attributes()[0].Name(); got != "map" {
+		t.Errorf("unexpected block attribute %s", got)
+	}
+	if got := file.Block("db").Property("url").Value(); got != `env("DATABASE_URL")` {
+		t.Errorf("unexpected property value %s", got)
+	}
+	if block := cst.Parse("extend type Address {\n  zip String\n}\n").Block("Address"); block == nil || block.Keyword() != "extend" || len(block.Fields()) != 1 {
+		t.Errorf("extend block not recognized: %v", block)
+	}
+	if got := len(file.Block("Role").Values()); got != 2 {
+		t.Errorf("expected 2 enum values, got %d", got)
+	}
+}
+
+func TestEdits(t *testing.T) {
+	file := cst.Parse(editSchema)
+
+	if err := file.RenameBlock("User", "Account"); err != nil {
+		t.Fatal(err)
+	}
+	account := file.Block("Account")
+	if _, err := account.AddField("name", "String?"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := account.AddField("role", "Role", `@default(USER)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := account.Field("email").AddAttribute(`@db.VarChar(255)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Block("Post").AddBlockAttribute("@@index([authorId])"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Block("Role").AddValue("GUEST"); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Block("Post").Field("id").SetType("String"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `// Main database
+datasource db {
+  provider = "postgresql" // overridden in CI
+  url      = env("DATABASE_URL")
+}
+
+/// A registered user
+model Account {
+  id    Int     @id @default(autoincrement())
+  email String  @unique @db.VarChar(255) // login name
+  posts Post[]
+  name  String?
+  role  Role    @default(USER)
+
+  // keep in sync with the auth service
+  @@map("users")
+}
+
+
+model Post {
+  id       String  @id
+  authorId Int
+  author   Account @relation(fields: [authorId], references: [id])
+
+  @@index([authorId])
+}
+
+enum Role {
+  USER
+  ADMIN // full access
+  GUEST
+}
+`
+	if got := file.String(); got != want {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+	if _, err := schema.ParseSchemaString("schema.prisma", file.String()); err != nil {
+		t.Errorf("edited schema does not parse: %v", err)
+	}
+
+	if err := file.RenameBlock("Post", "Role"); err == nil {
+		t.Error("expected error when renaming to an existing block")
+	}
+	if _, err := account.AddField("email", "String"); err == nil {
+		t.Error("expected error for duplicate field")
+	}
+	if _, err := account.Field("id").AddAttribute("unique"); err == nil {
+		t.Error("expected error for invalid attribute")
+	}
+}
+
+func TestMoveAndRemoveBlocks(t *testing.T) {
+	file := cst.Parse(editSchema)
+
+	if err := file.MoveBlock("Role", "User"); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.MoveBlock("db", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.RemoveBlock("Post"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.AddBlock("model Tag {\n  id Int @id\n}"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `enum Role {
+  USER
+  ADMIN // full access
+}
+
+/// A registered user
+model User {
+  id    Int     @id @default(autoincrement())
+  email String  @unique // login name
+  posts Post[]
+
+  // keep in sync with the auth service
+  @@map("users")
+}
+
+
+// Main database
+datasource db {
+  provider = "postgresql" // overridden in CI
+  url      = env("DATABASE_URL")
+}
+
+model Tag {
+  id Int @id
+}
+`
+	if got := file.String(); got != want {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+
+	if err := file.MoveBlock("Missing", ""); err == nil {
+		t.Error("expected error for unknown block")
+	}
+}
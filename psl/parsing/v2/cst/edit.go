@@ -0,0 +1,506 @@
+package cst
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// RenameBlock renames the block oldName and every field type that refers
+// to it.
+func (f *File) RenameBlock(oldName, newName string) error {
+	block := f.Block(oldName)
+	if block == nil {
+		return fmt.Errorf("block %q not found", oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+	if f.Block(newName) != nil {
+		return fmt.Errorf("block %q already exists", newName)
+	}
+
+	block.Rename(newName)
+	for _, other := range f.Blocks() {
+		for _, field := range other.Fields() {
+			if typ := field.Type(); typ != nil && typ.Name() == oldName {
+				typ.name.Text = newName
+			}
+		}
+	}
+	return nil
+}
+
+// AddBlock parses src as a single block and appends it to the file,
+// separated from the previous block by a blank line.
+func (f *File) AddBlock(src string) (*Block, error) {
+	blocks := Parse(src).Blocks()
+	if len(blocks) != 1 {
+		return nil, fmt.Errorf("expected one block, found %d", len(blocks))
+	}
+	block := blocks[0]
+	if f.Block(block.Name()) != nil {
+		return nil, fmt.Errorf("block %q already exists", block.Name())
+	}
+	f.insertBlock(block, len(f.elements), nil)
+	return block, nil
+}
+
+// RemoveBlock removes the block with the given name together with its
+// leading comments and the blank lines separating it from the next block.
+func (f *File) RemoveBlock(name string) error {
+	block := f.Block(name)
+	if block == nil {
+		return fmt.Errorf("block %q not found", name)
+	}
+	f.removeBlock(block)
+	return nil
+}
+
+// MoveBlock moves the block name in front of the block before. An empty
+// before moves the block to the end of the file. Leading comments move
+// with the block.
+func (f *File) MoveBlock(name, before string) error {
+	block := f.Block(name)
+	if block == nil {
+		return fmt.Errorf("block %q not found", name)
+	}
+	if name == before {
+		return nil
+	}
+	var target *Block
+	if before != "" {
+		if target = f.Block(before); target == nil {
+			return fmt.Errorf("block %q not found", before)
+		}
+	}
+
+	separator := f.removeBlock(block)
+	index := len(f.elements)
+	if target != nil {
+		index = f.indexOf(target)
+	}
+	f.insertBlock(block, index, separator)
+	return nil
+}
+
+// removeBlock removes block from the file and returns the blank lines that
+// separated it from its neighbours.
+func (f *File) removeBlock(block *Block) []*Token {
+	index := f.indexOf(block)
+
+	// Prefer the blank lines after the block; for the last block, take the
+	// ones before it instead.
+	start, end := index, index+1
+	for end < len(f.elements) && isBlankToken(f.elements[end]) {
+		end++
+	}
+	if end == len(f.elements) {
+		for start > 0 && isBlankToken(f.elements[start-1]) {
+			start--
+		}
+	}
+
+	var separator []*Token
+	for _, element := range f.elements[start:end] {
+		if token, ok := element.(*Token); ok {
+			separator = append(separator, token)
+		}
+	}
+	f.elements = append(f.elements[:start:start], f.elements[end:]...)
+	return separator
+}
+
+// insertBlock inserts block at index, followed by separator when another
+// block follows or preceded by it at the end of the file.
+func (f *File) insertBlock(block *Block, index int, separator []*Token) {
+	newline := f.newline()
+	if len(separator) == 0 || !hasNewline(separator) {
+		separator = []*Token{{Kind: TokenNewline, Text: newline}}
+	}
+	ensureNewline(&block.elements, newline)
+
+	var inserted []Element
+	if index < len(f.elements) {
+		inserted = append(inserted, block)
+		for _, token := range separator {
+			inserted = append(inserted, token)
+		}
+	} else {
+		if len(f.elements) > 0 {
+			ensureNewline(&f.elements, newline)
+			for _, token := range separator {
+				inserted = append(inserted, token)
+			}
+		}
+		inserted = append(inserted, block)
+	}
+
+	elements := append([]Element(nil), f.elements[:index]...)
+	elements = append(elements, inserted...)
+	f.elements = append(elements, f.elements[index:]...)
+}
+
+func (f *File) indexOf(block *Block) int {
+	for i, element := range f.elements {
+		if element == block {
+			return i
+		}
+	}
+	return -1
+}
+
+// newline returns the line break used by the file.
+func (f *File) newline() string {
+	if token := findToken(f.elements, func(t *Token) bool { return t.Kind == TokenNewline }); token != nil {
+		return token.Text
+	}
+	return "\n"
+}
+
+// AddField appends a field to a model, view or composite type after its
+// last field. The field copies the indentation of existing fields and, when
+// they are aligned in columns, their alignment.
+func (b *Block) AddField(name, typ string, attributes ...string) (*Member, error) {
+	if b.Keyword() == "enum" || b.Keyword() == "datasource" || b.Keyword() == "generator" {
+		return nil, fmt.Errorf("cannot add a field to %s %s", b.Keyword(), b.Name())
+	}
+	if b.Field(name) != nil {
+		return nil, fmt.Errorf("field %q already exists in %s", name, b.Name())
+	}
+
+	fields := b.Fields()
+	indent := b.indent()
+	line := indent + name
+	if column, ok := alignedColumn(fields, typeColumn); ok {
+		line = pad(line, column)
+	} else {
+		line += " "
+	}
+	line += typ
+	if len(attributes) > 0 {
+		if column, ok := alignedColumn(fields, attributeColumn); ok {
+			line = pad(line, column)
+		} else {
+			line += " "
+		}
+		line += strings.Join(attributes, " ")
+	}
+
+	member := parseMember(b.Keyword(), Tokenize(line+b.newline()))
+	if member.kind != MemberField || member.Name() != name {
+		return nil, fmt.Errorf("invalid field %q", strings.TrimSpace(line))
+	}
+
+	index := b.headerEnd()
+	if len(fields) > 0 {
+		index = b.indexOf(fields[len(fields)-1]) + 1
+	} else if attrs := b.BlockAttributes(); len(attrs) > 0 {
+		index = b.indexOf(attrs[0])
+	}
+	b.insert(index, member)
+	return member, nil
+}
+
+// AddValue appends a value to an enum after its last value.
+func (b *Block) AddValue(name string) (*Member, error) {
+	if b.Keyword() != "enum" {
+		return nil, fmt.Errorf("cannot add a value to %s %s", b.Keyword(), b.Name())
+	}
+	if b.memberNamed(MemberEnumValue, name) != nil {
+		return nil, fmt.Errorf("value %q already exists in %s", name, b.Name())
+	}
+
+	member := parseMember(b.Keyword(), Tokenize(b.indent()+name+b.newline()))
+	if member.kind != MemberEnumValue || member.Name() != name {
+		return nil, fmt.Errorf("invalid enum value %q", name)
+	}
+
+	index := b.headerEnd()
+	if values := b.Values(); len(values) > 0 {
+		index = b.indexOf(values[len(values)-1]) + 1
+	}
+	b.insert(index, member)
+	return member, nil
+}
+
+// AddBlockAttribute appends a block attribute such as @@index([email]).
+// The first block attribute of a block is separated from the fields by a
+// blank line.
+func (b *Block) AddBlockAttribute(attribute string) (*Member, error) {
+	member := parseMember(b.Keyword(), Tokenize(b.indent()+attribute+b.newline()))
+	if member.kind != MemberBlockAttribute || len(member.Attributes()) != 1 {
+		return nil, fmt.Errorf("invalid block attribute %q", attribute)
+	}
+
+	if attrs := b.BlockAttributes(); len(attrs) > 0 {
+		b.insert(b.indexOf(attrs[len(attrs)-1])+1, member)
+		return member, nil
+	}
+
+	index := b.headerEnd()
+	for i, element := range b.elements {
+		if m, ok := element.(*Member); ok && m.kind != MemberBlank {
+			index = i + 1
+		}
+	}
+	if index > b.headerEnd() {
+		blank := &Member{
+			kind:     MemberBlank,
+			elements: []Element{&Token{Kind: TokenNewline, Text: b.newline()}},
+		}
+		b.insert(index, blank)
+		index = b.indexOf(blank) + 1
+	}
+	b.insert(index, member)
+	return member, nil
+}
+
+// RemoveMember removes a line from the block.
+func (b *Block) RemoveMember(member *Member) error {
+	index := b.indexOf(member)
+	if index < 0 {
+		return fmt.Errorf("member %q not found in %s", member.Name(), b.Name())
+	}
+	b.elements = append(b.elements[:index:index], b.elements[index+1:]...)
+	return nil
+}
+
+// insert inserts member at index, breaking the preceding line if needed.
+func (b *Block) insert(index int, member *Member) {
+	elements := append([]Element(nil), b.elements[:index]...)
+	if last := lastToken(elements); last != nil && last.Kind != TokenNewline {
+		elements = append(elements, &Token{Kind: TokenNewline, Text: b.newline()})
+	}
+	elements = append(elements, member)
+	b.elements = append(elements, b.elements[index:]...)
+}
+
+func (b *Block) indexOf(element Element) int {
+	for i, e := range b.elements {
+		if e == element {
+			return i
+		}
+	}
+	return -1
+}
+
+// headerEnd returns the index of the first element after the header line.
+func (b *Block) headerEnd() int {
+	i := b.indexOf(b.open) + 1
+	for i < len(b.elements) {
+		token, ok := b.elements[i].(*Token)
+		if !ok || !token.IsTrivia() {
+			break
+		}
+		i++
+		if token.Kind == TokenNewline {
+			break
+		}
+	}
+	return i
+}
+
+// indent returns the indentation of the first indented member, or two
+// spaces.
+func (b *Block) indent() string {
+	for _, member := range b.Members() {
+		if member.kind == MemberBlank || len(member.elements) == 0 {
+			continue
+		}
+		if token, ok := member.elements[0].(*Token); ok && token.Kind == TokenWhitespace {
+			return token.Text
+		}
+	}
+	return "  "
+}
+
+// newline returns the line break used by the block.
+func (b *Block) newline() string {
+	if token := findToken(b.elements, func(t *Token) bool { return t.Kind == TokenNewline }); token != nil {
+		return token.Text
+	}
+	return "\n"
+}
+
+// AddAttribute appends an attribute such as @unique to a field or enum
+// value, keeping any trailing comment at the end of the line.
+func (m *Member) AddAttribute(attribute string) (*Attribute, error) {
+	if m.kind != MemberField && m.kind != MemberEnumValue {
+		return nil, fmt.Errorf("cannot add an attribute to %q", strings.TrimSpace(m.String()))
+	}
+	tokens := Tokenize(attribute)
+	i := 0
+	if len(tokens) == 0 || !tokens[0].is("@") {
+		return nil, fmt.Errorf("invalid attribute %q", attribute)
+	}
+	attr := parseAttribute(tokens, &i)
+	if i != len(tokens) {
+		return nil, fmt.Errorf("invalid attribute %q", attribute)
+	}
+
+	// Insert after the last non-trivia element of the line
+	index := len(m.elements)
+	for index > 0 {
+		if token, ok := m.elements[index-1].(*Token); !ok || !token.IsTrivia() {
+			break
+		}
+		index--
+	}
+	elements := append([]Element(nil), m.elements[:index]...)
+	elements = append(elements, &Token{Kind: TokenWhitespace, Text: " "}, attr)
+	m.elements = append(elements, m.elements[index:]...)
+	return attr, nil
+}
+
+// RemoveAttribute removes the first attribute with the given name and the
+// whitespace before it. It reports whether an attribute was removed.
+func (m *Member) RemoveAttribute(name string) bool {
+	for i, element := range m.elements {
+		if attr, ok := element.(*Attribute); ok && attr.Name() == name {
+			start := i
+			if i > 0 {
+				if token, ok := m.elements[i-1].(*Token); ok && token.Kind == TokenWhitespace {
+					start--
+				}
+			}
+			m.elements = append(m.elements[:start:start], m.elements[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SetType replaces the type of a field, e.g. with "String?".
+func (m *Member) SetType(typ string) error {
+	if m.typ == nil {
+		return fmt.Errorf("%q has no type", strings.TrimSpace(m.String()))
+	}
+	tokens := Tokenize(typ)
+	i := 0
+	if len(tokens) == 0 || tokens[0].Kind != TokenIdent {
+		return fmt.Errorf("invalid type %q", typ)
+	}
+	parsed := parseFieldType(tokens, &i)
+	if i != len(tokens) {
+		return fmt.Errorf("invalid type %q", typ)
+	}
+	*m.typ = *parsed
+	return nil
+}
+
+// typeColumn returns the column where the type of a field starts.
+func typeColumn(m *Member) (int, bool) {
+	return columnOf(m, func(e Element) bool { return e == m.typ })
+}
+
+// attributeColumn returns the column where the first attribute of a field
+// starts.
+func attributeColumn(m *Member) (int, bool) {
+	return columnOf(m, func(e Element) bool {
+		_, ok := e.(*Attribute)
+		return ok
+	})
+}
+
+func columnOf(m *Member, match func(Element) bool) (int, bool) {
+	var b strings.Builder
+	for _, element := range m.elements {
+		if match(element) {
+			return utf8.RuneCountInString(b.String()), true
+		}
+		element.writeTo(&b)
+	}
+	return 0, false
+}
+
+// alignedColumn returns the column shared by all fields that have one.
+func alignedColumn(fields []*Member, column func(*Member) (int, bool)) (int, bool) {
+	result, found := 0, false
+	for _, field := range fields {
+		col, ok := column(field)
+		if !ok {
+			continue
+		}
+		if found && col != result {
+			return 0, false
+		}
+		result, found = col, true
+	}
+	return result, found
+}
+
+// pad pads s with spaces up to column, keeping at least one space.
+func pad(s string, column int) string {
+	n := column - utf8.RuneCountInString(s)
+	if n < 1 {
+		n = 1
+	}
+	return s + strings.Repeat(" ", n)
+}
+
+func isBlankToken(element Element) bool {
+	token, ok := element.(*Token)
+	return ok && (token.Kind == TokenWhitespace || token.Kind == TokenNewline)
+}
+
+func hasNewline(tokens []*Token) bool {
+	for _, token := range tokens {
+		if token.Kind == TokenNewline {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureNewline appends a line break to elements unless they already end
+// with one.
+func ensureNewline(elements *[]Element, newline string) {
+	if last := lastToken(*elements); last != nil && last.Kind != TokenNewline {
+		*elements = append(*elements, &Token{Kind: TokenNewline, Text: newline})
+	}
+}
+
+// lastToken returns the last token of elements, or nil.
+func lastToken(elements []Element) *Token {
+	for i := len(elements) - 1; i >= 0; i-- {
+		switch e := elements[i].(type) {
+		case *Token:
+			return e
+		case *Block:
+			if token := lastToken(e.elements); token != nil {
+				return token
+			}
+		case *Member:
+			if token := lastToken(e.elements); token != nil {
+				return token
+			}
+		case *FieldType:
+			return e.tokens[len(e.tokens)-1]
+		case *Attribute:
+			return e.tokens[len(e.tokens)-1]
+		}
+	}
+	return nil
+}
+
+// findToken returns the first token of elements matching match, or nil.
+func findToken(elements []Element, match func(*Token) bool) *Token {
+	for _, element := range elements {
+		var found *Token
+		switch e := element.(type) {
+		case *Token:
+			if match(e) {
+				found = e
+			}
+		case *Block:
+			found = findToken(e.elements, match)
+		case *Member:
+			found = findToken(e.elements, match)
+		}
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
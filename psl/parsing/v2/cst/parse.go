@@ -0,0 +1,311 @@
+package cst
+
+// blockKeywords are the keywords that start a top-level block.
+var blockKeywords = map[string]bool{
+	"model":      true,
+	"view":       true,
+	"type":       true,
+	"enum":       true,
+	"datasource": true,
+	"generator":  true,
+	"extend":     true,
+}
+
+// Parse builds a lossless tree from src. It never fails: text that is not
+// part of a block is kept as top-level tokens, so File.String always
+// returns src.
+func Parse(src string) *File {
+	tokens := Tokenize(src)
+	file := &File{}
+
+	var pending []*Token
+	for i := 0; i < len(tokens); {
+		if !isBlockStart(tokens, i) {
+			pending = append(pending, tokens[i])
+			i++
+			continue
+		}
+
+		cut := leadingCommentsStart(pending)
+		for _, token := range pending[:cut] {
+			file.elements = append(file.elements, token)
+		}
+		block, next := parseBlock(tokens, i, pending[cut:])
+		file.elements = append(file.elements, block)
+		pending = nil
+		i = next
+	}
+	for _, token := range pending {
+		file.elements = append(file.elements, token)
+	}
+	return file
+}
+
+// isBlockStart reports whether tokens[i] starts a block: a keyword followed
+// by a name and an opening brace. Extensions are written "extend type Name {".
+func isBlockStart(tokens []*Token, i int) bool {
+	if tokens[i].Kind != TokenIdent || !blockKeywords[tokens[i].Text] {
+		return false
+	}
+	extend := tokens[i].Text == "extend"
+	i = skipWhitespace(tokens, i+1)
+	if extend {
+		if i == len(tokens) || tokens[i].Kind != TokenIdent || tokens[i].Text != "type" {
+			return false
+		}
+		i = skipWhitespace(tokens, i+1)
+	}
+	if i == len(tokens) || tokens[i].Kind != TokenIdent {
+		return false
+	}
+	i = skipWhitespace(tokens, i+1)
+	return i < len(tokens) && tokens[i].is("{")
+}
+
+func skipWhitespace(tokens []*Token, i int) int {
+	for i < len(tokens) && tokens[i].Kind == TokenWhitespace {
+		i++
+	}
+	return i
+}
+
+// leadingCommentsStart returns the index in pending where the comment lines
+// directly above a block start. Indentation before the keyword is included;
+// a blank line ends the run of comments.
+func leadingCommentsStart(pending []*Token) int {
+	cut := len(pending)
+	for cut > 0 && pending[cut-1].Kind == TokenWhitespace {
+		cut--
+	}
+	for cut > 0 && pending[cut-1].Kind == TokenNewline {
+		i := cut - 1
+		for i > 0 && pending[i-1].Kind == TokenWhitespace {
+			i--
+		}
+		if i == 0 || !pending[i-1].isComment() {
+			break
+		}
+		i--
+		for i > 0 && pending[i-1].Kind == TokenWhitespace {
+			i--
+		}
+		if i > 0 && pending[i-1].Kind != TokenNewline {
+			break
+		}
+		cut = i
+	}
+	return cut
+}
+
+// parseBlock parses the block whose keyword is tokens[i]. leading holds the
+// comment lines above it. It returns the block and the index of the first
+// token after it.
+func parseBlock(tokens []*Token, i int, leading []*Token) (*Block, int) {
+	block := &Block{}
+	for _, token := range leading {
+		block.elements = append(block.elements, token)
+	}
+	take := func() *Token {
+		token := tokens[i]
+		block.elements = append(block.elements, token)
+		i++
+		return token
+	}
+
+	// Header: keyword, name and opening brace, followed by the rest of the line
+	block.keyword = take()
+	for tokens[i].Kind == TokenWhitespace {
+		take()
+	}
+	if block.Keyword() == "extend" {
+		take()
+		for tokens[i].Kind == TokenWhitespace {
+			take()
+		}
+	}
+	block.name = take()
+	for tokens[i].Kind == TokenWhitespace {
+		take()
+	}
+	block.open = take()
+	for i < len(tokens) && tokens[i].isInlineTrivia() {
+		take()
+	}
+	if i < len(tokens) && tokens[i].Kind == TokenNewline {
+		take()
+	}
+
+	// Body: one member per logical line, until the matching closing brace
+	var line []*Token
+	parens, braces := 0, 0
+	for i < len(tokens) {
+		token := tokens[i]
+		if token.is("}") && braces == 0 {
+			break
+		}
+		switch {
+		case token.is("("), token.is("["):
+			parens++
+		case token.is(")"), token.is("]"):
+			if parens > 0 {
+				parens--
+			}
+		case token.is("{"):
+			braces++
+		case token.is("}"):
+			braces--
+		}
+		line = append(line, token)
+		i++
+		if token.Kind == TokenNewline && parens == 0 && braces == 0 {
+			block.elements = append(block.elements, parseMember(block.Keyword(), line))
+			line = nil
+		}
+	}
+
+	// Indentation before the closing brace belongs to the block itself
+	if onlyWhitespace(line) {
+		for _, token := range line {
+			block.elements = append(block.elements, token)
+		}
+	} else {
+		block.elements = append(block.elements, parseMember(block.Keyword(), line))
+	}
+	if i == len(tokens) {
+		// Unterminated block
+		return block, i
+	}
+
+	block.close = take()
+	for i < len(tokens) && tokens[i].isInlineTrivia() {
+		take()
+	}
+	if i < len(tokens) && tokens[i].Kind == TokenNewline {
+		take()
+	}
+	return block, i
+}
+
+func onlyWhitespace(tokens []*Token) bool {
+	for _, token := range tokens {
+		if token.Kind != TokenWhitespace {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMember classifies a logical line of a block with the given keyword.
+func parseMember(keyword string, tokens []*Token) *Member {
+	member := &Member{kind: MemberUnknown}
+	i := 0
+	take := func() *Token {
+		token := tokens[i]
+		member.elements = append(member.elements, token)
+		i++
+		return token
+	}
+	takeWhitespace := func() {
+		for i < len(tokens) && tokens[i].Kind == TokenWhitespace {
+			take()
+		}
+	}
+
+	takeWhitespace()
+	switch {
+	case i == len(tokens) || tokens[i].Kind == TokenNewline:
+		member.kind = MemberBlank
+	case tokens[i].isComment():
+		member.kind = MemberComment
+		for i < len(tokens) && tokens[i].IsTrivia() {
+			take()
+		}
+		if i < len(tokens) {
+			member.kind = MemberUnknown
+		}
+	case tokens[i].is("@@"):
+		member.kind = MemberBlockAttribute
+		member.elements = append(member.elements, parseAttribute(tokens, &i))
+	case tokens[i].Kind == TokenIdent:
+		member.name = take()
+		switch keyword {
+		case "datasource", "generator":
+			member.kind = MemberProperty
+		case "enum":
+			member.kind = MemberEnumValue
+		default:
+			member.kind = MemberField
+			takeWhitespace()
+			if i < len(tokens) && tokens[i].Kind == TokenIdent {
+				member.typ = parseFieldType(tokens, &i)
+				member.elements = append(member.elements, member.typ)
+			}
+		}
+	}
+
+	// Attributes and trailing trivia
+	for i < len(tokens) {
+		if tokens[i].is("@") || (tokens[i].is("@@") && member.kind != MemberBlockAttribute) {
+			member.elements = append(member.elements, parseAttribute(tokens, &i))
+			continue
+		}
+		take()
+	}
+	return member
+}
+
+// parseFieldType parses a type such as String?, Post[] or
+// Unsupported("circle") starting at tokens[*i].
+func parseFieldType(tokens []*Token, i *int) *FieldType {
+	typ := &FieldType{name: tokens[*i]}
+	typ.tokens = append(typ.tokens, tokens[*i])
+	*i++
+	if *i < len(tokens) && tokens[*i].is("(") {
+		end := matchingParen(tokens, *i)
+		typ.tokens = append(typ.tokens, tokens[*i:end]...)
+		*i = end
+	}
+	if *i+1 < len(tokens) && tokens[*i].is("[") && tokens[*i+1].is("]") {
+		typ.tokens = append(typ.tokens, tokens[*i], tokens[*i+1])
+		*i += 2
+	}
+	for *i < len(tokens) && (tokens[*i].is("?") || tokens[*i].is("!")) {
+		typ.tokens = append(typ.tokens, tokens[*i])
+		*i++
+	}
+	return typ
+}
+
+// parseAttribute parses an attribute starting at the @ or @@ at tokens[*i].
+func parseAttribute(tokens []*Token, i *int) *Attribute {
+	start := *i
+	*i++
+	if *i < len(tokens) && tokens[*i].Kind == TokenIdent {
+		*i++
+		for *i+1 < len(tokens) && tokens[*i].is(".") && tokens[*i+1].Kind == TokenIdent {
+			*i += 2
+		}
+	}
+	if *i < len(tokens) && tokens[*i].is("(") {
+		*i = matchingParen(tokens, *i)
+	}
+	return &Attribute{tokens: tokens[start:*i:*i]}
+}
+
+// matchingParen returns the index after the parenthesis closing the one at
+// tokens[open], or len(tokens) when it is unbalanced.
+func matchingParen(tokens []*Token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("("):
+			depth++
+		case tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(tokens)
+}
@@ -0,0 +1,172 @@
+// Package cst provides a lossless concrete syntax tree for Prisma schemas.
+//
+// Unlike the AST, the tree keeps every byte of the source, including
+// whitespace, blank lines and comments, so printing an unedited tree
+// reproduces the input exactly. Edits only touch the tokens they change,
+// which lets tools modify a schema without reformatting unrelated sections.
+package cst
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind identifies the lexical class of a token.
+type TokenKind int
+
+const (
+	TokenWhitespace   TokenKind = iota // spaces and tabs
+	TokenNewline                       // "\n", "\r\n" or "\r"
+	TokenComment                       // "// ..."
+	TokenDocComment                    // "/// ..."
+	TokenBlockComment                  // "/* ... */"
+	TokenIdent
+	TokenNumber
+	TokenString
+	TokenPunct   // "{", "}", "(", ")", "[", "]", ":", ",", ".", "=", "?", "!", "@" and "@@"
+	TokenUnknown // any character that is not part of the schema language
+)
+
+// Token is a single lexical token. Concatenating the text of all tokens of
+// a file yields the original source.
+type Token struct {
+	Kind TokenKind
+	Text string
+}
+
+// String returns the token text.
+func (t *Token) String() string {
+	return t.Text
+}
+
+// IsTrivia reports whether the token is whitespace, a newline or a comment.
+func (t *Token) IsTrivia() bool {
+	return t.Kind == TokenNewline || t.isInlineTrivia()
+}
+
+// isInlineTrivia reports whether the token is trivia that does not end a line.
+func (t *Token) isInlineTrivia() bool {
+	return t.Kind == TokenWhitespace || t.isComment()
+}
+
+func (t *Token) isComment() bool {
+	return t.Kind == TokenComment || t.Kind == TokenDocComment || t.Kind == TokenBlockComment
+}
+
+// is reports whether the token is the given punctuation.
+func (t *Token) is(punct string) bool {
+	return t.Kind == TokenPunct && t.Text == punct
+}
+
+const punctuation = "{}()[]:,.=?!@"
+
+// Tokenize splits src into tokens. It never fails: characters that are not
+// part of the schema language become TokenUnknown tokens.
+func Tokenize(src string) []*Token {
+	var tokens []*Token
+	for i := 0; i < len(src); {
+		kind, n := scanToken(src[i:])
+		tokens = append(tokens, &Token{Kind: kind, Text: src[i : i+n]})
+		i += n
+	}
+	return tokens
+}
+
+// scanToken returns the kind and byte length of the token at the start of s.
+func scanToken(s string) (TokenKind, int) {
+	c := s[0]
+	switch {
+	case c == ' ' || c == '\t':
+		n := 1
+		for n < len(s) && (s[n] == ' ' || s[n] == '\t') {
+			n++
+		}
+		return TokenWhitespace, n
+	case c == '\r':
+		if len(s) > 1 && s[1] == '\n' {
+			return TokenNewline, 2
+		}
+		return TokenNewline, 1
+	case c == '\n':
+		return TokenNewline, 1
+	case strings.HasPrefix(s, "//"):
+		n := strings.IndexAny(s, "\r\n")
+		if n < 0 {
+			n = len(s)
+		}
+		if strings.HasPrefix(s, "///") {
+			return TokenDocComment, n
+		}
+		return TokenComment, n
+	case strings.HasPrefix(s, "/*"):
+		end := strings.Index(s[2:], "*/")
+		if end < 0 {
+			return TokenBlockComment, len(s)
+		}
+		return TokenBlockComment, end + 4
+	case c == '"':
+		return TokenString, scanString(s)
+	case isDigit(c) || (c == '-' && len(s) > 1 && isDigit(s[1])):
+		return TokenNumber, scanNumber(s)
+	case strings.HasPrefix(s, "@@"):
+		return TokenPunct, 2
+	case strings.IndexByte(punctuation, c) >= 0:
+		return TokenPunct, 1
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+	if !isIdentStart(r) {
+		return TokenUnknown, size
+	}
+	n := size
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !isIdentStart(r) && r != '-' {
+			break
+		}
+		n += size
+	}
+	return TokenIdent, n
+}
+
+// scanString returns the length of the string literal at the start of s.
+// Unterminated strings end before the next line break.
+func scanString(s string) int {
+	for n := 1; n < len(s); n++ {
+		switch s[n] {
+		case '\\':
+			if n+1 < len(s) && s[n+1] != '\n' && s[n+1] != '\r' {
+				n++
+			}
+		case '"':
+			return n + 1
+		case '\n', '\r':
+			return n
+		}
+	}
+	return len(s)
+}
+
+// scanNumber returns the length of the number at the start of s.
+func scanNumber(s string) int {
+	n := 1
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	if n+1 < len(s) && s[n] == '.' && isDigit(s[n+1]) {
+		n += 2
+		for n < len(s) && isDigit(s[n]) {
+			n++
+		}
+	}
+	return n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,364 @@
+package cst
+
+import "strings"
+
+// Element is a token or node of the tree.
+type Element interface {
+	// String returns the source text of the element.
+	String() string
+	writeTo(b *strings.Builder)
+}
+
+func (t *Token) writeTo(b *strings.Builder) {
+	b.WriteString(t.Text)
+}
+
+// File is the root of the tree. Its elements are blocks and the trivia
+// between them.
+type File struct {
+	elements []Element
+}
+
+// String returns the source text of the file.
+func (f *File) String() string {
+	return render(f.elements)
+}
+
+// Elements returns the blocks and top-level tokens of the file in source
+// order.
+func (f *File) Elements() []Element {
+	return f.elements
+}
+
+// Blocks returns the top-level blocks in source order.
+func (f *File) Blocks() []*Block {
+	var blocks []*Block
+	for _, element := range f.elements {
+		if block, ok := element.(*Block); ok {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// Block returns the first block with the given name, or nil.
+func (f *File) Block(name string) *Block {
+	for _, block := range f.Blocks() {
+		if block.Name() == name {
+			return block
+		}
+	}
+	return nil
+}
+
+// Block is a top-level declaration such as a model, enum or datasource.
+// Comment lines directly above the declaration belong to the block.
+type Block struct {
+	elements []Element
+	keyword  *Token
+	name     *Token
+	open     *Token
+	close    *Token
+}
+
+func (b *Block) writeTo(builder *strings.Builder) {
+	writeElements(builder, b.elements)
+}
+
+// String returns the source text of the block, including its leading comments.
+func (b *Block) String() string {
+	return render(b.elements)
+}
+
+// Elements returns the tokens and members of the block in source order.
+func (b *Block) Elements() []Element {
+	return b.elements
+}
+
+// Keyword returns the block keyword, e.g. "model", "enum" or "extend".
+func (b *Block) Keyword() string {
+	return b.keyword.Text
+}
+
+// Name returns the block name.
+func (b *Block) Name() string {
+	return b.name.Text
+}
+
+// Rename changes the block name without updating references to it.
+// Use File.RenameBlock to rename a block and its references.
+func (b *Block) Rename(name string) {
+	b.name.Text = name
+}
+
+// Members returns the lines of the block body, including comment and
+// blank lines.
+func (b *Block) Members() []*Member {
+	var members []*Member
+	for _, element := range b.elements {
+		if member, ok := element.(*Member); ok {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// Fields returns the fields of a model, view or composite type.
+func (b *Block) Fields() []*Member {
+	return b.membersOfKind(MemberField)
+}
+
+// Field returns the field with the given name, or nil.
+func (b *Block) Field(name string) *Member {
+	return b.memberNamed(MemberField, name)
+}
+
+// Values returns the values of an enum.
+func (b *Block) Values() []*Member {
+	return b.membersOfKind(MemberEnumValue)
+}
+
+// Properties returns the key = value lines of a datasource or generator.
+func (b *Block) Properties() []*Member {
+	return b.membersOfKind(MemberProperty)
+}
+
+// Property returns the property with the given key, or nil.
+func (b *Block) Property(name string) *Member {
+	return b.memberNamed(MemberProperty, name)
+}
+
+// BlockAttributes returns the @@ attribute lines of the block.
+func (b *Block) BlockAttributes() []*Member {
+	return b.membersOfKind(MemberBlockAttribute)
+}
+
+func (b *Block) membersOfKind(kind MemberKind) []*Member {
+	var members []*Member
+	for _, member := range b.Members() {
+		if member.kind == kind {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+func (b *Block) memberNamed(kind MemberKind, name string) *Member {
+	for _, member := range b.membersOfKind(kind) {
+		if member.Name() == name {
+			return member
+		}
+	}
+	return nil
+}
+
+// MemberKind identifies the kind of a block line.
+type MemberKind int
+
+const (
+	MemberField          MemberKind = iota // name Type @attr
+	MemberEnumValue                        // VALUE @attr
+	MemberProperty                         // key = value
+	MemberBlockAttribute                   // @@attr(...)
+	MemberComment                          // a line holding only comments
+	MemberBlank                            // an empty line
+	MemberUnknown                          // a line that could not be classified
+)
+
+// Member is a single logical line of a block body. Attribute arguments may
+// span several physical lines.
+type Member struct {
+	elements []Element
+	kind     MemberKind
+	name     *Token
+	typ      *FieldType
+}
+
+func (m *Member) writeTo(b *strings.Builder) {
+	writeElements(b, m.elements)
+}
+
+// String returns the source text of the line, including indentation and
+// the line break.
+func (m *Member) String() string {
+	return render(m.elements)
+}
+
+// Kind returns the member kind.
+func (m *Member) Kind() MemberKind {
+	return m.kind
+}
+
+// Name returns the field, enum value or property name.
+func (m *Member) Name() string {
+	if m.name == nil {
+		return ""
+	}
+	return m.name.Text
+}
+
+// Rename changes the field, enum value or property name.
+func (m *Member) Rename(name string) {
+	if m.name != nil {
+		m.name.Text = name
+	}
+}
+
+// Type returns the field type as written, e.g. "String?" or "Post[]".
+func (m *Member) Type() *FieldType {
+	return m.typ
+}
+
+// Value returns the text after "=" of a property.
+func (m *Member) Value() string {
+	if m.kind != MemberProperty {
+		return ""
+	}
+	var b strings.Builder
+	seen := false
+	for _, element := range m.elements {
+		token, ok := element.(*Token)
+		if !ok {
+			continue
+		}
+		if seen && token.Kind != TokenComment && token.Kind != TokenNewline {
+			b.WriteString(token.Text)
+		}
+		if token.is("=") {
+			seen = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Attributes returns the attributes of the line in source order.
+func (m *Member) Attributes() []*Attribute {
+	var attributes []*Attribute
+	for _, element := range m.elements {
+		if attribute, ok := element.(*Attribute); ok {
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes
+}
+
+// Attribute returns the first attribute with the given name, or nil.
+func (m *Member) Attribute(name string) *Attribute {
+	for _, attribute := range m.Attributes() {
+		if attribute.Name() == name {
+			return attribute
+		}
+	}
+	return nil
+}
+
+// FieldType is the type of a field, e.g. "String?", "Post[]" or
+// Unsupported("circle").
+type FieldType struct {
+	tokens []*Token
+	name   *Token
+}
+
+func (t *FieldType) writeTo(b *strings.Builder) {
+	for _, token := range t.tokens {
+		b.WriteString(token.Text)
+	}
+}
+
+// String returns the type as written.
+func (t *FieldType) String() string {
+	var b strings.Builder
+	t.writeTo(&b)
+	return b.String()
+}
+
+// Name returns the base type name without arity, e.g. "Post" for "Post[]".
+func (t *FieldType) Name() string {
+	return t.name.Text
+}
+
+// IsList reports whether the type is a list.
+func (t *FieldType) IsList() bool {
+	return t.hasPunct("[")
+}
+
+// IsOptional reports whether the type is optional.
+func (t *FieldType) IsOptional() bool {
+	return t.hasPunct("?")
+}
+
+func (t *FieldType) hasPunct(punct string) bool {
+	for _, token := range t.tokens {
+		if token.is(punct) {
+			return true
+		}
+	}
+	return false
+}
+
+// Attribute is a field attribute (@id) or block attribute (@@index).
+type Attribute struct {
+	tokens []*Token
+}
+
+func (a *Attribute) writeTo(b *strings.Builder) {
+	for _, token := range a.tokens {
+		b.WriteString(token.Text)
+	}
+}
+
+// String returns the attribute as written.
+func (a *Attribute) String() string {
+	var b strings.Builder
+	a.writeTo(&b)
+	return b.String()
+}
+
+// Name returns the attribute name without the @ prefix, e.g. "default" or
+// "db.VarChar".
+func (a *Attribute) Name() string {
+	var b strings.Builder
+	for _, token := range a.tokens[1:] {
+		if token.is("(") {
+			break
+		}
+		b.WriteString(token.Text)
+	}
+	return b.String()
+}
+
+// IsBlockAttribute reports whether the attribute has the @@ prefix.
+func (a *Attribute) IsBlockAttribute() bool {
+	return a.tokens[0].Text == "@@"
+}
+
+// Arguments returns the raw text between the parentheses, or "" when the
+// attribute has no argument list.
+func (a *Attribute) Arguments() string {
+	for i, token := range a.tokens {
+		if token.is("(") {
+			end := len(a.tokens)
+			if a.tokens[end-1].is(")") {
+				end--
+			}
+			var b strings.Builder
+			for _, token := range a.tokens[i+1 : end] {
+				b.WriteString(token.Text)
+			}
+			return b.String()
+		}
+	}
+	return ""
+}
+
+func render(elements []Element) string {
+	var b strings.Builder
+	writeElements(&b, elements)
+	return b.String()
+}
+
+func writeElements(b *strings.Builder, elements []Element) {
+	for _, element := range elements {
+		element.writeTo(b)
+	}
+}
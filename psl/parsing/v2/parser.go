@@ -65,11 +65,20 @@ var parser = participle.MustBuild[RawSchema](
 )
 
 // ParseSchema parses a Prisma schema from an io.Reader.
+//
+// Syntax errors do not stop parsing: the returned schema holds every
+// declaration that could be recovered and the error is an ErrorList with
+// one entry per broken line.
 func ParseSchema(filename string, r io.Reader) (*ast.SchemaAst, error) {
-	raw, err := parser.Parse(filename, r)
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
+	src := string(data)
+	raw, err := parser.ParseString(filename, src)
+	if err != nil {
+		return recoverSchema(filename, src, err)
+	}
 	return convertRawSchema(raw), nil
 }
 
@@ -97,5 +106,34 @@ func convertRawSchema(raw *RawSchema) *ast.SchemaAst {
 			schema.Tops = append(schema.Tops, top)
 		}
 	}
+	for _, top := range schema.Tops {
+		resolveTopArity(top)
+	}
 	return schema
 }
+
+// resolveTopArity resolves the field arities of a top-level declaration.
+func resolveTopArity(top ast.Top) {
+	switch t := top.(type) {
+	case *ast.Model:
+		resolveFieldArity(t.Fields)
+	case *ast.CompositeType:
+		resolveFieldArity(t.Fields)
+	case *ast.ExtendedType:
+		resolveFieldArity(t.Fields)
+	}
+}
+
+// resolveFieldArity derives the arity of fields from the parsed type modifiers.
+func resolveFieldArity(fields []*ast.Field) {
+	for _, field := range fields {
+		switch {
+		case field.ListSuffix != nil:
+			field.Arity = ast.FieldArityList
+		case field.OptionalMark != nil:
+			field.Arity = ast.FieldArityOptional
+		default:
+			field.Arity = ast.FieldArityRequired
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -230,3 +231,88 @@ model User {
 		t.Error("Expected model to have documentation")
 	}
 }
+
+func TestParseErrorRecovery(t *testing.T) {
+	input := `datasource db {
+  provider = "postgresql"
+  url      = env("DATABASE_URL")
+}
+
+model User {
+  id    Int    @id @default(autoincrement())
+  email String @unique(
+  name  String?
+  posts Post[]
+}
+
+oops
+
+model Post {
+  id       Int @id
+  title    String @default("x" "y")
+  authorId Int
+}
+
+enum Role {
+  USER
+  ADMIN
+}
+`
+	schema, err := ParseSchemaString("test.prisma", input)
+	if err == nil {
+		t.Fatal("Expected syntax errors")
+	}
+	errs, ok := err.(ErrorList)
+	if !ok {
+		t.Fatalf("Expected ErrorList, got %T", err)
+	}
+
+	var lines []int
+	for _, e := range errs {
+		lines = append(lines, e.Pos.Line)
+	}
+	if len(lines) != 3 || lines[0] != 8 || lines[1] != 13 || lines[2] != 17 {
+		t.Errorf("Expected errors on lines 8, 13 and 17, got %v: %v", lines, err)
+	}
+
+	if schema == nil {
+		t.Fatal("Expected a partial schema")
+	}
+	if len(schema.Sources()) != 1 || len(schema.Enums()) != 1 {
+		t.Errorf("Expected datasource and enum to be recovered")
+	}
+	models := schema.Models()
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 models, got %d", len(models))
+	}
+	if len(models[0].Fields) != 3 || len(models[1].Fields) != 2 {
+		t.Errorf("Expected 3 and 2 recovered fields, got %d and %d", len(models[0].Fields), len(models[1].Fields))
+	}
+	if field := models[1].Fields[1]; field.GetName() != "authorId" || field.Pos.Line != 18 || field.Pos.Offset != strings.Index(input, "authorId Int") {
+		t.Errorf("Unexpected position for recovered field %s: %v", field.GetName(), field.Pos)
+	}
+}
+
+func TestModelSoftDeleteField(t *testing.T) {
+	for comment, want := range map[string]string{
+		"":                                      "",
+		"/// Posts of the blog":                 "",
+		"/// Posts\n/// @softDelete(deletedAt)": "deletedAt",
+		"/// @softDelete( deletedAt )":          "deletedAt",
+		"/// @softDelete(deletedAt":             "error: @softDelete(deletedAt is not closed",
+		"/// @softDelete(deletedAt\n/// )":      "error: @softDelete(deletedAt is not closed",
+		"/// @softDelete()":                     "error: @softDelete needs a field, e.g. @softDelete(deletedAt)",
+	} {
+		schema, err := ParseSchemaString("test.prisma", comment+"\nmodel Post {\n  id Int @id\n}\n")
+		if err != nil {
+			t.Fatalf("%q: %v", comment, err)
+		}
+		field, err := schema.Models()[0].SoftDeleteField()
+		if err != nil {
+			field = "error: " + err.Error()
+		}
+		if field != want {
+			t.Errorf("%q: got %q, want %q", comment, field, want)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/cst"
+)
+
+// Error is a syntax error at a position in a schema file. It implements
+// participle.Error.
+type Error struct {
+	Pos lexer.Position
+	Msg string
+}
+
+// Error returns the error in the form "file:line:column: message".
+func (e *Error) Error() string {
+	return participle.FormatError(e)
+}
+
+// Message returns the error message without position.
+func (e *Error) Message() string {
+	return e.Msg
+}
+
+// Position returns the position of the error.
+func (e *Error) Position() lexer.Position {
+	return e.Pos
+}
+
+// ErrorList is the list of syntax errors found in a schema, sorted by
+// position.
+type ErrorList []*Error
+
+// Error returns the first error and the number of remaining errors.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0].Error(), len(l)-1)
+}
+
+// Unwrap returns the individual errors.
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, err := range l {
+		errs[i] = err
+	}
+	return errs
+}
+
+// recoverSchema parses src block by block so that a syntax error only
+// affects the block it occurs in. Lines with errors are dropped from their
+// block and the block is parsed again, so a schema with a typo still yields
+// every declaration plus one error per broken line. err is the error of the
+// failed attempt to parse the whole file.
+func recoverSchema(filename, src string, err error) (*ast.SchemaAst, error) {
+	schema := &ast.SchemaAst{}
+	var errs ErrorList
+
+	pos := lexer.Position{Filename: filename, Line: 1, Column: 1}
+	// Report stray text between blocks once per line
+	inGarbage := false
+	for _, element := range cst.Parse(src).Elements() {
+		text := element.String()
+		switch e := element.(type) {
+		case *cst.Token:
+			if !e.IsTrivia() && !inGarbage {
+				inGarbage = true
+				errs = append(errs, &Error{Pos: pos, Msg: fmt.Sprintf("unexpected token %q (expected a model, enum, type, view, datasource or generator block)", e.Text)})
+			}
+			if e.Kind == cst.TokenNewline {
+				inGarbage = false
+			}
+		case *cst.Block:
+			inGarbage = false
+			tops, blockErrs := recoverBlock(e, pos)
+			schema.Tops = append(schema.Tops, tops...)
+			errs = append(errs, blockErrs...)
+		}
+		pos.Advance(text)
+	}
+
+	for _, top := range schema.Tops {
+		resolveTopArity(top)
+	}
+	if len(errs) == 0 {
+		// Every block parses on its own, so the error is between blocks
+		errs = append(errs, toError(err, pos))
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Pos.Offset < errs[j].Pos.Offset })
+	return schema, errs
+}
+
+// recoverBlock parses a single block starting at base. After each error the
+// line containing it is blanked out and the block is parsed again.
+func recoverBlock(block *cst.Block, base lexer.Position) ([]ast.Top, ErrorList) {
+	text := block.String()
+	lines := memberSpans(block)
+	var errs ErrorList
+
+	for {
+		raw, err := parseFragment(text, base)
+		if err == nil {
+			tops := make([]ast.Top, 0, len(raw.Items))
+			for _, item := range raw.Items {
+				if top := item.ToTop(); top != nil {
+					tops = append(tops, top)
+				}
+			}
+			return tops, errs
+		}
+
+		perr := toError(err, base)
+		if len(errs) == 0 || errs[len(errs)-1].Pos.Offset != perr.Pos.Offset {
+			errs = append(errs, perr)
+		}
+
+		line, ok := lineAt(lines, perr.Pos.Offset-base.Offset)
+		if !ok {
+			// The error is in the block header: drop the whole block
+			return nil, errs
+		}
+		text = text[:line.start] + blank(text[line.start:line.end]) + text[line.end:]
+		line.blanked = true
+	}
+}
+
+// span is the byte range of a block member, relative to the block start.
+type span struct {
+	start, end int
+	blanked    bool
+	// opener is the line that opened an unclosed parenthesis or bracket
+	// this line belongs to.
+	opener *span
+}
+
+// memberSpans returns the ranges of the non-trivia members of block.
+// A member with an unclosed parenthesis swallows the rest of the block, so
+// it is split into physical lines that all point back to its first line.
+func memberSpans(block *cst.Block) []*span {
+	var spans []*span
+	offset := 0
+	for _, element := range block.Elements() {
+		text := element.String()
+		member, ok := element.(*cst.Member)
+		if ok && member.Kind() != cst.MemberBlank && member.Kind() != cst.MemberComment {
+			if !isUnbalanced(text) {
+				spans = append(spans, &span{start: offset, end: offset + len(text)})
+			} else {
+				var opener *span
+				start := offset
+				for _, line := range strings.SplitAfter(text, "\n") {
+					if line == "" {
+						continue
+					}
+					line := &span{start: start, end: start + len(line), opener: opener}
+					if opener == nil {
+						opener = line
+					}
+					spans = append(spans, line)
+					start = line.end
+				}
+			}
+		}
+		offset += len(text)
+	}
+	return spans
+}
+
+// isUnbalanced reports whether text has more opening than closing
+// parentheses and brackets.
+func isUnbalanced(text string) bool {
+	depth := 0
+	for _, token := range cst.Tokenize(text) {
+		if token.Kind != cst.TokenPunct {
+			continue
+		}
+		switch token.Text {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		}
+	}
+	return depth > 0
+}
+
+// lineAt returns the member containing offset. Errors are often reported at
+// the first token after the broken line, so when no member contains offset
+// the last member before it is used instead. Lines after an unclosed
+// parenthesis resolve to the line that opened it. Members that have already
+// been blanked are never returned.
+func lineAt(lines []*span, offset int) (*span, bool) {
+	var candidate *span
+	for _, line := range lines {
+		if line.start > offset {
+			break
+		}
+		candidate = line
+		if offset < line.end {
+			break
+		}
+	}
+	if candidate != nil && candidate.opener != nil && !candidate.opener.blanked {
+		candidate = candidate.opener
+	}
+	if candidate == nil || candidate.blanked {
+		return nil, false
+	}
+	return candidate, true
+}
+
+// blank replaces everything but line breaks with spaces, keeping offsets of
+// the following text intact.
+func blank(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' || text[i] == '\r' {
+			b.WriteByte(text[i])
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// parseFragment parses text as if it started at base in the original file,
+// so that positions in the AST and in errors refer to the original file.
+func parseFragment(text string, base lexer.Position) (*RawSchema, error) {
+	lex, err := parser.Lexer().Lex(base.Filename, strings.NewReader(text))
+	if err != nil {
+		return nil, err
+	}
+	peeker, err := lexer.Upgrade(&shiftedLexer{Lexer: lex, base: base}, elidedTypes()...)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseFromLexer(peeker)
+}
+
+// elidedTypes returns the token types the parser skips.
+func elidedTypes() []lexer.TokenType {
+	symbols := parser.Lexer().Symbols()
+	names := []string{"Whitespace", "Newline", "Comment", "MultiLineComment", "DocComment"}
+	types := make([]lexer.TokenType, len(names))
+	for i, name := range names {
+		types[i] = symbols[name]
+	}
+	return types
+}
+
+// shiftedLexer moves token and lexer error positions from a fragment to
+// the file it was taken from.
+type shiftedLexer struct {
+	lexer.Lexer
+	base lexer.Position
+}
+
+// Next returns the next token with its position shifted.
+func (l *shiftedLexer) Next() (lexer.Token, error) {
+	token, err := l.Lexer.Next()
+	if err != nil {
+		var perr participle.Error
+		if errors.As(err, &perr) {
+			err = &lexer.Error{Msg: perr.Message(), Pos: l.shift(perr.Position())}
+		}
+		return token, err
+	}
+	token.Pos = l.shift(token.Pos)
+	return token, nil
+}
+
+func (l *shiftedLexer) shift(pos lexer.Position) lexer.Position {
+	pos = l.base.Add(pos)
+	pos.Filename = l.base.Filename
+	return pos
+}
+
+// toError converts a parser error into an Error.
+func toError(err error, base lexer.Position) *Error {
+	var perr participle.Error
+	if errors.As(err, &perr) {
+		return &Error{Pos: perr.Position(), Msg: perr.Message()}
+	}
+	return &Error{Pos: base, Msg: err.Error()}
+}
@@ -2,12 +2,10 @@
 package psl
 
 import (
-	"strings"
-
 	"github.com/satishbabariya/prisma-go/psl/core"
 	"github.com/satishbabariya/prisma-go/psl/diagnostics"
 	"github.com/satishbabariya/prisma-go/psl/formatting"
-	parser "github.com/satishbabariya/prisma-go/psl/parsing/v2"
+	"github.com/satishbabariya/prisma-go/psl/parsing"
 	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
 )
 
@@ -22,25 +20,14 @@ type (
 )
 
 // ParseSchema parses a Prisma schema string and returns the AST and diagnostics.
+// The AST is partial when the schema has syntax errors.
 func ParseSchema(input string) (*ast.SchemaAst, diagnostics.Diagnostics) {
-	// Adapt V2 parser (returns error) to legacy signature (returns Diagnostics)
-	schema, err := parser.ParseSchema("schema.prisma", strings.NewReader(input))
-	var diags diagnostics.Diagnostics
-	if err != nil {
-		// Create a simple error diagnostic
-		diags.PushError(diagnostics.NewDatamodelError(err.Error(), diagnostics.Span{}))
-	}
-	return schema, diags
+	return parsing.ParseSchema(input)
 }
 
 // ParseSchemaFromFile parses a Prisma schema from a source file.
 func ParseSchemaFromFile(file core.SourceFile) (*ast.SchemaAst, diagnostics.Diagnostics) {
-	schema, err := parser.ParseSchema(file.Path, strings.NewReader(file.Data))
-	var diags diagnostics.Diagnostics
-	if err != nil {
-		diags.PushError(diagnostics.NewDatamodelError(err.Error(), diagnostics.Span{}))
-	}
-	return schema, diags
+	return parsing.ParseSchemaFromSourceFile(file)
 }
 
 // Reformat reformats a Prisma schema string.
@@ -0,0 +1,271 @@
+package psl
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/psl/core"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+	"github.com/satishbabariya/prisma-go/psl/parsing"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+)
+
+// SchemaFileExtension is the file extension of Prisma schema files.
+const SchemaFileExtension = ".prisma"
+
+// builtinScalarTypes lists the scalar types that never need a declaration.
+var builtinScalarTypes = map[string]bool{
+	"String":   true,
+	"Int":      true,
+	"BigInt":   true,
+	"Float":    true,
+	"Decimal":  true,
+	"Boolean":  true,
+	"DateTime": true,
+	"Json":     true,
+	"Bytes":    true,
+}
+
+// IsSchemaFolder reports whether path points to a multi-file schema directory.
+func IsSchemaFolder(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// ReadSchemaFiles reads the schema located at path.
+// A regular file is returned as a single source file. A directory is walked
+// recursively and every *.prisma file in it is returned, sorted by path so
+// the resulting schema is deterministic.
+func ReadSchemaFiles(path string) ([]core.SourceFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []core.SourceFile{core.NewSourceFile(path, string(content))}, nil
+	}
+
+	var paths []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Skip hidden directories such as .git
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) == SchemaFileExtension {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no %s files found in %s", SchemaFileExtension, path)
+	}
+
+	sort.Strings(paths)
+
+	files := make([]core.SourceFile, 0, len(paths))
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, core.NewSourceFile(p, string(content)))
+	}
+	return files, nil
+}
+
+// ParseSchemaFiles parses several schema files into a single AST.
+// Top-level declarations are merged in file order. Besides syntax errors it
+// reports declarations that are defined in more than one file and field types
+// that are not declared in any file. Syntax errors do not stop parsing: the
+// declarations that could be recovered are still merged. The FileID of every
+// diagnostic span is the index of the offending file in files.
+func ParseSchemaFiles(files []core.SourceFile) (*ast.SchemaAst, diagnostics.Diagnostics) {
+	diags := diagnostics.NewDiagnostics()
+	merged := &ast.SchemaAst{}
+	fileIDs := make(map[string]diagnostics.FileID, len(files))
+
+	for i, file := range files {
+		fileID := diagnostics.FileID(i)
+		fileIDs[file.Path] = fileID
+
+		schema, fileDiags := parsing.ParseSourceFile(file, fileID)
+		for _, err := range fileDiags.Errors() {
+			diags.PushError(err)
+		}
+		for _, warn := range fileDiags.Warnings() {
+			diags.PushWarning(warn)
+		}
+		merged.Tops = append(merged.Tops, schema.Tops...)
+	}
+
+	validateMergedTops(merged, fileIDs, &diags)
+
+	return merged, diags
+}
+
+// ValidateSchemaFiles parses several schema files like ParseSchemaFiles and,
+// when they parse cleanly, validates the merged schema with the parser
+// database, which also reports relation, attribute and naming errors.
+func ValidateSchemaFiles(files []core.SourceFile) (*ast.SchemaAst, diagnostics.Diagnostics) {
+	schema, diags := ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		return schema, diags
+	}
+
+	dbDiags := diagnostics.NewDiagnostics()
+	database.NewParserDatabase(files, &dbDiags, database.NoExtensionTypes{})
+	for _, err := range dbDiags.Errors() {
+		diags.PushError(err)
+	}
+	for _, warn := range dbDiags.Warnings() {
+		diags.PushWarning(warn)
+	}
+	return schema, diags
+}
+
+// RenderDiagnostics renders errors of diags next to the file they belong to.
+func RenderDiagnostics(files []core.SourceFile, diags diagnostics.Diagnostics) string {
+	var result strings.Builder
+	for i, file := range files {
+		fileDiags := diags.ForFile(diagnostics.FileID(i))
+		result.WriteString(fileDiags.ToPrettyString(file.Path, file.Data))
+	}
+	return result.String()
+}
+
+// RenderWarnings renders warnings of diags next to the file they belong to.
+func RenderWarnings(files []core.SourceFile, diags diagnostics.Diagnostics) string {
+	var result strings.Builder
+	for i, file := range files {
+		fileDiags := diags.ForFile(diagnostics.FileID(i))
+		result.WriteString(fileDiags.WarningsToPrettyString(file.Path, file.Data))
+	}
+	return result.String()
+}
+
+// validateMergedTops checks the cross-file invariants of a merged schema.
+func validateMergedTops(schema *ast.SchemaAst, fileIDs map[string]diagnostics.FileID, diags *diagnostics.Diagnostics) {
+	typeNames := make(map[string]string)
+	datasources := make(map[string]bool)
+	generators := make(map[string]bool)
+
+	for _, top := range schema.Tops {
+		name := top.GetName()
+		ident := topIdentifier(top)
+		if ident == nil {
+			continue
+		}
+		span := identifierSpan(ident, fileIDs)
+
+		switch top.(type) {
+		case *ast.SourceConfig:
+			if datasources[name] {
+				diags.PushError(diagnostics.NewDuplicateTopError(name, "datasource", "datasource", span))
+			}
+			datasources[name] = true
+		case *ast.GeneratorConfig:
+			if generators[name] {
+				diags.PushError(diagnostics.NewDuplicateTopError(name, "generator", "generator", span))
+			}
+			generators[name] = true
+		case *ast.ExtendedType:
+			// Extensions refer to types declared elsewhere
+		default:
+			topType := topTypeName(top)
+			if existing, ok := typeNames[name]; ok {
+				diags.PushError(diagnostics.NewDuplicateTopError(name, topType, existing, span))
+				continue
+			}
+			typeNames[name] = topType
+		}
+	}
+
+	for _, top := range schema.Tops {
+		var fields []*ast.Field
+		switch t := top.(type) {
+		case *ast.Model:
+			fields = t.Fields
+		case *ast.CompositeType:
+			fields = t.Fields
+		default:
+			continue
+		}
+
+		for _, field := range fields {
+			if field.Type == nil || field.Type.IsUnsupported() {
+				continue
+			}
+			typeName := field.Type.Name
+			if builtinScalarTypes[typeName] || strings.Contains(typeName, ".") {
+				continue
+			}
+			if _, ok := typeNames[typeName]; ok {
+				continue
+			}
+			fileID := fileIDs[field.Type.Pos.Filename]
+			span := diagnostics.NewSpan(field.Type.Pos.Offset, field.Type.Pos.Offset+len(typeName), fileID)
+			diags.PushError(diagnostics.NewTypeNotFoundError(typeName, span))
+		}
+	}
+}
+
+// topIdentifier returns the name identifier of a top-level declaration.
+func topIdentifier(top ast.Top) *ast.Identifier {
+	switch t := top.(type) {
+	case *ast.Model:
+		return t.Name
+	case *ast.Enum:
+		return t.Name
+	case *ast.CompositeType:
+		return t.Name
+	case *ast.SourceConfig:
+		return t.Name
+	case *ast.GeneratorConfig:
+		return t.Name
+	case *ast.ExtendedType:
+		return t.Name
+	}
+	return nil
+}
+
+// topTypeName returns the keyword used in diagnostics for a declaration.
+func topTypeName(top ast.Top) string {
+	switch t := top.(type) {
+	case *ast.Model:
+		if t.IsView() {
+			return "view"
+		}
+		return "model"
+	case *ast.Enum:
+		return "enum"
+	case *ast.CompositeType:
+		return "composite type"
+	}
+	return "declaration"
+}
+
+// identifierSpan returns the span of an identifier in its source file.
+func identifierSpan(ident *ast.Identifier, fileIDs map[string]diagnostics.FileID) diagnostics.Span {
+	fileID := fileIDs[ident.Pos.Filename]
+	return diagnostics.NewSpan(ident.Pos.Offset, ident.Pos.Offset+len(ident.Name), fileID)
+}
@@ -0,0 +1,118 @@
+package psl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/psl/core"
+	"github.com/satishbabariya/prisma-go/psl/database"
+	"github.com/satishbabariya/prisma-go/psl/diagnostics"
+)
+
+const datasourceFile = `datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+`
+
+func TestReadSchemaFilesMerges(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		"schema.prisma":         datasourceFile,
+		"models/user.prisma":    "model User {\n  id    Int    @id\n  posts Post[]\n}\n",
+		"models/post.prisma":    "model Post {\n  id       Int  @id\n  authorId Int\n  author   User @relation(fields: [authorId], references: [id])\n}\n",
+		".git/ignored.prisma":   "model Ignored {\n  id Int @id\n}\n",
+		"models/notes.markdown": "not a schema",
+	} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := ReadSchemaFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, file := range files {
+		rel, _ := filepath.Rel(dir, file.Path)
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	if got := strings.Join(paths, ","); got != "models/post.prisma,models/user.prisma,schema.prisma" {
+		t.Fatalf("files = %s, want the .prisma files outside hidden folders, sorted", got)
+	}
+
+	// Post refers to User, which is declared in another file
+	schema, diags := ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(RenderDiagnostics(files, diags))
+	}
+	var names []string
+	for _, top := range schema.Tops {
+		names = append(names, top.GetName())
+	}
+	if got := strings.Join(names, ","); got != "Post,User,db" {
+		t.Errorf("merged tops = %s, want Post,User,db in file order", got)
+	}
+}
+
+func TestParseSchemaFilesDiagnostics(t *testing.T) {
+	files := []core.SourceFile{
+		NewSourceFile("a.prisma", datasourceFile+"\nmodel User {\n  id Int @id\n}\n"),
+		NewSourceFile("b.prisma", "model User {\n  id Int @id\n}\n\nmodel Post {\n  id  Int @id\n  tag Tag\n}\n\nmodel Broken {\n  id Int @id(\n}\n"),
+	}
+	_, diags := ParseSchemaFiles(files)
+
+	errs := diags.Errors()
+	if len(errs) != 3 {
+		t.Fatalf("got %d errors, want 3:\n%s", len(errs), RenderDiagnostics(files, diags))
+	}
+	for _, want := range []string{"User", "Tag"} {
+		found := false
+		for _, err := range errs {
+			if strings.Contains(err.Message(), `"`+want+`"`) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no error names %s:\n%s", want, RenderDiagnostics(files, diags))
+		}
+	}
+	for _, err := range errs {
+		if err.Span().FileID != 1 {
+			t.Errorf("%q is reported in file %d, want b.prisma", err.Message(), err.Span().FileID)
+		}
+	}
+	if rendered := RenderDiagnostics(files, diags); strings.Contains(rendered, "a.prisma") {
+		t.Errorf("errors are rendered against a.prisma:\n%s", rendered)
+	}
+}
+
+func TestParserDatabaseDiagnosticFiles(t *testing.T) {
+	files := []core.SourceFile{
+		NewSourceFile("a.prisma", datasourceFile+"\nmodel User {\n  id Int @id\n}\n"),
+		NewSourceFile("b.prisma", "model Post {\n  id    Int    @id\n  title String @unknown\n  title String\n}\n\nmodel Tag {\n  id    Int @id\n  other Int @id\n}\n"),
+	}
+	diags := diagnostics.NewDiagnostics()
+	database.NewParserDatabase(files, &diags, database.NoExtensionTypes{})
+
+	if len(diags.Errors()) == 0 {
+		t.Fatal("the semantic errors of b.prisma are not reported")
+	}
+	for _, err := range diags.Errors() {
+		if err.Span().FileID != 1 {
+			t.Errorf("%q is reported in file %d, want b.prisma", err.Message(), err.Span().FileID)
+		}
+	}
+	for _, want := range []string{`"title" is already defined`, `"@unknown"`, "At most one field"} {
+		if !strings.Contains(RenderDiagnostics(files, diags), want) {
+			t.Errorf("no error contains %s", want)
+		}
+	}
+}
@@ -159,7 +159,7 @@ func Validate(
 	db := database.NewSingleFile(file, &diags, extensionTypes)
 
 	// Parse the schema
-	ast, parseDiags := parsing.ParseSchemaFromSourceFileV2(file)
+	ast, parseDiags := parsing.ParseSchemaFromSourceFile(file)
 	// Merge diagnostics
 	for _, err := range parseDiags.Errors() {
 		diags.PushError(err)
@@ -215,7 +215,7 @@ func ParseSchemaWithoutValidation(
 	db := database.NewSingleFile(file, &diags, extensionTypes)
 
 	// Parse the schema
-	ast, parseDiags := parsing.ParseSchemaFromSourceFileV2(file)
+	ast, parseDiags := parsing.ParseSchemaFromSourceFile(file)
 	// Merge diagnostics
 	for _, err := range parseDiags.Errors() {
 		diags.PushError(err)
@@ -269,8 +269,8 @@ func ValidateMultiFile(
 	}
 
 	// Extract configuration from all ASTs
-	for _, file := range files {
-		ast, parseDiags := parsing.ParseSchemaFromSourceFileV2(file)
+	for i, file := range files {
+		ast, parseDiags := parsing.ParseSourceFile(file, diagnostics.FileID(i))
 		// Merge diagnostics
 		for _, err := range parseDiags.Errors() {
 			diags.PushError(err)
@@ -155,6 +155,18 @@ func (w *WhereBuilder) JsonContains(field string, value interface{}) *WhereBuild
 	return w
 }
 
+// JsonStringContains adds a JSON string filter condition (checks if the string at path contains a substring)
+func (w *WhereBuilder) JsonStringContains(field string, path string, value string) *WhereBuilder {
+	w.conditions = append(w.conditions, sqlgen.Condition{
+		Field:    field,
+		Operator: "JSON_STRING_CONTAINS",
+		Value:    value,
+		JsonPath: path,
+		JsonType: "string_contains",
+	})
+	return w
+}
+
 // JsonArrayContains adds a JSON array contains filter condition (checks if array contains a value)
 func (w *WhereBuilder) JsonArrayContains(field string, path string, value interface{}) *WhereBuilder {
 	w.conditions = append(w.conditions, sqlgen.Condition{
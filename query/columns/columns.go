@@ -3,6 +3,7 @@ package columns
 
 import (
 	"github.com/satishbabariya/prisma-go/query/sqlgen"
+	"github.com/satishbabariya/prisma-go/runtime/types"
 )
 
 // Column represents a database column with type safety
@@ -383,6 +384,88 @@ func (c DateTimeColumn) LTE(value interface{}) Condition {
 	}
 }
 
+// DecimalColumn represents an exact decimal column
+type DecimalColumn struct {
+	BaseColumn
+}
+
+// NewDecimalColumn creates a new DecimalColumn
+func NewDecimalColumn(table, name string) DecimalColumn {
+	return DecimalColumn{
+		BaseColumn: BaseColumn{
+			name:  name,
+			table: table,
+		},
+	}
+}
+
+// EQ creates an equality condition
+func (c DecimalColumn) EQ(value types.Decimal) Condition {
+	return Condition{
+		Column:   c,
+		Operator: "=",
+		Value:    value,
+	}
+}
+
+// NOT_EQ creates a not-equal condition
+func (c DecimalColumn) NOT_EQ(value types.Decimal) Condition {
+	return Condition{
+		Column:   c,
+		Operator: "!=",
+		Value:    value,
+	}
+}
+
+// GT creates a greater-than condition
+func (c DecimalColumn) GT(value types.Decimal) Condition {
+	return Condition{
+		Column:   c,
+		Operator: ">",
+		Value:    value,
+	}
+}
+
+// GTE creates a greater-than-or-equal condition
+func (c DecimalColumn) GTE(value types.Decimal) Condition {
+	return Condition{
+		Column:   c,
+		Operator: ">=",
+		Value:    value,
+	}
+}
+
+// LT creates a less-than condition
+func (c DecimalColumn) LT(value types.Decimal) Condition {
+	return Condition{
+		Column:   c,
+		Operator: "<",
+		Value:    value,
+	}
+}
+
+// LTE creates a less-than-or-equal condition
+func (c DecimalColumn) LTE(value types.Decimal) Condition {
+	return Condition{
+		Column:   c,
+		Operator: "<=",
+		Value:    value,
+	}
+}
+
+// IN creates an IN condition
+func (c DecimalColumn) IN(values []types.Decimal) Condition {
+	interfaceValues := make([]interface{}, len(values))
+	for i, v := range values {
+		interfaceValues[i] = v
+	}
+	return Condition{
+		Column:   c,
+		Operator: "IN",
+		Value:    interfaceValues,
+	}
+}
+
 // Condition represents a column-based condition
 type Condition struct {
 	Column   Column
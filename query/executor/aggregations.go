@@ -5,8 +5,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/satishbabariya/prisma-go/query/sqlgen"
+	"github.com/satishbabariya/prisma-go/runtime/types"
 )
 
 // AggregateResult holds the result of an aggregation query
@@ -19,15 +21,29 @@ type AggregateResult struct {
 }
 
 // Count executes a COUNT query
-func (e *Executor) Count(ctx context.Context, table string, where *sqlgen.WhereClause) (int64, error) {
+func (e *Executor) Count(ctx context.Context, table string, where *sqlgen.WhereClause) (_ int64, err error) {
+	var result int64
+	if ok, err := e.intercept(ctx, table, "count", &Args{Where: where}, &result, func(ctx context.Context, args *Args) (err error) {
+		result, err = e.Count(ctx, table, args.Where)
+		return err
+	}); ok {
+		return result, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "count", where, nil)
+	defer end(&err)
+
 	aggregates := []sqlgen.AggregateFunction{
 		{Function: "COUNT", Field: "*", Alias: "count"},
 	}
 
-	query := e.generator.GenerateAggregate(table, aggregates, where, nil, nil)
+	query, err := rewriteQuery(ctx, e.generator.GenerateAggregate(table, aggregates, e.scopeDeleted(table, where), nil, nil))
+	if err != nil {
+		return 0, err
+	}
 
 	var count int64
-	err := e.db.QueryRowContext(ctx, query.SQL, query.Args...).Scan(&count)
+	err = e.queryRow(ctx, e.db, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("count query failed: %w", err)
 	}
@@ -36,15 +52,29 @@ func (e *Executor) Count(ctx context.Context, table string, where *sqlgen.WhereC
 }
 
 // Sum executes a SUM aggregation
-func (e *Executor) Sum(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (float64, error) {
+func (e *Executor) Sum(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (_ float64, err error) {
+	var result float64
+	if ok, err := e.intercept(ctx, table, "aggregate", &Args{Field: field, Where: where}, &result, func(ctx context.Context, args *Args) (err error) {
+		result, err = e.Sum(ctx, table, args.Field, args.Where)
+		return err
+	}); ok {
+		return result, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "aggregate", where, nil)
+	defer end(&err)
+
 	aggregates := []sqlgen.AggregateFunction{
 		{Function: "SUM", Field: field, Alias: "sum"},
 	}
 
-	query := e.generator.GenerateAggregate(table, aggregates, where, nil, nil)
+	query, err := rewriteQuery(ctx, e.generator.GenerateAggregate(table, aggregates, e.scopeDeleted(table, where), nil, nil))
+	if err != nil {
+		return 0, err
+	}
 
 	var sum sql.NullFloat64
-	err := e.db.QueryRowContext(ctx, query.SQL, query.Args...).Scan(&sum)
+	err = e.queryRow(ctx, e.db, query).Scan(&sum)
 	if err != nil {
 		return 0, fmt.Errorf("sum query failed: %w", err)
 	}
@@ -57,15 +87,29 @@ func (e *Executor) Sum(ctx context.Context, table string, field string, where *s
 }
 
 // Avg executes an AVG aggregation
-func (e *Executor) Avg(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (float64, error) {
+func (e *Executor) Avg(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (_ float64, err error) {
+	var result float64
+	if ok, err := e.intercept(ctx, table, "aggregate", &Args{Field: field, Where: where}, &result, func(ctx context.Context, args *Args) (err error) {
+		result, err = e.Avg(ctx, table, args.Field, args.Where)
+		return err
+	}); ok {
+		return result, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "aggregate", where, nil)
+	defer end(&err)
+
 	aggregates := []sqlgen.AggregateFunction{
 		{Function: "AVG", Field: field, Alias: "avg"},
 	}
 
-	query := e.generator.GenerateAggregate(table, aggregates, where, nil, nil)
+	query, err := rewriteQuery(ctx, e.generator.GenerateAggregate(table, aggregates, e.scopeDeleted(table, where), nil, nil))
+	if err != nil {
+		return 0, err
+	}
 
 	var avg sql.NullFloat64
-	err := e.db.QueryRowContext(ctx, query.SQL, query.Args...).Scan(&avg)
+	err = e.queryRow(ctx, e.db, query).Scan(&avg)
 	if err != nil {
 		return 0, fmt.Errorf("avg query failed: %w", err)
 	}
@@ -78,15 +122,29 @@ func (e *Executor) Avg(ctx context.Context, table string, field string, where *s
 }
 
 // Min executes a MIN aggregation
-func (e *Executor) Min(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (float64, error) {
+func (e *Executor) Min(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (_ float64, err error) {
+	var result float64
+	if ok, err := e.intercept(ctx, table, "aggregate", &Args{Field: field, Where: where}, &result, func(ctx context.Context, args *Args) (err error) {
+		result, err = e.Min(ctx, table, args.Field, args.Where)
+		return err
+	}); ok {
+		return result, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "aggregate", where, nil)
+	defer end(&err)
+
 	aggregates := []sqlgen.AggregateFunction{
 		{Function: "MIN", Field: field, Alias: "min"},
 	}
 
-	query := e.generator.GenerateAggregate(table, aggregates, where, nil, nil)
+	query, err := rewriteQuery(ctx, e.generator.GenerateAggregate(table, aggregates, e.scopeDeleted(table, where), nil, nil))
+	if err != nil {
+		return 0, err
+	}
 
 	var min sql.NullFloat64
-	err := e.db.QueryRowContext(ctx, query.SQL, query.Args...).Scan(&min)
+	err = e.queryRow(ctx, e.db, query).Scan(&min)
 	if err != nil {
 		return 0, fmt.Errorf("min query failed: %w", err)
 	}
@@ -99,15 +157,29 @@ func (e *Executor) Min(ctx context.Context, table string, field string, where *s
 }
 
 // Max executes a MAX aggregation
-func (e *Executor) Max(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (float64, error) {
+func (e *Executor) Max(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (_ float64, err error) {
+	var result float64
+	if ok, err := e.intercept(ctx, table, "aggregate", &Args{Field: field, Where: where}, &result, func(ctx context.Context, args *Args) (err error) {
+		result, err = e.Max(ctx, table, args.Field, args.Where)
+		return err
+	}); ok {
+		return result, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "aggregate", where, nil)
+	defer end(&err)
+
 	aggregates := []sqlgen.AggregateFunction{
 		{Function: "MAX", Field: field, Alias: "max"},
 	}
 
-	query := e.generator.GenerateAggregate(table, aggregates, where, nil, nil)
+	query, err := rewriteQuery(ctx, e.generator.GenerateAggregate(table, aggregates, e.scopeDeleted(table, where), nil, nil))
+	if err != nil {
+		return 0, err
+	}
 
 	var max sql.NullFloat64
-	err := e.db.QueryRowContext(ctx, query.SQL, query.Args...).Scan(&max)
+	err = e.queryRow(ctx, e.db, query).Scan(&max)
 	if err != nil {
 		return 0, fmt.Errorf("max query failed: %w", err)
 	}
@@ -119,11 +191,68 @@ func (e *Executor) Max(ctx context.Context, table string, field string, where *s
 	return max.Float64, nil
 }
 
+// SumDecimal executes a SUM aggregation on a Decimal column without going
+// through float64
+func (e *Executor) SumDecimal(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (types.Decimal, error) {
+	return e.aggregateDecimal(ctx, "SUM", table, field, where)
+}
+
+// AvgDecimal executes an AVG aggregation on a Decimal column
+func (e *Executor) AvgDecimal(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (types.Decimal, error) {
+	return e.aggregateDecimal(ctx, "AVG", table, field, where)
+}
+
+// MinDecimal executes a MIN aggregation on a Decimal column
+func (e *Executor) MinDecimal(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (types.Decimal, error) {
+	return e.aggregateDecimal(ctx, "MIN", table, field, where)
+}
+
+// MaxDecimal executes a MAX aggregation on a Decimal column
+func (e *Executor) MaxDecimal(ctx context.Context, table string, field string, where *sqlgen.WhereClause) (types.Decimal, error) {
+	return e.aggregateDecimal(ctx, "MAX", table, field, where)
+}
+
+// aggregateDecimal runs a single aggregate function and scans the result
+// into a Decimal. NULL results (no rows) return zero.
+func (e *Executor) aggregateDecimal(ctx context.Context, function string, table string, field string, where *sqlgen.WhereClause) (_ types.Decimal, err error) {
+	var decimal types.Decimal
+	if ok, err := e.intercept(ctx, table, "aggregate", &Args{Field: field, Where: where}, &decimal, func(ctx context.Context, args *Args) (err error) {
+		decimal, err = e.aggregateDecimal(ctx, function, table, args.Field, args.Where)
+		return err
+	}); ok {
+		return decimal, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "aggregate", where, nil)
+	defer end(&err)
+
+	alias := strings.ToLower(function)
+	aggregates := []sqlgen.AggregateFunction{
+		{Function: function, Field: field, Alias: alias},
+	}
+
+	query, err := rewriteQuery(ctx, e.generator.GenerateAggregate(table, aggregates, e.scopeDeleted(table, where), nil, nil))
+	if err != nil {
+		return types.Decimal{}, err
+	}
+
+	var result types.NullDecimal
+	err = e.queryRow(ctx, e.db, query).Scan(&result)
+	if err != nil {
+		return types.Decimal{}, fmt.Errorf("%s query failed: %w", alias, err)
+	}
+
+	return result.Decimal, nil
+}
+
 // Aggregate executes multiple aggregations in a single query
-func (e *Executor) Aggregate(ctx context.Context, table string, aggregates []sqlgen.AggregateFunction, where *sqlgen.WhereClause, groupBy *sqlgen.GroupBy) ([]map[string]interface{}, error) {
-	query := e.generator.GenerateAggregate(table, aggregates, where, groupBy, nil)
+func (e *Executor) Aggregate(ctx context.Context, table string, aggregates []sqlgen.AggregateFunction, where *sqlgen.WhereClause, groupBy *sqlgen.GroupBy) (_ []map[string]interface{}, err error) {
+	ctx, end := e.startOperation(ctx, table, "aggregate", where, nil)
+	defer end(&err)
+
+	query := e.generator.GenerateAggregate(table, aggregates, e.scopeDeleted(table, where), groupBy, nil)
 
-	rows, err := e.db.QueryContext(ctx, query.SQL, query.Args...)
+	rows, err := e.query(ctx, e.db, query)
 	if err != nil {
 		return nil, fmt.Errorf("aggregate query failed: %w", err)
 	}
@@ -0,0 +1,79 @@
+// Package executor provides precision handling for Decimal fields.
+package executor
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/runtime/types"
+)
+
+// decimalSpec returns the precision and scale from a `decimal:"p,s"` struct
+// tag, which the generator emits for fields declared as @db.Decimal(p, s).
+func decimalSpec(field reflect.StructField) (precision, scale int32, ok bool) {
+	tag := field.Tag.Get("decimal")
+	if tag == "" {
+		return 0, 0, false
+	}
+	parts := strings.Split(tag, ",")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	p, err := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	s, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	return int32(p), int32(s), true
+}
+
+// fitDecimal rounds a Decimal value to the scale of its column and rejects
+// values with too many integer digits, instead of letting the database
+// round or fail with a driver specific error.
+func fitDecimal(field reflect.StructField, value interface{}) (interface{}, error) {
+	precision, scale, ok := decimalSpec(field)
+	if !ok {
+		return value, nil
+	}
+	switch d := value.(type) {
+	case types.Decimal:
+		fitted, err := d.Fit(precision, scale)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		return fitted, nil
+	case *types.Decimal:
+		if d == nil {
+			return value, nil
+		}
+		fitted, err := d.Fit(precision, scale)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		return &fitted, nil
+	}
+	return value, nil
+}
+
+// rescaleDecimal sets a scanned Decimal to the scale of its column. Drivers
+// such as SQLite return 10.5 for a value stored as 10.50.
+func rescaleDecimal(field reflect.StructField, fieldValue reflect.Value) {
+	_, scale, ok := decimalSpec(field)
+	if !ok {
+		return
+	}
+	switch d := fieldValue.Interface().(type) {
+	case types.Decimal:
+		fieldValue.Set(reflect.ValueOf(d.Round(scale, types.RoundHalfUp)))
+	case *types.Decimal:
+		if d != nil {
+			rounded := d.Round(scale, types.RoundHalfUp)
+			fieldValue.Set(reflect.ValueOf(&rounded))
+		}
+	}
+}
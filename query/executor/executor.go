@@ -25,8 +25,18 @@ type Executor struct {
 	cacheMu      sync.RWMutex
 	queryCache   cache.Cache
 	cacheEnabled bool
+	errorMapper  ErrorMapper
+	observers    *Observers
+	pipeline     *Pipeline
+	models       map[string]string // table -> model
+	softDelete   map[string]string // table -> deletion timestamp column
+	deleted      deletedScope
 }
 
+// ErrorMapper maps the error of a query on table, e.g. to classify the
+// errors of the database driver
+type ErrorMapper func(table string, err error) error
+
 // NewExecutor creates a new query executor
 func NewExecutor(db *sql.DB, provider string) *Executor {
 	debug.Debug("Creating new query executor", "provider", provider)
@@ -59,6 +69,19 @@ func (e *Executor) DisableCache() {
 	e.SetCache(nil)
 }
 
+// SetErrorMapper sets the function mapping the errors of queries. Generated
+// clients set it to classify driver errors by their schema.
+func (e *Executor) SetErrorMapper(mapper ErrorMapper) {
+	e.errorMapper = mapper
+}
+
+// mapError maps *err, if any, with the error mapper of the executor
+func (e *Executor) mapError(table string, err *error) {
+	if *err != nil && e.errorMapper != nil {
+		*err = e.errorMapper(table, *err)
+	}
+}
+
 // getCachedStmt gets a cached prepared statement or creates a new one
 func (e *Executor) getCachedStmt(ctx context.Context, query string) (*sql.Stmt, error) {
 	e.cacheMu.RLock()
@@ -110,7 +133,18 @@ func (e *Executor) FindMany(ctx context.Context, table string, selectFields map[
 }
 
 // FindManyWithRelations executes a SELECT query with relations and maps results to a slice
-func (e *Executor) FindManyWithRelations(ctx context.Context, table string, selectFields map[string]bool, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, limit, offset *int, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) error {
+func (e *Executor) FindManyWithRelations(ctx context.Context, table string, selectFields map[string]bool, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, limit, offset *int, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) (err error) {
+	args := &Args{Select: selectFields, Where: where, OrderBy: orderBy, Limit: limit, Offset: offset, Include: include}
+	if ok, err := e.intercept(ctx, table, "findMany", args, dest, func(ctx context.Context, args *Args) error {
+		return e.FindManyWithRelations(ctx, table, args.Select, args.Where, args.OrderBy, args.Limit, args.Offset, args.Include, relations, dest)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "findMany", where, orderBy)
+	defer end(&err)
+	where = e.scopeDeleted(table, where)
+
 	debug.Debug("FindManyWithRelations called", "table", table, "hasJoins", include != nil && len(include) > 0)
 
 	// Convert selectFields map to slice
@@ -127,7 +161,7 @@ func (e *Executor) FindManyWithRelations(ctx context.Context, table string, sele
 	// Build JOINs if relations are included
 	var joins []sqlgen.Join
 	if include != nil && len(include) > 0 && relations != nil {
-		joins = buildJoinsFromIncludes(table, include, relations, e.provider)
+		joins = e.scopeJoins(buildJoinsFromIncludes(table, include, relations, e.provider))
 		debug.Debug("Built joins from includes", "joinCount", len(joins))
 	}
 
@@ -136,6 +170,9 @@ func (e *Executor) FindManyWithRelations(ctx context.Context, table string, sele
 	} else {
 		query = e.generator.GenerateSelect(table, columns, where, orderBy, limit, offset)
 	}
+	if query, err = rewriteQuery(ctx, query); err != nil {
+		return err
+	}
 	debug.Debug("Generated SQL query", "sql", query.SQL, "args", query.Args)
 
 	// Check cache if enabled
@@ -150,7 +187,7 @@ func (e *Executor) FindManyWithRelations(ctx context.Context, table string, sele
 	}
 
 	debug.Debug("Executing query", "sql", query.SQL, "args", query.Args)
-	rows, err := e.db.QueryContext(ctx, query.SQL, query.Args...)
+	rows, err := e.query(ctx, e.db, query)
 	if err != nil {
 		debug.Error("Query execution failed", "table", table, "sql", query.SQL, "args", query.Args, "error", err)
 		return fmt.Errorf("query execution failed for table %q: SQL=%q, args=%v: %w", table, query.SQL, query.Args, err)
@@ -203,7 +240,18 @@ func (e *Executor) FindManyWithRelations(ctx context.Context, table string, sele
 }
 
 // FindManyWithJoins executes a SELECT query with explicit JOINs and maps results to a slice
-func (e *Executor) FindManyWithJoins(ctx context.Context, table string, selectFields map[string]bool, joins []sqlgen.Join, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, limit, offset *int, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) error {
+func (e *Executor) FindManyWithJoins(ctx context.Context, table string, selectFields map[string]bool, joins []sqlgen.Join, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, limit, offset *int, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) (err error) {
+	args := &Args{Select: selectFields, Joins: joins, Where: where, OrderBy: orderBy, Limit: limit, Offset: offset, Include: include}
+	if ok, err := e.intercept(ctx, table, "findMany", args, dest, func(ctx context.Context, args *Args) error {
+		return e.FindManyWithJoins(ctx, table, args.Select, args.Joins, args.Where, args.OrderBy, args.Limit, args.Offset, args.Include, relations, dest)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "findMany", where, orderBy)
+	defer end(&err)
+	where = e.scopeDeleted(table, where)
+
 	// Convert selectFields map to slice
 	var columns []string
 	if selectFields != nil && len(selectFields) > 0 {
@@ -220,7 +268,7 @@ func (e *Executor) FindManyWithJoins(ctx context.Context, table string, selectFi
 
 	// Add relation-based joins if includes are specified
 	if include != nil && len(include) > 0 && relations != nil {
-		relationJoins := buildJoinsFromIncludes(table, include, relations, e.provider)
+		relationJoins := e.scopeJoins(buildJoinsFromIncludes(table, include, relations, e.provider))
 		allJoins = append(allJoins, relationJoins...)
 	}
 
@@ -229,6 +277,9 @@ func (e *Executor) FindManyWithJoins(ctx context.Context, table string, selectFi
 	} else {
 		query = e.generator.GenerateSelect(table, columns, where, orderBy, limit, offset)
 	}
+	if query, err = rewriteQuery(ctx, query); err != nil {
+		return err
+	}
 
 	// Check cache if enabled
 	if e.cacheEnabled && e.queryCache != nil {
@@ -238,7 +289,7 @@ func (e *Executor) FindManyWithJoins(ctx context.Context, table string, selectFi
 		}
 	}
 
-	rows, err := e.db.QueryContext(ctx, query.SQL, query.Args...)
+	rows, err := e.query(ctx, e.db, query)
 	if err != nil {
 		return fmt.Errorf("query execution failed: %w", err)
 	}
@@ -274,7 +325,18 @@ func (e *Executor) FindManyWithJoins(ctx context.Context, table string, selectFi
 }
 
 // FindFirstWithJoins executes a SELECT query with explicit JOINs and returns the first result
-func (e *Executor) FindFirstWithJoins(ctx context.Context, table string, selectFields map[string]bool, joins []sqlgen.Join, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) error {
+func (e *Executor) FindFirstWithJoins(ctx context.Context, table string, selectFields map[string]bool, joins []sqlgen.Join, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) (err error) {
+	args := &Args{Select: selectFields, Joins: joins, Where: where, OrderBy: orderBy, Include: include}
+	if ok, err := e.intercept(ctx, table, "findFirst", args, dest, func(ctx context.Context, args *Args) error {
+		return e.FindFirstWithJoins(ctx, table, args.Select, args.Joins, args.Where, args.OrderBy, args.Include, relations, dest)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "findFirst", where, orderBy)
+	defer end(&err)
+	where = e.scopeDeleted(table, where)
+
 	// Convert selectFields map to slice
 	var columns []string
 	if selectFields != nil && len(selectFields) > 0 {
@@ -292,7 +354,7 @@ func (e *Executor) FindFirstWithJoins(ctx context.Context, table string, selectF
 
 	// Add relation-based joins if includes are specified
 	if include != nil && len(include) > 0 && relations != nil {
-		relationJoins := buildJoinsFromIncludes(table, include, relations, e.provider)
+		relationJoins := e.scopeJoins(buildJoinsFromIncludes(table, include, relations, e.provider))
 		allJoins = append(allJoins, relationJoins...)
 	}
 
@@ -301,8 +363,11 @@ func (e *Executor) FindFirstWithJoins(ctx context.Context, table string, selectF
 	} else {
 		query = e.generator.GenerateSelect(table, columns, where, orderBy, &limit, nil)
 	}
+	if query, err = rewriteQuery(ctx, query); err != nil {
+		return err
+	}
 
-	rows, err := e.db.QueryContext(ctx, query.SQL, query.Args...)
+	rows, err := e.query(ctx, e.db, query)
 	if err != nil {
 		return fmt.Errorf("query execution failed: %w", err)
 	}
@@ -323,14 +388,13 @@ func (e *Executor) FindFirstWithJoins(ctx context.Context, table string, selectF
 			return err
 		}
 	} else {
-		// Simple scan without JOINs - scan first row only
-		if rows.Next() {
-			err = e.scanRows(rows, dest)
-			if err != nil {
-				return fmt.Errorf("failed to scan result: %w", err)
-			}
-		} else {
-			return sql.ErrNoRows
+		// Simple scan without JOINs - the row read above is the result
+		resultColumns, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to get columns: %w", err)
+		}
+		if err := e.scanRowIntoStruct(rows, resultColumns, dest); err != nil {
+			return fmt.Errorf("failed to scan result: %w", err)
 		}
 	}
 
@@ -389,7 +453,18 @@ func (e *Executor) FindFirst(ctx context.Context, table string, selectFields map
 }
 
 // FindFirstWithRelations executes a SELECT query with relations and maps to a single struct
-func (e *Executor) FindFirstWithRelations(ctx context.Context, table string, selectFields map[string]bool, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) error {
+func (e *Executor) FindFirstWithRelations(ctx context.Context, table string, selectFields map[string]bool, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) (err error) {
+	args := &Args{Select: selectFields, Where: where, OrderBy: orderBy, Include: include}
+	if ok, err := e.intercept(ctx, table, "findFirst", args, dest, func(ctx context.Context, args *Args) error {
+		return e.FindFirstWithRelations(ctx, table, args.Select, args.Where, args.OrderBy, args.Include, relations, dest)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "findFirst", where, orderBy)
+	defer end(&err)
+	where = e.scopeDeleted(table, where)
+
 	// Convert selectFields map to slice
 	var columns []string
 	if selectFields != nil && len(selectFields) > 0 {
@@ -404,7 +479,7 @@ func (e *Executor) FindFirstWithRelations(ctx context.Context, table string, sel
 	// Build JOINs if relations are included
 	var joins []sqlgen.Join
 	if include != nil && len(include) > 0 && relations != nil {
-		joins = buildJoinsFromIncludes(table, include, relations, e.provider)
+		joins = e.scopeJoins(buildJoinsFromIncludes(table, include, relations, e.provider))
 	}
 
 	if len(joins) > 0 {
@@ -412,6 +487,9 @@ func (e *Executor) FindFirstWithRelations(ctx context.Context, table string, sel
 	} else {
 		query = e.generator.GenerateSelect(table, columns, where, orderBy, &limit, nil)
 	}
+	if query, err = rewriteQuery(ctx, query); err != nil {
+		return err
+	}
 
 	// Check cache if enabled
 	if e.cacheEnabled && e.queryCache != nil {
@@ -422,7 +500,7 @@ func (e *Executor) FindFirstWithRelations(ctx context.Context, table string, sel
 		}
 	}
 
-	rows, err := e.db.QueryContext(ctx, query.SQL, query.Args...)
+	rows, err := e.query(ctx, e.db, query)
 	if err != nil {
 		return fmt.Errorf("query execution failed: %w", err)
 	}
@@ -457,13 +535,23 @@ func (e *Executor) FindFirstWithRelations(ctx context.Context, table string, sel
 }
 
 // Create executes an INSERT query and returns the created record
-func (e *Executor) Create(ctx context.Context, table string, data interface{}, nestedWrites ...*builder.NestedWriteOperation) (interface{}, error) {
+func (e *Executor) Create(ctx context.Context, table string, data interface{}, nestedWrites ...*builder.NestedWriteOperation) (_ interface{}, err error) {
+	var created interface{}
+	if ok, err := e.intercept(ctx, table, "create", &Args{Data: data}, &created, func(ctx context.Context, args *Args) (err error) {
+		created, err = e.Create(ctx, table, args.Data, nestedWrites...)
+		return err
+	}); ok {
+		return created, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "create", nil, nil)
+	defer end(&err)
+
 	// Invalidate cache for this table
 	e.invalidateTableCache(table)
 
 	// Start transaction for nested writes
 	var tx *sql.Tx
-	var err error
 	if len(nestedWrites) > 0 {
 		tx, err = e.db.BeginTx(ctx, nil)
 		if err != nil {
@@ -483,16 +571,24 @@ func (e *Executor) Create(ctx context.Context, table string, data interface{}, n
 		return nil, fmt.Errorf("failed to extract insert data: %w", err)
 	}
 
-	query := e.generator.GenerateInsert(table, columns, values)
+	query, err := rewriteQuery(ctx, e.generator.GenerateInsert(table, columns, values))
+	if err != nil {
+		return nil, err
+	}
+
+	// PostgreSQL returns the inserted row, so the INSERT must only run once
+	if tx == nil && (e.provider == "postgresql" || e.provider == "postgres") {
+		return e.insertReturning(ctx, e.db, query, data)
+	}
 
 	var result sql.Result
 	var insertedID interface{}
 
 	// Execute INSERT
 	if tx != nil {
-		result, err = tx.ExecContext(ctx, query.SQL, query.Args...)
+		result, err = e.exec(ctx, tx, query)
 	} else {
-		result, err = e.db.ExecContext(ctx, query.SQL, query.Args...)
+		result, err = e.exec(ctx, e.db, query)
 	}
 
 	if err != nil {
@@ -519,41 +615,82 @@ func (e *Executor) Create(ctx context.Context, table string, data interface{}, n
 
 	// Query back the record
 	if tx != nil {
-		// Use transaction for query
-		where := &sqlgen.WhereClause{
-			Conditions: []sqlgen.Condition{
-				{Field: "id", Operator: "=", Value: insertedID},
-			},
-			Operator: "AND",
-		}
-		var found interface{} = data
-		// For now, query using regular connection (transaction query would need separate method)
-		if err := e.FindFirst(ctx, table, nil, where, nil, nil, &found); err == nil {
-			return found, nil
-		}
-	} else {
-		// For PostgreSQL, we can use RETURNING
-		if e.provider == "postgresql" || e.provider == "postgres" {
-			row := e.db.QueryRowContext(ctx, query.SQL, query.Args...)
-			return e.scanRowToStruct(row, data)
+		return e.findInserted(ctx, tx, table, data, insertedID), nil
+	}
+	return e.findInserted(ctx, e.db, table, data, insertedID), nil
+}
+
+// insertReturning executes an INSERT ... RETURNING * query and scans the
+// returned row into a new value of the type of data
+func (e *Executor) insertReturning(ctx context.Context, q queryer, query *sqlgen.Query, data interface{}) (interface{}, error) {
+	rows, err := e.query(ctx, q, query)
+	if err != nil {
+		return nil, fmt.Errorf("insert failed: %w", err)
+	}
+	defer rows.Close()
+	return e.scanInserted(rows, data)
+}
+
+// findInserted queries back the record inserted from data by its id. data
+// is returned as is when the record cannot be found.
+func (e *Executor) findInserted(ctx context.Context, q queryer, table string, data interface{}, id interface{}) interface{} {
+	if id == nil {
+		return data
+	}
+	where := &sqlgen.WhereClause{
+		Conditions: []sqlgen.Condition{
+			{Field: e.idColumn(data), Operator: "=", Value: id},
+		},
+		Operator: "AND",
+	}
+	limit := 1
+	query := e.generator.GenerateSelect(table, nil, where, nil, &limit, nil)
+	rows, err := e.query(ctx, q, query)
+	if err != nil {
+		return data
+	}
+	defer rows.Close()
+	found, err := e.scanInserted(rows, data)
+	if err != nil {
+		return data
+	}
+	return found
+}
+
+// scanInserted scans the first row of rows into a new value of the type of
+// data, returning data when there is no row
+func (e *Executor) scanInserted(rows *sql.Rows, data interface{}) (interface{}, error) {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
 		}
+		return data, nil
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	found := reflect.New(reflect.Indirect(reflect.ValueOf(data)).Type())
+	if err := e.scanRowIntoStruct(rows, columns, found.Interface()); err != nil {
+		return nil, err
+	}
+	return found.Interface(), rows.Err()
+}
 
-		// Query back the record
-		if insertedID != nil {
-			where := &sqlgen.WhereClause{
-				Conditions: []sqlgen.Condition{
-					{Field: "id", Operator: "=", Value: insertedID},
-				},
-				Operator: "AND",
-			}
-			var found interface{} = data
-			if err := e.FindFirst(ctx, table, nil, where, nil, nil, &found); err == nil {
-				return found, nil
+// idColumn returns the column of the Id field of data, which generated
+// models tag with its database name
+func (e *Executor) idColumn(data interface{}) string {
+	t := reflect.Indirect(reflect.ValueOf(data)).Type()
+	if t.Kind() == reflect.Struct {
+		for _, name := range []string{"Id", "ID"} {
+			if field, ok := t.FieldByName(name); ok {
+				if column := field.Tag.Get("db"); column != "" && column != "-" {
+					return column
+				}
 			}
 		}
 	}
-
-	return data, nil
+	return "id"
 }
 
 // extractIDFromData extracts ID from data struct
@@ -577,7 +714,10 @@ func (e *Executor) extractIDFromData(data interface{}) interface{} {
 }
 
 // Upsert executes an INSERT ... ON CONFLICT ... DO UPDATE query
-func (e *Executor) Upsert(ctx context.Context, table string, data interface{}, conflictTarget []string, updateColumns []string) (interface{}, error) {
+func (e *Executor) Upsert(ctx context.Context, table string, data interface{}, conflictTarget []string, updateColumns []string) (_ interface{}, err error) {
+	ctx, end := e.startOperation(ctx, table, "upsert", nil, nil)
+	defer end(&err)
+
 	// Invalidate cache for this table
 	e.invalidateTableCache(table)
 
@@ -597,16 +737,20 @@ func (e *Executor) Upsert(ctx context.Context, table string, data interface{}, c
 		}
 	}
 
+	if err := e.checkUpsertScope(ctx, table, columns, values, conflictTarget); err != nil {
+		return nil, err
+	}
+
 	query := e.generator.GenerateUpsert(table, columns, values, updateColumns, conflictTarget)
 
 	// For PostgreSQL, we can use RETURNING
 	if e.provider == "postgresql" || e.provider == "postgres" {
-		row := e.db.QueryRowContext(ctx, query.SQL, query.Args...)
+		row := e.queryRow(ctx, e.db, query)
 		return e.scanRowToStruct(row, data)
 	}
 
 	// For other databases, execute upsert then query back
-	result, err := e.db.ExecContext(ctx, query.SQL, query.Args...)
+	result, err := e.exec(ctx, e.db, query)
 	if err != nil {
 		return nil, fmt.Errorf("upsert failed: %w", err)
 	}
@@ -621,9 +765,8 @@ func (e *Executor) Upsert(ctx context.Context, table string, data interface{}, c
 			},
 			Operator: "AND",
 		}
-		var found interface{} = data
-		if err := e.FindFirst(ctx, table, nil, where, nil, nil, &found); err == nil {
-			return found, nil
+		if err := e.FindFirst(ctx, table, nil, where, nil, nil, data); err == nil {
+			return data, nil
 		}
 	}
 
@@ -631,20 +774,32 @@ func (e *Executor) Upsert(ctx context.Context, table string, data interface{}, c
 }
 
 // Update executes an UPDATE query
-func (e *Executor) Update(ctx context.Context, table string, set map[string]interface{}, where *sqlgen.WhereClause, dest interface{}) error {
+func (e *Executor) Update(ctx context.Context, table string, set map[string]interface{}, where *sqlgen.WhereClause, dest interface{}) (err error) {
+	if ok, err := e.intercept(ctx, table, "update", &Args{Set: set, Where: where}, dest, func(ctx context.Context, args *Args) error {
+		return e.Update(ctx, table, args.Set, args.Where, dest)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "update", where, nil)
+	defer end(&err)
+
 	// Invalidate cache for this table
 	e.invalidateTableCache(table)
 
-	query := e.generator.GenerateUpdate(table, set, where)
+	query, err := rewriteQuery(ctx, e.generator.GenerateUpdate(table, set, e.scopeDeleted(table, where)))
+	if err != nil {
+		return err
+	}
 
 	// For PostgreSQL, we can use RETURNING
 	if e.provider == "postgresql" || e.provider == "postgres" {
-		row := e.db.QueryRowContext(ctx, query.SQL, query.Args...)
+		row := e.queryRow(ctx, e.db, query)
 		return e.scanRow(row, dest)
 	}
 
 	// For other databases, execute update then query back
-	_, err := e.db.ExecContext(ctx, query.SQL, query.Args...)
+	_, err = e.exec(ctx, e.db, query)
 	if err != nil {
 		return fmt.Errorf("update failed: %w", err)
 	}
@@ -659,13 +814,25 @@ func (e *Executor) Update(ctx context.Context, table string, set map[string]inte
 }
 
 // Delete executes a DELETE query
-func (e *Executor) Delete(ctx context.Context, table string, where *sqlgen.WhereClause) error {
+func (e *Executor) Delete(ctx context.Context, table string, where *sqlgen.WhereClause) (err error) {
+	if ok, err := e.intercept(ctx, table, "delete", &Args{Where: where}, nil, func(ctx context.Context, args *Args) error {
+		return e.Delete(ctx, table, args.Where)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "delete", where, nil)
+	defer end(&err)
+
 	// Invalidate cache for this table
 	e.invalidateTableCache(table)
 
-	query := e.generator.GenerateDelete(table, where)
+	query, err := rewriteQuery(ctx, e.deleteQuery(table, where))
+	if err != nil {
+		return err
+	}
 
-	_, err := e.db.ExecContext(ctx, query.SQL, query.Args...)
+	_, err = e.exec(ctx, e.db, query)
 	if err != nil {
 		return fmt.Errorf("delete failed: %w", err)
 	}
@@ -674,7 +841,10 @@ func (e *Executor) Delete(ctx context.Context, table string, where *sqlgen.Where
 }
 
 // CreateMany executes batch INSERT queries
-func (e *Executor) CreateMany(ctx context.Context, table string, data []interface{}) ([]interface{}, error) {
+func (e *Executor) CreateMany(ctx context.Context, table string, data []interface{}) (_ []interface{}, err error) {
+	ctx, end := e.startOperation(ctx, table, "createMany", nil, nil)
+	defer end(&err)
+
 	// Invalidate cache for this table
 	e.invalidateTableCache(table)
 
@@ -695,6 +865,7 @@ func (e *Executor) CreateMany(ctx context.Context, table string, data []interfac
 		// Build multi-row INSERT
 		var parts []string
 		var args []interface{}
+		var argColumns []string
 		argIndex := 1
 
 		parts = append(parts, fmt.Sprintf("INSERT INTO %s", e.quoteIdentifier(table)))
@@ -721,6 +892,7 @@ func (e *Executor) CreateMany(ctx context.Context, table string, data []interfac
 					placeholders[j] = "?"
 				}
 				args = append(args, values[j])
+				argColumns = append(argColumns, columns[j])
 				argIndex++
 			}
 			valueParts[i] = fmt.Sprintf("(%s)", strings.Join(placeholders, ", "))
@@ -729,8 +901,8 @@ func (e *Executor) CreateMany(ctx context.Context, table string, data []interfac
 		parts = append(parts, strings.Join(valueParts, ", "))
 		parts = append(parts, "RETURNING *")
 
-		querySQL := strings.Join(parts, " ")
-		rows, err := e.db.QueryContext(ctx, querySQL, args...)
+		query := &sqlgen.Query{SQL: strings.Join(parts, " "), Args: args, Columns: argColumns}
+		rows, err := e.query(ctx, e.db, query)
 		if err != nil {
 			return nil, fmt.Errorf("batch insert failed: %w", err)
 		}
@@ -765,13 +937,27 @@ func (e *Executor) CreateMany(ctx context.Context, table string, data []interfac
 }
 
 // UpdateMany executes batch UPDATE queries
-func (e *Executor) UpdateMany(ctx context.Context, table string, set map[string]interface{}, where *sqlgen.WhereClause) (int64, error) {
+func (e *Executor) UpdateMany(ctx context.Context, table string, set map[string]interface{}, where *sqlgen.WhereClause) (_ int64, err error) {
+	var count int64
+	if ok, err := e.intercept(ctx, table, "updateMany", &Args{Set: set, Where: where}, &count, func(ctx context.Context, args *Args) (err error) {
+		count, err = e.UpdateMany(ctx, table, args.Set, args.Where)
+		return err
+	}); ok {
+		return count, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "updateMany", where, nil)
+	defer end(&err)
+
 	// Invalidate cache for this table
 	e.invalidateTableCache(table)
 
-	query := e.generator.GenerateUpdate(table, set, where)
+	query, err := rewriteQuery(ctx, e.generator.GenerateUpdate(table, set, e.scopeDeleted(table, where)))
+	if err != nil {
+		return 0, err
+	}
 
-	result, err := e.db.ExecContext(ctx, query.SQL, query.Args...)
+	result, err := e.exec(ctx, e.db, query)
 	if err != nil {
 		return 0, fmt.Errorf("batch update failed: %w", err)
 	}
@@ -785,13 +971,27 @@ func (e *Executor) UpdateMany(ctx context.Context, table string, set map[string]
 }
 
 // DeleteMany executes batch DELETE queries
-func (e *Executor) DeleteMany(ctx context.Context, table string, where *sqlgen.WhereClause) (int64, error) {
+func (e *Executor) DeleteMany(ctx context.Context, table string, where *sqlgen.WhereClause) (_ int64, err error) {
+	var count int64
+	if ok, err := e.intercept(ctx, table, "deleteMany", &Args{Where: where}, &count, func(ctx context.Context, args *Args) (err error) {
+		count, err = e.DeleteMany(ctx, table, args.Where)
+		return err
+	}); ok {
+		return count, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "deleteMany", where, nil)
+	defer end(&err)
+
 	// Invalidate cache for this table
 	e.invalidateTableCache(table)
 
-	query := e.generator.GenerateDelete(table, where)
+	query, err := rewriteQuery(ctx, e.deleteQuery(table, where))
+	if err != nil {
+		return 0, err
+	}
 
-	result, err := e.db.ExecContext(ctx, query.SQL, query.Args...)
+	result, err := e.exec(ctx, e.db, query)
 	if err != nil {
 		return 0, fmt.Errorf("batch delete failed: %w", err)
 	}
@@ -831,7 +1031,8 @@ func (e *Executor) scanRows(rows *sql.Rows, dest interface{}) error {
 	}
 
 	elementType := sliceValue.Type().Elem()
-	if elementType.Kind() == reflect.Ptr {
+	pointers := elementType.Kind() == reflect.Ptr
+	if pointers {
 		elementType = elementType.Elem()
 	}
 
@@ -845,7 +1046,11 @@ func (e *Executor) scanRows(rows *sql.Rows, dest interface{}) error {
 		if err := e.scanRowIntoStruct(rows, columns, element); err != nil {
 			return fmt.Errorf("failed to scan row into struct (columns: %v): %w", columns, err)
 		}
-		sliceValue = reflect.Append(sliceValue, reflect.ValueOf(element))
+		if pointers {
+			sliceValue = reflect.Append(sliceValue, reflect.ValueOf(element))
+		} else {
+			sliceValue = reflect.Append(sliceValue, reflect.ValueOf(element).Elem())
+		}
 	}
 
 	destValue.Elem().Set(sliceValue)
@@ -853,7 +1058,7 @@ func (e *Executor) scanRows(rows *sql.Rows, dest interface{}) error {
 }
 
 // scanRow scans a single row into a struct
-func (e *Executor) scanRow(row *sql.Row, dest interface{}) error {
+func (e *Executor) scanRow(row rowScanner, dest interface{}) error {
 	// Get columns from the struct type
 	columns := e.getStructColumns(dest)
 	values := make([]interface{}, len(columns))
@@ -873,7 +1078,7 @@ func (e *Executor) scanRow(row *sql.Row, dest interface{}) error {
 }
 
 // scanRowToStruct scans a row into a struct (for RETURNING)
-func (e *Executor) scanRowToStruct(row *sql.Row, dest interface{}) (interface{}, error) {
+func (e *Executor) scanRowToStruct(row rowScanner, dest interface{}) (interface{}, error) {
 	columns := e.getStructColumns(dest)
 	values := make([]interface{}, len(columns))
 	valuePtrs := make([]interface{}, len(columns))
@@ -917,6 +1122,9 @@ func (e *Executor) getStructColumns(dest interface{}) []string {
 	var columns []string
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
+		if !holdsColumn(field) {
+			continue
+		}
 		dbTag := field.Tag.Get("db")
 		if dbTag != "" && dbTag != "-" {
 			columns = append(columns, dbTag)
@@ -946,13 +1154,13 @@ func (e *Executor) mapValuesToStruct(columns []string, values []interface{}, des
 		field := t.Field(i)
 		fieldValue := v.Field(i)
 
-		if !fieldValue.CanSet() {
+		if !fieldValue.CanSet() || field.Tag.Get("db") == "-" {
 			continue
 		}
 
 		// Get column name from tag or field name
 		columnName := field.Tag.Get("db")
-		if columnName == "" || columnName == "-" {
+		if columnName == "" {
 			columnName = e.toSnakeCase(field.Name)
 		}
 
@@ -973,7 +1181,7 @@ func (e *Executor) mapValuesToStruct(columns []string, values []interface{}, des
 			continue
 		}
 
-		if err := e.setFieldValue(fieldValue, value); err != nil {
+		if err := e.setColumnValue(field, fieldValue, value); err != nil {
 			return fmt.Errorf("failed to set field %s: %w", field.Name, err)
 		}
 	}
@@ -981,6 +1189,19 @@ func (e *Executor) mapValuesToStruct(columns []string, values []interface{}, des
 	return nil
 }
 
+// setColumnValue sets a struct field from a column value, decoding Json
+// fields and applying the scale of Decimal fields
+func (e *Executor) setColumnValue(field reflect.StructField, fieldValue reflect.Value, value interface{}) error {
+	if isJsonField(field) {
+		return decodeJson(fieldValue, value)
+	}
+	if err := e.setFieldValue(fieldValue, value); err != nil {
+		return err
+	}
+	rescaleDecimal(field, fieldValue)
+	return nil
+}
+
 // setFieldValue sets a struct field value from a database value
 func (e *Executor) setFieldValue(fieldValue reflect.Value, value interface{}) error {
 	fieldType := fieldValue.Type()
@@ -1023,6 +1244,13 @@ func (e *Executor) setFieldValue(fieldValue reflect.Value, value interface{}) er
 		return nil
 	}
 
+	// Types such as types.Decimal convert driver values themselves
+	if fieldValue.CanAddr() {
+		if scanner, ok := fieldValue.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(value)
+		}
+	}
+
 	// Special handling for SQLite boolean conversion (int64 -> bool)
 	// SQLite stores booleans as INTEGER (0 or 1), but Go expects bool
 	if fieldType.Kind() == reflect.Bool {
@@ -1127,6 +1355,18 @@ func (e *Executor) setFieldValue(fieldValue reflect.Value, value interface{}) er
 	return fmt.Errorf("cannot convert %s to %s", valueType, fieldType)
 }
 
+// nowValue returns the current time as a value of t, a time.Time or a
+// pointer to one
+func nowValue(t reflect.Type) reflect.Value {
+	now := reflect.ValueOf(time.Now().UTC())
+	if t.Kind() != reflect.Ptr {
+		return now.Convert(t)
+	}
+	value := reflect.New(t.Elem())
+	value.Elem().Set(now.Convert(t.Elem()))
+	return value
+}
+
 // extractInsertData extracts columns and values from a struct
 func (e *Executor) extractInsertData(data interface{}) ([]string, []interface{}, error) {
 	v := reflect.ValueOf(data)
@@ -1146,8 +1386,8 @@ func (e *Executor) extractInsertData(data interface{}) ([]string, []interface{},
 		field := t.Field(i)
 		fieldValue := v.Field(i)
 
-		// Skip unexported fields
-		if !fieldValue.CanInterface() {
+		// Skip unexported fields, computed fields and related records
+		if !fieldValue.CanInterface() || !holdsColumn(field) {
 			continue
 		}
 
@@ -1157,18 +1397,59 @@ func (e *Executor) extractInsertData(data interface{}) ([]string, []interface{},
 			columnName = e.toSnakeCase(field.Name)
 		}
 
+		// Fill in @default(now()) as Prisma does, rather than relying on
+		// a database default the table may not have
+		if fieldValue.IsZero() && hasPrismaOption(field, "now") {
+			fieldValue = nowValue(fieldValue.Type())
+		}
+
 		// Skip zero values for optional fields (can be improved)
 		if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
 			continue
 		}
 
+		// Let the database fill in defaults like autoincrement() and now()
+		if fieldValue.IsZero() && hasPrismaOption(field, "default") {
+			continue
+		}
+
+		value, err := fitDecimal(field, fieldValue.Interface())
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err = encodeJson(field, value)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		columns = append(columns, columnName)
-		values = append(values, fieldValue.Interface())
+		values = append(values, value)
 	}
 
 	return columns, values, nil
 }
 
+// holdsRelation reports whether field holds related models rather than a
+// column. Generated models are recognised by their TableName method.
+func holdsRelation(field reflect.StructField) bool {
+	t := field.Type
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	return t.Implements(tableNamerType) || reflect.PointerTo(t).Implements(tableNamerType)
+}
+
+// holdsColumn reports whether field holds a column, unlike fields tagged
+// db:"-", e.g. computed fields, and fields holding related records
+func holdsColumn(field reflect.StructField) bool {
+	return field.Tag.Get("db") != "-" && !holdsRelation(field)
+}
+
+var tableNamerType = reflect.TypeOf((*interface{ TableName() string })(nil)).Elem()
+
 // toSnakeCase converts PascalCase to snake_case
 func (e *Executor) toSnakeCase(s string) string {
 	var result strings.Builder
@@ -131,7 +131,6 @@ func (e *Executor) scanJoinResultsSimple(
 
 	if isSlice {
 		sliceValue = destValue
-		destValue = reflect.New(reflect.TypeOf(nil)) // Will be set per row
 	}
 
 	for rows.Next() {
@@ -511,13 +510,13 @@ func (e *Executor) mapValuesToStructFromMap(columnMap map[string]int, values []i
 		field := t.Field(i)
 		fieldValue := v.Field(i)
 
-		if !fieldValue.CanSet() {
+		if !fieldValue.CanSet() || field.Tag.Get("db") == "-" {
 			continue
 		}
 
 		// Get column name from tag or field name
 		columnName := field.Tag.Get("db")
-		if columnName == "" || columnName == "-" {
+		if columnName == "" {
 			columnName = e.toSnakeCase(field.Name)
 		}
 
@@ -538,7 +537,7 @@ func (e *Executor) mapValuesToStructFromMap(columnMap map[string]int, values []i
 			continue
 		}
 
-		if err := e.setFieldValue(fieldValue, value); err != nil {
+		if err := e.setColumnValue(field, fieldValue, value); err != nil {
 			return fmt.Errorf("failed to set field %s: %w", field.Name, err)
 		}
 	}
@@ -0,0 +1,45 @@
+// Package executor provides encoding of Json fields.
+package executor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/runtime/types"
+)
+
+// isJsonField reports whether field is stored as JSON. The generator marks
+// Json fields with a `prisma:"json"` struct tag.
+func isJsonField(field reflect.StructField) bool {
+	return hasPrismaOption(field, "json")
+}
+
+// hasPrismaOption reports whether the prisma struct tag of field lists
+// option
+func hasPrismaOption(field reflect.StructField, option string) bool {
+	for _, o := range strings.Split(field.Tag.Get("prisma"), ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeJson encodes the value of a Json field as JSON text
+func encodeJson(field reflect.StructField, value interface{}) (interface{}, error) {
+	if !isJsonField(field) {
+		return value, nil
+	}
+	encoded, err := types.JsonValue{V: value}.Value()
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	return encoded, nil
+}
+
+// decodeJson decodes JSON text from the database into a Json field
+func decodeJson(fieldValue reflect.Value, value interface{}) error {
+	target := types.JsonValue{V: fieldValue.Addr().Interface()}
+	return target.Scan(value)
+}
@@ -0,0 +1,215 @@
+// Package executor provides the middleware pipeline of operations.
+package executor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+)
+
+// Args are the arguments of an operation, before its SQL is generated.
+// Middleware may change them, e.g. to add a tenant condition to Where.
+type Args struct {
+	Select  map[string]bool // Selected columns, all when empty
+	Where   *sqlgen.WhereClause
+	OrderBy []sqlgen.OrderBy
+	Limit   *int
+	Offset  *int
+	Include map[string]bool // Included relations
+	Joins   []sqlgen.Join   // Explicit joins of findMany and findFirst
+
+	Data  interface{}            // Record of create
+	Set   map[string]interface{} // Columns set by update and updateMany
+	Field string                 // Aggregated column of sum, avg, min and max
+}
+
+// Call is an operation of the generated client passing through the
+// middleware pipeline: findMany, findFirst, create, update, delete,
+// updateMany, deleteMany, count and aggregate.
+type Call struct {
+	Model string // Model of Table, or Table when the model is not known
+	Table string
+	Name  string // Prisma name of the operation: findMany, create, ...
+	Args  *Args
+
+	// Result points to the result of the operation, set once next returned:
+	// the destination slice or struct of finds and updates, an *interface{}
+	// holding the created record, an *int64 for count, updateMany and
+	// deleteMany, an *float64 or *types.Decimal for aggregates. It is nil
+	// for delete.
+	Result interface{}
+
+	rewrites  []func(query *sqlgen.Query) (*sqlgen.Query, error)
+	rewritten bool
+}
+
+// Next runs the rest of the pipeline and then the operation itself, with
+// the arguments of the call
+type Next func(ctx context.Context) error
+
+// Middleware intercepts the operations of the generated client. It may
+// change call.Args before calling next, rewrite the generated SQL with
+// call.RewriteQuery, return a result without calling next with
+// call.SetResult, e.g. from a cache, or transform call.Result after next
+// returned.
+type Middleware func(ctx context.Context, call *Call, next Next) error
+
+// RewriteQuery registers a rewrite of the SQL generated for the operation,
+// run before the SQL runs. Rewrites run in the order they were registered.
+// Statements an operation runs afterwards, e.g. to query back an updated
+// record, are not rewritten.
+func (c *Call) RewriteQuery(rewrite func(query *sqlgen.Query) (*sqlgen.Query, error)) {
+	c.rewrites = append(c.rewrites, rewrite)
+}
+
+// SetResult sets the result of the call to value, or to the value it
+// points to: a []User or *[]User for findMany on User, a *User for create
+func (c *Call) SetResult(value interface{}) error {
+	dest := reflect.ValueOf(c.Result)
+	if dest.Kind() != reflect.Ptr || dest.IsNil() {
+		return fmt.Errorf("%s.%s has no result", c.Model, c.Name)
+	}
+	target := dest.Elem()
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		target.Set(reflect.Zero(target.Type()))
+	case v.Type().AssignableTo(target.Type()):
+		target.Set(v)
+	case v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Type().AssignableTo(target.Type()):
+		target.Set(v.Elem())
+	default:
+		return fmt.Errorf("cannot set the result of %s.%s, a %s, to %T", c.Model, c.Name, target.Type(), value)
+	}
+	return nil
+}
+
+// ResultOf returns the result of call as a *T, e.g. a *[]User for findMany
+// on User or the *User created by create
+func ResultOf[T any](call *Call) (*T, bool) {
+	switch result := call.Result.(type) {
+	case *T:
+		return result, true
+	case *interface{}:
+		record, ok := (*result).(*T)
+		return record, ok && record != nil
+	}
+	return nil, false
+}
+
+// Pipeline runs the middleware of operations, in the order it was added,
+// each one wrapping the next ones. A nil *Pipeline runs operations
+// directly.
+type Pipeline struct {
+	mu     sync.RWMutex
+	list   []Middleware
+	parent *Pipeline
+}
+
+// Derive returns a pipeline running the middleware of p, including the
+// middleware added to p later, and then its own. Middleware added to the
+// derived pipeline does not change p.
+func (p *Pipeline) Derive() *Pipeline {
+	return &Pipeline{parent: p}
+}
+
+// Use adds a middleware
+func (p *Pipeline) Use(middleware Middleware) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.list = append(p.list, middleware)
+}
+
+// middleware returns a snapshot of the middleware of the pipeline
+func (p *Pipeline) middleware() []Middleware {
+	if p == nil {
+		return nil
+	}
+	inherited := p.parent.middleware()
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(inherited) == 0 {
+		return p.list
+	}
+	return append(inherited[:len(inherited):len(inherited)], p.list...)
+}
+
+// Derive returns an executor sharing the connection, cache, error mapper,
+// observers, model names and soft-deleting tables of e, running its
+// operations through pipeline
+func (e *Executor) Derive(pipeline *Pipeline) *Executor {
+	e.cacheMu.RLock()
+	queryCache := e.queryCache
+	e.cacheMu.RUnlock()
+
+	derived := NewExecutor(e.db, e.provider)
+	derived.SetCache(queryCache)
+	derived.errorMapper = e.errorMapper
+	derived.observers = e.observers
+	derived.models = e.models
+	derived.softDelete = e.softDelete
+	derived.deleted = e.deleted
+	derived.pipeline = pipeline
+	return derived
+}
+
+// SetPipeline sets the middleware pipeline of the operations of the
+// executor. Generated clients share the pipeline of their PrismaClient.
+func (e *Executor) SetPipeline(pipeline *Pipeline) {
+	e.pipeline = pipeline
+}
+
+// callKey is the context key of the call running its operation
+type callKey struct{}
+
+// intercept runs the operation name on table through the pipeline, with
+// run as the operation itself, called with the arguments of the call. It
+// reports false without running anything when there is no middleware or
+// ctx is already in a call, e.g. to query back a record, so that the
+// operation runs directly.
+func (e *Executor) intercept(ctx context.Context, table, name string, args *Args, result interface{}, run func(ctx context.Context, args *Args) error) (bool, error) {
+	middleware := e.pipeline.middleware()
+	if len(middleware) == 0 || ctx.Value(callKey{}) != nil {
+		return false, nil
+	}
+	model := e.models[table]
+	if model == "" {
+		model = table
+	}
+	call := &Call{Model: model, Table: table, Name: name, Args: args, Result: result}
+
+	var next Next
+	index := 0
+	next = func(ctx context.Context) error {
+		if index >= len(middleware) {
+			return run(context.WithValue(ctx, callKey{}, call), call.Args)
+		}
+		m := middleware[index]
+		index++
+		return m(ctx, call, next)
+	}
+	return true, next(ctx)
+}
+
+// rewriteQuery applies the rewrites of the call of ctx to the first query
+// generated for its operation
+func rewriteQuery(ctx context.Context, query *sqlgen.Query) (*sqlgen.Query, error) {
+	call, ok := ctx.Value(callKey{}).(*Call)
+	if !ok || call.rewritten {
+		return query, nil
+	}
+	call.rewritten = true
+	for _, rewrite := range call.rewrites {
+		rewritten, err := rewrite(query)
+		if err != nil {
+			return nil, err
+		}
+		if rewritten != nil {
+			query = rewritten
+		}
+	}
+	return query, nil
+}
@@ -122,7 +122,7 @@ func (e *Executor) executeNestedCreate(ctx context.Context, tx *sql.Tx, parentTa
 		query := e.generator.GenerateInsert(relMeta.RelatedTable, columns, values)
 
 		// Execute INSERT
-		_, err = tx.ExecContext(ctx, query.SQL, query.Args...)
+		_, err = e.exec(ctx, tx, query)
 		if err != nil {
 			return fmt.Errorf("failed to insert related record: %w", err)
 		}
@@ -167,7 +167,7 @@ func (e *Executor) executeNestedCreateManyToMany(ctx context.Context, tx *sql.Tx
 		values := []interface{}{parentID, relatedID}
 
 		query := e.generator.GenerateInsert(relMeta.JunctionTable, columns, values)
-		_, err := tx.ExecContext(ctx, query.SQL, query.Args...)
+		_, err := e.exec(ctx, tx, query)
 		if err != nil {
 			// Ignore duplicate key errors (record already exists)
 			if !strings.Contains(err.Error(), "duplicate") && !strings.Contains(err.Error(), "UNIQUE") {
@@ -228,7 +228,7 @@ func (e *Executor) executeNestedUpdate(ctx context.Context, tx *sql.Tx, parentTa
 	query := e.generator.GenerateUpdate(relMeta.RelatedTable, set, where)
 
 	// Execute UPDATE
-	_, err := tx.ExecContext(ctx, query.SQL, query.Args...)
+	_, err := e.exec(ctx, tx, query)
 	if err != nil {
 		return fmt.Errorf("failed to update related records: %w", err)
 	}
@@ -288,10 +288,10 @@ func (e *Executor) executeNestedDelete(ctx context.Context, tx *sql.Tx, parentTa
 	}
 
 	// Generate DELETE query
-	query := e.generator.GenerateDelete(relMeta.RelatedTable, where)
+	query := e.deleteQuery(relMeta.RelatedTable, where)
 
 	// Execute DELETE
-	_, err := tx.ExecContext(ctx, query.SQL, query.Args...)
+	_, err := e.exec(ctx, tx, query)
 	if err != nil {
 		return fmt.Errorf("failed to delete related records: %w", err)
 	}
@@ -333,7 +333,7 @@ func (e *Executor) executeNestedDeleteManyToMany(ctx context.Context, tx *sql.Tx
 	}
 
 	query := e.generator.GenerateDelete(relMeta.JunctionTable, where)
-	_, err := tx.ExecContext(ctx, query.SQL, query.Args...)
+	_, err := e.exec(ctx, tx, query)
 	return err
 }
 
@@ -387,7 +387,7 @@ func (e *Executor) executeNestedConnect(ctx context.Context, tx *sql.Tx, parentT
 		}
 
 		query := e.generator.GenerateUpdate(relMeta.RelatedTable, set, where)
-		_, err := tx.ExecContext(ctx, query.SQL, query.Args...)
+		_, err := e.exec(ctx, tx, query)
 		if err != nil {
 			return fmt.Errorf("failed to connect record: %w", err)
 		}
@@ -472,7 +472,7 @@ func (e *Executor) executeNestedDisconnect(ctx context.Context, tx *sql.Tx, pare
 	}
 
 	query := e.generator.GenerateUpdate(relMeta.RelatedTable, set, where)
-	_, err := tx.ExecContext(ctx, query.SQL, query.Args...)
+	_, err := e.exec(ctx, tx, query)
 	if err != nil {
 		return fmt.Errorf("failed to disconnect records: %w", err)
 	}
@@ -527,7 +527,7 @@ func (e *Executor) executeNestedUpsert(ctx context.Context, tx *sql.Tx, parentTa
 	// Check if record exists
 	checkQuery := e.generator.GenerateSelect(relMeta.RelatedTable, []string{"id"}, where, nil, nil, nil)
 	var existingID interface{}
-	err := tx.QueryRowContext(ctx, checkQuery.SQL, checkQuery.Args...).Scan(&existingID)
+	err := e.queryRow(ctx, tx, checkQuery).Scan(&existingID)
 
 	if err == sql.ErrNoRows {
 		// Record doesn't exist, create it
@@ -551,7 +551,7 @@ func (e *Executor) executeNestedUpsert(ctx context.Context, tx *sql.Tx, parentTa
 		})
 
 		query := e.generator.GenerateUpdate(relMeta.RelatedTable, updateData, updateWhere)
-		_, err = tx.ExecContext(ctx, query.SQL, query.Args...)
+		_, err = e.exec(ctx, tx, query)
 		if err != nil {
 			return fmt.Errorf("failed to update record: %w", err)
 		}
@@ -0,0 +1,286 @@
+// Package executor provides observation of operations and statements.
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+)
+
+// Operation is a call of a client method, e.g. findMany on the User model
+type Operation struct {
+	Model string // Model of Table, or Table when the model is not known
+	Table string
+	Name  string // Prisma name of the method: findMany, create, updateMany, ...
+
+	// Where and OrderBy are the filter and order of the operation, if any
+	Where   *sqlgen.WhereClause
+	OrderBy []sqlgen.OrderBy
+}
+
+// Statement is a SQL statement run by the executor
+type Statement struct {
+	System    string     // Database provider, e.g. postgresql
+	Operation *Operation // Operation running the statement, nil outside of operations
+	Verb      string     // First keyword of SQL, e.g. SELECT
+	SQL       string
+	Args      []interface{}
+
+	// Columns are the columns Args are bound to, by position, "" for an
+	// argument bound to none; nil when they are not known, e.g. for raw
+	// queries
+	Columns []string
+
+	// RowsAffected is set before an INSERT, UPDATE or DELETE without
+	// RETURNING ends, -1 for other statements and when the driver does not
+	// report it
+	RowsAffected int64
+}
+
+// NewStatement returns the statement running query with args on system
+func NewStatement(system, query string, args []interface{}) *Statement {
+	return &Statement{System: system, Verb: sqlVerb(query), SQL: query, Args: args, RowsAffected: -1}
+}
+
+// Observer is notified of the operations of an executor and of the SQL
+// statements they run, e.g. to trace or time them. The context returned by
+// a Start method is the one the operation or statement runs with, so that
+// statements are children of their operation in traces; the function
+// returned, if not nil, is called when it ends, with its error.
+type Observer interface {
+	StartOperation(ctx context.Context, op *Operation) (context.Context, func(err error))
+	StartStatement(ctx context.Context, stmt *Statement) (context.Context, func(err error))
+}
+
+// StatementGuard is implemented by observers that may keep a statement
+// from running. The error of CheckStatement fails the statement, and the
+// operation running it, before the observers are notified of its start.
+type StatementGuard interface {
+	CheckStatement(ctx context.Context, stmt *Statement) error
+}
+
+// TransactionObserver is implemented by observers that are also notified
+// of transactions
+type TransactionObserver interface {
+	StartTransaction(ctx context.Context, system string) (context.Context, func(err error))
+}
+
+// Observers notifies a set of observers, in the order they were added, and
+// ends them in reverse order. A nil *Observers notifies nobody.
+type Observers struct {
+	mu   sync.RWMutex
+	list []Observer
+}
+
+// Add adds an observer
+func (o *Observers) Add(observer Observer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.list = append(o.list, observer)
+}
+
+// observers returns the observers added so far
+func (o *Observers) observers() []Observer {
+	if o == nil {
+		return nil
+	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.list
+}
+
+// StartOperation notifies the observers of the start of op
+func (o *Observers) StartOperation(ctx context.Context, op *Operation) (context.Context, func(err error)) {
+	return start(ctx, o.observers(), func(ctx context.Context, observer Observer) (context.Context, func(error)) {
+		return observer.StartOperation(ctx, op)
+	})
+}
+
+// CheckStatement returns the error of the first observer implementing
+// StatementGuard that keeps stmt from running, if any
+func (o *Observers) CheckStatement(ctx context.Context, stmt *Statement) error {
+	for _, observer := range o.observers() {
+		if guard, ok := observer.(StatementGuard); ok {
+			if err := guard.CheckStatement(ctx, stmt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// StartStatement notifies the observers of the start of stmt
+func (o *Observers) StartStatement(ctx context.Context, stmt *Statement) (context.Context, func(err error)) {
+	return start(ctx, o.observers(), func(ctx context.Context, observer Observer) (context.Context, func(error)) {
+		return observer.StartStatement(ctx, stmt)
+	})
+}
+
+// StartTransaction notifies the observers implementing TransactionObserver
+// of the start of a transaction
+func (o *Observers) StartTransaction(ctx context.Context, system string) (context.Context, func(err error)) {
+	return start(ctx, o.observers(), func(ctx context.Context, observer Observer) (context.Context, func(error)) {
+		if tx, ok := observer.(TransactionObserver); ok {
+			return tx.StartTransaction(ctx, system)
+		}
+		return ctx, nil
+	})
+}
+
+// start starts each observer with the context of the previous one, and
+// returns the function ending them
+func start(ctx context.Context, observers []Observer, startOne func(context.Context, Observer) (context.Context, func(error))) (context.Context, func(err error)) {
+	if len(observers) == 0 {
+		return ctx, func(error) {}
+	}
+	ends := make([]func(error), 0, len(observers))
+	for _, observer := range observers {
+		var end func(error)
+		ctx, end = startOne(ctx, observer)
+		if end != nil {
+			ends = append(ends, end)
+		}
+	}
+	return ctx, func(err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](err)
+		}
+	}
+}
+
+// SetObservers sets the observers of the operations and statements of the
+// executor. Generated clients share the observers of their PrismaClient.
+func (e *Executor) SetObservers(observers *Observers) {
+	e.observers = observers
+}
+
+// SetModelNames sets the names of the models of tables, to name them in
+// operations
+func (e *Executor) SetModelNames(models map[string]string) {
+	e.models = models
+}
+
+// operationKey is the context key of the running operation
+type operationKey struct{}
+
+// OperationFromContext returns the operation running with ctx, if any
+func OperationFromContext(ctx context.Context) (*Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(*Operation)
+	return op, ok
+}
+
+// startOperation starts the operation name on table. The function returned
+// maps the error of the operation with the error mapper and ends it; defer
+// it with the address of the error result. Operations started within
+// another, e.g. to query back a record, belong to the outer one.
+func (e *Executor) startOperation(ctx context.Context, table, name string, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy) (context.Context, func(err *error)) {
+	if _, ok := OperationFromContext(ctx); ok {
+		return ctx, func(err *error) { e.mapError(table, err) }
+	}
+	model := e.models[table]
+	if model == "" {
+		model = table
+	}
+	op := &Operation{Model: model, Table: table, Name: name, Where: where, OrderBy: orderBy}
+	ctx = context.WithValue(ctx, operationKey{}, op)
+	ctx, end := e.observers.StartOperation(ctx, op)
+	return ctx, func(err *error) {
+		e.mapError(table, err)
+		end(*err)
+	}
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// startStatement notifies the observers of the start of query, unless a
+// StatementGuard keeps it from running
+func (e *Executor) startStatement(ctx context.Context, query *sqlgen.Query) (context.Context, *Statement, func(err error), error) {
+	stmt := NewStatement(e.provider, query.SQL, query.Args)
+	stmt.Operation, _ = OperationFromContext(ctx)
+	if len(query.Columns) == len(query.Args) {
+		stmt.Columns = query.Columns
+	}
+	if err := e.observers.CheckStatement(ctx, stmt); err != nil {
+		return ctx, stmt, nil, err
+	}
+	ctx, end := e.observers.StartStatement(ctx, stmt)
+	return ctx, stmt, end, nil
+}
+
+// query runs a query returning rows on q
+func (e *Executor) query(ctx context.Context, q queryer, query *sqlgen.Query) (*sql.Rows, error) {
+	if e.observers == nil {
+		return q.QueryContext(ctx, query.SQL, query.Args...)
+	}
+	ctx, _, end, err := e.startStatement(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.QueryContext(ctx, query.SQL, query.Args...)
+	end(err)
+	return rows, err
+}
+
+// rowScanner is the row of queryRow
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// failedRow is the row of a statement kept from running
+type failedRow struct {
+	err error
+}
+
+func (r failedRow) Scan(dest ...interface{}) error {
+	return r.err
+}
+
+// queryRow runs a query returning at most one row on q
+func (e *Executor) queryRow(ctx context.Context, q queryer, query *sqlgen.Query) rowScanner {
+	if e.observers == nil {
+		return q.QueryRowContext(ctx, query.SQL, query.Args...)
+	}
+	ctx, _, end, err := e.startStatement(ctx, query)
+	if err != nil {
+		return failedRow{err: err}
+	}
+	row := q.QueryRowContext(ctx, query.SQL, query.Args...)
+	end(row.Err())
+	return row
+}
+
+// exec runs a statement without rows on q
+func (e *Executor) exec(ctx context.Context, q queryer, query *sqlgen.Query) (sql.Result, error) {
+	if e.observers == nil {
+		return q.ExecContext(ctx, query.SQL, query.Args...)
+	}
+	ctx, stmt, end, err := e.startStatement(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	result, err := q.ExecContext(ctx, query.SQL, query.Args...)
+	if err == nil {
+		if n, err := result.RowsAffected(); err == nil {
+			stmt.RowsAffected = n
+		}
+	}
+	end(err)
+	return result, err
+}
+
+// sqlVerb returns the first keyword of query in upper case, e.g. SELECT
+func sqlVerb(query string) string {
+	query = strings.TrimSpace(query)
+	if i := strings.IndexAny(query, " \t\r\n("); i >= 0 {
+		query = query[:i]
+	}
+	return strings.ToUpper(query)
+}
@@ -0,0 +1,166 @@
+// Package executor provides soft deletes of records.
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+)
+
+// deletedScope selects the records of soft-deleting tables that reads see
+type deletedScope int
+
+const (
+	withoutDeleted deletedScope = iota // Records not deleted, the default
+	withDeleted                        // All records
+	onlyDeleted                        // Deleted records
+)
+
+// SetSoftDelete sets the tables whose records are soft-deleted, with the
+// timestamp column marking deleted records. Deletes set the column instead
+// of removing records, and reads and writes skip records where it is set,
+// including the related records of included relations.
+func (e *Executor) SetSoftDelete(columns map[string]string) {
+	e.softDelete = columns
+}
+
+// WithDeleted returns an executor whose reads and writes also see
+// soft-deleted records
+func (e *Executor) WithDeleted() *Executor {
+	return e.scoped(withDeleted)
+}
+
+// OnlyDeleted returns an executor whose reads and writes only see
+// soft-deleted records. Included relations still skip their soft-deleted
+// records.
+func (e *Executor) OnlyDeleted() *Executor {
+	return e.scoped(onlyDeleted)
+}
+
+// scoped returns an executor like e whose reads and writes see the records
+// of scope
+func (e *Executor) scoped(scope deletedScope) *Executor {
+	derived := e.Derive(e.pipeline)
+	derived.deleted = scope
+	return derived
+}
+
+// Restore restores the soft-deleted records of table matching where, all
+// of them when where is nil, and returns their number. It runs as an
+// updateMany operation clearing the timestamp column.
+func (e *Executor) Restore(ctx context.Context, table string, where *sqlgen.WhereClause) (int64, error) {
+	column, ok := e.softDelete[table]
+	if !ok {
+		return 0, fmt.Errorf("records of %s are not soft-deleted", table)
+	}
+	// The update must see the deleted records, whatever the scope of e
+	return e.scoped(withDeleted).UpdateMany(ctx, table, map[string]interface{}{column: nil}, scopeWhere(table, column, "IS NOT NULL", where))
+}
+
+// checkUpsertScope returns an error when the record an upsert of columns
+// conflicts with on conflictTarget exists but is out of the scope of e: the
+// upsert could neither update it nor insert its values.
+func (e *Executor) checkUpsertScope(ctx context.Context, table string, columns []string, values []interface{}, conflictTarget []string) error {
+	column, ok := e.softDelete[table]
+	if !ok || e.deleted == withDeleted || len(conflictTarget) == 0 {
+		return nil
+	}
+	where := sqlgen.NewWhereClause()
+	for _, target := range conflictTarget {
+		i := indexOf(columns, target)
+		if i < 0 {
+			// The conflict target is not set, so nothing conflicts
+			return nil
+		}
+		where.AddCondition(sqlgen.Condition{Field: target, Operator: "=", Value: values[i]})
+	}
+
+	// The records out of scope, deleted ones unless e only sees those
+	operator, state := "IS NOT NULL", "soft-deleted"
+	if e.deleted == onlyDeleted {
+		operator, state = "IS NULL", "not soft-deleted"
+	}
+	limit := 1
+	query := e.generator.GenerateSelect(table, []string{column}, scopeWhere(table, column, operator, where), nil, &limit, nil)
+	var deletedAt interface{}
+	switch err := e.queryRow(ctx, e.db, query).Scan(&deletedAt); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("upsert failed: %w", err)
+	}
+	return fmt.Errorf("upsert failed: the conflicting record of %s is %s", table, state)
+}
+
+// indexOf returns the index of s in list, -1 when list does not hold it
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// scopeDeleted returns where restricted to the records of table the reads
+// of e see
+func (e *Executor) scopeDeleted(table string, where *sqlgen.WhereClause) *sqlgen.WhereClause {
+	column, ok := e.softDelete[table]
+	if !ok {
+		return where
+	}
+	switch e.deleted {
+	case withDeleted:
+		return where
+	case onlyDeleted:
+		return scopeWhere(table, column, "IS NOT NULL", where)
+	}
+	return scopeWhere(table, column, "IS NULL", where)
+}
+
+// scopeJoins returns joins with the soft-deleted records of their tables
+// left out, unless the reads of e see them
+func (e *Executor) scopeJoins(joins []sqlgen.Join) []sqlgen.Join {
+	if len(e.softDelete) == 0 || e.deleted == withDeleted {
+		return joins
+	}
+	scoped := make([]sqlgen.Join, len(joins))
+	for i, join := range joins {
+		if column, ok := e.softDelete[join.Table]; ok {
+			alias := join.Alias
+			if alias == "" {
+				alias = join.Table
+			}
+			join.Condition = fmt.Sprintf("%s AND %s.%s IS NULL", join.Condition, quoteIdentifier(alias), quoteIdentifier(column))
+		}
+		scoped[i] = join
+	}
+	return scoped
+}
+
+// deleteQuery returns the query deleting the records of table matching
+// where: an UPDATE setting the timestamp of the records not deleted yet
+// when table is soft-deleting
+func (e *Executor) deleteQuery(table string, where *sqlgen.WhereClause) *sqlgen.Query {
+	column, ok := e.softDelete[table]
+	if !ok {
+		return e.generator.GenerateDelete(table, where)
+	}
+	set := map[string]interface{}{column: time.Now().UTC()}
+	return e.generator.GenerateUpdate(table, set, scopeWhere(table, column, "IS NULL", where))
+}
+
+// scopeWhere returns where restricted to the records of table whose column
+// matches operator, IS NULL or IS NOT NULL
+func scopeWhere(table, column, operator string, where *sqlgen.WhereClause) *sqlgen.WhereClause {
+	scoped := sqlgen.NewWhereClause()
+	scoped.AddCondition(sqlgen.Condition{Table: table, Field: column, Operator: operator})
+	if where != nil && !where.IsEmpty() {
+		scoped.AddGroup(where)
+	}
+	return scoped
+}
@@ -33,7 +33,18 @@ func NewTxExecutor(tx *sql.Tx, provider string) *TxExecutor {
 // Override query methods to use transaction
 
 // FindManyWithRelations executes a SELECT query within a transaction
-func (e *TxExecutor) FindManyWithRelations(ctx context.Context, table string, selectFields map[string]bool, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, limit, offset *int, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) error {
+func (e *TxExecutor) FindManyWithRelations(ctx context.Context, table string, selectFields map[string]bool, where *sqlgen.WhereClause, orderBy []sqlgen.OrderBy, limit, offset *int, include map[string]bool, relations map[string]RelationMetadata, dest interface{}) (err error) {
+	args := &Args{Select: selectFields, Where: where, OrderBy: orderBy, Limit: limit, Offset: offset, Include: include}
+	if ok, err := e.intercept(ctx, table, "findMany", args, dest, func(ctx context.Context, args *Args) error {
+		return e.FindManyWithRelations(ctx, table, args.Select, args.Where, args.OrderBy, args.Limit, args.Offset, args.Include, relations, dest)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "findMany", where, orderBy)
+	defer end(&err)
+	where = e.scopeDeleted(table, where)
+
 	// Convert selectFields map to slice
 	var columns []string
 	if selectFields != nil && len(selectFields) > 0 {
@@ -47,7 +58,7 @@ func (e *TxExecutor) FindManyWithRelations(ctx context.Context, table string, se
 	// Build JOINs if relations are included
 	var joins []sqlgen.Join
 	if include != nil && len(include) > 0 && relations != nil {
-		joins = buildJoinsFromIncludes(table, include, relations, e.provider)
+		joins = e.scopeJoins(buildJoinsFromIncludes(table, include, relations, e.provider))
 	}
 
 	if len(joins) > 0 {
@@ -55,8 +66,11 @@ func (e *TxExecutor) FindManyWithRelations(ctx context.Context, table string, se
 	} else {
 		query = e.generator.GenerateSelect(table, columns, where, orderBy, limit, offset)
 	}
+	if query, err = rewriteQuery(ctx, query); err != nil {
+		return err
+	}
 
-	rows, err := e.tx.QueryContext(ctx, query.SQL, query.Args...)
+	rows, err := e.query(ctx, e.tx, query)
 	if err != nil {
 		return fmt.Errorf("query execution failed: %w", err)
 	}
@@ -82,57 +96,74 @@ func (e *TxExecutor) FindManyWithRelations(ctx context.Context, table string, se
 }
 
 // Create executes an INSERT query within a transaction
-func (e *TxExecutor) Create(ctx context.Context, table string, data interface{}) (interface{}, error) {
+func (e *TxExecutor) Create(ctx context.Context, table string, data interface{}) (_ interface{}, err error) {
+	var created interface{}
+	if ok, err := e.intercept(ctx, table, "create", &Args{Data: data}, &created, func(ctx context.Context, args *Args) (err error) {
+		created, err = e.Create(ctx, table, args.Data)
+		return err
+	}); ok {
+		return created, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "create", nil, nil)
+	defer end(&err)
+
 	columns, values, err := e.extractInsertData(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract insert data: %w", err)
 	}
 
-	query := e.generator.GenerateInsert(table, columns, values)
+	query, err := rewriteQuery(ctx, e.generator.GenerateInsert(table, columns, values))
+	if err != nil {
+		return nil, err
+	}
 
 	// For PostgreSQL, use RETURNING
 	if e.provider == "postgresql" || e.provider == "postgres" {
-		row := e.tx.QueryRowContext(ctx, query.SQL, query.Args...)
-		return e.scanRowToStruct(row, data)
+		return e.insertReturning(ctx, e.tx, query, data)
 	}
 
 	// For other databases, execute insert then query back
-	result, err := e.tx.ExecContext(ctx, query.SQL, query.Args...)
+	result, err := e.exec(ctx, e.tx, query)
 	if err != nil {
 		return nil, fmt.Errorf("insert failed: %w", err)
 	}
 
 	// Get the last insert ID if available
-	id, err := result.LastInsertId()
-	if err == nil {
-		// Query back the record
-		where := &sqlgen.WhereClause{
-			Conditions: []sqlgen.Condition{
-				{Field: "id", Operator: "=", Value: id},
-			},
-			Operator: "AND",
-		}
-		var found interface{} = data
-		if err := e.FindFirstWithRelations(ctx, table, nil, where, nil, nil, nil, &found); err == nil {
-			return found, nil
-		}
+	var insertedID interface{}
+	if id, err := result.LastInsertId(); err == nil {
+		insertedID = id
+	} else {
+		insertedID = e.extractIDFromData(data)
 	}
 
-	return data, nil
+	return e.findInserted(ctx, e.tx, table, data, insertedID), nil
 }
 
 // Update executes an UPDATE query within a transaction
-func (e *TxExecutor) Update(ctx context.Context, table string, set map[string]interface{}, where *sqlgen.WhereClause, dest interface{}) error {
-	query := e.generator.GenerateUpdate(table, set, where)
+func (e *TxExecutor) Update(ctx context.Context, table string, set map[string]interface{}, where *sqlgen.WhereClause, dest interface{}) (err error) {
+	if ok, err := e.intercept(ctx, table, "update", &Args{Set: set, Where: where}, dest, func(ctx context.Context, args *Args) error {
+		return e.Update(ctx, table, args.Set, args.Where, dest)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "update", where, nil)
+	defer end(&err)
+
+	query, err := rewriteQuery(ctx, e.generator.GenerateUpdate(table, set, e.scopeDeleted(table, where)))
+	if err != nil {
+		return err
+	}
 
 	// For PostgreSQL, use RETURNING
 	if e.provider == "postgresql" || e.provider == "postgres" {
-		row := e.tx.QueryRowContext(ctx, query.SQL, query.Args...)
+		row := e.queryRow(ctx, e.tx, query)
 		return e.scanRow(row, dest)
 	}
 
 	// For other databases, execute update
-	_, err := e.tx.ExecContext(ctx, query.SQL, query.Args...)
+	_, err = e.exec(ctx, e.tx, query)
 	if err != nil {
 		return fmt.Errorf("update failed: %w", err)
 	}
@@ -141,10 +172,22 @@ func (e *TxExecutor) Update(ctx context.Context, table string, set map[string]in
 }
 
 // Delete executes a DELETE query within a transaction
-func (e *TxExecutor) Delete(ctx context.Context, table string, where *sqlgen.WhereClause) error {
-	query := e.generator.GenerateDelete(table, where)
+func (e *TxExecutor) Delete(ctx context.Context, table string, where *sqlgen.WhereClause) (err error) {
+	if ok, err := e.intercept(ctx, table, "delete", &Args{Where: where}, nil, func(ctx context.Context, args *Args) error {
+		return e.Delete(ctx, table, args.Where)
+	}); ok {
+		return err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "delete", where, nil)
+	defer end(&err)
+
+	query, err := rewriteQuery(ctx, e.deleteQuery(table, where))
+	if err != nil {
+		return err
+	}
 
-	_, err := e.tx.ExecContext(ctx, query.SQL, query.Args...)
+	_, err = e.exec(ctx, e.tx, query)
 	if err != nil {
 		return fmt.Errorf("delete failed: %w", err)
 	}
@@ -153,15 +196,29 @@ func (e *TxExecutor) Delete(ctx context.Context, table string, where *sqlgen.Whe
 }
 
 // Count executes a COUNT query within a transaction
-func (e *TxExecutor) Count(ctx context.Context, table string, where *sqlgen.WhereClause) (int64, error) {
+func (e *TxExecutor) Count(ctx context.Context, table string, where *sqlgen.WhereClause) (_ int64, err error) {
+	var result int64
+	if ok, err := e.intercept(ctx, table, "count", &Args{Where: where}, &result, func(ctx context.Context, args *Args) (err error) {
+		result, err = e.Count(ctx, table, args.Where)
+		return err
+	}); ok {
+		return result, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "count", where, nil)
+	defer end(&err)
+
 	aggregates := []sqlgen.AggregateFunction{
 		{Function: "COUNT", Field: "*", Alias: "count"},
 	}
 
-	query := e.generator.GenerateAggregate(table, aggregates, where, nil, nil)
+	query, err := rewriteQuery(ctx, e.generator.GenerateAggregate(table, aggregates, e.scopeDeleted(table, where), nil, nil))
+	if err != nil {
+		return 0, err
+	}
 
 	var count int64
-	err := e.tx.QueryRowContext(ctx, query.SQL, query.Args...).Scan(&count)
+	err = e.queryRow(ctx, e.tx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("count query failed: %w", err)
 	}
@@ -170,7 +227,10 @@ func (e *TxExecutor) Count(ctx context.Context, table string, where *sqlgen.Wher
 }
 
 // CreateMany executes batch INSERT queries within a transaction
-func (e *TxExecutor) CreateMany(ctx context.Context, table string, data []interface{}) ([]interface{}, error) {
+func (e *TxExecutor) CreateMany(ctx context.Context, table string, data []interface{}) (_ []interface{}, err error) {
+	ctx, end := e.startOperation(ctx, table, "createMany", nil, nil)
+	defer end(&err)
+
 	if len(data) == 0 {
 		return []interface{}{}, nil
 	}
@@ -188,6 +248,7 @@ func (e *TxExecutor) CreateMany(ctx context.Context, table string, data []interf
 		// Build multi-row INSERT
 		var parts []string
 		var args []interface{}
+		var argColumns []string
 		argIndex := 1
 
 		parts = append(parts, fmt.Sprintf("INSERT INTO %s", e.quoteIdentifier(table)))
@@ -210,6 +271,7 @@ func (e *TxExecutor) CreateMany(ctx context.Context, table string, data []interf
 			for j := range values {
 				placeholders[j] = fmt.Sprintf("$%d", argIndex)
 				args = append(args, values[j])
+				argColumns = append(argColumns, columns[j])
 				argIndex++
 			}
 			valueParts[i] = fmt.Sprintf("(%s)", strings.Join(placeholders, ", "))
@@ -218,8 +280,8 @@ func (e *TxExecutor) CreateMany(ctx context.Context, table string, data []interf
 		parts = append(parts, strings.Join(valueParts, ", "))
 		parts = append(parts, "RETURNING *")
 
-		querySQL := strings.Join(parts, " ")
-		rows, err := e.tx.QueryContext(ctx, querySQL, args...)
+		query := &sqlgen.Query{SQL: strings.Join(parts, " "), Args: args, Columns: argColumns}
+		rows, err := e.query(ctx, e.tx, query)
 		if err != nil {
 			return nil, fmt.Errorf("batch insert failed: %w", err)
 		}
@@ -254,10 +316,24 @@ func (e *TxExecutor) CreateMany(ctx context.Context, table string, data []interf
 }
 
 // UpdateMany executes batch UPDATE queries within a transaction
-func (e *TxExecutor) UpdateMany(ctx context.Context, table string, set map[string]interface{}, where *sqlgen.WhereClause) (int64, error) {
-	query := e.generator.GenerateUpdate(table, set, where)
+func (e *TxExecutor) UpdateMany(ctx context.Context, table string, set map[string]interface{}, where *sqlgen.WhereClause) (_ int64, err error) {
+	var count int64
+	if ok, err := e.intercept(ctx, table, "updateMany", &Args{Set: set, Where: where}, &count, func(ctx context.Context, args *Args) (err error) {
+		count, err = e.UpdateMany(ctx, table, args.Set, args.Where)
+		return err
+	}); ok {
+		return count, err
+	}
 
-	result, err := e.tx.ExecContext(ctx, query.SQL, query.Args...)
+	ctx, end := e.startOperation(ctx, table, "updateMany", where, nil)
+	defer end(&err)
+
+	query, err := rewriteQuery(ctx, e.generator.GenerateUpdate(table, set, e.scopeDeleted(table, where)))
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := e.exec(ctx, e.tx, query)
 	if err != nil {
 		return 0, fmt.Errorf("batch update failed: %w", err)
 	}
@@ -271,10 +347,24 @@ func (e *TxExecutor) UpdateMany(ctx context.Context, table string, set map[strin
 }
 
 // DeleteMany executes batch DELETE queries within a transaction
-func (e *TxExecutor) DeleteMany(ctx context.Context, table string, where *sqlgen.WhereClause) (int64, error) {
-	query := e.generator.GenerateDelete(table, where)
+func (e *TxExecutor) DeleteMany(ctx context.Context, table string, where *sqlgen.WhereClause) (_ int64, err error) {
+	var count int64
+	if ok, err := e.intercept(ctx, table, "deleteMany", &Args{Where: where}, &count, func(ctx context.Context, args *Args) (err error) {
+		count, err = e.DeleteMany(ctx, table, args.Where)
+		return err
+	}); ok {
+		return count, err
+	}
+
+	ctx, end := e.startOperation(ctx, table, "deleteMany", where, nil)
+	defer end(&err)
+
+	query, err := rewriteQuery(ctx, e.deleteQuery(table, where))
+	if err != nil {
+		return 0, err
+	}
 
-	result, err := e.tx.ExecContext(ctx, query.SQL, query.Args...)
+	result, err := e.exec(ctx, e.tx, query)
 	if err != nil {
 		return 0, fmt.Errorf("batch delete failed: %w", err)
 	}
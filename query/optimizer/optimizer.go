@@ -59,6 +59,22 @@ func (o *Optimizer) AnalyzeQueryPlan(query string) (*QueryPlan, error) {
 	}, nil
 }
 
+// ExplainSQL returns the statement capturing the plan of query with the
+// EXPLAIN of the provider, or false when the provider has none. Its
+// arguments are those of query; the plan is captured without running it.
+func (o *Optimizer) ExplainSQL(query string) (string, bool) {
+	switch o.provider {
+	case "postgresql", "postgres":
+		return "EXPLAIN (FORMAT JSON) " + query, true
+	case "mysql":
+		return "EXPLAIN FORMAT=JSON " + query, true
+	case "sqlite":
+		return "EXPLAIN QUERY PLAN " + query, true
+	default:
+		return "", false
+	}
+}
+
 // QueryPlan represents an analyzed query execution plan
 type QueryPlan struct {
 	Query         string
@@ -2,7 +2,11 @@
 package sqlgen
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -54,11 +58,40 @@ func buildWhereRecursive(where *WhereClause, argIndex *int, placeholder func(int
 	return result, args
 }
 
+// whereColumns returns the column each argument of the WHERE clause built
+// from where is bound to, in the order buildWhereRecursive binds them. The
+// arguments of subqueries are bound to no column.
+func whereColumns(where *WhereClause, provider string) []string {
+	if where == nil {
+		return nil
+	}
+	var columns []string
+	argIndex := 1
+	for _, cond := range where.Conditions {
+		_, args := buildCondition(cond, &argIndex, func(int) string { return "?" }, quoteIdentifier, provider)
+		column := cond.Field
+		if cond.IsSubquery {
+			column = ""
+		}
+		for range args {
+			columns = append(columns, column)
+		}
+	}
+	for _, group := range where.Groups {
+		columns = append(columns, whereColumns(group, provider)...)
+	}
+	return columns
+}
+
 // buildCondition builds a single condition
 func buildCondition(cond Condition, argIndex *int, placeholder func(int) string, quoter func(string) string, provider string) (string, []interface{}) {
 	var args []interface{}
 	var sql string
 
+	if cond.Table != "" {
+		quoter = qualified(cond.Table, quoter)
+	}
+
 	// Handle JSON filters first
 	if cond.JsonType != "" {
 		return buildJsonCondition(cond, argIndex, placeholder, quoter, provider)
@@ -212,108 +245,311 @@ func buildCondition(cond Condition, argIndex *int, placeholder func(int) string,
 	return sql, args
 }
 
-// buildJsonCondition builds JSON-specific conditions based on provider
+// qualified returns a quoter of the columns of table
+func qualified(table string, quoter func(string) string) func(string) string {
+	return func(column string) string {
+		return quoter(table) + "." + quoter(column)
+	}
+}
+
+// buildJsonCondition builds JSON-specific conditions based on provider.
+// Paths use JSONPath syntax ("$.address.city", "$.tags[0]"); a leading "$."
+// may be omitted.
 func buildJsonCondition(cond Condition, argIndex *int, placeholder func(int) string, quoter func(string) string, provider string) (string, []interface{}) {
 	var args []interface{}
 	var sql string
 
 	field := quoter(cond.Field)
-	path := cond.JsonPath
-	if path == "" {
-		path = "$" // Default to root
+	path := normalizeJsonPath(cond.JsonPath)
+
+	// next returns the placeholder for value and records it as an argument
+	next := func(value interface{}) string {
+		p := placeholder(*argIndex)
+		args = append(args, value)
+		(*argIndex)++
+		return p
 	}
 
 	switch provider {
-	case "postgresql", "postgres":
+	case "postgresql", "postgres", "cockroachdb":
 		switch cond.JsonType {
 		case "path":
-			// PostgreSQL: field->>'$.path' = value
-			// Convert JSON path to PostgreSQL path format
-			pgPath := convertJsonPathToPostgres(path)
-			sql = fmt.Sprintf("%s->>%s = %s", field, pgPath, placeholder(*argIndex))
-			args = append(args, cond.Value)
-			(*argIndex)++
+			// PostgreSQL: field #>> '{a,b}' = value
+			sql = fmt.Sprintf("%s #>> %s::text[] = %s", field, next(convertJsonPathToPostgres(path)), next(jsonText(cond.Value)))
+
+		case "string_contains":
+			sql = fmt.Sprintf("%s #>> %s::text[] LIKE %s ESCAPE '\\'", field, next(convertJsonPathToPostgres(path)), next(likeContains(cond.Value, provider)))
 
 		case "contains":
 			// PostgreSQL: field @> '{"key": "value"}'::jsonb
-			sql = fmt.Sprintf("%s @> %s::jsonb", field, placeholder(*argIndex))
-			args = append(args, cond.Value)
-			(*argIndex)++
+			sql = fmt.Sprintf("%s @> %s::jsonb", field, next(jsonText(cond.Value)))
 
 		case "array_contains":
-			// PostgreSQL: field @> '[value]'::jsonb
-			sql = fmt.Sprintf("%s @> %s::jsonb", field, placeholder(*argIndex))
-			args = append(args, cond.Value)
-			(*argIndex)++
+			// PostgreSQL: field #> '{a,b}' @> '[value]'::jsonb
+			sql = fmt.Sprintf("%s #> %s::text[] @> %s::jsonb", field, next(convertJsonPathToPostgres(path)), next(jsonArray(cond.Value)))
 
 		case "has_key":
 			// PostgreSQL: field ? 'key'
-			sql = fmt.Sprintf("%s ? %s", field, placeholder(*argIndex))
-			args = append(args, cond.Value)
-			(*argIndex)++
+			sql = fmt.Sprintf("%s ? %s", field, next(cond.Value))
 		}
 
 	case "mysql":
 		switch cond.JsonType {
 		case "path":
-			// MySQL: JSON_EXTRACT(field, '$.path') = value
-			sql = fmt.Sprintf("JSON_EXTRACT(%s, %s) = %s", field, placeholder(*argIndex), placeholder(*argIndex+1))
-			args = append(args, path, cond.Value)
-			(*argIndex) += 2
+			// MySQL: JSON_UNQUOTE(JSON_EXTRACT(field, '$.path')) = value
+			sql = fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, %s)) = %s", field, next(path), next(jsonText(cond.Value)))
+
+		case "string_contains":
+			// The backslash must be escaped in MySQL string literals
+			sql = fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, %s)) LIKE %s ESCAPE '\\\\'", field, next(path), next(likeContains(cond.Value, provider)))
 
 		case "contains":
 			// MySQL: JSON_CONTAINS(field, 'value')
-			sql = fmt.Sprintf("JSON_CONTAINS(%s, %s)", field, placeholder(*argIndex))
-			args = append(args, cond.Value)
-			(*argIndex)++
+			sql = fmt.Sprintf("JSON_CONTAINS(%s, %s)", field, next(jsonText(cond.Value)))
 
 		case "array_contains":
-			// MySQL: JSON_CONTAINS(field, 'value', '$.path')
-			sql = fmt.Sprintf("JSON_CONTAINS(%s, %s, %s)", field, placeholder(*argIndex), placeholder(*argIndex+1))
-			args = append(args, cond.Value, path)
-			(*argIndex) += 2
+			// MySQL: JSON_CONTAINS(field, '[value]', '$.path')
+			sql = fmt.Sprintf("JSON_CONTAINS(%s, %s, %s)", field, next(jsonArray(cond.Value)), next(path))
 
 		case "has_key":
-			// MySQL: JSON_CONTAINS_PATH(field, 'one', '$.key')
-			sql = fmt.Sprintf("JSON_CONTAINS_PATH(%s, 'one', %s)", field, placeholder(*argIndex))
-			args = append(args, fmt.Sprintf("$.%s", cond.Value))
-			(*argIndex)++
+			// MySQL: JSON_CONTAINS_PATH(field, 'one', '$."key"')
+			sql = fmt.Sprintf("JSON_CONTAINS_PATH(%s, 'one', %s)", field, next(jsonKeyPath(cond.Value)))
 		}
 
 	case "sqlite":
 		switch cond.JsonType {
 		case "path":
 			// SQLite: json_extract(field, '$.path') = value
-			sql = fmt.Sprintf("json_extract(%s, %s) = %s", field, placeholder(*argIndex), placeholder(*argIndex+1))
-			args = append(args, path, cond.Value)
-			(*argIndex) += 2
+			sql = fmt.Sprintf("json_extract(%s, %s) = %s", field, next(path), next(jsonScalar(cond.Value)))
+
+		case "string_contains":
+			sql = fmt.Sprintf("json_extract(%s, %s) LIKE %s ESCAPE '\\'", field, next(path), next(likeContains(cond.Value, provider)))
 
 		case "contains":
 			// SQLite: json_extract(field, '$') contains value (approximation)
-			sql = fmt.Sprintf("json_extract(%s, '$') LIKE %s", field, placeholder(*argIndex))
-			args = append(args, fmt.Sprintf("%%\"%v\"%%", cond.Value))
-			(*argIndex)++
+			sql = fmt.Sprintf("json_extract(%s, '$') LIKE %s ESCAPE '\\'", field, next(likeContains(fmt.Sprintf("\"%v\"", cond.Value), provider)))
 
 		case "array_contains":
-			// SQLite: json_each with subquery
-			sql = fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%s) WHERE value = %s)", field, placeholder(*argIndex))
-			args = append(args, cond.Value)
-			(*argIndex)++
+			// SQLite: one json_each lookup per element
+			var parts []string
+			for _, element := range jsonElements(cond.Value) {
+				parts = append(parts, fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%s, %s) WHERE value = %s)", field, next(path), next(jsonScalar(element))))
+			}
+			sql = strings.Join(parts, " AND ")
 
 		case "has_key":
-			// SQLite: json_extract(field, '$.key') IS NOT NULL
-			sql = fmt.Sprintf("json_extract(%s, %s) IS NOT NULL", field, placeholder(*argIndex))
-			args = append(args, fmt.Sprintf("$.%s", cond.Value))
-			(*argIndex)++
+			// SQLite: json_type is NULL only for missing keys, unlike json_extract
+			sql = fmt.Sprintf("json_type(%s, %s) IS NOT NULL", field, next(jsonKeyPath(cond.Value)))
+		}
+
+	case "sqlserver", "mssql":
+		switch cond.JsonType {
+		case "path":
+			// SQL Server: JSON_VALUE(field, '$.path') = value
+			sql = fmt.Sprintf("JSON_VALUE(%s, %s) = %s", field, next(path), next(jsonText(cond.Value)))
+
+		case "string_contains":
+			sql = fmt.Sprintf("JSON_VALUE(%s, %s) LIKE %s ESCAPE '\\'", field, next(path), next(likeContains(cond.Value, provider)))
+
+		case "contains":
+			// SQL Server has no containment operator, see sqlServerJsonContains
+			var parts []string
+			sqlServerJsonContains(field, "$", jsonDecode(cond.Value), next, &parts)
+			if len(parts) == 0 {
+				parts = append(parts, fmt.Sprintf("ISJSON(%s) = 1", field))
+			}
+			sql = strings.Join(parts, " AND ")
+
+		case "array_contains":
+			// SQL Server: one OPENJSON lookup per element
+			var parts []string
+			for _, element := range jsonElements(cond.Value) {
+				parts = append(parts, fmt.Sprintf("EXISTS (SELECT 1 FROM OPENJSON(%s, %s) WHERE value = %s)", field, next(path), next(jsonText(element))))
+			}
+			sql = strings.Join(parts, " AND ")
+
+		case "has_key":
+			sql = fmt.Sprintf("EXISTS (SELECT 1 FROM OPENJSON(%s) WHERE [key] = %s)", field, next(cond.Value))
 		}
 	}
 
 	return sql, args
 }
 
-// convertJsonPathToPostgres converts JSON path to PostgreSQL format
-// e.g., "$.name" -> "'$.name'" or "$[0]" -> "'$[0]'"
+// normalizeJsonPath adds the "$" root to a JSON path if it is missing
+func normalizeJsonPath(path string) string {
+	switch {
+	case path == "" || path == "$":
+		return "$"
+	case strings.HasPrefix(path, "$"):
+		return path
+	case strings.HasPrefix(path, "["):
+		return "$" + path
+	default:
+		return "$." + path
+	}
+}
+
+// convertJsonPathToPostgres converts a JSON path to a PostgreSQL text array
+// for the #> and #>> operators, e.g. "$.items[0].name" -> {"items","0","name"}
 func convertJsonPathToPostgres(path string) string {
-	// PostgreSQL expects the path as a string literal
-	return fmt.Sprintf("'%s'", path)
+	var elements []string
+	for _, segment := range jsonPathSegments(path) {
+		segment = strings.ReplaceAll(segment, `\`, `\\`)
+		segment = strings.ReplaceAll(segment, `"`, `\"`)
+		elements = append(elements, `"`+segment+`"`)
+	}
+	return "{" + strings.Join(elements, ",") + "}"
+}
+
+// jsonPathSegments splits a JSON path into object keys and array indexes
+func jsonPathSegments(path string) []string {
+	var segments []string
+	rest := strings.TrimPrefix(normalizeJsonPath(path), "$")
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, `"`) {
+				// Quoted key: ."some.key"
+				end := strings.Index(rest[1:], `"`)
+				if end < 0 {
+					return append(segments, rest[1:])
+				}
+				segments = append(segments, rest[1:end+1])
+				rest = rest[end+2:]
+				continue
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return append(segments, strings.Trim(rest[1:], `"'`))
+			}
+			segments = append(segments, strings.Trim(rest[1:end], `"'`))
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		}
+	}
+	return segments
+}
+
+// likeEscaper escapes the LIKE wildcards for an ESCAPE '\' clause; SQL
+// Server also treats [ as a wildcard.
+var (
+	likeEscaper          = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	sqlServerLikeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `[`, `\[`)
+)
+
+// likeContains returns the LIKE pattern matching the strings containing
+// value literally
+func likeContains(value interface{}, provider string) string {
+	escaper := likeEscaper
+	if provider == "sqlserver" || provider == "mssql" {
+		escaper = sqlServerLikeEscaper
+	}
+	return "%" + escaper.Replace(fmt.Sprint(value)) + "%"
+}
+
+// sqlServerJsonContains appends the conditions approximating JSON
+// containment of value at path: objects match key by key, arrays element
+// by element and scalars by equality. As with PostgreSQL's @>, a top-level
+// scalar matches an element of a top-level array. Objects nested in arrays
+// are compared by their JSON text.
+func sqlServerJsonContains(field, path string, value interface{}, next func(interface{}) string, parts *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			sqlServerJsonContains(field, path+"."+strconv.Quote(key), v[key], next, parts)
+		}
+	case []interface{}:
+		for _, element := range v {
+			*parts = append(*parts, fmt.Sprintf("EXISTS (SELECT 1 FROM OPENJSON(%s, %s) WHERE value = %s)", field, next(path), next(jsonText(element))))
+		}
+	default:
+		if path == "$" {
+			*parts = append(*parts, fmt.Sprintf("EXISTS (SELECT 1 FROM OPENJSON(%s) WHERE value = %s)", field, next(jsonText(v))))
+			return
+		}
+		*parts = append(*parts, fmt.Sprintf("JSON_VALUE(%s, %s) = %s", field, next(path), next(jsonText(v))))
+	}
+}
+
+// jsonDecode returns the decoded JSON of value; strings are JSON text, as
+// for the other providers' containment filters
+func jsonDecode(value interface{}) interface{} {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(fmt.Sprint(jsonText(value))), &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
+
+// jsonKeyPath returns the JSON path of a top-level key
+func jsonKeyPath(key interface{}) string {
+	return "$." + strconv.Quote(fmt.Sprint(key))
+}
+
+// jsonText returns value as text comparable with an extracted JSON value:
+// strings are used as is, everything else is encoded as JSON.
+func jsonText(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// jsonScalar returns scalars unchanged, so that drivers bind them with
+// their native type, and encodes other values as JSON.
+func jsonScalar(value interface{}) interface{} {
+	switch value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value
+	}
+	return jsonText(value)
+}
+
+// jsonElements returns the elements of a slice value, or the value itself
+func jsonElements(value interface{}) []interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Type().Elem().Kind() == reflect.Uint8 {
+		return []interface{}{value}
+	}
+	elements := make([]interface{}, v.Len())
+	for i := range elements {
+		elements[i] = v.Index(i).Interface()
+	}
+	return elements
+}
+
+// jsonArray encodes value as a JSON array, wrapping non-slice values
+func jsonArray(value interface{}) string {
+	data, err := json.Marshal(jsonElements(value))
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
 }
@@ -0,0 +1,186 @@
+package sqlgen
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestBuildJsonCondition(t *testing.T) {
+	tests := []struct {
+		provider string
+		cond     Condition
+		sql      string
+		args     []interface{}
+	}{
+		{"postgresql", Condition{Field: "meta", JsonType: "path", JsonPath: "$.address.city", Value: "Berlin"},
+			`"meta" #>> $1::text[] = $2`, []interface{}{`{"address","city"}`, "Berlin"}},
+		{"postgresql", Condition{Field: "meta", JsonType: "string_contains", JsonPath: "tags[0]", Value: "50%_go"},
+			`"meta" #>> $1::text[] LIKE $2 ESCAPE '\'`, []interface{}{`{"tags","0"}`, `%50\%\_go%`}},
+		{"postgresql", Condition{Field: "meta", JsonType: "array_contains", JsonPath: "tags", Value: "go"},
+			`"meta" #> $1::text[] @> $2::jsonb`, []interface{}{`{"tags"}`, `["go"]`}},
+		{"postgresql", Condition{Field: "meta", JsonType: "has_key", Value: "vip"},
+			`"meta" ? $1`, []interface{}{"vip"}},
+		{"mysql", Condition{Field: "meta", JsonType: "path", JsonPath: "level", Value: 3},
+			"JSON_UNQUOTE(JSON_EXTRACT(`meta`, ?)) = ?", []interface{}{"$.level", "3"}},
+		{"mysql", Condition{Field: "meta", JsonType: "string_contains", JsonPath: "name", Value: `a\b`},
+			"JSON_UNQUOTE(JSON_EXTRACT(`meta`, ?)) LIKE ? ESCAPE '\\\\'", []interface{}{"$.name", `%a\\b%`}},
+		{"mysql", Condition{Field: "meta", JsonType: "array_contains", JsonPath: "$.tags", Value: []string{"a", "b"}},
+			"JSON_CONTAINS(`meta`, ?, ?)", []interface{}{`["a","b"]`, "$.tags"}},
+		{"mysql", Condition{Field: "meta", JsonType: "has_key", Value: "a.b"},
+			"JSON_CONTAINS_PATH(`meta`, 'one', ?)", []interface{}{`$."a.b"`}},
+		{"sqlite", Condition{Field: "meta", JsonType: "array_contains", JsonPath: "tags", Value: []int{1, 2}},
+			`EXISTS (SELECT 1 FROM json_each("meta", ?) WHERE value = ?) AND EXISTS (SELECT 1 FROM json_each("meta", ?) WHERE value = ?)`,
+			[]interface{}{"$.tags", 1, "$.tags", 2}},
+		{"sqlserver", Condition{Field: "meta", JsonType: "string_contains", JsonPath: "name", Value: "[x]"},
+			`JSON_VALUE("meta", @p1) LIKE @p2 ESCAPE '\'`, []interface{}{"$.name", `%\[x]%`}},
+		{"sqlserver", Condition{Field: "meta", JsonType: "contains", Value: map[string]interface{}{"level": 3, "address": map[string]interface{}{"city": "Berlin"}, "tags": []string{"go"}}},
+			`JSON_VALUE("meta", @p1) = @p2 AND JSON_VALUE("meta", @p3) = @p4 AND EXISTS (SELECT 1 FROM OPENJSON("meta", @p5) WHERE value = @p6)`,
+			[]interface{}{`$."address"."city"`, "Berlin", `$."level"`, "3", `$."tags"`, "go"}},
+		{"sqlserver", Condition{Field: "meta", JsonType: "contains", Value: "go"},
+			`EXISTS (SELECT 1 FROM OPENJSON("meta") WHERE value = @p1)`, []interface{}{"go"}},
+	}
+
+	for _, tt := range tests {
+		argIndex := 1
+		placeholder := func(i int) string { return "?" }
+		quoter := quoteIdentifier
+		switch tt.provider {
+		case "postgresql":
+			placeholder = func(i int) string { return "$" + string(rune('0'+i)) }
+		case "mysql":
+			quoter = quoteIdentifierMySQL
+		case "sqlserver":
+			placeholder = func(i int) string { return "@p" + string(rune('0'+i)) }
+		}
+		sql, args := buildJsonCondition(tt.cond, &argIndex, placeholder, quoter, tt.provider)
+		if sql != tt.sql {
+			t.Errorf("%s %s: got SQL %s, want %s", tt.provider, tt.cond.JsonType, sql, tt.sql)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s %s: got args %#v, want %#v", tt.provider, tt.cond.JsonType, args, tt.args)
+		}
+		if argIndex != len(tt.args)+1 {
+			t.Errorf("%s %s: argIndex is %d after %d args", tt.provider, tt.cond.JsonType, argIndex, len(tt.args))
+		}
+	}
+}
+
+func TestJsonPathSegments(t *testing.T) {
+	tests := map[string]string{
+		"":                    "",
+		"$":                   "",
+		"name":                "name",
+		"$.items[0].name":     "items 0 name",
+		`$."a.b".c`:           "a.b c",
+		`[2]["key"]`:          "2 key",
+		"address.geo.lat":     "address geo lat",
+		"$.matrix[1][2]":      "matrix 1 2",
+		`$.weird"quote`:       `weird"quote`,
+		"$.unterminated[3":    "unterminated 3",
+		`$."unterminated.key`: "unterminated.key",
+	}
+	for path, want := range tests {
+		if got := strings.Join(jsonPathSegments(path), " "); got != want {
+			t.Errorf("jsonPathSegments(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestJsonConditionsOnSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE "user" (id INTEGER PRIMARY KEY, meta TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	rows := []string{
+		`{"name": "Ada Lovelace", "tags": ["math", "code"], "level": 3, "address": {"city": "London"}}`,
+		`{"name": "Alan Turing", "tags": ["code"], "level": 2, "vip": null}`,
+		`{"name": "50% off_sale at C:\\shop"}`,
+	}
+	for i, meta := range rows {
+		if _, err := db.Exec(`INSERT INTO "user" (id, meta) VALUES (?, ?)`, i+1, meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		cond Condition
+		want []int
+	}{
+		{Condition{Field: "meta", JsonType: "path", JsonPath: "address.city", Value: "London"}, []int{1}},
+		{Condition{Field: "meta", JsonType: "path", JsonPath: "level", Value: 2}, []int{2}},
+		{Condition{Field: "meta", JsonType: "string_contains", JsonPath: "name", Value: "Turing"}, []int{2}},
+		// Wildcards and the escape character match literally
+		{Condition{Field: "meta", JsonType: "string_contains", JsonPath: "name", Value: "%"}, []int{3}},
+		{Condition{Field: "meta", JsonType: "string_contains", JsonPath: "name", Value: "_"}, []int{3}},
+		{Condition{Field: "meta", JsonType: "string_contains", JsonPath: "name", Value: `:\s`}, []int{3}},
+		{Condition{Field: "meta", JsonType: "array_contains", JsonPath: "tags", Value: "code"}, []int{1, 2}},
+		{Condition{Field: "meta", JsonType: "array_contains", JsonPath: "tags", Value: []string{"code", "math"}}, []int{1}},
+		{Condition{Field: "meta", JsonType: "has_key", Value: "vip"}, []int{2}},
+	}
+	gen := NewGenerator("sqlite")
+	for _, tt := range tests {
+		where := NewWhereClause()
+		where.AddCondition(tt.cond)
+		query := gen.GenerateSelect("user", []string{"id"}, where, []OrderBy{{Field: "id", Direction: "ASC"}}, nil, nil)
+
+		result, err := db.Query(query.SQL, query.Args...)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", tt.cond.JsonType, err, query.SQL)
+		}
+		var got []int
+		for result.Next() {
+			var id int
+			if err := result.Scan(&id); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, id)
+		}
+		result.Close()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s = %v, want %v", tt.cond.JsonType, tt.cond.JsonPath, got, tt.want)
+		}
+	}
+}
+
+func TestQueryColumns(t *testing.T) {
+	where := NewWhereClause()
+	where.AddCondition(Condition{Field: "id", Operator: "IN", Value: []int{1, 2}})
+	where.AddCondition(Condition{Field: "deleted_at", Operator: "IS NULL"})
+	group := NewWhereClause()
+	group.SetOperator("OR")
+	group.AddCondition(Condition{Field: "name", Operator: "LIKE", Value: "A%"})
+	group.AddCondition(Condition{Field: "email", Operator: "=", Value: "a@b.c"})
+	where.AddGroup(group)
+	limit := 10
+
+	for _, provider := range []string{"postgresql", "mysql", "sqlite", "sqlserver"} {
+		g := NewGenerator(provider)
+		tests := map[string]struct {
+			query *Query
+			want  []string
+		}{
+			"update": {g.GenerateUpdate("users", map[string]interface{}{"password_hash": "x"}, where), []string{"password_hash", "id", "id", "name", "email"}},
+			"insert": {g.GenerateInsert("users", []string{"email", "name"}, []interface{}{"a@b.c", "Ann"}), []string{"email", "name"}},
+			"delete": {g.GenerateDelete("users", where), []string{"id", "id", "name", "email"}},
+			"select": {g.GenerateSelect("users", nil, where, nil, &limit, nil), []string{"id", "id", "name", "email", ""}},
+		}
+		for name, tt := range tests {
+			want := tt.want
+			if provider == "sqlserver" && name == "select" {
+				want = want[:len(want)-1] // SQL Server inlines the limit
+			}
+			if !reflect.DeepEqual(tt.query.Columns, want) || len(tt.query.Args) != len(want) {
+				t.Errorf("%s %s: columns = %q for %d args, want %q", provider, name, tt.query.Columns, len(tt.query.Args), want)
+			}
+		}
+	}
+}
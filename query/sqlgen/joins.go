@@ -449,6 +449,10 @@ func (g *SQLiteGenerator) GenerateSelectWithJoins(
 
 	// OFFSET
 	if offset != nil && *offset > 0 {
+		if limit == nil || *limit == 0 {
+			// SQLite requires LIMIT when using OFFSET
+			parts = append(parts, "LIMIT -1")
+		}
 		parts = append(parts, "OFFSET ?")
 		args = append(args, *offset)
 		argIndex++
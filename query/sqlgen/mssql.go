@@ -68,8 +68,9 @@ func (g *SQLServerGenerator) GenerateSelect(table string, columns []string, wher
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), whereColumns(where, "sqlserver")...),
 	}
 }
 
@@ -342,14 +343,16 @@ func (g *SQLServerGenerator) GenerateInsert(table string, columns []string, valu
 	parts = append(parts, "OUTPUT INSERTED.*")
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), columns...),
 	}
 }
 
 func (g *SQLServerGenerator) GenerateUpdate(table string, set map[string]interface{}, where *WhereClause) *Query {
 	var parts []string
 	var args []interface{}
+	var setColumns []string
 	argIndex := 1
 
 	parts = append(parts, fmt.Sprintf("UPDATE %s", quoteIdentifier(table)))
@@ -357,6 +360,7 @@ func (g *SQLServerGenerator) GenerateUpdate(table string, set map[string]interfa
 	// SET clause
 	setParts := make([]string, 0, len(set))
 	for col, val := range set {
+		setColumns = append(setColumns, col)
 		setParts = append(setParts, fmt.Sprintf("%s = @p%d", quoteIdentifier(col), argIndex))
 		args = append(args, val)
 		argIndex++
@@ -378,8 +382,9 @@ func (g *SQLServerGenerator) GenerateUpdate(table string, set map[string]interfa
 	parts = append(parts, "OUTPUT INSERTED.*")
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), append(setColumns, whereColumns(where, "sqlserver")...)...),
 	}
 }
 
@@ -402,8 +407,9 @@ func (g *SQLServerGenerator) GenerateDelete(table string, where *WhereClause) *Q
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), whereColumns(where, "sqlserver")...),
 	}
 }
 
@@ -468,8 +474,9 @@ func (g *SQLServerGenerator) GenerateUpsert(table string, columns []string, valu
 	parts = append(parts, "OUTPUT INSERTED.*")
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), columns...),
 	}
 }
 
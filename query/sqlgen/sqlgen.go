@@ -10,6 +10,19 @@ import (
 type Query struct {
 	SQL  string
 	Args []interface{}
+
+	// Columns are the columns Args are bound to, by position: the column
+	// inserted, set or compared, or "" for an argument bound to none, e.g.
+	// of a LIMIT. Columns is nil when they are not known.
+	Columns []string
+}
+
+// boundColumns returns the columns of the n arguments of a statement,
+// which binds columns first and then arguments bound to no column
+func boundColumns(n int, columns ...string) []string {
+	bound := make([]string, n)
+	copy(bound, columns)
+	return bound
 }
 
 // CTE represents a Common Table Expression
@@ -89,6 +102,21 @@ func NewGenerator(provider string) Generator {
 	}
 }
 
+// QuoteIdentifier quotes an identifier the way the generator of provider
+// quotes it
+func QuoteIdentifier(provider, name string) string {
+	switch provider {
+	case "mysql":
+		return quoteIdentifierMySQL(name)
+	case "sqlite":
+		return quoteIdentifierSQLite(name)
+	case "sqlserver", "mssql":
+		return quoteIdentifierSQLServer(name)
+	default:
+		return quoteIdentifier(name)
+	}
+}
+
 // PostgresGenerator generates PostgreSQL SQL
 type PostgresGenerator struct{}
 
@@ -145,8 +173,9 @@ func (g *PostgresGenerator) GenerateSelect(table string, columns []string, where
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), whereColumns(where, "postgresql")...),
 	}
 }
 
@@ -639,8 +668,9 @@ func (g *PostgresGenerator) GenerateInsert(table string, columns []string, value
 	parts = append(parts, "RETURNING *")
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), columns...),
 	}
 }
 
@@ -709,14 +739,16 @@ func (g *PostgresGenerator) GenerateUpsert(table string, columns []string, value
 	parts = append(parts, "RETURNING *")
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), columns...),
 	}
 }
 
 func (g *PostgresGenerator) GenerateUpdate(table string, set map[string]interface{}, where *WhereClause) *Query {
 	var parts []string
 	var args []interface{}
+	var setColumns []string
 	argIndex := 1
 
 	parts = append(parts, fmt.Sprintf("UPDATE %s", quoteIdentifier(table)))
@@ -725,6 +757,7 @@ func (g *PostgresGenerator) GenerateUpdate(table string, set map[string]interfac
 	if len(set) > 0 {
 		setParts := make([]string, 0, len(set))
 		for col, val := range set {
+			setColumns = append(setColumns, col)
 			setParts = append(setParts, fmt.Sprintf("%s = $%d", quoteIdentifier(col), argIndex))
 			args = append(args, val)
 			argIndex++
@@ -747,8 +780,9 @@ func (g *PostgresGenerator) GenerateUpdate(table string, set map[string]interfac
 	parts = append(parts, "RETURNING *")
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), append(setColumns, whereColumns(where, "postgresql")...)...),
 	}
 }
 
@@ -777,8 +811,9 @@ func (g *PostgresGenerator) GenerateDelete(table string, where *WhereClause) *Qu
 	parts = append(parts, "RETURNING *")
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), whereColumns(where, "postgresql")...),
 	}
 }
 
@@ -855,8 +890,9 @@ func (g *MySQLGenerator) GenerateSelect(table string, columns []string, where *W
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), whereColumns(where, "mysql")...),
 	}
 }
 
@@ -1013,8 +1049,9 @@ func (g *MySQLGenerator) GenerateInsert(table string, columns []string, values [
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), columns...),
 	}
 }
 
@@ -1088,14 +1125,16 @@ func (g *MySQLGenerator) GenerateUpsert(table string, columns []string, values [
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), columns...),
 	}
 }
 
 func (g *MySQLGenerator) GenerateUpdate(table string, set map[string]interface{}, where *WhereClause) *Query {
 	var parts []string
 	var args []interface{}
+	var setColumns []string
 	argIndex := 1
 
 	parts = append(parts, fmt.Sprintf("UPDATE %s", quoteIdentifierMySQL(table)))
@@ -1104,6 +1143,7 @@ func (g *MySQLGenerator) GenerateUpdate(table string, set map[string]interface{}
 	if len(set) > 0 {
 		setParts := make([]string, 0, len(set))
 		for col, val := range set {
+			setColumns = append(setColumns, col)
 			setParts = append(setParts, fmt.Sprintf("%s = ?", quoteIdentifierMySQL(col)))
 			args = append(args, val)
 		}
@@ -1113,8 +1153,8 @@ func (g *MySQLGenerator) GenerateUpdate(table string, set map[string]interface{}
 	// WHERE clause
 	if where != nil && !where.IsEmpty() {
 		whereSQL, whereArgs := buildWhereRecursive(where, &argIndex, func(i int) string {
-			return fmt.Sprintf("$%d", i)
-		}, quoteIdentifier, "postgresql")
+			return "?"
+		}, quoteIdentifierMySQL, "mysql")
 		if whereSQL != "" {
 			parts = append(parts, "WHERE "+whereSQL)
 			args = append(args, whereArgs...)
@@ -1122,8 +1162,9 @@ func (g *MySQLGenerator) GenerateUpdate(table string, set map[string]interface{}
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), append(setColumns, whereColumns(where, "mysql")...)...),
 	}
 }
 
@@ -1137,8 +1178,8 @@ func (g *MySQLGenerator) GenerateDelete(table string, where *WhereClause) *Query
 	// WHERE clause
 	if where != nil && !where.IsEmpty() {
 		whereSQL, whereArgs := buildWhereRecursive(where, &argIndex, func(i int) string {
-			return fmt.Sprintf("$%d", i)
-		}, quoteIdentifier, "postgresql")
+			return "?"
+		}, quoteIdentifierMySQL, "mysql")
 		if whereSQL != "" {
 			parts = append(parts, "WHERE "+whereSQL)
 			args = append(args, whereArgs...)
@@ -1149,8 +1190,9 @@ func (g *MySQLGenerator) GenerateDelete(table string, where *WhereClause) *Query
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), whereColumns(where, "mysql")...),
 	}
 }
 
@@ -1187,8 +1229,8 @@ func (g *SQLiteGenerator) GenerateSelect(table string, columns []string, where *
 	// WHERE clause
 	if where != nil && !where.IsEmpty() {
 		whereSQL, whereArgs := buildWhereRecursive(where, &argIndex, func(i int) string {
-			return fmt.Sprintf("$%d", i)
-		}, quoteIdentifier, "postgresql")
+			return "?"
+		}, quoteIdentifierSQLite, "sqlite")
 		if whereSQL != "" {
 			parts = append(parts, "WHERE "+whereSQL)
 			args = append(args, whereArgs...)
@@ -1217,13 +1259,18 @@ func (g *SQLiteGenerator) GenerateSelect(table string, columns []string, where *
 
 	// OFFSET
 	if offset != nil && *offset > 0 {
+		if limit == nil || *limit == 0 {
+			// SQLite requires LIMIT when using OFFSET
+			parts = append(parts, "LIMIT -1")
+		}
 		parts = append(parts, fmt.Sprintf("OFFSET ?"))
 		args = append(args, *offset)
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), whereColumns(where, "sqlite")...),
 	}
 }
 
@@ -1339,6 +1386,10 @@ func (g *SQLiteGenerator) GenerateSelectWithCTE(
 
 	// OFFSET
 	if offset != nil && *offset > 0 {
+		if limit == nil || *limit == 0 {
+			// SQLite requires LIMIT when using OFFSET
+			parts = append(parts, "LIMIT -1")
+		}
 		parts = append(parts, "OFFSET ?")
 		args = append(args, *offset)
 	}
@@ -1392,8 +1443,9 @@ func (g *SQLiteGenerator) GenerateInsert(table string, columns []string, values
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), columns...),
 	}
 }
 
@@ -1457,14 +1509,16 @@ func (g *SQLiteGenerator) GenerateUpsert(table string, columns []string, values
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), columns...),
 	}
 }
 
 func (g *SQLiteGenerator) GenerateUpdate(table string, set map[string]interface{}, where *WhereClause) *Query {
 	var parts []string
 	var args []interface{}
+	var setColumns []string
 	argIndex := 1
 
 	parts = append(parts, fmt.Sprintf("UPDATE %s", quoteIdentifierSQLite(table)))
@@ -1473,6 +1527,7 @@ func (g *SQLiteGenerator) GenerateUpdate(table string, set map[string]interface{
 	if len(set) > 0 {
 		setParts := make([]string, 0, len(set))
 		for col, val := range set {
+			setColumns = append(setColumns, col)
 			setParts = append(setParts, fmt.Sprintf("%s = ?", quoteIdentifierSQLite(col)))
 			args = append(args, val)
 		}
@@ -1482,8 +1537,8 @@ func (g *SQLiteGenerator) GenerateUpdate(table string, set map[string]interface{
 	// WHERE clause
 	if where != nil && !where.IsEmpty() {
 		whereSQL, whereArgs := buildWhereRecursive(where, &argIndex, func(i int) string {
-			return fmt.Sprintf("$%d", i)
-		}, quoteIdentifier, "postgresql")
+			return "?"
+		}, quoteIdentifierSQLite, "sqlite")
 		if whereSQL != "" {
 			parts = append(parts, "WHERE "+whereSQL)
 			args = append(args, whereArgs...)
@@ -1491,8 +1546,9 @@ func (g *SQLiteGenerator) GenerateUpdate(table string, set map[string]interface{
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), append(setColumns, whereColumns(where, "sqlite")...)...),
 	}
 }
 
@@ -1506,8 +1562,8 @@ func (g *SQLiteGenerator) GenerateDelete(table string, where *WhereClause) *Quer
 	// WHERE clause
 	if where != nil && !where.IsEmpty() {
 		whereSQL, whereArgs := buildWhereRecursive(where, &argIndex, func(i int) string {
-			return fmt.Sprintf("$%d", i)
-		}, quoteIdentifier, "postgresql")
+			return "?"
+		}, quoteIdentifierSQLite, "sqlite")
 		if whereSQL != "" {
 			parts = append(parts, "WHERE "+whereSQL)
 			args = append(args, whereArgs...)
@@ -1518,8 +1574,9 @@ func (g *SQLiteGenerator) GenerateDelete(table string, where *WhereClause) *Quer
 	}
 
 	return &Query{
-		SQL:  strings.Join(parts, " "),
-		Args: args,
+		SQL:     strings.Join(parts, " "),
+		Args:    args,
+		Columns: boundColumns(len(args), whereColumns(where, "sqlite")...),
 	}
 }
 
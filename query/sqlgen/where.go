@@ -12,11 +12,12 @@ type WhereClause struct {
 // Condition represents a single filter condition
 type Condition struct {
 	Field    string
-	Operator string // "=", "!=", ">", "<", ">=", "<=", "IN", "NOT IN", "LIKE", "IS NULL", "IS NOT NULL", "JSON_PATH", "JSON_CONTAINS", "JSON_ARRAY_CONTAINS", "EXISTS", "NOT EXISTS"
+	Table    string // Table qualifying Field, for queries with joins
+	Operator string // "=", "!=", ">", "<", ">=", "<=", "IN", "NOT IN", "LIKE", "IS NULL", "IS NOT NULL", "JSON_PATH", "JSON_STRING_CONTAINS", "JSON_CONTAINS", "JSON_ARRAY_CONTAINS", "JSON_HAS_KEY", "EXISTS", "NOT EXISTS"
 	Value    interface{}
 	// JSON-specific fields
 	JsonPath string // JSON path (e.g., "$.name", "$[0]", "$.items[*].id")
-	JsonType string // JSON filter type: "path", "contains", "array_contains", "has_key"
+	JsonType string // JSON filter type: "path", "string_contains", "contains", "array_contains", "has_key"
 	// Join condition fields
 	IsJoinCondition bool   // true if this is a join condition
 	LeftTable       string // left table name for join
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql" // MySQL driver
@@ -12,6 +13,7 @@ import (
 	_ "github.com/mattn/go-sqlite3"    // SQLite driver
 
 	"github.com/satishbabariya/prisma-go/query/cache"
+	"github.com/satishbabariya/prisma-go/query/executor"
 )
 
 // PrismaClient is the main database client
@@ -22,6 +24,20 @@ type PrismaClient struct {
 	queryCache  cache.Cache
 	cacheConfig CacheConfig
 	extensions  *ExtensionChain
+	observers   *executor.Observers
+	pipeline    *executor.Pipeline
+	schemaNames SchemaNames
+
+	extensionsOnce sync.Once
+
+	slowQueryOnce sync.Once
+	slowQueries   *slowQueryLog
+
+	nPlusOneOnce sync.Once
+	nPlusOne     *nPlusOneDetector
+
+	loggerOnce sync.Once
+	logger     *queryLogger
 }
 
 // CacheConfig holds cache configuration
@@ -54,6 +70,8 @@ func NewPrismaClient(provider string, connectionString string) (*PrismaClient, e
 			DefaultTTL: 5 * time.Minute,
 		},
 		extensions: NewExtensionChain(),
+		observers:  &executor.Observers{},
+		pipeline:   &executor.Pipeline{},
 	}, nil
 }
 
@@ -70,9 +88,29 @@ func NewPrismaClientFromDB(provider string, db *sql.DB) (*PrismaClient, error) {
 			DefaultTTL: 5 * time.Minute,
 		},
 		extensions: NewExtensionChain(),
+		observers:  &executor.Observers{},
+		pipeline:   &executor.Pipeline{},
 	}, nil
 }
 
+// Derive returns a client sharing the connection, observers, cache and
+// schema names of c, with middleware and extensions of its own: those of
+// c run first, and those added to the derived client do not change c.
+// Disconnecting either client closes the shared connection.
+func (c *PrismaClient) Derive() *PrismaClient {
+	return &PrismaClient{
+		db:          c.db,
+		provider:    c.provider,
+		middlewares: append([]Middleware(nil), c.middlewares...),
+		queryCache:  c.queryCache,
+		cacheConfig: c.cacheConfig,
+		extensions:  NewExtensionChain(),
+		observers:   c.observers,
+		pipeline:    c.pipeline.Derive(),
+		schemaNames: c.schemaNames,
+	}
+}
+
 // getDriverName maps Prisma provider names to Go database driver names
 func getDriverName(provider string) string {
 	switch provider {
@@ -119,13 +157,13 @@ func (c *PrismaClient) Disconnect(ctx context.Context) error {
 
 // Raw executes a raw SQL query and returns the result
 func (c *PrismaClient) Raw(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	return c.db.QueryContext(ctx, query, args...)
+	return c.RawQuery(ctx, query, args...)
 }
 
 // RawScan executes a raw SQL query and scans the results into the destination
 // Note: This is a placeholder - full implementation would require reflection or sqlx
 func (c *PrismaClient) RawScan(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	rows, err := c.db.QueryContext(ctx, query, args...)
+	rows, err := c.RawQuery(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -144,12 +182,24 @@ func (c *PrismaClient) DB() *sql.DB {
 
 // RawQuery executes a raw SQL query with parameters and maps results to structs
 func (c *PrismaClient) RawQuery(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	return c.db.QueryContext(ctx, query, args...)
+	ctx, _, end, err := c.startStatement(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	end(err)
+	return rows, err
 }
 
-// RawQueryRow executes a raw SQL query that returns a single row
+// RawQueryRow executes a raw SQL query that returns a single row. As a
+// *sql.Row cannot carry an error of its own, a StatementGuard does not
+// keep the query from running.
 func (c *PrismaClient) RawQueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	return c.db.QueryRowContext(ctx, query, args...)
+	stmt := executor.NewStatement(c.provider, query, args)
+	ctx, end := c.observers.StartStatement(ctx, stmt)
+	row := c.db.QueryRowContext(ctx, query, args...)
+	end(row.Err())
+	return row
 }
 
 // RawExec executes a raw SQL statement (INSERT, UPDATE, DELETE)
@@ -160,17 +210,78 @@ func (c *PrismaClient) RawExec(ctx context.Context, query string, args ...interf
 	if len(c.middlewares) > 0 {
 		err = c.executeWithMiddleware(ctx, query, args, func() error {
 			var execErr error
-			result, execErr = c.db.ExecContext(ctx, query, args...)
+			result, execErr = c.exec(ctx, query, args)
 			return execErr
 		})
 	} else {
-		result, err = c.db.ExecContext(ctx, query, args...)
+		result, err = c.exec(ctx, query, args)
 	}
 
 	return result, err
 }
 
-// Use adds a middleware to the client
+// exec executes a raw SQL statement, notifying the observers
+func (c *PrismaClient) exec(ctx context.Context, query string, args []interface{}) (sql.Result, error) {
+	ctx, stmt, end, err := c.startStatement(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.db.ExecContext(ctx, query, args...)
+	if err == nil {
+		if n, err := result.RowsAffected(); err == nil {
+			stmt.RowsAffected = n
+		}
+	}
+	end(err)
+	return result, err
+}
+
+// Observe adds an observer of the operations of the generated client, of
+// the SQL statements they and raw queries run, and of transactions
+func (c *PrismaClient) Observe(observer executor.Observer) {
+	c.observers.Add(observer)
+}
+
+// Observers returns the observers of the client, shared with the executors
+// of the generated client
+func (c *PrismaClient) Observers() *executor.Observers {
+	return c.observers
+}
+
+// Intercept adds a middleware to the operations of the generated client,
+// run in the order middleware was added. Middleware can change the
+// arguments of an operation, rewrite its SQL, return a result without
+// running it or transform its result, e.g. to scope queries to a tenant or
+// cache them.
+func (c *PrismaClient) Intercept(middleware executor.Middleware) {
+	c.pipeline.Use(middleware)
+}
+
+// Pipeline returns the middleware pipeline of the client, shared with the
+// executors of the generated client
+func (c *PrismaClient) Pipeline() *executor.Pipeline {
+	return c.pipeline
+}
+
+// SetSchemaNames sets the names of the schema of the generated client, to
+// name models and relations in diagnostics
+func (c *PrismaClient) SetSchemaNames(names SchemaNames) {
+	c.schemaNames = names
+}
+
+// startStatement notifies the observers of the start of a raw query,
+// unless a StatementGuard keeps it from running
+func (c *PrismaClient) startStatement(ctx context.Context, query string, args []interface{}) (context.Context, *executor.Statement, func(err error), error) {
+	stmt := executor.NewStatement(c.provider, query, args)
+	if err := c.observers.CheckStatement(ctx, stmt); err != nil {
+		return ctx, stmt, nil, err
+	}
+	ctx, end := c.observers.StartStatement(ctx, stmt)
+	return ctx, stmt, end, nil
+}
+
+// Use adds a middleware of the raw statements run with RawExec. Use
+// Intercept for the operations of the generated client.
 func (c *PrismaClient) Use(middleware Middleware) {
 	c.middlewares = append(c.middlewares, middleware)
 }
@@ -214,9 +325,13 @@ func (c *PrismaClient) ClearCache() {
 	}
 }
 
-// UseExtension adds an extension to the client
+// UseExtension adds an extension to the client. Extensions run in the
+// middleware pipeline, after the middleware added before the first one.
 func (c *PrismaClient) UseExtension(ext Extension) {
 	c.extensions.Add(ext)
+	c.extensionsOnce.Do(func() {
+		c.pipeline.Use(c.extensions.middleware())
+	})
 }
 
 // Extensions returns the extension chain
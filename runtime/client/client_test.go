@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+)
+
+// newTestClient returns a client of an in-memory SQLite database created by
+// ddl, closed when the test ends. The database has a single connection, so
+// that every statement sees it.
+func newTestClient(t *testing.T, ddl ...string) *PrismaClient {
+	t.Helper()
+	c, err := NewPrismaClient("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Disconnect(context.Background()) })
+	c.SetMaxOpenConns(1)
+	for _, stmt := range ddl {
+		if _, err := c.RawExec(context.Background(), stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+// newTestExecutor returns an executor of c wired the way the generated
+// client wires its own, for a schema of the given names
+func newTestExecutor(c *PrismaClient, names SchemaNames) *executor.Executor {
+	exec := executor.NewExecutor(c.DB(), "sqlite")
+	exec.SetErrorMapper(names.ClassifyError)
+	exec.SetObservers(c.Observers())
+	exec.SetPipeline(c.Pipeline())
+	exec.SetSoftDelete(names.SoftDeletes())
+	exec.SetModelNames(names.Models())
+	c.SetSchemaNames(names)
+	return exec
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+	"github.com/satishbabariya/prisma-go/runtime/types"
+)
+
+const decimalSchema = `
+datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+
+model Product {
+  id    Int     @id @default(autoincrement())
+  price Decimal
+}
+`
+
+type decimalProduct struct {
+	Id    int           `json:"id" db:"id" prisma:"default"`
+	Price types.Decimal `json:"price" db:"price"`
+}
+
+func TestDecimalFilters(t *testing.T) {
+	_, exec, _ := newMigratedClient(t, decimalSchema)
+	ctx := context.Background()
+	for _, price := range []string{"9.50", "10.50", "100.25"} {
+		if _, err := exec.Create(ctx, "product", &decimalProduct{Price: types.NewDecimal(price)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 10.50 and 100.25 sort before 9.75 as text
+	for _, tt := range []struct {
+		operator string
+		value    string
+		want     []string
+	}{
+		{">", "9.75", []string{"10.5", "100.25"}},
+		{"<", "10.25", []string{"9.5"}},
+		{"=", "10.5", []string{"10.5"}},
+	} {
+		where := sqlgen.NewWhereClause()
+		where.AddCondition(sqlgen.Condition{Field: "price", Operator: tt.operator, Value: types.NewDecimal(tt.value)})
+		var products []decimalProduct
+		if err := exec.FindMany(ctx, "product", nil, where, nil, nil, nil, nil, &products); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, product := range products {
+			got = append(got, product.Price.String())
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("price %s %s = %v, want %v", tt.operator, tt.value, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("price %s %s = %v, want %v", tt.operator, tt.value, got, tt.want)
+				break
+			}
+		}
+	}
+
+	max, err := exec.MaxDecimal(ctx, "product", "price", nil)
+	if err != nil || !max.Equal(types.NewDecimal("100.25")) {
+		t.Errorf("max = %s (%v), want 100.25", max, err)
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/generator/codegen"
+	"github.com/satishbabariya/prisma-go/migrate/converter"
+	"github.com/satishbabariya/prisma-go/migrate/diff"
+	"github.com/satishbabariya/prisma-go/migrate/introspect"
+	"github.com/satishbabariya/prisma-go/migrate/sqlgen"
+	"github.com/satishbabariya/prisma-go/psl"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
+	"github.com/satishbabariya/prisma-go/query/executor"
+)
+
+const defaultsSchema = `
+datasource db {
+  provider = "sqlite"
+  url      = "file:dev.db"
+}
+
+model Event {
+  id        Int       @id @default(autoincrement())
+  name      String
+  createdAt DateTime  @default(now())
+  seenAt    DateTime? @default(now())
+}
+`
+
+// defaultsEvent is the struct generated for Event
+type defaultsEvent struct {
+	Id        int        `json:"id" db:"id" prisma:"default"`
+	Name      string     `json:"name" db:"name"`
+	Createdat time.Time  `json:"created_at" db:"created_at" prisma:"now"`
+	Seenat    *time.Time `json:"seen_at" db:"seen_at" prisma:"now"`
+}
+
+// newMigratedClient returns a SQLite client whose tables are created by
+// prisma-go from schema, an executor of its generated client and the parsed
+// schema
+func newMigratedClient(t *testing.T, schema string) (*PrismaClient, *executor.Executor, *ast.SchemaAst) {
+	t.Helper()
+	files := []psl.SourceFile{psl.NewSourceFile("schema.prisma", schema)}
+	schemaAST, diags := psl.ParseSchemaFiles(files)
+	if diags.HasErrors() {
+		t.Fatal(psl.RenderDiagnostics(files, diags))
+	}
+	target, err := converter.ConvertASTToDBSchema(schemaAST, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	differ, err := diff.NewDiffer("sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	migration, err := sqlgen.NewSQLiteMigrationGenerator().GenerateMigrationSQL(differ.CompareSchemas(&introspect.DatabaseSchema{}, target), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(t, migration)
+	return c, executor.NewExecutor(c.DB(), "sqlite"), schemaAST
+}
+
+func TestCreateFillsNowDefaults(t *testing.T) {
+	// The table is migrated by prisma-go, without DEFAULT for now()
+	_, exec, schemaAST := newMigratedClient(t, defaultsSchema)
+	eventType := reflect.TypeOf(defaultsEvent{})
+	for i, field := range codegen.GenerateModelsFromAST(schemaAST, nil)[0].Fields {
+		if want := "`" + string(eventType.Field(i).Tag) + "`"; field.Tags != want {
+			t.Errorf("generated tags of %s = %s, want %s", field.Name, field.Tags, want)
+		}
+	}
+
+	before := time.Now().UTC().Add(-time.Second)
+	if _, err := exec.Create(context.Background(), "event", &defaultsEvent{Name: "launch"}); err != nil {
+		t.Fatal(err)
+	}
+	var events []defaultsEvent
+	if err := exec.FindMany(context.Background(), "event", nil, nil, nil, nil, nil, nil, &events); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Createdat.Before(before) || events[0].Seenat == nil || events[0].Seenat.Before(before) {
+		t.Errorf("events = %+v, want now() filled in", events)
+	}
+
+	// Values set by the caller are kept
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := exec.Create(context.Background(), "event", &defaultsEvent{Name: "past", Createdat: at}); err != nil {
+		t.Fatal(err)
+	}
+	var past defaultsEvent
+	where := whereEquals("name", "past")
+	if err := exec.FindFirst(context.Background(), "event", nil, where, nil, nil, &past); err != nil {
+		t.Fatal(err)
+	}
+	if !past.Createdat.Equal(at) {
+		t.Errorf("createdAt = %v, want %v", past.Createdat, at)
+	}
+}
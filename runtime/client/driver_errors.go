@@ -0,0 +1,77 @@
+package client
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/runtime/dberrors"
+)
+
+// ClassifyError classifies err like the package-level ClassifyError, with
+// the table and columns named by the driver resolved to the model and its
+// fields. table is the table of the failed query, the model of errors that
+// do not name their table.
+func (n SchemaNames) ClassifyError(table string, err error) error {
+	return dberrors.Classify(table, err, n.resolve)
+}
+
+// resolve returns the model of table and the fields of the columns of a
+// driver error. The columns of a constraint are found from its name when
+// the driver does not report them.
+func (n SchemaNames) resolve(table, constraint string, columns []string) (string, []string) {
+	names, ok := n.table(table)
+	if len(columns) == 0 && constraint != "" && ok {
+		columns = constraintColumns(constraint, table, names)
+	}
+	fields := make([]string, len(columns))
+	for i, column := range columns {
+		fields[i] = column
+		if field, ok := names.Fields[column]; ok {
+			fields[i] = field
+		}
+	}
+	return names.Model, fields
+}
+
+// constraintColumns returns the columns of a constraint named by Prisma's
+// conventions, <table>_<columns>_key for unique and <table>_<columns>_fkey
+// for foreign key constraints, for drivers that only report the name
+func constraintColumns(constraint, table string, names TableNames) []string {
+	rest, ok := strings.CutPrefix(constraint, table+"_")
+	if !ok {
+		return nil
+	}
+	for _, suffix := range []string{"_key", "_fkey", "_idx"} {
+		if columns, ok := strings.CutSuffix(rest, suffix); ok {
+			return matchColumns(columns, names.Fields)
+		}
+	}
+	return nil
+}
+
+// matchColumns splits s, column names joined by underscores, into the known
+// columns, or returns nil when s does not split into them
+func matchColumns(s string, known map[string]string) []string {
+	if _, ok := known[s]; ok {
+		return []string{s}
+	}
+	// Longest names first, for columns with underscores in their name
+	candidates := make([]string, 0, len(known))
+	for column := range known {
+		candidates = append(candidates, column)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if len(candidates[i]) != len(candidates[j]) {
+			return len(candidates[i]) > len(candidates[j])
+		}
+		return candidates[i] < candidates[j]
+	})
+	for _, column := range candidates {
+		if rest, ok := strings.CutPrefix(s, column+"_"); ok {
+			if columns := matchColumns(rest, known); columns != nil {
+				return append([]string{column}, columns...)
+			}
+		}
+	}
+	return nil
+}
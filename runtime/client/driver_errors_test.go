@@ -0,0 +1,184 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+var testSchemaNames = SchemaNames{
+	"users": {Model: "User", Fields: map[string]string{"id": "id", "email_address": "email", "first_name": "firstName", "last_name": "lastName"}},
+	"Post":  {Model: "Post", Fields: map[string]string{"id": "id", "author_id": "authorId"}},
+}
+
+// pgError has the fields of pgconn.PgError that are classified
+type pgError struct {
+	Code           string
+	Message        string
+	Detail         string
+	TableName      string
+	ColumnName     string
+	ConstraintName string
+}
+
+func (e *pgError) Error() string    { return e.Message }
+func (e *pgError) SQLState() string { return e.Code }
+
+// msError is an error of SQL Server, like mssql.Error
+type msError struct {
+	number  int32
+	message string
+}
+
+func (e msError) Error() string         { return e.message }
+func (e msError) SQLErrorNumber() int32 { return e.number }
+
+func TestClassifyDriverError(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		table      string
+		err        error
+		code       string
+		model      string
+		target     []string
+		constraint string
+	}{
+		{
+			name: "postgres unique",
+			err: &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "users_email_address_key"`,
+				Detail: "Key (email_address)=(a@b.c) already exists.", Table: "users", Constraint: "users_email_address_key"},
+			code: "P2002", model: "User", target: []string{"email"}, constraint: "users_email_address_key",
+		},
+		{
+			name: "postgres compound unique",
+			err: &pq.Error{Code: "23505", Detail: `Key (first_name, "last_name")=(a, b) already exists.`,
+				Table: "users", Constraint: "users_first_name_last_name_key"},
+			code: "P2002", model: "User", target: []string{"firstName", "lastName"}, constraint: "users_first_name_last_name_key",
+		},
+		{
+			name: "postgres foreign key of a deleted parent",
+			err: &pq.Error{Code: "23503", Detail: `Key (id)=(1) is still referenced from table "Post".`,
+				Table: "Post", Constraint: "Post_author_id_fkey"},
+			code: "P2003", model: "Post", target: []string{"authorId"}, constraint: "Post_author_id_fkey",
+		},
+		{
+			name: "postgres not null",
+			err:  &pq.Error{Code: "23502", Table: "users", Column: "first_name"},
+			code: "P2011", model: "User", target: []string{"firstName"},
+		},
+		{
+			name:  "postgres undefined table",
+			table: "users",
+			err:   &pq.Error{Code: "42P01", Message: `relation "Comment" does not exist`},
+			code:  "P2021",
+		},
+		{
+			name: "pgx unique",
+			err: fmt.Errorf("insert failed: %w", &pgError{Code: "23505", Detail: "Key (email_address)=(a@b.c) already exists.",
+				TableName: "users", ConstraintName: "users_email_address_key"}),
+			code: "P2002", model: "User", target: []string{"email"}, constraint: "users_email_address_key",
+		},
+		{
+			name: "mysql duplicate entry",
+			err:  &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.users_email_address_key'"},
+			code: "P2002", model: "User", target: []string{"email"}, constraint: "users_email_address_key",
+		},
+		{
+			name:  "mysql 5.7 duplicate entry",
+			table: "users",
+			err:   &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a-b' for key 'users_first_name_last_name_key'"},
+			code:  "P2002", model: "User", target: []string{"firstName", "lastName"}, constraint: "users_first_name_last_name_key",
+		},
+		{
+			name: "mysql foreign key",
+			err: &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`db`.`Post`, " +
+				"CONSTRAINT `Post_author_id_fkey` FOREIGN KEY (`author_id`) REFERENCES `users` (`id`))"},
+			code: "P2003", model: "Post", target: []string{"authorId"}, constraint: "Post_author_id_fkey",
+		},
+		{
+			name:  "mysql null",
+			table: "users",
+			err:   &mysql.MySQLError{Number: 1048, Message: "Column 'first_name' cannot be null"},
+			code:  "P2011", model: "User", target: []string{"firstName"},
+		},
+		{
+			name: "sqlite unique",
+			err:  sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique},
+			code: "P2002",
+		},
+		{
+			name:  "sqlite foreign key",
+			table: "Post",
+			err:   sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey},
+			code:  "P2003", model: "Post",
+		},
+		{
+			name: "sql server unique",
+			err: msError{2627, "Violation of UNIQUE KEY constraint 'users_email_address_key'. " +
+				"Cannot insert duplicate key in object 'dbo.users'. The duplicate key value is (a@b.c)."},
+			code: "P2002", model: "User", target: []string{"email"}, constraint: "users_email_address_key",
+		},
+		{
+			name: "sql server null",
+			err:  msError{515, "Cannot insert the value NULL into column 'first_name', table 'db.dbo.users'; column does not allow nulls. INSERT fails."},
+			code: "P2011", model: "User", target: []string{"firstName"},
+		},
+		{
+			name: "sql server deadlock",
+			err:  msError{1205, "Transaction (Process ID 52) was deadlocked on lock resources with another process"},
+			code: "P2034",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var prismaErr *PrismaError
+			if !errors.As(testSchemaNames.ClassifyError(tt.table, tt.err), &prismaErr) {
+				t.Fatal("error is not a PrismaError")
+			}
+			if prismaErr.Code != tt.code {
+				t.Errorf("code = %s, want %s (%s)", prismaErr.Code, tt.code, prismaErr.Message)
+			}
+			if prismaErr.Model != tt.model {
+				t.Errorf("model = %q, want %q", prismaErr.Model, tt.model)
+			}
+			if target, _ := prismaErr.Meta["target"].([]string); !reflect.DeepEqual(target, tt.target) {
+				t.Errorf("target = %v, want %v", prismaErr.Meta["target"], tt.target)
+			}
+			if constraint, _ := prismaErr.Meta["constraint"].(string); constraint != tt.constraint {
+				t.Errorf("constraint = %q, want %q", constraint, tt.constraint)
+			}
+			if !errors.Is(prismaErr, tt.err) {
+				t.Error("driver error is not the cause")
+			}
+		})
+	}
+}
+
+func TestClassifyDriverErrorSentinels(t *testing.T) {
+	unique := sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}
+	if !IsUniqueConstraint(fmt.Errorf("insert failed: %w", unique)) {
+		t.Error("unique violation is not a unique constraint error")
+	}
+	if !IsForeignKeyConstraint(&mysql.MySQLError{Number: 1451}) {
+		t.Error("MySQL 1451 is not a foreign key constraint error")
+	}
+
+	// The message of driver errors with codes that are not mapped is not
+	// classified
+	syntax := &pq.Error{Code: "42601", Message: `syntax error at or near "UNIQUE"`}
+	var prismaErr *PrismaError
+	if !errors.As(ClassifyError(syntax), &prismaErr) || prismaErr.Code != "P0000" {
+		t.Errorf("syntax error classified as %v", prismaErr)
+	}
+}
+
+func TestDriverMessage(t *testing.T) {
+	err := testSchemaNames.ClassifyError("", &pq.Error{Code: "23505", Detail: "Key (first_name, last_name)=(a, b) already exists.", Table: "users"})
+	if want := "[P2002] Unique constraint failed on the fields: (`firstName`, `lastName`)"; err.Error() != want {
+		t.Errorf("message = %q, want %q", err, want)
+	}
+}
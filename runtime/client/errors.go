@@ -0,0 +1,75 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/satishbabariya/prisma-go/runtime/dberrors"
+)
+
+// Error types for client operations
+var (
+	// ErrNotFound is returned when a record is not found
+	ErrNotFound = dberrors.ErrNotFound
+
+	// ErrUniqueConstraint is returned when a unique constraint is violated
+	ErrUniqueConstraint = dberrors.ErrUniqueConstraint
+
+	// ErrForeignKeyConstraint is returned when a foreign key constraint is violated
+	ErrForeignKeyConstraint = dberrors.ErrForeignKeyConstraint
+
+	// ErrNullConstraint is returned when a null constraint is violated
+	ErrNullConstraint = dberrors.ErrNullConstraint
+
+	// ErrValidationFailed is returned when input data fails validation
+	ErrValidationFailed = dberrors.ErrValidationFailed
+
+	// ErrTimeout is returned when an operation times out
+	ErrTimeout = dberrors.ErrTimeout
+
+	// ErrCanceled is returned when an operation is canceled
+	ErrCanceled = dberrors.ErrCanceled
+)
+
+// PrismaError is an error classified with a Prisma error code, e.g. P2002
+// for a unique constraint violation
+type PrismaError = dberrors.PrismaError
+
+// NewPrismaError creates a new PrismaError
+func NewPrismaError(code, message string) *PrismaError {
+	return dberrors.NewPrismaError(code, message)
+}
+
+// NewValidationError returns a P2009 error for invalid input to field
+func NewValidationError(model, field, message string) *PrismaError {
+	return NewPrismaError("P2009", message).
+		WithCause(ErrValidationFailed).
+		WithModel(model).
+		WithField(field)
+}
+
+// ClassifyError classifies a database error into a PrismaError, see
+// dberrors.ClassifyError, and SchemaNames for errors naming models and
+// fields
+func ClassifyError(err error) error {
+	return dberrors.ClassifyError(err)
+}
+
+// IsNotFound checks if an error is a not found error
+func IsNotFound(err error) bool {
+	return errors.Is(ClassifyError(err), ErrNotFound)
+}
+
+// IsUniqueConstraint checks if an error is a unique constraint violation
+func IsUniqueConstraint(err error) bool {
+	return errors.Is(ClassifyError(err), ErrUniqueConstraint)
+}
+
+// IsForeignKeyConstraint checks if an error is a foreign key constraint violation
+func IsForeignKeyConstraint(err error) bool {
+	return errors.Is(ClassifyError(err), ErrForeignKeyConstraint)
+}
+
+// IsValidationError checks if an error is a validation failure
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrValidationFailed)
+}
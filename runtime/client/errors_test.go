@@ -0,0 +1,20 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestValidationErrorFields(t *testing.T) {
+	err := NewValidationError("User", "email", "email is required")
+	if err.Model != "User" || err.Field != "email" {
+		t.Errorf("got model %q field %q", err.Model, err.Field)
+	}
+	if !IsValidationError(fmt.Errorf("create: %w", err)) {
+		t.Error("wrapped validation error is not recognised")
+	}
+	if !errors.Is(ClassifyError(err), ErrValidationFailed) {
+		t.Error("classifying a validation error changed its class")
+	}
+}
@@ -3,7 +3,10 @@ package client
 
 import (
 	"context"
+	"reflect"
 	"time"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
 )
 
 // ExtensionContext provides context for extension hooks
@@ -92,12 +95,12 @@ func (ec *ExtensionChain) ExecuteQuery(ctx context.Context, model string, operat
 		ext := ec.extensions[i]
 		if ext.AfterQuery != nil {
 			if err := ext.AfterQuery(extCtx, func() error { return err }); err != nil {
-				return result, err
+				return extCtx.Result, err
 			}
 		}
 	}
 
-	return result, err
+	return extCtx.Result, err
 }
 
 // ExecuteMutation executes a mutation operation through the extension chain
@@ -131,15 +134,48 @@ func (ec *ExtensionChain) ExecuteMutation(ctx context.Context, model string, ope
 		ext := ec.extensions[i]
 		if ext.AfterMutation != nil {
 			if err := ext.AfterMutation(extCtx, func() error { return err }); err != nil {
-				return result, err
+				return extCtx.Result, err
 			}
 		}
 	}
 
-	return result, err
+	return extCtx.Result, err
+}
+
+// mutations are the operations run with ExecuteMutation
+var mutations = map[string]bool{
+	"create": true, "createMany": true, "update": true, "updateMany": true,
+	"upsert": true, "delete": true, "deleteMany": true,
+}
+
+// middleware returns the middleware running the operations of the generated
+// client through the chain. ExtensionContext.Args is the *executor.Args of
+// the operation and Result the pointer to its result; a result replaced by
+// an After hook becomes the result of the operation.
+func (ec *ExtensionChain) middleware() executor.Middleware {
+	return func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		execute := ec.ExecuteQuery
+		if mutations[call.Name] {
+			execute = ec.ExecuteMutation
+		}
+		result, err := execute(ctx, call.Model, call.Name, call.Args, func() (interface{}, error) {
+			err := next(ctx)
+			return call.Result, err
+		})
+		if err != nil || result == nil {
+			return err
+		}
+		if reflect.TypeOf(result).Comparable() && result == call.Result {
+			return nil
+		}
+		return call.SetResult(result)
+	}
 }
 
 // LoggingExtension creates an extension that logs operations
+//
+// Deprecated: use PrismaClient.EnableLogging, which logs structured events
+// with slog and redacts sensitive parameters.
 func LoggingExtension(logger func(format string, args ...interface{})) Extension {
 	return Extension{
 		Name: "logging",
@@ -0,0 +1,400 @@
+// Package client provides structured logging with log/slog.
+package client
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+)
+
+// LogEvent is a kind of event logged by the client, like the levels of the
+// log option of Prisma
+type LogEvent string
+
+// Events logged by the client
+const (
+	LogQuery LogEvent = "query" // SQL statements, with their parameters
+	LogInfo  LogEvent = "info"  // Operations and transactions that succeeded
+	LogWarn  LogEvent = "warn"  // Operations failing with a request error, e.g. P2002 or P2025
+	LogError LogEvent = "error" // Other failures, e.g. of the connection
+)
+
+// DefaultLogLevels are the levels of the events logged by default
+var DefaultLogLevels = map[LogEvent]slog.Level{
+	LogQuery: slog.LevelDebug,
+	LogInfo:  slog.LevelInfo,
+	LogWarn:  slog.LevelWarn,
+	LogError: slog.LevelError,
+}
+
+// Attributes of logged events
+const (
+	LogAttrQuery        = "query"
+	LogAttrParams       = "params"
+	LogAttrDuration     = "duration"
+	LogAttrModel        = "model"
+	LogAttrOperation    = "operation"
+	LogAttrTraceID      = "trace_id"
+	LogAttrRowsAffected = "rows_affected"
+	LogAttrError        = "error"
+)
+
+// Redacted replaces the parameters of sensitive fields in logs
+const Redacted = "[redacted]"
+
+// Redactor returns the value logged for a parameter bound to field of
+// model. model and field are empty when the column of the parameter is not
+// known, e.g. for a LIMIT; field is the column when its model is unknown.
+type Redactor func(model, field string, value interface{}) interface{}
+
+// RedactFields redacts the parameters of the fields named Model.field, or
+// field for the field of any model
+func RedactFields(fields ...string) Redactor {
+	redacted := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		redacted[field] = true
+	}
+	return func(model, field string, value interface{}) interface{} {
+		if field != "" && (redacted[field] || redacted[model+"."+field]) {
+			return Redacted
+		}
+		return value
+	}
+}
+
+// RedactAll redacts every parameter
+func RedactAll(model, field string, value interface{}) interface{} {
+	return Redacted
+}
+
+// LogConfig configures the structured logging of a client
+type LogConfig struct {
+	// Logger receives the events, slog.Default() by default
+	Logger *slog.Logger
+
+	// Levels are the events logged and their level, DefaultLogLevels by
+	// default. Events missing from Levels are not logged.
+	Levels map[LogEvent]slog.Level
+
+	// Redact redacts the parameters of statements. The parameters of the
+	// fields annotated /// @sensitive in the schema are redacted whatever
+	// it returns.
+	Redact Redactor
+}
+
+// EnableLogging logs the events of the client with slog: the statements of
+// its operations and raw queries as query events, with their parameters,
+// duration, model, operation, trace id and rows affected, and operations
+// and transactions as info, warn or error events depending on their
+// outcome. It replaces the configuration of logging enabled before.
+func (c *PrismaClient) EnableLogging(config LogConfig) {
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.Levels == nil {
+		config.Levels = DefaultLogLevels
+	}
+
+	c.loggerOnce.Do(func() {
+		c.logger = &queryLogger{client: c}
+		c.Observe(c.logger)
+	})
+	c.logger.setConfig(&config)
+}
+
+// DisableLogging stops logging the events of the client
+func (c *PrismaClient) DisableLogging() {
+	if c.logger != nil {
+		c.logger.setConfig(nil)
+	}
+}
+
+// traceIDKey is the context key of the trace id
+type traceIDKey struct{}
+
+// WithTraceID returns a context whose events are logged with traceID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace id set by WithTraceID, or else the
+// id of the trace of the OpenTelemetry span of ctx
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	if id, ok := ctx.Value(traceIDKey{}).(string); ok {
+		return id, true
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String(), true
+	}
+	return "", false
+}
+
+// queryLogger is the observer logging the events of a client
+type queryLogger struct {
+	client *PrismaClient
+
+	mu     sync.Mutex
+	config *LogConfig
+}
+
+func (l *queryLogger) setConfig(config *LogConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = config
+}
+
+func (l *queryLogger) getConfig() *LogConfig {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.config
+}
+
+// StartOperation logs the outcome of op when it ends
+func (l *queryLogger) StartOperation(ctx context.Context, op *executor.Operation) (context.Context, func(err error)) {
+	config := l.getConfig()
+	if config == nil {
+		return ctx, nil
+	}
+	start := time.Now()
+	return ctx, func(err error) {
+		attrs := []slog.Attr{
+			slog.String(LogAttrModel, op.Model),
+			slog.String(LogAttrOperation, op.Name),
+			slog.Duration(LogAttrDuration, time.Since(start)),
+		}
+		l.logOutcome(ctx, config, op.Model+"."+op.Name, err, attrs)
+	}
+}
+
+// StartStatement logs stmt when it ends, and its error when it runs
+// outside of an operation
+func (l *queryLogger) StartStatement(ctx context.Context, stmt *executor.Statement) (context.Context, func(err error)) {
+	config := l.getConfig()
+	if config == nil {
+		return ctx, nil
+	}
+	start := time.Now()
+	return ctx, func(err error) {
+		d := time.Since(start)
+		if level, ok := config.Levels[LogQuery]; ok && config.Logger.Enabled(ctx, level) {
+			attrs := []slog.Attr{
+				slog.String(LogAttrQuery, stmt.SQL),
+				slog.Any(LogAttrParams, l.redactParams(config, stmt)),
+				slog.Duration(LogAttrDuration, d),
+			}
+			if op := stmt.Operation; op != nil {
+				attrs = append(attrs, slog.String(LogAttrModel, op.Model), slog.String(LogAttrOperation, op.Name))
+			}
+			if stmt.RowsAffected >= 0 {
+				attrs = append(attrs, slog.Int64(LogAttrRowsAffected, stmt.RowsAffected))
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any(LogAttrError, err))
+			}
+			l.log(ctx, config, level, "query", attrs)
+		}
+		// The errors of operations are logged when they end
+		if err != nil && stmt.Operation == nil {
+			l.logOutcome(ctx, config, "query", err, []slog.Attr{slog.Duration(LogAttrDuration, d)})
+		}
+	}
+}
+
+// StartTransaction logs the outcome of a transaction when it ends
+func (l *queryLogger) StartTransaction(ctx context.Context, system string) (context.Context, func(err error)) {
+	config := l.getConfig()
+	if config == nil {
+		return ctx, nil
+	}
+	start := time.Now()
+	return ctx, func(err error) {
+		l.logOutcome(ctx, config, "transaction", err, []slog.Attr{slog.Duration(LogAttrDuration, time.Since(start))})
+	}
+}
+
+// logOutcome logs the end of an operation or transaction as an info event,
+// or as a warn or error event when it failed
+func (l *queryLogger) logOutcome(ctx context.Context, config *LogConfig, msg string, err error, attrs []slog.Attr) {
+	event := LogInfo
+	if err != nil {
+		event = LogError
+		if requestError(err) {
+			event = LogWarn
+		}
+		attrs = append(attrs, slog.Any(LogAttrError, err))
+	}
+	if level, ok := config.Levels[event]; ok {
+		l.log(ctx, config, level, msg, attrs)
+	}
+}
+
+// log logs msg with attrs and the trace id of ctx
+func (l *queryLogger) log(ctx context.Context, config *LogConfig, level slog.Level, msg string, attrs []slog.Attr) {
+	if id, ok := TraceIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String(LogAttrTraceID, id))
+	}
+	config.Logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// requestError reports whether err is caused by the request rather than
+// by the database or the client, e.g. a record that does not exist or a
+// unique constraint violation
+func requestError(err error) bool {
+	var prismaErr *PrismaError
+	if errors.As(err, &prismaErr) {
+		return strings.HasPrefix(prismaErr.Code, "P2")
+	}
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, ErrNotFound)
+}
+
+// redactParams returns the parameters of stmt to log, redacting those of
+// sensitive fields and those config.Redact redacts. On tables with
+// sensitive fields, parameters whose column is not known are redacted too.
+func (l *queryLogger) redactParams(config *LogConfig, stmt *executor.Statement) []interface{} {
+	params := make([]interface{}, len(stmt.Args))
+	copy(params, stmt.Args)
+
+	var names TableNames
+	if op := stmt.Operation; op != nil {
+		names, _ = l.client.schemaNames.table(op.Table)
+	}
+	sensitive := make(map[string]bool, len(names.Sensitive))
+	for _, column := range names.Sensitive {
+		sensitive[strings.ToLower(column)] = true
+	}
+
+	columns := stmt.Columns
+	if columns == nil {
+		columns = paramColumns(stmt.SQL, len(params))
+	}
+	for i, column := range columns {
+		if dot := strings.LastIndexByte(column, '.'); dot >= 0 {
+			column = column[dot+1:]
+		}
+		if sensitive[strings.ToLower(column)] || (column == "" && len(sensitive) > 0) {
+			params[i] = Redacted
+			continue
+		}
+		if config.Redact == nil {
+			continue
+		}
+		model, field := names.Model, column
+		if f, ok := names.Fields[column]; ok {
+			field = f
+		}
+		if column == "" {
+			model = ""
+		}
+		params[i] = config.Redact(model, field, params[i])
+	}
+	return params
+}
+
+var (
+	// sqlPlaceholders matches the placeholders of a statement, $1, ? or
+	// @p1, and the string literals that may contain them
+	sqlPlaceholders = regexp.MustCompile(`'(?:[^']|'')*'|\$\d+|@p\d+|\?`)
+
+	// sqlComparedColumn matches the column compared to a placeholder
+	// ending the text before it, e.g. "email" = or [id] IN (?,
+	sqlComparedColumn = regexp.MustCompile(`(?i)([\w.]+|"[^"]+"|\[[^\]]+\]|` + "`[^`]+`" + `)\s*(?:=|<>|!=|<=|>=|<|>|\bNOT\s+LIKE|\bI?LIKE|\b(?:NOT\s+)?IN\s*\((?:\s*(?:\?|\$\d+|@p\d+)\s*,)*)\s*$`)
+
+	// sqlInsertColumns matches the columns and values of an INSERT
+	sqlInsertColumns = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+\S+\s*\(([^)]*)\)\s*VALUES\s*`)
+)
+
+// paramColumns returns the column each of the n parameters of query is
+// bound to: the column of an INSERT or SET, or the column it is compared
+// to. The column of a parameter that is not bound to one is empty.
+func paramColumns(query string, n int) []string {
+	columns := make([]string, n)
+	var insert []string
+	valuesEnd := -1
+	if m := sqlInsertColumns.FindStringSubmatchIndex(query); m != nil {
+		for _, column := range strings.Split(query[m[2]:m[3]], ",") {
+			insert = append(insert, unquoteIdentifier(strings.TrimSpace(column)))
+		}
+		valuesEnd = valuesClauseEnd(query, m[1])
+	}
+
+	next := 0
+	inserted := 0
+	for _, m := range sqlPlaceholders.FindAllStringIndex(query, -1) {
+		placeholder := query[m[0]:m[1]]
+		if placeholder[0] == '\'' {
+			continue
+		}
+		i := next
+		switch placeholder[0] {
+		case '$':
+			i, _ = strconv.Atoi(placeholder[1:])
+			i--
+		case '@':
+			i, _ = strconv.Atoi(placeholder[2:])
+			i--
+		default:
+			next++
+		}
+		if i < 0 || i >= n {
+			continue
+		}
+		if len(insert) > 0 && m[0] < valuesEnd {
+			columns[i] = insert[inserted%len(insert)]
+			inserted++
+			continue
+		}
+		if c := sqlComparedColumn.FindStringSubmatch(query[:m[0]]); c != nil {
+			column := c[1]
+			if dot := strings.LastIndexByte(column, '.'); dot >= 0 && column[0] != '"' && column[0] != '`' && column[0] != '[' {
+				column = column[dot+1:]
+			}
+			columns[i] = unquoteIdentifier(column)
+		}
+	}
+	return columns
+}
+
+// valuesClauseEnd returns the end of the VALUES clause of an INSERT
+// starting at start: the offset following its last tuple
+func valuesClauseEnd(query string, start int) int {
+	depth := 0
+	end := start
+	for i := start; i < len(query); i++ {
+		switch query[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				end = i + 1
+			}
+		case '\'':
+			if j := strings.IndexByte(query[i+1:], '\''); j >= 0 {
+				i += j + 1
+			}
+		default:
+			if depth == 0 && query[i] != ',' && query[i] != ' ' && query[i] != '\n' && query[i] != '\t' {
+				return end
+			}
+		}
+	}
+	return end
+}
+
+// unquoteIdentifier removes the quotes of a quoted identifier
+func unquoteIdentifier(id string) string {
+	if len(id) >= 2 && ((id[0] == '"' || id[0] == '`') && id[len(id)-1] == id[0] || id[0] == '[' && id[len(id)-1] == ']') {
+		return id[1 : len(id)-1]
+	}
+	return id
+}
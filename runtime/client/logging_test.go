@@ -0,0 +1,189 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+)
+
+// loggingSchema declares users with a sensitive password
+var loggingSchema = SchemaNames{
+	"users": {
+		Model:     "User",
+		Fields:    map[string]string{"id": "id", "email": "email", "password_hash": "passwordHash"},
+		Sensitive: []string{"password_hash"},
+	},
+}
+
+type loggingUser struct {
+	ID           int    `db:"id"`
+	Email        string `db:"email"`
+	PasswordHash string `db:"password_hash"`
+}
+
+// newLoggingClient returns a SQLite client with a users table logging to
+// the returned buffer as JSON, and an executor of its generated client
+func newLoggingClient(t *testing.T, config LogConfig) (*PrismaClient, *executor.Executor, *bytes.Buffer) {
+	t.Helper()
+	c := newTestClient(t, `CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT UNIQUE, password_hash TEXT)`)
+	exec := newTestExecutor(c, loggingSchema)
+
+	var buf bytes.Buffer
+	config.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c.EnableLogging(config)
+	return c, exec, &buf
+}
+
+// logRecords decodes the JSON records logged to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	buf.Reset()
+	return records
+}
+
+func TestLogging(t *testing.T) {
+	_, exec, buf := newLoggingClient(t, LogConfig{Redact: RedactFields("User.email")})
+	ctx := WithTraceID(context.Background(), "trace-1")
+
+	user := &loggingUser{Email: "a@b.c", PasswordHash: "secret"}
+	if _, err := exec.Create(ctx, "users", user); err != nil {
+		t.Fatal(err)
+	}
+	records := logRecords(t, buf)
+	if len(records) < 2 {
+		t.Fatalf("records = %v, want the statements and the operation", records)
+	}
+	insert := records[0]
+	if insert["level"] != "DEBUG" || insert["msg"] != "query" || !strings.HasPrefix(insert["query"].(string), "INSERT") {
+		t.Fatalf("first record = %v, want the INSERT", insert)
+	}
+	for _, param := range insert["params"].([]interface{}) {
+		if param == "secret" || param == "a@b.c" {
+			t.Errorf("params = %v, want the email and password redacted", insert["params"])
+		}
+	}
+	if insert["model"] != "User" || insert["operation"] != "create" || insert["trace_id"] != "trace-1" || insert["rows_affected"] != 1.0 {
+		t.Errorf("INSERT record = %v", insert)
+	}
+	op := records[len(records)-1]
+	if op["level"] != "INFO" || op["msg"] != "User.create" || op["trace_id"] != "trace-1" {
+		t.Errorf("operation record = %v", op)
+	}
+
+	// A unique constraint violation is a warning
+	if _, err := exec.Create(ctx, "users", &loggingUser{Email: "a@b.c"}); err == nil {
+		t.Fatal("duplicate email created")
+	}
+	records = logRecords(t, buf)
+	op = records[len(records)-1]
+	if op["level"] != "WARN" || op["msg"] != "User.create" || !strings.Contains(op["error"].(string), "P2002") {
+		t.Errorf("operation record = %v, want a warning", op)
+	}
+}
+
+func TestLoggingLevels(t *testing.T) {
+	c, exec, buf := newLoggingClient(t, LogConfig{Levels: map[LogEvent]slog.Level{LogError: slog.LevelError}})
+	ctx := context.Background()
+
+	where := sqlgen.NewWhereClause()
+	where.AddCondition(sqlgen.Condition{Field: "password_hash", Operator: "=", Value: "secret"})
+	if _, err := exec.Count(ctx, "users", where); err != nil {
+		t.Fatal(err)
+	}
+	if records := logRecords(t, buf); len(records) != 0 {
+		t.Errorf("records = %v, want only errors logged", records)
+	}
+
+	c.RawExec(ctx, `SELECT * FROM missing`)
+	records := logRecords(t, buf)
+	if len(records) != 1 || records[0]["level"] != "ERROR" || records[0]["msg"] != "query" {
+		t.Errorf("records = %v, want the error of the raw query", records)
+	}
+
+	c.DisableLogging()
+	c.RawExec(ctx, `SELECT * FROM missing`)
+	if records := logRecords(t, buf); len(records) != 0 {
+		t.Errorf("records = %v after logging was disabled", records)
+	}
+}
+
+func TestParamColumns(t *testing.T) {
+	tests := []struct {
+		query string
+		n     int
+		want  []string
+	}{
+		{`INSERT INTO "users" ("email", "password_hash") VALUES (?, ?), (?, ?) RETURNING *`, 4, []string{"email", "password_hash", "email", "password_hash"}},
+		{`UPDATE users SET email = $2 WHERE "users"."id" = $1`, 2, []string{"id", "email"}},
+		{`SELECT * FROM users WHERE id IN (?, ?) AND name LIKE ? AND note = '?' LIMIT ?`, 4, []string{"id", "id", "name", ""}},
+		{"SELECT * FROM `users` WHERE `email` <> ?", 1, []string{"email"}},
+		{`UPDATE [users] SET [password_hash] = @p1 WHERE [users].[id] IN (@p2, @p3)`, 3, []string{"password_hash", "id", "id"}},
+		{`INSERT INTO [users] ([email], [password_hash]) VALUES (@p1, @p2) OUTPUT INSERTED.*`, 2, []string{"email", "password_hash"}},
+	}
+	for _, tt := range tests {
+		if got := paramColumns(tt.query, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("paramColumns(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRedactParamsSQLServer(t *testing.T) {
+	l := &queryLogger{client: &PrismaClient{schemaNames: loggingSchema}}
+	op := &executor.Operation{Model: "User", Table: "users", Name: "update"}
+
+	where := sqlgen.NewWhereClause()
+	where.AddCondition(sqlgen.Condition{Field: "id", Operator: "=", Value: 1})
+	query := sqlgen.NewGenerator("sqlserver").GenerateUpdate("users", map[string]interface{}{"email": "a@b.c", "password_hash": "secret"}, where)
+
+	tests := []struct {
+		name string
+		stmt *executor.Statement
+		want map[interface{}]interface{} // Logged value of each argument
+	}{
+		{
+			name: "columns of the executor",
+			stmt: &executor.Statement{Operation: op, SQL: query.SQL, Args: query.Args, Columns: query.Columns},
+			want: map[interface{}]interface{}{"a@b.c": "a@b.c", "secret": Redacted, 1: 1},
+		},
+		{
+			name: "columns of the SQL",
+			stmt: &executor.Statement{Operation: op, SQL: `UPDATE [users] SET [password_hash] = @p1 WHERE [id] = @p2`, Args: []interface{}{"secret", 1}},
+			want: map[interface{}]interface{}{"secret": Redacted, 1: 1},
+		},
+		{
+			// The columns of a MERGE are not known, so all of its
+			// arguments are redacted on a table with sensitive columns
+			name: "unknown columns",
+			stmt: &executor.Statement{Operation: op, SQL: `MERGE [users] AS target USING (SELECT @p1, @p2) AS source ([email], [password_hash])`, Args: []interface{}{"a@b.c", "secret"}},
+			want: map[interface{}]interface{}{"a@b.c": Redacted, "secret": Redacted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := l.redactParams(&LogConfig{}, tt.stmt)
+			for i, arg := range tt.stmt.Args {
+				if params[i] != tt.want[arg] {
+					t.Errorf("%v is logged as %v, want %v (%s)", arg, params[i], tt.want[arg], tt.stmt.SQL)
+				}
+			}
+		})
+	}
+}
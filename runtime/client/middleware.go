@@ -16,7 +16,10 @@ type QueryEvent struct {
 	End      time.Time
 }
 
-// Middleware is a function that intercepts queries
+// Middleware is a function that intercepts raw statements. It observes
+// them; middleware of the operations of the generated client, which can
+// change their arguments, SQL and results, is added with
+// PrismaClient.Intercept.
 type Middleware func(ctx context.Context, event *QueryEvent, next func() error) error
 
 // PrismaClientWithMiddleware wraps PrismaClient with middleware support
@@ -72,6 +75,9 @@ func (c *PrismaClientWithMiddleware) executeWithMiddleware(ctx context.Context,
 }
 
 // LoggingMiddleware creates a middleware that logs queries
+//
+// Deprecated: use PrismaClient.EnableLogging, which logs structured events
+// with slog and redacts sensitive parameters.
 func LoggingMiddleware(logger func(format string, args ...interface{})) Middleware {
 	return func(ctx context.Context, event *QueryEvent, next func() error) error {
 		logger("Executing query: %s with args: %v", event.Query, event.Args)
@@ -0,0 +1,251 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+)
+
+type middlewareNote struct {
+	ID     int    `db:"id"`
+	Tenant string `db:"tenant"`
+	Title  string `db:"title"`
+}
+
+// newMiddlewareClient returns a SQLite client with notes of two tenants,
+// and an executor of its generated client
+func newMiddlewareClient(t *testing.T) (*PrismaClient, *executor.Executor) {
+	t.Helper()
+	c := newTestClient(t,
+		`CREATE TABLE notes (id INTEGER PRIMARY KEY, tenant TEXT, title TEXT)`,
+		`INSERT INTO notes (tenant, title) VALUES ('acme', 'a'), ('acme', 'b'), ('globex', 'c')`,
+	)
+	exec := newTestExecutor(c, SchemaNames{"notes": {Model: "Note"}})
+	return c, exec
+}
+
+// statementRecorder is an observer called with each statement
+type statementRecorder func(stmt *executor.Statement)
+
+func (r statementRecorder) StartOperation(ctx context.Context, op *executor.Operation) (context.Context, func(error)) {
+	return ctx, nil
+}
+
+func (r statementRecorder) StartStatement(ctx context.Context, stmt *executor.Statement) (context.Context, func(error)) {
+	r(stmt)
+	return ctx, nil
+}
+
+// tenantKey is the context key of the tenant of the tests
+type tenantKey struct{}
+
+// tenantScope scopes the operations on notes to the tenant of the context
+func tenantScope(ctx context.Context, call *executor.Call, next executor.Next) error {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok || call.Table != "notes" {
+		return next(ctx)
+	}
+	where := sqlgen.NewWhereClause()
+	where.AddCondition(sqlgen.Condition{Field: "tenant", Operator: "=", Value: tenant})
+	if call.Args.Where != nil {
+		where.AddGroup(call.Args.Where)
+	}
+	call.Args.Where = where
+	return next(ctx)
+}
+
+func TestMiddlewareChangesArgs(t *testing.T) {
+	c, exec := newMiddlewareClient(t)
+	c.Intercept(tenantScope)
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	var notes []middlewareNote
+	if err := exec.FindMany(ctx, "notes", nil, nil, nil, nil, nil, nil, &notes); err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 {
+		t.Errorf("found %d notes of acme, want 2", len(notes))
+	}
+	count, err := exec.Count(ctx, "notes", nil)
+	if err != nil || count != 2 {
+		t.Errorf("count = %d (%v), want 2", count, err)
+	}
+
+	// Writes are scoped too
+	deleted, err := exec.DeleteMany(ctx, "notes", nil)
+	if err != nil || deleted != 2 {
+		t.Errorf("deleted %d notes (%v), want 2", deleted, err)
+	}
+	if count, _ := exec.Count(context.Background(), "notes", nil); count != 1 {
+		t.Errorf("%d notes left, want the one of globex", count)
+	}
+}
+
+func TestMiddlewareRewritesQuery(t *testing.T) {
+	c, exec := newMiddlewareClient(t)
+	var statements []string
+	c.Observe(statementRecorder(func(stmt *executor.Statement) { statements = append(statements, stmt.SQL) }))
+	c.Intercept(func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		call.RewriteQuery(func(query *sqlgen.Query) (*sqlgen.Query, error) {
+			return &sqlgen.Query{SQL: "/* " + call.Model + "." + call.Name + " */ " + query.SQL, Args: query.Args}, nil
+		})
+		return next(ctx)
+	})
+
+	where := sqlgen.NewWhereClause()
+	where.AddCondition(sqlgen.Condition{Field: "id", Operator: "=", Value: 1})
+	var note middlewareNote
+	if err := exec.Update(context.Background(), "notes", map[string]interface{}{"title": "z"}, where, &note); err != nil {
+		t.Fatal(err)
+	}
+	if note.Title != "z" {
+		t.Errorf("updated note = %+v", note)
+	}
+
+	// The UPDATE is rewritten, but not the SELECT querying the note back
+	if len(statements) != 2 || !strings.HasPrefix(statements[0], "/* Note.update */ UPDATE") || strings.HasPrefix(statements[1], "/*") {
+		t.Errorf("statements = %q", statements)
+	}
+
+	failing := errors.New("rejected")
+	c.Intercept(func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		call.RewriteQuery(func(query *sqlgen.Query) (*sqlgen.Query, error) { return nil, failing })
+		return next(ctx)
+	})
+	if err := exec.Delete(context.Background(), "notes", where); !errors.Is(err, failing) {
+		t.Errorf("delete with a failing rewrite = %v", err)
+	}
+}
+
+func TestMiddlewareShortCircuits(t *testing.T) {
+	c, exec := newMiddlewareClient(t)
+	ran := 0
+	c.Observe(statementRecorder(func(*executor.Statement) { ran++ }))
+	c.Intercept(func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		switch call.Name {
+		case "findMany":
+			return call.SetResult([]middlewareNote{{ID: 42, Title: "cached"}})
+		case "count":
+			return call.SetResult(int64(7))
+		}
+		return next(ctx)
+	})
+
+	var notes []middlewareNote
+	if err := exec.FindMany(context.Background(), "notes", nil, nil, nil, nil, nil, nil, &notes); err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || notes[0].ID != 42 {
+		t.Errorf("notes = %+v, want the cached one", notes)
+	}
+	if count, err := exec.Count(context.Background(), "notes", nil); err != nil || count != 7 {
+		t.Errorf("count = %d (%v), want 7", count, err)
+	}
+	if ran != 0 {
+		t.Errorf("%d statements ran", ran)
+	}
+}
+
+func TestMiddlewareTransformsResults(t *testing.T) {
+	c, exec := newMiddlewareClient(t)
+	c.Intercept(func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		if err := next(ctx); err != nil {
+			return err
+		}
+		if notes, ok := executor.ResultOf[[]middlewareNote](call); ok {
+			for i := range *notes {
+				(*notes)[i].Title = strings.ToUpper((*notes)[i].Title)
+			}
+		}
+		if note, ok := executor.ResultOf[middlewareNote](call); ok {
+			note.Tenant = "hidden"
+		}
+		return nil
+	})
+
+	var notes []middlewareNote
+	if err := exec.FindMany(context.Background(), "notes", nil, nil, nil, nil, nil, nil, &notes); err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 3 || notes[0].Title != "A" {
+		t.Errorf("notes = %+v, want upper case titles", notes)
+	}
+	created, err := exec.Create(context.Background(), "notes", &middlewareNote{Tenant: "acme", Title: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note, ok := created.(*middlewareNote); !ok || note.Tenant != "hidden" {
+		t.Errorf("created = %+v", created)
+	}
+}
+
+func TestExtensionsRunInPipeline(t *testing.T) {
+	c, exec := newMiddlewareClient(t)
+	var operations []string
+	c.UseExtension(TimingExtension(func(model, operation string, _ time.Duration) {
+		operations = append(operations, model+"."+operation)
+	}))
+	c.UseExtension(ResultTransformationExtension(func(ctx *ExtensionContext, result interface{}) interface{} {
+		if ctx.Operation == "count" {
+			return int64(100)
+		}
+		return result
+	}))
+
+	var notes []middlewareNote
+	if err := exec.FindMany(context.Background(), "notes", nil, nil, nil, nil, nil, nil, &notes); err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 3 {
+		t.Errorf("found %d notes, want 3", len(notes))
+	}
+	if count, err := exec.Count(context.Background(), "notes", nil); err != nil || count != 100 {
+		t.Errorf("count = %d (%v), want the transformed 100", count, err)
+	}
+	if strings.Join(operations, ",") != "Note.findMany,Note.count" {
+		t.Errorf("operations = %v", operations)
+	}
+}
+
+func TestDeriveIsolatesMiddleware(t *testing.T) {
+	c, exec := newMiddlewareClient(t)
+	var operations []string
+	c.Intercept(func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		operations = append(operations, "base."+call.Name)
+		return next(ctx)
+	})
+
+	derived := c.Derive()
+	derived.Intercept(tenantScope)
+	derivedExec := exec.Derive(derived.Pipeline())
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	// The derived client runs the middleware of c, then its own
+	if count, err := derivedExec.Count(ctx, "notes", nil); err != nil || count != 2 {
+		t.Errorf("derived count = %d (%v), want 2", count, err)
+	}
+	if count, err := exec.Count(ctx, "notes", nil); err != nil || count != 3 {
+		t.Errorf("base count = %d (%v), want all 3 notes", count, err)
+	}
+
+	// Middleware added to c later runs on the derived client too
+	c.Intercept(func(ctx context.Context, call *executor.Call, next executor.Next) error {
+		operations = append(operations, "late."+call.Name)
+		return next(ctx)
+	})
+	var notes []middlewareNote
+	if err := derivedExec.FindMany(ctx, "notes", nil, nil, nil, nil, nil, nil, &notes); err != nil || len(notes) != 2 {
+		t.Errorf("derived findMany = %+v (%v)", notes, err)
+	}
+	if strings.Join(operations, ",") != "base.count,base.count,base.findMany,late.findMany" {
+		t.Errorf("operations = %v", operations)
+	}
+	if derived.DB() != c.DB() {
+		t.Error("the derived client does not share the connection")
+	}
+}
@@ -0,0 +1,401 @@
+// Package client provides the detection of N+1 queries.
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+)
+
+// NPlusOne is a statement shape that ran more times than the threshold of
+// the detector within a query scope, each time with different arguments,
+// typically because a relation is loaded record by record in a loop
+type NPlusOne struct {
+	Model     string // Model and Operation that ran the statement, empty for raw queries
+	Operation string
+	SQL       string
+	Count     int // Times the statement ran with different arguments
+
+	// Relation is the relation loaded record by record, e.g. User.posts,
+	// empty when the filter of the statement is not a key of a relation.
+	// Include is the call loading it with the query of Owner instead, e.g.
+	// Include().Posts() on User, empty when Owner has no field for it.
+	Relation string
+	Owner    string
+	Include  string
+
+	// CallSite is the file:line of the call of the client that ran the
+	// statement
+	CallSite string
+}
+
+// Error describes the N+1 query and how to avoid it
+func (n NPlusOne) Error() string {
+	var b strings.Builder
+	b.WriteString("N+1 query: ")
+	if n.Model != "" {
+		fmt.Fprintf(&b, "%s.%s ", n.Model, n.Operation)
+	}
+	fmt.Fprintf(&b, "ran %d times with different arguments", n.Count)
+	if n.CallSite != "" {
+		fmt.Fprintf(&b, " at %s", n.CallSite)
+	}
+	switch {
+	case n.Include != "":
+		fmt.Fprintf(&b, "; load %s with %s on the query of %s instead", n.Relation, n.Include, n.Owner)
+	case n.Relation != "":
+		fmt.Fprintf(&b, "; declare the list of %s on %s to load it with Include on the query of %s instead", n.Model, n.Owner, n.Owner)
+	}
+	return b.String()
+}
+
+// NPlusOneConfig configures the detection of N+1 queries
+type NPlusOneConfig struct {
+	// Threshold is the number of times a statement shape may run with
+	// different arguments within a query scope, 5 by default
+	Threshold int
+
+	// Strict fails the statement crossing the threshold with the NPlusOne
+	// before it runs, and so the operation running it, so that tests
+	// exercising N+1 queries fail. It is meant for tests and development
+	// only.
+	Strict bool
+
+	// OnDetect receives the N+1 queries, once per statement shape and query
+	// scope. By default they are logged with slog at the warning level.
+	OnDetect func(NPlusOne)
+}
+
+// EnableNPlusOneDetection detects N+1 queries: SELECT statements of the
+// same shape running more than config.Threshold times with different
+// arguments within a query scope started by WithQueryScope. Statements run
+// outside of a scope are not counted. The detection is meant for
+// development, as it fingerprints every statement; it replaces the
+// configuration of a detection enabled before.
+func (c *PrismaClient) EnableNPlusOneDetection(config NPlusOneConfig) {
+	if config.Threshold <= 0 {
+		config.Threshold = 5
+	}
+	if config.OnDetect == nil {
+		config.OnDetect = logNPlusOne
+	}
+
+	c.nPlusOneOnce.Do(func() {
+		c.nPlusOne = &nPlusOneDetector{client: c}
+		c.Observe(c.nPlusOne)
+	})
+	c.nPlusOne.setConfig(&config)
+}
+
+// DisableNPlusOneDetection stops detecting N+1 queries
+func (c *PrismaClient) DisableNPlusOneDetection() {
+	if c.nPlusOne != nil {
+		c.nPlusOne.setConfig(nil)
+	}
+}
+
+// QueryScope counts the statements run within a context, e.g. an HTTP
+// request, to detect N+1 queries
+type QueryScope struct {
+	mu       sync.Mutex
+	shapes   map[string]*queryShape
+	detected []NPlusOne
+}
+
+// queryShape counts the distinct arguments of a statement shape
+type queryShape struct {
+	args     map[string]bool
+	reported bool
+}
+
+// queryScopeKey is the context key of the query scope
+type queryScopeKey struct{}
+
+// WithQueryScope returns a context starting a query scope: the statements
+// run with it, and the contexts derived from it, are counted together by
+// the N+1 detection
+func WithQueryScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryScopeKey{}, &QueryScope{shapes: make(map[string]*queryShape)})
+}
+
+// QueryScopeFromContext returns the query scope of ctx, if any
+func QueryScopeFromContext(ctx context.Context) (*QueryScope, bool) {
+	scope, ok := ctx.Value(queryScopeKey{}).(*QueryScope)
+	return scope, ok
+}
+
+// Detected returns the N+1 queries detected in the scope so far
+func (s *QueryScope) Detected() []NPlusOne {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]NPlusOne(nil), s.detected...)
+}
+
+// count counts a run of the statement fingerprinted shape with args, and
+// reports whether it makes the shape cross threshold
+func (s *QueryScope) count(shape string, args []interface{}, threshold int) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, ok := s.shapes[shape]
+	if !ok {
+		q = &queryShape{args: make(map[string]bool)}
+		s.shapes[shape] = q
+	}
+	if q.reported {
+		return len(q.args), false
+	}
+	q.args[fmt.Sprintf("%#v", args)] = true
+	if len(q.args) <= threshold {
+		return len(q.args), false
+	}
+	q.reported = true
+	return len(q.args), true
+}
+
+// logNPlusOne logs an N+1 query with slog
+func logNPlusOne(n NPlusOne) {
+	attrs := []any{"count", n.Count, "sql", n.SQL}
+	if n.Model != "" {
+		attrs = append(attrs, "model", n.Model, "operation", n.Operation)
+	}
+	if n.Relation != "" {
+		attrs = append(attrs, "relation", n.Relation, "owner", n.Owner)
+	}
+	if n.Include != "" {
+		attrs = append(attrs, "include", n.Include)
+	}
+	if n.CallSite != "" {
+		attrs = append(attrs, "callSite", n.CallSite)
+	}
+	slog.Warn(n.Error(), attrs...)
+}
+
+// nPlusOneDetector is the observer counting the statements of a client in
+// their query scope
+type nPlusOneDetector struct {
+	client *PrismaClient
+
+	mu     sync.Mutex
+	config *NPlusOneConfig
+}
+
+func (d *nPlusOneDetector) setConfig(config *NPlusOneConfig) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.config = config
+}
+
+// StartOperation does nothing, statements are counted by shape
+func (d *nPlusOneDetector) StartOperation(ctx context.Context, op *executor.Operation) (context.Context, func(err error)) {
+	return ctx, nil
+}
+
+// StartStatement does nothing, statements are counted before they run
+func (d *nPlusOneDetector) StartStatement(ctx context.Context, stmt *executor.Statement) (context.Context, func(err error)) {
+	return ctx, nil
+}
+
+// CheckStatement counts stmt in the query scope of ctx, reporting it when
+// its shape crosses the threshold. In strict mode, it returns the NPlusOne
+// so that the statement does not run.
+func (d *nPlusOneDetector) CheckStatement(ctx context.Context, stmt *executor.Statement) error {
+	d.mu.Lock()
+	config := d.config
+	d.mu.Unlock()
+	if config == nil || !readStatement(stmt) {
+		return nil
+	}
+	scope, ok := QueryScopeFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	count, crossed := scope.count(fingerprintSQL(stmt.SQL), stmt.Args, config.Threshold)
+	if !crossed {
+		return nil
+	}
+
+	n := NPlusOne{SQL: stmt.SQL, Count: count, CallSite: callSite()}
+	if op := stmt.Operation; op != nil {
+		n.Model, n.Operation = op.Model, op.Name
+		n.Relation, n.Owner, n.Include = d.client.schemaNames.relationOf(op.Table, keyColumns(op))
+	}
+	scope.mu.Lock()
+	scope.detected = append(scope.detected, n)
+	scope.mu.Unlock()
+	config.OnDetect(n)
+	if config.Strict {
+		return n
+	}
+	return nil
+}
+
+// readStatement reports whether stmt reads records outside of a write, the
+// statements loading relations
+func readStatement(stmt *executor.Statement) bool {
+	if stmt.Verb != "SELECT" && stmt.Verb != "WITH" {
+		return false
+	}
+	if stmt.Operation == nil {
+		return true
+	}
+	for _, write := range []string{"create", "update", "upsert", "delete"} {
+		if strings.HasPrefix(stmt.Operation.Name, write) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	sqlIdentifiers = regexp.MustCompile(`"[^"]*"|` + "`[^`]*`")
+	sqlStrings     = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumbers     = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	sqlParameters  = regexp.MustCompile(`\$\d+|\?`)
+	sqlLists       = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	sqlWhitespace  = regexp.MustCompile(`\s+`)
+)
+
+// identifierToken stands for a quoted identifier while a statement is
+// fingerprinted
+const identifierToken = "\x00"
+
+// fingerprintSQL returns the shape of query: its text with literals and
+// parameters replaced by ?, lists of values collapsed and whitespace
+// normalized, so that statements differing by their values share it
+func fingerprintSQL(query string) string {
+	// Quoted identifiers are set aside so that their digits are kept
+	var identifiers []string
+	query = sqlIdentifiers.ReplaceAllStringFunc(query, func(id string) string {
+		identifiers = append(identifiers, id)
+		return identifierToken
+	})
+	query = sqlStrings.ReplaceAllString(query, "?")
+	query = sqlNumbers.ReplaceAllString(query, "?")
+	query = sqlParameters.ReplaceAllString(query, "?")
+	query = sqlLists.ReplaceAllString(query, "(?)")
+	query = strings.TrimSpace(sqlWhitespace.ReplaceAllString(query, " "))
+	for _, id := range identifiers {
+		query = strings.Replace(query, identifierToken, id, 1)
+	}
+	return query
+}
+
+// keyColumns returns the columns the filter of op compares for equality,
+// the keys of the relation an N+1 query loads
+func keyColumns(op *executor.Operation) []string {
+	if op.Where == nil {
+		return nil
+	}
+	var columns []string
+	for _, cond := range op.Where.Conditions {
+		if cond.Operator == "=" || cond.Operator == "IN" {
+			columns = append(columns, cond.Field)
+		}
+	}
+	return columns
+}
+
+// relationOf returns the relation loaded by filtering table on columns,
+// e.g. User.posts for the foreign key of Post, the model whose query should
+// include it and the call including it. It returns nothing when no
+// relation, or more than one, matches the filter, e.g. when Post.author and
+// Comment.user both load users by their id.
+func (n SchemaNames) relationOf(table string, columns []string) (relation, owner, include string) {
+	names, ok := n.table(table)
+	if !ok {
+		return "", "", ""
+	}
+	type match struct{ relation, owner, include string }
+	var matches []match
+	for _, column := range columns {
+		// A list of another model whose foreign key is column, or a
+		// relation to a single record referencing column
+		for _, other := range sortedKeys(n) {
+			for _, field := range sortedKeys(n[other].Relations) {
+				rel := n[other].Relations[field]
+				if rel.Model != names.Model {
+					continue
+				}
+				if (rel.List && strings.EqualFold(rel.ForeignKey, column)) || (!rel.List && strings.EqualFold(rel.References, column)) {
+					model := n[other].Model
+					matches = append(matches, match{model + "." + field, model, includeCall(field)})
+				}
+			}
+		}
+	}
+	if len(matches) == 0 {
+		// The records of the model are loaded by the foreign key of their
+		// relation, whose model has no list of them
+		for _, column := range columns {
+			for _, field := range sortedKeys(names.Relations) {
+				rel := names.Relations[field]
+				if !rel.List && strings.EqualFold(rel.ForeignKey, column) {
+					matches = append(matches, match{names.Model + "." + field, rel.Model, ""})
+				}
+			}
+		}
+	}
+	if len(matches) != 1 {
+		return "", "", ""
+	}
+	return matches[0].relation, matches[0].owner, matches[0].include
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// includeCall returns the call of the include builder of the generated
+// client loading the relation field
+func includeCall(field string) string {
+	return "Include()." + strings.ToUpper(field[:1]) + field[1:] + "()"
+}
+
+// callSite returns the file:line of the first caller outside of prisma-go
+// and of the generated client, whose methods are those of its Client and
+// Builder types
+func callSite() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !clientFrame(frame) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// clientFrame reports whether frame belongs to the runtime, the standard
+// library's database/sql or the client
+func clientFrame(frame runtime.Frame) bool {
+	fn := frame.Function
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	if strings.HasPrefix(fn, "runtime.") || strings.HasPrefix(fn, "database/sql.") ||
+		strings.HasPrefix(fn, "github.com/satishbabariya/prisma-go/") {
+		return true
+	}
+	// Methods of the generated client, e.g. pkg.(*UserQueryBuilder).Execute
+	if i := strings.Index(fn, ".(*"); i >= 0 {
+		receiver, _, _ := strings.Cut(fn[i+3:], ")")
+		return strings.HasSuffix(receiver, "Client") || strings.HasSuffix(receiver, "Builder")
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+)
+
+// nPlusOneSchema declares users and their posts
+var nPlusOneSchema = SchemaNames{
+	"users": {
+		Model:     "User",
+		Fields:    map[string]string{"id": "id"},
+		Relations: map[string]RelationNames{"posts": {Model: "Post", List: true, ForeignKey: "user_id", References: "id"}},
+	},
+	"posts": {
+		Model:     "Post",
+		Fields:    map[string]string{"id": "id", "user_id": "userId"},
+		Relations: map[string]RelationNames{"author": {Model: "User", ForeignKey: "user_id", References: "id"}},
+	},
+}
+
+type nPlusOnePost struct {
+	ID     int `db:"id"`
+	UserID int `db:"user_id"`
+}
+
+// newNPlusOneClient returns a SQLite client with users and posts tables,
+// and an executor of its generated client
+func newNPlusOneClient(t *testing.T, config NPlusOneConfig) (*PrismaClient, *executor.Executor) {
+	t.Helper()
+	c := newTestClient(t,
+		`CREATE TABLE users (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER)`,
+	)
+	exec := newTestExecutor(c, nPlusOneSchema)
+	c.EnableNPlusOneDetection(config)
+	return c, exec
+}
+
+// findPostOf runs the query of an N+1 loop: the first post of a user
+func findPostOf(ctx context.Context, exec *executor.Executor, userID int) error {
+	where := sqlgen.NewWhereClause()
+	where.AddCondition(sqlgen.Condition{Field: "user_id", Operator: "=", Value: userID})
+	var post nPlusOnePost
+	return exec.FindFirst(ctx, "posts", nil, where, nil, nil, &post)
+}
+
+func TestNPlusOneDetection(t *testing.T) {
+	var detected []NPlusOne
+	_, exec := newNPlusOneClient(t, NPlusOneConfig{
+		Threshold: 3,
+		OnDetect:  func(n NPlusOne) { detected = append(detected, n) },
+	})
+
+	// Outside of a scope and with the same arguments, statements do not
+	// count
+	for i := 0; i < 5; i++ {
+		findPostOf(context.Background(), exec, i)
+	}
+	ctx := WithQueryScope(context.Background())
+	for i := 0; i < 5; i++ {
+		findPostOf(ctx, exec, 1)
+	}
+	if len(detected) != 0 {
+		t.Fatalf("detected %v", detected)
+	}
+
+	for i := 2; i <= 6; i++ {
+		findPostOf(ctx, exec, i)
+	}
+	if len(detected) != 1 {
+		t.Fatalf("detected %d N+1 queries, want 1", len(detected))
+	}
+	n := detected[0]
+	if n.Model != "Post" || n.Operation != "findFirst" || n.Count != 4 {
+		t.Errorf("N+1 query = %+v", n)
+	}
+	if n.Relation != "User.posts" || n.Owner != "User" || n.Include != "Include().Posts()" {
+		t.Errorf("relation = %s on %s with %s, want User.posts with Include().Posts()", n.Relation, n.Owner, n.Include)
+	}
+	if !strings.Contains(n.CallSite, "nplusone_test.go:") {
+		t.Errorf("call site = %q, want the loop of the test", n.CallSite)
+	}
+	if !strings.Contains(n.Error(), "load User.posts with Include().Posts() on the query of User") {
+		t.Errorf("message = %q", n.Error())
+	}
+	scope, _ := QueryScopeFromContext(ctx)
+	if len(scope.Detected()) != 1 {
+		t.Errorf("scope detected %v", scope.Detected())
+	}
+}
+
+func TestNPlusOneStrict(t *testing.T) {
+	c, exec := newNPlusOneClient(t, NPlusOneConfig{Threshold: 2, Strict: true, OnDetect: func(NPlusOne) {}})
+	var statements []string
+	c.Observe(statementRecorder(func(stmt *executor.Statement) { statements = append(statements, stmt.SQL) }))
+	ctx := WithQueryScope(context.Background())
+
+	for i := 0; i < 2; i++ {
+		findPostOf(ctx, exec, i) // No posts are found
+	}
+	// The statement crossing the threshold fails its operation, and is
+	// neither run nor seen by the other observers
+	err := findPostOf(ctx, exec, 2)
+	var n NPlusOne
+	if !errors.As(err, &n) || n.Count != 3 {
+		t.Fatalf("err = %v, want the N+1 query", err)
+	}
+	if len(statements) != 2 {
+		t.Errorf("observed %d statements, want the 2 that ran", len(statements))
+	}
+}
+
+func TestRelationOf(t *testing.T) {
+	tests := []struct {
+		table, column            string
+		relation, owner, include string
+	}{
+		{"posts", "user_id", "User.posts", "User", "Include().Posts()"},
+		{"users", "id", "Post.author", "Post", "Include().Author()"},
+		{"posts", "id", "", "", ""},
+	}
+	for _, tt := range tests {
+		relation, owner, include := nPlusOneSchema.relationOf(tt.table, []string{tt.column})
+		if relation != tt.relation || owner != tt.owner || include != tt.include {
+			t.Errorf("relationOf(%s, %s) = %q, %q, %q", tt.table, tt.column, relation, owner, include)
+		}
+	}
+
+	// Users loaded by id may be the authors of posts or of comments
+	schema := SchemaNames{"users": nPlusOneSchema["users"], "posts": nPlusOneSchema["posts"], "comments": {
+		Model:     "Comment",
+		Relations: map[string]RelationNames{"user": {Model: "User", ForeignKey: "user_id", References: "id"}},
+	}}
+	for i := 0; i < 10; i++ {
+		if relation, owner, include := schema.relationOf("users", []string{"id"}); relation != "" || owner != "" || include != "" {
+			t.Fatalf("relationOf(users, id) = %q, %q, %q, want no relation of the two", relation, owner, include)
+		}
+	}
+
+	// Without the list of posts on User, the posts are loaded by their
+	// author
+	schema = SchemaNames{"users": {Model: "User"}, "posts": nPlusOneSchema["posts"]}
+	relation, owner, include := schema.relationOf("posts", []string{"user_id"})
+	if relation != "Post.author" || owner != "User" || include != "" {
+		t.Errorf("relationOf(posts, user_id) = %q, %q, %q", relation, owner, include)
+	}
+}
+
+func TestFingerprintSQL(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{`SELECT * FROM "posts" WHERE "user_id" = $1 LIMIT 1`, "SELECT *  FROM \"posts\"\n WHERE \"user_id\" = $2 LIMIT 5"},
+		{`SELECT * FROM posts WHERE id IN (?, ?)`, `SELECT * FROM posts WHERE id IN (?, ?, ?)`},
+		{`SELECT * FROM posts WHERE title = 'a'`, `SELECT * FROM posts WHERE title = 'it''s'`},
+	}
+	for _, tt := range tests {
+		if a, b := fingerprintSQL(tt.a), fingerprintSQL(tt.b); a != b {
+			t.Errorf("fingerprints differ: %q and %q", a, b)
+		}
+	}
+	if a, b := fingerprintSQL(`SELECT * FROM "t1"`), fingerprintSQL(`SELECT * FROM "t2"`); a == b {
+		t.Errorf("fingerprints of different tables are both %q", a)
+	}
+}
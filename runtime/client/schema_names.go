@@ -0,0 +1,63 @@
+package client
+
+import "strings"
+
+// SchemaNames maps the tables of a schema to their models, so that
+// classified errors, logs and N+1 reports name models and fields rather
+// than tables and columns, and soft deletes know their timestamp columns.
+// Generated clients declare the SchemaNames of their schema.
+type SchemaNames map[string]TableNames
+
+// TableNames holds the model of a table, the fields of its columns and the
+// keys of its relations
+type TableNames struct {
+	Model      string
+	Fields     map[string]string        // column name -> field name
+	Sensitive  []string                 // Columns of fields annotated /// @sensitive, redacted in logs
+	Relations  map[string]RelationNames // relation field -> keys
+	SoftDelete string                   // Timestamp column of /// @softDelete, marking deleted records
+}
+
+// RelationNames holds the related model of a relation field and the columns
+// joining them
+type RelationNames struct {
+	Model      string // Related model
+	List       bool
+	ForeignKey string // Column of the foreign key, in the table of the related model for lists
+	References string // Column referenced by the foreign key
+}
+
+// Models maps the tables of the schema to their models
+func (n SchemaNames) Models() map[string]string {
+	models := make(map[string]string, len(n))
+	for table, names := range n {
+		models[table] = names.Model
+	}
+	return models
+}
+
+// SoftDeletes maps the tables of soft-deleted models to the timestamp
+// columns marking their deleted records
+func (n SchemaNames) SoftDeletes() map[string]string {
+	columns := make(map[string]string)
+	for table, names := range n {
+		if names.SoftDelete != "" {
+			columns[table] = names.SoftDelete
+		}
+	}
+	return columns
+}
+
+// table returns the names of a table. Names are matched case-insensitively
+// when there is no exact match, as MySQL may fold the case of table names.
+func (n SchemaNames) table(table string) (TableNames, bool) {
+	if names, ok := n[table]; ok {
+		return names, true
+	}
+	for name, names := range n {
+		if strings.EqualFold(name, table) {
+			return names, true
+		}
+	}
+	return TableNames{}, false
+}
@@ -0,0 +1,267 @@
+// Package client provides the slow query log.
+package client
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/query/optimizer"
+)
+
+// SlowQuery is a SQL statement that ran longer than the threshold of the
+// slow query log
+type SlowQuery struct {
+	SQL       string
+	Args      []interface{} // Redacted by SlowQueryConfig.RedactArgs
+	Duration  time.Duration
+	Error     error
+	Model     string // Model and Operation that ran the statement, empty for raw queries
+	Operation string
+
+	// Plan is the output of EXPLAIN for the statement, empty when it was
+	// not captured, e.g. because of the rate limit
+	Plan    string
+	PlanErr error
+
+	// Suggestions are the indexes the optimizer suggests for the filter and
+	// order of the operation
+	Suggestions []string
+}
+
+// SlowQueryConfig configures the slow query log
+type SlowQueryConfig struct {
+	// Threshold is the duration above which statements are logged, 200ms
+	// by default
+	Threshold time.Duration
+
+	// Explain captures the plan of slow SELECT, UPDATE and DELETE
+	// statements, in the background, with EXPLAIN (FORMAT JSON) on
+	// PostgreSQL, EXPLAIN FORMAT=JSON on MySQL and EXPLAIN QUERY PLAN on
+	// SQLite
+	Explain bool
+
+	// MaxExplainsPerMinute limits the plans captured, 10 by default. The
+	// plan of a statement is captured at most once a minute; slow queries
+	// over the limit are logged without their plan.
+	MaxExplainsPerMinute int
+
+	// ExplainTimeout bounds the capture of a plan, 5s by default
+	ExplainTimeout time.Duration
+
+	// RedactArgs returns the arguments that are logged. By default each
+	// argument is replaced by its type, e.g. <string>.
+	RedactArgs func(args []interface{}) []interface{}
+
+	// OnSlowQuery receives the slow queries, with their plan when it is
+	// captured, in which case it is called from another goroutine. By
+	// default slow queries are logged with slog at the warning level.
+	OnSlowQuery func(SlowQuery)
+}
+
+// EnableSlowQueryLog logs the statements of the client running longer than
+// the threshold of config, with the model and operation that ran them. It
+// replaces the configuration of a log enabled before.
+func (c *PrismaClient) EnableSlowQueryLog(config SlowQueryConfig) {
+	if config.Threshold <= 0 {
+		config.Threshold = 200 * time.Millisecond
+	}
+	if config.MaxExplainsPerMinute <= 0 {
+		config.MaxExplainsPerMinute = 10
+	}
+	if config.ExplainTimeout <= 0 {
+		config.ExplainTimeout = 5 * time.Second
+	}
+	if config.RedactArgs == nil {
+		config.RedactArgs = RedactArgTypes
+	}
+	if config.OnSlowQuery == nil {
+		config.OnSlowQuery = logSlowQuery
+	}
+
+	c.slowQueryOnce.Do(func() {
+		c.slowQueries = &slowQueryLog{
+			db:        c.db,
+			optimizer: optimizer.NewOptimizer(c.provider),
+			explained: make(map[string]time.Time),
+		}
+		c.Observe(c.slowQueries)
+	})
+	c.slowQueries.setConfig(&config)
+}
+
+// DisableSlowQueryLog stops logging slow queries
+func (c *PrismaClient) DisableSlowQueryLog() {
+	if c.slowQueries != nil {
+		c.slowQueries.setConfig(nil)
+	}
+}
+
+// RedactArgTypes replaces each argument by its type, e.g. <string>
+func RedactArgTypes(args []interface{}) []interface{} {
+	redacted := make([]interface{}, len(args))
+	for i, arg := range args {
+		if arg != nil {
+			redacted[i] = fmt.Sprintf("<%T>", arg)
+		}
+	}
+	return redacted
+}
+
+// logSlowQuery logs a slow query with slog
+func logSlowQuery(q SlowQuery) {
+	attrs := []any{"duration", q.Duration, "sql", q.SQL, "args", q.Args}
+	if q.Model != "" {
+		attrs = append(attrs, "model", q.Model, "operation", q.Operation)
+	}
+	if q.Error != nil {
+		attrs = append(attrs, "error", q.Error)
+	}
+	if q.Plan != "" {
+		attrs = append(attrs, "plan", q.Plan)
+	}
+	if q.PlanErr != nil {
+		attrs = append(attrs, "planError", q.PlanErr)
+	}
+	if len(q.Suggestions) > 0 {
+		attrs = append(attrs, "suggestions", q.Suggestions)
+	}
+	slog.Warn("slow query", attrs...)
+}
+
+// slowQueryLog is the observer timing the statements of a client
+type slowQueryLog struct {
+	db        *sql.DB
+	optimizer *optimizer.Optimizer
+
+	mu        sync.Mutex
+	config    *SlowQueryConfig
+	explained map[string]time.Time // SQL -> time its plan was captured
+	window    time.Time            // start of the minute of explains
+	explains  int                  // plans captured in the window
+}
+
+func (l *slowQueryLog) setConfig(config *SlowQueryConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = config
+}
+
+// StartOperation does nothing, slow queries are timed by statement
+func (l *slowQueryLog) StartOperation(ctx context.Context, op *executor.Operation) (context.Context, func(err error)) {
+	return ctx, nil
+}
+
+// StartStatement times stmt, reporting it if it is slow
+func (l *slowQueryLog) StartStatement(ctx context.Context, stmt *executor.Statement) (context.Context, func(err error)) {
+	l.mu.Lock()
+	config := l.config
+	l.mu.Unlock()
+	if config == nil {
+		return ctx, nil
+	}
+	start := time.Now()
+	return ctx, func(err error) {
+		if d := time.Since(start); d >= config.Threshold {
+			l.report(config, stmt, d, err)
+		}
+	}
+}
+
+// report reports a slow statement, capturing its plan in the background
+// when allowed
+func (l *slowQueryLog) report(config *SlowQueryConfig, stmt *executor.Statement, d time.Duration, err error) {
+	q := SlowQuery{
+		SQL:      stmt.SQL,
+		Args:     config.RedactArgs(stmt.Args),
+		Duration: d,
+		Error:    err,
+	}
+	if op := stmt.Operation; op != nil {
+		q.Model, q.Operation = op.Model, op.Name
+		q.Suggestions = l.optimizer.SuggestIndexes(op.Table, op.Where, op.OrderBy)
+	}
+
+	explain, ok := l.optimizer.ExplainSQL(stmt.SQL)
+	if !config.Explain || !ok || !explainable(stmt.Verb) || !l.allowExplain(config, stmt.SQL) {
+		config.OnSlowQuery(q)
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), config.ExplainTimeout)
+		defer cancel()
+		q.Plan, q.PlanErr = capturePlan(ctx, l.db, explain, stmt.Args)
+		config.OnSlowQuery(q)
+	}()
+}
+
+// allowExplain reports whether the plan of query may be captured now,
+// counting it against the rate limit
+func (l *slowQueryLog) allowExplain(config *SlowQueryConfig, query string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.window) >= time.Minute {
+		l.window, l.explains = now, 0
+		for sql, at := range l.explained {
+			if now.Sub(at) >= time.Minute {
+				delete(l.explained, sql)
+			}
+		}
+	}
+	if at, ok := l.explained[query]; ok && now.Sub(at) < time.Minute {
+		return false
+	}
+	if l.explains >= config.MaxExplainsPerMinute {
+		return false
+	}
+	l.explains++
+	l.explained[query] = now
+	return true
+}
+
+// explainable reports whether the plan of statements with verb is worth
+// capturing
+func explainable(verb string) bool {
+	switch verb {
+	case "SELECT", "WITH", "UPDATE", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+// capturePlan runs explain and returns its output: the JSON document of
+// PostgreSQL and MySQL, or the lines of SQLite's query plan
+func capturePlan(ctx context.Context, db *sql.DB, explain string, args []interface{}) (string, error) {
+	rows, err := db.QueryContext(ctx, explain, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return "", err
+		}
+		// The plan is the last column: the JSON of PostgreSQL and MySQL,
+		// the detail of SQLite
+		lines = append(lines, values[len(values)-1].String)
+	}
+	return strings.Join(lines, "\n"), rows.Err()
+}
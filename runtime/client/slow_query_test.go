@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+)
+
+// newSlowQueryClient returns a SQLite client with a users table, logging
+// every statement as slow to the returned channel
+func newSlowQueryClient(t *testing.T, config SlowQueryConfig) (*PrismaClient, chan SlowQuery) {
+	t.Helper()
+	c := newTestClient(t, `CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT)`)
+	queries := make(chan SlowQuery, 10)
+	config.Threshold = time.Nanosecond
+	config.OnSlowQuery = func(q SlowQuery) { queries <- q }
+	c.EnableSlowQueryLog(config)
+	return c, queries
+}
+
+// nextSlowQuery returns the next slow query
+func nextSlowQuery(t *testing.T, queries <-chan SlowQuery) SlowQuery {
+	t.Helper()
+	select {
+	case q := <-queries:
+		return q
+	case <-time.After(5 * time.Second):
+		t.Fatal("no slow query logged")
+		return SlowQuery{}
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	c, queries := newSlowQueryClient(t, SlowQueryConfig{Explain: true})
+	exec := newTestExecutor(c, SchemaNames{"users": {Model: "User"}})
+
+	where := sqlgen.NewWhereClause()
+	where.AddCondition(sqlgen.Condition{Field: "email", Operator: "=", Value: "a@b.c"})
+	if _, err := exec.Count(context.Background(), "users", where); err != nil {
+		t.Fatal(err)
+	}
+
+	q := nextSlowQuery(t, queries)
+	if q.Model != "User" || q.Operation != "count" || !strings.HasPrefix(q.SQL, "SELECT COUNT(*)") {
+		t.Errorf("slow query = %+v", q)
+	}
+	if len(q.Args) != 1 || q.Args[0] != "<string>" {
+		t.Errorf("args = %v, want them redacted", q.Args)
+	}
+	if q.PlanErr != nil || !strings.Contains(q.Plan, "SCAN users") {
+		t.Errorf("plan = %q (%v)", q.Plan, q.PlanErr)
+	}
+	if len(q.Suggestions) != 1 || q.Suggestions[0] != "CREATE INDEX idx_users_email ON users(email)" {
+		t.Errorf("suggestions = %v", q.Suggestions)
+	}
+}
+
+func TestSlowQueryExplainRateLimit(t *testing.T) {
+	c, queries := newSlowQueryClient(t, SlowQueryConfig{Explain: true, MaxExplainsPerMinute: 2})
+	ctx := context.Background()
+
+	for _, query := range []string{
+		`SELECT id FROM users`,
+		`SELECT id FROM users`, // explained already
+		`SELECT email FROM users`,
+		`SELECT * FROM users`, // over the limit
+	} {
+		rows, err := c.RawQuery(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+	}
+
+	plans := map[string]int{}
+	for i := 0; i < 4; i++ {
+		if q := nextSlowQuery(t, queries); q.Plan != "" {
+			plans[q.SQL]++
+		}
+	}
+	if len(plans) != 2 || plans[`SELECT id FROM users`] != 1 || plans[`SELECT email FROM users`] != 1 {
+		t.Errorf("plans captured = %v", plans)
+	}
+}
+
+func TestSlowQueryThreshold(t *testing.T) {
+	c, queries := newSlowQueryClient(t, SlowQueryConfig{})
+	c.EnableSlowQueryLog(SlowQueryConfig{Threshold: time.Hour, OnSlowQuery: func(q SlowQuery) { t.Errorf("fast query logged: %s", q.SQL) }})
+	if _, err := c.RawExec(context.Background(), `INSERT INTO users (email) VALUES (?)`, "a@b.c"); err != nil {
+		t.Fatal(err)
+	}
+
+	c.EnableSlowQueryLog(SlowQueryConfig{Threshold: time.Nanosecond, RedactArgs: func(args []interface{}) []interface{} { return args },
+		OnSlowQuery: func(q SlowQuery) { queries <- q }})
+	if _, err := c.RawExec(context.Background(), `DELETE FROM users WHERE email = ?`, "a@b.c"); err != nil {
+		t.Fatal(err)
+	}
+	if q := nextSlowQuery(t, queries); q.Model != "" || q.Args[0] != "a@b.c" || q.Plan != "" {
+		t.Errorf("raw slow query = %+v", q)
+	}
+
+	c.DisableSlowQueryLog()
+	if _, err := c.RawExec(context.Background(), `DELETE FROM users`); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case q := <-queries:
+		t.Errorf("query logged after the log was disabled: %s", q.SQL)
+	default:
+	}
+}
@@ -0,0 +1,251 @@
+package client
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/query/sqlgen"
+)
+
+type softDeleteBook struct {
+	ID        int        `db:"id"`
+	AuthorID  int        `db:"author_id"`
+	Title     string     `db:"title"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+type softDeleteAuthor struct {
+	ID        int              `db:"id"`
+	Name      string           `db:"name"`
+	DeletedAt *time.Time       `db:"deleted_at"`
+	Books     []softDeleteBook `json:"books"`
+}
+
+// newSoftDeleteClient returns a SQLite client with authors and their
+// books, both soft-deleted, and an executor of its generated client
+func newSoftDeleteClient(t *testing.T) (*PrismaClient, *executor.Executor) {
+	t.Helper()
+	c := newTestClient(t,
+		`CREATE TABLE authors (id INTEGER PRIMARY KEY, name TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE books (id INTEGER PRIMARY KEY, author_id INTEGER, title TEXT, deleted_at DATETIME)`,
+		`CREATE UNIQUE INDEX books_title_unique ON books (title) WHERE deleted_at IS NULL`,
+		`INSERT INTO authors (name) VALUES ('Ann'), ('Bob')`,
+		`INSERT INTO books (author_id, title) VALUES (1, 'a'), (1, 'b'), (2, 'c')`,
+	)
+	exec := newTestExecutor(c, SchemaNames{
+		"authors": {Model: "Author", SoftDelete: "deleted_at"},
+		"books":   {Model: "Book", SoftDelete: "deleted_at"},
+	})
+	return c, exec
+}
+
+// whereEquals returns the WHERE clause of the records whose column equals
+// value
+func whereEquals(column string, value interface{}) *sqlgen.WhereClause {
+	where := sqlgen.NewWhereClause()
+	where.AddCondition(sqlgen.Condition{Field: column, Operator: "=", Value: value})
+	return where
+}
+
+func TestSoftDelete(t *testing.T) {
+	_, exec := newSoftDeleteClient(t)
+	ctx := context.Background()
+
+	if err := exec.Delete(ctx, "books", whereEquals("title", "a")); err != nil {
+		t.Fatal(err)
+	}
+	var books []softDeleteBook
+	if err := exec.FindMany(ctx, "books", nil, nil, nil, nil, nil, nil, &books); err != nil || len(books) != 2 {
+		t.Errorf("findMany = %+v (%v), want the 2 books not deleted", books, err)
+	}
+	if count, err := exec.Count(ctx, "books", nil); err != nil || count != 2 {
+		t.Errorf("count = %d (%v), want 2", count, err)
+	}
+
+	// Deleting again leaves the timestamp as it is
+	var deleted []softDeleteBook
+	if err := exec.OnlyDeleted().FindMany(ctx, "books", nil, nil, nil, nil, nil, nil, &deleted); err != nil || len(deleted) != 1 || deleted[0].DeletedAt == nil {
+		t.Fatalf("deleted books = %+v (%v), want book a", deleted, err)
+	}
+	if n, err := exec.DeleteMany(ctx, "books", whereEquals("title", "a")); err != nil || n != 0 {
+		t.Errorf("deleting a deleted book = %d (%v), want 0", n, err)
+	}
+	if count, err := exec.WithDeleted().Count(ctx, "books", nil); err != nil || count != 3 {
+		t.Errorf("count with deleted = %d (%v), want 3", count, err)
+	}
+
+	// The unique index ignores the deleted book, so restoring it conflicts
+	// with a new book of its title
+	if _, err := exec.Create(ctx, "books", &softDeleteBook{ID: 4, AuthorID: 2, Title: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exec.Restore(ctx, "books", whereEquals("title", "a")); err == nil {
+		t.Error("restoring a book whose title is taken succeeded")
+	}
+	if err := exec.Delete(ctx, "books", whereEquals("id", 4)); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := exec.Restore(ctx, "books", whereEquals("id", 1)); err != nil || n != 1 {
+		t.Errorf("restore = %d (%v), want 1", n, err)
+	}
+	if count, err := exec.Count(ctx, "books", nil); err != nil || count != 3 {
+		t.Errorf("count after restore = %d (%v), want 3", count, err)
+	}
+	if _, err := exec.Restore(ctx, "notes", nil); err == nil {
+		t.Error("restoring records of a table without soft deletes succeeded")
+	}
+}
+
+func TestSoftDeleteIncludes(t *testing.T) {
+	c, exec := newSoftDeleteClient(t)
+	ctx := context.Background()
+	var last *executor.Statement
+	c.Observe(statementRecorder(func(stmt *executor.Statement) { last = stmt }))
+	if err := exec.Delete(ctx, "books", whereEquals("title", "b")); err != nil {
+		t.Fatal(err)
+	}
+
+	// rows runs exec.FindManyWithRelations of the authors with their books,
+	// and returns the number of rows its statement joins
+	relations := map[string]executor.RelationMetadata{
+		"books": {RelatedTable: "books", ForeignKey: "author_id", LocalKey: "id", IsList: true},
+	}
+	rows := func(exec *executor.Executor) int {
+		t.Helper()
+		var authors []softDeleteAuthor
+		if err := exec.FindManyWithRelations(ctx, "authors", nil, nil, nil, nil, nil, map[string]bool{"books": true}, relations, &authors); err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		if err := c.DB().QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+last.SQL+")", last.Args...).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	if n := rows(exec); n != 2 {
+		t.Errorf("authors joined with %d books, want 2 not deleted", n)
+	}
+	if !strings.Contains(last.SQL, `"books"."deleted_at" IS NULL`) {
+		t.Errorf("join does not skip deleted books: %s", last.SQL)
+	}
+	if n := rows(exec.WithDeleted()); n != 3 {
+		t.Errorf("authors joined with %d books, want all 3", n)
+	}
+
+	// Deleted authors still skip their deleted books
+	if err := exec.Delete(ctx, "authors", whereEquals("name", "Ann")); err != nil {
+		t.Fatal(err)
+	}
+	if n := rows(exec); n != 1 {
+		t.Errorf("authors not deleted joined with %d books, want 1", n)
+	}
+	if n := rows(exec.OnlyDeleted()); n != 1 {
+		t.Errorf("deleted authors joined with %d books, want 1", n)
+	}
+}
+
+func TestSoftDeleteIncludesBelongsTo(t *testing.T) {
+	c, exec := newSoftDeleteClient(t)
+	ctx := context.Background()
+	var last *executor.Statement
+	c.Observe(statementRecorder(func(stmt *executor.Statement) { last = stmt }))
+	if err := exec.Delete(ctx, "authors", whereEquals("name", "Ann")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The foreign key of Book.author is in books, but the relation joins
+	// authors
+	relations := map[string]executor.RelationMetadata{
+		"author": {RelatedTable: "authors", ForeignKey: "author_id", LocalKey: "id"},
+	}
+	// authors runs exec.FindManyWithRelations of the books with their
+	// author, and returns the names of the authors its statement joins
+	authors := func(exec *executor.Executor) []string {
+		t.Helper()
+		var books []softDeleteBook
+		if err := exec.FindManyWithRelations(ctx, "books", nil, nil, nil, nil, nil, map[string]bool{"author": true}, relations, &books); err != nil {
+			t.Fatal(err)
+		}
+		query := `SELECT "authors"."name" FROM "books"` + last.SQL[strings.Index(last.SQL, " LEFT JOIN"):] + ` ORDER BY "books"."id"`
+		rows, err := c.DB().QueryContext(ctx, query, last.Args...)
+		if err != nil {
+			t.Fatalf("%v\n%s", err, query)
+		}
+		defer rows.Close()
+		var names []string
+		for rows.Next() {
+			var name sql.NullString
+			if err := rows.Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, name.String)
+		}
+		return names
+	}
+
+	if names := strings.Join(authors(exec), ","); names != ",,Bob" {
+		t.Errorf("books are joined with authors %q, want only Bob", names)
+	}
+	if !strings.Contains(last.SQL, `"authors"."deleted_at" IS NULL`) {
+		t.Errorf("join does not skip deleted authors: %s", last.SQL)
+	}
+	if names := strings.Join(authors(exec.WithDeleted()), ","); names != "Ann,Ann,Bob" {
+		t.Errorf("books are joined with authors %q, want Ann,Ann,Bob", names)
+	}
+}
+
+func TestSoftDeleteWrites(t *testing.T) {
+	_, exec := newSoftDeleteClient(t)
+	ctx := context.Background()
+	if err := exec.Delete(ctx, "books", whereEquals("title", "a")); err != nil {
+		t.Fatal(err)
+	}
+	title := func(id int) string {
+		t.Helper()
+		var book softDeleteBook
+		if err := exec.WithDeleted().FindFirst(ctx, "books", nil, whereEquals("id", id), nil, nil, &book); err != nil {
+			t.Fatal(err)
+		}
+		return book.Title
+	}
+
+	// Writes skip the deleted book like reads
+	set := map[string]interface{}{"title": "z"}
+	if n, err := exec.UpdateMany(ctx, "books", set, whereEquals("id", 1)); err != nil || n != 0 {
+		t.Errorf("updateMany of a deleted book = %d (%v), want 0", n, err)
+	}
+	var book softDeleteBook
+	exec.Update(ctx, "books", set, whereEquals("id", 1), &book)
+	if _, err := exec.Upsert(ctx, "books", &softDeleteBook{ID: 1, AuthorID: 1, Title: "z"}, []string{"id"}, []string{"title"}); err == nil {
+		t.Error("upserting a deleted book succeeded")
+	}
+	if got := title(1); got != "a" {
+		t.Errorf("deleted book was renamed %q", got)
+	}
+	if _, err := exec.OnlyDeleted().Upsert(ctx, "books", &softDeleteBook{ID: 2, AuthorID: 1, Title: "z"}, []string{"id"}, []string{"title"}); err == nil {
+		t.Error("upserting a book not deleted succeeded with OnlyDeleted")
+	}
+
+	// Writes of WithDeleted see it
+	if n, err := exec.WithDeleted().UpdateMany(ctx, "books", set, whereEquals("id", 1)); err != nil || n != 1 {
+		t.Errorf("updateMany with deleted = %d (%v), want 1", n, err)
+	}
+	if _, err := exec.WithDeleted().Upsert(ctx, "books", &softDeleteBook{ID: 1, AuthorID: 1, Title: "y"}, []string{"id"}, []string{"title"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := title(1); got != "y" {
+		t.Errorf("book title = %q, want y", got)
+	}
+	// Upserts of records that do not exist insert them
+	if _, err := exec.Upsert(ctx, "books", &softDeleteBook{ID: 5, AuthorID: 2, Title: "e"}, []string{"id"}, []string{"title"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := title(5); got != "e" {
+		t.Errorf("upserted book title = %q, want e", got)
+	}
+}
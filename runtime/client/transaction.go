@@ -50,7 +50,18 @@ type Tx struct {
 	*sql.Tx
 	db       *sql.DB
 	provider string
-	depth    int // Track nesting depth for savepoints
+	depth    int             // Track nesting depth for savepoints
+	ctx      context.Context // Context of the transaction, see Context
+}
+
+// Context returns the context the transaction was started with, carrying
+// the transaction span of the observers. Pass it to the queries of the
+// transaction so that they are traced within it.
+func (tx *Tx) Context() context.Context {
+	if tx.ctx == nil {
+		return context.Background()
+	}
+	return tx.ctx
 }
 
 // TransactionFunc is a function that runs within a transaction
@@ -65,7 +76,10 @@ func (c *PrismaClient) Transaction(ctx context.Context, fn TransactionFunc) erro
 }
 
 // TransactionWithTxAndOptions executes a function with a Tx wrapper and custom options
-func (c *PrismaClient) TransactionWithTxAndOptions(ctx context.Context, opts *sql.TxOptions, fn TransactionFunc) error {
+func (c *PrismaClient) TransactionWithTxAndOptions(ctx context.Context, opts *sql.TxOptions, fn TransactionFunc) (err error) {
+	ctx, end := c.observers.StartTransaction(ctx, c.provider)
+	defer func() { end(err) }()
+
 	// Begin transaction
 	sqlTx, err := c.db.BeginTx(ctx, opts)
 	if err != nil {
@@ -77,6 +91,7 @@ func (c *PrismaClient) TransactionWithTxAndOptions(ctx context.Context, opts *sq
 		db:       c.db,
 		provider: c.provider,
 		depth:    0,
+		ctx:      ctx,
 	}
 
 	// Defer rollback in case of panic
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ValidationErrors holds the failed @validate rules of a record, at most one
+// per field. Each error is a P2009 PrismaError naming the model and field.
+type ValidationErrors []*PrismaError
+
+// Add appends err unless it is nil
+func (e *ValidationErrors) Add(err *PrismaError) {
+	if err != nil {
+		*e = append(*e, err)
+	}
+}
+
+// Err returns e as an error, or nil when no rule failed
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error implements the error interface
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message
+	}
+	return "[P2009] " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the fields
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Fields returns the errors by field name
+func (e ValidationErrors) Fields() map[string]*PrismaError {
+	fields := make(map[string]*PrismaError, len(e))
+	for _, err := range e {
+		fields[err.Field] = err
+	}
+	return fields
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IsEmail reports whether s is a bare email address, e.g. "ann@example.com"
+// but not "Ann <ann@example.com>"
+func IsEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s && addr.Name == ""
+}
+
+// IsURL reports whether s is an absolute URL with a host
+func IsURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// IsUUID reports whether s is a UUID in its canonical hyphenated form
+func IsUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
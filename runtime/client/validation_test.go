@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationErrors(t *testing.T) {
+	var errs ValidationErrors
+	errs.Add(nil)
+	if errs.Err() != nil {
+		t.Fatal("no failed rules should be a nil error")
+	}
+	errs.Add(NewValidationError("User", "email", "email must be an email address"))
+	errs.Add(NewValidationError("User", "age", "age must be at most 150"))
+
+	err := errs.Err()
+	if err.Error() != "[P2009] email must be an email address; age must be at most 150" {
+		t.Errorf("Error() = %q", err)
+	}
+	if !IsValidationError(err) {
+		t.Error("ValidationErrors is not a validation error")
+	}
+	var prismaErr *PrismaError
+	if !errors.As(err, &prismaErr) || prismaErr.Field != "email" {
+		t.Errorf("first field error = %v", prismaErr)
+	}
+	if got := errs.Fields()["age"]; got == nil || got.Message != "age must be at most 150" {
+		t.Errorf("Fields()[age] = %v", got)
+	}
+}
+
+func TestValidationRules(t *testing.T) {
+	for _, tt := range []struct {
+		check func(string) bool
+		value string
+		want  bool
+	}{
+		{IsEmail, "ann@example.com", true},
+		{IsEmail, "Ann <ann@example.com>", false},
+		{IsEmail, "ann", false},
+		{IsURL, "https://example.com/a?b=c", true},
+		{IsURL, "/relative/path", false},
+		{IsURL, "example.com", false},
+		{IsUUID, "123e4567-e89b-12d3-a456-426614174000", true},
+		{IsUUID, "123e4567e89b12d3a456426614174000", false},
+	} {
+		if got := tt.check(tt.value); got != tt.want {
+			t.Errorf("check of %q = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package dataloader batches the loads of one request into one query per
+// batch of keys, so resolving a relation for a list of records does not
+// issue a query per record.
+//
+// A Loader collects the keys loaded by concurrent goroutines during a short
+// wait and passes them to its BatchFunc at once. Results are cached for the
+// life of the loader, which is meant to be a single request.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc loads the values of keys in one go. Keys without a value are
+// left out of the map and load the zero value.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader batches and caches loads by key
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*call[V]
+	batch *batch[K, V]
+}
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys       []K
+	calls      []*call[V]
+	dispatched bool
+}
+
+type options struct {
+	wait     time.Duration
+	maxBatch int
+}
+
+// Option configures a Loader
+type Option func(*options)
+
+// WithWait sets how long a batch collects keys before it is loaded, 1ms by
+// default
+func WithWait(wait time.Duration) Option {
+	return func(o *options) {
+		o.wait = wait
+	}
+}
+
+// WithMaxBatch sets the number of keys after which a batch is loaded
+// without waiting, 1000 by default
+func WithMaxBatch(n int) Option {
+	return func(o *options) {
+		o.maxBatch = n
+	}
+}
+
+// New creates a loader fetching batches with fetch
+func New[K comparable, V any](fetch BatchFunc[K, V], opts ...Option) *Loader[K, V] {
+	o := options{wait: time.Millisecond, maxBatch: 1000}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxBatch < 1 {
+		o.maxBatch = 1
+	}
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     o.wait,
+		maxBatch: o.maxBatch,
+		cache:    make(map[K]*call[V]),
+	}
+}
+
+// Load returns the value of key. The key is loaded with the keys other
+// goroutines load within the wait, using the context of the first load of
+// the batch. Failed loads are not cached.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	c, ok := l.cache[key]
+	if !ok {
+		c = &call[V]{done: make(chan struct{})}
+		l.cache[key] = c
+
+		b := l.batch
+		if b == nil {
+			b = &batch[K, V]{}
+			l.batch = b
+			time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+		}
+		b.keys = append(b.keys, key)
+		b.calls = append(b.calls, c)
+		if len(b.keys) >= l.maxBatch {
+			go l.dispatch(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Prime caches the value of a key that was loaded otherwise
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.cache[key]; ok {
+		return
+	}
+	c := &call[V]{done: make(chan struct{}), value: value}
+	close(c.done)
+	l.cache[key] = c
+}
+
+// dispatch loads a batch once, when its wait is over or it is full
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	l.mu.Lock()
+	if b.dispatched {
+		l.mu.Unlock()
+		return
+	}
+	b.dispatched = true
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	values, err := l.fetch(ctx, b.keys)
+
+	if err != nil {
+		l.mu.Lock()
+		for _, key := range b.keys {
+			delete(l.cache, key)
+		}
+		l.mu.Unlock()
+	}
+	for i, key := range b.keys {
+		c := b.calls[i]
+		if err != nil {
+			c.err = err
+		} else {
+			c.value = values[key]
+		}
+		close(c.done)
+	}
+}
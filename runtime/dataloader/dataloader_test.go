@@ -0,0 +1,123 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a BatchFunc doubling its keys and recording its batches
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *recorder) fetch(ctx context.Context, keys []int) (map[int]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batch := append([]int(nil), keys...)
+	sort.Ints(batch)
+	r.batches = append(r.batches, batch)
+	if r.err != nil {
+		return nil, r.err
+	}
+	values := make(map[int]int, len(keys))
+	for _, key := range keys {
+		if key >= 0 {
+			values[key] = key * 2
+		}
+	}
+	return values, nil
+}
+
+func loadAll(t *testing.T, l *Loader[int, int], keys []int) []int {
+	t.Helper()
+	values := make([]int, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i, key int) {
+			defer wg.Done()
+			value, err := l.Load(context.Background(), key)
+			if err != nil {
+				t.Error(err)
+			}
+			values[i] = value
+		}(i, key)
+	}
+	wg.Wait()
+	return values
+}
+
+func TestLoadBatches(t *testing.T) {
+	r := &recorder{}
+	l := New(r.fetch, WithWait(20*time.Millisecond))
+
+	values := loadAll(t, l, []int{1, 2, 3, 2, -1})
+	if want := []int{2, 4, 6, 4, 0}; !equal(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if len(r.batches) != 1 || !equal(r.batches[0], []int{-1, 1, 2, 3}) {
+		t.Errorf("batches = %v, want one batch of the distinct keys", r.batches)
+	}
+
+	// Cached keys are not loaded again
+	loadAll(t, l, []int{1, 4})
+	if len(r.batches) != 2 || !equal(r.batches[1], []int{4}) {
+		t.Errorf("batches = %v", r.batches)
+	}
+}
+
+func TestMaxBatch(t *testing.T) {
+	r := &recorder{}
+	l := New(r.fetch, WithWait(time.Hour), WithMaxBatch(2))
+
+	loadAll(t, l, []int{1, 2, 3, 4})
+	if len(r.batches) != 2 {
+		t.Errorf("batches = %v, want 2 full batches", r.batches)
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	r := &recorder{err: errors.New("connection refused")}
+	l := New(r.fetch)
+
+	if _, err := l.Load(context.Background(), 1); err == nil || err.Error() != "connection refused" {
+		t.Fatalf("err = %v", err)
+	}
+
+	// Failed loads are retried
+	r.err = nil
+	if value, err := l.Load(context.Background(), 1); err != nil || value != 2 {
+		t.Errorf("Load = %d, %v", value, err)
+	}
+}
+
+func TestPrime(t *testing.T) {
+	r := &recorder{}
+	l := New(r.fetch)
+	l.Prime(5, 50)
+
+	if value, err := l.Load(context.Background(), 5); err != nil || value != 50 {
+		t.Errorf("Load = %d, %v", value, err)
+	}
+	if len(r.batches) != 0 {
+		t.Errorf("primed key was fetched: %v", r.batches)
+	}
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
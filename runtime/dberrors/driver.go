@@ -0,0 +1,440 @@
+package dberrors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// driverError is what a database driver reports about an error: the
+// Prisma code it maps to, and the constraint, table and columns involved
+// when the driver names them
+type driverError struct {
+	code       string
+	constraint string
+	table      string
+	columns    []string
+}
+
+// driverErrorOf returns the details of the native driver error in the
+// chain of err, nil when its code is not mapped, and whether there is a
+// driver error
+func driverErrorOf(err error) (*driverError, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return postgresError(string(pqErr.Code), pqErr.Message, pqErr.Detail, pqErr.Constraint, pqErr.Table, pqErr.Column), true
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return mysqlError(int(myErr.Number), myErr.Message), true
+	}
+	var liteErr sqlite3.Error
+	if errors.As(err, &liteErr) {
+		return sqliteError(liteErr), true
+	}
+	// pgx reports *pgconn.PgError, with the fields of pq.Error named
+	// ConstraintName, TableName and ColumnName
+	var pgErr interface {
+		error
+		SQLState() string
+	}
+	if errors.As(err, &pgErr) {
+		field := func(name string) string {
+			v := reflect.Indirect(reflect.ValueOf(pgErr))
+			if v.Kind() != reflect.Struct {
+				return ""
+			}
+			if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+				return f.String()
+			}
+			return ""
+		}
+		return postgresError(pgErr.SQLState(), field("Message"), field("Detail"), field("ConstraintName"), field("TableName"), field("ColumnName")), true
+	}
+	// go-mssqldb reports mssql.Error
+	var msErr interface {
+		error
+		SQLErrorNumber() int32
+	}
+	if errors.As(err, &msErr) {
+		return sqlServerError(int(msErr.SQLErrorNumber()), msErr.Error()), true
+	}
+	return nil, false
+}
+
+// postgresCodes maps SQLSTATE codes to Prisma codes
+var postgresCodes = map[string]string{
+	"08001": "P1001", // sqlclient_unable_to_establish_sqlconnection
+	"08006": "P1001", // connection_failure
+	"22001": "P2000", // string_data_right_truncation
+	"22003": "P2020", // numeric_value_out_of_range
+	"22P02": "P2023", // invalid_text_representation
+	"23001": "P2003", // restrict_violation
+	"23502": "P2011", // not_null_violation
+	"23503": "P2003", // foreign_key_violation
+	"23505": "P2002", // unique_violation
+	"23514": "P2004", // check_violation
+	"28000": "P1000", // invalid_authorization_specification
+	"28P01": "P1000", // invalid_password
+	"3D000": "P1003", // invalid_catalog_name
+	"40001": "P2034", // serialization_failure
+	"40P01": "P2034", // deadlock_detected
+	"42501": "P1010", // insufficient_privilege
+	"42703": "P2022", // undefined_column
+	"42P01": "P2021", // undefined_table
+	"42P04": "P1009", // duplicate_database
+	"53300": "P2037", // too_many_connections
+	"57014": "P1008", // query_canceled, e.g. by statement_timeout
+	"57P01": "P1017", // admin_shutdown
+}
+
+// postgresKey matches the columns in the detail of a constraint violation,
+// e.g. `Key (email)=(a@b.c) already exists.`
+var postgresKey = regexp.MustCompile(`^Key \((.+?)\)=`)
+
+func postgresError(sqlState, message, detail, constraint, table, column string) *driverError {
+	code, ok := postgresCodes[sqlState]
+	if !ok {
+		return nil
+	}
+	d := &driverError{code: code, constraint: constraint, table: table}
+	switch {
+	case column != "":
+		d.columns = []string{column}
+	case code == "P2021":
+		// relation "User" does not exist
+		d.table = quoted(message, `"`)
+	case code == "P2022":
+		// column "name" does not exist, column User.name does not exist
+		d.columns = []string{quoted(message, `"`)}
+		if d.columns[0] == "" {
+			d.columns[0] = strings.TrimSuffix(strings.TrimPrefix(message, "column "), " does not exist")
+		}
+	case strings.Contains(detail, "is still referenced"):
+		// The key is of the referenced table, not the table of the
+		// constraint; its columns follow from the constraint name
+	default:
+		if m := postgresKey.FindStringSubmatch(detail); m != nil {
+			d.columns = splitColumns(m[1])
+		}
+	}
+	return d
+}
+
+// mysqlCodes maps MySQL error numbers to Prisma codes
+var mysqlCodes = map[int]string{
+	1007: "P1009", // ER_DB_CREATE_EXISTS
+	1040: "P2037", // ER_CON_COUNT_ERROR
+	1044: "P1010", // ER_DBACCESS_DENIED_ERROR
+	1045: "P1000", // ER_ACCESS_DENIED_ERROR
+	1048: "P2011", // ER_BAD_NULL_ERROR
+	1049: "P1003", // ER_BAD_DB_ERROR
+	1054: "P2022", // ER_BAD_FIELD_ERROR
+	1062: "P2002", // ER_DUP_ENTRY
+	1142: "P1010", // ER_TABLEACCESS_DENIED_ERROR
+	1146: "P2021", // ER_NO_SUCH_TABLE
+	1205: "P2034", // ER_LOCK_WAIT_TIMEOUT
+	1213: "P2034", // ER_LOCK_DEADLOCK
+	1264: "P2020", // ER_WARN_DATA_OUT_OF_RANGE
+	1364: "P2011", // ER_NO_DEFAULT_FOR_FIELD
+	1406: "P2000", // ER_DATA_TOO_LONG
+	1451: "P2003", // ER_ROW_IS_REFERENCED_2
+	1452: "P2003", // ER_NO_REFERENCED_ROW_2
+	1586: "P2002", // ER_DUP_ENTRY_WITH_KEY_NAME
+	3819: "P2004", // ER_CHECK_CONSTRAINT_VIOLATED
+	4025: "P2004", // ER_CONSTRAINT_FAILED (MariaDB)
+}
+
+var (
+	// Duplicate entry 'a@b.c' for key 'User.User_email_key'
+	mysqlDuplicateKey = regexp.MustCompile(`for key '([^']+)'`)
+	// a foreign key constraint fails (`db`.`Post`, CONSTRAINT `Post_authorId_fkey` FOREIGN KEY (`authorId`) REFERENCES ...
+	mysqlForeignKey = regexp.MustCompile("\\(`(?:[^`]+`\\.`)?([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(([^)]+)\\)")
+)
+
+func mysqlError(number int, message string) *driverError {
+	code, ok := mysqlCodes[number]
+	if !ok {
+		return nil
+	}
+	d := &driverError{code: code}
+	switch number {
+	case 1062, 1586:
+		if m := mysqlDuplicateKey.FindStringSubmatch(message); m != nil {
+			// MySQL 8 prefixes the key with its table
+			d.constraint = m[1]
+			if table, key, ok := strings.Cut(m[1], "."); ok {
+				d.table, d.constraint = table, key
+			}
+		}
+	case 1451, 1452:
+		if m := mysqlForeignKey.FindStringSubmatch(message); m != nil {
+			d.table, d.constraint, d.columns = m[1], m[2], splitColumns(m[3])
+		}
+	case 3819, 4025:
+		// Check constraint 'User_age_check' is violated, or on MariaDB
+		// CONSTRAINT `User_age_check` failed for `db`.`User`
+		d.constraint = quoted(message, "'")
+		if d.constraint == "" {
+			d.constraint = quoted(message, "`")
+		}
+	case 1048, 1054, 1364, 1406, 1264:
+		// Column 'name' cannot be null, Field 'name' doesn't have a default value
+		if column := quoted(message, "'"); column != "" {
+			d.columns = []string{column}
+		}
+	case 1146:
+		// Table 'db.User' doesn't exist
+		table := quoted(message, "'")
+		if i := strings.LastIndex(table, "."); i >= 0 {
+			table = table[i+1:]
+		}
+		d.table = table
+	}
+	return d
+}
+
+// sqliteCodes maps SQLite extended result codes to Prisma codes
+var sqliteCodes = map[sqlite3.ErrNoExtended]string{
+	sqlite3.ErrConstraintUnique:     "P2002",
+	sqlite3.ErrConstraintPrimaryKey: "P2002",
+	sqlite3.ErrConstraintForeignKey: "P2003",
+	sqlite3.ErrConstraintNotNull:    "P2011",
+	sqlite3.ErrConstraintCheck:      "P2004",
+}
+
+func sqliteError(err sqlite3.Error) *driverError {
+	message := err.Error()
+	code, ok := sqliteCodes[err.ExtendedCode]
+	if !ok {
+		// Primary result codes, for errors without an extended code
+		switch {
+		case err.Code == sqlite3.ErrBusy || err.Code == sqlite3.ErrLocked:
+			code = "P1008"
+		case err.Code == sqlite3.ErrCantOpen:
+			code = "P1003"
+		case err.Code == sqlite3.ErrPerm || err.Code == sqlite3.ErrAuth:
+			code = "P1010"
+		case err.Code == sqlite3.ErrTooBig:
+			code = "P2000"
+		case err.Code == sqlite3.ErrError && strings.HasPrefix(message, "no such table: "):
+			return &driverError{code: "P2021", table: strings.TrimPrefix(message, "no such table: ")}
+		case err.Code == sqlite3.ErrError && strings.HasPrefix(message, "no such column: "):
+			return &driverError{code: "P2022", columns: []string{strings.TrimPrefix(message, "no such column: ")}}
+		default:
+			return nil
+		}
+		return &driverError{code: code}
+	}
+
+	d := &driverError{code: code}
+	// UNIQUE constraint failed: User.email, User.name
+	// CHECK constraint failed: age_positive
+	_, detail, ok := strings.Cut(message, "constraint failed: ")
+	if !ok {
+		return d
+	}
+	if code == "P2004" {
+		d.constraint = detail
+		return d
+	}
+	for _, column := range strings.Split(detail, ", ") {
+		if table, name, ok := strings.Cut(column, "."); ok {
+			d.table = table
+			column = name
+		}
+		d.columns = append(d.columns, column)
+	}
+	return d
+}
+
+// sqlServerCodes maps SQL Server error numbers to Prisma codes
+var sqlServerCodes = map[int]string{
+	207:   "P2022", // Invalid column name
+	208:   "P2021", // Invalid object name
+	220:   "P2020", // Arithmetic overflow
+	229:   "P1010", // Permission denied
+	515:   "P2011", // Cannot insert the value NULL
+	547:   "P2003", // Statement conflicted with a constraint
+	1205:  "P2034", // Deadlock victim
+	1801:  "P1009", // Database already exists
+	2601:  "P2002", // Duplicate key row in unique index
+	2627:  "P2002", // Violation of unique or primary key constraint
+	2628:  "P2000", // String or binary data would be truncated
+	4060:  "P1003", // Cannot open database
+	8115:  "P2020", // Arithmetic overflow
+	8152:  "P2000", // String or binary data would be truncated
+	18456: "P1000", // Login failed
+}
+
+func sqlServerError(number int, message string) *driverError {
+	code, ok := sqlServerCodes[number]
+	if !ok {
+		return nil
+	}
+	d := &driverError{code: code}
+	switch number {
+	case 2627:
+		// Violation of UNIQUE KEY constraint 'User_email_key'. Cannot insert
+		// duplicate key in object 'dbo.User'. ...
+		d.constraint = quoted(message, "'")
+		d.table = afterQuoted(message, "object ")
+	case 2601:
+		// Cannot insert duplicate key row in object 'dbo.User' with unique
+		// index 'User_email_key'. ...
+		d.table = afterQuoted(message, "object ")
+		d.constraint = afterQuoted(message, "index ")
+	case 547:
+		// The INSERT statement conflicted with the FOREIGN KEY constraint
+		// "Post_authorId_fkey". ... The table named in the message is the
+		// referenced one, not the table of the constraint.
+		d.constraint = quoted(message, `"`)
+		if strings.Contains(message, "CHECK constraint") {
+			d.code = "P2004"
+		}
+	case 515:
+		// Cannot insert the value NULL into column 'name', table 'db.dbo.User'; ...
+		d.columns = []string{afterQuoted(message, "column ")}
+		d.table = afterQuoted(message, "table ")
+	case 207:
+		d.columns = []string{quoted(message, "'")}
+	case 208:
+		d.table = quoted(message, "'")
+	}
+	if i := strings.LastIndex(d.table, "."); i >= 0 {
+		d.table = d.table[i+1:]
+	}
+	return d
+}
+
+// quoted returns the first text in message between quote characters
+func quoted(message, quote string) string {
+	_, rest, ok := strings.Cut(message, quote)
+	if !ok {
+		return ""
+	}
+	text, _, _ := strings.Cut(rest, quote)
+	return text
+}
+
+// afterQuoted returns the quoted text following prefix in message
+func afterQuoted(message, prefix string) string {
+	_, rest, ok := strings.Cut(message, prefix)
+	if !ok {
+		return ""
+	}
+	return quoted(rest, "'")
+}
+
+// splitColumns splits a list of column names as drivers print them, e.g.
+// `"firstName", "lastName"` or "`authorId`"
+func splitColumns(list string) []string {
+	var columns []string
+	for _, column := range strings.Split(list, ",") {
+		columns = append(columns, strings.Trim(strings.TrimSpace(column), "\"`[]"))
+	}
+	return columns
+}
+
+// newDriverError builds the PrismaError of a driver error. Meta holds the
+// model ("modelName"), the constraint ("constraint") and the fields
+// ("target") of the error when they are known.
+func newDriverError(table string, d *driverError, resolve Resolver, cause error) *PrismaError {
+	if d.table != "" {
+		table = d.table
+	}
+	model, fields := "", d.columns
+	if resolve != nil {
+		model, fields = resolve(table, d.constraint, d.columns)
+	}
+
+	e := NewPrismaError(d.code, driverMessage(d.code, d.constraint, table, fields, cause)).WithCause(cause)
+	if model != "" {
+		e.WithModel(model).WithMeta("modelName", model)
+	}
+	if d.constraint != "" {
+		e.WithMeta("constraint", d.constraint)
+	}
+	if len(fields) > 0 {
+		e.WithMeta("target", fields)
+		if len(fields) == 1 {
+			e.WithField(fields[0])
+		}
+	}
+	return e
+}
+
+// driverMessage returns the message of a Prisma code, with the details of
+// the error
+func driverMessage(code, constraint, table string, fields []string, cause error) string {
+	target := "(`" + strings.Join(fields, "`, `") + "`)"
+	switch code {
+	case "P2002":
+		switch {
+		case len(fields) > 0:
+			return "Unique constraint failed on the fields: " + target
+		case constraint != "":
+			return fmt.Sprintf("Unique constraint failed on the constraint: `%s`", constraint)
+		}
+		return "Unique constraint failed"
+	case "P2003":
+		switch {
+		case len(fields) > 0:
+			return "Foreign key constraint failed on the field: " + target
+		case constraint != "":
+			return fmt.Sprintf("Foreign key constraint failed on the constraint: `%s`", constraint)
+		}
+		return "Foreign key constraint failed"
+	case "P2011":
+		if len(fields) > 0 {
+			return "Null constraint violation on the fields: " + target
+		}
+		return "Null constraint violation"
+	case "P2004":
+		if constraint != "" {
+			return fmt.Sprintf("A constraint failed on the database: `%s`", constraint)
+		}
+		return "A constraint failed on the database"
+	case "P2000":
+		if len(fields) > 0 {
+			return "The provided value for the column is too long for the column's type. Column: " + fields[0]
+		}
+		return "The provided value for the column is too long for the column's type"
+	case "P2020":
+		return "Value out of range for the type: " + cause.Error()
+	case "P2021":
+		return fmt.Sprintf("The table `%s` does not exist in the current database", table)
+	case "P2022":
+		if len(fields) > 0 {
+			return fmt.Sprintf("The column `%s` does not exist in the current database", fields[0])
+		}
+		return "The column does not exist in the current database"
+	case "P2023":
+		return "Inconsistent column data: " + cause.Error()
+	case "P2034":
+		return "Transaction failed due to a write conflict or a deadlock. Please retry your transaction"
+	case "P2037":
+		return "Too many database connections opened: " + cause.Error()
+	case "P1000":
+		return "Authentication failed against the database server"
+	case "P1001":
+		return "Can't reach the database server"
+	case "P1003":
+		return "Database does not exist"
+	case "P1008":
+		return "Operation timed out"
+	case "P1009":
+		return "Database already exists"
+	case "P1010":
+		return "User was denied access on the database"
+	case "P1017":
+		return "Server has closed the connection"
+	}
+	return cause.Error()
+}
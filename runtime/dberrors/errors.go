@@ -0,0 +1,180 @@
+// Package dberrors classifies database errors into PrismaErrors with Prisma
+// error codes, for the client runtime and migrations alike.
+package dberrors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error types of classified errors
+var (
+	// ErrNotFound is returned when a record is not found
+	ErrNotFound = errors.New("record not found")
+
+	// ErrUniqueConstraint is returned when a unique constraint is violated
+	ErrUniqueConstraint = errors.New("unique constraint violation")
+
+	// ErrForeignKeyConstraint is returned when a foreign key constraint is violated
+	ErrForeignKeyConstraint = errors.New("foreign key constraint violation")
+
+	// ErrNullConstraint is returned when a null constraint is violated
+	ErrNullConstraint = errors.New("null constraint violation")
+
+	// ErrValidationFailed is returned when input data fails validation
+	ErrValidationFailed = errors.New("validation failed")
+
+	// ErrTimeout is returned when an operation times out
+	ErrTimeout = errors.New("operation timeout")
+
+	// ErrCanceled is returned when an operation is canceled
+	ErrCanceled = errors.New("operation canceled")
+)
+
+// PrismaError is an error classified with a Prisma error code, e.g. P2002
+// for a unique constraint violation
+type PrismaError struct {
+	Code    string                 // Error code (e.g., "P2002", "P2025")
+	Message string                 // Human-readable error message
+	Meta    map[string]interface{} // Additional error metadata
+	Cause   error                  // Underlying error cause
+	Model   string                 // Model name if applicable
+	Field   string                 // Field name if applicable
+}
+
+// Error implements the error interface
+func (e *PrismaError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+	}
+	return e.Message
+}
+
+// Unwrap implements error unwrapping
+func (e *PrismaError) Unwrap() error {
+	return e.Cause
+}
+
+// codeErrors are the sentinel errors matched by the PrismaErrors of a code,
+// whatever their cause
+var codeErrors = map[string]error{
+	"P1008": ErrTimeout,
+	"P2002": ErrUniqueConstraint,
+	"P2003": ErrForeignKeyConstraint,
+	"P2009": ErrValidationFailed,
+	"P2011": ErrNullConstraint,
+	"P2025": ErrNotFound,
+}
+
+// Is implements error comparison. A PrismaError matches the sentinel error
+// of its code, e.g. ErrUniqueConstraint for P2002, and what its cause
+// matches.
+func (e *PrismaError) Is(target error) bool {
+	if sentinel, ok := codeErrors[e.Code]; ok && sentinel == target {
+		return true
+	}
+	return errors.Is(e.Cause, target)
+}
+
+// NewPrismaError creates a new PrismaError
+func NewPrismaError(code, message string) *PrismaError {
+	return &PrismaError{
+		Code:    code,
+		Message: message,
+		Meta:    make(map[string]interface{}),
+	}
+}
+
+// WithCause adds the underlying cause
+func (e *PrismaError) WithCause(err error) *PrismaError {
+	e.Cause = err
+	return e
+}
+
+// WithModel adds the model name
+func (e *PrismaError) WithModel(model string) *PrismaError {
+	e.Model = model
+	return e
+}
+
+// WithField adds the field name
+func (e *PrismaError) WithField(field string) *PrismaError {
+	e.Field = field
+	return e
+}
+
+// WithMeta adds metadata
+func (e *PrismaError) WithMeta(key string, value interface{}) *PrismaError {
+	e.Meta[key] = value
+	return e
+}
+
+// ClassifyError classifies a database error into a PrismaError. Errors that
+// are already classified are returned as they are. Errors of the database
+// drivers are classified by their native codes: SQLSTATE for PostgreSQL,
+// error numbers for MySQL and SQL Server, and extended result codes for
+// SQLite. The cause of such errors is the driver error, and their Meta the
+// constraint ("constraint") and columns ("target") named by the driver; see
+// Classify for errors naming models and fields. Other errors are
+// classified by their message.
+func ClassifyError(err error) error {
+	return Classify("", err, nil)
+}
+
+// Resolver returns the model of table and the fields of columns, the table
+// and columns of a driver error, or "" and nil when they are not known
+type Resolver func(table, constraint string, columns []string) (model string, fields []string)
+
+// Classify classifies err like ClassifyError, with the table and columns
+// named by the driver resolved to the model and its fields by resolve.
+// table is the table of the failed query, the model of errors that do not
+// name their table.
+func Classify(table string, err error, resolve Resolver) error {
+	if err == nil {
+		return nil
+	}
+	var prismaErr *PrismaError
+	if errors.As(err, &prismaErr) {
+		return prismaErr
+	}
+	d, native := driverErrorOf(err)
+	switch {
+	case d != nil:
+		return newDriverError(table, d, resolve, err)
+	case native:
+		// The message of a driver error with a code that is not mapped,
+		// e.g. a syntax error, says nothing about its class
+		return NewPrismaError("P0000", err.Error()).WithCause(err)
+	}
+	return classifyMessage(err)
+}
+
+// classifyMessage classifies an error that is not a driver error by its
+// message
+func classifyMessage(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewPrismaError("P1008", "Operation timed out").WithCause(ErrTimeout)
+	case errors.Is(err, context.Canceled):
+		return NewPrismaError("P1017", "Operation was canceled").WithCause(ErrCanceled)
+	case errors.Is(err, sql.ErrNoRows):
+		return NewPrismaError("P2025", "Record not found").WithCause(ErrNotFound)
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "unique") || strings.Contains(msg, "duplicate"):
+		return NewPrismaError("P2002", "Unique constraint violation").WithCause(ErrUniqueConstraint).WithMeta("cause", err.Error())
+	case strings.Contains(msg, "foreign key"):
+		return NewPrismaError("P2003", "Foreign key constraint violation").WithCause(ErrForeignKeyConstraint).WithMeta("cause", err.Error())
+	case strings.Contains(msg, "not null") || strings.Contains(msg, "cannot be null"):
+		return NewPrismaError("P2011", "Null constraint violation").WithCause(ErrNullConstraint).WithMeta("cause", err.Error())
+	case strings.Contains(msg, "no rows found"):
+		return NewPrismaError("P2025", "Record not found").WithCause(ErrNotFound)
+	}
+
+	return NewPrismaError("P0000", err.Error()).WithCause(err)
+}
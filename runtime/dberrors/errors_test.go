@@ -0,0 +1,67 @@
+package dberrors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestClassifyError(t *testing.T) {
+	for _, tt := range []struct {
+		err   error
+		code  string
+		cause error
+	}{
+		{sql.ErrNoRows, "P2025", ErrNotFound},
+		{fmt.Errorf("query failed: %w", context.DeadlineExceeded), "P1008", ErrTimeout},
+		{errors.New("insert failed: UNIQUE constraint failed: User.email"), "P2002", ErrUniqueConstraint},
+		{errors.New(`pq: duplicate key value violates unique constraint "User_email_key"`), "P2002", ErrUniqueConstraint},
+		{errors.New("insert failed: FOREIGN KEY constraint failed"), "P2003", ErrForeignKeyConstraint},
+		{errors.New("insert failed: NOT NULL constraint failed: User.name"), "P2011", ErrNullConstraint},
+		{NewPrismaError("P2009", "email is required").WithCause(ErrValidationFailed), "P2009", ErrValidationFailed},
+	} {
+		err := ClassifyError(tt.err)
+		var prismaErr *PrismaError
+		if !errors.As(err, &prismaErr) {
+			t.Fatalf("ClassifyError(%v) = %T", tt.err, err)
+		}
+		if prismaErr.Code != tt.code || !errors.Is(err, tt.cause) {
+			t.Errorf("ClassifyError(%v) = %v, want %s wrapping %v", tt.err, err, tt.code, tt.cause)
+		}
+	}
+
+	if ClassifyError(nil) != nil {
+		t.Error("ClassifyError(nil) != nil")
+	}
+	err := ClassifyError(errors.New("syntax error"))
+	if err.Error() != "[P0000] syntax error" {
+		t.Errorf("unclassified error = %q", err)
+	}
+}
+
+func TestClassifyDriverErrorMeta(t *testing.T) {
+	driverErr := &pq.Error{Code: "23505", Constraint: "users_email_key", Detail: "Key (email)=(a@b.c) already exists.", Table: "users"}
+
+	var prismaErr *PrismaError
+	if !errors.As(ClassifyError(driverErr), &prismaErr) {
+		t.Fatal("driver error is not classified")
+	}
+	if prismaErr.Meta["constraint"] != "users_email_key" || !reflect.DeepEqual(prismaErr.Meta["target"], []string{"email"}) || prismaErr.Model != "" {
+		t.Errorf("meta = %v, model %q, want the constraint and columns of the driver", prismaErr.Meta, prismaErr.Model)
+	}
+
+	resolve := func(table, constraint string, columns []string) (string, []string) {
+		return "User", []string{"emailAddress"}
+	}
+	if !errors.As(Classify("", driverErr, resolve), &prismaErr) {
+		t.Fatal("driver error is not classified")
+	}
+	if prismaErr.Model != "User" || prismaErr.Meta["modelName"] != "User" || prismaErr.Field != "emailAddress" {
+		t.Errorf("model %q, field %q, meta %v, want those resolved", prismaErr.Model, prismaErr.Field, prismaErr.Meta)
+	}
+}
@@ -0,0 +1,240 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/satishbabariya/prisma-go/query/cache"
+)
+
+// Snapshot holds the values of the metrics at a point in time, shaped like
+// the JSON output of Prisma's $metrics
+type Snapshot struct {
+	Counters   []Metric `json:"counters"`
+	Gauges     []Metric `json:"gauges"`
+	Histograms []Metric `json:"histograms"`
+}
+
+// Metric is the value of a metric for a set of labels. Value is a float64
+// for counters and gauges, and a HistogramValue for histograms.
+type Metric struct {
+	Key         string            `json:"key"`
+	Labels      map[string]string `json:"labels"`
+	Value       interface{}       `json:"value"`
+	Description string            `json:"description"`
+}
+
+// HistogramValue is the value of a histogram. Buckets holds the upper bound
+// of each bucket and the number of observations in it, not counting those
+// of lower buckets; Count also counts the observations above the last
+// bound.
+type HistogramValue struct {
+	Buckets [][2]float64 `json:"buckets"`
+	Sum     float64      `json:"sum"`
+	Count   int64        `json:"count"`
+}
+
+// Descriptions of the metrics
+var descriptions = map[string]string{
+	"prisma_client_queries_duration_seconds":  "Duration of the operations of the client by model, operation and outcome",
+	"prisma_client_errors_total":              "Failed operations by model, operation and Prisma error code",
+	"prisma_client_cache_hits_total":          "Queries answered by the query cache",
+	"prisma_client_cache_misses_total":        "Queries missing from the query cache",
+	"prisma_client_cache_evictions_total":     "Entries evicted from the query cache",
+	"prisma_client_cache_entries":             "Entries in the query cache",
+	"prisma_pool_connections_open":            "Open connections of the pool",
+	"prisma_pool_connections_idle":            "Idle connections of the pool",
+	"prisma_pool_connections_busy":            "Connections of the pool in use",
+	"prisma_pool_connections_max":             "Maximum number of open connections of the pool, 0 for unlimited",
+	"prisma_pool_wait_count_total":            "Connections waited for",
+	"prisma_pool_wait_duration_seconds_total": "Time spent waiting for connections",
+}
+
+// Snapshot returns the current values of the metrics, sorted by key and
+// labels. The statistics of caches and pools are read now.
+func (m *Metrics) Snapshot() Snapshot {
+	m.mu.Lock()
+	s := Snapshot{Counters: []Metric{}, Gauges: []Metric{}, Histograms: []Metric{}}
+	for l, h := range m.durations {
+		value := HistogramValue{Sum: h.sum, Count: h.count, Buckets: make([][2]float64, len(m.buckets))}
+		for i, bound := range m.buckets {
+			value.Buckets[i] = [2]float64{bound, float64(h.counts[i])}
+		}
+		s.Histograms = append(s.Histograms, newMetric("prisma_client_queries_duration_seconds", value,
+			"model", l.model, "operation", l.operation, "outcome", l.outcome))
+	}
+	for l, n := range m.errors {
+		s.Counters = append(s.Counters, newMetric("prisma_client_errors_total", float64(n),
+			"model", l.model, "operation", l.operation, "code", l.code))
+	}
+	caches := make(map[string]func() cache.Stats, len(m.caches))
+	for name, stats := range m.caches {
+		caches[name] = stats
+	}
+	pools := make(map[string]func() PoolStats, len(m.pools))
+	for name, stats := range m.pools {
+		pools[name] = stats
+	}
+	m.mu.Unlock()
+
+	// Caches and pools are read without the lock, which their statistics
+	// functions may take themselves
+	for name, stats := range caches {
+		c := stats()
+		s.Counters = append(s.Counters,
+			newMetric("prisma_client_cache_hits_total", float64(c.Hits), "cache", name),
+			newMetric("prisma_client_cache_misses_total", float64(c.Misses), "cache", name),
+			newMetric("prisma_client_cache_evictions_total", float64(c.Evictions), "cache", name))
+		s.Gauges = append(s.Gauges, newMetric("prisma_client_cache_entries", float64(c.Size), "cache", name))
+	}
+	for name, stats := range pools {
+		p := stats()
+		s.Counters = append(s.Counters,
+			newMetric("prisma_pool_wait_count_total", float64(p.WaitCount), "pool", name),
+			newMetric("prisma_pool_wait_duration_seconds_total", p.WaitDuration.Seconds(), "pool", name))
+		s.Gauges = append(s.Gauges,
+			newMetric("prisma_pool_connections_open", float64(p.Open), "pool", name),
+			newMetric("prisma_pool_connections_idle", float64(p.Idle), "pool", name),
+			newMetric("prisma_pool_connections_busy", float64(p.InUse), "pool", name),
+			newMetric("prisma_pool_connections_max", float64(p.MaxOpen), "pool", name))
+	}
+
+	for _, metrics := range [][]Metric{s.Counters, s.Gauges, s.Histograms} {
+		sort.Slice(metrics, func(i, j int) bool {
+			if metrics[i].Key != metrics[j].Key {
+				return metrics[i].Key < metrics[j].Key
+			}
+			return labelString(metrics[i].Labels) < labelString(metrics[j].Labels)
+		})
+	}
+	return s
+}
+
+// newMetric returns the metric key with value and labels given as name and
+// value pairs
+func newMetric(key string, value interface{}, labels ...string) Metric {
+	m := Metric{Key: key, Labels: make(map[string]string, len(labels)/2), Value: value, Description: descriptions[key]}
+	for i := 0; i+1 < len(labels); i += 2 {
+		m.Labels[labels[i]] = labels[i+1]
+	}
+	return m
+}
+
+// JSON returns the metrics as JSON, like Prisma's $metrics.json()
+func (m *Metrics) JSON() ([]byte, error) {
+	return json.Marshal(m.Snapshot())
+}
+
+// WriteText writes the metrics to w in the Prometheus text exposition
+// format
+func (m *Metrics) WriteText(w io.Writer) error {
+	s := m.Snapshot()
+	bw := bufio.NewWriter(w)
+	for _, family := range []struct {
+		kind    string
+		metrics []Metric
+	}{
+		{"counter", s.Counters},
+		{"gauge", s.Gauges},
+		{"histogram", s.Histograms},
+	} {
+		for i, metric := range family.metrics {
+			if i == 0 || family.metrics[i-1].Key != metric.Key {
+				fmt.Fprintf(bw, "# HELP %s %s\n", metric.Key, metric.Description)
+				fmt.Fprintf(bw, "# TYPE %s %s\n", metric.Key, family.kind)
+			}
+			labels := labelString(metric.Labels)
+			value, ok := metric.Value.(HistogramValue)
+			if !ok {
+				fmt.Fprintf(bw, "%s%s %s\n", metric.Key, braces(labels), formatFloat(metric.Value.(float64)))
+				continue
+			}
+			var cumulative float64
+			for _, bucket := range value.Buckets {
+				cumulative += bucket[1]
+				fmt.Fprintf(bw, "%s_bucket%s %s\n", metric.Key, braces(joinLabels(labels, bucket[0])), formatFloat(cumulative))
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", metric.Key, braces(joinLabels(labels, math.Inf(1))), value.Count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", metric.Key, braces(labels), formatFloat(value.Sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", metric.Key, braces(labels), value.Count)
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler returns an http.Handler serving the metrics in the Prometheus
+// text format, or as JSON when requested with ?format=json or an Accept
+// header of application/json
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			body, err := m.JSON()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(body)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteText(w)
+	})
+}
+
+// labelString returns labels in the text format, sorted by name, without
+// braces
+func labelString(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escapeLabel(labels[name]) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+// joinLabels adds the le label of a bucket bound to labels
+func joinLabels(labels string, bound float64) string {
+	le := `le="` + formatFloat(bound) + `"`
+	if labels == "" {
+		return le
+	}
+	return labels + "," + le
+}
+
+// braces wraps labels in braces, if there are any
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+// escapeLabel escapes a label value for the text format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatFloat formats a sample value for the text format
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,197 @@
+// Package metrics collects the statistics of Prisma clients and exposes
+// them in the Prometheus text format, or as JSON shaped like the output of
+// Prisma's $metrics.
+//
+// A Metrics observes the operations of the generated client to record their
+// duration by model, operation and outcome and to count errors by Prisma
+// code. The statistics of query caches and connection pools are read when
+// the metrics are exposed.
+//
+//	m := metrics.New()
+//	m.Instrument(prisma.PrismaClient)
+//	http.Handle("/metrics", m.Handler())
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/query/cache"
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/runtime/client"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of the
+// query duration histogram
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Outcomes of queries
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// PoolStats holds the statistics of a connection pool
+type PoolStats struct {
+	MaxOpen      int
+	Open         int
+	Idle         int
+	InUse        int
+	WaitCount    int64
+	WaitDuration time.Duration
+}
+
+// DBStats returns the statistics of the pool of db, to register it with
+// RegisterPool
+func DBStats(db *sql.DB) func() PoolStats {
+	return func() PoolStats {
+		stats := db.Stats()
+		return PoolStats{
+			MaxOpen:      stats.MaxOpenConnections,
+			Open:         stats.OpenConnections,
+			Idle:         stats.Idle,
+			InUse:        stats.InUse,
+			WaitCount:    stats.WaitCount,
+			WaitDuration: stats.WaitDuration,
+		}
+	}
+}
+
+// Metrics collects the metrics of clients. It is an executor.Observer; its
+// methods are safe for concurrent use.
+type Metrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	durations map[queryLabels]*histogram
+	errors    map[errorLabels]int64
+	caches    map[string]func() cache.Stats
+	pools     map[string]func() PoolStats
+}
+
+// queryLabels are the labels of the query duration histogram
+type queryLabels struct {
+	model, operation, outcome string
+}
+
+// errorLabels are the labels of the error counter
+type errorLabels struct {
+	model, operation, code string
+}
+
+// histogram counts observations by bucket
+type histogram struct {
+	counts []int64 // by bucket, the last one being +Inf
+	sum    float64
+	count  int64
+}
+
+// Option configures Metrics
+type Option func(*Metrics)
+
+// WithBuckets sets the upper bounds, in seconds, of the buckets of the
+// query duration histogram
+func WithBuckets(buckets ...float64) Option {
+	return func(m *Metrics) {
+		m.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(m.buckets)
+	}
+}
+
+// New creates empty metrics
+func New(opts ...Option) *Metrics {
+	m := &Metrics{
+		buckets:   DefaultBuckets,
+		durations: make(map[queryLabels]*histogram),
+		errors:    make(map[errorLabels]int64),
+		caches:    make(map[string]func() cache.Stats),
+		pools:     make(map[string]func() PoolStats),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Instrument records the operations of c, and registers its query cache and
+// connection pool as "default"
+func (m *Metrics) Instrument(c *client.PrismaClient) {
+	c.Observe(m)
+	m.RegisterCache("default", c.GetCacheStats)
+	m.RegisterPool("default", DBStats(c.DB()))
+}
+
+// RegisterCache exposes the statistics of a query cache under name
+func (m *Metrics) RegisterCache(name string, stats func() cache.Stats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.caches[name] = stats
+}
+
+// RegisterPool exposes the statistics of a connection pool under name
+func (m *Metrics) RegisterPool(name string, stats func() PoolStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pools[name] = stats
+}
+
+// RecordQuery records a query of operation on model that took duration.
+// It makes Metrics a MetricsRecorder of the middleware of the v3 runtime.
+func (m *Metrics) RecordQuery(model, operation string, duration time.Duration, success bool) {
+	outcome := OutcomeSuccess
+	if !success {
+		outcome = OutcomeError
+	}
+	m.observe(queryLabels{model, operation, outcome}, duration)
+}
+
+// RecordError counts err, a failure of operation on model, by its Prisma
+// code. Errors that are not classified are counted with the code P0000.
+func (m *Metrics) RecordError(model, operation string, err error) {
+	code := "P0000"
+	var prismaErr *client.PrismaError
+	if errors.As(err, &prismaErr) && prismaErr.Code != "" {
+		code = prismaErr.Code
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[errorLabels{model, operation, code}]++
+}
+
+// StartOperation times an operation, recording its duration and error when
+// it ends
+func (m *Metrics) StartOperation(ctx context.Context, op *executor.Operation) (context.Context, func(err error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		m.RecordQuery(op.Model, op.Name, time.Since(start), err == nil)
+		if err != nil {
+			m.RecordError(op.Model, op.Name, err)
+		}
+	}
+}
+
+// StartStatement does not record statements, which are accounted for by
+// their operation
+func (m *Metrics) StartStatement(ctx context.Context, stmt *executor.Statement) (context.Context, func(err error)) {
+	return ctx, nil
+}
+
+// observe adds an observation of d to the histogram of labels
+func (m *Metrics) observe(labels queryLabels, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.durations[labels]
+	if !ok {
+		h = &histogram{counts: make([]int64, len(m.buckets)+1)}
+		m.durations[labels] = h
+	}
+	seconds := d.Seconds()
+	i := sort.SearchFloat64s(m.buckets, seconds)
+	h.counts[i]++
+	h.sum += seconds
+	h.count++
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satishbabariya/prisma-go/query/cache"
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/runtime/client"
+)
+
+func TestObserveOperations(t *testing.T) {
+	c, err := client.NewPrismaClient("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect(context.Background())
+	c.SetMaxOpenConns(1)
+	c.EnableCache(10, time.Minute)
+	if _, err := c.RawExec(context.Background(), `CREATE TABLE users (id INTEGER PRIMARY KEY)`); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New()
+	m.Instrument(c)
+	exec := executor.NewExecutor(c.DB(), "sqlite")
+	exec.SetObservers(c.Observers())
+	exec.SetModelNames(map[string]string{"users": "User"})
+	exec.SetErrorMapper(client.SchemaNames{}.ClassifyError)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := exec.Count(ctx, "users", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := exec.Count(ctx, "posts", nil); err == nil {
+		t.Fatal("count of a missing table succeeded")
+	}
+
+	var out strings.Builder
+	if err := m.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	for _, line := range []string{
+		"# TYPE prisma_client_queries_duration_seconds histogram",
+		`prisma_client_queries_duration_seconds_count{model="User",operation="count",outcome="success"} 2`,
+		`prisma_client_queries_duration_seconds_bucket{model="User",operation="count",outcome="success",le="+Inf"} 2`,
+		`prisma_client_queries_duration_seconds_count{model="posts",operation="count",outcome="error"} 1`,
+		`prisma_client_errors_total{code="P2021",model="posts",operation="count"} 1`,
+		`prisma_client_cache_misses_total{cache="default"} 0`,
+		`prisma_pool_connections_max{pool="default"} 1`,
+		"# TYPE prisma_pool_connections_idle gauge",
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, text)
+		}
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	m := New(WithBuckets(0.1, 0.01))
+	m.RecordQuery("User", "findMany", 5*time.Millisecond, true)
+	m.RecordQuery("User", "findMany", 50*time.Millisecond, true)
+	m.RecordQuery("User", "findMany", time.Second, true)
+
+	var out strings.Builder
+	m.WriteText(&out)
+	for _, line := range []string{
+		`prisma_client_queries_duration_seconds_bucket{model="User",operation="findMany",outcome="success",le="0.01"} 1`,
+		`prisma_client_queries_duration_seconds_bucket{model="User",operation="findMany",outcome="success",le="0.1"} 2`,
+		`prisma_client_queries_duration_seconds_bucket{model="User",operation="findMany",outcome="success",le="+Inf"} 3`,
+		`prisma_client_queries_duration_seconds_sum{model="User",operation="findMany",outcome="success"} 1.055`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out.String())
+		}
+	}
+
+	histograms := m.Snapshot().Histograms
+	if len(histograms) != 1 {
+		t.Fatalf("%d histograms, want 1", len(histograms))
+	}
+	value := histograms[0].Value.(HistogramValue)
+	if want := [][2]float64{{0.01, 1}, {0.1, 1}}; len(value.Buckets) != 2 || value.Buckets[0] != want[0] || value.Buckets[1] != want[1] || value.Count != 3 {
+		t.Errorf("histogram = %+v, want buckets %v and count 3", value, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.RecordError("User", "create", &client.PrismaError{Code: "P2002"})
+	m.RegisterCache("users", func() cache.Stats { return cache.Stats{Hits: 3, Misses: 1, Evictions: 2} })
+	m.RegisterPool("main", func() PoolStats { return PoolStats{Open: 4, Idle: 1, InUse: 3, WaitCount: 7} })
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type = %q", ct)
+	}
+	for _, line := range []string{
+		`prisma_client_errors_total{code="P2002",model="User",operation="create"} 1`,
+		`prisma_client_cache_evictions_total{cache="users"} 2`,
+		`prisma_pool_connections_busy{pool="main"} 3`,
+		`prisma_pool_wait_count_total{pool="main"} 7`,
+	} {
+		if !strings.Contains(rec.Body.String(), line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, rec.Body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics?format=json", nil))
+	var snapshot struct {
+		Counters []struct {
+			Key    string            `json:"key"`
+			Labels map[string]string `json:"labels"`
+			Value  float64           `json:"value"`
+		} `json:"counters"`
+		Gauges     []json.RawMessage `json:"gauges"`
+		Histograms []json.RawMessage `json:"histograms"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &snapshot); err != nil {
+		t.Fatalf("invalid JSON %s: %v", rec.Body, err)
+	}
+	if snapshot.Histograms == nil || len(snapshot.Gauges) != 5 {
+		t.Errorf("JSON = %s", rec.Body)
+	}
+	hits := false
+	for _, counter := range snapshot.Counters {
+		if counter.Key == "prisma_client_cache_hits_total" && counter.Labels["cache"] == "users" && counter.Value == 3 {
+			hits = true
+		}
+	}
+	if !hits {
+		t.Errorf("no cache hits in %s", rec.Body)
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := labelString(map[string]string{"b": "x\"y", "a": `c:\d` + "\n"}); got != `a="c:\\d\n",b="x\"y"` {
+		t.Errorf("labels = %s", got)
+	}
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Attributes of pool metrics
+const (
+	PoolNameKey = attribute.Key("pool.name")
+	StateKey    = attribute.Key("state")  // idle or used
+	ReasonKey   = attribute.Key("reason") // max_idle, max_idle_time or max_lifetime
+)
+
+// RegisterPoolMetrics reports the statistics of the connection pool of db,
+// as returned by db.Stats, whenever the meter of the options is collected:
+//
+//   - db.client.connections.usage: connections by state, idle or used
+//   - db.client.connections.max: maximum number of open connections
+//   - db.client.connections.waits: connections waited for
+//   - db.client.connections.wait_time: total time waited for connections
+//   - db.client.connections.closed: connections closed by reason
+//
+// Unregister the registration to stop reporting them.
+func RegisterPoolMetrics(db *sql.DB, opts ...Option) (metric.Registration, error) {
+	c := newConfig(opts)
+	meter := c.meterProvider.Meter(instrumentationName)
+
+	usage, err := meter.Int64ObservableUpDownCounter("db.client.connections.usage",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The number of connections that are currently in the state described by the state attribute"))
+	if err != nil {
+		return nil, err
+	}
+	maxOpen, err := meter.Int64ObservableUpDownCounter("db.client.connections.max",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The maximum number of open connections allowed"))
+	if err != nil {
+		return nil, err
+	}
+	waits, err := meter.Int64ObservableCounter("db.client.connections.waits",
+		metric.WithUnit("{wait}"),
+		metric.WithDescription("The total number of connections waited for"))
+	if err != nil {
+		return nil, err
+	}
+	waitTime, err := meter.Float64ObservableCounter("db.client.connections.wait_time",
+		metric.WithUnit("s"),
+		metric.WithDescription("The total time blocked waiting for a new connection"))
+	if err != nil {
+		return nil, err
+	}
+	closed, err := meter.Int64ObservableCounter("db.client.connections.closed",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The total number of connections closed by the pool"))
+	if err != nil {
+		return nil, err
+	}
+
+	pool := attribute.NewSet(PoolNameKey.String(c.poolName))
+	idle := attribute.NewSet(PoolNameKey.String(c.poolName), StateKey.String("idle"))
+	used := attribute.NewSet(PoolNameKey.String(c.poolName), StateKey.String("used"))
+	reason := func(r string) metric.ObserveOption {
+		return metric.WithAttributeSet(attribute.NewSet(PoolNameKey.String(c.poolName), ReasonKey.String(r)))
+	}
+
+	return meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		stats := db.Stats()
+		o.ObserveInt64(usage, int64(stats.Idle), metric.WithAttributeSet(idle))
+		o.ObserveInt64(usage, int64(stats.InUse), metric.WithAttributeSet(used))
+		o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), metric.WithAttributeSet(pool))
+		o.ObserveInt64(waits, stats.WaitCount, metric.WithAttributeSet(pool))
+		o.ObserveFloat64(waitTime, stats.WaitDuration.Seconds(), metric.WithAttributeSet(pool))
+		o.ObserveInt64(closed, stats.MaxIdleClosed, reason("max_idle"))
+		o.ObserveInt64(closed, stats.MaxIdleTimeClosed, reason("max_idle_time"))
+		o.ObserveInt64(closed, stats.MaxLifetimeClosed, reason("max_lifetime"))
+		return nil
+	}, usage, maxOpen, waits, waitTime, closed)
+}
@@ -0,0 +1,215 @@
+// Package tracing instruments Prisma clients with OpenTelemetry.
+//
+// An Observer traces each operation of the generated client, e.g.
+// User.findMany, with a child span for each SQL statement it runs, and
+// traces transactions. Spans follow the database semantic conventions
+// (db.system, db.statement, db.operation) and are started from the context
+// passed to the client, so they join the trace of the caller.
+// RegisterPoolMetrics reports the statistics of the connection pool.
+//
+//	if err := tracing.Instrument(client.PrismaClient); err != nil {
+//		return err
+//	}
+//	users, err := client.User.FindMany().Exec(ctx)
+package tracing
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/runtime/client"
+)
+
+// instrumentationName names the tracer and meter of the package
+const instrumentationName = "github.com/satishbabariya/prisma-go/runtime/tracing"
+
+// Attributes of operation spans
+const (
+	ModelKey     = attribute.Key("prisma.model")
+	OperationKey = attribute.Key("prisma.operation")
+
+	// RowsAffectedKey is set on the spans of statements reporting the
+	// number of rows they changed
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	poolName       string
+}
+
+// Option configures the instrumentation
+type Option func(*config)
+
+// WithTracerProvider sets the provider of the tracer, the global one by
+// default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the provider of the meter of pool metrics, the
+// global one by default
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPoolName sets the pool.name attribute of pool metrics, "prisma" by
+// default, to tell the pools of several clients apart
+func WithPoolName(name string) Option {
+	return func(c *config) {
+		c.poolName = name
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{poolName: "prisma"}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+	if c.meterProvider == nil {
+		c.meterProvider = otel.GetMeterProvider()
+	}
+	return c
+}
+
+// Instrument traces the operations, statements and transactions of c and
+// registers the metrics of its connection pool
+func Instrument(c *client.PrismaClient, opts ...Option) error {
+	c.Observe(NewObserver(opts...))
+	_, err := RegisterPoolMetrics(c.DB(), opts...)
+	return err
+}
+
+// Observer is an executor.Observer tracing operations, statements and
+// transactions
+type Observer struct {
+	tracer trace.Tracer
+}
+
+var _ executor.TransactionObserver = (*Observer)(nil)
+
+// NewObserver creates an observer tracing with the tracer of the options
+func NewObserver(opts ...Option) *Observer {
+	c := newConfig(opts)
+	return &Observer{tracer: c.tracerProvider.Tracer(instrumentationName)}
+}
+
+// StartOperation starts the span of an operation, named after its model
+// and method, e.g. User.findMany
+func (o *Observer) StartOperation(ctx context.Context, op *executor.Operation) (context.Context, func(err error)) {
+	ctx, span := o.tracer.Start(ctx, op.Model+"."+op.Name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			ModelKey.String(op.Model),
+			OperationKey.String(op.Name),
+			semconv.DBSQLTableKey.String(op.Table),
+		),
+	)
+	return ctx, func(err error) {
+		end(span, err)
+	}
+}
+
+// StartStatement starts the span of a SQL statement, named after its verb
+// and the table of its operation, e.g. SELECT users
+func (o *Observer) StartStatement(ctx context.Context, stmt *executor.Statement) (context.Context, func(err error)) {
+	name := stmt.Verb
+	attrs := []attribute.KeyValue{
+		dbSystem(stmt.System),
+		semconv.DBStatementKey.String(stmt.SQL),
+		semconv.DBOperationKey.String(stmt.Verb),
+	}
+	if stmt.Operation != nil {
+		name += " " + stmt.Operation.Table
+		attrs = append(attrs, semconv.DBSQLTableKey.String(stmt.Operation.Table))
+	}
+	ctx, span := o.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, func(err error) {
+		if stmt.RowsAffected >= 0 {
+			span.SetAttributes(RowsAffectedKey.Int64(stmt.RowsAffected))
+		}
+		end(span, err)
+	}
+}
+
+// StartTransaction starts the span of a transaction
+func (o *Observer) StartTransaction(ctx context.Context, system string) (context.Context, func(err error)) {
+	ctx, span := o.tracer.Start(ctx, "transaction",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(dbSystem(system)),
+	)
+	return ctx, func(err error) {
+		end(span, err)
+	}
+}
+
+// Middleware traces the raw queries of clients using middleware rather
+// than observers, e.g. PrismaClientWithMiddleware, with a span per query.
+// Do not use it with an instrumented client, whose raw queries are already
+// traced.
+func Middleware(provider string, opts ...Option) client.Middleware {
+	tracer := newConfig(opts).tracerProvider.Tracer(instrumentationName)
+	return func(ctx context.Context, event *client.QueryEvent, next func() error) error {
+		stmt := executor.NewStatement(provider, event.Query, event.Args)
+		_, span := tracer.Start(ctx, stmt.Verb,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(event.Start),
+			trace.WithAttributes(
+				dbSystem(provider),
+				semconv.DBStatementKey.String(stmt.SQL),
+				semconv.DBOperationKey.String(stmt.Verb),
+			),
+		)
+		err := next()
+		end(span, err)
+		return err
+	}
+}
+
+// end ends span, recording err
+func end(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// dbSystem returns the db.system attribute of a Prisma provider
+func dbSystem(provider string) attribute.KeyValue {
+	switch provider {
+	case "postgresql", "postgres":
+		return semconv.DBSystemPostgreSQL
+	case "mysql":
+		return semconv.DBSystemMySQL
+	case "sqlite":
+		return semconv.DBSystemSqlite
+	case "sqlserver":
+		return semconv.DBSystemMSSQL
+	case "cockroachdb":
+		return semconv.DBSystemCockroachdb
+	case "mongodb":
+		return semconv.DBSystemMongoDB
+	default:
+		return semconv.DBSystemKey.String(provider)
+	}
+}
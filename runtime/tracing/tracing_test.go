@@ -0,0 +1,210 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/satishbabariya/prisma-go/query/executor"
+	"github.com/satishbabariya/prisma-go/runtime/client"
+)
+
+// newClient returns an instrumented SQLite client with a users table, and
+// the exporter of its spans
+func newClient(t *testing.T, opts ...Option) (*client.PrismaClient, *tracetest.InMemoryExporter) {
+	t.Helper()
+	c, err := client.NewPrismaClient("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Disconnect(context.Background()) })
+	c.SetMaxOpenConns(1)
+	if _, err := c.RawExec(context.Background(), `CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT UNIQUE)`); err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	if err := Instrument(c, append(opts, WithTracerProvider(provider))...); err != nil {
+		t.Fatal(err)
+	}
+	return c, exporter
+}
+
+// newExecutor returns an executor of c observed like generated clients
+func newExecutor(c *client.PrismaClient) *executor.Executor {
+	exec := executor.NewExecutor(c.DB(), "sqlite")
+	exec.SetObservers(c.Observers())
+	exec.SetModelNames(map[string]string{"users": "User"})
+	return exec
+}
+
+// span returns the span named name
+func span(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+	t.Fatalf("no span %q in %v", name, names)
+	return tracetest.SpanStub{}
+}
+
+// attr returns the value of the attribute key of s
+func attr(s tracetest.SpanStub, key attribute.Key) string {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestOperationSpans(t *testing.T) {
+	c, exporter := newClient(t)
+	exec := newExecutor(c)
+
+	parent, caller := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+	if _, err := exec.Count(parent, "users", nil); err != nil {
+		t.Fatal(err)
+	}
+	caller.End()
+
+	spans := exporter.GetSpans()
+	op := span(t, spans, "User.count")
+	if op.Parent.SpanID() != caller.SpanContext().SpanID() {
+		t.Error("operation span is not a child of the span of the caller's context")
+	}
+	if attr(op, ModelKey) != "User" || attr(op, OperationKey) != "count" {
+		t.Errorf("operation attributes = %v", op.Attributes)
+	}
+
+	stmt := span(t, spans, "SELECT users")
+	if stmt.Parent.SpanID() != op.SpanContext.SpanID() {
+		t.Error("statement span is not a child of the operation span")
+	}
+	for key, want := range map[attribute.Key]string{
+		"db.system":    "sqlite",
+		"db.operation": "SELECT",
+		"db.sql.table": "users",
+	} {
+		if got := attr(stmt, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if attr(stmt, "db.statement") == "" {
+		t.Error("db.statement is not set")
+	}
+}
+
+func TestErrorSpans(t *testing.T) {
+	c, exporter := newClient(t)
+	exec := newExecutor(c)
+
+	if _, err := exec.Count(context.Background(), "posts", nil); err == nil {
+		t.Fatal("count of a missing table succeeded")
+	}
+	for _, name := range []string{"posts.count", "SELECT posts"} {
+		if s := span(t, exporter.GetSpans(), name); s.Status.Code != codes.Error || len(s.Events) == 0 {
+			t.Errorf("span %s does not record the error: %+v", name, s.Status)
+		}
+	}
+}
+
+func TestRawAndTransactionSpans(t *testing.T) {
+	c, exporter := newClient(t)
+
+	err := c.Transaction(context.Background(), func(tx *client.Tx) error {
+		txExec := executor.NewTxExecutor(tx.Tx, "sqlite")
+		txExec.SetObservers(c.Observers())
+		_, err := txExec.Count(tx.Context(), "users", nil)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RawExec(context.Background(), `INSERT INTO users (email) VALUES (?), (?)`, "a@b.c", "d@e.f"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	tx := span(t, spans, "transaction")
+	if op := span(t, spans, "users.count"); op.Parent.SpanID() != tx.SpanContext.SpanID() {
+		t.Error("operation span is not a child of the transaction span")
+	}
+	insert := span(t, spans, "INSERT")
+	if attr(insert, RowsAffectedKey) != "2" {
+		t.Errorf("rows affected = %q, want 2", attr(insert, RowsAffectedKey))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	c, err := client.NewPrismaClient("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect(context.Background())
+	c.Use(Middleware("sqlite", WithTracerProvider(provider)))
+	if _, err := c.RawExec(context.Background(), `CREATE TABLE users (id INTEGER PRIMARY KEY)`); err != nil {
+		t.Fatal(err)
+	}
+
+	s := span(t, exporter.GetSpans(), "CREATE")
+	if attr(s, "db.system") != "sqlite" || attr(s, "db.statement") != `CREATE TABLE users (id INTEGER PRIMARY KEY)` {
+		t.Errorf("attributes = %v", s.Attributes)
+	}
+}
+
+func TestPoolMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	c, _ := newClient(t, WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))), WithPoolName("main"))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	points := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				if pool, _ := dp.Attributes.Value(PoolNameKey); pool.AsString() != "main" {
+					t.Errorf("%s pool.name = %q", m.Name, pool.AsString())
+				}
+				name := m.Name
+				if state, ok := dp.Attributes.Value(StateKey); ok {
+					name += "." + state.AsString()
+				}
+				points[name] = dp.Value
+			}
+		}
+	}
+
+	stats := c.DB().Stats()
+	for name, want := range map[string]int64{
+		"db.client.connections.usage.idle": int64(stats.Idle),
+		"db.client.connections.usage.used": int64(stats.InUse),
+		"db.client.connections.max":        1,
+	} {
+		if got, ok := points[name]; !ok || got != want {
+			t.Errorf("%s = %d (reported %v), want %d", name, got, ok, want)
+		}
+	}
+}
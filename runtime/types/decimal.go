@@ -0,0 +1,463 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DivisionScale is the number of fractional digits kept by Decimal.Div.
+// Use DivRound to control the scale and rounding of a single division.
+var DivisionScale int32 = 16
+
+// RoundingMode controls how a Decimal is rounded to fewer fractional digits
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest neighbour, ties away from zero (1.5 -> 2, -1.5 -> -2)
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfDown rounds to the nearest neighbour, ties towards zero (1.5 -> 1, -1.5 -> -1)
+	RoundHalfDown
+	// RoundHalfEven rounds to the nearest neighbour, ties to the even neighbour (1.5 -> 2, 2.5 -> 2)
+	RoundHalfEven
+	// RoundUp rounds away from zero (1.1 -> 2, -1.1 -> -2)
+	RoundUp
+	// RoundDown rounds towards zero, truncating extra digits (1.9 -> 1, -1.9 -> -1)
+	RoundDown
+	// RoundCeiling rounds towards positive infinity (1.1 -> 2, -1.9 -> -1)
+	RoundCeiling
+	// RoundFloor rounds towards negative infinity (1.9 -> 1, -1.1 -> -2)
+	RoundFloor
+)
+
+// Decimal is an arbitrary-precision decimal number, stored as an unscaled
+// integer and a scale: the value is unscaled * 10^-scale. Arithmetic is
+// exact except for division, and the scale is preserved, so "10.50" stays
+// "10.50". The zero value is 0.
+//
+// Decimal values are immutable; all operations return a new value.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// NullDecimal is a Decimal that may be NULL. It implements sql.Scanner and
+// driver.Valuer like sql.NullString.
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool
+}
+
+var (
+	bigTen = big.NewInt(10)
+	bigOne = big.NewInt(1)
+)
+
+// NewDecimal creates a new decimal from a string such as "12.34" or "-1e-3".
+// It panics if value is not a valid decimal; use ParseDecimal to handle
+// invalid input.
+func NewDecimal(value string) Decimal {
+	d, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// ParseDecimal parses a decimal in plain ("12.34") or exponent ("1.234e1")
+// notation. The scale of the result is the number of fractional digits
+// written, adjusted by the exponent.
+func ParseDecimal(value string) (Decimal, error) {
+	s := strings.TrimSpace(value)
+	exp := int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q: bad exponent", value)
+		}
+		exp = e
+		s = s[:i]
+	}
+
+	digits := s
+	if len(digits) > 0 && (digits[0] == '+' || digits[0] == '-') {
+		digits = digits[1:]
+	}
+	intPart, fracPart := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		intPart, fracPart = digits[:i], digits[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+	}
+	if s[0] == '-' {
+		unscaled.Neg(unscaled)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale < -1<<31 || scale > 1<<31-1 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q: exponent out of range", value)
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewDecimalFromInt creates a new decimal from an integer
+func NewDecimalFromInt(value int64) Decimal {
+	return Decimal{unscaled: big.NewInt(value)}
+}
+
+// NewDecimalFromBigInt creates the decimal unscaled * 10^-scale
+func NewDecimalFromBigInt(unscaled *big.Int, scale int32) Decimal {
+	return Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+}
+
+// NewDecimalFromFloat creates a new decimal from the shortest decimal
+// representation of value, so 0.1 becomes exactly 0.1. It panics on NaN
+// and infinities.
+func NewDecimalFromFloat(value float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(value, 'f', -1, 64))
+	if err != nil {
+		panic(fmt.Sprintf("cannot convert %v to Decimal", value))
+	}
+	return d
+}
+
+func (d Decimal) value() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Coefficient returns the unscaled value
+func (d Decimal) Coefficient() *big.Int {
+	return new(big.Int).Set(d.value())
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Precision returns the number of significant digits of the unscaled value
+func (d Decimal) Precision() int {
+	abs := new(big.Int).Abs(d.value())
+	if abs.Sign() == 0 {
+		return 1
+	}
+	return len(abs.String())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// IsZero reports whether d is zero
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.value()), scale: d.scale}
+}
+
+// Abs returns the absolute value of d
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.value()), scale: d.scale}
+}
+
+// Add returns d + other with the larger of both scales
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{unscaled: a.Add(a, b), scale: scale}
+}
+
+// Sub returns d - other with the larger of both scales
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	return Decimal{unscaled: a.Sub(a, b), scale: scale}
+}
+
+// Mul returns d * other with the sum of both scales
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.value(), other.value()), scale: d.scale + other.scale}
+}
+
+// Div returns d / other rounded half to even at DivisionScale digits.
+// Trailing zeros are removed down to the larger scale of both operands,
+// so 10.00 / 4 is 2.50. It panics if other is zero.
+func (d Decimal) Div(other Decimal) Decimal {
+	q := d.DivRound(other, DivisionScale, RoundHalfEven)
+	min := d.scale
+	if other.scale > min {
+		min = other.scale
+	}
+	return q.trim(min)
+}
+
+// DivRound returns d / other rounded to scale digits with the given mode.
+// It panics if other is zero.
+func (d Decimal) DivRound(other Decimal, scale int32, mode RoundingMode) Decimal {
+	if other.IsZero() {
+		panic("decimal division by zero")
+	}
+	// d / other * 10^scale = d.unscaled * 10^(scale + other.scale - d.scale) / other.unscaled
+	num := new(big.Int).Set(d.value())
+	den := new(big.Int).Set(other.value())
+	if e := int64(scale) + int64(other.scale) - int64(d.scale); e >= 0 {
+		num.Mul(num, pow10(e))
+	} else {
+		den.Mul(den, pow10(-e))
+	}
+	return Decimal{unscaled: quoRound(num, den, mode), scale: scale}
+}
+
+// Round returns d rounded to scale fractional digits. A scale larger than
+// the scale of d appends zeros, a negative scale rounds to tens, hundreds
+// and so on.
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	if scale >= d.scale {
+		return Decimal{unscaled: new(big.Int).Mul(d.value(), pow10(int64(scale-d.scale))), scale: scale}
+	}
+	return Decimal{unscaled: quoRound(d.value(), pow10(int64(d.scale-scale)), mode), scale: scale}
+}
+
+// Fit rounds d half up to scale and checks that the result fits a column
+// declared as @db.Decimal(precision, scale), i.e. that it has at most
+// precision - scale integer digits.
+func (d Decimal) Fit(precision, scale int32) (Decimal, error) {
+	r := d.Round(scale, RoundHalfUp)
+	if !r.IsZero() && r.Precision() > int(precision) {
+		return Decimal{}, fmt.Errorf("decimal %s does not fit Decimal(%d, %d)", d, precision, scale)
+	}
+	return r, nil
+}
+
+// Cmp compares d and other and returns -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and other have the same value, regardless of
+// scale: 1.5 equals 1.50.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// LessThan reports whether d < other
+func (d Decimal) LessThan(other Decimal) bool {
+	return d.Cmp(other) < 0
+}
+
+// GreaterThan reports whether d > other
+func (d Decimal) GreaterThan(other Decimal) bool {
+	return d.Cmp(other) > 0
+}
+
+// Float64 returns the nearest float64 value of d
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d in plain notation with exactly Scale() fractional digits
+func (d Decimal) String() string {
+	if d.scale <= 0 {
+		return new(big.Int).Mul(d.value(), pow10(int64(-d.scale))).String()
+	}
+
+	digits := new(big.Int).Abs(d.value()).String()
+	if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+
+	var b strings.Builder
+	if d.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(digits[:point])
+	b.WriteByte('.')
+	b.WriteString(digits[point:])
+	return b.String()
+}
+
+// StringFixed returns d rounded half up to places fractional digits
+func (d Decimal) StringFixed(places int32) string {
+	return d.Round(places, RoundHalfUp).String()
+}
+
+// Value implements driver.Valuer. Decimals are sent as strings so that no
+// precision is lost on the way to the database.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	case int64:
+		*d = NewDecimalFromInt(v)
+		return nil
+	case float64:
+		*d = NewDecimalFromFloat(v)
+		return nil
+	case nil:
+		return fmt.Errorf("cannot scan NULL into Decimal, use NullDecimal or *Decimal")
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", src)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes d as a JSON string, because JSON numbers are usually
+// decoded as float64 by clients.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts both JSON strings and numbers. null leaves d
+// unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+	return d.UnmarshalText(data)
+}
+
+// Scan implements sql.Scanner
+func (n *NullDecimal) Scan(src interface{}) error {
+	if src == nil {
+		n.Decimal, n.Valid = Decimal{}, false
+		return nil
+	}
+	n.Valid = true
+	return n.Decimal.Scan(src)
+}
+
+// Value implements driver.Valuer
+func (n NullDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Decimal.Value()
+}
+
+// trim removes trailing fractional zeros while the scale is above min
+func (d Decimal) trim(min int32) Decimal {
+	unscaled := new(big.Int).Set(d.value())
+	scale := d.scale
+	rem := new(big.Int)
+	for scale > min {
+		q, r := new(big.Int).QuoRem(unscaled, bigTen, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		unscaled = q
+		scale--
+	}
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+// align returns the unscaled values of a and b at their common scale
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	x, y := new(big.Int).Set(a.value()), new(big.Int).Set(b.value())
+	switch {
+	case a.scale < b.scale:
+		x.Mul(x, pow10(int64(b.scale-a.scale)))
+		return x, y, b.scale
+	case a.scale > b.scale:
+		y.Mul(y, pow10(int64(a.scale-b.scale)))
+	}
+	return x, y, a.scale
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
+
+// quoRound returns num / den rounded with mode
+func quoRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	sign := num.Sign() * den.Sign()
+	// Compare the remainder with half of the divisor
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmpHalf := half.Cmp(new(big.Int).Abs(den))
+
+	var away bool
+	switch mode {
+	case RoundHalfUp:
+		away = cmpHalf >= 0
+	case RoundHalfDown:
+		away = cmpHalf > 0
+	case RoundHalfEven:
+		away = cmpHalf > 0 || cmpHalf == 0 && q.Bit(0) == 1
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	}
+	if away {
+		if sign < 0 {
+			q.Sub(q, bigOne)
+		} else {
+			q.Add(q, bigOne)
+		}
+	}
+	return q
+}
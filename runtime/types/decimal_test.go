@@ -0,0 +1,161 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		scale int32
+	}{
+		{"0", "0", 0},
+		{"12.30", "12.30", 2},
+		{"-0.001", "-0.001", 3},
+		{"+.5", "0.5", 1},
+		{"1.5e3", "1500", -2},
+		{"1234e-2", "12.34", 2},
+		{"123456789012345678901234567890.123456789", "123456789012345678901234567890.123456789", 9},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.input)
+		if err != nil {
+			t.Fatalf("ParseDecimal(%q): %v", tt.input, err)
+		}
+		if d.String() != tt.want || d.Scale() != tt.scale {
+			t.Errorf("ParseDecimal(%q) = %s (scale %d), want %s (scale %d)", tt.input, d, d.Scale(), tt.want, tt.scale)
+		}
+	}
+
+	for _, input := range []string{"", ".", "-", "1.2.3", "abc", "1e", "1,5"} {
+		if _, err := ParseDecimal(input); err == nil {
+			t.Errorf("ParseDecimal(%q) should fail", input)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := NewDecimal("0.1"), NewDecimal("0.2")
+	if got := a.Add(b).String(); got != "0.3" {
+		t.Errorf("0.1 + 0.2 = %s", got)
+	}
+	if got := NewDecimal("10.50").Sub(NewDecimal("0.5")).String(); got != "10.00" {
+		t.Errorf("10.50 - 0.5 = %s", got)
+	}
+	if got := NewDecimal("1.25").Mul(NewDecimal("-4")).String(); got != "-5.00" {
+		t.Errorf("1.25 * -4 = %s", got)
+	}
+	if got := NewDecimal("10.00").Div(NewDecimalFromInt(4)).String(); got != "2.50" {
+		t.Errorf("10.00 / 4 = %s", got)
+	}
+	if got := NewDecimal("1").Div(NewDecimal("3")).String(); got != "0.3333333333333333" {
+		t.Errorf("1 / 3 = %s", got)
+	}
+	if got := NewDecimal("2").DivRound(NewDecimal("3"), 2, RoundDown).String(); got != "0.66" {
+		t.Errorf("2 / 3 rounded down = %s", got)
+	}
+	if !NewDecimal("1.5").Equal(NewDecimal("1.50")) || !NewDecimal("-2").LessThan(NewDecimal("1")) {
+		t.Error("unexpected comparison result")
+	}
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" || zero.Add(NewDecimal("1.1")).String() != "1.1" {
+		t.Error("zero value is not usable as 0")
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	modes := []RoundingMode{RoundHalfUp, RoundHalfDown, RoundHalfEven, RoundUp, RoundDown, RoundCeiling, RoundFloor}
+	tests := []struct {
+		input string
+		want  [7]string
+	}{
+		{"2.5", [7]string{"3", "2", "2", "3", "2", "3", "2"}},
+		{"-2.5", [7]string{"-3", "-2", "-2", "-3", "-2", "-2", "-3"}},
+		{"1.1", [7]string{"1", "1", "1", "2", "1", "2", "1"}},
+		{"-1.6", [7]string{"-2", "-2", "-2", "-2", "-1", "-1", "-2"}},
+		{"3.5", [7]string{"4", "3", "4", "4", "3", "4", "3"}},
+	}
+	for _, tt := range tests {
+		for i, mode := range modes {
+			if got := NewDecimal(tt.input).Round(0, mode).String(); got != tt.want[i] {
+				t.Errorf("Round(%s, mode %d) = %s, want %s", tt.input, mode, got, tt.want[i])
+			}
+		}
+	}
+
+	if got := NewDecimal("1.5").Round(3, RoundHalfUp).String(); got != "1.500" {
+		t.Errorf("Round to larger scale = %s", got)
+	}
+	if got := NewDecimal("1250").Round(-2, RoundHalfEven).String(); got != "1200" {
+		t.Errorf("Round to hundreds = %s", got)
+	}
+}
+
+func TestDecimalFit(t *testing.T) {
+	d, err := NewDecimal("123.456").Fit(5, 2)
+	if err != nil || d.String() != "123.46" {
+		t.Errorf("Fit(5, 2) = %s, %v", d, err)
+	}
+	if _, err := NewDecimal("1234.5").Fit(5, 2); err == nil {
+		t.Error("expected overflow for Decimal(5, 2)")
+	}
+	if _, err := NewDecimal("999.995").Fit(5, 2); err == nil {
+		t.Error("expected overflow after rounding")
+	}
+}
+
+func TestDecimalScanAndValue(t *testing.T) {
+	for _, src := range []interface{}{"19.99", []byte("19.99"), 19.99} {
+		var d Decimal
+		if err := d.Scan(src); err != nil || d.String() != "19.99" {
+			t.Errorf("Scan(%#v) = %s, %v", src, d, err)
+		}
+	}
+	var d Decimal
+	if err := d.Scan(int64(42)); err != nil || d.String() != "42" {
+		t.Errorf("Scan(int64) = %s, %v", d, err)
+	}
+	if err := d.Scan(nil); err == nil {
+		t.Error("expected error when scanning NULL")
+	}
+
+	var n NullDecimal
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("NullDecimal.Scan(nil) = %v, %v", n, err)
+	}
+	if v, _ := n.Value(); v != nil {
+		t.Errorf("NULL value = %v", v)
+	}
+	if err := n.Scan("1.10"); err != nil || !n.Valid || n.Decimal.String() != "1.10" {
+		t.Errorf("NullDecimal.Scan = %v, %v", n, err)
+	}
+
+	if v, _ := NewDecimal("-0.50").Value(); v != "-0.50" {
+		t.Errorf("Value() = %v", v)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type invoice struct {
+		Total Decimal  `json:"total"`
+		Tax   *Decimal `json:"tax"`
+	}
+
+	data, err := json.Marshal(invoice{Total: NewDecimal("100.10")})
+	if err != nil || string(data) != `{"total":"100.10","tax":null}` {
+		t.Fatalf("Marshal = %s, %v", data, err)
+	}
+
+	var got invoice
+	if err := json.Unmarshal([]byte(`{"total":12345678901234567890.01,"tax":"0.07"}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Total.String() != "12345678901234567890.01" || got.Tax == nil || got.Tax.String() != "0.07" {
+		t.Errorf("Unmarshal = %s, %v", got.Total, got.Tax)
+	}
+	if err := json.Unmarshal([]byte(`{"total":"abc"}`), &got); err == nil {
+		t.Error("expected error for invalid decimal")
+	}
+}
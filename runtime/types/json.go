@@ -0,0 +1,64 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JsonValue stores any Go value in a Json column. It implements
+// driver.Valuer by encoding the value as JSON, and sql.Scanner by decoding
+// into V, so V should be a pointer when scanning.
+type JsonValue struct {
+	V interface{}
+}
+
+// Value implements driver.Valuer. Nil values and nil pointers are stored
+// as NULL.
+func (j JsonValue) Value() (driver.Value, error) {
+	if isNil(j.V) {
+		return nil, nil
+	}
+	data, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %T as JSON: %w", j.V, err)
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (j *JsonValue) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into JsonValue", src)
+	}
+	if j.V == nil {
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		j.V = v
+		return nil
+	}
+	return json.Unmarshal(data, j.V)
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
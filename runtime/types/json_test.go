@@ -0,0 +1,45 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJsonValue(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+
+	v, err := JsonValue{V: address{City: "Berlin"}}.Value()
+	if err != nil || v != `{"city":"Berlin"}` {
+		t.Errorf("Value() = %v, %v", v, err)
+	}
+	var nilSlice []address
+	var nilPtr *address
+	for _, src := range []interface{}{nil, nilSlice, nilPtr} {
+		if v, err := (JsonValue{V: src}).Value(); v != nil || err != nil {
+			t.Errorf("Value(%#v) = %v, %v, want NULL", src, v, err)
+		}
+	}
+
+	var got address
+	if err := (&JsonValue{V: &got}).Scan([]byte(`{"city":"Paris"}`)); err != nil || got.City != "Paris" {
+		t.Errorf("Scan into struct = %+v, %v", got, err)
+	}
+	var ptr *address
+	if err := (&JsonValue{V: &ptr}).Scan(nil); err != nil || ptr != nil {
+		t.Errorf("Scan(nil) = %v, %v", ptr, err)
+	}
+
+	var generic JsonValue
+	if err := generic.Scan(`{"tags":["a"]}`); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"tags": []interface{}{"a"}}
+	if !reflect.DeepEqual(generic.V, want) {
+		t.Errorf("Scan into nil V = %#v", generic.V)
+	}
+	if err := generic.Scan(42); err == nil {
+		t.Error("expected error when scanning int")
+	}
+}
@@ -0,0 +1,298 @@
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MaxBufferBytes bounds the size of the events buffered on disk. Events
+// recorded while the buffer is full are dropped.
+const MaxBufferBytes = 1 << 20
+
+// Files of the telemetry directory
+const (
+	bufferFile   = "buffer.jsonl"
+	sendingExt   = ".sending"
+	pendingExt   = ".pending"
+	settingsFile = "settings.json"
+)
+
+// abandonAfter is how long after it was claimed a .sending file is
+// considered abandoned by a process that exited before sending it. It is
+// well above the timeout of a flush.
+const abandonAfter = time.Minute
+
+// Dir returns the directory holding the buffered events and the settings
+// of telemetry: $PRISMA_TELEMETRY_DIR, or prisma-go/telemetry in the user
+// config directory
+func Dir() (string, error) {
+	if dir := os.Getenv("PRISMA_TELEMETRY_DIR"); dir != "" {
+		return dir, nil
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config, "prisma-go", "telemetry"), nil
+}
+
+// Buffer holds the events recorded and not sent yet in a directory, so
+// that they survive the process and can be inspected. Events are appended
+// to buffer.jsonl. A flush claims the files it sends by renaming them to
+// a .sending file of its own, so that events recorded meanwhile are kept
+// and no two processes send the same events. It removes the file once the
+// sink accepted its events, or releases it as a .pending file for a later
+// flush.
+type Buffer struct {
+	dir string
+}
+
+// NewBuffer returns the buffer of events in dir
+func NewBuffer(dir string) *Buffer {
+	return &Buffer{dir: dir}
+}
+
+// Dir returns the directory of the buffer
+func (b *Buffer) Dir() string {
+	return b.dir
+}
+
+// Append buffers events conforming to the schema, dropping the others and
+// those over MaxBufferBytes
+func (b *Buffer) Append(events ...TelemetryEvent) error {
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return err
+	}
+	size, err := b.size()
+	if err != nil {
+		return err
+	}
+	var lines []byte
+	for _, event := range events {
+		if Validate(event) != nil {
+			continue
+		}
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if size+int64(len(lines)+len(line)+1) > MaxBufferBytes {
+			break
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(filepath.Join(b.dir, bufferFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	// A single write, so that the lines of concurrent processes do not mix
+	if _, err := f.Write(lines); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Events returns the buffered events, oldest first
+func (b *Buffer) Events() ([]TelemetryEvent, error) {
+	files, err := b.files()
+	if err != nil {
+		return nil, err
+	}
+	var events []TelemetryEvent
+	for _, file := range files {
+		fileEvents, err := readEvents(file)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, fileEvents...)
+	}
+	return events, nil
+}
+
+// Flush sends the buffered events to sink, removing those it accepted.
+// Files being sent by other processes are left to them.
+func (b *Buffer) Flush(ctx context.Context, sink Sink) error {
+	files, err := b.files()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, sendingExt) && !abandoned(file) {
+			continue
+		}
+		claimed := b.name(sendingExt)
+		if err := os.Rename(file, claimed); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // Claimed by another process
+			}
+			return err
+		}
+		if err := b.send(ctx, sink, claimed); err != nil {
+			// Released for a later flush
+			os.Rename(claimed, b.name(pendingExt))
+			return err
+		}
+	}
+	return nil
+}
+
+// send sends the events of a claimed file to sink and removes it
+func (b *Buffer) send(ctx context.Context, sink Sink, file string) error {
+	events, err := readEvents(file)
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		if err := sink.Send(ctx, events); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// fileSeq tells apart the files named by a process at the same time
+var fileSeq atomic.Int64
+
+// name returns a new file name of the buffer with extension ext. Names
+// start with the time they were given, so that they sort oldest first.
+func (b *Buffer) name(ext string) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%d-%d-%d%s", time.Now().UnixNano(), os.Getpid(), fileSeq.Add(1), ext))
+}
+
+// abandoned reports whether the .sending file was claimed more than
+// abandonAfter ago
+func abandoned(file string) bool {
+	claimedAt, err := strconv.ParseInt(strings.SplitN(filepath.Base(file), "-", 2)[0], 10, 64)
+	return err != nil || time.Since(time.Unix(0, claimedAt)) > abandonAfter
+}
+
+// Clear removes the buffered events
+func (b *Buffer) Clear() error {
+	files, err := b.files()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// files returns the .sending and .pending files of the buffer, oldest
+// first, and buffer.jsonl, if they exist
+func (b *Buffer) files() ([]string, error) {
+	var files []string
+	for _, ext := range []string{sendingExt, pendingExt} {
+		matches, err := filepath.Glob(filepath.Join(b.dir, "*"+ext))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Slice(files, func(i, j int) bool { return filepath.Base(files[i]) < filepath.Base(files[j]) })
+	current := filepath.Join(b.dir, bufferFile)
+	if _, err := os.Stat(current); err == nil {
+		files = append(files, current)
+	}
+	return files, nil
+}
+
+// size returns the size of the buffered events
+func (b *Buffer) size() (int64, error) {
+	files, err := b.files()
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return 0, err
+		}
+		size += info.Size()
+	}
+	return size, nil
+}
+
+// readEvents reads the events of a buffer file, skipping the lines that
+// are not events conforming to the schema, e.g. a line cut by a crash
+func readEvents(path string) ([]TelemetryEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []TelemetryEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), MaxBufferBytes)
+	for scanner.Scan() {
+		var event TelemetryEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || Validate(event) != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// settings are the telemetry settings saved in the telemetry directory
+type settings struct {
+	Disabled bool `json:"disabled"`
+}
+
+// SetDisabled saves whether telemetry is disabled in dir. Disabling it
+// also removes the buffered events.
+func SetDisabled(dir string, disabled bool) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings{Disabled: disabled}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, settingsFile), append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	if disabled {
+		return NewBuffer(dir).Clear()
+	}
+	return nil
+}
+
+// DisabledIn reports whether telemetry was disabled in dir with
+// SetDisabled
+func DisabledIn(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, settingsFile))
+	if err != nil {
+		return false
+	}
+	var s settings
+	return json.Unmarshal(data, &s) == nil && s.Disabled
+}
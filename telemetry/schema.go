@@ -0,0 +1,157 @@
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Event types
+const (
+	EventCommand     = "command"
+	EventError       = "error"
+	EventPerformance = "performance"
+)
+
+// Providers are the values of the provider field
+var Providers = []string{"postgresql", "mysql", "sqlite", "sqlserver", "cockroachdb", "mongodb"}
+
+// MetadataKeys are the keys allowed in the metadata of events
+var MetadataKeys = []string{"error_type", "metric", "count", "models", "fields", "migrations", "success"}
+
+var (
+	// identifier matches error classes, versions and platforms: no
+	// separators of paths or URLs, and no spaces
+	identifier = regexp.MustCompile(`^[A-Za-z0-9_.*+-]{1,64}$`)
+
+	// token matches metadata strings, without the dots of host names
+	token = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+	command = regexp.MustCompile(`^[a-z][a-z-]*( [a-z][a-z-]*){0,2}$`)
+
+	// prismaCode matches the code of Prisma errors, e.g. [P2002]
+	prismaCode = regexp.MustCompile(`^\[(P\d{4})\]`)
+)
+
+// ErrSchema is the error of events that do not conform to the schema
+var ErrSchema = errors.New("telemetry event does not conform to the schema")
+
+// Validate checks that event conforms to the schema of recorded events.
+// Events are made to conform to it before they are buffered, and events
+// that do not are dropped, so that no path, schema content or connection
+// string leaves the machine:
+//
+//	event_type    command, error or performance
+//	command       the CLI command, e.g. "migrate dev"
+//	provider      one of Providers
+//	duration      nanoseconds
+//	error         the class of the error: its Prisma code, e.g. P1001, or
+//	              its Go type, e.g. *fs.PathError, never its message
+//	metadata      the keys of MetadataKeys, with numbers, booleans or
+//	              tokens of at most 64 letters, digits, _ or -
+//	timestamp     RFC 3339
+//	version       the prisma-go version
+//	os            runtime.GOOS
+//	architecture  runtime.GOARCH
+func Validate(event TelemetryEvent) error {
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrSchema, fmt.Sprintf(format, args...))
+	}
+	switch event.EventType {
+	case EventCommand, EventError, EventPerformance:
+	default:
+		return fail("event_type %q", event.EventType)
+	}
+	if event.Command != "" && !command.MatchString(event.Command) {
+		return fail("command %q", event.Command)
+	}
+	if event.Provider != "" && !contains(Providers, event.Provider) {
+		return fail("provider %q", event.Provider)
+	}
+	if event.Error != "" && !identifier.MatchString(event.Error) {
+		return fail("error %q is not an error class", event.Error)
+	}
+	for _, field := range []struct{ name, value string }{
+		{"version", event.Version}, {"os", event.OS}, {"architecture", event.Architecture},
+	} {
+		if field.value != "" && !identifier.MatchString(field.value) {
+			return fail("%s %q", field.name, field.value)
+		}
+	}
+	for key, value := range event.Metadata {
+		if !contains(MetadataKeys, key) {
+			return fail("metadata key %q", key)
+		}
+		if !metadataValue(value) {
+			return fail("metadata %s of type %T", key, value)
+		}
+	}
+	return nil
+}
+
+// conform makes event conform to the schema: errors are replaced by their
+// class, and unknown providers and metadata that could hold anything else
+// are dropped
+func conform(event TelemetryEvent) TelemetryEvent {
+	if event.Provider != "" && !contains(Providers, event.Provider) {
+		event.Provider = normalizeProvider(event.Provider)
+	}
+	if event.Error != "" && !identifier.MatchString(event.Error) {
+		event.Error = "unknown"
+	}
+	if len(event.Metadata) > 0 {
+		metadata := make(map[string]interface{}, len(event.Metadata))
+		for key, value := range event.Metadata {
+			if contains(MetadataKeys, key) && metadataValue(value) {
+				metadata[key] = value
+			}
+		}
+		event.Metadata = metadata
+	}
+	return event
+}
+
+// ErrorClass returns the class of err recorded in events: the Prisma code
+// its message starts with, e.g. P1001, or else its Go type
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+	if m := prismaCode.FindStringSubmatch(err.Error()); m != nil {
+		return m[1]
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// normalizeProvider returns the provider of an alias, e.g. postgres, or an
+// empty provider
+func normalizeProvider(provider string) string {
+	switch strings.ToLower(provider) {
+	case "postgres":
+		return "postgresql"
+	case "sqlite3":
+		return "sqlite"
+	}
+	return ""
+}
+
+// metadataValue reports whether value may be recorded as metadata
+func metadataValue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return true
+	case string:
+		return token.MatchString(v)
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Sink receives the events flushed by the collector. Events that a sink
+// fails to send stay buffered on disk and are sent with the next flush.
+type Sink interface {
+	Send(ctx context.Context, events []TelemetryEvent) error
+}
+
+// HTTPSink posts events as JSON to an endpoint
+type HTTPSink struct {
+	Endpoint string
+	Version  string // Version of prisma-go, sent as the User-Agent
+	Client   *http.Client
+}
+
+// Send posts events to the endpoint of the sink
+func (s *HTTPSink) Send(ctx context.Context, events []TelemetryEvent) error {
+	jsonData, err := json.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.Endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("prisma-go/%s", s.Version))
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Read response to completion (but ignore it)
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// FileSink appends events to a file, one JSON object per line, e.g. for
+// air-gapped CI or to audit what would be sent
+type FileSink struct {
+	Path string
+}
+
+// Send appends events to the file of the sink
+func (s *FileSink) Send(ctx context.Context, events []TelemetryEvent) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := writeEvents(f, events); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// discardSink drops events
+type discardSink struct{}
+
+func (discardSink) Send(ctx context.Context, events []TelemetryEvent) error {
+	return nil
+}
+
+// NewSink returns the sink of spec: "none" to drop events, "file:<path>"
+// or a path ending in .jsonl for a FileSink, or the URL of an HTTPSink
+func NewSink(spec, version string) (Sink, error) {
+	switch {
+	case spec == "none" || spec == "off":
+		return discardSink{}, nil
+	case strings.HasPrefix(spec, "file:"):
+		return &FileSink{Path: strings.TrimPrefix(spec, "file:")}, nil
+	case strings.HasSuffix(spec, ".jsonl"):
+		return &FileSink{Path: spec}, nil
+	case strings.HasPrefix(spec, "https://") || strings.HasPrefix(spec, "http://"):
+		return &HTTPSink{Endpoint: spec, Version: version}, nil
+	}
+	return nil, fmt.Errorf("unknown telemetry sink %q: use none, file:<path> or an http(s) URL", spec)
+}
+
+// writeEvents writes events to w as JSON lines
+func writeEvents(w io.Writer, events []TelemetryEvent) error {
+	enc := json.NewEncoder(w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,18 +1,24 @@
 // Package telemetry provides opt-in telemetry collection for prisma-go.
+//
+// Events are made to conform to a schema that leaves out paths, schema
+// contents and connection strings (see Validate), buffered on disk in Dir
+// so that they survive the process and can be inspected with
+// `prisma-go telemetry show`, and flushed to a Sink: the telemetry
+// endpoint by default, a JSONL file or nothing, as set by
+// PRISMA_TELEMETRY_SINK.
 package telemetry
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 	"os"
+	"runtime"
 	"sync"
 	"time"
 )
 
+// DefaultEndpoint is the endpoint events are posted to by default
+const DefaultEndpoint = "https://telemetry.prisma-go.dev/events"
+
 // TelemetryEvent represents a telemetry event
 type TelemetryEvent struct {
 	EventType    string                 `json:"event_type"`
@@ -30,38 +36,67 @@ type TelemetryEvent struct {
 // TelemetryCollector manages telemetry collection
 type TelemetryCollector struct {
 	enabled       bool
-	endpoint      string
-	events        []TelemetryEvent
+	sink          Sink
+	buffer        *Buffer
 	mu            sync.Mutex
-	httpClient    *http.Client
 	version       string
-	batchSize     int
 	flushInterval time.Duration
+	flushTimeout  time.Duration
+	stopTimeout   time.Duration // How long Shutdown waits for a flush in progress
 	stopChan      chan struct{}
 	wg            sync.WaitGroup
 }
 
+// Option configures the telemetry collector
+type Option func(*TelemetryCollector)
+
+// WithSink sets the sink of the events, instead of the one of
+// PRISMA_TELEMETRY_SINK
+func WithSink(sink Sink) Option {
+	return func(tc *TelemetryCollector) {
+		tc.sink = sink
+	}
+}
+
+// WithDir buffers the events in dir, instead of Dir()
+func WithDir(dir string) Option {
+	return func(tc *TelemetryCollector) {
+		tc.buffer = NewBuffer(dir)
+	}
+}
+
 var (
 	globalCollector *TelemetryCollector
 	once            sync.Once
 )
 
-// InitTelemetry initializes the global telemetry collector
-func InitTelemetry(version string, enabled bool) {
+// InitTelemetry initializes the global telemetry collector. Telemetry is
+// disabled when the sink or the directory of the buffer cannot be set up.
+func InitTelemetry(version string, enabled bool, opts ...Option) {
 	once.Do(func() {
-		globalCollector = &TelemetryCollector{
-			enabled:       enabled && !isTelemetryDisabled(),
-			endpoint:      getTelemetryEndpoint(),
-			events:        make([]TelemetryEvent, 0, 100),
-			httpClient:    &http.Client{Timeout: 5 * time.Second},
+		tc := &TelemetryCollector{
 			version:       version,
-			batchSize:     10,
 			flushInterval: 30 * time.Second,
+			flushTimeout:  2 * time.Second,
+			stopTimeout:   200 * time.Millisecond,
 			stopChan:      make(chan struct{}),
 		}
+		for _, opt := range opts {
+			opt(tc)
+		}
+		if tc.buffer == nil {
+			if dir, err := Dir(); err == nil {
+				tc.buffer = NewBuffer(dir)
+			}
+		}
+		if tc.sink == nil {
+			tc.sink, _ = NewSink(SinkSpec(), version)
+		}
+		tc.enabled = enabled && tc.buffer != nil && tc.sink != nil && !isTelemetryDisabled(tc.buffer.Dir())
+		globalCollector = tc
 
-		if globalCollector.enabled {
-			globalCollector.startBackgroundFlush()
+		if tc.enabled {
+			tc.startBackgroundFlush()
 		}
 	})
 }
@@ -73,41 +108,39 @@ func RecordCommand(command string, provider string, duration time.Duration, err
 	}
 
 	event := TelemetryEvent{
-		EventType:    "command",
+		EventType:    EventCommand,
 		Command:      command,
 		Provider:     provider,
 		Duration:     &duration,
+		Error:        ErrorClass(err),
 		Timestamp:    time.Now(),
 		Version:      globalCollector.version,
 		OS:           getOS(),
 		Architecture: getArchitecture(),
 	}
 
-	if err != nil {
-		event.Error = err.Error()
-	}
-
 	globalCollector.recordEvent(event)
 }
 
-// RecordError records an error event
+// RecordError records an error event. The error is recorded by its class,
+// see ErrorClass.
 func RecordError(errorType string, err error, metadata map[string]interface{}) {
 	if globalCollector == nil || !globalCollector.enabled {
 		return
 	}
 
 	event := TelemetryEvent{
-		EventType:    "error",
-		Error:        err.Error(),
-		Metadata:     metadata,
+		EventType:    EventError,
+		Error:        ErrorClass(err),
+		Metadata:     make(map[string]interface{}, len(metadata)+1),
 		Timestamp:    time.Now(),
 		Version:      globalCollector.version,
 		OS:           getOS(),
 		Architecture: getArchitecture(),
 	}
 
-	if metadata == nil {
-		event.Metadata = make(map[string]interface{})
+	for key, value := range metadata {
+		event.Metadata[key] = value
 	}
 	event.Metadata["error_type"] = errorType
 
@@ -121,93 +154,45 @@ func RecordPerformance(metric string, duration time.Duration, metadata map[strin
 	}
 
 	event := TelemetryEvent{
-		EventType:    "performance",
+		EventType:    EventPerformance,
 		Duration:     &duration,
-		Metadata:     metadata,
+		Metadata:     make(map[string]interface{}, len(metadata)+1),
 		Timestamp:    time.Now(),
 		Version:      globalCollector.version,
 		OS:           getOS(),
 		Architecture: getArchitecture(),
 	}
 
-	if metadata == nil {
-		event.Metadata = make(map[string]interface{})
+	for key, value := range metadata {
+		event.Metadata[key] = value
 	}
 	event.Metadata["metric"] = metric
 
 	globalCollector.recordEvent(event)
 }
 
-// recordEvent adds an event to the collector
+// recordEvent buffers an event, made to conform to the schema
 func (tc *TelemetryCollector) recordEvent(event TelemetryEvent) {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
 
-	tc.events = append(tc.events, event)
-
-	// Flush if batch size reached
-	if len(tc.events) >= tc.batchSize {
-		go tc.flush()
-	}
+	// Silently fail - telemetry should never break the application
+	tc.buffer.Append(conform(event))
 }
 
-// flush sends collected events to the telemetry endpoint
+// flush sends the buffered events to the sink, including those buffered
+// by earlier processes. The buffer keeps concurrent flushes from sending
+// the same events, so recording does not wait for a flush.
 func (tc *TelemetryCollector) flush() {
-	tc.mu.Lock()
-	if len(tc.events) == 0 {
-		tc.mu.Unlock()
-		return
-	}
-
-	events := make([]TelemetryEvent, len(tc.events))
-	copy(events, tc.events)
-	tc.events = tc.events[:0]
-	tc.mu.Unlock()
-
-	// Send events asynchronously
-	go tc.sendEvents(events)
-}
-
-// sendEvents sends events to the telemetry endpoint
-func (tc *TelemetryCollector) sendEvents(events []TelemetryEvent) {
-	if len(events) == 0 {
-		return
-	}
-
-	payload := map[string]interface{}{
-		"events": events,
-	}
-
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		// Silently fail - telemetry should never break the application
-		return
-	}
-
-	req, err := http.NewRequestWithContext(context.Background(), "POST", tc.endpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", fmt.Sprintf("prisma-go/%s", tc.version))
-
-	// Send request with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), tc.flushTimeout)
 	defer cancel()
 
-	req = req.WithContext(ctx)
-	resp, err := tc.httpClient.Do(req)
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	// Read response to completion (but ignore it)
-	io.Copy(io.Discard, resp.Body)
+	// Events that are not sent stay buffered for the next flush
+	tc.buffer.Flush(ctx, tc.sink)
 }
 
-// startBackgroundFlush starts a background goroutine to flush events periodically
+// startBackgroundFlush starts a background goroutine to flush events
+// periodically, starting with those buffered by earlier processes
 func (tc *TelemetryCollector) startBackgroundFlush() {
 	tc.wg.Add(1)
 	go func() {
@@ -215,32 +200,46 @@ func (tc *TelemetryCollector) startBackgroundFlush() {
 		ticker := time.NewTicker(tc.flushInterval)
 		defer ticker.Stop()
 
+		tc.flush()
 		for {
 			select {
 			case <-ticker.C:
 				tc.flush()
 			case <-tc.stopChan:
-				// Final flush before stopping
-				tc.flush()
 				return
 			}
 		}
 	}()
 }
 
-// Shutdown stops the telemetry collector and flushes remaining events
+// Shutdown stops the telemetry collector. Events are buffered on disk as
+// they are recorded, and the events not sent yet are flushed by the next
+// process, so that Shutdown does not wait for the sink.
 func Shutdown() {
-	if globalCollector == nil {
+	if globalCollector == nil || !globalCollector.enabled {
 		return
 	}
+	globalCollector.stop()
+}
 
-	close(globalCollector.stopChan)
-	globalCollector.wg.Wait()
-	globalCollector.flush()
+// stop stops the background flush, waiting at most stopTimeout for a
+// flush in progress. A flush cut short leaves its events to a later one.
+func (tc *TelemetryCollector) stop() {
+	close(tc.stopChan)
+	done := make(chan struct{})
+	go func() {
+		tc.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(tc.stopTimeout):
+	}
 }
 
-// isTelemetryDisabled checks if telemetry is disabled via environment variable or flag
-func isTelemetryDisabled() bool {
+// isTelemetryDisabled checks if telemetry is disabled via environment
+// variable, flag or `prisma-go telemetry disable`
+func isTelemetryDisabled(dir string) bool {
 	// Check environment variable
 	if os.Getenv("PRISMA_TELEMETRY_DISABLED") == "1" || os.Getenv("PRISMA_TELEMETRY_DISABLED") == "true" {
 		return true
@@ -253,27 +252,30 @@ func isTelemetryDisabled() bool {
 		}
 	}
 
-	return false
+	return DisabledIn(dir)
 }
 
-// getTelemetryEndpoint returns the telemetry endpoint URL
-func getTelemetryEndpoint() string {
-	endpoint := os.Getenv("PRISMA_TELEMETRY_ENDPOINT")
-	if endpoint == "" {
-		// Default endpoint (can be configured)
-		return "https://telemetry.prisma-go.dev/events"
+// SinkSpec returns the sink events are flushed to, as given to NewSink:
+// $PRISMA_TELEMETRY_SINK, or else $PRISMA_TELEMETRY_ENDPOINT or the
+// default endpoint
+func SinkSpec() string {
+	if sink := os.Getenv("PRISMA_TELEMETRY_SINK"); sink != "" {
+		return sink
+	}
+	if endpoint := os.Getenv("PRISMA_TELEMETRY_ENDPOINT"); endpoint != "" {
+		return endpoint
 	}
-	return endpoint
+	return DefaultEndpoint
 }
 
 // getOS returns the operating system name
 func getOS() string {
-	return os.Getenv("GOOS")
+	return runtime.GOOS
 }
 
 // getArchitecture returns the architecture
 func getArchitecture() string {
-	return os.Getenv("GOARCH")
+	return runtime.GOARCH
 }
 
 // IsEnabled returns whether telemetry is enabled
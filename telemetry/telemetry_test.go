@@ -0,0 +1,231 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEvent() TelemetryEvent {
+	d := time.Second
+	return TelemetryEvent{
+		EventType:    EventCommand,
+		Command:      "migrate dev",
+		Provider:     "postgresql",
+		Duration:     &d,
+		Timestamp:    time.Now(),
+		Version:      "0.1.0",
+		OS:           "linux",
+		Architecture: "amd64",
+	}
+}
+
+func TestConformToSchema(t *testing.T) {
+	event := testEvent()
+	event.Provider = "postgres"
+	event.Error = `open /home/ada/app/schema.prisma: no such file or directory`
+	event.Metadata = map[string]interface{}{
+		"metric":   "generate",
+		"count":    3,
+		"url":      "postgresql://ada:secret@db:5432/app",
+		"models":   "model User { id Int @id }",
+		"fields":   "db.internal",
+		"schema":   "anything",
+		"success":  true,
+		"duration": []string{"x"},
+	}
+	if err := Validate(event); !errors.Is(err, ErrSchema) {
+		t.Fatalf("Validate = %v, want a schema error", err)
+	}
+
+	event = conform(event)
+	if err := Validate(event); err != nil {
+		t.Fatalf("conforming event: %v", err)
+	}
+	if event.Provider != "postgresql" || event.Error != "unknown" {
+		t.Errorf("provider = %q, error = %q", event.Provider, event.Error)
+	}
+	want := map[string]interface{}{"metric": "generate", "count": 3, "success": true}
+	if len(event.Metadata) != len(want) {
+		t.Errorf("metadata = %v, want %v", event.Metadata, want)
+	}
+	for key, value := range want {
+		if event.Metadata[key] != value {
+			t.Errorf("metadata = %v, want %v", event.Metadata, want)
+		}
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	_, pathErr := os.Open("/nonexistent/secret.prisma")
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{pathErr, "*fs.PathError"},
+		{errors.New("[P1001] Can't reach database server at db.internal:5432"), "P1001"},
+		{errors.New("dial postgresql://ada:secret@db"), "*errors.errorString"},
+	}
+	for _, tt := range tests {
+		if got := ErrorClass(tt.err); got != tt.want {
+			t.Errorf("ErrorClass(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// failingSink fails to send events
+type failingSink struct{}
+
+func (failingSink) Send(ctx context.Context, events []TelemetryEvent) error {
+	return errors.New("unreachable")
+}
+
+func TestBufferFlush(t *testing.T) {
+	dir := t.TempDir()
+	buffer := NewBuffer(dir)
+	invalid := testEvent()
+	invalid.Command = "/home/ada/app"
+	if err := buffer.Append(testEvent(), invalid, testEvent()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Events the sink does not accept stay buffered, across buffers of the
+	// same directory like those of later processes
+	if err := buffer.Flush(context.Background(), failingSink{}); err == nil {
+		t.Fatal("flush to a failing sink succeeded")
+	}
+	buffer.Append(testEvent())
+	events, err := NewBuffer(dir).Events()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("buffered %d events, want 3", len(events))
+	}
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := NewBuffer(dir).Flush(context.Background(), &FileSink{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 || !strings.Contains(lines[0], `"command":"migrate dev"`) {
+		t.Errorf("file sink = %s", data)
+	}
+	if events, _ := buffer.Events(); len(events) != 0 {
+		t.Errorf("%d events buffered after a flush", len(events))
+	}
+}
+
+func TestBufferFlushClaims(t *testing.T) {
+	dir := t.TempDir()
+	buffer := NewBuffer(dir)
+	// A file another process is sending, and one abandoned by a process
+	// that exited while sending it
+	sending := testEvent()
+	sending.Timestamp = time.Unix(1, 0).UTC()
+	for name, event := range map[string]TelemetryEvent{buffer.name(sendingExt): sending, filepath.Join(dir, "1-99-1"+sendingExt): testEvent()} {
+		line, err := json.Marshal(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, append(line, '\n'), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buffer.Append(testEvent())
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := buffer.Flush(context.Background(), &FileSink{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	events, err := readEvents(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("sent %d events, want the abandoned and buffered ones", len(events))
+	}
+	if events, _ := buffer.Events(); len(events) != 1 || !events[0].Timestamp.Equal(sending.Timestamp) {
+		t.Errorf("buffered events = %v, want the one being sent by another process", events)
+	}
+}
+
+// blockingSink does not answer until the flush times out, like an
+// endpoint dropping packets
+type blockingSink struct{}
+
+func (blockingSink) Send(ctx context.Context, events []TelemetryEvent) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestShutdownDoesNotWaitForTheSink(t *testing.T) {
+	tc := &TelemetryCollector{
+		enabled:       true,
+		sink:          blockingSink{},
+		buffer:        NewBuffer(t.TempDir()),
+		flushInterval: time.Hour,
+		flushTimeout:  5 * time.Second,
+		stopTimeout:   10 * time.Millisecond,
+		stopChan:      make(chan struct{}),
+	}
+	tc.buffer.Append(testEvent())
+	tc.startBackgroundFlush()
+
+	// Recording does not wait for the flush in progress either
+	start := time.Now()
+	tc.recordEvent(testEvent())
+	tc.stop()
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("recording and stopping took %v", d)
+	}
+	if events, _ := tc.buffer.Events(); len(events) != 2 {
+		t.Errorf("buffered %d events, want both kept for the next process", len(events))
+	}
+}
+
+func TestSetDisabled(t *testing.T) {
+	dir := t.TempDir()
+	NewBuffer(dir).Append(testEvent())
+	if err := SetDisabled(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if !DisabledIn(dir) {
+		t.Error("telemetry is not disabled")
+	}
+	if events, _ := NewBuffer(dir).Events(); len(events) != 0 {
+		t.Errorf("%d events buffered after telemetry was disabled", len(events))
+	}
+	if err := SetDisabled(dir, false); err != nil || DisabledIn(dir) {
+		t.Errorf("telemetry is still disabled (%v)", err)
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	for spec, want := range map[string]string{
+		"none":                    "telemetry.discardSink",
+		"file:/var/log/t.jsonl":   "*telemetry.FileSink",
+		"events.jsonl":            "*telemetry.FileSink",
+		"https://example.com/e":   "*telemetry.HTTPSink",
+		"ftp://example.com/event": "",
+	} {
+		sink, err := NewSink(spec, "0.1.0")
+		got := ""
+		if err == nil {
+			got = fmt.Sprintf("%T", sink)
+		}
+		if got != want {
+			t.Errorf("NewSink(%q) = %s (%v), want %s", spec, got, err, want)
+		}
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/satishbabariya/prisma-go/migrate/introspect"
 	"github.com/satishbabariya/prisma-go/psl/core"
 	"github.com/satishbabariya/prisma-go/psl/database"
-	"github.com/satishbabariya/prisma-go/psl/parsing/ast"
 	"github.com/satishbabariya/prisma-go/psl/validation"
 	"github.com/stretchr/testify/require"
 )
@@ -39,12 +38,7 @@ func (suite *TestSuite) TestSchemaParsing() {
 	require.NotEmpty(suite.T(), suite.schema.Tops)
 
 	// Check if we have models
-	var models []*ast.Model
-	for _, top := range suite.schema.Tops {
-		if model := top.AsModel(); model != nil {
-			models = append(models, model)
-		}
-	}
+	models := suite.schema.Models()
 	require.NotEmpty(suite.T(), models, "Schema should contain at least one model")
 
 	// Check if we have datasource
@@ -15,7 +15,7 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/satishbabariya/prisma-go/migrate/introspect"
 	"github.com/satishbabariya/prisma-go/psl/parsing"
-	"github.com/satishbabariya/prisma-go/psl/parsing/ast"
+	"github.com/satishbabariya/prisma-go/psl/parsing/v2/ast"
 	"github.com/satishbabariya/prisma-go/query/compiler"
 	"github.com/satishbabariya/prisma-go/runtime/client"
 	"github.com/stretchr/testify/require"
@@ -3,137 +3,270 @@ package telemetry
 
 import (
 	"context"
-	"sync"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/satishbabariya/prisma-go/v3/internal/core/database/pool"
+)
+
+// instrumentationName names the tracer and meter of the adapter.
+const instrumentationName = "github.com/satishbabariya/prisma-go/v3"
+
+// Attributes of Prisma spans and metrics.
+const (
+	ModelKey        = attribute.Key("prisma.model")
+	OperationKey    = attribute.Key("prisma.operation")
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+	PoolNameKey     = attribute.Key("pool.name")
+	StateKey        = attribute.Key("state")
 )
 
-// OpenTelemetryAdapter implements Telemetry using OpenTelemetry.
+// OpenTelemetryAdapter implements Telemetry using OpenTelemetry. Spans are
+// started from the context of the caller, so they join its trace, and are
+// exported by the tracer provider; metrics are reported to the meter
+// provider. Both are the global providers unless set by options.
 type OpenTelemetryAdapter struct {
-	config *Config
-	spans  []SpanInfo
-	mu     sync.Mutex
+	config         *Config
+	tracerProvider trace.TracerProvider
+	tracer         trace.Tracer
+	meter          metric.Meter
+	duration       metric.Float64Histogram
+	errors         metric.Int64Counter
 }
 
-// SpanInfo holds information about a span for tracing.
-type SpanInfo struct {
-	TraceID    string
-	SpanID     string
-	Name       string
-	StartTime  time.Time
-	EndTime    time.Time
-	Status     string
-	Attributes map[string]string
+// OpenTelemetryOption configures an OpenTelemetryAdapter.
+type OpenTelemetryOption func(*OpenTelemetryAdapter)
+
+// WithTracerProvider sets the tracer provider of the adapter.
+func WithTracerProvider(provider trace.TracerProvider) OpenTelemetryOption {
+	return func(o *OpenTelemetryAdapter) {
+		o.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider of the adapter.
+func WithMeterProvider(provider metric.MeterProvider) OpenTelemetryOption {
+	return func(o *OpenTelemetryAdapter) {
+		o.meter = provider.Meter(instrumentationName)
+	}
 }
 
 // NewOpenTelemetryAdapter creates a new OpenTelemetry telemetry adapter.
-func NewOpenTelemetryAdapter(config *Config) *OpenTelemetryAdapter {
-	return &OpenTelemetryAdapter{
-		config: config,
-		spans:  []SpanInfo{},
+func NewOpenTelemetryAdapter(config *Config, opts ...OpenTelemetryOption) *OpenTelemetryAdapter {
+	o := &OpenTelemetryAdapter{
+		config:         config,
+		tracerProvider: otel.GetTracerProvider(),
+		meter:          otel.GetMeterProvider().Meter(instrumentationName),
 	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.tracer = o.tracerProvider.Tracer(instrumentationName)
+
+	// Creating instruments only fails for invalid names, and the instrument
+	// returned is usable even then
+	o.duration, _ = o.meter.Float64Histogram("db.client.operation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Prisma client operations"))
+	o.errors, _ = o.meter.Int64Counter("db.client.operation.errors",
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Number of failed Prisma client operations"))
+	return o
 }
 
-// RecordQuery records a query execution as a span.
-func (o *OpenTelemetryAdapter) RecordQuery(ctx context.Context, info QueryInfo) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
+// StartOperation starts the span of a client operation, e.g. User.findMany.
+// The statements of the operation are traced within the returned context.
+func (o *OpenTelemetryAdapter) StartOperation(ctx context.Context, model, operation string) (context.Context, func(err error)) {
+	ctx, span := o.tracer.Start(ctx, model+"."+operation,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(ModelKey.String(model), OperationKey.String(operation)),
+	)
+	start := time.Now()
+	return ctx, func(err error) {
+		o.recordDuration(ctx, model, operation, time.Since(start), err == nil)
+		endSpan(span, err)
+	}
+}
 
-	status := "OK"
-	if !info.Success {
-		status = "ERROR"
+// StartStatement starts the span of a SQL statement run on system.
+func (o *OpenTelemetryAdapter) StartStatement(ctx context.Context, system, query string) (context.Context, func(err error, rowsAffected int64)) {
+	verb := SQLVerb(query)
+	ctx, span := o.tracer.Start(ctx, verb,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemKey.String(system),
+			semconv.DBStatementKey.String(query),
+			semconv.DBOperationKey.String(verb),
+		),
+	)
+	return ctx, func(err error, rowsAffected int64) {
+		if rowsAffected >= 0 {
+			span.SetAttributes(RowsAffectedKey.Int64(rowsAffected))
+		}
+		endSpan(span, err)
+	}
+}
+
+// StartTransaction starts the span of a transaction on system.
+func (o *OpenTelemetryAdapter) StartTransaction(ctx context.Context, system string) (context.Context, func(err error)) {
+	ctx, span := o.tracer.Start(ctx, "transaction",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(semconv.DBSystemKey.String(system)),
+	)
+	return ctx, func(err error) {
+		endSpan(span, err)
 	}
+}
 
-	span := SpanInfo{
-		TraceID:   generateTraceID(),
-		SpanID:    generateSpanID(),
-		Name:      "prisma." + info.Operation,
-		StartTime: time.Now().Add(-info.Duration),
-		EndTime:   time.Now(),
-		Status:    status,
-		Attributes: map[string]string{
-			"db.system":    "prisma",
-			"db.operation": info.Operation,
-			"db.model":     info.Model,
-		},
+// RecordQuery records a query execution that already ran as a span ending
+// now, and its duration.
+func (o *OpenTelemetryAdapter) RecordQuery(ctx context.Context, info QueryInfo) {
+	end := time.Now()
+	ctx, span := o.tracer.Start(ctx, info.Model+"."+info.Operation,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithTimestamp(end.Add(-info.Duration)),
+		trace.WithAttributes(
+			ModelKey.String(info.Model),
+			OperationKey.String(info.Operation),
+			RowsAffectedKey.Int64(info.RowsAffected),
+		),
+	)
+	if !info.Success {
+		span.SetStatus(codes.Error, "query failed")
 	}
-	o.spans = append(o.spans, span)
+	span.End(trace.WithTimestamp(end))
+	o.recordDuration(ctx, info.Model, info.Operation, info.Duration, info.Success)
 }
 
-// RecordError records an error as a span event.
+// RecordError records an error on the span of ctx and counts it.
 func (o *OpenTelemetryAdapter) RecordError(ctx context.Context, info ErrorInfo) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
-
-	span := SpanInfo{
-		TraceID:   generateTraceID(),
-		SpanID:    generateSpanID(),
-		Name:      "prisma.error",
-		StartTime: time.Now(),
-		EndTime:   time.Now(),
-		Status:    "ERROR",
-		Attributes: map[string]string{
-			"error.message": info.Error.Error(),
-			"db.model":      info.Model,
-			"db.operation":  info.Operation,
-		},
-	}
-	o.spans = append(o.spans, span)
-}
-
-// RecordConnection records a connection event.
-func (o *OpenTelemetryAdapter) RecordConnection(ctx context.Context, info ConnectionInfo) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
+	if info.Error == nil {
+		return
+	}
+	attrs := []attribute.KeyValue{ModelKey.String(info.Model), OperationKey.String(info.Operation)}
+	if info.Query != "" {
+		attrs = append(attrs, semconv.DBStatementKey.String(info.Query))
+	}
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(info.Error, trace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, info.Error.Error())
+	o.errors.Add(ctx, 1, metric.WithAttributes(ModelKey.String(info.Model), OperationKey.String(info.Operation)))
+}
 
-	status := "OK"
+// RecordConnection records a connection event as a span ending now.
+func (o *OpenTelemetryAdapter) RecordConnection(ctx context.Context, info ConnectionInfo) {
+	end := time.Now()
+	_, span := o.tracer.Start(ctx, "prisma.connection."+info.Event,
+		trace.WithTimestamp(end.Add(-info.Duration)),
+		trace.WithAttributes(attribute.String("db.connection.event", info.Event)),
+	)
 	if !info.Success {
-		status = "ERROR"
+		span.SetStatus(codes.Error, "connection "+info.Event+" failed")
 	}
+	span.End(trace.WithTimestamp(end))
+}
 
-	span := SpanInfo{
-		TraceID:   generateTraceID(),
-		SpanID:    generateSpanID(),
-		Name:      "prisma.connection." + info.Event,
-		StartTime: time.Now().Add(-info.Duration),
-		EndTime:   time.Now(),
-		Status:    status,
-		Attributes: map[string]string{
-			"db.connection.event": info.Event,
-		},
+// RegisterPool reports the statistics of p as pool.name name whenever the
+// meter is collected. Unregister the registration to stop reporting them.
+func (o *OpenTelemetryAdapter) RegisterPool(name string, p interface{ Stats() pool.PoolStats }) (metric.Registration, error) {
+	usage, err := o.meter.Int64ObservableUpDownCounter("db.client.connections.usage",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The number of connections that are currently in the state described by the state attribute"))
+	if err != nil {
+		return nil, err
+	}
+	maxOpen, err := o.meter.Int64ObservableUpDownCounter("db.client.connections.max",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The maximum number of open connections allowed"))
+	if err != nil {
+		return nil, err
 	}
-	o.spans = append(o.spans, span)
+	waits, err := o.meter.Int64ObservableCounter("db.client.connections.waits",
+		metric.WithUnit("{wait}"),
+		metric.WithDescription("The total number of connections waited for"))
+	if err != nil {
+		return nil, err
+	}
+	waitTime, err := o.meter.Float64ObservableCounter("db.client.connections.wait_time",
+		metric.WithUnit("s"),
+		metric.WithDescription("The total time blocked waiting for a new connection"))
+	if err != nil {
+		return nil, err
+	}
+	failedChecks, err := o.meter.Int64ObservableCounter("db.client.connections.failed_health_checks",
+		metric.WithUnit("{check}"),
+		metric.WithDescription("The number of failed health checks of the pool"))
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := metric.WithAttributes(PoolNameKey.String(name))
+	idle := metric.WithAttributes(PoolNameKey.String(name), StateKey.String("idle"))
+	used := metric.WithAttributes(PoolNameKey.String(name), StateKey.String("used"))
+
+	return o.meter.RegisterCallback(func(ctx context.Context, obs metric.Observer) error {
+		stats := p.Stats()
+		obs.ObserveInt64(usage, int64(stats.Idle), idle)
+		obs.ObserveInt64(usage, int64(stats.InUse), used)
+		obs.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), attrs)
+		obs.ObserveInt64(waits, stats.WaitCount, attrs)
+		obs.ObserveFloat64(waitTime, stats.WaitDuration.Seconds(), attrs)
+		obs.ObserveInt64(failedChecks, stats.FailedHealthChecks, attrs)
+		return nil
+	}, usage, maxOpen, waits, waitTime, failedChecks)
 }
 
-// Flush flushes buffered spans to the backend.
+// Flush exports the spans buffered by the tracer provider, if it buffers
+// them.
 func (o *OpenTelemetryAdapter) Flush(ctx context.Context) error {
-	// In a real implementation, this would send spans to the OTLP endpoint
-	// For now, we just clear the buffer
-	o.mu.Lock()
-	defer o.mu.Unlock()
-	o.spans = []SpanInfo{}
+	if flusher, ok := o.tracerProvider.(interface{ ForceFlush(context.Context) error }); ok {
+		return flusher.ForceFlush(ctx)
+	}
 	return nil
 }
 
-// Close closes the telemetry adapter.
+// Close closes the telemetry adapter. The providers are owned by the
+// caller and are not shut down.
 func (o *OpenTelemetryAdapter) Close(ctx context.Context) error {
 	return o.Flush(ctx)
 }
 
-// GetSpans returns all collected spans for testing/debugging.
-func (o *OpenTelemetryAdapter) GetSpans() []SpanInfo {
-	o.mu.Lock()
-	defer o.mu.Unlock()
-	return o.spans
+// recordDuration records the duration of an operation.
+func (o *OpenTelemetryAdapter) recordDuration(ctx context.Context, model, operation string, d time.Duration, success bool) {
+	outcome := "success"
+	if !success {
+		outcome = "error"
+	}
+	o.duration.Record(ctx, d.Seconds(), metric.WithAttributes(
+		ModelKey.String(model),
+		OperationKey.String(operation),
+		attribute.String("outcome", outcome),
+	))
 }
 
-// generateTraceID generates a simple trace ID.
-func generateTraceID() string {
-	return time.Now().Format("20060102150405.000000000")
+// endSpan ends span, recording err.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
 
-// generateSpanID generates a simple span ID.
-func generateSpanID() string {
-	return time.Now().Format("150405.000000")
+// SQLVerb returns the first keyword of query in upper case, e.g. SELECT.
+func SQLVerb(query string) string {
+	query = strings.TrimSpace(query)
+	if i := strings.IndexAny(query, " \t\r\n("); i >= 0 {
+		query = query[:i]
+	}
+	return strings.ToUpper(query)
 }
 
 // Ensure OpenTelemetryAdapter implements Telemetry interface.
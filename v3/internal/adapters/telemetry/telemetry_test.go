@@ -6,6 +6,15 @@ import (
 	"errors"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/satishbabariya/prisma-go/v3/internal/core/database/pool"
 )
 
 func TestNoopTelemetry(t *testing.T) {
@@ -85,37 +94,110 @@ func TestPrometheusTelemetry(t *testing.T) {
 }
 
 func TestOpenTelemetryAdapter(t *testing.T) {
-	ctx := context.Background()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
 	adapter := NewOpenTelemetryAdapter(&Config{
 		Type:        "opentelemetry",
 		ServiceName: "test-service",
+	}, WithTracerProvider(provider))
+
+	// Trace an operation running a statement, within the span of the caller
+	ctx, caller := provider.Tracer("test").Start(context.Background(), "request")
+	opCtx, endOp := adapter.StartOperation(ctx, "User", "create")
+	_, endStmt := adapter.StartStatement(opCtx, "postgresql", `INSERT INTO "User" ("email") VALUES ($1)`)
+	endStmt(nil, 1)
+	adapter.RecordError(opCtx, ErrorInfo{
+		Error:     errors.New("test error"),
+		Model:     "User",
+		Operation: "create",
 	})
+	endOp(nil)
 
-	// Record a query
+	// Record a query that already ran
 	adapter.RecordQuery(ctx, QueryInfo{
-		Model:     "User",
-		Operation: "create",
+		Model:     "Post",
+		Operation: "findMany",
 		Duration:  50 * time.Millisecond,
 		Success:   true,
 	})
+	caller.End()
 
-	// Record an error
-	adapter.RecordError(ctx, ErrorInfo{
-		Error:     errors.New("test error"),
-		Model:     "Post",
-		Operation: "delete",
-	})
+	if err := adapter.Flush(ctx); err != nil {
+		t.Fatalf("Flush should not return error, got: %v", err)
+	}
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	if len(spans) != 4 {
+		t.Fatalf("Expected 4 spans, got %d", len(spans))
+	}
+
+	op, stmt := spans["User.create"], spans["INSERT"]
+	if op.Parent.SpanID() != caller.SpanContext().SpanID() {
+		t.Error("operation span should be a child of the caller's span")
+	}
+	if stmt.Parent.SpanID() != op.SpanContext.SpanID() {
+		t.Error("statement span should be a child of the operation span")
+	}
+	attrs := map[attribute.Key]string{}
+	for _, kv := range stmt.Attributes {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	if attrs["db.system"] != "postgresql" || attrs["db.operation"] != "INSERT" || attrs["db.statement"] == "" || attrs[RowsAffectedKey] != "1" {
+		t.Errorf("unexpected statement attributes: %v", attrs)
+	}
+	if d := spans["Post.findMany"].EndTime.Sub(spans["Post.findMany"].StartTime); d != 50*time.Millisecond {
+		t.Errorf("Expected recorded span of 50ms, got %v", d)
+	}
+	if op.Status.Code != codes.Error || len(op.Events) != 1 {
+		t.Error("error should be recorded on the span of its context")
+	}
+}
 
-	// Check spans
-	spans := adapter.GetSpans()
-	if len(spans) != 2 {
-		t.Fatalf("Expected 2 spans, got %d", len(spans))
+// fakePool reports fixed pool statistics
+type fakePool struct{}
+
+func (fakePool) Stats() pool.PoolStats {
+	return pool.PoolStats{MaxOpenConnections: 10, InUse: 3, Idle: 2, WaitCount: 4}
+}
+
+func TestOpenTelemetryPoolMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	adapter := NewOpenTelemetryAdapter(&Config{Type: "opentelemetry"},
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	if _, err := adapter.RegisterPool("main", fakePool{}); err != nil {
+		t.Fatal(err)
 	}
 
-	// Flush and check
-	adapter.Flush(ctx)
-	spans = adapter.GetSpans()
-	if len(spans) != 0 {
-		t.Errorf("Expected 0 spans after flush, got %d", len(spans))
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				name := m.Name
+				if state, ok := dp.Attributes.Value(StateKey); ok {
+					name += "." + state.AsString()
+				}
+				values[name] = dp.Value
+			}
+		}
+	}
+	for name, want := range map[string]int64{
+		"db.client.connections.usage.used": 3,
+		"db.client.connections.usage.idle": 2,
+		"db.client.connections.max":        10,
+		"db.client.connections.waits":      4,
+	} {
+		if values[name] != want {
+			t.Errorf("Expected %s = %d, got %d", name, want, values[name])
+		}
 	}
 }
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client is the base Prisma client that generated clients embed.
@@ -44,6 +46,9 @@ type ClientConfig struct {
 
 	// Logger is the logger instance.
 	Logger Logger
+
+	// TracerProvider traces statements and transactions when set.
+	TracerProvider trace.TracerProvider
 }
 
 // DefaultConfig returns the default client configuration.
@@ -209,7 +214,10 @@ func (c *Client) QueryContext(ctx context.Context, query string, args ...interfa
 		c.config.Logger.Debug("Executing query", "query", query, "args", args)
 	}
 
-	return c.db.QueryContext(ctx, query, args...)
+	ctx, end := c.config.startStatement(ctx, query)
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	end(err)
+	return rows, err
 }
 
 // QueryRowContext executes a query that returns a single row.
@@ -230,7 +238,10 @@ func (c *Client) QueryRowContext(ctx context.Context, query string, args ...inte
 		c.config.Logger.Debug("Executing query", "query", query, "args", args)
 	}
 
-	return c.db.QueryRowContext(ctx, query, args...)
+	ctx, end := c.config.startStatement(ctx, query)
+	row := c.db.QueryRowContext(ctx, query, args...)
+	end(row.Err())
+	return row
 }
 
 // ExecContext executes a statement.
@@ -251,5 +262,8 @@ func (c *Client) ExecContext(ctx context.Context, query string, args ...interfac
 		c.config.Logger.Debug("Executing statement", "query", query, "args", args)
 	}
 
-	return c.db.ExecContext(ctx, query, args...)
+	ctx, end := c.config.startStatement(ctx, query)
+	result, err := c.db.ExecContext(ctx, query, args...)
+	end(err)
+	return result, err
 }
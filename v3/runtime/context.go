@@ -3,6 +3,7 @@ package runtime
 
 import (
 	"context"
+	"log/slog"
 )
 
 // contextKey is a type for context keys.
@@ -55,7 +56,8 @@ func TraceIDFromContext(ctx context.Context) (string, bool) {
 	return id, ok
 }
 
-// Logger interface for runtime logging.
+// Logger interface for runtime logging. Its methods take alternating keys
+// and values like those of log/slog, and a *slog.Logger is a Logger.
 type Logger interface {
 	Debug(msg string, args ...interface{})
 	Info(msg string, args ...interface{})
@@ -63,6 +65,8 @@ type Logger interface {
 	Error(msg string, args ...interface{})
 }
 
+var _ Logger = (*slog.Logger)(nil)
+
 // ContextWithLogger stores a logger in the context.
 func ContextWithLogger(ctx context.Context, logger Logger) context.Context {
 	return context.WithValue(ctx, loggerKey, logger)
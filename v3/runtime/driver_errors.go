@@ -0,0 +1,136 @@
+// Package runtime provides the classification of native database driver errors
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// nativeClass is what ClassifyError reports for a Prisma code
+type nativeClass struct {
+	message   string
+	cause     error // Sentinel error wrapped with the driver error
+	retryable bool
+}
+
+// nativeClasses maps the Prisma codes of native driver errors to their class
+var nativeClasses = map[string]nativeClass{
+	"P1001": {"Cannot connect to database", ErrConnectionFailed, true},
+	"P1008": {"Operation timed out", ErrTimeout, true},
+	"P1017": {"Server has closed the connection", ErrConnectionFailed, true},
+	"P2002": {"Unique constraint violation", ErrUniqueConstraint, false},
+	"P2003": {"Foreign key constraint violation", ErrForeignKeyConstraint, false},
+	"P2011": {"Null constraint violation", ErrNullConstraint, false},
+	"P2034": {"Transaction failed due to a write conflict or a deadlock", ErrTransactionFailed, true},
+}
+
+// postgresCodes maps SQLSTATE codes to Prisma codes
+var postgresCodes = map[string]string{
+	"08001": "P1001", // sqlclient_unable_to_establish_sqlconnection
+	"08006": "P1001", // connection_failure
+	"23001": "P2003", // restrict_violation
+	"23502": "P2011", // not_null_violation
+	"23503": "P2003", // foreign_key_violation
+	"23505": "P2002", // unique_violation
+	"40001": "P2034", // serialization_failure
+	"40P01": "P2034", // deadlock_detected
+	"57014": "P1008", // query_canceled, e.g. by statement_timeout
+	"57P01": "P1017", // admin_shutdown
+}
+
+// mysqlCodes maps MySQL error numbers to Prisma codes
+var mysqlCodes = map[uint64]string{
+	1048: "P2011", // ER_BAD_NULL_ERROR
+	1062: "P2002", // ER_DUP_ENTRY
+	1205: "P2034", // ER_LOCK_WAIT_TIMEOUT
+	1213: "P2034", // ER_LOCK_DEADLOCK
+	1364: "P2011", // ER_NO_DEFAULT_FOR_FIELD
+	1451: "P2003", // ER_ROW_IS_REFERENCED_2
+	1452: "P2003", // ER_NO_REFERENCED_ROW_2
+	1586: "P2002", // ER_DUP_ENTRY_WITH_KEY_NAME
+}
+
+// sqliteCodes maps SQLite extended and primary result codes to Prisma codes
+var sqliteCodes = map[int64]string{
+	5:    "P1008", // SQLITE_BUSY
+	6:    "P1008", // SQLITE_LOCKED
+	787:  "P2003", // SQLITE_CONSTRAINT_FOREIGNKEY
+	1299: "P2011", // SQLITE_CONSTRAINT_NOTNULL
+	1555: "P2002", // SQLITE_CONSTRAINT_PRIMARYKEY
+	2067: "P2002", // SQLITE_CONSTRAINT_UNIQUE
+}
+
+// sqlServerCodes maps SQL Server error numbers to Prisma codes
+var sqlServerCodes = map[int32]string{
+	515:  "P2011", // Cannot insert the value NULL
+	547:  "P2003", // Statement conflicted with a constraint
+	1205: "P2034", // Deadlock victim
+	2601: "P2002", // Duplicate key row in unique index
+	2627: "P2002", // Violation of unique or primary key constraint
+}
+
+// nativeCode returns the Prisma code of the native driver error in the
+// chain of err, "" when its code is not mapped, and whether there is a
+// driver error. Drivers are recognized by the methods and fields of their
+// errors, so that the runtime does not depend on them.
+func nativeCode(err error) (string, bool) {
+	// lib/pq and pgx
+	var pgErr interface {
+		error
+		SQLState() string
+	}
+	if errors.As(err, &pgErr) {
+		return postgresCodes[pgErr.SQLState()], true
+	}
+	// go-mssqldb
+	var msErr interface {
+		error
+		SQLErrorNumber() int32
+	}
+	if errors.As(err, &msErr) {
+		return sqlServerCodes[msErr.SQLErrorNumber()], true
+	}
+
+	// go-sql-driver/mysql reports *MySQLError{Number}, mattn/go-sqlite3
+	// Error{Code, ExtendedCode}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		v := reflect.Indirect(reflect.ValueOf(e))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		switch v.Type().PkgPath() {
+		case "github.com/go-sql-driver/mysql":
+			if number := v.FieldByName("Number"); number.CanUint() {
+				return mysqlCodes[number.Uint()], true
+			}
+		case "github.com/mattn/go-sqlite3":
+			code, extended := v.FieldByName("Code"), v.FieldByName("ExtendedCode")
+			if code.CanInt() && extended.CanInt() {
+				if prismaCode, ok := sqliteCodes[extended.Int()]; ok {
+					return prismaCode, true
+				}
+				return sqliteCodes[code.Int()], true
+			}
+		}
+	}
+	return "", false
+}
+
+// classifyNative returns the PrismaError of a native driver error with
+// Prisma code code. Its cause wraps both the sentinel error of the class
+// and err. Unlike the errors classified by runtime/dberrors, its Meta does
+// not name the constraint, target fields or model of the error.
+func classifyNative(code string, err error) *PrismaError {
+	class, ok := nativeClasses[code]
+	if !ok {
+		return NewPrismaError("P0000", "Unknown error").
+			WithCause(err).
+			WithMeta("retryable", false)
+	}
+	e := NewPrismaError(code, class.message).
+		WithCause(fmt.Errorf("%w: %w", class.cause, err)).
+		WithMeta("retryable", class.retryable)
+	e.Retryable = class.retryable
+	return e
+}
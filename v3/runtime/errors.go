@@ -199,7 +199,13 @@ func ClassifyError(err error) error {
 			WithMeta("retryable", false)
 	}
 
-	// Parse database-specific errors
+	// Native driver errors are classified by their codes: their messages
+	// may say "unique" or "connection" whatever the error
+	if code, ok := nativeCode(err); ok {
+		return classifyNative(code, err)
+	}
+
+	// Parse the messages of other errors
 	errMsg := fmt.Sprintf("%v", err)
 
 	// Connection errors
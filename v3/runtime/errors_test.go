@@ -3,9 +3,13 @@ package runtime
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -75,6 +79,91 @@ func TestClassifyError(t *testing.T) {
 	}
 }
 
+// sqlServerError mimics the errors of go-mssqldb
+type sqlServerError struct{ number int32 }
+
+func (e sqlServerError) Error() string         { return "mssql: error" }
+func (e sqlServerError) SQLErrorNumber() int32 { return e.number }
+
+func TestClassifyError_NativeCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCode  string
+		wantCause error
+		retryable bool
+	}{
+		{
+			name:      "postgres unique violation",
+			err:       fmt.Errorf("insert: %w", &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"}),
+			wantCode:  "P2002",
+			wantCause: ErrUniqueConstraint,
+		},
+		{
+			name:     "postgres syntax error naming a unique column",
+			err:      &pq.Error{Code: "42601", Message: `syntax error at or near "unique_connection"`},
+			wantCode: "P0000",
+		},
+		{
+			name:      "postgres deadlock",
+			err:       &pq.Error{Code: "40P01", Message: "deadlock detected"},
+			wantCode:  "P2034",
+			wantCause: ErrTransactionFailed,
+			retryable: true,
+		},
+		{
+			name:      "mysql duplicate entry",
+			err:       &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a' for key 'email'"},
+			wantCode:  "P2002",
+			wantCause: ErrUniqueConstraint,
+		},
+		{
+			name:      "mysql foreign key",
+			err:       &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"},
+			wantCode:  "P2003",
+			wantCause: ErrForeignKeyConstraint,
+		},
+		{
+			name:     "mysql unknown column named duplicate",
+			err:      &mysql.MySQLError{Number: 1054, Message: "Unknown column 'duplicate' in 'field list'"},
+			wantCode: "P0000",
+		},
+		{
+			name:      "sqlite not null",
+			err:       sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintNotNull},
+			wantCode:  "P2011",
+			wantCause: ErrNullConstraint,
+		},
+		{
+			name:      "sqlite busy",
+			err:       fmt.Errorf("query: %w", sqlite3.Error{Code: sqlite3.ErrBusy}),
+			wantCode:  "P1008",
+			wantCause: ErrTimeout,
+			retryable: true,
+		},
+		{
+			name:      "sql server unique key",
+			err:       sqlServerError{number: 2627},
+			wantCode:  "P2002",
+			wantCause: ErrUniqueConstraint,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prismaErr *PrismaError
+			require.True(t, errors.As(ClassifyError(tt.err), &prismaErr))
+			assert.Equal(t, tt.wantCode, prismaErr.Code)
+			assert.Equal(t, tt.retryable, prismaErr.Retryable)
+			if tt.wantCause != nil {
+				assert.True(t, errors.Is(prismaErr, tt.wantCause))
+			}
+			// The driver error stays in the chain
+			assert.True(t, errors.Is(prismaErr, tt.err))
+		})
+	}
+}
+
 func TestPrismaError_Methods(t *testing.T) {
 	t.Run("WithCause adds cause and sets retryable", func(t *testing.T) {
 		err := NewPrismaError("P1001", "Connection failed").
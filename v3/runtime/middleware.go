@@ -87,29 +87,27 @@ func (mc *MiddlewareChain) Execute(ctx context.Context, info QueryInfo, handler
 	return next(ctx, info)
 }
 
-// LoggingMiddleware creates a middleware that logs queries.
+// LoggingMiddleware creates a middleware that logs queries, with the trace
+// ID of the context when it has one. A *slog.Logger is a Logger.
 func LoggingMiddleware(logger Logger) Middleware {
 	return func(ctx context.Context, info QueryInfo, next Next) QueryResult {
-		logger.Info("Query started",
+		attrs := []interface{}{
 			"model", info.Model,
 			"operation", info.Operation,
-		)
+		}
+		if traceID, ok := TraceIDFromContext(ctx); ok {
+			attrs = append(attrs, "trace_id", traceID)
+		}
+		logger.Info("Query started", attrs...)
 
+		start := time.Now()
 		result := next(ctx, info)
+		attrs = append(attrs, "duration", time.Since(start))
 
 		if result.Error != nil {
-			logger.Error("Query failed",
-				"model", info.Model,
-				"operation", info.Operation,
-				"duration", info.Duration,
-				"error", result.Error,
-			)
+			logger.Error("Query failed", append(attrs, "error", result.Error)...)
 		} else {
-			logger.Info("Query completed",
-				"model", info.Model,
-				"operation", info.Operation,
-				"duration", info.Duration,
-			)
+			logger.Info("Query completed", append(attrs, "rows_affected", result.RowsAffected)...)
 		}
 
 		return result
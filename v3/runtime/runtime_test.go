@@ -2,9 +2,16 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestHooks_Register_Execute(t *testing.T) {
@@ -91,6 +98,76 @@ func TestMiddlewareChain(t *testing.T) {
 	}
 }
 
+func TestTracingMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client := NewClient(WithTracerProvider(provider))
+
+	chain := NewMiddlewareChain()
+	chain.Use(TracingMiddleware(provider.Tracer("test")))
+
+	// The handler runs its statements with the context of the middleware
+	handler := func(ctx context.Context, info QueryInfo) QueryResult {
+		_, end := client.config.startStatement(ctx, `DELETE FROM "User" WHERE "id" = $1`)
+		end(nil)
+		return QueryResult{RowsAffected: 1, Error: ErrNotFound}
+	}
+	ctx, caller := provider.Tracer("test").Start(context.Background(), "request")
+	chain.Execute(ctx, QueryInfo{Model: "User", Operation: "delete"}, handler)
+	caller.End()
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	op, ok := spans["User.delete"]
+	if !ok {
+		t.Fatalf("Expected a User.delete span, got %v", spans)
+	}
+	if op.Parent.SpanID() != caller.SpanContext().SpanID() {
+		t.Error("Operation span should be a child of the caller's span")
+	}
+	if op.Status.Code != codes.Error {
+		t.Error("Operation span should record the error of the result")
+	}
+
+	stmt, ok := spans["DELETE"]
+	if !ok {
+		t.Fatalf("Expected a DELETE span, got %v", spans)
+	}
+	if stmt.Parent.SpanID() != op.SpanContext.SpanID() {
+		t.Error("Statement span should be a child of the operation span")
+	}
+	attrs := map[string]string{}
+	for _, kv := range stmt.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["db.system"] != "postgresql" || attrs["db.operation"] != "DELETE" || attrs["db.statement"] == "" {
+		t.Errorf("Unexpected statement attributes: %v", attrs)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	chain := NewMiddlewareChain()
+	chain.Use(LoggingMiddleware(slog.New(slog.NewTextHandler(&buf, nil))))
+
+	ctx := WithTraceID(context.Background(), "trace-1")
+	chain.Execute(ctx, QueryInfo{Model: "User", Operation: "update"}, func(ctx context.Context, info QueryInfo) QueryResult {
+		return QueryResult{RowsAffected: 2}
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %q", buf.String())
+	}
+	for _, want := range []string{"model=User", "operation=update", "trace_id=trace-1", "rows_affected=2", "duration="} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("Expected %s in %q", want, lines[1])
+		}
+	}
+}
+
 func TestContextHelpers(t *testing.T) {
 	ctx := context.Background()
 
@@ -0,0 +1,115 @@
+// Package runtime provides OpenTelemetry tracing for Prisma clients.
+package runtime
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the tracer of the client.
+const tracerName = "github.com/satishbabariya/prisma-go/v3/runtime"
+
+// dbSystem is the db.system of the databases the client connects to.
+var dbSystem = semconv.DBSystemPostgreSQL
+
+// Attributes of operation spans.
+const (
+	ModelKey        = attribute.Key("prisma.model")
+	OperationKey    = attribute.Key("prisma.operation")
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+)
+
+// WithTracerProvider traces the SQL statements and transactions of the
+// client with the tracer of provider. Statements are traced within the span
+// of the context they run with, e.g. the span of TracingMiddleware.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *ClientConfig) {
+		c.TracerProvider = provider
+	}
+}
+
+// TracingMiddleware traces each query with a span named after its model
+// and operation, e.g. User.findMany. The span is started from the context
+// of the caller, and the rest of the chain runs within it.
+func TracingMiddleware(tracer trace.Tracer) Middleware {
+	return func(ctx context.Context, info QueryInfo, next Next) QueryResult {
+		ctx, span := tracer.Start(ctx, info.Model+"."+info.Operation,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(ModelKey.String(info.Model), OperationKey.String(info.Operation)),
+		)
+		result := next(ctx, info)
+		if result.RowsAffected > 0 {
+			span.SetAttributes(RowsAffectedKey.Int64(result.RowsAffected))
+		}
+		endSpan(span, result.Error)
+		return result
+	}
+}
+
+// tracer returns the tracer of the client, nil when it is not traced.
+func (c *ClientConfig) tracer() trace.Tracer {
+	if c.TracerProvider == nil {
+		return nil
+	}
+	return c.TracerProvider.Tracer(tracerName)
+}
+
+// startStatement starts the span of query, if the client is traced.
+func (c *ClientConfig) startStatement(ctx context.Context, query string) (context.Context, func(err error)) {
+	tracer := c.tracer()
+	if tracer == nil {
+		return ctx, func(error) {}
+	}
+	verb := sqlVerb(query)
+	ctx, span := tracer.Start(ctx, verb,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			dbSystem,
+			semconv.DBStatementKey.String(query),
+			semconv.DBOperationKey.String(verb),
+		),
+	)
+	return ctx, func(err error) {
+		endSpan(span, err)
+	}
+}
+
+// startTransaction starts the span of a transaction, if the client is
+// traced.
+func (c *ClientConfig) startTransaction(ctx context.Context) (context.Context, func(err error)) {
+	tracer := c.tracer()
+	if tracer == nil {
+		return ctx, func(error) {}
+	}
+	ctx, span := tracer.Start(ctx, "transaction",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(dbSystem),
+	)
+	return ctx, func(err error) {
+		endSpan(span, err)
+	}
+}
+
+// endSpan ends span, recording err.
+func endSpan(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// sqlVerb returns the first keyword of query in upper case, e.g. SELECT.
+func sqlVerb(query string) string {
+	query = strings.TrimSpace(query)
+	if i := strings.IndexAny(query, " \t\r\n("); i >= 0 {
+		query = query[:i]
+	}
+	return strings.ToUpper(query)
+}
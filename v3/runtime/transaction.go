@@ -29,6 +29,17 @@ type Transaction interface {
 type Tx struct {
 	tx     *sql.Tx
 	client *Client
+	ctx    context.Context
+}
+
+// Context returns the context the transaction was started with, carrying
+// its span when the client is traced. Run the queries of the transaction
+// with it so that they are traced within the transaction.
+func (t *Tx) Context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
 }
 
 // Commit commits the transaction.
@@ -58,6 +69,13 @@ func (t *Tx) QueryContext(ctx context.Context, query string, args ...interface{}
 		t.client.config.Logger.Debug("Executing query (tx)", "query", query, "args", args)
 	}
 
+	if t.client != nil {
+		var end func(error)
+		ctx, end = t.client.config.startStatement(ctx, query)
+		rows, err := t.tx.QueryContext(ctx, query, args...)
+		end(err)
+		return rows, err
+	}
 	return t.tx.QueryContext(ctx, query, args...)
 }
 
@@ -72,6 +90,13 @@ func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...interfac
 		t.client.config.Logger.Debug("Executing query (tx)", "query", query, "args", args)
 	}
 
+	if t.client != nil {
+		var end func(error)
+		ctx, end = t.client.config.startStatement(ctx, query)
+		row := t.tx.QueryRowContext(ctx, query, args...)
+		end(row.Err())
+		return row
+	}
 	return t.tx.QueryRowContext(ctx, query, args...)
 }
 
@@ -86,6 +111,13 @@ func (t *Tx) ExecContext(ctx context.Context, query string, args ...interface{})
 		t.client.config.Logger.Debug("Executing statement (tx)", "query", query, "args", args)
 	}
 
+	if t.client != nil {
+		var end func(error)
+		ctx, end = t.client.config.startStatement(ctx, query)
+		result, err := t.tx.ExecContext(ctx, query, args...)
+		end(err)
+		return result, err
+	}
 	return t.tx.ExecContext(ctx, query, args...)
 }
 
@@ -133,6 +165,7 @@ func (c *Client) BeginTx(ctx context.Context, opts *TransactionOptions) (*Tx, er
 	return &Tx{
 		tx:     tx,
 		client: c,
+		ctx:    ctx,
 	}, nil
 }
 
@@ -144,7 +177,10 @@ func (c *Client) Transaction(ctx context.Context, fn func(tx Transaction) error)
 }
 
 // TransactionWithOptions executes a function within a transaction with options.
-func (c *Client) TransactionWithOptions(ctx context.Context, opts *TransactionOptions, fn func(tx Transaction) error) error {
+func (c *Client) TransactionWithOptions(ctx context.Context, opts *TransactionOptions, fn func(tx Transaction) error) (err error) {
+	ctx, end := c.config.startTransaction(ctx)
+	defer func() { end(err) }()
+
 	if opts == nil {
 		opts = DefaultTransactionOptions()
 	}